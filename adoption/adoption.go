@@ -0,0 +1,433 @@
+package adoption
+
+/* This package implements the adoption of orphaned positions. When a thread starts, the free balance of its symbol
+in the exchange is reconciled with the open transactions of the symbol across all threads. Holdings not tracked by
+any thread transaction (i.e. bought manually, or left by a thread whose transactions were lost) are offered for
+adoption in the thread detail page and the REST API, imported as an open transaction of the thread at a cost basis
+supplied by the operator and sold by the bot as any other transaction. Transactions not backed by holdings
+(i.e. sold manually in the exchange) are offered for release, removed from the open transactions of the thread
+without a sale. */
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// OrderSource is the orders table source of the positions adopted by an operator
+const OrderSource = "adoption"
+
+/* Thread timeline event kind of adoptions and releases */
+const eventKind = "adoption"
+
+/* Adoption errors */
+var (
+	ErrNoThread  = errors.New("Adoption requires a running thread")
+	ErrQuantity  = errors.New("Quantity must be at least one lot size step and the exchange minimum, up to the orphaned holdings")
+	ErrCostBasis = errors.New("Cost basis must be a positive price")
+	ErrNotOpen   = errors.New("OrderID is not an open transaction of the thread")
+	ErrBacked    = errors.New("Open transactions are backed by the exchange holdings, nothing to release")
+)
+
+// Status struct define the exchange holdings of the thread symbol reconciled with the open thread transactions
+type Status struct {
+	Symbol   string  `json:"symbol"`
+	Holdings float64 `json:"holdings"` /* Free balance of the symbol in the exchange */
+	Tracked  float64 `json:"tracked"`  /* Quantity of the open transactions of the symbol across all threads */
+	Orphaned float64 `json:"orphaned"` /* Holdings not tracked by any transaction, rounded down to the lot size step */
+	Unbacked float64 `json:"unbacked"` /* Tracked quantity not held in the exchange */
+	Price    float64 `json:"price"`    /* Last price, suggested cost basis */
+}
+
+// Check reconcile the free balance of sessionData.Symbol in the exchange with the open transactions of the symbol
+// across all threads
+func Check(
+	sessionData *types.Session,
+	marketData *types.Market) (status Status, err error) {
+
+	status.Symbol = sessionData.Symbol
+	status.Holdings = sessionData.SymbolFunds
+	status.Price = marketData.Price
+
+	if status.Tracked, err = mysql.GetThreadSymbolQuantity(sessionData); err != nil {
+
+		return status, err
+
+	}
+
+	status.Orphaned, status.Unbacked = reconcile(
+		status.Holdings,
+		status.Tracked,
+		sessionData.StepSize,
+		sessionData.MinQuantity,
+		sessionData.MinNotional,
+		status.Price)
+
+	return status, nil
+
+}
+
+// Detect log and notify orphaned holdings or unbacked transactions of sessionData.Symbol, run when the thread starts
+func Detect(
+	configData *types.Config,
+	sessionData *types.Session,
+	marketData *types.Market) {
+
+	var message string
+
+	status, err := Check(sessionData, marketData)
+
+	switch {
+	case err != nil:
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   marketData,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return
+
+	case status.Orphaned > 0:
+
+		message = "Orphaned holdings " + strconv.FormatFloat(status.Orphaned, 'f', -1, 64) + " " + status.Symbol + " not tracked by thread transactions, adopt them in the thread detail page"
+
+	case status.Unbacked > 0:
+
+		message = "Thread transactions " + strconv.FormatFloat(status.Unbacked, 'f', -1, 64) + " " + status.Symbol + " not backed by exchange holdings, release them in the thread detail page"
+
+	default:
+
+		return
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   marketData,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Warning,
+		Key:      "adoption-" + sessionData.ThreadID,
+		Title:    "Position mismatch",
+		Data: messages.Data{
+			ThreadID: sessionData.ThreadID,
+			Symbol:   sessionData.Symbol,
+			Message:  message,
+		},
+	}.Send(configData, sessionData)
+
+}
+
+// Parse validate an adoption entered by an operator. Empty quantity adopts all the orphaned holdings.
+func Parse(
+	quantity string,
+	costBasis string) (adoptQuantity float64, adoptCostBasis float64, err error) {
+
+	if quantity = strings.TrimSpace(quantity); quantity != "" {
+
+		if adoptQuantity, err = strconv.ParseFloat(quantity, 64); err != nil || adoptQuantity <= 0 {
+
+			return 0, 0, ErrQuantity
+
+		}
+
+	}
+
+	if adoptCostBasis, err = strconv.ParseFloat(strings.TrimSpace(costBasis), 64); err != nil || adoptCostBasis <= 0 {
+
+		return 0, 0, ErrCostBasis
+
+	}
+
+	return adoptQuantity, adoptCostBasis, nil
+
+}
+
+// Adopt import quantity of the orphaned holdings of sessionData.Symbol, all of them when quantity is 0, as an open
+// transaction of the thread at costBasis per unit. The transaction is recorded as a filled BUY with the adoption
+// source and a negative OrderID, as it has no exchange order.
+func Adopt(
+	configData *types.Config,
+	sessionData *types.Session,
+	marketData *types.Market,
+	username string,
+	quantity float64,
+	costBasis float64) (order *types.Order, err error) {
+
+	if sessionData.ThreadID == "" {
+
+		return nil, ErrNoThread
+
+	}
+
+	if costBasis <= 0 {
+
+		return nil, ErrCostBasis
+
+	}
+
+	/* Enter and defer exiting busy mode */
+	sessionData.Busy = true
+	defer func() {
+		sessionData.Busy = false
+	}()
+
+	status, err := Check(sessionData, marketData)
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	if quantity, err = adoptQuantity(quantity, status.Orphaned, sessionData.StepSize, sessionData.MinQuantity); err != nil {
+
+		return nil, err
+
+	}
+
+	transactTime := time.Now().UnixNano() / int64(time.Millisecond)
+
+	order = &types.Order{
+		ClientOrderID:           "adoption-" + strconv.FormatInt(transactTime, 10),
+		CumulativeQuoteQuantity: quantity * costBasis,
+		ExecutedQuantity:        quantity,
+		OrderID:                 -transactTime, /* Not an exchange order */
+		Price:                   costBasis,
+		Side:                    "BUY",
+		Status:                  "FILLED",
+		Symbol:                  sessionData.Symbol,
+		TransactTime:            transactTime,
+	}
+
+	/* Save order to database */
+	if err = mysql.SaveOrder(
+		sessionData,
+		order,
+		0, /* OrderIDSource */
+		costBasis /* OrderPrice */); err != nil {
+
+		return nil, err
+
+	}
+
+	if err = mysql.UpdateOrderSource(sessionData, order.OrderID, OrderSource); err != nil {
+
+		return order, err
+
+	}
+
+	/* Save Thread Transaction */
+	if err = mysql.SaveThreadTransaction(
+		sessionData,
+		order.OrderID,
+		order.CumulativeQuoteQuantity,
+		costBasis,
+		quantity); err != nil {
+
+		return order, err
+
+	}
+
+	_ = cycle.Settle(sessionData) /* The adopted position opens the trade cycle */
+
+	message := "Adopted " + strconv.FormatFloat(quantity, 'f', -1, 64) + " " + sessionData.Symbol + " at cost basis " + strconv.FormatFloat(costBasis, 'f', -1, 64) + " by " + username
+
+	_ = mysql.SaveThreadEvent(sessionData, eventKind, username, message) /* Thread timeline */
+
+	logger.LogEntry{ /* Log Entry */
+		Config:  configData,
+		Market:  marketData,
+		Session: sessionData,
+		Order: &types.Order{
+			OrderID: order.OrderID,
+			Price:   costBasis,
+		},
+		Message:  message,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return order, nil
+
+}
+
+// Release remove the open transaction orderID of the thread without a sale, when the open transactions of
+// sessionData.Symbol are not backed by the exchange holdings. Holdings left untracked by the release can be adopted.
+func Release(
+	configData *types.Config,
+	sessionData *types.Session,
+	marketData *types.Market,
+	username string,
+	orderID int64) (err error) {
+
+	var orders []types.Order
+	var released *types.Order
+
+	if sessionData.ThreadID == "" {
+
+		return ErrNoThread
+
+	}
+
+	/* Enter and defer exiting busy mode */
+	sessionData.Busy = true
+	defer func() {
+		sessionData.Busy = false
+	}()
+
+	if orders, err = mysql.GetThreadTransactionByThreadID(sessionData); err != nil {
+
+		return err
+
+	}
+
+	for i := range orders {
+
+		if orders[i].OrderID == orderID {
+
+			released = &orders[i]
+
+		}
+
+	}
+
+	if released == nil {
+
+		return ErrNotOpen
+
+	}
+
+	status, err := Check(sessionData, marketData)
+	if err != nil {
+
+		return err
+
+	}
+
+	if status.Unbacked == 0 {
+
+		return ErrBacked
+
+	}
+
+	if err = mysql.DeleteThreadTransactionByOrderID(sessionData, orderID); err != nil {
+
+		return err
+
+	}
+
+	_ = cycle.Settle(sessionData) /* Releasing the last transaction leaves the trade cycle idle */
+
+	message := "Released " + strconv.FormatFloat(released.ExecutedQuantity, 'f', -1, 64) + " " + sessionData.Symbol + " not backed by exchange holdings by " + username
+
+	_ = mysql.SaveThreadEvent(sessionData, eventKind, username, message) /* Thread timeline */
+
+	logger.LogEntry{ /* Log Entry */
+		Config:  configData,
+		Market:  marketData,
+		Session: sessionData,
+		Order: &types.Order{
+			OrderID: orderID,
+			Price:   released.Price,
+		},
+		Message:  message,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+/* Return the untracked holdings, 0 below the exchange minimums (dust), and the tracked quantity not held */
+func reconcile(
+	holdings float64,
+	tracked float64,
+	stepSize float64,
+	minQuantity float64,
+	minNotional float64,
+	price float64) (orphaned float64, unbacked float64) {
+
+	difference := holdings - tracked
+
+	if difference < 0 {
+
+		if -difference >= math.Max(stepSize, 1e-8) {
+
+			unbacked = -difference
+
+		}
+
+		return 0, unbacked
+
+	}
+
+	orphaned = roundStep(difference, stepSize)
+
+	if orphaned < minQuantity || (price > 0 && orphaned*price < minNotional) {
+
+		return 0, 0
+
+	}
+
+	return orphaned, 0
+
+}
+
+/* Return quantity rounded down to stepSize, orphaned when quantity is 0, within the exchange minimum and orphaned */
+func adoptQuantity(
+	quantity float64,
+	orphaned float64,
+	stepSize float64,
+	minQuantity float64) (float64, error) {
+
+	if quantity == 0 {
+
+		quantity = orphaned
+
+	}
+
+	quantity = roundStep(quantity, stepSize)
+
+	if quantity <= 0 || quantity < minQuantity || quantity > orphaned+1e-9 {
+
+		return 0, ErrQuantity
+
+	}
+
+	return quantity, nil
+
+}
+
+/* Round quantity down according to the exchange lotSizeStep */
+func roundStep(
+	quantity float64,
+	stepSize float64) float64 {
+
+	if stepSize > 0 {
+
+		return math.Round(math.Floor(quantity/stepSize+1e-9)*stepSize*1e8) / 1e8 /* 8 decimals, the exchange precision */
+
+	}
+
+	return quantity
+
+}
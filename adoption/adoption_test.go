@@ -0,0 +1,115 @@
+package adoption
+
+import "testing"
+
+func Test_reconcile(t *testing.T) {
+	type args struct {
+		holdings    float64
+		tracked     float64
+		stepSize    float64
+		minQuantity float64
+		minNotional float64
+		price       float64
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantOrphaned float64
+		wantUnbacked float64
+	}{
+		{
+			name:         "orphaned",
+			args:         args{holdings: 0.00359, tracked: 0.001, stepSize: 0.0001, minQuantity: 0.0001, minNotional: 10, price: 30000},
+			wantOrphaned: 0.0025,
+		},
+		{
+			name: "dust",
+			args: args{holdings: 0.0012, tracked: 0.001, stepSize: 0.0001, minQuantity: 0.0001, minNotional: 10, price: 30000},
+		},
+		{
+			name:         "unbacked",
+			args:         args{holdings: 0.0005, tracked: 0.002, stepSize: 0.0001, minQuantity: 0.0001, minNotional: 10, price: 30000},
+			wantUnbacked: 0.0015,
+		},
+		{
+			name: "rounding",
+			args: args{holdings: 0.00199, tracked: 0.002, stepSize: 0.0001, minQuantity: 0.0001, minNotional: 10, price: 30000},
+		},
+		{
+			name: "reconciled",
+			args: args{holdings: 0.002, tracked: 0.002, stepSize: 0.0001, minQuantity: 0.0001, minNotional: 10, price: 30000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOrphaned, gotUnbacked := reconcile(tt.args.holdings, tt.args.tracked, tt.args.stepSize, tt.args.minQuantity, tt.args.minNotional, tt.args.price)
+			if gotOrphaned != tt.wantOrphaned {
+				t.Errorf("reconcile() orphaned = %v, want %v", gotOrphaned, tt.wantOrphaned)
+			}
+			if gotUnbacked != tt.wantUnbacked {
+				t.Errorf("reconcile() unbacked = %v, want %v", gotUnbacked, tt.wantUnbacked)
+			}
+		})
+	}
+}
+
+func Test_adoptQuantity(t *testing.T) {
+	type args struct {
+		quantity    float64
+		orphaned    float64
+		stepSize    float64
+		minQuantity float64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr error
+	}{
+		{name: "all", args: args{quantity: 0, orphaned: 0.0025, stepSize: 0.0001, minQuantity: 0.0001}, want: 0.0025},
+		{name: "part", args: args{quantity: 0.00119, orphaned: 0.0025, stepSize: 0.0001, minQuantity: 0.0001}, want: 0.0011},
+		{name: "above orphaned", args: args{quantity: 0.003, orphaned: 0.0025, stepSize: 0.0001, minQuantity: 0.0001}, wantErr: ErrQuantity},
+		{name: "below minimum", args: args{quantity: 0.00005, orphaned: 0.0025, stepSize: 0.00001, minQuantity: 0.0001}, wantErr: ErrQuantity},
+		{name: "nothing orphaned", args: args{quantity: 0, orphaned: 0, stepSize: 0.0001, minQuantity: 0.0001}, wantErr: ErrQuantity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := adoptQuantity(tt.args.quantity, tt.args.orphaned, tt.args.stepSize, tt.args.minQuantity)
+			if err != tt.wantErr {
+				t.Errorf("adoptQuantity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("adoptQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		quantity      string
+		costBasis     string
+		wantQuantity  float64
+		wantCostBasis float64
+		wantErr       error
+	}{
+		{name: "all", quantity: " ", costBasis: "27150.5", wantCostBasis: 27150.5},
+		{name: "quantity", quantity: "0.0025", costBasis: "27150.5", wantQuantity: 0.0025, wantCostBasis: 27150.5},
+		{name: "quantity error", quantity: "-1", costBasis: "27150.5", wantErr: ErrQuantity},
+		{name: "cost basis error", quantity: "0.0025", costBasis: "", wantErr: ErrCostBasis},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuantity, gotCostBasis, err := Parse(tt.quantity, tt.costBasis)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotQuantity != tt.wantQuantity || gotCostBasis != tt.wantCostBasis {
+				t.Errorf("Parse() = %v, %v, want %v, %v", gotQuantity, gotCostBasis, tt.wantQuantity, tt.wantCostBasis)
+			}
+		})
+	}
+}
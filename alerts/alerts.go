@@ -0,0 +1,430 @@
+package alerts
+
+/* This package implements the alert rules engine. Alert rules compare a metric of the running thread with a
+threshold, i.e. "unrealized loss above 5%" or "no trades for more than 6 hours", and are routed to Telegram, a
+webhook or the log. Every thread evaluates the rules that apply to it every minute. A rule fires once when its
+condition becomes true and fires again only after the condition cleared. */
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/telegram"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// Metric struct define a thread metric available to alert rules
+type Metric struct {
+	Name        string
+	Description string
+}
+
+// Metrics list the thread metrics available to alert rules
+var Metrics = []Metric{
+	{Name: "unrealized_loss_pct", Description: "Unrealized loss of the open transactions as percentage of their cost"},
+	{Name: "hours_since_trade", Description: "Hours since the last order of the thread, or since the thread started"},
+	{Name: "open_transactions", Description: "Number of open transactions of the thread"},
+	{Name: "fiat_funds", Description: "Free fiat funds of the exchange account"},
+	{Name: "drawdown_pct", Description: "Drawdown from the equity peak across all threads"},
+}
+
+// Operators list the comparison operators of alert rules
+var Operators = []string{">", "<"}
+
+// Channels list the channels alert rules are routed to
+var Channels = []string{"telegram", "webhook", "log"}
+
+/* Alert rule errors */
+var (
+	ErrInvalidName      = errors.New("Name must have 1 to 64 characters")
+	ErrInvalidMetric    = errors.New("Invalid metric")
+	ErrInvalidOperator  = errors.New("Operator must be > or <")
+	ErrInvalidThreshold = errors.New("Threshold must be a number")
+	ErrInvalidChannel   = errors.New("Channel must be telegram, webhook or log")
+	ErrInvalidTarget    = errors.New("Webhook channel requires an http or https URL")
+)
+
+var started = time.Now() /* Thread start, for threads without orders */
+
+var validName = regexp.MustCompile(`^.{1,64}$`)
+
+// Page struct define the alert rules page (alerts.html)
+type Page struct {
+	Rules     []types.AlertRule
+	Metrics   []Metric
+	Operators []string
+	Channels  []string
+	ThreadID  string /* Running thread */
+	CanAdmin  bool
+	Message   string
+	Theme     string /* UI theme of the logged in user */
+}
+
+// LoadPage load the alert rules page
+func LoadPage(
+	sessionData *types.Session,
+	message string) (page Page) {
+
+	var err error
+
+	page.Metrics = Metrics
+	page.Operators = Operators
+	page.Channels = Channels
+	page.ThreadID = sessionData.ThreadID
+	page.Message = message
+
+	if page.Rules, err = mysql.GetAlertRules(sessionData); err != nil && page.Message == "" {
+
+		page.Message = err.Error()
+
+	}
+
+	return page
+
+}
+
+// Parse validate an alert rule entered in the alert rules page
+func Parse(
+	name string,
+	threadID string,
+	metric string,
+	operator string,
+	threshold string,
+	channel string,
+	target string) (rule types.AlertRule, err error) {
+
+	rule = types.AlertRule{
+		Name:     strings.TrimSpace(name),
+		ThreadID: strings.TrimSpace(threadID),
+		Metric:   metric,
+		Operator: operator,
+		Channel:  channel,
+		Target:   strings.TrimSpace(target),
+	}
+
+	if !validName.MatchString(rule.Name) {
+
+		return rule, ErrInvalidName
+
+	}
+
+	if !isMetric(rule.Metric) {
+
+		return rule, ErrInvalidMetric
+
+	}
+
+	if !slices.Contains(Operators, rule.Operator) {
+
+		return rule, ErrInvalidOperator
+
+	}
+
+	if rule.Threshold, err = strconv.ParseFloat(strings.TrimSpace(threshold), 64); err != nil {
+
+		return rule, ErrInvalidThreshold
+
+	}
+
+	if !slices.Contains(Channels, rule.Channel) {
+
+		return rule, ErrInvalidChannel
+
+	}
+
+	if rule.Channel == "webhook" && !strings.HasPrefix(rule.Target, "http://") && !strings.HasPrefix(rule.Target, "https://") {
+
+		return rule, ErrInvalidTarget
+
+	}
+
+	if rule.Channel != "webhook" {
+
+		rule.Target = ""
+
+	}
+
+	return rule, nil
+
+}
+
+// Evaluate the alert rules of the running thread and notify the rules that fired
+func Evaluate(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) {
+
+	var err error
+	var rules []types.AlertRule
+	var values map[string]float64
+
+	if sessionData.ThreadID == "" {
+
+		return
+
+	}
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if rules, err = mysql.GetAlertRules(sessionData); err != nil || len(rules) == 0 {
+
+		return
+
+	}
+
+	if values, err = metricValues(marketData, sessionData); err != nil {
+
+		return
+
+	}
+
+	if sessionData.AlertsFired == nil {
+
+		sessionData.AlertsFired = make(map[int64]bool)
+
+	}
+
+	for _, rule := range rules {
+
+		if rule.ThreadID != "" && rule.ThreadID != sessionData.ThreadID {
+
+			continue
+
+		}
+
+		value := values[rule.Metric]
+
+		if !triggered(rule, value) {
+
+			delete(sessionData.AlertsFired, rule.ID) /* Condition cleared, the rule may fire again */
+			continue
+
+		}
+
+		if sessionData.AlertsFired[rule.ID] {
+
+			continue
+
+		}
+
+		sessionData.AlertsFired[rule.ID] = true
+
+		notify(configData, sessionData, rule, value)
+
+	}
+
+}
+
+/* Return the metrics of the running thread */
+func metricValues(
+	marketData *types.Market,
+	sessionData *types.Session) (values map[string]float64, err error) {
+
+	var orders []types.Order
+	var lastOrderTime int64
+
+	if orders, err = mysql.GetThreadTransactionByThreadID(sessionData); err != nil {
+
+		return nil, err
+
+	}
+
+	if lastOrderTime, err = mysql.GetThreadLastOrderTime(sessionData); err != nil {
+
+		return nil, err
+
+	}
+
+	last := started
+	if lastOrderTime > 0 && time.Unix(0, lastOrderTime*int64(time.Millisecond)).After(started) {
+		last = time.Unix(0, lastOrderTime*int64(time.Millisecond))
+	}
+
+	values = map[string]float64{
+		"unrealized_loss_pct": unrealizedLossPct(orders, marketData.Price),
+		"hours_since_trade":   time.Since(last).Hours(),
+		"open_transactions":   float64(len(orders)),
+		"fiat_funds":          sessionData.SymbolFiatFunds,
+	}
+
+	if sessionData.Global != nil {
+
+		values["drawdown_pct"] = sessionData.Global.Drawdown * 100
+
+	}
+
+	return values, nil
+
+}
+
+/* Return the unrealized loss of orders at price as percentage of their cost, negative for a profit */
+func unrealizedLossPct(
+	orders []types.Order,
+	price float64) float64 {
+
+	var cost, value float64
+
+	for _, order := range orders {
+
+		cost += order.CumulativeQuoteQuantity
+		value += order.ExecutedQuantity * price
+
+	}
+
+	if cost == 0 || price == 0 {
+
+		return 0
+
+	}
+
+	return (cost - value) / cost * 100
+
+}
+
+/* Return true when value meets the rule condition */
+func triggered(
+	rule types.AlertRule,
+	value float64) bool {
+
+	switch rule.Operator {
+	case ">":
+		return value > rule.Threshold
+	case "<":
+		return value < rule.Threshold
+	}
+
+	return false
+
+}
+
+/* Route the alert to the rule channel, Telegram alerts are logged when the thread is not connected to Telegram */
+func notify(
+	configData *types.Config,
+	sessionData *types.Session,
+	rule types.AlertRule,
+	value float64) {
+
+	text := fmt.Sprintf("Alert %s @ %s - %s %.2f %s %.2f", rule.Name, sessionData.ThreadID, rule.Metric, value, rule.Operator, rule.Threshold)
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  text,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	switch rule.Channel {
+	case "telegram":
+
+		if sessionData.TgBotAPIChatID != 0 {
+
+			telegram.Message{
+				Text: "\f" + text,
+			}.Notify(configData, sessionData, false)
+
+		}
+
+	case "webhook":
+
+		if err := postWebhook(rule, sessionData.ThreadID, value, text); err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+		}
+
+	}
+
+}
+
+/* Post the alert as JSON to the rule webhook URL */
+func postWebhook(
+	rule types.AlertRule,
+	threadID string,
+	value float64,
+	text string) (err error) {
+
+	var body []byte
+	var response *http.Response
+
+	if body, err = json.Marshal(map[string]interface{}{
+		"rule":      rule.Name,
+		"threadId":  threadID,
+		"metric":    rule.Metric,
+		"operator":  rule.Operator,
+		"threshold": rule.Threshold,
+		"value":     value,
+		"text":      text,
+	}); err != nil {
+
+		return err
+
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	if response, err = client.Post(rule.Target, "application/json", bytes.NewReader(body)); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+
+		return errors.New("Webhook " + rule.Target + " returned " + response.Status)
+
+	}
+
+	return nil
+
+}
+
+/* Return true when name is one of Metrics */
+func isMetric(name string) bool {
+
+	for _, metric := range Metrics {
+
+		if metric.Name == name {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
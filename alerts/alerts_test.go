@@ -0,0 +1,161 @@
+package alerts
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		name      string
+		threadID  string
+		metric    string
+		operator  string
+		threshold string
+		channel   string
+		target    string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    types.AlertRule
+		wantErr error
+	}{
+		{
+			name:    "telegram",
+			args:    args{name: "Unrealized loss", metric: "unrealized_loss_pct", operator: ">", threshold: "5", channel: "telegram", target: "https://example.com"},
+			want:    types.AlertRule{Name: "Unrealized loss", Metric: "unrealized_loss_pct", Operator: ">", Threshold: 5, Channel: "telegram"},
+			wantErr: nil,
+		},
+		{
+			name:    "webhook",
+			args:    args{name: "Idle", threadID: "c683ok5mk1u1120gnmmg", metric: "hours_since_trade", operator: ">", threshold: "6", channel: "webhook", target: "https://example.com/hook"},
+			want:    types.AlertRule{Name: "Idle", ThreadID: "c683ok5mk1u1120gnmmg", Metric: "hours_since_trade", Operator: ">", Threshold: 6, Channel: "webhook", Target: "https://example.com/hook"},
+			wantErr: nil,
+		},
+		{
+			name:    "webhook without URL",
+			args:    args{name: "Idle", metric: "hours_since_trade", operator: ">", threshold: "6", channel: "webhook"},
+			wantErr: ErrInvalidTarget,
+		},
+		{
+			name:    "unknown metric",
+			args:    args{name: "Price", metric: "price", operator: ">", threshold: "1", channel: "log"},
+			wantErr: ErrInvalidMetric,
+		},
+		{
+			name:    "invalid threshold",
+			args:    args{name: "Funds", metric: "fiat_funds", operator: "<", threshold: "ten", channel: "log"},
+			wantErr: ErrInvalidThreshold,
+		},
+		{
+			name:    "empty name",
+			args:    args{name: " ", metric: "fiat_funds", operator: "<", threshold: "10", channel: "log"},
+			wantErr: ErrInvalidName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.name, tt.args.threadID, tt.args.metric, tt.args.operator, tt.args.threshold, tt.args.channel, tt.args.target)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_triggered(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  types.AlertRule
+		value float64
+		want  bool
+	}{
+		{
+			name:  "above",
+			rule:  types.AlertRule{Operator: ">", Threshold: 5},
+			value: 5.5,
+			want:  true,
+		},
+		{
+			name:  "equal is not above",
+			rule:  types.AlertRule{Operator: ">", Threshold: 5},
+			value: 5,
+			want:  false,
+		},
+		{
+			name:  "below",
+			rule:  types.AlertRule{Operator: "<", Threshold: 100},
+			value: 50,
+			want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := triggered(tt.rule, tt.value); got != tt.want {
+				t.Errorf("triggered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_unrealizedLossPct(t *testing.T) {
+	orders := []types.Order{
+		{CumulativeQuoteQuantity: 100, ExecutedQuantity: 0.0025},
+		{CumulativeQuoteQuantity: 100, ExecutedQuantity: 0.0025},
+	}
+	tests := []struct {
+		name  string
+		price float64
+		want  float64
+	}{
+		{
+			name:  "loss",
+			price: 38000,
+			want:  5,
+		},
+		{
+			name:  "profit",
+			price: 42000,
+			want:  -5,
+		},
+		{
+			name:  "no price",
+			price: 0,
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unrealizedLossPct(orders, tt.price); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("unrealizedLossPct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_postWebhook(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	rule := types.AlertRule{Name: "Idle", Metric: "hours_since_trade", Operator: ">", Threshold: 6, Channel: "webhook", Target: server.URL}
+	if err := postWebhook(rule, "c683ok5mk1u1120gnmmg", 7, "Alert Idle"); err != nil {
+		t.Errorf("postWebhook() error = %v", err)
+	}
+	if got["rule"] != "Idle" || got["threadId"] != "c683ok5mk1u1120gnmmg" || got["value"] != float64(7) {
+		t.Errorf("postWebhook() body = %v", got)
+	}
+}
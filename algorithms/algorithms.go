@@ -6,14 +6,25 @@ import (
 	"sync"
 	"time"
 
+	"github.com/aleibovici/cryptopump/calendar"
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/events"
 	"github.com/aleibovici/cryptopump/exchange"
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
 	"github.com/aleibovici/cryptopump/markets"
+	"github.com/aleibovici/cryptopump/metrics"
 	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/perf"
 	"github.com/aleibovici/cryptopump/plotter"
+	"github.com/aleibovici/cryptopump/reload"
+	"github.com/aleibovici/cryptopump/risk"
+	"github.com/aleibovici/cryptopump/shadow"
+	"github.com/aleibovici/cryptopump/supervisor"
 	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/tracing"
 	"github.com/aleibovici/cryptopump/types"
+	"github.com/aleibovici/cryptopump/wsstats"
 
 	"github.com/adshao/go-binance/v2"
 )
@@ -55,6 +66,79 @@ func (c Channel) Stop(
 
 }
 
+// Watch wait for a websocket channel to disconnect. A channel that silently stops delivering updates
+// for markets.StaleTimeout is stopped so that the connection is re-established, returning stale true.
+func (c Channel) Watch(
+	doneC chan struct{},
+	stopC chan struct{},
+	lastUpdate *time.Time,
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) (stale bool) {
+
+	start := time.Now() /* A new channel is not stale before its first update */
+
+	for {
+
+		select {
+		case <-doneC:
+
+			return false
+
+		case <-time.After(5 * time.Second):
+
+			last := *lastUpdate
+
+			if last.Before(start) {
+
+				last = start
+
+			}
+
+			if time.Since(last) <= markets.StaleTimeout(configData) {
+
+				continue
+
+			}
+
+			marketData.Stale = true
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   marketData,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  "Market data stale - no " + c.name + " update since " + last.Format("15:04:05") + ", reconnecting",
+				LogLevel: "InfoLevel",
+			}.Do()
+
+			stopC <- struct{}{} /* Stop websocket channel */
+			<-doneC
+
+			return true
+
+		}
+
+	}
+
+}
+
+/* Return the disconnection reason of a websocket channel */
+func disconnectReason(
+	sessionData *types.Session,
+	stale bool) string {
+
+	switch {
+	case sessionData.StopWs:
+		return wsstats.ReasonStopped
+	case stale:
+		return wsstats.ReasonStale
+	default:
+		return wsstats.ReasonClosed
+	}
+
+}
+
 // SetTrue set all goroutines to stop
 func (c Channel) SetTrue(sessionData *types.Session) {
 
@@ -106,7 +190,11 @@ func UpdatePendingOrders(
 	var order types.Order
 	var orderStatus *types.Order
 
-	if order, err = mysql.GetOrderTransactionPending(sessionData); err != nil {
+	if order, err = mysql.GetOrderTransactionPending(sessionData); errors.Is(err, mysql.ErrDBUnavailable) {
+
+		return /* Retried on the next run, errors are logged by mysql */
+
+	} else if err != nil {
 
 		/* Cleanly exit ThreadID */
 		threads.Thread{}.Terminate(sessionData, functions.GetFunctionName()+" - "+err.Error())
@@ -131,7 +219,7 @@ func UpdatePendingOrders(
 			orderStatus.CumulativeQuoteQuantity,
 			orderStatus.ExecutedQuantity,
 			orderStatus.Price,
-			string(orderStatus.Status)); err != nil {
+			string(orderStatus.Status)); err != nil && !errors.Is(err, mysql.ErrDBUnavailable) { /* Retried on the next run */
 
 			/* Cleanly exit ThreadID */
 			threads.Thread{}.Terminate(sessionData, functions.GetFunctionName()+" - "+err.Error())
@@ -152,6 +240,107 @@ func is24hsHighPrice(
 
 }
 
+/* Check if order book ask side depth exceeds bid side depth by configData.BuyOrderBookAskBidRatio */
+func isOrderBookAskHeavy(
+	configData *types.Config,
+	marketData *types.Market) bool {
+
+	if configData.BuyOrderBookAskBidRatio == 0 || /* Rule disabled */
+		marketData.OrderBook == nil { /* Order book not yet loaded, or its last retrieval failed */
+
+		return false
+
+	}
+
+	return marketData.OrderBookAskDepth > (marketData.OrderBookBidDepth * configData.BuyOrderBookAskBidRatio)
+
+}
+
+/* Start a cooldown when the last configData.BuyLossStreakCount cycles since the previous cooldown were all losses */
+func updateLossStreakCooldown(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) {
+
+	var err error
+	var profits []float64
+	var transactTimes []int64
+
+	if configData.BuyLossStreakCount == 0 { /* Cooldown disabled */
+
+		return
+
+	}
+
+	if profits, transactTimes, err = mysql.GetThreadCycleProfitLast(
+		sessionData,
+		configData.BuyLossStreakCount); err != nil {
+
+		return
+
+	}
+
+	if len(profits) < configData.BuyLossStreakCount {
+
+		return
+
+	}
+
+	for key := range profits {
+
+		/* Stop if a cycle was profitable or closed before the previous cooldown started */
+		if profits[key] >= 0 ||
+			(transactTimes[key]/1000) <= sessionData.CooldownStart.Unix() {
+
+			return
+
+		}
+
+	}
+
+	sessionData.CooldownStart = time.Now()
+	sessionData.CooldownUntil = time.Now().Add(time.Duration(configData.BuyLossStreakCooldown) * time.Minute)
+
+	_ = mysql.UpdateSessionCooldown(sessionData)
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   marketData,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Loss streak cooldown until " + sessionData.CooldownUntil.Format("2006-01-02 15:04:05"),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+}
+
+/* Check if a loss streak cooldown is active, ending it early when the trend turns positive (MA7 above MA14) */
+func isLossStreakCooldown(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) bool {
+
+	if !time.Now().Before(sessionData.CooldownUntil) {
+
+		return false
+
+	}
+
+	if configData.BuyLossStreakTrendExit &&
+		marketData.Ma7 > marketData.Ma14 {
+
+		sessionData.CooldownUntil = time.Now()
+
+		_ = mysql.UpdateSessionCooldown(sessionData)
+
+		return false
+
+	}
+
+	return true
+
+}
+
 /* Verify that an order is in a sellable time range
 This function help to avoid issue when a sale happen in the same seccond as the Buy transaction. Duration must be provided in seconds */
 func isOrderInTimeRangeToSell(
@@ -204,21 +393,36 @@ func isBuyUpmarket(
 
 	}
 
-	/* Validate RSI7 lower than buy_rsi7_entry */
-	if marketData.Rsi7 > configData.BuyRsi7Entry {
+	if configData.BuyScoreThreshold > 0 {
 
-		sessionData.BuyDecisionTreeResult = "RSI7 higher than threshold"
+		/* Validate weighted indicator score instead of individual indicators */
+		if !isBuyScoreReached(configData, marketData, sessionData, configData.BuyDirectionUp) {
 
-		return false, 0
+			sessionData.BuyDecisionTreeResult = "Buy score lower than threshold"
 
-	}
+			return false, 0
 
-	/* If Market Direction is less than configData.BuyDirectionUp do not buy. Defined in WsKline. */
-	if marketData.Direction < configData.BuyDirectionUp {
+		}
 
-		sessionData.BuyDecisionTreeResult = "Upmarket direction not reached"
+	} else {
 
-		return false, 0
+		/* Validate RSI7 lower than buy_rsi7_entry */
+		if marketData.Rsi7 > configData.BuyRsi7Entry {
+
+			sessionData.BuyDecisionTreeResult = "RSI7 higher than threshold"
+
+			return false, 0
+
+		}
+
+		/* If Market Direction is less than configData.BuyDirectionUp do not buy. Defined in WsKline. */
+		if marketData.Direction < configData.BuyDirectionUp {
+
+			sessionData.BuyDecisionTreeResult = "Upmarket direction not reached"
+
+			return false, 0
+
+		}
 
 	}
 
@@ -337,6 +541,166 @@ func isBuyUpmarket(
 
 }
 
+/* Calculate the weighted indicator score, stored in sessionData.BuyScore, and validate it reaches configData.BuyScoreThreshold */
+func isBuyScoreReached(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	direction int) bool {
+
+	var score float64
+
+	if marketData.Rsi7 < configData.BuyRsi7Entry {
+		score += configData.BuyScoreRsi7
+	}
+
+	if marketData.MACD > 0 {
+		score += configData.BuyScoreMacd
+	}
+
+	if marketData.Direction >= direction {
+		score += configData.BuyScoreDirection
+	}
+
+	if marketData.Ma7 > marketData.Ma14 {
+		score += configData.BuyScoreMa
+	}
+
+	if marketData.OrderBookImbalance > 0 {
+		score += configData.BuyScoreOrderBook
+	}
+
+	sessionData.BuyScore = score
+
+	return score >= configData.BuyScoreThreshold
+
+}
+
+/* Aggregate trailing stop on the weighted average entry of all thread transactions, activated at configData.SellTrailingActivation profit and triggered on a configData.SellTrailingDistance pullback from the persisted high-water mark */
+func isTrailingStop(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) bool {
+
+	var err error
+	var averageEntry float64
+
+	if configData.SellTrailingActivation == 0 { /* Trailing stop disabled */
+
+		return false
+
+	}
+
+	if sessionData.TrailingStopTriggered { /* Keep selling until the stack is empty */
+
+		return true
+
+	}
+
+	if sessionData.TrailingHigh == 0 {
+
+		if averageEntry, err = mysql.GetThreadAverageEntry(sessionData); err != nil || averageEntry == 0 {
+
+			return false
+
+		}
+
+		if (marketData.Price/averageEntry)-1 < configData.SellTrailingActivation {
+
+			return false
+
+		}
+
+	}
+
+	if marketData.Price > sessionData.TrailingHigh { /* New high-water mark */
+
+		sessionData.TrailingHigh = marketData.Price
+		_ = mysql.UpdateSessionTrailingHigh(sessionData)
+
+		return false
+
+	}
+
+	if marketData.Price > sessionData.TrailingHigh*(1-configData.SellTrailingDistance) {
+
+		return false
+
+	}
+
+	sessionData.TrailingStopTriggered = true
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   marketData,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Trailing stop triggered at " + functions.Float64ToStr(marketData.Price, 2) + " high " + functions.Float64ToStr(sessionData.TrailingHigh, 2),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return true
+
+}
+
+/* Clear the aggregate trailing stop when the thread has no transactions */
+func resetTrailingStop(sessionData *types.Session) {
+
+	if sessionData.TrailingHigh == 0 && !sessionData.TrailingStopTriggered {
+
+		return
+
+	}
+
+	sessionData.TrailingHigh = 0
+	sessionData.TrailingStopTriggered = false
+	_ = mysql.UpdateSessionTrailingHigh(sessionData)
+
+}
+
+/* Return the stoploss ratio, tightened to configData.SellVolatilityStoploss while the volatility circuit breaker is tripped */
+func stoplossRatio(
+	configData *types.Config,
+	sessionData *types.Session) float64 {
+
+	if sessionData.VolatilityHalt && configData.SellVolatilityStoploss > 0 {
+
+		return configData.SellVolatilityStoploss
+
+	}
+
+	return configData.Stoploss
+
+}
+
+/* Publish the stoploss event of order sold at the market price for reason */
+func publishStoploss(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	order types.Order,
+	reason string,
+	title string) {
+
+	events.Publish(configData, sessionData, events.StoplossTriggered, events.Stoploss{
+		Order:  order,
+		Symbol: sessionData.Symbol,
+		Price:  marketData.Price,
+		Reason: reason,
+		Title:  title,
+	})
+
+}
+
+/* Check if marketData.Price is at or below the absolute stop price defined for the thread */
+func isStopPriceReached(
+	marketData *types.Market,
+	sessionData *types.Session) bool {
+
+	return sessionData.StopPrice > 0 && marketData.Price <= sessionData.StopPrice
+
+}
+
 /* Buy Downmarket algorithms */
 func isBuyDownmarket(
 	configData *types.Config,
@@ -363,8 +727,18 @@ func isBuyDownmarket(
 
 	}
 
-	/* Validate market direction is uptrend */
-	if marketData.Direction < configData.BuyDirectionDown {
+	if configData.BuyScoreThreshold > 0 {
+
+		/* Validate weighted indicator score instead of individual indicators */
+		if !isBuyScoreReached(configData, marketData, sessionData, configData.BuyDirectionDown) {
+
+			sessionData.BuyDecisionTreeResult = "Buy score lower than threshold"
+
+			return false, 0
+
+		}
+
+	} else if marketData.Direction < configData.BuyDirectionDown { /* Validate market direction is uptrend */
 
 		sessionData.BuyDecisionTreeResult = "Downmarket direction not reached"
 
@@ -436,9 +810,23 @@ func isBuyInitial(
 	marketData *types.Market,
 	sessionData *types.Session) (bool, float64) {
 
-	/* Validate RSI7 lower than buy_rsi7_entry */
-	/* Validate RSI3 not negative */
-	if marketData.Rsi7 < configData.BuyRsi7Entry && marketData.Rsi3 > 0 {
+	var is bool
+
+	if configData.BuyScoreThreshold > 0 {
+
+		/* Validate weighted indicator score instead of individual indicators */
+		/* Validate RSI3 not negative */
+		is = isBuyScoreReached(configData, marketData, sessionData, configData.BuyDirectionUp) && marketData.Rsi3 > 0
+
+	} else {
+
+		/* Validate RSI7 lower than buy_rsi7_entry */
+		/* Validate RSI3 not negative */
+		is = marketData.Rsi7 < configData.BuyRsi7Entry && marketData.Rsi3 > 0
+
+	}
+
+	if is {
 
 		/* Do not log if DryRun mode set to true */
 		if !configData.DryRun {
@@ -468,6 +856,8 @@ func WsUserDataServe(
 	sessionData *types.Session,
 	wg *sync.WaitGroup) {
 
+	defer supervisor.Recover(configData, sessionData) /* Restart the thread when the websocket channel can't start */
+
 	var doneC chan struct{}
 	var stopC chan struct{}
 	var err error
@@ -489,8 +879,11 @@ func WsUserDataServe(
 	wsHandler := &types.WsHandler{}
 	wsHandler.BinanceWsUserDataServe = func(message []byte) {
 
+		defer supervisor.Recover(configData, sessionData) /* Restart the thread on the panics of the handler */
+
 		/* This session variable stores the time of the last WsUserDataServe used for status check */
 		sessionData.LastWsUserDataServeTime = time.Now()
+		wsstats.Message(sessionData.ThreadID, metrics.StreamUserData) /* Count the message for the connection statistics */
 
 		/* Stop Ws channel */
 		if sessionData.StopWs {
@@ -567,6 +960,8 @@ func WsUserDataServe(
 
 	errHandler := func(err error) {
 
+		wsstats.Error(sessionData.ThreadID, metrics.StreamUserData, err) /* Last error of the connection */
+
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
 			Market:   nil,
@@ -588,8 +983,12 @@ func WsUserDataServe(
 
 		}
 
+		wsstats.Connected(sessionData.ThreadID, metrics.StreamUserData, time.Now())
+
 		<-doneC
 
+		wsstats.Disconnected(sessionData, metrics.StreamUserData, disconnectReason(sessionData, false), time.Now())
+
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
 			Market:   nil,
@@ -599,6 +998,8 @@ func WsUserDataServe(
 			LogLevel: "DebugLevel",
 		}.Do()
 
+		metrics.WebsocketReconnect(metrics.StreamUserData) /* Count the reconnection for the metrics endpoint */
+
 		time.Sleep(time.Second / 3) /* Sleep for 3 seconds */
 
 	}
@@ -612,6 +1013,8 @@ func WsKline(
 	sessionData *types.Session,
 	wg *sync.WaitGroup) {
 
+	defer supervisor.Recover(configData, sessionData) /* Restart the thread when the websocket channel can't start */
+
 	var doneC chan struct{}
 	var stopC chan struct{}
 	var err error
@@ -619,8 +1022,11 @@ func WsKline(
 	wsHandler := &types.WsHandler{}
 	wsHandler.BinanceWsKline = func(event *binance.WsKlineEvent) {
 
+		defer supervisor.Recover(configData, sessionData) /* Restart the thread on the panics of the handler */
+
 		/* This session variable stores the time of the last WsKline used for status check */
 		sessionData.LastWsKlineTime = time.Now()
+		wsstats.Message(sessionData.ThreadID, metrics.StreamKline) /* Count the message for the connection statistics */
 
 		/* Stop Ws channel */
 		if sessionData.StopWs {
@@ -668,6 +1074,8 @@ func WsKline(
 
 	errHandler := func(err error) {
 
+		wsstats.Error(sessionData.ThreadID, metrics.StreamKline, err) /* Last error of the connection */
+
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
 			Market:   marketData,
@@ -689,7 +1097,13 @@ func WsKline(
 
 		}
 
-		<-doneC
+		wsstats.Connected(sessionData.ThreadID, metrics.StreamKline, time.Now())
+
+		stale := Channel{
+			name: "WsKline",
+		}.Watch(doneC, stopC, &sessionData.LastWsKlineTime, configData, marketData, sessionData) /* Wait for disconnection or stale data */
+
+		wsstats.Disconnected(sessionData, metrics.StreamKline, disconnectReason(sessionData, stale), time.Now())
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
@@ -700,6 +1114,8 @@ func WsKline(
 			LogLevel: "DebugLevel",
 		}.Do()
 
+		metrics.WebsocketReconnect(metrics.StreamKline) /* Count the reconnection for the metrics endpoint */
+
 		time.Sleep(time.Second / 3) /* Sleep for 3 seconds */
 
 	}
@@ -714,6 +1130,8 @@ func WsBookTicker(
 	sessionData *types.Session,
 	wg *sync.WaitGroup) {
 
+	defer supervisor.Recover(configData, sessionData) /* Restart the thread when the websocket channel can't start */
+
 	var doneC chan struct{}
 	var stopC chan struct{}
 	var err error
@@ -721,11 +1139,14 @@ func WsBookTicker(
 	wsHandler := &types.WsHandler{}
 	wsHandler.BinanceWsBookTicker = func(event *binance.WsBookTickerEvent) {
 
+		defer supervisor.Recover(configData, sessionData) /* Restart the thread on the panics of the handler */
+
 		/* Record requests-per-second increment used with github.com/paulbellamy/ratecounter */
 		sessionData.RateCounter.Incr(1)
 
 		/* This session variable stores the time of the last WsBookTicker used for status check */
 		sessionData.LastWsBookTickerTime = time.Now()
+		wsstats.Message(sessionData.ThreadID, metrics.StreamBookTicker) /* Count the message for the connection statistics */
 
 		/* Stop Ws channel */
 		if sessionData.StopWs {
@@ -760,46 +1181,116 @@ func WsBookTicker(
 
 		}
 
+		perf.Tick(sessionData.ThreadID, time.Now()) /* Ticks processed per second of the cycle performance metrics */
+
 		marketData.Price = functions.StrToFloat64(event.BestAskPrice) /* Add current BestAskPrice to marketData struct for wide system use */
 
-		/* Execute decision algorithms for buy and sell */
-		if is, buyQuantityFiat := BuyDecisionTree(
-			configData,
-			marketData,
-			sessionData); is {
+		/* Best ask/bid spread in basis points used by the volatility circuit breaker */
+		if bestBid := functions.StrToFloat64(event.BestBidPrice); bestBid > 0 {
 
-			exchange.BuyTicker(
-				buyQuantityFiat,
-				configData,
+			marketData.Spread = (marketData.Price - bestBid) / ((marketData.Price + bestBid) / 2) * 10000
+
+		}
+
+		/* Apply the configuration and risk limits reloaded by the configuration hot-reload before any decision in this cycle */
+		reload.Apply(configData, sessionData)
+		risk.ApplyLimits(configData, sessionData)
+
+		/* Orders would be rejected while the exchange rate limits the thread */
+		if time.Now().Before(sessionData.RateLimitedUntil) {
+
+			return
+
+		}
+
+		/* Settle a trade cycle closed or failed outside the trade decisions (i.e. forced buys) */
+		if cycle.Settling(sessionData) {
+
+			_ = cycle.Settle(sessionData)
+
+		}
+
+		/* No trade decisions until the order of unknown outcome of the trade cycle is recovered (exchange.RecoverCycle) */
+		if cycle.Unresolved(sessionData) {
+
+			sessionData.BuyDecisionTreeResult = "Trade cycle recovery pending"
+
+			return
+
+		}
+
+		/* Execute decision algorithms for buy and sell */
+		decision := time.Now() /* Decision start, traced when the decision leads to a trade */
+
+		if is, buyQuantityFiat := BuyDecisionTree(
+			configData,
+			marketData,
+			sessionData); is {
+
+			perf.Decision(sessionData.ThreadID, time.Since(decision)) /* Decision latency and trade signal of the cycle performance metrics */
+			perf.Signal(sessionData.ThreadID, time.Now())
+
+			sessionData.CorrelationID = functions.GetCorrelationID() /* A buy starts a new trade cycle */
+
+			trace := tradeTrace(configData, marketData, sessionData, "BUY", decision)
+
+			exchange.BuyTicker(
+				buyQuantityFiat,
+				configData,
 				marketData,
 				sessionData)
 
-			/* Update ThreadCount after BUY */
-			sessionData.ThreadCount, err = mysql.GetThreadTransactionCount(sessionData)
+			/* Update ThreadCount after BUY and settle the trade cycle */
+			err = cycle.Settle(sessionData)
+
+			trace.Finish()
+
+			sessionData.CorrelationID = ""
 
 		} else if is, order := SellDecisionTree(
 			configData,
 			marketData,
 			sessionData); is {
 
+			perf.Decision(sessionData.ThreadID, time.Since(decision))
+			perf.Signal(sessionData.ThreadID, time.Now())
+
+			sessionData.CorrelationID = cycleCorrelationID(sessionData, order) /* The sale closes the trade cycle of the order */
+
+			trace := tradeTrace(configData, marketData, sessionData, "SELL", decision)
+
 			exchange.SellTicker(
 				order,
 				configData,
 				marketData,
 				sessionData)
 
-			/* Update ThreadCount after SELL */
-			sessionData.ThreadCount, err = mysql.GetThreadTransactionCount(sessionData)
+			/* Update ThreadCount after SELL and settle the closed trade cycle */
+			err = cycle.Settle(sessionData)
 
 			/* Update Number of Sale Transactions per hour */
 			sessionData.SellTransactionCount, err = mysql.GetOrderTransactionCount(sessionData, "SELL")
 
+			/* Start a cooldown if the sale completed a loss streak */
+			updateLossStreakCooldown(
+				configData,
+				marketData,
+				sessionData)
+
+			trace.Finish()
+
+			sessionData.CorrelationID = ""
+
+		} else {
+
+			perf.Decision(sessionData.ThreadID, time.Since(decision))
+
 		}
 
 		/* Reload config data every 10 seconds */
 		if time.Now().Second()%10 == 0 {
 
-			configData = functions.GetConfigData(viperData, sessionData)
+			configData = reload.Config(viperData, sessionData)
 
 		}
 
@@ -807,6 +1298,8 @@ func WsBookTicker(
 
 	errHandler := func(err error) {
 
+		wsstats.Error(sessionData.ThreadID, metrics.StreamBookTicker, err) /* Last error of the connection */
+
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
 			Market:   marketData,
@@ -828,7 +1321,13 @@ func WsBookTicker(
 
 		}
 
-		<-doneC
+		wsstats.Connected(sessionData.ThreadID, metrics.StreamBookTicker, time.Now())
+
+		stale := Channel{
+			name: "WsBookTicker",
+		}.Watch(doneC, stopC, &sessionData.LastWsBookTickerTime, configData, marketData, sessionData) /* Wait for disconnection or stale data */
+
+		wsstats.Disconnected(sessionData, metrics.StreamBookTicker, disconnectReason(sessionData, stale), time.Now())
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
@@ -839,6 +1338,8 @@ func WsBookTicker(
 			LogLevel: "DebugLevel",
 		}.Do()
 
+		metrics.WebsocketReconnect(metrics.StreamBookTicker) /* Count the reconnection for the metrics endpoint */
+
 		time.Sleep(time.Second / 3) /* Sleep for 3 seconds */
 
 	}
@@ -851,6 +1352,8 @@ func BuyDecisionTree(
 	marketData *types.Market,
 	sessionData *types.Session) (is bool, buyQuantityFiat float64) {
 
+	sessionData.BuyScore = 0 /* Reset the score of the previous decision */
+
 	/* Protect against the exchange sending zeroed ticker pricing (seen in few occasions with Binance TestNet)*/
 	if marketData.Price == 0 {
 
@@ -869,6 +1372,26 @@ func BuyDecisionTree(
 
 	}
 
+	/* If Symbol is denied by the symbol allow/deny list stop BUY, including Force Buy. */
+	if sessionData.SymbolDenied {
+
+		sessionData.ForceBuy = false
+		sessionData.BuyDecisionTreeResult = "Symbol denied"
+
+		return false, 0
+
+	}
+
+	/* If the thread is shutting down stop BUY, including Force Buy. */
+	if sessionData.Stopping {
+
+		sessionData.ForceBuy = false
+		sessionData.BuyDecisionTreeResult = "Thread stopping"
+
+		return false, 0
+
+	}
+
 	/* Trigger Force Buy */
 	if sessionData.ForceBuy {
 
@@ -878,6 +1401,15 @@ func BuyDecisionTree(
 
 	}
 
+	/* If the thread was paused by an operator stop BUY, existing positions are still sold. Force Buy is still allowed. */
+	if sessionData.Paused {
+
+		sessionData.BuyDecisionTreeResult = "Thread paused"
+
+		return false, 0
+
+	}
+
 	/* If configData.Exit is True stop BUY. */
 	if configData.Exit {
 
@@ -887,10 +1419,99 @@ func BuyDecisionTree(
 
 	}
 
-	/* Validate marketData not older than 100 seconds */
-	if time.Since(marketData.TimeStamp).Seconds() > 100 {
+	/* If an emergency liquidation is active stop BUY until operator re-enable. */
+	if sessionData.Global.Liquidate {
 
-		sessionData.BuyDecisionTreeResult = "Market data older than 100 seconds"
+		sessionData.BuyDecisionTreeResult = "Emergency liquidation active"
+
+		return false, 0
+
+	}
+
+	/* If the drawdown kill switch is active stop BUY until operator re-enable. */
+	if sessionData.Global.DrawdownHalt {
+
+		sessionData.BuyDecisionTreeResult = "Drawdown kill switch active"
+
+		return false, 0
+
+	}
+
+	/* If the daily loss limit was reached stop BUY until next UTC day. */
+	if sessionData.Global.DailyLossHalt {
+
+		sessionData.BuyDecisionTreeResult = "Daily loss limit reached"
+
+		return false, 0
+
+	}
+
+	/* If configData.Rebalance is True orders are placed by the rebalancer, existing positions are still sold. */
+	if configData.Rebalance {
+
+		sessionData.BuyDecisionTreeResult = "Rebalance mode active"
+
+		return false, 0
+
+	}
+
+	/* Validate price and kline data were updated within configData.MarketDataStaleTimeout */
+	if (markets.Data{}).IsStale(configData, marketData, sessionData) {
+
+		sessionData.BuyDecisionTreeResult = "Market data stale"
+
+		return false, 0
+
+	}
+
+	/* Do not BUY while abnormal volatility is detected */
+	if sessionData.VolatilityHalt {
+
+		sessionData.BuyDecisionTreeResult = "Volatility circuit breaker active"
+
+		return false, 0
+
+	}
+
+	/* Do not BUY while price is at or below the absolute stop price */
+	if isStopPriceReached(marketData, sessionData) {
+
+		sessionData.BuyDecisionTreeResult = "Stop price reached"
+
+		return false, 0
+
+	}
+
+	/* Check trading hours. Positions opened earlier are still managed by SellDecisionTree outside the window. */
+	if !functions.IsInTradingWindow(
+		configData,
+		time.Now()) {
+
+		sessionData.BuyDecisionTreeResult = "Outside trading hours"
+
+		return false, 0
+
+	}
+
+	/* Suppress buys around high-impact economic events */
+	if is, event := calendar.IsBlackout(
+		configData,
+		sessionData,
+		time.Now()); is {
+
+		sessionData.BuyDecisionTreeResult = "Event blackout: " + event.Title
+
+		return false, 0
+
+	}
+
+	/* Pause new entries after a loss streak */
+	if isLossStreakCooldown(
+		configData,
+		marketData,
+		sessionData) {
+
+		sessionData.BuyDecisionTreeResult = "Loss streak cooldown"
 
 		return false, 0
 
@@ -918,51 +1539,114 @@ func BuyDecisionTree(
 
 	}
 
+	/* Check if ask side depth dwarfs bid side depth near mid price */
+	if isOrderBookAskHeavy(
+		configData,
+		marketData) {
+
+		sessionData.BuyDecisionTreeResult = "Order book ask depth imbalance"
+
+		return false, 0
+
+	}
+
 	/* Check for subsequent BUY */
 	if sessionData.ThreadCount > 0 {
 
 		/* Buy on DOWNMARKET */
-		if is, buyQuantityFiat := isBuyDownmarket(
+		if is, buyQuantityFiat = isBuyDownmarket(
 			configData,
 			marketData,
-			sessionData); is {
+			sessionData); !is {
 
-			return true, buyQuantityFiat
+			/* Buy on UPMARKET */
+			is, buyQuantityFiat = isBuyUpmarket(
+				configData,
+				marketData,
+				sessionData)
 
 		}
 
-		/* Buy on UPMARKET */
-		if is, buyQuantityFiat := isBuyUpmarket(
+	}
+
+	/* Check for initial BUY */
+	if sessionData.ThreadCount == 0 {
+
+		/* Buy on INITIAL */
+		is, buyQuantityFiat = isBuyInitial(
 			configData,
 			marketData,
-			sessionData); is {
+			sessionData)
 
-			return true, buyQuantityFiat
+	}
 
-		}
+	if !is {
 
 		return false, 0
 
 	}
 
-	/* Check for initial BUY */
-	if sessionData.ThreadCount == 0 {
+	/* Calculate position size according to the configured sizing mode */
+	if buyQuantityFiat = risk.PositionSize(
+		configData,
+		marketData,
+		sessionData,
+		buyQuantityFiat); buyQuantityFiat == 0 {
 
-		/* Buy on INITIAL */
-		if is, buyQuantityFiat := isBuyInitial(
-			configData,
-			marketData,
-			sessionData); is {
+		sessionData.BuyDecisionTreeResult = "Position size is zero"
 
-			return true, buyQuantityFiat
+		return false, 0
 
-		}
+	}
+
+	/* Validate risk limits that depend on the buy quantity */
+	if !isBuyRiskAccepted(
+		configData,
+		marketData,
+		sessionData,
+		buyQuantityFiat) {
 
 		return false, 0
 
 	}
 
-	return false, 0
+	return true, buyQuantityFiat
+
+}
+
+/* Validate risk limits that depend on the buy quantity */
+func isBuyRiskAccepted(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	buyQuantityFiat float64) bool {
+
+	/* Limit aggregate exposure to symbols correlated with this thread symbol */
+	if risk.IsCorrelatedExposureExceeded(
+		configData,
+		sessionData,
+		buyQuantityFiat) {
+
+		sessionData.BuyDecisionTreeResult = "Correlated exposure limit reached"
+
+		return false
+
+	}
+
+	/* Abort the buy when the market order is expected to fill too far above market price */
+	if risk.IsSlippageExceeded(
+		configData,
+		marketData,
+		sessionData,
+		buyQuantityFiat) {
+
+		sessionData.BuyDecisionTreeResult = "Slippage limit exceeded"
+
+		return false
+
+	}
+
+	return true
 
 }
 
@@ -974,9 +1658,18 @@ func SellDecisionTree(
 
 	var err error
 
+	/* Sell the simulated buys of a thread in dry-run mode, it has no transactions */
+	if configData.DryRun && sessionData.ThreadCount == 0 {
+
+		return shadowSellDecision(configData, marketData, sessionData)
+
+	}
+
 	/* Return false if no transactions found */
 	if sessionData.ThreadCount == 0 {
 
+		resetTrailingStop(sessionData) /* The stack is empty, clear the aggregate trailing stop */
+
 		return false, order
 
 	}
@@ -999,15 +1692,90 @@ func SellDecisionTree(
 
 	}
 
-	/* Validate marketData is not older than 100 seconds */
-	if time.Since(marketData.TimeStamp).Seconds() > 100 {
+	/* Liquidate open transactions at market when the drawdown kill switch is active */
+	if sessionData.Global.DrawdownHalt && configData.ConfigGlobal.DrawdownLiquidate {
 
-		sessionData.SellDecisionTreeResult = "Market data older than 100 seconds"
+		if order, err = mysql.GetThreadLastTransaction(sessionData); err != nil {
+
+			return false, order
+
+		}
+
+		sessionData.ForceSell = true /* Execute OrderTypeMarket */
+		sessionData.SellDecisionTreeResult = "Drawdown liquidation"
+
+		return true, order
+
+	}
+
+	/* Liquidate open transactions at market when an emergency liquidation was confirmed */
+	if sessionData.Global.Liquidate {
+
+		if order, err = mysql.GetThreadLastTransaction(sessionData); err != nil {
+
+			return false, order
+
+		}
+
+		sessionData.ForceSell = true /* Execute OrderTypeMarket */
+		sessionData.SellDecisionTreeResult = "Emergency liquidation"
+
+		return true, order
+
+	}
+
+	/* Validate price and kline data were updated within configData.MarketDataStaleTimeout */
+	if (markets.Data{}).IsStale(configData, marketData, sessionData) {
+
+		sessionData.SellDecisionTreeResult = "Market data stale"
 
 		return false, order
 
 	}
 
+	/* Absolute stop price. Sell all thread transactions at market, one per cycle, while price is at or below sessionData.StopPrice */
+	if isStopPriceReached(marketData, sessionData) {
+
+		if order, err = mysql.GetThreadLastTransaction(sessionData); err != nil {
+
+			return false, order
+
+		}
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   marketData,
+			Session:  sessionData,
+			Order:    &order,
+			Message:  "STOPLOSS",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		publishStoploss(configData, marketData, sessionData, order, "stop price", "Stop price hit "+sessionData.Symbol)
+
+		sessionData.ForceSell = true /* Execute OrderTypeMarket */
+		sessionData.SellDecisionTreeResult = "Stop price sale"
+
+		return true, order
+
+	}
+
+	/* Aggregate trailing stop. Sell all thread transactions at market, one per cycle, once triggered */
+	if isTrailingStop(configData, marketData, sessionData) {
+
+		if order, err = mysql.GetThreadLastTransaction(sessionData); err != nil {
+
+			return false, order
+
+		}
+
+		sessionData.ForceSell = true /* Execute OrderTypeMarket */
+		sessionData.SellDecisionTreeResult = "Trailing stop sale"
+
+		return true, order
+
+	}
+
 	/* 	If last canceled transaction (LastSellCanceledTime) is less than (configData.SellWaitAfterCancel) seconds return false
 	   	This function protects against sequential seeling with same pricing */
 	if time.Duration(time.Since(sessionData.LastSellCanceledTime).Seconds()) < time.Duration(configData.SellWaitAfterCancel) {
@@ -1041,10 +1809,10 @@ func SellDecisionTree(
 
 	/* STOPLOSS Loss as ratio that should trigger a sale.
 	Returns the highert Thread order above marketData.Price treshold.*/
-	if configData.Stoploss > 0 {
+	if stoploss := stoplossRatio(configData, sessionData); stoploss > 0 {
 
 		if order, err := mysql.GetThreadTransactionByPriceHigher(marketData, sessionData); err == nil &&
-			(marketData.Price <= (order.Price * (1 - configData.Stoploss))) {
+			(marketData.Price <= (order.Price * (1 - stoploss))) {
 
 			logger.LogEntry{ /* Log Entry */
 				Config:   configData,
@@ -1055,6 +1823,8 @@ func SellDecisionTree(
 				LogLevel: "InfoLevel",
 			}.Do()
 
+			publishStoploss(configData, marketData, sessionData, order, "stoploss", "Stoploss hit "+sessionData.Symbol)
+
 			sessionData.SellDecisionTreeResult = "Stoploss sale"
 
 			return true, order
@@ -1121,6 +1891,15 @@ func SellDecisionTree(
 		(order.Price*(1+calculateProfit(configData, sessionData))) &&
 		order.OrderID != 0 {
 
+		/* Sale must cover round-trip commissions at the account commission rate plus configData.ProfitNetMin */
+		if marketData.Price < exchange.MinimumSellPrice(configData, sessionData, order.Price) {
+
+			sessionData.SellDecisionTreeResult = "Net profit after commissions not reached"
+
+			return false, order
+
+		}
+
 		/* Hold sale if RSI3 above defined threshold.
 		The objective of this setting is to extend the holding as long as possible while ticker price is climbing */
 		if marketData.Rsi3 > configData.SellHoldOnRSI3 {
@@ -1142,3 +1921,73 @@ func SellDecisionTree(
 	return false, order
 
 }
+
+/* Return the lowest priced simulated buy of a thread in dry-run mode once the market price reaches its profit target */
+func shadowSellDecision(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) (is bool, order types.Order) {
+
+	open, err := mysql.GetShadowOpenOrders(sessionData)
+	if err != nil || len(open) == 0 { /* Errors are logged by mysql */
+
+		return false, order
+
+	}
+
+	if (markets.Data{}).IsStale(configData, marketData, sessionData) {
+
+		sessionData.SellDecisionTreeResult = "Market data stale"
+
+		return false, order
+
+	}
+
+	if target, ok := shadow.Target(open, marketData.Price, calculateProfit(configData, sessionData)); ok {
+
+		sessionData.SellDecisionTreeResult = "Dry run profit target reached"
+
+		return true, shadow.Order(target)
+
+	}
+
+	sessionData.SellDecisionTreeResult = "Dry run profit target not reached"
+
+	return false, order
+
+}
+
+/* Return the correlation ID of the trade cycle of the order being sold, a new one for the orders bought without */
+func cycleCorrelationID(
+	sessionData *types.Session,
+	order types.Order) string {
+
+	if correlationID, err := mysql.GetOrderCorrelationID(sessionData, order.OrderID); err == nil && correlationID != "" { /* Errors are logged by mysql */
+
+		return correlationID
+
+	}
+
+	return functions.GetCorrelationID()
+
+}
+
+/* Begin the trace of a trade decision of side, from the websocket tick to the end of the decision */
+func tradeTrace(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	side string,
+	decision time.Time) *tracing.Trace {
+
+	trace := tracing.Begin(configData, sessionData, "tick", sessionData.LastWsBookTickerTime, map[string]string{
+		"side":           side,
+		"price":          functions.Float64ToStr(marketData.Price, 8),
+		"correlation.id": sessionData.CorrelationID,
+	})
+
+	trace.Span("decision", decision, time.Now(), map[string]string{"side": side})
+
+	return trace
+
+}
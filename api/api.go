@@ -0,0 +1,1139 @@
+package api
+
+/* This package implements the versioned JSON REST API served alongside the HTML UI under /api/v1/.
+Successful responses return {"data": ...} and failed responses return {"error": {"status": ..., "message": ...}}.
+Every request must include a REST API token created in admin.html as the header "Authorization: Bearer <token>",
+and the role of the token user must allow the route: viewer reads, trader starts, stops, buys and sells, and admin
+updates the configuration. */
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/adoption"
+	"github.com/aleibovici/cryptopump/approval"
+	"github.com/aleibovici/cryptopump/auth"
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/heatmap"
+	"github.com/aleibovici/cryptopump/i18n"
+	"github.com/aleibovici/cryptopump/labels"
+	"github.com/aleibovici/cryptopump/loader"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/logviewer"
+	"github.com/aleibovici/cryptopump/metrics"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/plotter"
+	"github.com/aleibovici/cryptopump/pnl"
+	"github.com/aleibovici/cryptopump/report"
+	"github.com/aleibovici/cryptopump/risk"
+	"github.com/aleibovici/cryptopump/shadow"
+	"github.com/aleibovici/cryptopump/snapshot"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/types"
+	"github.com/aleibovici/cryptopump/wsstats"
+)
+
+// Prefix is the URI path prefix of the REST API
+const Prefix = "/api/v1/"
+
+const websocketsLimitMax = 1000 /* Websocket connections returned at most */
+
+/* Configuration keys that cannot be changed while the thread is running, as in index_nostart.html */
+var immutableKeys = []string{"exchangename", "newsession", "symbol", "symbol_fiat", "testnet"}
+
+/* API errors */
+var (
+	ErrUnauthorized  = errors.New("Unauthorized")
+	ErrNotFound      = errors.New("Not found")
+	ErrNotAllowed    = errors.New("Method not allowed")
+	ErrNotRunning    = errors.New("Thread not running")
+	ErrRunning       = errors.New("Thread already running")
+	ErrInvalidBody   = errors.New("Invalid request body")
+	ErrUnknownKey    = errors.New("Unknown configuration key")
+	ErrImmutableKey  = errors.New("Configuration key cannot be changed while the thread is running")
+	ErrInvalidValue  = errors.New("Invalid configuration value")
+	ErrInvalidAction = errors.New("Invalid pending action ID")
+	ErrInvalidLevel  = errors.New("Invalid log level")
+)
+
+// Handler serve the REST API for the session running in this process
+type Handler struct {
+	SessionData *types.Session
+	MarketData  *types.Market
+	ViperData   *types.ViperData
+	Start       func(configData *types.Config)                                       /* Start the execution process */
+	Clone       func(threadID string, symbols []string, dryRun bool) []threads.Clone /* Start threads with the configuration of a thread */
+}
+
+// MetricsPath is the URI path of the Prometheus metrics endpoint
+const MetricsPath = "/metrics"
+
+// Metrics serve the Prometheus metrics of the thread running in this process, authenticated with a REST API token
+// of any role
+type Metrics struct {
+	SessionData *types.Session
+}
+
+func (h *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("X-Content-Type-Options", "nosniff") /* Add X-Content-Type-Options header */
+
+	if _, err := auth.Authenticate(h.SessionData, auth.BearerToken(r.Header.Get("Authorization")), auth.KindAPI); err != nil {
+
+		http.Error(w, ErrUnauthorized.Error(), http.StatusUnauthorized)
+		return
+
+	}
+
+	if r.Method != "GET" {
+
+		w.Header().Set("Allow", "GET")
+		http.Error(w, ErrNotAllowed.Error(), http.StatusMethodNotAllowed)
+		return
+
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	metrics.Write(w, h.SessionData)
+
+}
+
+type errorBody struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type sellRequest struct {
+	OrderID int64 `json:"orderId"`
+}
+
+type orderRequest struct {
+	Side     string  `json:"side"`
+	Quantity float64 `json:"quantity"`
+	Price    float64 `json:"price"` /* 0 for a market order */
+}
+
+type cloneRequest struct {
+	ThreadID string   `json:"threadId"` /* Thread of the configuration cloned, the running thread when empty */
+	Symbols  []string `json:"symbols"`
+	DryRun   bool     `json:"dryRun"` /* Simulate the orders of the cloned threads */
+}
+
+type labelRequest struct {
+	ThreadID string   `json:"threadId"` /* Labeled thread, the running thread when empty */
+	Name     string   `json:"name"`
+	Tags     []string `json:"tags"`
+}
+
+type adoptRequest struct {
+	Quantity  float64 `json:"quantity"` /* 0 adopts all the orphaned holdings */
+	CostBasis float64 `json:"costBasis"`
+}
+
+type pendingRequest struct {
+	ID int64 `json:"id"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")                                      /* Set the Content-Type header */
+	w.Header().Set("X-Content-Type-Options", "nosniff")                                     /* Add X-Content-Type-Options header */
+	w.Header().Set("Content-Language", i18n.Negotiate("", r.Header.Get("Accept-Language"))) /* Error messages are translated to the Accept-Language header */
+
+	configData := functions.GetConfigData(h.ViperData, h.SessionData) /* Get configuration data */
+
+	token, err := auth.Authenticate(h.SessionData, auth.BearerToken(r.Header.Get("Authorization")), auth.KindAPI)
+	if err != nil {
+
+		writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+		return
+
+	}
+
+	route := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, Prefix), "/")
+
+	if !auth.Allowed(token.Role, routeRole(route, r.Method)) { /* Enforce the role required by the route */
+
+		writeError(w, http.StatusForbidden, auth.ErrForbidden)
+		return
+
+	}
+
+	switch route {
+	case "sessions":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		sessions, err := mysql.GetSessions(h.SessionData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		matches := []types.SessionSummary{}
+
+		for _, session := range sessions { /* Sessions with the ThreadID, name or tag searched */
+
+			if labels.Match(types.SessionLabel{ThreadID: session.ThreadID, Name: session.Name, Tags: session.Tags}, r.URL.Query().Get("search")) {
+				matches = append(matches, session)
+			}
+
+		}
+
+		writeData(w, http.StatusOK, matches)
+
+	case "session/label":
+
+		if !allowMethod(w, r, "PUT") {
+			return
+		}
+
+		var request labelRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+
+			writeError(w, http.StatusBadRequest, ErrInvalidBody)
+			return
+
+		}
+
+		if request.ThreadID == "" {
+
+			if !h.requireRunning(w) {
+				return
+			}
+
+			request.ThreadID = h.SessionData.ThreadID
+
+		}
+
+		label, err := labels.Parse(request.ThreadID, request.Name, strings.Join(request.Tags, ","))
+		if err != nil {
+
+			writeError(w, http.StatusBadRequest, err)
+			return
+
+		}
+
+		if err := labels.Save(h.SessionData, token.Username, label); err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, label)
+
+	case "session":
+
+		if !allowMethod(w, r, "GET") || !h.requireRunning(w) {
+			return
+		}
+
+		tmp, err := loader.LoadSessionDataAdditionalComponents(h.SessionData, h.MarketData, configData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, json.RawMessage(tmp))
+
+	case "session/start":
+
+		if !allowMethod(w, r, "POST") {
+			return
+		}
+
+		if h.SessionData.ThreadID != "" {
+
+			writeError(w, http.StatusConflict, ErrRunning)
+			return
+
+		}
+
+		go h.Start(configData) /* Start the execution process */
+
+		writeData(w, http.StatusAccepted, map[string]string{"status": "starting"})
+
+	case "session/clone":
+
+		if !allowMethod(w, r, "POST") {
+			return
+		}
+
+		var request cloneRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(threads.ParseSymbols(request.Symbols)) == 0 {
+
+			writeError(w, http.StatusBadRequest, ErrInvalidBody)
+			return
+
+		}
+
+		clones := h.Clone(request.ThreadID, request.Symbols, request.DryRun)
+
+		h.log(configData, fmt.Sprintf("Threads cloned from REST API by user %s - %d symbols", token.Username, len(clones)))
+
+		writeData(w, http.StatusAccepted, clones)
+
+	case "session/stop":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		writeData(w, http.StatusAccepted, map[string]string{"status": "stopping", "threadId": h.SessionData.ThreadID})
+
+		if f, ok := w.(http.Flusher); ok { /* Send the response before the process exits */
+			f.Flush()
+		}
+
+		threads.Thread{}.Terminate(h.SessionData, "") /* Terminate ThreadID */
+
+	case "config":
+
+		switch r.Method {
+		case "GET":
+
+			writeData(w, http.StatusOK, h.ViperData.V1.GetStringMap("config"))
+
+		case "PUT", "PATCH":
+
+			var update map[string]interface{}
+
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+
+				writeError(w, http.StatusBadRequest, ErrInvalidBody)
+				return
+
+			}
+
+			values, err := configValues(h.ViperData.V1.GetStringMap("config"), update, h.SessionData.ThreadID != "")
+			if err != nil {
+
+				writeError(w, configErrorStatus(err), err)
+				return
+
+			}
+
+			for key, value := range values {
+				h.ViperData.V1.Set("config."+key, value)
+			}
+
+			if err := h.ViperData.V1.WriteConfig(); err != nil {
+
+				writeError(w, http.StatusInternalServerError, err)
+				return
+
+			}
+
+			h.log(configData, fmt.Sprintf("Configuration updated from REST API by user %s - %d keys", token.Username, len(values)))
+
+			writeData(w, http.StatusOK, h.ViperData.V1.GetStringMap("config"))
+
+		default:
+
+			w.Header().Set("Allow", "GET, PUT, PATCH")
+			writeError(w, http.StatusMethodNotAllowed, ErrNotAllowed)
+
+		}
+
+	case "loglevels":
+
+		switch r.Method {
+		case "GET":
+
+			writeData(w, http.StatusOK, h.logLevels())
+
+		case "PUT", "PATCH":
+
+			var update map[string]string
+
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+
+				writeError(w, http.StatusBadRequest, ErrInvalidBody)
+				return
+
+			}
+
+			values, err := logLevelValues(update)
+			if err != nil {
+
+				writeError(w, http.StatusBadRequest, err)
+				return
+
+			}
+
+			for key, value := range values {
+				h.ViperData.V2.Set("config_global."+key, value)
+			}
+
+			if err := h.ViperData.V2.WriteConfig(); err != nil { /* Persisted in config_global.yml to survive restarts */
+
+				writeError(w, http.StatusInternalServerError, err)
+				return
+
+			}
+
+			logger.Configure(functions.GetConfigData(h.ViperData, h.SessionData)) /* Apply without restarting */
+
+			h.log(configData, fmt.Sprintf("Log levels updated from REST API by user %s - %d levels", token.Username, len(values)))
+
+			writeData(w, http.StatusOK, h.logLevels())
+
+		default:
+
+			w.Header().Set("Allow", "GET, PUT, PATCH")
+			writeError(w, http.StatusMethodNotAllowed, ErrNotAllowed)
+
+		}
+
+	case "logs":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit")) /* 0 for the maximum */
+
+		records, err := logviewer.Query(h.SessionData, logviewer.Filter{
+			ThreadID:  query.Get("threadID"),
+			Level:     query.Get("level"),
+			Component: query.Get("component"),
+			Text:      query.Get("text"),
+			From:      query.Get("from"),
+			To:        query.Get("to"),
+		}, limit)
+		if err != nil {
+
+			writeError(w, http.StatusBadRequest, err)
+			return
+
+		}
+
+		if records == nil {
+			records = []types.LogRecord{}
+		}
+
+		writeData(w, http.StatusOK, records)
+
+	case "snapshot":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		h.log(configData, fmt.Sprintf("State snapshot taken from REST API by user %s", token.Username))
+
+		writeData(w, http.StatusOK, snapshot.Take(configData, h.MarketData, h.SessionData))
+
+	case "buy":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		h.SessionData.ForceBuy = true /* Force buy */
+
+		writeData(w, http.StatusAccepted, map[string]string{"status": "buying"})
+
+	case "sell":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		var request sellRequest /* Force sell most recent order when orderId is empty */
+
+		if r.ContentLength != 0 {
+
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+
+				writeError(w, http.StatusBadRequest, ErrInvalidBody)
+				return
+
+			}
+
+		}
+
+		pending, err := approval.ForceSell(configData, h.MarketData, h.SessionData, request.OrderID) /* Force sell, or request confirmation above SellConfirmNotional */
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		if pending {
+
+			action, _ := approval.GetPending(h.SessionData)
+			writeData(w, http.StatusAccepted, map[string]interface{}{"status": "pending", "pendingAction": action})
+			return
+
+		}
+
+		writeData(w, http.StatusAccepted, map[string]interface{}{"status": "selling", "orderId": request.OrderID})
+
+	case "order":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		var request orderRequest
+		var price string
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+
+			writeError(w, http.StatusBadRequest, ErrInvalidBody)
+			return
+
+		}
+
+		if request.Price != 0 {
+			price = strconv.FormatFloat(request.Price, 'f', -1, 64)
+		}
+
+		side, quantity, limitPrice, err := exchange.ParseManualOrder(request.Side, strconv.FormatFloat(request.Quantity, 'f', -1, 64), price, h.SessionData.StepSize)
+		if err != nil {
+
+			writeError(w, http.StatusBadRequest, err)
+			return
+
+		}
+
+		order, err := exchange.ManualTicker(side, quantity, limitPrice, token.Username, configData, h.MarketData, h.SessionData) /* Place manual order recorded with the operator source */
+		if err != nil {
+
+			writeError(w, manualErrorStatus(err), err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, order)
+
+	case "pause", "resume":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		if err := (threads.Thread{}).Pause(h.SessionData, route == "pause", token.Username); err != nil { /* Pause or resume new buys, exits are still managed */
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, map[string]interface{}{"threadId": h.SessionData.ThreadID, "paused": h.SessionData.Paused})
+
+	case "sell/pending":
+
+		if !allowMethod(w, r, "GET") || !h.requireRunning(w) {
+			return
+		}
+
+		action, err := approval.GetPending(h.SessionData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		if action.ID == 0 {
+
+			writeError(w, http.StatusNotFound, approval.ErrNotPending)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, action)
+
+	case "sell/confirm", "sell/reject":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		var request pendingRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.ID <= 0 {
+
+			writeError(w, http.StatusBadRequest, ErrInvalidAction)
+			return
+
+		}
+
+		if route == "sell/reject" {
+
+			if err := approval.Reject(configData, h.SessionData, request.ID); err != nil {
+
+				writeError(w, http.StatusInternalServerError, err)
+				return
+
+			}
+
+			writeData(w, http.StatusOK, map[string]interface{}{"status": "rejected", "id": request.ID})
+			return
+
+		}
+
+		if err := approval.Approve(configData, h.SessionData, request.ID); err != nil {
+
+			status := http.StatusInternalServerError
+			if errors.Is(err, approval.ErrNotPending) || errors.Is(err, approval.ErrExpired) {
+				status = http.StatusConflict
+			}
+
+			writeError(w, status, err)
+			return
+
+		}
+
+		writeData(w, http.StatusAccepted, map[string]interface{}{"status": "selling", "id": request.ID})
+
+	case "orders":
+
+		if !allowMethod(w, r, "GET") || !h.requireRunning(w) {
+			return
+		}
+
+		orders, err := mysql.GetThreadTransactionByThreadID(h.SessionData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		if orders == nil {
+			orders = []types.Order{}
+		}
+
+		writeData(w, http.StatusOK, orders)
+
+	case "adoption":
+
+		if !allowMethod(w, r, "GET") || !h.requireRunning(w) {
+			return
+		}
+
+		status, err := adoption.Check(h.SessionData, h.MarketData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, status)
+
+	case "adoption/adopt":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		var request adoptRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+
+			writeError(w, http.StatusBadRequest, ErrInvalidBody)
+			return
+
+		}
+
+		order, err := adoption.Adopt(configData, h.SessionData, h.MarketData, token.Username, request.Quantity, request.CostBasis) /* Import orphaned holdings as an open transaction */
+		if err != nil {
+
+			writeError(w, adoptionErrorStatus(err), err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, order)
+
+	case "adoption/release":
+
+		if !allowMethod(w, r, "POST") || !h.requireRunning(w) {
+			return
+		}
+
+		var request sellRequest
+
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+
+			writeError(w, http.StatusBadRequest, ErrInvalidBody)
+			return
+
+		}
+
+		if err := adoption.Release(configData, h.SessionData, h.MarketData, token.Username, request.OrderID); err != nil { /* Remove an open transaction not backed by holdings */
+
+			writeError(w, adoptionErrorStatus(err), err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, request)
+
+	case "shadow":
+
+		if !allowMethod(w, r, "GET") || !h.requireRunning(w) {
+			return
+		}
+
+		status, err := shadow.Load(h.SessionData, h.MarketData.Price)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, status)
+
+	case "profit":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		profit, profitNet, profitPct, err := mysql.GetProfit(h.SessionData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		data := map[string]float64{"profit": profit, "profitNet": profitNet, "profitPct": profitPct}
+
+		if h.SessionData.ThreadID != "" {
+
+			threadProfit, threadProfitPct, err := mysql.GetProfitByThreadID(h.SessionData)
+			if err != nil {
+
+				writeError(w, http.StatusInternalServerError, err)
+				return
+
+			}
+
+			data["threadProfit"] = threadProfit
+			data["threadProfitPct"] = threadProfitPct
+
+		}
+
+		writeData(w, http.StatusOK, data)
+
+	case "allocations":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		allocations, err := risk.Allocations(configData, h.SessionData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		if allocations == nil {
+			allocations = []risk.Allocation{}
+		}
+
+		writeData(w, http.StatusOK, allocations)
+
+	case "pnl":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		ticker, err := pnl.Load(h.SessionData, pnl.ParseMinutes(r.URL.Query().Get("minutes")))
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, ticker)
+
+	case "annotations":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		feed, err := plotter.LoadAnnotations(configData, h.SessionData)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, feed)
+
+	case "heatmap":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		query := r.URL.Query()
+
+		page, err := heatmap.Load(configData, h.SessionData, query.Get("threadID"), query.Get("from"), query.Get("to"))
+		if err != nil {
+
+			writeError(w, http.StatusBadRequest, err)
+			return
+
+		}
+
+		writeData(w, http.StatusOK, page)
+
+	case "websockets":
+
+		if !allowMethod(w, r, "GET") || !h.requireRunning(w) {
+			return
+		}
+
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit <= 0 || limit > websocketsLimitMax {
+			limit = websocketsLimitMax
+		}
+
+		connections, err := mysql.GetWsConnections(h.SessionData, limit)
+		if err != nil {
+
+			writeError(w, http.StatusInternalServerError, err)
+			return
+
+		}
+
+		if connections == nil {
+			connections = []types.WsConnection{}
+		}
+
+		writeData(w, http.StatusOK, map[string]interface{}{"streams": wsstats.Read(h.SessionData.ThreadID, time.Now()), "history": connections})
+
+	case "report":
+
+		if !allowMethod(w, r, "GET") {
+			return
+		}
+
+		query := r.URL.Query()
+
+		request, err := report.Parse(query.Get("kind"), query.Get("format"), query.Get("from"), query.Get("to"))
+		if err != nil {
+
+			writeError(w, http.StatusBadRequest, err)
+			return
+
+		}
+
+		w.Header().Set("Content-Type", request.ContentType()) /* Reports are returned as CSV or PDF instead of JSON */
+		w.Header().Set("Content-Disposition", `attachment; filename="`+request.Filename()+`"`)
+
+		if err := report.Write(w, h.SessionData, request); err != nil {
+
+			h.log(configData, functions.GetFunctionName()+" - "+err.Error())
+
+		}
+
+	default:
+
+		writeError(w, http.StatusNotFound, ErrNotFound)
+
+	}
+
+}
+
+/* Write a conflict error and return false when the thread is not running */
+func (h *Handler) requireRunning(w http.ResponseWriter) bool {
+
+	if h.SessionData.ThreadID == "" {
+
+		writeError(w, http.StatusConflict, ErrNotRunning)
+		return false
+
+	}
+
+	return true
+
+}
+
+/* Log an API operation */
+func (h *Handler) log(configData *types.Config, message string) {
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  h.SessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+}
+
+/* Return the role required by a route and method: reads require RoleViewer, configuration updates and the state snapshot RoleAdmin and other operations RoleTrader */
+func routeRole(
+	route string,
+	method string) string {
+
+	switch {
+	case route == "snapshot": /* Configuration and errors of the thread */
+		return auth.RoleAdmin
+	case method == "GET":
+		return auth.RoleViewer
+	case route == "config", route == "loglevels":
+		return auth.RoleAdmin
+	default:
+		return auth.RoleTrader
+	}
+
+}
+
+/* Return the HTTP status of a manual order error */
+func manualErrorStatus(err error) int {
+
+	var validationErr *exchange.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, exchange.ErrManualDryRun):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+
+}
+
+/* Return the http status of an adoption error, bad request for validation errors */
+func adoptionErrorStatus(err error) int {
+
+	switch err {
+	case adoption.ErrQuantity, adoption.ErrCostBasis, adoption.ErrNotOpen:
+		return http.StatusBadRequest
+	case adoption.ErrBacked:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+
+}
+
+/* Write a method not allowed error and return false when the request method is not method */
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+
+	if r.Method != method {
+
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, ErrNotAllowed)
+		return false
+
+	}
+
+	return true
+
+}
+
+/* Write data as a successful response */
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+
+}
+
+/* Write err as a failed response */
+func writeError(w http.ResponseWriter, status int, err error) {
+
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"error": errorBody{Status: status, Message: errorMessage(w.Header().Get("Content-Language"), err)}})
+
+}
+
+/* Return the message of err translated to locale, keeping the configuration key of key errors untranslated */
+func errorMessage(
+	locale string,
+	err error) string {
+
+	var keyErr keyError
+
+	if errors.As(err, &keyErr) {
+
+		return i18n.T(locale, keyErr.err.Error()) + " - " + keyErr.key
+
+	}
+
+	return i18n.T(locale, err.Error())
+
+}
+
+/* keyError carry the configuration key that failed validation */
+type keyError struct {
+	err error
+	key string
+}
+
+func (e keyError) Error() string { return e.err.Error() + " - " + e.key }
+
+func (e keyError) Unwrap() error { return e.err }
+
+/* Return the HTTP status for a configuration validation error */
+func configErrorStatus(err error) int {
+
+	if errors.Is(err, ErrImmutableKey) {
+
+		return http.StatusConflict
+
+	}
+
+	return http.StatusBadRequest
+
+}
+
+/* Validate the configuration update against the existing settings and return the values to save as strings */
+func configValues(
+	settings map[string]interface{},
+	update map[string]interface{},
+	running bool) (values map[string]string, err error) {
+
+	if len(update) == 0 {
+
+		return nil, ErrInvalidBody
+
+	}
+
+	values = make(map[string]string)
+
+	for key, value := range update {
+
+		key = strings.ToLower(key) /* Viper keys are case-insensitive */
+
+		if _, ok := settings[key]; !ok {
+
+			return nil, keyError{err: ErrUnknownKey, key: key}
+
+		}
+
+		if running {
+
+			for _, immutable := range immutableKeys {
+
+				if key == immutable {
+
+					return nil, keyError{err: ErrImmutableKey, key: key}
+
+				}
+
+			}
+
+		}
+
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case float64:
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, keyError{err: ErrInvalidValue, key: key}
+		}
+
+	}
+
+	return values, nil
+
+}
+
+/* Return the global log level (key "global") and the log level of each subsystem, empty when using the global level */
+func (h *Handler) logLevels() map[string]string {
+
+	levels := map[string]string{"global": h.ViperData.V2.GetString("config_global.loglevel")}
+
+	for _, subsystem := range logger.Subsystems {
+		levels[subsystem] = h.ViperData.V2.GetString("config_global.loglevel" + subsystem)
+	}
+
+	return levels
+
+}
+
+// logLevelValues validate the log level update, keyed by "global" or a subsystem, and return the values to save keyed
+// by configuration key. Subsystems accept an empty level to use the global level.
+func logLevelValues(update map[string]string) (values map[string]string, err error) {
+
+	if len(update) == 0 {
+
+		return nil, ErrInvalidBody
+
+	}
+
+	values = make(map[string]string)
+
+	for key, level := range update {
+
+		key = strings.ToLower(key)
+		level = strings.ToLower(level)
+
+		if key == "global" {
+
+			if !logger.ValidLevel(level, false) {
+
+				return nil, keyError{err: ErrInvalidLevel, key: key}
+
+			}
+
+			values["loglevel"] = level
+			continue
+
+		}
+
+		known := false
+		for _, subsystem := range logger.Subsystems {
+			known = known || key == subsystem
+		}
+
+		if !known {
+
+			return nil, keyError{err: ErrUnknownKey, key: key}
+
+		}
+
+		if !logger.ValidLevel(level, true) {
+
+			return nil, keyError{err: ErrInvalidLevel, key: key}
+
+		}
+
+		values["loglevel"+key] = level
+
+	}
+
+	return values, nil
+
+}
@@ -0,0 +1,357 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/auth"
+	"github.com/aleibovici/cryptopump/exchange"
+	cryptopumpv1 "github.com/aleibovici/cryptopump/proto/cryptopump/v1"
+)
+
+func Test_configValues(t *testing.T) {
+	settings := map[string]interface{}{
+		"stoploss":     "0",
+		"symbol":       "BTCUSDT",
+		"dryrun":       "false",
+		"buy_wait":     "60",
+		"exchangename": "BINANCE",
+	}
+	type args struct {
+		update  map[string]interface{}
+		running bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "valid update",
+			args: args{
+				update:  map[string]interface{}{"Stoploss": 0.05, "dryrun": true, "buy_wait": "30"},
+				running: true,
+			},
+			want:    map[string]string{"stoploss": "0.05", "dryrun": "true", "buy_wait": "30"},
+			wantErr: nil,
+		},
+		{
+			name: "unknown key",
+			args: args{
+				update:  map[string]interface{}{"unknown": "1"},
+				running: false,
+			},
+			want:    nil,
+			wantErr: ErrUnknownKey,
+		},
+		{
+			name: "immutable key while running",
+			args: args{
+				update:  map[string]interface{}{"symbol": "ETHUSDT"},
+				running: true,
+			},
+			want:    nil,
+			wantErr: ErrImmutableKey,
+		},
+		{
+			name: "immutable key while stopped",
+			args: args{
+				update:  map[string]interface{}{"symbol": "ETHUSDT"},
+				running: false,
+			},
+			want:    map[string]string{"symbol": "ETHUSDT"},
+			wantErr: nil,
+		},
+		{
+			name: "invalid value",
+			args: args{
+				update:  map[string]interface{}{"stoploss": []interface{}{1}},
+				running: false,
+			},
+			want:    nil,
+			wantErr: ErrInvalidValue,
+		},
+		{
+			name: "empty update",
+			args: args{
+				update:  map[string]interface{}{},
+				running: false,
+			},
+			want:    nil,
+			wantErr: ErrInvalidBody,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := configValues(settings, tt.args.update, tt.args.running)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("configValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("configValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_logLevelValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  map[string]string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "global and subsystems",
+			update:  map[string]string{"Global": "INFO", "exchange": "off", "mysql": ""},
+			want:    map[string]string{"loglevel": "info", "loglevelexchange": "off", "loglevelmysql": ""},
+			wantErr: nil,
+		},
+		{
+			name:    "empty global level",
+			update:  map[string]string{"global": ""},
+			want:    nil,
+			wantErr: ErrInvalidLevel,
+		},
+		{
+			name:    "invalid level",
+			update:  map[string]string{"threads": "trace"},
+			want:    nil,
+			wantErr: ErrInvalidLevel,
+		},
+		{
+			name:    "unknown subsystem",
+			update:  map[string]string{"telegram": "info"},
+			want:    nil,
+			wantErr: ErrUnknownKey,
+		},
+		{
+			name:    "empty update",
+			update:  map[string]string{},
+			want:    nil,
+			wantErr: ErrInvalidBody,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := logLevelValues(tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("logLevelValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logLevelValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writeError(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		status int
+		err    error
+		want   string
+	}{
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			err:    ErrNotFound,
+			want:   `{"error":{"status":404,"message":"Not found"}}` + "\n",
+		},
+		{
+			name:   "unknown key",
+			status: http.StatusBadRequest,
+			err:    keyError{err: ErrUnknownKey, key: "foo"},
+			want:   `{"error":{"status":400,"message":"Unknown configuration key - foo"}}` + "\n",
+		},
+		{
+			name:   "unknown key in portuguese",
+			locale: "pt",
+			status: http.StatusBadRequest,
+			err:    keyError{err: ErrUnknownKey, key: "foo"},
+			want:   `{"error":{"status":400,"message":"Chave de configuração desconhecida - foo"}}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			w.Header().Set("Content-Language", tt.locale)
+			writeError(w, tt.status, tt.err)
+			if w.Code != tt.status {
+				t.Errorf("writeError() status = %v, want %v", w.Code, tt.status)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("writeError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_routeRole(t *testing.T) {
+	type args struct {
+		route  string
+		method string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "read orders",
+			args: args{route: "orders", method: "GET"},
+			want: auth.RoleViewer,
+		},
+		{
+			name: "read configuration",
+			args: args{route: "config", method: "GET"},
+			want: auth.RoleViewer,
+		},
+		{
+			name: "update configuration",
+			args: args{route: "config", method: "PUT"},
+			want: auth.RoleAdmin,
+		},
+		{
+			name: "state snapshot",
+			args: args{route: "snapshot", method: "GET"},
+			want: auth.RoleAdmin,
+		},
+		{
+			name: "update log levels",
+			args: args{route: "loglevels", method: "PUT"},
+			want: auth.RoleAdmin,
+		},
+		{
+			name: "force sell",
+			args: args{route: "sell", method: "POST"},
+			want: auth.RoleTrader,
+		},
+		{
+			name: "stop thread",
+			args: args{route: "session/stop", method: "POST"},
+			want: auth.RoleTrader,
+		},
+		{
+			name: "manual order",
+			args: args{route: "order", method: "POST"},
+			want: auth.RoleTrader,
+		},
+		{
+			name: "clone thread",
+			args: args{route: "session/clone", method: "POST"},
+			want: auth.RoleTrader,
+		},
+		{
+			name: "label session",
+			args: args{route: "session/label", method: "PUT"},
+			want: auth.RoleTrader,
+		},
+		{
+			name: "adoption status",
+			args: args{route: "adoption", method: "GET"},
+			want: auth.RoleViewer,
+		},
+		{
+			name: "adopt position",
+			args: args{route: "adoption/adopt", method: "POST"},
+			want: auth.RoleTrader,
+		},
+		{
+			name: "release transaction",
+			args: args{route: "adoption/release", method: "POST"},
+			want: auth.RoleTrader,
+		},
+		{
+			name: "shadow orders",
+			args: args{route: "shadow", method: "GET"},
+			want: auth.RoleViewer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routeRole(tt.args.route, tt.args.method); got != tt.want {
+				t.Errorf("routeRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_methodRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		want   string
+	}{
+		{
+			name:   "list orders",
+			method: cryptopumpv1.ControlPlane_ListOrders_FullMethodName,
+			want:   auth.RoleViewer,
+		},
+		{
+			name:   "stream orders",
+			method: cryptopumpv1.ControlPlane_StreamOrders_FullMethodName,
+			want:   auth.RoleViewer,
+		},
+		{
+			name:   "force sell",
+			method: cryptopumpv1.ControlPlane_Sell_FullMethodName,
+			want:   auth.RoleTrader,
+		},
+		{
+			name:   "confirm sell",
+			method: cryptopumpv1.ControlPlane_ConfirmSell_FullMethodName,
+			want:   auth.RoleTrader,
+		},
+		{
+			name:   "update configuration",
+			method: cryptopumpv1.ControlPlane_UpdateConfig_FullMethodName,
+			want:   auth.RoleAdmin,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := methodRole(tt.method); got != tt.want {
+				t.Errorf("methodRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_manualErrorStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "pre-trade validation",
+			err:  &exchange.ValidationError{Err: exchange.ErrMinNotional, Detail: "order value 5.00 below 10.00"},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "dry run",
+			err:  exchange.ErrManualDryRun,
+			want: http.StatusConflict,
+		},
+		{
+			name: "exchange error",
+			err:  errors.New("<APIError> code=-2010, msg=Account has insufficient balance"),
+			want: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manualErrorStatus(tt.err); got != tt.want {
+				t.Errorf("manualErrorStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
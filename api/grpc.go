@@ -0,0 +1,658 @@
+package api
+
+/* gRPC control plane of proto/cryptopump/v1/cryptopump.proto, served with the -grpc flag. The RPCs mirror the REST
+API routes on the same Handler, with the same REST API tokens sent as the metadata "authorization: Bearer <token>" and
+the same roles, and StreamMarket and StreamOrders push the market data and the filled orders of the thread running in
+this session. */
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/approval"
+	"github.com/aleibovici/cryptopump/auth"
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	cryptopumpv1 "github.com/aleibovici/cryptopump/proto/cryptopump/v1"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/types"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const marketInterval = time.Second /* Market data polling of StreamMarket */
+
+const orderStreamBuffer = 100 /* Order events buffered for each StreamOrders, dropped when full */
+
+// ControlPlane serve the gRPC control plane for the session of the REST API Handler
+type ControlPlane struct {
+	cryptopumpv1.UnimplementedControlPlaneServer
+	Handler *Handler /* REST API of the thread of the web UI, the session restarted by the supervisor included */
+
+	streams struct {
+		sync.Mutex
+		orders map[chan *cryptopumpv1.OrderEvent]bool /* StreamOrders in progress */
+	}
+}
+
+/* Context key of the REST API token of a call */
+type tokenKey struct{}
+
+// StartControlPlane start the gRPC control plane of handler at address in the background, logging when it can't listen
+func StartControlPlane(
+	configData *types.Config,
+	handler *Handler,
+	address string) {
+
+	c := &ControlPlane{Handler: handler}
+	c.streams.orders = make(map[chan *cryptopumpv1.OrderEvent]bool)
+
+	events.Subscribe(c.publish, events.OrderFilled) /* Order events of StreamOrders */
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(c.unary), grpc.StreamInterceptor(c.stream))
+	cryptopumpv1.RegisterControlPlaneServer(server, c)
+
+	go func() {
+
+		listener, err := net.Listen("tcp", address)
+		if err == nil {
+			err = server.Serve(listener)
+		}
+
+		if err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  handler.SessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+		}
+
+	}()
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  handler.SessionData,
+		Order:    &types.Order{},
+		Message:  "gRPC control plane started at " + address,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+}
+
+/* Authenticate the REST API token of a unary call and enforce the role required by the method */
+func (c *ControlPlane) unary(
+	ctx context.Context,
+	request interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	ctx, err := c.authenticate(ctx, info.FullMethod)
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	return handler(ctx, request)
+
+}
+
+/* Authenticate the REST API token of a streaming call and enforce the role required by the method */
+func (c *ControlPlane) stream(
+	server interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	if _, err := c.authenticate(stream.Context(), info.FullMethod); err != nil {
+
+		return err
+
+	}
+
+	return handler(server, stream)
+
+}
+
+/* Return ctx with the REST API token of the authorization metadata, failing when the token role doesn't allow method */
+func (c *ControlPlane) authenticate(
+	ctx context.Context,
+	method string) (context.Context, error) {
+
+	var header string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+
+	}
+
+	token, err := auth.Authenticate(c.Handler.SessionData, auth.BearerToken(header), auth.KindAPI)
+	if err != nil {
+
+		return nil, status.Error(codes.Unauthenticated, ErrUnauthorized.Error())
+
+	}
+
+	if !auth.Allowed(token.Role, methodRole(method)) {
+
+		return nil, status.Error(codes.PermissionDenied, auth.ErrForbidden.Error())
+
+	}
+
+	return context.WithValue(ctx, tokenKey{}, token), nil
+
+}
+
+/* Return the username of the REST API token of a call */
+func username(ctx context.Context) string {
+
+	token, _ := ctx.Value(tokenKey{}).(types.AuthToken)
+
+	return token.Username
+
+}
+
+/* Return the role required by a method: reads and streams require RoleViewer, configuration updates RoleAdmin and other operations RoleTrader, as the REST API routes */
+func methodRole(method string) string {
+
+	switch method {
+	case cryptopumpv1.ControlPlane_ListSessions_FullMethodName,
+		cryptopumpv1.ControlPlane_ListOrders_FullMethodName,
+		cryptopumpv1.ControlPlane_GetProfit_FullMethodName,
+		cryptopumpv1.ControlPlane_StreamMarket_FullMethodName,
+		cryptopumpv1.ControlPlane_StreamOrders_FullMethodName:
+		return auth.RoleViewer
+	case cryptopumpv1.ControlPlane_UpdateConfig_FullMethodName:
+		return auth.RoleAdmin
+	default:
+		return auth.RoleTrader
+	}
+
+}
+
+/* Return a failed precondition error when the thread is not running */
+func (c *ControlPlane) requireRunning() error {
+
+	if c.Handler.SessionData.ThreadID == "" {
+
+		return status.Error(codes.FailedPrecondition, ErrNotRunning.Error())
+
+	}
+
+	return nil
+
+}
+
+// ListSessions list all sessions
+func (c *ControlPlane) ListSessions(
+	ctx context.Context,
+	request *cryptopumpv1.ListSessionsRequest) (*cryptopumpv1.ListSessionsResponse, error) {
+
+	sessions, err := mysql.GetSessions(c.Handler.SessionData)
+	if err != nil {
+
+		return nil, status.Error(codes.Internal, err.Error())
+
+	}
+
+	response := &cryptopumpv1.ListSessionsResponse{}
+
+	for _, session := range sessions {
+
+		response.Sessions = append(response.Sessions, &cryptopumpv1.Session{
+			ThreadId:        session.ThreadID,
+			ThreadIdSession: session.ThreadIDSession,
+			Exchange:        session.Exchange,
+			FiatSymbol:      session.FiatSymbol,
+			FiatFunds:       session.FiatFunds,
+			DiffTotal:       session.DiffTotal,
+			Status:          session.Status,
+		})
+
+	}
+
+	return response, nil
+
+}
+
+// ListOrders list the open transactions of the thread running in this session
+func (c *ControlPlane) ListOrders(
+	ctx context.Context,
+	request *cryptopumpv1.ListOrdersRequest) (*cryptopumpv1.ListOrdersResponse, error) {
+
+	if err := c.requireRunning(); err != nil {
+
+		return nil, err
+
+	}
+
+	orders, err := mysql.GetThreadTransactionByThreadID(c.Handler.SessionData)
+	if err != nil {
+
+		return nil, status.Error(codes.Internal, err.Error())
+
+	}
+
+	response := &cryptopumpv1.ListOrdersResponse{}
+
+	for _, order := range orders {
+
+		response.Orders = append(response.Orders, protoOrder(order))
+
+	}
+
+	return response, nil
+
+}
+
+// GetProfit return the profit across all threads, and for the thread running in this session
+func (c *ControlPlane) GetProfit(
+	ctx context.Context,
+	request *cryptopumpv1.GetProfitRequest) (*cryptopumpv1.GetProfitResponse, error) {
+
+	var err error
+
+	response := &cryptopumpv1.GetProfitResponse{}
+
+	if response.Profit, response.ProfitNet, response.ProfitPct, err = mysql.GetProfit(c.Handler.SessionData); err != nil {
+
+		return nil, status.Error(codes.Internal, err.Error())
+
+	}
+
+	if c.Handler.SessionData.ThreadID != "" {
+
+		if response.ThreadProfit, response.ThreadProfitPct, err = mysql.GetProfitByThreadID(c.Handler.SessionData); err != nil {
+
+			return nil, status.Error(codes.Internal, err.Error())
+
+		}
+
+	}
+
+	return response, nil
+
+}
+
+// StartSession start the bot on the trading pair previously set
+func (c *ControlPlane) StartSession(
+	ctx context.Context,
+	request *cryptopumpv1.StartSessionRequest) (*cryptopumpv1.ActionResponse, error) {
+
+	if c.Handler.SessionData.ThreadID != "" {
+
+		return nil, status.Error(codes.FailedPrecondition, ErrRunning.Error())
+
+	}
+
+	go c.Handler.Start(functions.GetConfigData(c.Handler.ViperData, c.Handler.SessionData)) /* Start the execution process */
+
+	return &cryptopumpv1.ActionResponse{Status: "starting"}, nil
+
+}
+
+// StopSession stop the bot without selling the open transactions
+func (c *ControlPlane) StopSession(
+	ctx context.Context,
+	request *cryptopumpv1.StopSessionRequest) (*cryptopumpv1.ActionResponse, error) {
+
+	if err := c.requireRunning(); err != nil {
+
+		return nil, err
+
+	}
+
+	sessionData := c.Handler.SessionData
+
+	go threads.Thread{}.Terminate(sessionData, "") /* Terminate ThreadID after the response, the process may exit */
+
+	return &cryptopumpv1.ActionResponse{Status: "stopping", ThreadId: sessionData.ThreadID}, nil
+
+}
+
+// Buy market
+func (c *ControlPlane) Buy(
+	ctx context.Context,
+	request *cryptopumpv1.BuyRequest) (*cryptopumpv1.ActionResponse, error) {
+
+	if err := c.requireRunning(); err != nil {
+
+		return nil, err
+
+	}
+
+	c.Handler.SessionData.ForceBuy = true /* Force buy */
+
+	return &cryptopumpv1.ActionResponse{Status: "buying", ThreadId: c.Handler.SessionData.ThreadID}, nil
+
+}
+
+// Sell market an order, or the top order when OrderId is 0, returning the pending action above Sell Confirm Notional
+func (c *ControlPlane) Sell(
+	ctx context.Context,
+	request *cryptopumpv1.SellRequest) (*cryptopumpv1.ActionResponse, error) {
+
+	if err := c.requireRunning(); err != nil {
+
+		return nil, err
+
+	}
+
+	configData := functions.GetConfigData(c.Handler.ViperData, c.Handler.SessionData)
+
+	pending, err := approval.ForceSell(configData, c.Handler.MarketData, c.Handler.SessionData, request.GetOrderId()) /* Force sell, or request confirmation above SellConfirmNotional */
+	if err != nil {
+
+		return nil, status.Error(codes.Internal, err.Error())
+
+	}
+
+	if pending {
+
+		action, _ := approval.GetPending(c.Handler.SessionData)
+
+		return &cryptopumpv1.ActionResponse{Status: "pending", ThreadId: c.Handler.SessionData.ThreadID, PendingAction: protoPendingAction(action)}, nil
+
+	}
+
+	return &cryptopumpv1.ActionResponse{Status: "selling", ThreadId: c.Handler.SessionData.ThreadID}, nil
+
+}
+
+// ConfirmSell execute the manual sale pending confirmation
+func (c *ControlPlane) ConfirmSell(
+	ctx context.Context,
+	request *cryptopumpv1.PendingActionRequest) (*cryptopumpv1.ActionResponse, error) {
+
+	if err := c.requireRunning(); err != nil {
+
+		return nil, err
+
+	}
+
+	if request.GetId() <= 0 {
+
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidAction.Error())
+
+	}
+
+	if err := approval.Approve(functions.GetConfigData(c.Handler.ViperData, c.Handler.SessionData), c.Handler.SessionData, request.GetId()); err != nil {
+
+		if errors.Is(err, approval.ErrNotPending) || errors.Is(err, approval.ErrExpired) {
+
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+
+		}
+
+		return nil, status.Error(codes.Internal, err.Error())
+
+	}
+
+	return &cryptopumpv1.ActionResponse{Status: "selling", ThreadId: c.Handler.SessionData.ThreadID}, nil
+
+}
+
+// RejectSell cancel the manual sale pending confirmation
+func (c *ControlPlane) RejectSell(
+	ctx context.Context,
+	request *cryptopumpv1.PendingActionRequest) (*cryptopumpv1.ActionResponse, error) {
+
+	if err := c.requireRunning(); err != nil {
+
+		return nil, err
+
+	}
+
+	if request.GetId() <= 0 {
+
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidAction.Error())
+
+	}
+
+	if err := approval.Reject(functions.GetConfigData(c.Handler.ViperData, c.Handler.SessionData), c.Handler.SessionData, request.GetId()); err != nil {
+
+		return nil, status.Error(codes.Internal, err.Error())
+
+	}
+
+	return &cryptopumpv1.ActionResponse{Status: "rejected", ThreadId: c.Handler.SessionData.ThreadID}, nil
+
+}
+
+// UpdateConfig update the session configuration and return it
+func (c *ControlPlane) UpdateConfig(
+	ctx context.Context,
+	request *cryptopumpv1.UpdateConfigRequest) (*cryptopumpv1.UpdateConfigResponse, error) {
+
+	update := make(map[string]interface{})
+
+	for key, value := range request.GetConfig() {
+		update[key] = value
+	}
+
+	values, err := configValues(c.Handler.ViperData.V1.GetStringMap("config"), update, c.Handler.SessionData.ThreadID != "")
+	if err != nil {
+
+		if errors.Is(err, ErrImmutableKey) {
+
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+
+		}
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+
+	}
+
+	for key, value := range values {
+		c.Handler.ViperData.V1.Set("config."+key, value)
+	}
+
+	if err := c.Handler.ViperData.V1.WriteConfig(); err != nil {
+
+		return nil, status.Error(codes.Internal, err.Error())
+
+	}
+
+	c.Handler.log(functions.GetConfigData(c.Handler.ViperData, c.Handler.SessionData), fmt.Sprintf("Configuration updated from gRPC by user %s - %d keys", username(ctx), len(values)))
+
+	response := &cryptopumpv1.UpdateConfigResponse{Config: make(map[string]string)}
+
+	for key, value := range c.Handler.ViperData.V1.GetStringMapString("config") {
+		response.Config[key] = value
+	}
+
+	return response, nil
+
+}
+
+// StreamMarket send the market data of the thread running in this session on each price update
+func (c *ControlPlane) StreamMarket(
+	request *cryptopumpv1.StreamMarketRequest,
+	stream cryptopumpv1.ControlPlane_StreamMarketServer) error {
+
+	if err := c.requireRunning(); err != nil {
+
+		return err
+
+	}
+
+	var last time.Time /* TimeStamp of the market data sent last */
+
+	ticker := time.NewTicker(marketInterval)
+	defer ticker.Stop()
+
+	for {
+
+		if marketData := c.Handler.MarketData; !marketData.TimeStamp.Equal(last) {
+
+			last = marketData.TimeStamp
+
+			if err := stream.Send(&cryptopumpv1.MarketEvent{
+				Symbol: c.Handler.SessionData.Symbol,
+				Price:  marketData.Price,
+				Spread: marketData.Spread,
+				Rsi3:   marketData.Rsi3,
+				Rsi7:   marketData.Rsi7,
+				Rsi14:  marketData.Rsi14,
+				Macd:   marketData.MACD,
+				Stale:  marketData.Stale,
+				Time:   marketData.TimeStamp.UnixNano() / int64(time.Millisecond),
+			}); err != nil {
+
+				return err
+
+			}
+
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+
+	}
+
+}
+
+// StreamOrders send the filled orders of the thread running in this session
+func (c *ControlPlane) StreamOrders(
+	request *cryptopumpv1.StreamOrdersRequest,
+	stream cryptopumpv1.ControlPlane_StreamOrdersServer) error {
+
+	orders := make(chan *cryptopumpv1.OrderEvent, orderStreamBuffer)
+
+	c.streams.Lock()
+	c.streams.orders[orders] = true
+	c.streams.Unlock()
+
+	defer func() {
+
+		c.streams.Lock()
+		delete(c.streams.orders, orders)
+		c.streams.Unlock()
+
+	}()
+
+	for {
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-orders:
+
+			if err := stream.Send(event); err != nil {
+
+				return err
+
+			}
+
+		}
+
+	}
+
+}
+
+/* Send the order filled events of the thread running in this session to each StreamOrders, without blocking the publisher */
+func (c *ControlPlane) publish(event events.Event) {
+
+	order, ok := event.Data.(events.Order)
+	if !ok || event.Session == nil || event.Session.ThreadID != c.Handler.SessionData.ThreadID {
+
+		return
+
+	}
+
+	orderEvent := protoOrderEvent(order, event.Time)
+
+	c.streams.Lock()
+	defer c.streams.Unlock()
+
+	for orders := range c.streams.orders {
+
+		select {
+		case orders <- orderEvent:
+		default: /* Stream too slow, the event is dropped */
+		}
+
+	}
+
+}
+
+/* Return the proto message of an order */
+func protoOrder(order types.Order) *cryptopumpv1.Order {
+
+	return &cryptopumpv1.Order{
+		OrderId:                 order.OrderID,
+		ClientOrderId:           order.ClientOrderID,
+		Symbol:                  order.Symbol,
+		Side:                    order.Side,
+		Status:                  order.Status,
+		Price:                   order.Price,
+		ExecutedQuantity:        order.ExecutedQuantity,
+		CumulativeQuoteQuantity: order.CumulativeQuoteQuantity,
+		TransactTime:            order.TransactTime,
+	}
+
+}
+
+/* Return the proto message of a pending action, nil without pending action */
+func protoPendingAction(action types.PendingAction) *cryptopumpv1.PendingAction {
+
+	if action.ID == 0 {
+
+		return nil
+
+	}
+
+	return &cryptopumpv1.PendingAction{
+		Id:          action.ID,
+		ThreadId:    action.ThreadID,
+		Action:      action.Action,
+		OrderId:     action.OrderID,
+		Notional:    action.Notional,
+		CreatedTime: action.CreatedTime,
+	}
+
+}
+
+/* Return the proto message of an order filled event */
+func protoOrderEvent(
+	order events.Order,
+	t time.Time) *cryptopumpv1.OrderEvent {
+
+	return &cryptopumpv1.OrderEvent{
+		Side: order.Side,
+		Order: &cryptopumpv1.Order{
+			OrderId:                 order.OrderID,
+			Symbol:                  order.Symbol,
+			Side:                    order.Side,
+			Status:                  order.Status,
+			Price:                   order.Price,
+			ExecutedQuantity:        order.Quantity,
+			CumulativeQuoteQuantity: order.Price * order.Quantity,
+			TransactTime:            t.UnixNano() / int64(time.Millisecond),
+		},
+		Profit: order.Profit,
+		Time:   t.UnixNano() / int64(time.Millisecond),
+	}
+
+}
@@ -0,0 +1,255 @@
+package approval
+
+/* This package implements the two-step confirmation of manual sales. A manual sale of an order valued
+above configData.SellConfirmNotional is saved to the pendingaction table instead of being executed, and
+is only executed once the operator approves it from the confirmation dialog. */
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const approvalTimeout = 5 * time.Minute /* Pending actions expire when not approved within approvalTimeout */
+
+/* Approval errors */
+var (
+	ErrNotPending = errors.New("Manual sale not pending confirmation")
+	ErrExpired    = errors.New("Manual sale confirmation expired")
+)
+
+// ForceSell request the manual sale of orderID (0 for the most recent thread transaction). The sale is
+// executed immediately unless the order value exceeds configData.SellConfirmNotional, in which case it
+// is saved as a pending action and pending is true.
+func ForceSell(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	orderID int64) (pending bool, err error) {
+
+	var order types.Order
+
+	if configData.SellConfirmNotional > 0 {
+
+		if orderID != 0 {
+
+			sessionData.ForceSellOrderID = orderID
+			order, err = mysql.GetOrderByOrderID(sessionData)
+
+		} else {
+
+			order, err = mysql.GetThreadLastTransaction(sessionData)
+
+		}
+
+		if err != nil {
+
+			sessionData.ForceSellOrderID = 0
+			return false, err
+
+		}
+
+		if notional := orderNotional(order, marketData.Price); notional > configData.SellConfirmNotional {
+
+			sessionData.ForceSellOrderID = 0
+
+			if err = mysql.SavePendingAction(sessionData, types.PendingAction{
+				ThreadID:    sessionData.ThreadID,
+				Action:      "SELL",
+				OrderID:     order.OrderID, /* Order priced, not the most recent transaction at approval */
+				Notional:    notional,
+				CreatedTime: time.Now().UnixNano() / int64(time.Millisecond),
+			}); err != nil {
+
+				return false, err
+
+			}
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   marketData,
+				Session:  sessionData,
+				Order:    &order,
+				Message:  fmt.Sprintf("Manual sale of %.2f %s pending confirmation", notional, sessionData.SymbolFiat),
+				LogLevel: "InfoLevel",
+			}.Do()
+
+			return true, nil
+
+		}
+
+	}
+
+	sessionData.ForceSellOrderID = orderID /* Force sell a specific orderID, 0 for most recent order */
+	sessionData.ForceSell = true           /* Force sell */
+
+	return false, nil
+
+}
+
+// GetPending retrieve the manual sale awaiting confirmation for sessionData.ThreadID, expiring it after approvalTimeout
+func GetPending(
+	sessionData *types.Session) (action types.PendingAction, err error) {
+
+	if action, err = mysql.GetPendingAction(sessionData); err != nil || action.ID == 0 {
+
+		return types.PendingAction{}, err
+
+	}
+
+	if isExpired(action.CreatedTime, time.Now()) {
+
+		return types.PendingAction{}, mysql.UpdatePendingAction(sessionData, action.ID, "EXPIRED")
+
+	}
+
+	return action, nil
+
+}
+
+// Approve execute the pending manual sale with id
+func Approve(
+	configData *types.Config,
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var action types.PendingAction
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "InfoLevel",
+			}.Do()
+		}
+	}()
+
+	if action, err = GetPending(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if action.ID == 0 || action.ID != id {
+
+		return ErrNotPending
+
+	}
+
+	if err = mysql.UpdatePendingAction(sessionData, action.ID, "APPROVED"); err != nil {
+
+		return err
+
+	}
+
+	sessionData.ForceSellOrderID = action.OrderID /* Force sell a specific orderID, 0 for most recent order */
+	sessionData.ForceSell = true                  /* Force sell */
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{OrderID: action.OrderID},
+		Message:  fmt.Sprintf("Manual sale of %.2f %s confirmed", action.Notional, sessionData.SymbolFiat),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Reject cancel the pending manual sale with id
+func Reject(
+	configData *types.Config,
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var action types.PendingAction
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "InfoLevel",
+			}.Do()
+		}
+	}()
+
+	if action, err = GetPending(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if action.ID == 0 || action.ID != id {
+
+		return ErrNotPending
+
+	}
+
+	if err = mysql.UpdatePendingAction(sessionData, action.ID, "REJECTED"); err != nil {
+
+		return err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{OrderID: action.OrderID},
+		Message:  "Manual sale cancelled",
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Expired return true when the pending action is older than the confirmation timeout
+func Expired(
+	action types.PendingAction,
+	now time.Time) bool {
+
+	return isExpired(action.CreatedTime, now)
+
+}
+
+/* Return the order value in fiat at price, or at cost when price is not available */
+func orderNotional(
+	order types.Order,
+	price float64) float64 {
+
+	if price <= 0 {
+
+		return order.CumulativeQuoteQuantity
+
+	}
+
+	return order.ExecutedQuantity * price
+
+}
+
+/* Return true when a pending action created at createdTime (milliseconds) is older than approvalTimeout */
+func isExpired(
+	createdTime int64,
+	now time.Time) bool {
+
+	return now.Sub(time.Unix(0, createdTime*int64(time.Millisecond))) > approvalTimeout
+
+}
@@ -0,0 +1,151 @@
+package approval
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_orderNotional(t *testing.T) {
+	type args struct {
+		order types.Order
+		price float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "market price",
+			args: args{
+				order: types.Order{ExecutedQuantity: 0.5, CumulativeQuoteQuantity: 20000},
+				price: 50000,
+			},
+			want: 25000,
+		},
+		{
+			name: "no market price",
+			args: args{
+				order: types.Order{ExecutedQuantity: 0.5, CumulativeQuoteQuantity: 20000},
+				price: 0,
+			},
+			want: 20000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := orderNotional(tt.args.order, tt.args.price); got != tt.want {
+				t.Errorf("orderNotional() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isExpired(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		createdTime int64
+		now         time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "pending",
+			args: args{
+				createdTime: now.Add(-time.Minute).UnixNano() / int64(time.Millisecond),
+				now:         now,
+			},
+			want: false,
+		},
+		{
+			name: "expired",
+			args: args{
+				createdTime: now.Add(-6*time.Minute).UnixNano() / int64(time.Millisecond),
+				now:         now,
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isExpired(tt.args.createdTime, tt.args.now); got != tt.want {
+				t.Errorf("isExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForceSell(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadLastTransaction(?)")).
+		WillReturnRows(sqlmock.NewRows([]string{"CummulativeQuoteQty", "OrderID", "Price", "ExecutedQuantity", "TransactTime"}).
+			AddRow(1000, 4259371, 50000, 0.02, 1638230400000))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SavePendingAction(?,?,?,?,?)")).
+		WithArgs("c683ok5mk1u1120gnmmg", "SELL", 4259371, 1200.0, sqlmock.AnyArg()). /* Order priced, not 0 for the most recent transaction */
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+
+	pending, err := ForceSell(
+		&types.Config{ConfigGlobal: &types.ConfigGlobal{}, SellConfirmNotional: 500},
+		&types.Market{Price: 60000},
+		&types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Db: db},
+		0)
+
+	if !pending || err != nil {
+		t.Errorf("ForceSell() = %v, %v, want pending", pending, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("ForceSell() %v", err)
+	}
+}
+
+func TestReject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"ID", "Action", "OrderID", "Notional", "CreatedTime"}
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Db: db}
+	configData := &types.Config{ConfigGlobal: &types.ConfigGlobal{}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPendingAction(?)")).
+		WithArgs("c683ok5mk1u1120gnmmg").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, "SELL", 4259371, 1200, now))
+
+	if err := Reject(configData, sessionData, 8); err != ErrNotPending { /* Pending action of another thread */
+		t.Errorf("Reject() error = %v, wantErr %v", err, ErrNotPending)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPendingAction(?)")).
+		WithArgs("c683ok5mk1u1120gnmmg").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, "SELL", 4259371, 1200, now))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdatePendingAction(?,?)")).
+		WithArgs(7, "REJECTED").
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+
+	if err := Reject(configData, sessionData, 7); err != nil {
+		t.Errorf("Reject() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Reject() %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package audit
+
+/* This package implements the verification of the order audit trail. Every order saved or updated by any thread is
+appended to the audit table by the mysql package with a JSON payload (types.AuditEvent), and the SaveAudit procedure
+chains each record to the previous one: PrevHash is the Hash of the previous record and Hash is the SHA-256 of PrevHash
+and the payload. The table rejects updates and deletes, and any change made around it (edited payload, deleted or
+reordered records) breaks the chain from the changed record, so the integrity of the trade history can be proven by
+Verify. Recording the last hash reported by Verify outside the database also proves that no record was removed from
+the end of the table. */
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const pageSize = 1000 /* Records retrieved per query */
+
+// Genesis is the PrevHash of the first record of the audit table
+var Genesis = strings.Repeat("0", 64)
+
+/* Verification errors */
+var (
+	ErrPrevHash = errors.New("previous hash doesn't match the previous record, a record was deleted, inserted or reordered")
+	ErrHash     = errors.New("hash doesn't match the payload, the record was modified")
+	ErrPayload  = errors.New("payload doesn't match the record")
+)
+
+// Result struct define the result of the verification of the audit table
+type Result struct {
+	Records  int64  /* Records verified */
+	LastID   int64  /* ID of the last valid record */
+	LastHash string /* Hash of the last valid record, Genesis when there are none */
+	BrokenID int64  /* ID of the first invalid record, 0 when the chain is intact */
+	Err      error  /* Reason of the first invalid record */
+}
+
+// Hash return the SHA-256 of prevHash and payload in hex, as computed by the SaveAudit procedure
+func Hash(
+	prevHash string,
+	payload string) string {
+
+	sum := sha256.Sum256([]byte(prevHash + payload))
+
+	return hex.EncodeToString(sum[:])
+
+}
+
+// Check return the error of record when it doesn't follow the record with hash prevHash
+func Check(
+	record types.AuditRecord,
+	prevHash string) error {
+
+	if record.PrevHash != prevHash {
+
+		return ErrPrevHash
+
+	}
+
+	if record.Hash != Hash(record.PrevHash, record.Payload) {
+
+		return ErrHash
+
+	}
+
+	event := types.AuditEvent{}
+	if err := json.Unmarshal([]byte(record.Payload), &event); err != nil ||
+		event.Event != record.Event ||
+		event.Time != record.Time ||
+		event.ThreadID != record.ThreadID ||
+		event.Order.OrderID != record.OrderID {
+
+		return ErrPayload
+
+	}
+
+	return nil
+
+}
+
+// Verify the hash chain of the audit table from the first record, stopping at the first invalid record
+func Verify(sessionData *types.Session) (result Result, err error) {
+
+	var records []types.AuditRecord
+
+	result.LastHash = Genesis
+
+	for {
+
+		if records, err = mysql.GetAudit(sessionData, result.LastID, pageSize); err != nil {
+
+			return result, err
+
+		}
+
+		for _, record := range records {
+
+			if result.Err = Check(record, result.LastHash); result.Err != nil {
+
+				result.BrokenID = record.ID
+				return result, nil
+
+			}
+
+			result.Records++
+			result.LastID = record.ID
+			result.LastHash = record.Hash
+
+		}
+
+		if len(records) < pageSize {
+
+			return result, nil
+
+		}
+
+	}
+
+}
+
+// Command verify the audit table from the command line, printing the result to out. Returns an error when the chain
+// is broken.
+func Command(
+	sessionData *types.Session,
+	out io.Writer) (err error) {
+
+	var result Result
+
+	if result, err = Verify(sessionData); err != nil {
+
+		fmt.Fprintln(out, "Audit trail verification failed: "+err.Error())
+		return err
+
+	}
+
+	if result.Err != nil {
+
+		fmt.Fprintf(out, "Audit trail broken at record %d: %s\n", result.BrokenID, result.Err.Error())
+		fmt.Fprintf(out, "%d records verified before it, last valid record %d with hash %s\n", result.Records, result.LastID, result.LastHash)
+		return result.Err
+
+	}
+
+	fmt.Fprintf(out, "Audit trail intact: %d records verified, last record %d with hash %s\n", result.Records, result.LastID, result.LastHash)
+
+	return nil
+
+}
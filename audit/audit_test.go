@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Return the record of event chained to prevHash */
+func record(
+	id int64,
+	prevHash string,
+	event types.AuditEvent) types.AuditRecord {
+
+	payload, _ := json.Marshal(event)
+
+	return types.AuditRecord{
+		ID:       id,
+		Time:     event.Time,
+		ThreadID: event.ThreadID,
+		Event:    event.Event,
+		OrderID:  event.Order.OrderID,
+		Payload:  string(payload),
+		PrevHash: prevHash,
+		Hash:     Hash(prevHash, string(payload)),
+	}
+
+}
+
+func TestHash(t *testing.T) {
+
+	/* SHA2(CONCAT(REPEAT('0', 64), '{}'), 256) */
+	if got := Hash(Genesis, "{}"); got != "5508d2b710e64bc470079e1b211d9c58e21011e59d0559e422345dc19d659a75" {
+		t.Errorf("Hash() = %v", got)
+	}
+
+	if Hash(Genesis, "{}") == Hash(Genesis, "{ }") {
+		t.Errorf("Hash() doesn't depend on the payload")
+	}
+
+}
+
+func TestCheck(t *testing.T) {
+
+	first := record(1, Genesis, types.AuditEvent{Event: "order.create", Time: 1638230400000, ThreadID: "c683ok5mk1u1120gnmmg", Order: types.Order{OrderID: 42, Side: "BUY", Price: 100}})
+	second := record(2, first.Hash, types.AuditEvent{Event: "order.update", Time: 1638230460000, ThreadID: "c683ok5mk1u1120gnmmg", Order: types.Order{OrderID: 42, Status: "FILLED"}})
+
+	modified := second
+	modified.Payload = `{"event":"order.update","time":1638230460000,"threadId":"c683ok5mk1u1120gnmmg","order":{"orderId":42,"status":"CANCELED"}}`
+
+	column := second
+	column.OrderID = 43
+
+	tests := []struct {
+		name     string
+		record   types.AuditRecord
+		prevHash string
+		want     error
+	}{
+		{name: "first", record: first, prevHash: Genesis, want: nil},
+		{name: "second", record: second, prevHash: first.Hash, want: nil},
+		{name: "deleted", record: second, prevHash: Genesis, want: ErrPrevHash},
+		{name: "modified", record: modified, prevHash: first.Hash, want: ErrHash},
+		{name: "column", record: column, prevHash: first.Hash, want: ErrPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.record, tt.prevHash); got != tt.want {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,639 @@
+package auth
+
+/* This package implements the dashboard authentication and role-based access control. Users are stored in
+the user table with a role and bcrypt password hashes, the UI authenticates with a session
+cookie and the REST API with bearer tokens. Tokens are random values of which only the SHA-256 hash is stored in the authtoken table, so they are
+shared by all sessions using the same database. After maxFailedLogins consecutive failed logins the user is
+locked out for lockoutDuration. Dashboard form posts must carry the CSRF token derived from the session token,
+and sessions expire when idle and above the concurrent session limit of the global configuration. Optional
+two-factor authentication is implemented in totp.go. */
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+	"golang.org/x/crypto/bcrypt"
+)
+
+/* Token kinds */
+const (
+	KindSession = "SESSION" /* UI session cookie */
+	KindAPI     = "API"     /* REST API bearer token */
+)
+
+// CookieName is the name of the UI session cookie
+const CookieName = "cryptopump_session"
+
+// CSRFCookieName is the name of the cookie holding the CSRF token of the UI session, read by static/javascript/csrf.js
+const CSRFCookieName = "cryptopump_csrf"
+
+// CSRFField is the name of the html form field carrying the CSRF token
+const CSRFField = "csrfToken"
+
+const (
+	sessionDuration   = 24 * time.Hour   /* UI sessions expire after sessionDuration */
+	maxFailedLogins   = 5                /* Consecutive failed logins before lockout */
+	lockoutDuration   = 15 * time.Minute /* Login lockout duration */
+	passwordMinLength = 8                /* Minimum password length */
+	passwordMaxLength = 72               /* Maximum password length in bytes, the bcrypt input limit */
+	hashCost          = 12               /* bcrypt cost */
+	tokenLength       = 32               /* Token length in bytes */
+	lastSeenInterval  = time.Minute      /* Minimum time between UI session last request updates */
+)
+
+/* Authentication errors */
+var (
+	ErrUnauthenticated    = errors.New("Unauthenticated")
+	ErrInvalidCredentials = errors.New("Invalid username or password")
+	ErrLocked             = errors.New("Too many failed logins, try again later")
+	ErrInvalidUsername    = errors.New("Username cannot be empty")
+	ErrWeakPassword       = errors.New("Password must have at least 8 characters")
+	ErrLongPassword       = errors.New("Password must have at most 72 bytes")
+	ErrPasswordMismatch   = errors.New("Passwords do not match")
+	ErrUnknownUser        = errors.New("Unknown user")
+	ErrInvalidCSRF        = errors.New("Invalid or missing CSRF token, reload the page")
+)
+
+// Setup return true when no users exist and the first user must be created
+func Setup(
+	sessionData *types.Session) (bool, error) {
+
+	count, err := mysql.GetUserCount(sessionData)
+	if err != nil {
+
+		return false, err
+
+	}
+
+	return count == 0, nil
+
+}
+
+// CreateUser save a dashboard user, or reset the password, role and lockout of an existing user
+func CreateUser(
+	configData *types.Config,
+	sessionData *types.Session,
+	username string,
+	password string,
+	role string) (err error) {
+
+	var passwordHash string
+
+	if username = strings.TrimSpace(username); username == "" {
+
+		return ErrInvalidUsername
+
+	}
+
+	if !ValidRole(role) {
+
+		return ErrInvalidRole
+
+	}
+
+	if len(password) < passwordMinLength {
+
+		return ErrWeakPassword
+
+	}
+
+	if len(password) > passwordMaxLength {
+
+		return ErrLongPassword
+
+	}
+
+	if passwordHash, err = hashPassword(password); err != nil {
+
+		return err
+
+	}
+
+	if err = mysql.SaveUser(sessionData, types.User{
+		Username:     username,
+		PasswordHash: passwordHash,
+		Role:         role,
+	}); err != nil {
+
+		return err
+
+	}
+
+	log(configData, sessionData, "User "+username+" saved with role "+role)
+
+	return nil
+
+}
+
+// Login verify username, password and, for users with two-factor authentication enabled, the authentication
+// or recovery code and return a new UI session token. Failed logins are counted and the user is locked out
+// after maxFailedLogins consecutive failures.
+func Login(
+	configData *types.Config,
+	sessionData *types.Session,
+	username string,
+	password string,
+	code string) (token string, err error) {
+
+	var user types.User
+
+	now := time.Now()
+
+	if user, err = mysql.GetUser(sessionData, strings.TrimSpace(username)); err != nil {
+
+		return "", err
+
+	}
+
+	if user.Username == "" {
+
+		_ = verifyPassword(password, "") /* Spend the same time as an existing user */
+		return "", ErrInvalidCredentials
+
+	}
+
+	if isLocked(user.LockedUntil, now) {
+
+		return "", ErrLocked
+
+	}
+
+	loginErr := ErrInvalidCredentials
+
+	if verifyPassword(password, user.PasswordHash) {
+
+		loginErr = nil
+
+		if user.TOTPEnabled {
+
+			loginErr = verifyUserCode(configData, sessionData, user, code)
+
+		}
+
+	}
+
+	if loginErr != nil {
+
+		failedLogins, lockedUntil := failedLogin(user.FailedLogins, now)
+
+		if err = mysql.UpdateUserLogin(sessionData, user.Username, failedLogins, lockedUntil); err != nil {
+
+			return "", err
+
+		}
+
+		if lockedUntil != 0 {
+
+			log(configData, sessionData, fmt.Sprintf("User %s locked out for %s after %d failed logins", user.Username, lockoutDuration, failedLogins))
+			return "", ErrLocked
+
+		}
+
+		log(configData, sessionData, "Failed login for user "+user.Username)
+		return "", loginErr
+
+	}
+
+	if user.FailedLogins != 0 || user.LockedUntil != 0 {
+
+		if err = mysql.UpdateUserLogin(sessionData, user.Username, 0, 0); err != nil {
+
+			return "", err
+
+		}
+
+	}
+
+	if token, err = issueToken(sessionData, user.Username, KindSession, now.Add(sessionDuration).UnixNano()/int64(time.Millisecond)); err != nil {
+
+		return "", err
+
+	}
+
+	if configData.ConfigGlobal != nil && configData.ConfigGlobal.SessionMax > 0 { /* Revoke the least recently used sessions above the limit */
+
+		if err = mysql.DeleteExcessAuthTokens(sessionData, user.Username, KindSession, configData.ConfigGlobal.SessionMax); err != nil {
+
+			return "", err
+
+		}
+
+	}
+
+	return token, nil
+
+}
+
+// Logout revoke a UI session token
+func Logout(
+	sessionData *types.Session,
+	token string) error {
+
+	if token == "" {
+
+		return nil
+
+	}
+
+	return mysql.DeleteAuthToken(sessionData, hashToken(token))
+
+}
+
+// Authenticate return the username and role a token of kind was issued to
+func Authenticate(
+	sessionData *types.Session,
+	token string,
+	kind string) (authToken types.AuthToken, err error) {
+
+	if token == "" {
+
+		return types.AuthToken{}, ErrUnauthenticated
+
+	}
+
+	if authToken, err = mysql.GetAuthToken(sessionData, hashToken(token)); err != nil {
+
+		return types.AuthToken{}, err
+
+	}
+
+	if authToken.Username == "" || authToken.Kind != kind {
+
+		return types.AuthToken{}, ErrUnauthenticated
+
+	}
+
+	if isExpired(authToken.Expires, time.Now()) {
+
+		_ = mysql.DeleteAuthToken(sessionData, hashToken(token))
+		return types.AuthToken{}, ErrUnauthenticated
+
+	}
+
+	return authToken, nil
+
+}
+
+// CreateAPIToken return a new REST API token for username with the role of the user. Only the token hash
+// is stored, so the token cannot be displayed again.
+func CreateAPIToken(
+	configData *types.Config,
+	sessionData *types.Session,
+	username string) (token string, err error) {
+
+	var user types.User
+
+	if user, err = mysql.GetUser(sessionData, strings.TrimSpace(username)); err != nil {
+
+		return "", err
+
+	}
+
+	if user.Username == "" {
+
+		return "", ErrUnknownUser
+
+	}
+
+	if token, err = issueToken(sessionData, user.Username, KindAPI, 0); err != nil {
+
+		return "", err
+
+	}
+
+	log(configData, sessionData, "REST API token created for user "+user.Username)
+
+	return token, nil
+
+}
+
+// RevokeAPITokens revoke all REST API tokens of username
+func RevokeAPITokens(
+	configData *types.Config,
+	sessionData *types.Session,
+	username string) error {
+
+	if err := mysql.DeleteAuthTokenByUsername(sessionData, username, KindAPI); err != nil {
+
+		return err
+
+	}
+
+	log(configData, sessionData, "REST API tokens revoked for user "+username)
+
+	return nil
+
+}
+
+// SessionUser return the username and role of the UI session cookie in r. Sessions without requests for
+// configData.ConfigGlobal.SessionIdleTimeout minutes are revoked.
+func SessionUser(
+	configData *types.Config,
+	sessionData *types.Session,
+	r *http.Request) (authToken types.AuthToken, err error) {
+
+	var idleTimeout time.Duration
+
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+
+		return types.AuthToken{}, ErrUnauthenticated
+
+	}
+
+	if authToken, err = Authenticate(sessionData, cookie.Value, KindSession); err != nil {
+
+		return types.AuthToken{}, err
+
+	}
+
+	if configData.ConfigGlobal != nil {
+
+		idleTimeout = time.Duration(configData.ConfigGlobal.SessionIdleTimeout) * time.Minute
+
+	}
+
+	now := time.Now()
+
+	if isIdle(authToken.LastSeen, idleTimeout, now) {
+
+		_ = mysql.DeleteAuthToken(sessionData, hashToken(cookie.Value))
+		log(configData, sessionData, "Session of user "+authToken.Username+" expired after "+idleTimeout.String()+" idle")
+		return types.AuthToken{}, ErrUnauthenticated
+
+	}
+
+	if now.Sub(time.Unix(0, authToken.LastSeen*int64(time.Millisecond))) >= lastSeenInterval {
+
+		_ = mysql.UpdateAuthTokenLastSeen(sessionData, hashToken(cookie.Value), now.UnixNano()/int64(time.Millisecond))
+
+	}
+
+	return authToken, nil
+
+}
+
+// SessionToken return the UI session token of the cookie in r
+func SessionToken(r *http.Request) string {
+
+	if cookie, err := r.Cookie(CookieName); err == nil {
+
+		return cookie.Value
+
+	}
+
+	return ""
+
+}
+
+// SetSessionCookie write the UI session cookie with token
+func SetSessionCookie(
+	w http.ResponseWriter,
+	r *http.Request,
+	token string) {
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(sessionDuration.Seconds()),
+		HttpOnly: true,
+		Secure:   isSecure(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	SetCSRFCookie(w, r, token)
+
+}
+
+// SetCSRFCookie write the CSRF token cookie of the UI session token. The cookie is readable by scripts so
+// static/javascript/csrf.js can add the token to html forms.
+func SetCSRFCookie(
+	w http.ResponseWriter,
+	r *http.Request,
+	token string) {
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    CSRFToken(token),
+		Path:     "/",
+		MaxAge:   int(sessionDuration.Seconds()),
+		HttpOnly: false,
+		Secure:   isSecure(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+
+}
+
+// CSRFToken return the CSRF token of a UI session token
+func CSRFToken(token string) string {
+
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte("csrf"))
+
+	return hex.EncodeToString(mac.Sum(nil))
+
+}
+
+// VerifyCSRF return true when the CSRF token of the html form in r matches the UI session cookie
+func VerifyCSRF(r *http.Request) bool {
+
+	token := SessionToken(r)
+	if token == "" {
+
+		return false
+
+	}
+
+	return hmac.Equal([]byte(r.PostFormValue(CSRFField)), []byte(CSRFToken(token)))
+
+}
+
+// HasCSRFCookie return true when r carries the CSRF token cookie, sessions created before CSRF protection do not
+func HasCSRFCookie(r *http.Request) bool {
+
+	_, err := r.Cookie(CSRFCookieName)
+
+	return err == nil
+
+}
+
+// ClearSessionCookie remove the UI session cookie
+func ClearSessionCookie(w http.ResponseWriter) {
+
+	for _, name := range []string{CookieName, CSRFCookieName} {
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Value:    "",
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: name == CookieName,
+			SameSite: http.SameSiteStrictMode,
+		})
+
+	}
+
+}
+
+// BearerToken return the token of an "Authorization: Bearer <token>" header
+func BearerToken(header string) string {
+
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+
+		return strings.TrimSpace(header[7:])
+
+	}
+
+	return ""
+
+}
+
+/* Log an authentication event */
+func log(
+	configData *types.Config,
+	sessionData *types.Session,
+	message string) {
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+}
+
+/* Save the hash of a new random token of kind for username and return the token */
+func issueToken(
+	sessionData *types.Session,
+	username string,
+	kind string,
+	expires int64) (string, error) {
+
+	b := make([]byte, tokenLength)
+
+	if _, err := rand.Read(b); err != nil {
+
+		return "", err
+
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := mysql.SaveAuthToken(sessionData, hashToken(token), types.AuthToken{
+		Username: username,
+		Kind:     kind,
+		Expires:  expires,
+		LastSeen: time.Now().UnixNano() / int64(time.Millisecond),
+	}); err != nil {
+
+		return "", err
+
+	}
+
+	return token, nil
+
+}
+
+/* Return the SHA-256 hash of token as stored in the authtoken table */
+func hashToken(token string) string {
+
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+
+}
+
+/* Return true when r was received over TLS, directly or through a reverse proxy */
+func isSecure(r *http.Request) bool {
+
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+
+}
+
+/* Return true when a UI session last seen at lastSeen (milliseconds) exceeded idleTimeout, 0 disables */
+func isIdle(
+	lastSeen int64,
+	idleTimeout time.Duration,
+	now time.Time) bool {
+
+	return idleTimeout > 0 && lastSeen != 0 && now.Sub(time.Unix(0, lastSeen*int64(time.Millisecond))) > idleTimeout
+
+}
+
+/* Return the failed login count and lockout end time in milliseconds after a failed login, 0 when not locked */
+func failedLogin(
+	failedLogins int,
+	now time.Time) (int, int64) {
+
+	failedLogins++
+
+	if failedLogins >= maxFailedLogins {
+
+		return failedLogins, now.Add(lockoutDuration).UnixNano() / int64(time.Millisecond)
+
+	}
+
+	return failedLogins, 0
+
+}
+
+/* Return true while the lockout ending at lockedUntil (milliseconds) is active */
+func isLocked(
+	lockedUntil int64,
+	now time.Time) bool {
+
+	return lockedUntil != 0 && now.UnixNano()/int64(time.Millisecond) < lockedUntil
+
+}
+
+/* Return true when a token expiring at expires (milliseconds) has expired, 0 never expires */
+func isExpired(
+	expires int64,
+	now time.Time) bool {
+
+	return expires != 0 && now.UnixNano()/int64(time.Millisecond) >= expires
+
+}
+
+/* bcrypt hash of a random password, compared when the user or its hash is invalid to spend the same time as a valid hash */
+const dummyHash = "$2a$12$sgzbcCLfT1PBQF78XJ9do.6Oy1zGI8cQdNfAEutItF7HPaBkKZMBa"
+
+/* Return the bcrypt password hash */
+func hashPassword(password string) (string, error) {
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), hashCost)
+
+	return string(hash), err
+
+}
+
+/* Return true when password matches the bcrypt password hash */
+func verifyPassword(
+	password string,
+	encoded string) bool {
+
+	if _, err := bcrypt.Cost([]byte(encoded)); err != nil {
+
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(password)) /* Spend the same time as a valid hash */
+		return false
+
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+
+}
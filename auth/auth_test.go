@@ -0,0 +1,497 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_verifyPassword(t *testing.T) {
+	encoded, err := hashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("hashPassword() error = %v", err)
+	}
+	type args struct {
+		password string
+		encoded  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "valid password",
+			args: args{password: "correct horse", encoded: encoded},
+			want: true,
+		},
+		{
+			name: "invalid password",
+			args: args{password: "battery staple", encoded: encoded},
+			want: false,
+		},
+		{
+			name: "stored hash",
+			args: args{password: "cryptopump", encoded: dummyHash},
+			want: true,
+		},
+		{
+			name: "invalid hash",
+			args: args{password: "correct horse", encoded: "correct horse"},
+			want: false,
+		},
+		{
+			name: "empty hash",
+			args: args{password: "", encoded: ""},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyPassword(tt.args.password, tt.args.encoded); got != tt.want {
+				t.Errorf("verifyPassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_failedLogin(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		failedLogins int
+		now          time.Time
+	}
+	tests := []struct {
+		name             string
+		args             args
+		wantFailedLogins int
+		wantLockedUntil  int64
+	}{
+		{
+			name:             "first failure",
+			args:             args{failedLogins: 0, now: now},
+			wantFailedLogins: 1,
+			wantLockedUntil:  0,
+		},
+		{
+			name:             "lockout",
+			args:             args{failedLogins: maxFailedLogins - 1, now: now},
+			wantFailedLogins: maxFailedLogins,
+			wantLockedUntil:  now.Add(lockoutDuration).UnixNano() / int64(time.Millisecond),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFailedLogins, gotLockedUntil := failedLogin(tt.args.failedLogins, tt.args.now)
+			if gotFailedLogins != tt.wantFailedLogins {
+				t.Errorf("failedLogin() failedLogins = %v, want %v", gotFailedLogins, tt.wantFailedLogins)
+			}
+			if gotLockedUntil != tt.wantLockedUntil {
+				t.Errorf("failedLogin() lockedUntil = %v, want %v", gotLockedUntil, tt.wantLockedUntil)
+			}
+		})
+	}
+}
+
+func Test_isLocked(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		lockedUntil int64
+		now         time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "not locked",
+			args: args{lockedUntil: 0, now: now},
+			want: false,
+		},
+		{
+			name: "locked",
+			args: args{lockedUntil: now.Add(time.Minute).UnixNano() / int64(time.Millisecond), now: now},
+			want: true,
+		},
+		{
+			name: "lockout ended",
+			args: args{lockedUntil: now.Add(-time.Minute).UnixNano() / int64(time.Millisecond), now: now},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLocked(tt.args.lockedUntil, tt.args.now); got != tt.want {
+				t.Errorf("isLocked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "bearer token",
+			header: "Bearer abc123",
+			want:   "abc123",
+		},
+		{
+			name:   "case insensitive scheme",
+			header: "bearer abc123",
+			want:   "abc123",
+		},
+		{
+			name:   "basic authentication",
+			header: "Basic YWRtaW46YWRtaW4=",
+			want:   "",
+		},
+		{
+			name:   "empty header",
+			header: "",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BearerToken(tt.header); got != tt.want {
+				t.Errorf("BearerToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	type args struct {
+		role     string
+		required string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "viewer reads",
+			args: args{role: RoleViewer, required: RoleViewer},
+			want: true,
+		},
+		{
+			name: "viewer trades",
+			args: args{role: RoleViewer, required: RoleTrader},
+			want: false,
+		},
+		{
+			name: "trader trades",
+			args: args{role: RoleTrader, required: RoleTrader},
+			want: true,
+		},
+		{
+			name: "trader configures",
+			args: args{role: RoleTrader, required: RoleAdmin},
+			want: false,
+		},
+		{
+			name: "admin trades",
+			args: args{role: RoleAdmin, required: RoleTrader},
+			want: true,
+		},
+		{
+			name: "unknown role",
+			args: args{role: "root", required: RoleViewer},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Allowed(tt.args.role, tt.args.required); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		want   string
+	}{
+		{
+			name:   "logout",
+			action: "logout",
+			want:   RoleViewer,
+		},
+		{
+			name:   "save preferences",
+			action: "preferencesSave",
+			want:   RoleViewer,
+		},
+		{
+			name:   "journal note",
+			action: "noteSave",
+			want:   RoleTrader,
+		},
+		{
+			name:   "force sell",
+			action: "sell",
+			want:   RoleTrader,
+		},
+		{
+			name:   "manual order",
+			action: "manualOrder",
+			want:   RoleTrader,
+		},
+		{
+			name:   "pause thread",
+			action: "pause",
+			want:   RoleTrader,
+		},
+		{
+			name:   "update configuration",
+			action: "update",
+			want:   RoleAdmin,
+		},
+		{
+			name:   "save alert rule",
+			action: "alertSave",
+			want:   RoleAdmin,
+		},
+		{
+			name:   "enroll two-factor authentication",
+			action: "totpEnroll",
+			want:   RoleViewer,
+		},
+		{
+			name:   "unknown action",
+			action: "unknown",
+			want:   RoleAdmin,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ActionRole(tt.action); got != tt.want {
+				t.Errorf("ActionRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_totp(t *testing.T) {
+	secret := []byte("12345678901234567890") /* RFC 6238 test vectors truncated to 6 digits */
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{
+			name: "59",
+			unix: 59,
+			want: "287082",
+		},
+		{
+			name: "1111111109",
+			unix: 1111111109,
+			want: "081804",
+		},
+		{
+			name: "2000000000",
+			unix: 2000000000,
+			want: "279037",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := totp(secret, uint64(tt.unix/totpPeriod)); got != tt.want {
+				t.Errorf("totp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validTOTP(t *testing.T) {
+	secret := base32NoPadding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111109, 0)
+	tests := []struct {
+		name string
+		code string
+		now  time.Time
+		want bool
+	}{
+		{
+			name: "current period",
+			code: "081804",
+			now:  now,
+			want: true,
+		},
+		{
+			name: "previous period",
+			code: "081804",
+			now:  now.Add(totpPeriod * time.Second),
+			want: true,
+		},
+		{
+			name: "expired",
+			code: "081804",
+			now:  now.Add(3 * totpPeriod * time.Second),
+			want: false,
+		},
+		{
+			name: "wrong code",
+			code: "123456",
+			now:  now,
+			want: false,
+		},
+		{
+			name: "empty code",
+			code: "",
+			now:  now,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validTOTP(secret, tt.code, tt.now); got != tt.want {
+				t.Errorf("validTOTP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newRecoveryCode(t *testing.T) {
+	code, err := newRecoveryCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != recoveryCodeLength+1 || code[recoveryCodeLength/2] != '-' {
+		t.Errorf("newRecoveryCode() = %v", code)
+	}
+	if got := normalizeCode(" " + strings.ToUpper(code) + " "); len(got) != recoveryCodeLength || got != strings.Replace(code, "-", "", 1) {
+		t.Errorf("normalizeCode() = %v", got)
+	}
+}
+
+func TestRequiresCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		want   bool
+	}{
+		{
+			name:   "confirm liquidation",
+			action: "liquidateConfirm",
+			want:   true,
+		},
+		{
+			name:   "create API token",
+			action: "apiTokenCreate",
+			want:   true,
+		},
+		{
+			name:   "buy",
+			action: "buy",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequiresCode(tt.action); got != tt.want {
+				t.Errorf("RequiresCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCSRF(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		token   string
+		want    bool
+	}{
+		{
+			name:    "valid",
+			session: "abc123",
+			token:   CSRFToken("abc123"),
+			want:    true,
+		},
+		{
+			name:    "token of another session",
+			session: "abc123",
+			token:   CSRFToken("def456"),
+			want:    false,
+		},
+		{
+			name:    "missing token",
+			session: "abc123",
+			token:   "",
+			want:    false,
+		},
+		{
+			name:    "missing session",
+			session: "",
+			token:   CSRFToken(""),
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", strings.NewReader(url.Values{CSRFField: {tt.token}}.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.session != "" {
+				r.AddCookie(&http.Cookie{Name: CookieName, Value: tt.session})
+			}
+			if got := VerifyCSRF(r); got != tt.want {
+				t.Errorf("VerifyCSRF() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isIdle(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		lastSeen    int64
+		idleTimeout time.Duration
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "active",
+			args: args{lastSeen: now.Add(-10*time.Minute).UnixNano() / int64(time.Millisecond), idleTimeout: 30 * time.Minute},
+			want: false,
+		},
+		{
+			name: "idle",
+			args: args{lastSeen: now.Add(-31*time.Minute).UnixNano() / int64(time.Millisecond), idleTimeout: 30 * time.Minute},
+			want: true,
+		},
+		{
+			name: "disabled",
+			args: args{lastSeen: now.Add(-48*time.Hour).UnixNano() / int64(time.Millisecond), idleTimeout: 0},
+			want: false,
+		},
+		{
+			name: "never seen",
+			args: args{lastSeen: 0, idleTimeout: 30 * time.Minute},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIdle(tt.args.lastSeen, tt.args.idleTimeout, now); got != tt.want {
+				t.Errorf("isIdle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
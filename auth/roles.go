@@ -0,0 +1,83 @@
+package auth
+
+import "errors"
+
+/* Roles, each role includes the permissions of the roles before it */
+const (
+	RoleViewer = "viewer" /* Read-only dashboards */
+	RoleTrader = "trader" /* Start and stop threads, buy and sell */
+	RoleAdmin  = "admin"  /* Configuration, credentials and users */
+)
+
+/* Role errors */
+var (
+	ErrForbidden   = errors.New("Forbidden")
+	ErrInvalidRole = errors.New("Role must be viewer, trader or admin")
+)
+
+/* Role rank used to compare roles */
+var roleRank = map[string]int{
+	RoleViewer: 1,
+	RoleTrader: 2,
+	RoleAdmin:  3,
+}
+
+/* Role required by each html form action (submitselect). Actions not listed require RoleAdmin. */
+var actionRoles = map[string]string{
+	"login":           RoleViewer,
+	"logout":          RoleViewer,
+	"preferencesSave": RoleViewer,
+	"totpEnroll":      RoleViewer,
+	"totpEnable":      RoleViewer,
+	"totpDisable":     RoleViewer,
+	"new":             RoleTrader,
+	"start":           RoleTrader,
+	"stop":            RoleTrader,
+	"buy":             RoleTrader,
+	"sell":            RoleTrader,
+	"sellConfirm":     RoleTrader,
+	"sellReject":      RoleTrader,
+	"stopPrice":       RoleTrader,
+	"pause":           RoleTrader,
+	"resume":          RoleTrader,
+	"manualOrder":     RoleTrader,
+	"reservation":     RoleTrader,
+	"noteSave":        RoleTrader,
+	"threadClone":     RoleTrader,
+	"sessionLabel":    RoleTrader,
+	"adopt":           RoleTrader,
+	"release":         RoleTrader,
+}
+
+// Allowed return true when role has the permissions of required
+func Allowed(
+	role string,
+	required string) bool {
+
+	rank, ok := roleRank[role]
+
+	return ok && rank >= roleRank[required]
+
+}
+
+// ValidRole return true when role is viewer, trader or admin
+func ValidRole(role string) bool {
+
+	_, ok := roleRank[role]
+
+	return ok
+
+}
+
+// ActionRole return the role required by an html form action
+func ActionRole(action string) string {
+
+	if role, ok := actionRoles[action]; ok {
+
+		return role
+
+	}
+
+	return RoleAdmin
+
+}
@@ -0,0 +1,413 @@
+package auth
+
+/* Optional two-factor authentication with time-based one-time passwords (TOTP, RFC 6238, HMAC-SHA1, 6 digits,
+30 seconds). Users enroll from the security page with any authenticator app, and once enabled the authentication
+code is required to login and to run the actions listed in codeActions. Single use recovery codes replace the
+authentication code when the authenticator is lost, only their SHA-256 hash is stored in the recoverycode table. */
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	totpIssuer         = "CryptoPump" /* Issuer displayed by authenticator apps */
+	totpPeriod         = 30           /* Seconds each authentication code is valid */
+	totpDigits         = 6            /* Authentication code digits */
+	totpSkew           = 1            /* Periods accepted before and after the current period to allow for clock drift */
+	totpSecretLength   = 20           /* TOTP secret length in bytes */
+	recoveryCodeCount  = 10           /* Recovery codes issued when two-factor authentication is enabled */
+	recoveryCodeLength = 10           /* Recovery code characters, displayed as xxxxx-xxxxx */
+)
+
+/* Two-factor authentication errors */
+var (
+	ErrInvalidCode     = errors.New("Invalid authentication code")
+	ErrTOTPEnabled     = errors.New("Two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled = errors.New("Start two-factor authentication enrollment first")
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+/* Html form actions (submitselect) requiring the authentication code of users with two-factor authentication enabled */
+var codeActions = map[string]bool{
+	"liquidateConfirm": true,
+	"userSave":         true,
+	"apiTokenCreate":   true,
+	"apiTokenRevoke":   true,
+	"totpDisable":      true,
+}
+
+// SecurityPage struct define the two-factor authentication page (security.html)
+type SecurityPage struct {
+	Username      string
+	TOTPEnabled   bool
+	Secret        string   /* Pending enrollment secret */
+	URI           string   /* Pending enrollment otpauth:// URI */
+	RecoveryCodes []string /* Recovery codes, displayed once */
+	Message       string
+	Theme         string /* UI theme of the logged in user */
+}
+
+// RequiresCode return true when an html form action requires the authentication code
+func RequiresCode(action string) bool {
+
+	return codeActions[action]
+
+}
+
+// LoadSecurityPage load the two-factor authentication page of username
+func LoadSecurityPage(
+	sessionData *types.Session,
+	username string,
+	message string) (page SecurityPage) {
+
+	page.Username = username
+	page.Message = message
+
+	user, err := mysql.GetUser(sessionData, username)
+	if err != nil {
+
+		if page.Message == "" {
+			page.Message = err.Error()
+		}
+
+		return page
+
+	}
+
+	page.TOTPEnabled = user.TOTPEnabled
+
+	if !user.TOTPEnabled && user.TOTPSecret != "" { /* Enrollment started but not confirmed */
+
+		page.Secret = user.TOTPSecret
+		page.URI = totpURI(user.Username, user.TOTPSecret)
+
+	}
+
+	return page
+
+}
+
+// EnrollTOTP save a new TOTP secret for username, enabled once confirmed with EnableTOTP
+func EnrollTOTP(
+	sessionData *types.Session,
+	username string) (err error) {
+
+	var user types.User
+	var secret string
+
+	if user, err = mysql.GetUser(sessionData, username); err != nil {
+
+		return err
+
+	}
+
+	if user.Username == "" {
+
+		return ErrUnknownUser
+
+	}
+
+	if user.TOTPEnabled {
+
+		return ErrTOTPEnabled
+
+	}
+
+	if secret, err = newTOTPSecret(); err != nil {
+
+		return err
+
+	}
+
+	return mysql.UpdateUserTOTP(sessionData, user.Username, secret, false)
+
+}
+
+// EnableTOTP enable two-factor authentication for username when code matches the enrollment secret and
+// return new recovery codes, replacing any previous ones
+func EnableTOTP(
+	configData *types.Config,
+	sessionData *types.Session,
+	username string,
+	code string) (codes []string, err error) {
+
+	var user types.User
+
+	if user, err = mysql.GetUser(sessionData, username); err != nil {
+
+		return nil, err
+
+	}
+
+	if user.TOTPEnabled {
+
+		return nil, ErrTOTPEnabled
+
+	}
+
+	if user.TOTPSecret == "" {
+
+		return nil, ErrTOTPNotEnrolled
+
+	}
+
+	if !validTOTP(user.TOTPSecret, code, time.Now()) {
+
+		return nil, ErrInvalidCode
+
+	}
+
+	if err = mysql.DeleteRecoveryCodes(sessionData, user.Username); err != nil {
+
+		return nil, err
+
+	}
+
+	for i := 0; i < recoveryCodeCount; i++ {
+
+		recoveryCode, err := newRecoveryCode()
+		if err != nil {
+
+			return nil, err
+
+		}
+
+		if err = mysql.SaveRecoveryCode(sessionData, user.Username, hashToken(normalizeCode(recoveryCode))); err != nil {
+
+			return nil, err
+
+		}
+
+		codes = append(codes, recoveryCode)
+
+	}
+
+	if err = mysql.UpdateUserTOTP(sessionData, user.Username, user.TOTPSecret, true); err != nil {
+
+		return nil, err
+
+	}
+
+	log(configData, sessionData, "Two-factor authentication enabled for user "+user.Username)
+
+	return codes, nil
+
+}
+
+// DisableTOTP disable two-factor authentication for username and delete the recovery codes
+func DisableTOTP(
+	configData *types.Config,
+	sessionData *types.Session,
+	username string) (err error) {
+
+	if err = mysql.UpdateUserTOTP(sessionData, username, "", false); err != nil {
+
+		return err
+
+	}
+
+	if err = mysql.DeleteRecoveryCodes(sessionData, username); err != nil {
+
+		return err
+
+	}
+
+	log(configData, sessionData, "Two-factor authentication disabled for user "+username)
+
+	return nil
+
+}
+
+// VerifyCode verify the authentication code or a recovery code of username, always successful for
+// users without two-factor authentication enabled. Recovery codes are consumed.
+func VerifyCode(
+	configData *types.Config,
+	sessionData *types.Session,
+	username string,
+	code string) error {
+
+	user, err := mysql.GetUser(sessionData, username)
+	if err != nil {
+
+		return err
+
+	}
+
+	if !user.TOTPEnabled {
+
+		return nil
+
+	}
+
+	return verifyUserCode(configData, sessionData, user, code)
+
+}
+
+/* Verify the authentication code or consume a recovery code of a user with two-factor authentication enabled */
+func verifyUserCode(
+	configData *types.Config,
+	sessionData *types.Session,
+	user types.User,
+	code string) error {
+
+	if validTOTP(user.TOTPSecret, code, time.Now()) {
+
+		return nil
+
+	}
+
+	if normalized := normalizeCode(code); len(normalized) == recoveryCodeLength {
+
+		used, err := mysql.UseRecoveryCode(sessionData, user.Username, hashToken(normalized))
+		if err != nil {
+
+			return err
+
+		}
+
+		if used {
+
+			log(configData, sessionData, "Recovery code used by user "+user.Username)
+			return nil
+
+		}
+
+	}
+
+	return ErrInvalidCode
+
+}
+
+/* Return the TOTP code of secret for counter as defined in RFC 4226 */
+func totp(
+	secret []byte,
+	counter uint64) string {
+
+	var message [8]byte
+
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%modulo)
+
+}
+
+/* Return true when code is the TOTP code of the base32 secret at now, allowing totpSkew periods of clock drift */
+func validTOTP(
+	secret string,
+	code string,
+	now time.Time) bool {
+
+	code = strings.TrimSpace(code)
+
+	if len(code) != totpDigits {
+
+		return false
+
+	}
+
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(key) == 0 {
+
+		return false
+
+	}
+
+	counter := now.Unix() / totpPeriod
+
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+
+		if hmac.Equal([]byte(totp(key, uint64(counter+i))), []byte(code)) {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Return a new random base32 TOTP secret */
+func newTOTPSecret() (string, error) {
+
+	b := make([]byte, totpSecretLength)
+
+	if _, err := rand.Read(b); err != nil {
+
+		return "", err
+
+	}
+
+	return base32NoPadding.EncodeToString(b), nil
+
+}
+
+/* Return the otpauth:// URI of secret used to add the account to authenticator apps */
+func totpURI(
+	username string,
+	secret string) string {
+
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", totpIssuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprint(totpDigits))
+	values.Set("period", fmt.Sprint(totpPeriod))
+
+	return "otpauth://totp/" + url.PathEscape(totpIssuer+":"+username) + "?" + values.Encode()
+
+}
+
+/* Return a new random recovery code formatted as xxxxx-xxxxx */
+func newRecoveryCode() (string, error) {
+
+	const alphabet = "abcdefghijkmnpqrstuvwxyz23456789" /* 32 characters without the ambiguous 0, 1, l and o */
+
+	b := make([]byte, recoveryCodeLength)
+
+	if _, err := rand.Read(b); err != nil {
+
+		return "", err
+
+	}
+
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+
+	return string(b[:recoveryCodeLength/2]) + "-" + string(b[recoveryCodeLength/2:]), nil
+
+}
+
+/* Return a recovery code without separators and spaces in lower case */
+func normalizeCode(code string) string {
+
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+
+}
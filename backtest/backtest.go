@@ -0,0 +1,238 @@
+package backtest
+
+/* This package implements the backtest results browser. A backtest run is saved with Save, with its parameters
+and the simulated equity and buy-and-hold value of the symbol over the period; the key metrics (return, buy-and-hold
+return and maximum drawdown) are derived from the equity. The backtests page lists the runs, compares the metrics
+and parameters of the selected runs and charts their equity against buy-and-hold. */
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/plotter"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const compareLimit = 5 /* Runs compared in the backtests page */
+
+// Run struct define a backtest run in the backtests page
+type Run struct {
+	types.Backtest
+	Period   string
+	Created  string
+	Selected bool
+}
+
+// Parameter struct define a parameter of the compared runs, with the value of each run
+type Parameter struct {
+	Name    string
+	Values  []string
+	Differs bool /* The runs use different values */
+}
+
+// Page struct define the backtests page (backtests.html)
+type Page struct {
+	Runs       []Run
+	Compare    []Run /* Selected runs */
+	Parameters []Parameter
+	Chart      template.HTML /* Equity of the selected runs against buy-and-hold */
+	CanAdmin   bool
+	Message    string
+	Theme      string /* UI theme of the logged in user */
+}
+
+// Save a backtest run and its equity, deriving the return, buy-and-hold return and maximum drawdown from equity
+func Save(
+	sessionData *types.Session,
+	backtest types.Backtest,
+	equity []types.BacktestEquity) (id int64, err error) {
+
+	backtest.ProfitPct, backtest.BuyHoldPct, backtest.MaxDrawdownPct = metrics(equity)
+
+	if backtest.CreatedTime == 0 {
+
+		backtest.CreatedTime = time.Now().UnixNano() / int64(time.Millisecond)
+
+	}
+
+	if id, err = mysql.SaveBacktest(sessionData, backtest); err != nil {
+
+		return 0, err
+
+	}
+
+	for _, point := range equity {
+
+		if err = mysql.SaveBacktestEquity(sessionData, id, point); err != nil {
+
+			return id, err
+
+		}
+
+	}
+
+	return id, nil
+
+}
+
+// LoadPage load the backtests page comparing the runs selected by the id values of query, up to 5 runs
+func LoadPage(
+	sessionData *types.Session,
+	query url.Values) (page Page) {
+
+	var backtests []types.Backtest
+	var err error
+
+	if backtests, err = mysql.GetBacktests(sessionData); err != nil {
+
+		page.Message = err.Error()
+		return page
+
+	}
+
+	selected := make(map[string]bool)
+	for _, id := range query["id"] {
+		selected[id] = true
+	}
+
+	for _, backtest := range backtests {
+
+		run := Run{
+			Backtest: backtest,
+			Period:   date(backtest.StartTime) + " - " + date(backtest.EndTime),
+			Created:  time.Unix((backtest.CreatedTime / 1000), 0).Local().Format("2006-01-02 15:04"),
+			Selected: selected[fmt.Sprint(backtest.ID)],
+		}
+
+		if run.Selected && len(page.Compare) < compareLimit {
+			page.Compare = append(page.Compare, run)
+		}
+
+		page.Runs = append(page.Runs, run)
+
+	}
+
+	if len(page.Compare) == 0 {
+
+		return page
+
+	}
+
+	names := make([]string, 0, len(page.Compare))
+	equity := make([][]types.BacktestEquity, 0, len(page.Compare))
+
+	for _, run := range page.Compare {
+
+		points, err := mysql.GetBacktestEquity(sessionData, run.ID)
+		if err != nil {
+
+			page.Message = err.Error()
+			return page
+
+		}
+
+		names = append(names, fmt.Sprintf("#%d %s", run.ID, run.Name))
+		equity = append(equity, points)
+
+	}
+
+	page.Parameters = parameters(page.Compare)
+	page.Chart = plotter.PlotBacktest(names, equity)
+
+	return page
+
+}
+
+/* Return the return and buy-and-hold return over the period and the maximum drawdown of equity, as percentages */
+func metrics(equity []types.BacktestEquity) (profitPct float64, buyHoldPct float64, maxDrawdownPct float64) {
+
+	var peak float64
+
+	if len(equity) == 0 {
+
+		return 0, 0, 0
+
+	}
+
+	first, last := equity[0], equity[len(equity)-1]
+
+	if first.Equity > 0 {
+		profitPct = (last.Equity/first.Equity - 1) * 100
+	}
+
+	if first.BuyHold > 0 {
+		buyHoldPct = (last.BuyHold/first.BuyHold - 1) * 100
+	}
+
+	for _, point := range equity {
+
+		peak = math.Max(peak, point.Equity)
+
+		if peak > 0 {
+			maxDrawdownPct = math.Max(maxDrawdownPct, (peak-point.Equity)/peak*100)
+		}
+
+	}
+
+	return functions.Round(profitPct, 2), functions.Round(buyHoldPct, 2), functions.Round(maxDrawdownPct, 2)
+
+}
+
+/* Return the parameters of runs sorted by name with the value of each run, missing parameters are empty */
+func parameters(runs []Run) (list []Parameter) {
+
+	values := make([]map[string]interface{}, len(runs))
+	names := make(map[string]bool)
+
+	for i, run := range runs {
+
+		_ = json.Unmarshal([]byte(run.Parameters), &values[i]) /* Invalid parameters are listed as empty */
+
+		for name := range values[i] {
+			names[name] = true
+		}
+
+	}
+
+	for name := range names {
+
+		parameter := Parameter{Name: name}
+
+		for i := range runs {
+
+			value := ""
+			if v, ok := values[i][name]; ok {
+				value = fmt.Sprint(v)
+			}
+
+			if i > 0 && value != parameter.Values[0] {
+				parameter.Differs = true
+			}
+
+			parameter.Values = append(parameter.Values, value)
+
+		}
+
+		list = append(list, parameter)
+
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+
+}
+
+/* Return the date of a time in milliseconds */
+func date(t int64) string {
+
+	return time.Unix((t / 1000), 0).Local().Format("2006-01-02")
+
+}
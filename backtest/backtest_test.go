@@ -0,0 +1,65 @@
+package backtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_metrics(t *testing.T) {
+	tests := []struct {
+		name            string
+		equity          []types.BacktestEquity
+		wantProfitPct   float64
+		wantBuyHoldPct  float64
+		wantMaxDrawdown float64
+	}{
+		{
+			name: "drawdown and recovery",
+			equity: []types.BacktestEquity{
+				{Time: 1, Equity: 1000, BuyHold: 1000},
+				{Time: 2, Equity: 1200, BuyHold: 950},
+				{Time: 3, Equity: 900, BuyHold: 900},
+				{Time: 4, Equity: 1100, BuyHold: 980},
+			},
+			wantProfitPct:   10,
+			wantBuyHoldPct:  -2,
+			wantMaxDrawdown: 25,
+		},
+		{
+			name:   "no equity",
+			equity: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profitPct, buyHoldPct, maxDrawdownPct := metrics(tt.equity)
+			if profitPct != tt.wantProfitPct || buyHoldPct != tt.wantBuyHoldPct || maxDrawdownPct != tt.wantMaxDrawdown {
+				t.Errorf("metrics() = %v, %v, %v, want %v, %v, %v", profitPct, buyHoldPct, maxDrawdownPct, tt.wantProfitPct, tt.wantBuyHoldPct, tt.wantMaxDrawdown)
+			}
+		})
+	}
+}
+
+func Test_parameters(t *testing.T) {
+	runs := []Run{
+		{Backtest: types.Backtest{Parameters: `{"buy_rsi7_entry":30,"profit":1.5,"symbol":"BTCUSDT"}`}},
+		{Backtest: types.Backtest{Parameters: `{"buy_rsi7_entry":25,"profit":1.5}`}},
+		{Backtest: types.Backtest{Parameters: `invalid`}},
+	}
+	want := []Parameter{
+		{Name: "buy_rsi7_entry", Values: []string{"30", "25", ""}, Differs: true},
+		{Name: "profit", Values: []string{"1.5", "1.5", ""}, Differs: true},
+		{Name: "symbol", Values: []string{"BTCUSDT", "", ""}, Differs: true},
+	}
+	if got := parameters(runs); !reflect.DeepEqual(got, want) {
+		t.Errorf("parameters() = %v, want %v", got, want)
+	}
+	want = []Parameter{
+		{Name: "profit", Values: []string{"1.5", "1.5"}, Differs: false},
+	}
+	if got := parameters([]Run{runs[1], runs[1]})[1:]; !reflect.DeepEqual(got, want) {
+		t.Errorf("parameters() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package calendar
+
+/* This package contains the functions responsible for loading high-impact economic events
+(i.e. CPI, FOMC) from a configurable JSON feed, used to suppress new buys around each event.
+The feed is expected in the format published by https://nfs.faireconomy.media/ff_calendar_thisweek.json */
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* feedEvent define an event entry in the calendar feed */
+type feedEvent struct {
+	Title   string `json:"title"`
+	Country string `json:"country"`
+	Date    string `json:"date"`
+	Impact  string `json:"impact"`
+}
+
+// Load retrieve high-impact events from configData.ConfigGlobal.EventFeedURL and store them in sessionData.Events
+func Load(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+	var response *http.Response
+	var feed []feedEvent
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if configData.ConfigGlobal.EventFeedURL == "" { /* Feed not configured */
+
+		return
+
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	if response, err = client.Get(configData.ConfigGlobal.EventFeedURL); err != nil {
+
+		return
+
+	}
+
+	defer response.Body.Close()
+
+	if err = json.NewDecoder(response.Body).Decode(&feed); err != nil {
+
+		return
+
+	}
+
+	sessionData.Events = parse(feed)
+
+}
+
+/* Parse feed entries and keep only high-impact events */
+func parse(feed []feedEvent) (events []types.Event) {
+
+	for _, entry := range feed {
+
+		if !strings.EqualFold(entry.Impact, "high") {
+
+			continue
+
+		}
+
+		date, err := time.Parse(time.RFC3339, entry.Date)
+		if err != nil {
+
+			continue
+
+		}
+
+		events = append(events, types.Event{
+			Title:   entry.Title,
+			Country: entry.Country,
+			Time:    date,
+		})
+
+	}
+
+	return events
+
+}
+
+// IsBlackout Check if t is within configData.BuyEventBlackout minutes before or after a high-impact event
+func IsBlackout(
+	configData *types.Config,
+	sessionData *types.Session,
+	t time.Time) (is bool, event types.Event) {
+
+	if configData.BuyEventBlackout == 0 { /* Blackout disabled */
+
+		return false, event
+
+	}
+
+	window := time.Duration(configData.BuyEventBlackout) * time.Minute
+
+	for _, entry := range sessionData.Events {
+
+		if t.After(entry.Time.Add(-window)) &&
+			t.Before(entry.Time.Add(window)) {
+
+			return true, entry
+
+		}
+
+	}
+
+	return false, event
+
+}
@@ -0,0 +1,86 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_parse(t *testing.T) {
+	type args struct {
+		feed []feedEvent
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "success",
+			args: args{
+				feed: []feedEvent{
+					{Title: "CPI m/m", Country: "USD", Date: "2021-11-10T08:30:00-05:00", Impact: "High"},
+					{Title: "Crude Oil Inventories", Country: "USD", Date: "2021-11-10T10:30:00-05:00", Impact: "Medium"},
+					{Title: "FOMC Statement", Country: "USD", Date: "invalid", Impact: "High"},
+				},
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parse(tt.args.feed); len(got) != tt.want {
+				t.Errorf("parse() = %v, want %v", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestIsBlackout(t *testing.T) {
+	event := time.Date(2021, 11, 10, 13, 30, 0, 0, time.UTC)
+	sessionData := &types.Session{
+		Events: []types.Event{{Title: "CPI m/m", Country: "USD", Time: event}},
+	}
+	type args struct {
+		configData *types.Config
+		t          time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "inside",
+			args: args{
+				configData: &types.Config{BuyEventBlackout: 30},
+				t:          event.Add(-10 * time.Minute),
+			},
+			want: true,
+		},
+		{
+			name: "outside",
+			args: args{
+				configData: &types.Config{BuyEventBlackout: 30},
+				t:          event.Add(45 * time.Minute),
+			},
+			want: false,
+		},
+		{
+			name: "disabled",
+			args: args{
+				configData: &types.Config{BuyEventBlackout: 0},
+				t:          event,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := IsBlackout(tt.args.configData, sessionData, tt.args.t); got != tt.want {
+				t.Errorf("IsBlackout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
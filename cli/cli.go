@@ -0,0 +1,500 @@
+package cli
+
+/* This package implements the command line subcommands operating the bot without the web UI, i.e. over SSH.
+cryptopump run, or no subcommand, starts the bot and the web UI with the usual flags. The other subcommands connect
+to the database with the same environment as the bot, print their result to the terminal and exit. Sales requested
+from the command line are queued in the thread command queue and executed by the running thread within seconds. */
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aleibovici/cryptopump/backtest"
+	"github.com/aleibovici/cryptopump/commands"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/labels"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/report"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// Run is the subcommand starting the bot and the web UI, the default without a subcommand
+const Run = "run"
+
+// Help is the subcommand printing the usage
+const Help = "help"
+
+/* Thread command source of the sales requested from the command line */
+const commandSource = "CLI"
+
+/* Command line errors */
+var (
+	ErrUnknownCommand = errors.New("Unknown command")
+	ErrOrderID        = errors.New("OrderID required, i.e. sell -order 123456")
+	ErrReport         = errors.New("Report required, i.e. export -report trades")
+)
+
+// Env struct define the configuration, database session and output of a subcommand
+type Env struct {
+	Config  *types.Config
+	Session *types.Session
+	Out     io.Writer
+}
+
+/* Subcommand of the command line */
+type command struct {
+	name    string /* Words of the subcommand, i.e. threads list */
+	args    string /* Arguments in the usage */
+	summary string
+	run     func(env Env, args []string) error /* Nil for run and help, handled by main */
+}
+
+/* Subcommands in usage order */
+var subcommands = []command{
+	{name: Run, args: "[-symbols BTCUSDT,ETHUSDT] [-debug 6060] [-grpc 9090]", summary: "Start the bot and the web UI (default)"},
+	{name: "status", summary: "Print the running threads and the profit", run: status},
+	{name: "threads list", args: "[-search term]", summary: "List the running threads, filtered by ThreadID, name or tag", run: listThreads},
+	{name: "sell", args: "-order OrderID", summary: "Sell an open transaction of a running thread", run: sell},
+	{name: "backtest", args: "[-id ID]...", summary: "List the backtest runs, or compare the runs selected by -id", run: compareBacktests},
+	{name: "export", args: "-report trades|threads|monthly [-format csv|pdf] [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-output file]", summary: "Export a report, to stdout without -output", run: exportReport},
+	{name: "migrate", args: "[-schema file] [-apply]", summary: "Print the schema changes of the database, apply them with -apply", run: migrate},
+	{name: Help, summary: "Print this usage"},
+}
+
+// Parse return the subcommand of the command line arguments, without the program name, and the arguments that
+// follow it. No arguments, or flags without a subcommand, run the bot as before subcommands.
+func Parse(args []string) (name string, rest []string, err error) {
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+
+		return Run, args, nil
+
+	}
+
+	for _, command := range subcommands {
+
+		words := strings.Fields(command.name)
+
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == command.name {
+
+			return command.name, args[len(words):], nil
+
+		}
+
+	}
+
+	return "", nil, fmt.Errorf("%w %s", ErrUnknownCommand, args[0])
+
+}
+
+// Usage print the subcommands and their arguments to out
+func Usage(out io.Writer) {
+
+	fmt.Fprintln(out, "Usage: cryptopump [command] [arguments]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	for _, command := range subcommands {
+
+		fmt.Fprintf(w, "  %s\t%s\n", strings.TrimSpace(command.name+" "+command.args), command.summary)
+
+	}
+
+	w.Flush()
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Run cryptopump [command] -h for the arguments of a command.")
+
+}
+
+// Execute run the subcommand name with its arguments. Run and help are handled by the caller.
+func Execute(
+	name string,
+	args []string,
+	env Env) error {
+
+	for _, command := range subcommands {
+
+		if command.name == name && command.run != nil {
+
+			if err := command.run(env, args); err != nil && !errors.Is(err, flag.ErrHelp) {
+
+				return err
+
+			}
+
+			return nil
+
+		}
+
+	}
+
+	return fmt.Errorf("%w %s", ErrUnknownCommand, name)
+
+}
+
+/* Print the running threads and the profit of all threads */
+func status(
+	env Env,
+	args []string) (err error) {
+
+	var sessions []types.SessionSummary
+	var profit, profitNet, profitPct float64
+
+	if err = parse(flag.NewFlagSet("status", flag.ContinueOnError), args, env.Out); err != nil {
+
+		return err
+
+	}
+
+	if sessions, err = mysql.GetSessions(env.Session); err != nil {
+
+		return err
+
+	}
+
+	if profit, profitNet, profitPct, err = mysql.GetProfit(env.Session); err != nil {
+
+		return err
+
+	}
+
+	faults := 0
+	funds := 0.0
+
+	for _, session := range sessions {
+
+		if session.Status {
+			faults++
+		}
+
+		funds += session.FiatFunds
+
+	}
+
+	fmt.Fprintf(env.Out, "Threads: %d running, %d in fault\n", len(sessions), faults)
+	fmt.Fprintf(env.Out, "Funds: %s\n", functions.Float64ToStr(funds, 2))
+	fmt.Fprintf(env.Out, "Profit: %s\n", functions.Float64ToStr(profit, 2))
+	fmt.Fprintf(env.Out, "Net Profit: %s\n", functions.Float64ToStr(profitNet, 2))
+	fmt.Fprintf(env.Out, "Avg. Transaction: %s%%\n", functions.Float64ToStr(profitPct, 2))
+
+	return nil
+
+}
+
+/* Print the running threads matching -search */
+func listThreads(
+	env Env,
+	args []string) (err error) {
+
+	var sessions []types.SessionSummary
+
+	set := flag.NewFlagSet("threads list", flag.ContinueOnError)
+	search := set.String("search", "", "ThreadID, session name or tag")
+
+	if err = parse(set, args, env.Out); err != nil {
+
+		return err
+
+	}
+
+	if sessions, err = mysql.GetSessions(env.Session); err != nil {
+
+		return err
+
+	}
+
+	w := tabwriter.NewWriter(env.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "THREADID\tNAME\tEXCHANGE\tFUNDS\tORDER DIFF\tSTATUS\tTAGS")
+
+	for _, session := range sessions {
+
+		if !labels.Match(types.SessionLabel{ThreadID: session.ThreadID, Name: session.Name, Tags: session.Tags}, *search) {
+
+			continue
+
+		}
+
+		state := "nominal"
+		if session.Status {
+			state = "fault"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s %s\t%s\t%s\t%s\n",
+			session.ThreadID,
+			session.Name,
+			session.Exchange,
+			session.FiatSymbol,
+			functions.Float64ToStr(session.FiatFunds, 2),
+			functions.Float64ToStr(session.DiffTotal, 2),
+			state,
+			strings.Join(session.Tags, ","))
+
+	}
+
+	return w.Flush()
+
+}
+
+/* Queue the sale of the open transaction -order for the running thread holding it */
+func sell(
+	env Env,
+	args []string) (err error) {
+
+	var threadID string
+	var sessions []types.SessionSummary
+
+	set := flag.NewFlagSet("sell", flag.ContinueOnError)
+	orderID := set.Int64("order", 0, "OrderID of the open transaction to sell")
+
+	if err = parse(set, args, env.Out); err != nil {
+
+		return err
+
+	}
+
+	if *orderID <= 0 {
+
+		return ErrOrderID
+
+	}
+
+	if threadID, err = mysql.GetThreadIDByOrderID(env.Session, *orderID); err != nil {
+
+		return err
+
+	}
+
+	if threadID == "" {
+
+		return fmt.Errorf("Order %d is not an open transaction", *orderID)
+
+	}
+
+	if sessions, err = mysql.GetSessions(env.Session); err != nil {
+
+		return err
+
+	}
+
+	for _, session := range sessions {
+
+		if session.ThreadID != threadID {
+
+			continue
+
+		}
+
+		/* Queued so the sale is confirmed above SellConfirmNotional */
+		if err = commands.Queue(env.Session, threadID, commands.Sell, *orderID, commandSource); err != nil {
+
+			return err
+
+		}
+
+		fmt.Fprintf(env.Out, "Selling %d @ %s\n", *orderID, threadID)
+
+		return nil
+
+	}
+
+	return fmt.Errorf("Thread %s not running", threadID)
+
+}
+
+/* List the backtest runs, or compare the metrics and parameters of the runs selected by -id */
+func compareBacktests(
+	env Env,
+	args []string) (err error) {
+
+	var ids list
+
+	set := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	set.Var(&ids, "id", "ID of a backtest run to compare, repeated up to 5 runs")
+
+	if err = parse(set, args, env.Out); err != nil {
+
+		return err
+
+	}
+
+	page := backtest.LoadPage(env.Session, url.Values{"id": ids})
+
+	if page.Message != "" {
+
+		return errors.New(page.Message)
+
+	}
+
+	runs := page.Runs
+	if len(ids) > 0 {
+		runs = page.Compare
+	}
+
+	w := tabwriter.NewWriter(env.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSYMBOL\tPERIOD\tTRADES\tRETURN\tBUY-AND-HOLD\tMAX DRAWDOWN\tWIN RATE\tCREATED")
+
+	for _, run := range runs {
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s%%\t%s%%\t%s%%\t%s%%\t%s\n",
+			run.ID,
+			run.Name,
+			run.Symbol,
+			run.Period,
+			run.Trades,
+			functions.Float64ToStr(run.ProfitPct, 2),
+			functions.Float64ToStr(run.BuyHoldPct, 2),
+			functions.Float64ToStr(run.MaxDrawdownPct, 2),
+			functions.Float64ToStr(run.WinRatePct, 2),
+			run.Created)
+
+	}
+
+	if len(page.Parameters) > 0 {
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "PARAMETER\tVALUES")
+
+		for _, parameter := range page.Parameters {
+
+			name := parameter.Name
+			if parameter.Differs {
+				name += " *"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(parameter.Values, "\t"))
+
+		}
+
+	}
+
+	return w.Flush()
+
+}
+
+/* Export a report to -output, or to out without -output */
+func exportReport(
+	env Env,
+	args []string) (err error) {
+
+	var request report.Request
+
+	set := flag.NewFlagSet("export", flag.ContinueOnError)
+	kind := set.String("report", "", "Report: trades, threads or monthly")
+	format := set.String("format", "csv", "Format: csv or pdf")
+	from := set.String("from", "", "First day YYYY-MM-DD, 30 days before -to when empty")
+	to := set.String("to", "", "Last day YYYY-MM-DD, today when empty")
+	output := set.String("output", "", "File written, the report file name when a directory")
+
+	if err = parse(set, args, env.Out); err != nil {
+
+		return err
+
+	}
+
+	if *kind == "" {
+
+		return ErrReport
+
+	}
+
+	if request, err = report.Parse(*kind, *format, *from, *to); err != nil {
+
+		return err
+
+	}
+
+	if *output == "" {
+
+		return report.Write(env.Out, env.Session, request)
+
+	}
+
+	path := *output
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = strings.TrimSuffix(path, string(os.PathSeparator)) + string(os.PathSeparator) + request.Filename()
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+
+		return err
+
+	}
+
+	if err = report.Write(file, env.Session, request); err != nil {
+
+		file.Close()
+		return err
+
+	}
+
+	if err = file.Close(); err != nil {
+
+		return err
+
+	}
+
+	fmt.Fprintf(env.Out, "Report written to %s\n", path)
+
+	return nil
+
+}
+
+/* Parse the arguments of a subcommand, printing its flags for -h. Positional arguments are not accepted. */
+func parse(
+	set *flag.FlagSet,
+	args []string,
+	out io.Writer) error {
+
+	set.SetOutput(io.Discard) /* Errors are returned to the caller */
+
+	if err := set.Parse(args); err != nil {
+
+		if errors.Is(err, flag.ErrHelp) {
+
+			fmt.Fprintf(out, "Usage of %s:\n", set.Name())
+			set.SetOutput(out)
+			set.PrintDefaults()
+
+		}
+
+		return err
+
+	}
+
+	if set.NArg() > 0 {
+
+		return fmt.Errorf("Unexpected argument %s", set.Arg(0))
+
+	}
+
+	return nil
+
+}
+
+/* Values of a repeated flag */
+type list []string
+
+/* Return the values of the flag, flag.Value */
+func (l *list) String() string {
+
+	return strings.Join(*l, ",")
+
+}
+
+/* Add a value of the flag, flag.Value */
+func (l *list) Set(value string) error {
+
+	*l = append(*l, value)
+	return nil
+
+}
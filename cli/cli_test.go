@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantRest []string
+		wantErr  error
+	}{
+		{name: "no arguments", args: nil, wantName: Run, wantRest: nil},
+		{name: "flags without a subcommand", args: []string{"-symbols", "BTCUSDT", "-resume", "c683ok5mk1u1120gnmmg"}, wantName: Run, wantRest: []string{"-symbols", "BTCUSDT", "-resume", "c683ok5mk1u1120gnmmg"}},
+		{name: "run", args: []string{"run", "-debug", "6060"}, wantName: Run, wantRest: []string{"-debug", "6060"}},
+		{name: "status", args: []string{"status"}, wantName: "status", wantRest: []string{}},
+		{name: "two words", args: []string{"threads", "list", "-search", "core"}, wantName: "threads list", wantRest: []string{"-search", "core"}},
+		{name: "sell", args: []string{"sell", "--order", "123456"}, wantName: "sell", wantRest: []string{"--order", "123456"}},
+		{name: "incomplete", args: []string{"threads"}, wantErr: ErrUnknownCommand},
+		{name: "unknown", args: []string{"buy"}, wantErr: ErrUnknownCommand},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotRest, err := Parse(tt.args)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotName != tt.wantName || !reflect.DeepEqual(gotRest, tt.wantRest) {
+				t.Errorf("Parse() = %v %v, want %v %v", gotName, gotRest, tt.wantName, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		wantOut string
+		wantErr bool
+	}{
+		{name: "help of a command", command: "sell", args: []string{"-h"}, wantOut: "-order", wantErr: false},
+		{name: "missing order", command: "sell", args: nil, wantErr: true},
+		{name: "unexpected argument", command: "status", args: []string{"all"}, wantErr: true},
+		{name: "unknown flag", command: "export", args: []string{"-kind", "trades"}, wantErr: true},
+		{name: "missing report", command: "export", args: nil, wantErr: true},
+		{name: "run", command: Run, args: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := Execute(tt.command, tt.args, Env{Out: out}); (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("Execute() out = %v, want %v", out.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+
+	out := &bytes.Buffer{}
+	Usage(out)
+
+	for _, command := range subcommands {
+
+		if !strings.Contains(out.String(), "  "+command.name) {
+			t.Errorf("Usage() missing command %v", command.name)
+		}
+
+	}
+
+}
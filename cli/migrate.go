@@ -0,0 +1,267 @@
+package cli
+
+/* Schema migration of the database to the dump of the release (mysql/cryptopump.sql, or
+mysql/cryptopump-mariadb.sql for MariaDB). The tables and columns of the dump missing in the database are added,
+and the stored procedures missing or whose body differs are created or replaced. Columns and procedures of the
+database missing in the dump are kept, and indexes, triggers and column types are not migrated. */
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aleibovici/cryptopump/mysql"
+)
+
+/* Schema dump of the MySQL flavour, the -schema default */
+const defaultSchema = "./mysql/cryptopump.sql"
+
+var (
+	tablePattern     = regexp.MustCompile("(?ms)^CREATE TABLE `(\\w+)` \\(\\n(.*?)\\n\\)[^\\n]*;$")
+	procedurePattern = regexp.MustCompile("(?ms)^CREATE DEFINER=\\S+ (PROCEDURE `(\\w+)`.*?END) ?;;?$") /* END ;; in the MySQL flavour, END; in the MariaDB flavour */
+	columnPattern    = regexp.MustCompile("^`(\\w+)` ")
+	beginPattern     = regexp.MustCompile(`\bBEGIN\b`)
+)
+
+/* Table of the schema dump */
+type table struct {
+	name    string
+	create  string      /* CREATE TABLE statement */
+	columns [][2]string /* Name and definition of each column, in column order */
+}
+
+/* Stored procedure of the schema dump */
+type procedure struct {
+	name   string
+	create string /* CREATE PROCEDURE statement, without DEFINER */
+}
+
+/* Schema dump */
+type schema struct {
+	tables     []table
+	procedures []procedure
+}
+
+/* Schema change, the description printed and the statements applying it */
+type change struct {
+	description string
+	statements  []string
+}
+
+/* Print the schema changes of the database to the -schema dump, applying them with -apply */
+func migrate(
+	env Env,
+	args []string) (err error) {
+
+	var dump []byte
+	var columns map[string][]string
+	var procedures map[string]string
+
+	set := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	file := set.String("schema", defaultSchema, "Schema dump, ./mysql/cryptopump-mariadb.sql for MariaDB")
+	apply := set.Bool("apply", false, "Apply the schema changes, printed only without -apply")
+
+	if err = parse(set, args, env.Out); err != nil {
+
+		return err
+
+	}
+
+	if dump, err = os.ReadFile(*file); err != nil {
+
+		return err
+
+	}
+
+	if columns, err = mysql.GetSchemaColumns(env.Session); err != nil {
+
+		return err
+
+	}
+
+	if procedures, err = mysql.GetSchemaProcedures(env.Session); err != nil {
+
+		return err
+
+	}
+
+	changes := plan(parseSchema(string(dump)), columns, procedures)
+
+	if len(changes) == 0 {
+
+		fmt.Fprintln(env.Out, "Schema up to date")
+		return nil
+
+	}
+
+	for _, change := range changes {
+
+		fmt.Fprintln(env.Out, change.description)
+
+		if !*apply {
+
+			continue
+
+		}
+
+		for _, statement := range change.statements {
+
+			if err = mysql.ExecSchema(env.Session, statement); err != nil {
+
+				return fmt.Errorf("%s: %w", change.description, err)
+
+			}
+
+		}
+
+	}
+
+	if !*apply {
+
+		fmt.Fprintf(env.Out, "%d schema changes, run migrate -apply to apply them\n", len(changes))
+		return nil
+
+	}
+
+	fmt.Fprintf(env.Out, "%d schema changes applied\n", len(changes))
+
+	return nil
+
+}
+
+/* Return the tables and stored procedures of a schema dump */
+func parseSchema(dump string) (s schema) {
+
+	for _, match := range tablePattern.FindAllStringSubmatch(dump, -1) {
+
+		t := table{name: match[1], create: strings.TrimSuffix(match[0], ";")}
+
+		for _, line := range strings.Split(match[2], "\n") {
+
+			line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+
+			if column := columnPattern.FindStringSubmatch(line); column != nil {
+
+				t.columns = append(t.columns, [2]string{column[1], line})
+
+			}
+
+		}
+
+		s.tables = append(s.tables, t)
+
+	}
+
+	for _, match := range procedurePattern.FindAllStringSubmatch(dump, -1) {
+
+		s.procedures = append(s.procedures, procedure{name: match[2], create: "CREATE " + match[1]})
+
+	}
+
+	return s
+
+}
+
+/* Return the changes of the database tables columns and stored procedure bodies to the schema dump */
+func plan(
+	dump schema,
+	columns map[string][]string,
+	procedures map[string]string) (changes []change) {
+
+	for _, t := range dump.tables {
+
+		existing, ok := columns[t.name]
+
+		if !ok {
+
+			changes = append(changes, change{description: "Create table " + t.name, statements: []string{t.create}})
+			continue
+
+		}
+
+		for i, column := range t.columns {
+
+			if contains(existing, column[0]) {
+
+				continue
+
+			}
+
+			position := "FIRST"
+			if i > 0 {
+				position = "AFTER `" + t.columns[i-1][0] + "`"
+			}
+
+			changes = append(changes, change{
+				description: "Add column " + t.name + "." + column[0],
+				statements:  []string{"ALTER TABLE `" + t.name + "` ADD COLUMN " + column[1] + " " + position},
+			})
+
+		}
+
+	}
+
+	for _, p := range dump.procedures {
+
+		body, ok := procedures[p.name]
+
+		switch {
+		case !ok:
+
+			changes = append(changes, change{description: "Create procedure " + p.name, statements: []string{p.create}})
+
+		case body != "" && normalize(body) != normalize(procedureBody(p.create)): /* Empty body without privileges on the procedure */
+
+			changes = append(changes, change{
+				description: "Replace procedure " + p.name,
+				statements:  []string{"DROP PROCEDURE IF EXISTS `" + p.name + "`", p.create},
+			})
+
+		}
+
+	}
+
+	return changes
+
+}
+
+/* Return the body (BEGIN ... END) of a CREATE PROCEDURE statement */
+func procedureBody(create string) string {
+
+	if location := beginPattern.FindStringIndex(create); location != nil {
+
+		return create[location[0]:]
+
+	}
+
+	return create
+
+}
+
+/* Return s with whitespace runs as one space, the dump flavours differ in line breaks */
+func normalize(s string) string {
+
+	return strings.Join(strings.Fields(s), " ")
+
+}
+
+/* Return true when list contains name, case insensitive as column names */
+func contains(
+	list []string,
+	name string) bool {
+
+	for _, item := range list {
+
+		if strings.EqualFold(item, name) {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
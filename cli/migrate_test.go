@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+/* Excerpt of the MySQL flavour of the schema dump */
+const mysqlDump = "--\n" +
+	"-- Table structure for table `symbollist`\n" +
+	"--\n" +
+	"\n" +
+	"DROP TABLE IF EXISTS `symbollist`;\n" +
+	"CREATE TABLE `symbollist` (\n" +
+	"  `Symbol` varchar(45) NOT NULL,\n" +
+	"  `List` varchar(45) NOT NULL DEFAULT 'default',\n" +
+	"  PRIMARY KEY (`Symbol`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;\n" +
+	"/*!40101 SET character_set_client = @saved_cs_client */;\n" +
+	"DELIMITER ;;\n" +
+	"CREATE DEFINER=`root`@`%` PROCEDURE `GetSymbolList`()\n" +
+	"BEGIN\n" +
+	"SELECT `symbollist`.`Symbol`, `symbollist`.`List`\n" +
+	"FROM `cryptopump`.`symbollist`;\n" +
+	"END ;;\n" +
+	"DELIMITER ;\n"
+
+/* Excerpt of the MariaDB flavour of the schema dump */
+const mariadbDump = "CREATE TABLE `symbollist` (\n" +
+	"  `Symbol` varchar(45) NOT NULL,\n" +
+	"  `List` varchar(45) NOT NULL DEFAULT 'default',\n" +
+	"  PRIMARY KEY (`Symbol`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+	"/*!40101 SET character_set_client = @saved_cs_client */;\n" +
+	"\n" +
+	"CREATE DEFINER=`root`@`%` TRIGGER `audit_before_update` BEFORE UPDATE ON `audit` FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit table is append-only';\n" +
+	"\n" +
+	"CREATE DEFINER=`root`@`%` PROCEDURE `GetSymbolList`() BEGIN SELECT `symbollist`.`Symbol`, `symbollist`.`List` FROM `cryptopump`.`symbollist`; END;\n" +
+	"\n"
+
+func Test_parseSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		dump string
+		want schema
+	}{
+		{
+			name: "mysql",
+			dump: mysqlDump,
+			want: schema{
+				tables: []table{{
+					name:    "symbollist",
+					create:  "CREATE TABLE `symbollist` (\n  `Symbol` varchar(45) NOT NULL,\n  `List` varchar(45) NOT NULL DEFAULT 'default',\n  PRIMARY KEY (`Symbol`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci",
+					columns: [][2]string{{"Symbol", "`Symbol` varchar(45) NOT NULL"}, {"List", "`List` varchar(45) NOT NULL DEFAULT 'default'"}},
+				}},
+				procedures: []procedure{{
+					name:   "GetSymbolList",
+					create: "CREATE PROCEDURE `GetSymbolList`()\nBEGIN\nSELECT `symbollist`.`Symbol`, `symbollist`.`List`\nFROM `cryptopump`.`symbollist`;\nEND",
+				}},
+			},
+		},
+		{
+			name: "mariadb",
+			dump: mariadbDump,
+			want: schema{
+				tables: []table{{
+					name:    "symbollist",
+					create:  "CREATE TABLE `symbollist` (\n  `Symbol` varchar(45) NOT NULL,\n  `List` varchar(45) NOT NULL DEFAULT 'default',\n  PRIMARY KEY (`Symbol`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+					columns: [][2]string{{"Symbol", "`Symbol` varchar(45) NOT NULL"}, {"List", "`List` varchar(45) NOT NULL DEFAULT 'default'"}},
+				}},
+				procedures: []procedure{{
+					name:   "GetSymbolList",
+					create: "CREATE PROCEDURE `GetSymbolList`() BEGIN SELECT `symbollist`.`Symbol`, `symbollist`.`List` FROM `cryptopump`.`symbollist`; END",
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSchema(tt.dump); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSchema() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_plan(t *testing.T) {
+
+	dump := parseSchema(mysqlDump)
+	body := "BEGIN\nSELECT `symbollist`.`Symbol`, `symbollist`.`List`\nFROM `cryptopump`.`symbollist`;\nEND"
+
+	tests := []struct {
+		name       string
+		columns    map[string][]string
+		procedures map[string]string
+		want       []change
+	}{
+		{
+			name:       "up to date",
+			columns:    map[string][]string{"symbollist": {"Symbol", "List"}},
+			procedures: map[string]string{"GetSymbolList": body},
+			want:       nil,
+		},
+		{
+			name:       "up to date with the other flavour",
+			columns:    map[string][]string{"symbollist": {"symbol", "list"}},
+			procedures: map[string]string{"GetSymbolList": "BEGIN SELECT `symbollist`.`Symbol`, `symbollist`.`List` FROM `cryptopump`.`symbollist`; END"},
+			want:       nil,
+		},
+		{
+			name:       "missing table and procedure",
+			columns:    map[string][]string{},
+			procedures: map[string]string{},
+			want: []change{
+				{description: "Create table symbollist", statements: []string{dump.tables[0].create}},
+				{description: "Create procedure GetSymbolList", statements: []string{dump.procedures[0].create}},
+			},
+		},
+		{
+			name:       "missing column and changed procedure",
+			columns:    map[string][]string{"symbollist": {"Symbol"}},
+			procedures: map[string]string{"GetSymbolList": "BEGIN\nSELECT `symbollist`.`Symbol`\nFROM `cryptopump`.`symbollist`;\nEND"},
+			want: []change{
+				{description: "Add column symbollist.List", statements: []string{"ALTER TABLE `symbollist` ADD COLUMN `List` varchar(45) NOT NULL DEFAULT 'default' AFTER `Symbol`"}},
+				{description: "Replace procedure GetSymbolList", statements: []string{"DROP PROCEDURE IF EXISTS `GetSymbolList`", dump.procedures[0].create}},
+			},
+		},
+		{
+			name:       "procedure without privileges",
+			columns:    map[string][]string{"symbollist": {"List", "Symbol"}},
+			procedures: map[string]string{"GetSymbolList": ""},
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plan(dump, tt.columns, tt.procedures); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("plan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
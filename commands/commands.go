@@ -0,0 +1,170 @@
+package commands
+
+/* This package implements the thread command queue. Commands received by one thread for another, such as the
+Telegram commands received by the Master Node, are saved in the threadcommand table and executed by the thread
+they are queued for on its next check, every 5 seconds. */
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aleibovici/cryptopump/approval"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Thread commands */
+const (
+	Pause   = "pause"   /* Pause new buys */
+	Resume  = "resume"  /* Resume new buys */
+	Sell    = "sell"    /* Sell an open thread transaction */
+	Stop    = "stop"    /* Terminate the thread */
+	Approve = "approve" /* Execute a manual sale pending confirmation */
+	Reject  = "reject"  /* Cancel a manual sale pending confirmation */
+)
+
+/* Queue errors */
+var (
+	ErrUnknownCommand = errors.New("Unknown thread command")
+	ErrNoThread       = errors.New("ThreadID required")
+)
+
+// Queue a command for threadID, orderID is the open thread transaction to sell for the sell command and the
+// pending action ID for the approve and reject commands
+func Queue(
+	sessionData *types.Session,
+	threadID string,
+	command string,
+	orderID int64,
+	source string) (err error) {
+
+	switch command {
+	case Pause, Resume, Sell, Stop, Approve, Reject:
+	default:
+		return ErrUnknownCommand
+	}
+
+	if threadID == "" {
+
+		return ErrNoThread
+
+	}
+
+	return mysql.SaveThreadCommand(sessionData, types.ThreadCommand{
+		ThreadID:    threadID,
+		Command:     command,
+		OrderID:     orderID,
+		Source:      source,
+		CreatedTime: time.Now().UnixNano() / int64(time.Millisecond),
+	})
+
+}
+
+// Load execute the commands queued for the thread of sessionData in the order they were queued
+func Load(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) {
+
+	var commands []types.ThreadCommand
+	var err error
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if sessionData.ThreadID == "" { /* No thread running in this session */
+
+		return
+
+	}
+
+	if commands, err = mysql.GetThreadCommands(sessionData); err != nil {
+
+		return
+
+	}
+
+	for _, command := range commands {
+
+		/* Delete before executing so a failing or terminating command is not repeated */
+		if err = mysql.DeleteThreadCommand(sessionData, command.ID); err != nil {
+
+			return
+
+		}
+
+		if err = execute(configData, marketData, sessionData, command); err != nil {
+
+			return
+
+		}
+
+	}
+
+}
+
+/* Execute a thread command */
+func execute(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	command types.ThreadCommand) (err error) {
+
+	switch command.Command {
+	case Pause, Resume:
+
+		return threads.Thread{}.Pause(sessionData, command.Command == Pause, command.Source)
+
+	case Sell:
+
+		if _, err = approval.ForceSell(configData, marketData, sessionData, command.OrderID); err != nil { /* Force sell, or request confirmation above SellConfirmNotional */
+
+			return err
+
+		}
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   marketData,
+			Session:  sessionData,
+			Order:    &types.Order{OrderID: command.OrderID},
+			Message:  fmt.Sprintf("Manual sale requested by %s", command.Source),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	case Approve:
+
+		return approval.Approve(configData, sessionData, command.OrderID) /* OrderID holds the pending action ID */
+
+	case Reject:
+
+		return approval.Reject(configData, sessionData, command.OrderID) /* OrderID holds the pending action ID */
+
+	case Stop:
+
+		threads.Thread{}.Terminate(sessionData, "Thread stopped by "+command.Source) /* Terminate ThreadID */
+
+	default:
+
+		return ErrUnknownCommand
+
+	}
+
+	return nil
+
+}
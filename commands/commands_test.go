@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestQueue(t *testing.T) {
+	tests := []struct {
+		name     string
+		threadID string
+		command  string
+		wantErr  error
+	}{
+		{
+			name:     "unknown command",
+			threadID: "c683ok5mk1u1120gnmmg",
+			command:  "buy",
+			wantErr:  ErrUnknownCommand,
+		},
+		{
+			name:    "no thread",
+			command: Pause,
+			wantErr: ErrNoThread,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Queue(&types.Session{}, tt.threadID, tt.command, 0, "Telegram"); err != tt.wantErr {
+				t.Errorf("Queue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package cycle
+
+/* This package implements the trade cycle state machine of a thread. A cycle moves from IDLE to BUY_PLACED when a
+buy order is sent, to POSITION_OPEN once the buy is filled, to SELL_PLACED when a sell order is sent and to CLOSED
+once the sale is filled, then back to POSITION_OPEN or IDLE by the open transactions left. A manual sale on a thread
+without open transactions moves from IDLE to SELL_PLACED. Orders rejected by the
+exchange move the cycle to BUY_FAILED or SELL_FAILED. The state and the order in flight are persisted in the Session
+table on every transition, so a thread resuming after a crash resolves the order it had in flight with the exchange
+(exchange.RecoverCycle) instead of guessing from the open transactions. */
+
+import (
+	"errors"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Trade cycle states */
+const (
+	Idle         = "IDLE"          /* No open transactions and no order in flight */
+	BuyPlaced    = "BUY_PLACED"    /* Buy order sent, waiting for the fill */
+	PositionOpen = "POSITION_OPEN" /* Open transactions and no order in flight */
+	SellPlaced   = "SELL_PLACED"   /* Sell order sent, waiting for the fill */
+	Closed       = "CLOSED"        /* Sale filled, settled to POSITION_OPEN or IDLE */
+	BuyFailed    = "BUY_FAILED"    /* Buy order rejected, or its outcome unknown when an order is recorded */
+	SellFailed   = "SELL_FAILED"   /* Sell order rejected, or its outcome unknown when an order is recorded */
+)
+
+// ErrTransition is returned for a transition not allowed by the trade cycle state machine
+var ErrTransition = errors.New("Invalid trade cycle transition")
+
+/* States reachable from each state, a state can always be set again to update the order in flight */
+var transitions = map[string][]string{
+	Idle:         {BuyPlaced, SellPlaced, PositionOpen}, /* Manual sales of holdings not tracked by a transaction */
+	BuyPlaced:    {PositionOpen, Idle, BuyFailed},
+	PositionOpen: {BuyPlaced, SellPlaced, Idle},
+	SellPlaced:   {Closed, PositionOpen, SellFailed},
+	Closed:       {Idle, PositionOpen},
+	BuyFailed:    {BuyPlaced, PositionOpen, Idle},
+	SellFailed:   {SellPlaced, Closed, PositionOpen, Idle},
+}
+
+// States list the trade cycle states in cycle order
+var States = []string{Idle, BuyPlaced, PositionOpen, SellPlaced, Closed, BuyFailed, SellFailed}
+
+// State return the trade cycle state of sessionData, IDLE before the first transition
+func State(sessionData *types.Session) string {
+
+	if sessionData.CycleState == "" {
+
+		return Idle
+
+	}
+
+	return sessionData.CycleState
+
+}
+
+// Valid return true when the trade cycle state machine allows the transition from state from to state to
+func Valid(
+	from string,
+	to string) bool {
+
+	if from == to {
+
+		_, ok := transitions[to]
+
+		return ok
+
+	}
+
+	for _, state := range transitions[from] {
+
+		if state == to {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+// Transition move the trade cycle of sessionData to state with orderID in flight, selling the open transaction
+// orderIDSource, and persist it in the Session table
+func Transition(
+	sessionData *types.Session,
+	state string,
+	orderID int64,
+	orderIDSource int64) (err error) {
+
+	from := State(sessionData)
+
+	if !Valid(from, state) {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{OrderID: orderID},
+			Message:  "Trade cycle " + from + " to " + state + " - " + ErrTransition.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return ErrTransition
+
+	}
+
+	sessionData.CycleState = state
+	sessionData.CycleOrderID = orderID
+	sessionData.CycleOrderIDSource = orderIDSource
+
+	if err = mysql.UpdateSessionCycle(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if from != state {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{OrderID: orderID},
+			Message:  "Trade cycle " + from + " to " + state,
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	return nil
+
+}
+
+// Resolve update sessionData.ThreadCount and move the trade cycle to POSITION_OPEN or IDLE by the open transactions
+// of the thread, once the outcome of the order in flight is known
+func Resolve(sessionData *types.Session) (err error) {
+
+	if sessionData.ThreadCount, err = mysql.GetThreadTransactionCount(sessionData); err != nil {
+
+		return err
+
+	}
+
+	return settle(sessionData)
+
+}
+
+// Settle update sessionData.ThreadCount and resolve the trade cycle unless an order is in flight or its outcome is
+// unknown. Run after each trade and after transactions opened or closed outside the bot (manual orders, adoption).
+func Settle(sessionData *types.Session) (err error) {
+
+	if sessionData.ThreadCount, err = mysql.GetThreadTransactionCount(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if InFlight(sessionData) || Unresolved(sessionData) {
+
+		return nil
+
+	}
+
+	return settle(sessionData)
+
+}
+
+// Restore load the trade cycle state and order in flight of sessionData from the Session table
+func Restore(sessionData *types.Session) (err error) {
+
+	if sessionData.CycleState, sessionData.CycleOrderID, sessionData.CycleOrderIDSource, err = mysql.GetSessionCycle(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if _, ok := transitions[sessionData.CycleState]; !ok { /* Sessions saved before the state machine */
+
+		sessionData.CycleState = Idle
+
+	}
+
+	return nil
+
+}
+
+// InFlight return true when an order of the trade cycle was sent and its fill is pending
+func InFlight(sessionData *types.Session) bool {
+
+	return State(sessionData) == BuyPlaced || State(sessionData) == SellPlaced
+
+}
+
+// Settling return true when the trade cycle is CLOSED or failed without an order, to be settled before the next trade
+func Settling(sessionData *types.Session) bool {
+
+	switch State(sessionData) {
+	case Closed:
+
+		return true
+
+	case BuyFailed, SellFailed:
+
+		return sessionData.CycleOrderID == 0
+
+	}
+
+	return false
+
+}
+
+// Unresolved return true when an order of the trade cycle is recorded in a failed state, its outcome must be resolved
+// with the exchange before the next trade
+func Unresolved(sessionData *types.Session) bool {
+
+	return (State(sessionData) == BuyFailed || State(sessionData) == SellFailed) && sessionData.CycleOrderID != 0
+
+}
+
+/* Move the trade cycle to the state of the open transactions when the state or the order in flight differ */
+func settle(sessionData *types.Session) error {
+
+	if state := settled(sessionData.ThreadCount); state != State(sessionData) || sessionData.CycleOrderID != 0 {
+
+		return Transition(sessionData, state, 0, 0)
+
+	}
+
+	return nil
+
+}
+
+/* Return the state of a trade cycle without order in flight by the open transactions of the thread */
+func settled(threadCount int) string {
+
+	if threadCount > 0 {
+
+		return PositionOpen
+
+	}
+
+	return Idle
+
+}
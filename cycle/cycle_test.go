@@ -0,0 +1,152 @@
+package cycle
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestValid(t *testing.T) {
+	type args struct {
+		from string
+		to   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{name: "buy", args: args{from: Idle, to: BuyPlaced}, want: true},
+		{name: "buy filled", args: args{from: BuyPlaced, to: PositionOpen}, want: true},
+		{name: "sell", args: args{from: PositionOpen, to: SellPlaced}, want: true},
+		{name: "sell filled", args: args{from: SellPlaced, to: Closed}, want: true},
+		{name: "settled", args: args{from: Closed, to: Idle}, want: true},
+		{name: "order acknowledged", args: args{from: SellPlaced, to: SellPlaced}, want: true},
+		{name: "sell failed", args: args{from: SellPlaced, to: SellFailed}, want: true},
+		{name: "manual sell without position", args: args{from: Idle, to: SellPlaced}, want: true},
+		{name: "closed without position", args: args{from: Idle, to: Closed}, want: false},
+		{name: "buy while selling", args: args{from: SellPlaced, to: BuyPlaced}, want: false},
+		{name: "closed without sale", args: args{from: PositionOpen, to: Closed}, want: false},
+		{name: "unknown state", args: args{from: Idle, to: "FILLED"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Valid(tt.args.from, tt.args.to); got != tt.want {
+				t.Errorf("Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransition(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Db: db} /* Thread without open transactions */
+
+	for _, step := range []struct {
+		state   string
+		orderID int64
+	}{
+		{state: SellPlaced, orderID: 0},          /* Manual sell sent */
+		{state: SellPlaced, orderID: 2217134963}, /* Acknowledged by the exchange */
+		{state: Closed, orderID: 0},              /* Filled */
+	} {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionCycle(?,?,?,?)")).
+			WithArgs("c683ok5mk1u1120gnmmg", step.state, step.orderID, 0).
+			WillReturnRows(sqlmock.NewRows([]string{""}))
+
+		if err := Transition(sessionData, step.state, step.orderID, 0); err != nil {
+			t.Errorf("Transition() to %v error = %v", step.state, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Transition() %v", err)
+	}
+
+	if err := Transition(sessionData, BuyFailed, 0, 0); err != ErrTransition { /* Not persisted */
+		t.Errorf("Transition() error = %v, wantErr %v", err, ErrTransition)
+	}
+}
+
+func TestPending(t *testing.T) {
+	tests := []struct {
+		name           string
+		sessionData    *types.Session
+		wantState      string
+		wantInFlight   bool
+		wantSettling   bool
+		wantUnresolved bool
+	}{
+		{
+			name:        "new thread",
+			sessionData: &types.Session{},
+			wantState:   Idle,
+		},
+		{
+			name:         "sell in flight",
+			sessionData:  &types.Session{CycleState: SellPlaced, CycleOrderID: 2217134963, CycleOrderIDSource: 2217098215},
+			wantState:    SellPlaced,
+			wantInFlight: true,
+		},
+		{
+			name:         "closed",
+			sessionData:  &types.Session{CycleState: Closed},
+			wantState:    Closed,
+			wantSettling: true,
+		},
+		{
+			name:         "buy rejected",
+			sessionData:  &types.Session{CycleState: BuyFailed},
+			wantState:    BuyFailed,
+			wantSettling: true,
+		},
+		{
+			name:           "sell outcome unknown",
+			sessionData:    &types.Session{CycleState: SellFailed, CycleOrderID: 2217134963},
+			wantState:      SellFailed,
+			wantUnresolved: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := State(tt.sessionData); got != tt.wantState {
+				t.Errorf("State() = %v, want %v", got, tt.wantState)
+			}
+			if got := InFlight(tt.sessionData); got != tt.wantInFlight {
+				t.Errorf("InFlight() = %v, want %v", got, tt.wantInFlight)
+			}
+			if got := Settling(tt.sessionData); got != tt.wantSettling {
+				t.Errorf("Settling() = %v, want %v", got, tt.wantSettling)
+			}
+			if got := Unresolved(tt.sessionData); got != tt.wantUnresolved {
+				t.Errorf("Unresolved() = %v, want %v", got, tt.wantUnresolved)
+			}
+		})
+	}
+}
+
+func Test_settled(t *testing.T) {
+	tests := []struct {
+		name        string
+		threadCount int
+		want        string
+	}{
+		{name: "no transactions", threadCount: 0, want: Idle},
+		{name: "open transactions", threadCount: 3, want: PositionOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settled(tt.threadCount); got != tt.want {
+				t.Errorf("settled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package diagnostics
+
+/* This package implements the opt-in debug server of the process, started with the -debug command line flag. It
+serves the net/http/pprof profiles at /debug/pprof/ and the runtime stats (goroutine count, heap and GC pauses) at
+/debug/stats on a listener separate from the webui, bound to localhost unless a host is given, so the memory growth
+of long runs can be diagnosed with go tool pprof without exposing the profiles. */
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// StatsPath is the URI path of the runtime stats
+const StatsPath = "/debug/stats"
+
+const (
+	defaultHost = "127.0.0.1" /* Host of an address without host */
+	pausesMax   = 16          /* Recent GC pauses in the runtime stats */
+)
+
+var started = time.Now()
+
+// Stats struct define the runtime stats of the process
+type Stats struct {
+	UptimeSeconds  float64   `json:"uptimeSeconds"`
+	Goroutines     int       `json:"goroutines"`
+	HeapAlloc      uint64    `json:"heapAlloc"`    /* Bytes of allocated heap objects */
+	HeapInuse      uint64    `json:"heapInuse"`    /* Bytes in in-use heap spans */
+	HeapObjects    uint64    `json:"heapObjects"`  /* Allocated heap objects */
+	HeapReleased   uint64    `json:"heapReleased"` /* Bytes of heap returned to the OS */
+	Sys            uint64    `json:"sys"`          /* Bytes obtained from the OS */
+	TotalAlloc     uint64    `json:"totalAlloc"`   /* Cumulative bytes allocated */
+	NextGC         uint64    `json:"nextGC"`       /* Heap size of the next GC */
+	NumGC          uint32    `json:"numGC"`
+	PauseTotalMs   float64   `json:"pauseTotalMs"`
+	PausesMs       []float64 `json:"pausesMs"` /* Most recent GC pauses first */
+	GCCPUFraction  float64   `json:"gcCpuFraction"`
+	LastGCUnixNano uint64    `json:"lastGCUnixNano"`
+}
+
+// Address return the listen address of the -debug flag value, empty when the debug server is disabled. A port alone
+// (6060 or :6060) is bound to localhost.
+func Address(value string) string {
+
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+
+		return ""
+
+	}
+
+	host, port, err := net.SplitHostPort(value)
+	if err != nil { /* Port without colon */
+
+		host, port = "", value
+
+	}
+
+	if host == "" {
+		host = defaultHost
+	}
+
+	return net.JoinHostPort(host, port)
+
+}
+
+// Start the debug server at address in the background, logging when it can't listen
+func Start(
+	configData *types.Config,
+	sessionData *types.Session,
+	address string) {
+
+	server := &http.Server{Addr: address, Handler: Mux()}
+
+	go func() {
+
+		if err := server.ListenAndServe(); err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+		}
+
+	}()
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Debug server started at http://" + address + "/debug/pprof/",
+		LogLevel: "InfoLevel",
+	}.Do()
+
+}
+
+// Mux return the handler of the debug server, the pprof profiles and the runtime stats
+func Mux() *http.ServeMux {
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index) /* heap, goroutine, allocs, block, mutex and threadcreate profiles */
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc(StatsPath, stats)
+
+	return mux
+
+}
+
+/* Serve the runtime stats */
+func stats(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	_ = json.NewEncoder(w).Encode(Read(time.Now()))
+
+}
+
+// Read return the runtime stats of the process at now
+func Read(now time.Time) Stats {
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	stats := Stats{
+		UptimeSeconds:  now.Sub(started).Seconds(),
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAlloc:      memStats.HeapAlloc,
+		HeapInuse:      memStats.HeapInuse,
+		HeapObjects:    memStats.HeapObjects,
+		HeapReleased:   memStats.HeapReleased,
+		Sys:            memStats.Sys,
+		TotalAlloc:     memStats.TotalAlloc,
+		NextGC:         memStats.NextGC,
+		NumGC:          memStats.NumGC,
+		PauseTotalMs:   milliseconds(memStats.PauseTotalNs),
+		PausesMs:       []float64{},
+		GCCPUFraction:  memStats.GCCPUFraction,
+		LastGCUnixNano: memStats.LastGC,
+	}
+
+	/* PauseNs is a circular buffer, the most recent pause is at (NumGC+255)%256 */
+	for i := uint32(0); i < memStats.NumGC && i < pausesMax; i++ {
+
+		stats.PausesMs = append(stats.PausesMs, milliseconds(memStats.PauseNs[(memStats.NumGC-1-i)%uint32(len(memStats.PauseNs))]))
+
+	}
+
+	return stats
+
+}
+
+/* Return nanoseconds in milliseconds */
+func milliseconds(nanoseconds uint64) float64 {
+
+	return float64(nanoseconds) / float64(time.Millisecond)
+
+}
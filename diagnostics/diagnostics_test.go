@@ -0,0 +1,72 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestAddress(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "disabled", value: "", want: ""},
+		{name: "port", value: "6060", want: "127.0.0.1:6060"},
+		{name: "colon port", value: ":6060", want: "127.0.0.1:6060"},
+		{name: "host", value: "0.0.0.0:6060", want: "0.0.0.0:6060"},
+		{name: "localhost", value: " localhost:6061 ", want: "localhost:6061"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Address(tt.value); got != tt.want {
+				t.Errorf("Address() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestRead(t *testing.T) {
+
+	runtime.GC()
+
+	got := Read(time.Now())
+
+	if got.Goroutines < 1 {
+		t.Errorf("Read() Goroutines = %v, want > 0", got.Goroutines)
+	}
+
+	if got.NumGC < 1 || len(got.PausesMs) < 1 || len(got.PausesMs) > pausesMax {
+		t.Errorf("Read() NumGC = %v, PausesMs = %v", got.NumGC, got.PausesMs)
+	}
+
+	if got.HeapAlloc == 0 || got.Sys == 0 {
+		t.Errorf("Read() HeapAlloc = %v, Sys = %v", got.HeapAlloc, got.Sys)
+	}
+
+}
+
+func TestMux(t *testing.T) {
+
+	w := httptest.NewRecorder()
+	Mux().ServeHTTP(w, httptest.NewRequest("GET", StatsPath, nil))
+
+	var stats Stats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil || w.Code != 200 {
+		t.Fatalf("stats = %v, %v", w.Code, err)
+	}
+
+	w = httptest.NewRecorder()
+	Mux().ServeHTTP(w, httptest.NewRequest("GET", "/debug/pprof/", nil))
+
+	if w.Code != 200 {
+		t.Errorf("pprof index = %v, want 200", w.Code)
+	}
+
+}
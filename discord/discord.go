@@ -0,0 +1,203 @@
+package discord
+
+/* This package implements the Discord notification channel. The order, profit and error notifications sent via
+Telegram are posted to a Discord webhook or, with a bot token and channel ID, to a Discord channel through the bot
+API. Each event type is enabled in the global configuration (DiscordEvents). Messages are posted in the background
+so notifications never delay trading. */
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/outbox"
+	"github.com/aleibovici/cryptopump/throttle"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Discord event types */
+const (
+	EventOrder  = "order"  /* Filled BUY and SELL orders */
+	EventProfit = "profit" /* Realized profit of a sale */
+	EventError  = "error"  /* System faults and risk limits */
+)
+
+// Events list the event types that can be enabled for Discord
+var Events = []string{EventOrder, EventProfit, EventError}
+
+const maxLength = 2000 /* Discord message content limit */
+
+var apiURL = "https://discord.com/api/v10" /* Discord bot API */
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+var throttled = &throttle.Channel{} /* Notification rate limit */
+
+// ErrNotConfigured is returned when neither a webhook URL nor a bot token and channel ID are configured
+var ErrNotConfigured = errors.New("Discord webhook URL or bot token and channel ID required")
+
+// Message defines the message structure to send via Discord
+type Message struct {
+	Event string /* order, profit or error */
+	Text  string
+}
+
+// Send the message via Discord when its event type is enabled, batched into a digest when the notification rate
+// limit is exceeded
+func (message Message) Send(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !Enabled(configData, message.Event) {
+
+		return
+
+	}
+
+	/* Errors are critical and never throttled */
+	if !throttled.Allow(throttle.Rate(configData), message.Event == EventError, message.Text, func(text string) {
+		send(configData.ConfigGlobal, sessionData, text)
+	}) {
+
+		return
+
+	}
+
+	send(configData.ConfigGlobal, sessionData, message.Text)
+
+}
+
+/* Post text in the background, errors are logged and the message queued for retry */
+func send(
+	global *types.ConfigGlobal,
+	sessionData *types.Session,
+	text string) {
+
+	go func() {
+
+		if err := post(global, text); err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+			outbox.Enqueue(sessionData, messages.Discord, text, err) /* Retried by the Master Node */
+
+		}
+
+	}()
+
+}
+
+// Deliver post the text of a queued notification, registered to outbox to retry failed messages
+func Deliver(
+	configData *types.Config,
+	sessionData *types.Session,
+	text string) error {
+
+	return post(configData.ConfigGlobal, text)
+
+}
+
+// Enabled return true when Discord is configured and event is listed in DiscordEvents
+func Enabled(
+	configData *types.Config,
+	event string) bool {
+
+	global := configData.ConfigGlobal
+
+	if global == nil || (global.DiscordWebhookURL == "" && (global.DiscordBotToken == "" || global.DiscordChannelID == "")) {
+
+		return false
+
+	}
+
+	for _, enabled := range strings.Split(global.DiscordEvents, ",") {
+
+		if strings.TrimSpace(enabled) == event {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Post text to the Discord webhook, or to the channel through the bot API when no webhook is configured */
+func post(
+	global *types.ConfigGlobal,
+	text string) (err error) {
+
+	var body []byte
+	var request *http.Request
+	var response *http.Response
+
+	if len(text) > maxLength {
+		text = text[:maxLength]
+	}
+
+	if body, err = json.Marshal(map[string]string{"content": text}); err != nil {
+
+		return err
+
+	}
+
+	switch {
+	case global.DiscordWebhookURL != "":
+
+		if request, err = http.NewRequest("POST", global.DiscordWebhookURL, bytes.NewReader(body)); err != nil {
+
+			return err
+
+		}
+
+	case global.DiscordBotToken != "" && global.DiscordChannelID != "":
+
+		if request, err = http.NewRequest("POST", apiURL+"/channels/"+global.DiscordChannelID+"/messages", bytes.NewReader(body)); err != nil {
+
+			return err
+
+		}
+
+		request.Header.Set("Authorization", "Bot "+global.DiscordBotToken)
+
+	default:
+
+		return ErrNotConfigured
+
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	if response, err = client.Do(request); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+
+		return fmt.Errorf("Discord returned %s", response.Status)
+
+	}
+
+	return nil
+
+}
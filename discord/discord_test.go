@@ -0,0 +1,86 @@
+package discord
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		global *types.ConfigGlobal
+		event  string
+		want   bool
+	}{
+		{
+			name:   "webhook",
+			global: &types.ConfigGlobal{DiscordWebhookURL: "https://discord.com/api/webhooks/1/x", DiscordEvents: "order, error"},
+			event:  EventError,
+			want:   true,
+		},
+		{
+			name:   "event disabled",
+			global: &types.ConfigGlobal{DiscordWebhookURL: "https://discord.com/api/webhooks/1/x", DiscordEvents: "order,error"},
+			event:  EventProfit,
+			want:   false,
+		},
+		{
+			name:   "bot",
+			global: &types.ConfigGlobal{DiscordBotToken: "token", DiscordChannelID: "123", DiscordEvents: "profit"},
+			event:  EventProfit,
+			want:   true,
+		},
+		{
+			name:   "not configured",
+			global: &types.ConfigGlobal{DiscordBotToken: "token", DiscordEvents: "profit"},
+			event:  EventProfit,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(&types.Config{ConfigGlobal: tt.global}, tt.event); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_post(t *testing.T) {
+	var content, authorization, path string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		data, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		content = body["content"]
+		authorization = r.Header.Get("Authorization")
+		path = r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := post(&types.ConfigGlobal{DiscordWebhookURL: server.URL + "/api/webhooks/1/x"}, "SELL BTCUSDT"); err != nil {
+		t.Fatalf("post() error = %v", err)
+	}
+	if content != "SELL BTCUSDT" || authorization != "" || path != "/api/webhooks/1/x" {
+		t.Errorf("post() webhook content = %v, authorization = %v, path = %v", content, authorization, path)
+	}
+
+	apiURL = server.URL
+	if err := post(&types.ConfigGlobal{DiscordBotToken: "token", DiscordChannelID: "123"}, "Profit 1.25"); err != nil {
+		t.Fatalf("post() error = %v", err)
+	}
+	if content != "Profit 1.25" || authorization != "Bot token" || path != "/channels/123/messages" {
+		t.Errorf("post() bot content = %v, authorization = %v, path = %v", content, authorization, path)
+	}
+
+	if err := post(&types.ConfigGlobal{}, "text"); err != ErrNotConfigured {
+		t.Errorf("post() error = %v, want %v", err, ErrNotConfigured)
+	}
+}
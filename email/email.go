@@ -0,0 +1,275 @@
+package email
+
+/* This package implements the SMTP email notifier. Notifications are sent immediately, alerts with a key (stoploss
+sales, exchange order errors and risk limits) at most once per critical interval for the same alert, and the Master Node sends a daily digest
+with the trades and realized profit of each thread over the last 24 hours at the configured time (EmailDigestTime). */
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const criticalInterval = 10 * time.Minute /* Minimum time between two emails of the same critical alert */
+
+// ErrNotConfigured is returned when the SMTP host, sender or recipients are missing
+var ErrNotConfigured = errors.New("SMTP host, sender and recipients required")
+
+var sendMail = smtp.SendMail /* Replaced in tests */
+
+var (
+	mutex      sync.Mutex
+	sent       = make(map[string]time.Time) /* Last time each critical alert was sent */
+	lastDigest time.Time                    /* Last daily digest sent by this process */
+)
+
+// Send an email immediately. Alerts with a key are skipped when the same key was sent within the last 10 minutes.
+func Send(
+	configData *types.Config,
+	sessionData *types.Session,
+	key string,
+	subject string,
+	body string) {
+
+	if !Enabled(configData) || (key != "" && !allow(key, time.Now())) {
+
+		return
+
+	}
+
+	go func() {
+
+		if err := send(configData.ConfigGlobal, "[CryptoPump] "+subject, body); err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+		}
+
+	}()
+
+}
+
+// Digest send the daily digest of the last 24 hours once a day at configData.ConfigGlobal.EmailDigestTime (only Master Node)
+func Digest(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var summaries []types.ProfitSummary
+	var err error
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	now := time.Now()
+
+	mutex.Lock()
+	last := lastDigest
+	mutex.Unlock()
+
+	if !sessionData.MasterNode || !Enabled(configData) || !due(configData.ConfigGlobal.EmailDigestTime, now, last) {
+
+		return
+
+	}
+
+	from := now.Add(-24 * time.Hour)
+
+	if err = mysql.ExportThreadProfit(sessionData, from.UnixNano()/int64(time.Millisecond), now.UnixNano()/int64(time.Millisecond), func(summary types.ProfitSummary) error {
+
+		summaries = append(summaries, summary)
+		return nil
+
+	}); err != nil {
+
+		return
+
+	}
+
+	mutex.Lock()
+	lastDigest = now
+	mutex.Unlock()
+
+	subject, body := digest(summaries, from, now)
+
+	err = send(configData.ConfigGlobal, subject, body)
+
+}
+
+// Enabled return true when the SMTP host, sender and recipients are configured
+func Enabled(configData *types.Config) bool {
+
+	global := configData.ConfigGlobal
+
+	return global != nil && global.SMTPHost != "" && global.EmailFrom != "" && len(recipients(global.EmailTo)) > 0
+
+}
+
+/* Return true and record the time when key was not sent within criticalInterval before now */
+func allow(
+	key string,
+	now time.Time) bool {
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if t, ok := sent[key]; ok && now.Sub(t) < criticalInterval {
+
+		return false
+
+	}
+
+	sent[key] = now
+
+	return true
+
+}
+
+/* Return true when the digest time (HH:MM, local time) of today has passed and the digest was not sent since */
+func due(
+	digestTime string,
+	now time.Time,
+	last time.Time) bool {
+
+	t, err := time.ParseInLocation("15:04", digestTime, now.Location())
+	if err != nil { /* Empty or invalid time disables the digest */
+
+		return false
+
+	}
+
+	y, m, d := now.Date()
+	scheduled := time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, now.Location())
+
+	return !now.Before(scheduled) && last.Before(scheduled)
+
+}
+
+/* Return the subject and body of the digest of the thread profit summaries between from and to */
+func digest(
+	summaries []types.ProfitSummary,
+	from time.Time,
+	to time.Time) (subject string, body string) {
+
+	var buffer bytes.Buffer
+	var trades int
+	var profit float64
+
+	fmt.Fprintf(&buffer, "Trades and realized profit from %s to %s\n\n", from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&buffer, "%-22s %-10s %8s %12s %8s\n", "Thread", "Symbol", "Sales", "Profit", "Avg. %")
+
+	for _, summary := range summaries {
+
+		fmt.Fprintf(&buffer, "%-22s %-10s %8d %12.2f %8.2f\n", summary.Key, summary.Symbol, summary.Trades, summary.Profit, summary.ProfitPct*100)
+
+		trades += summary.Trades
+		profit += summary.Profit
+
+	}
+
+	if len(summaries) == 0 {
+
+		buffer.WriteString("No sales\n")
+
+	}
+
+	fmt.Fprintf(&buffer, "\n%-33s %8d %12.2f\n", "Total", trades, profit)
+
+	return fmt.Sprintf("[CryptoPump] Daily digest %s - profit %.2f", to.Format("2006-01-02"), profit), buffer.String()
+
+}
+
+/* Send a plain text email to the configured recipients */
+func send(
+	global *types.ConfigGlobal,
+	subject string,
+	body string) error {
+
+	var auth smtp.Auth
+
+	to := recipients(global.EmailTo)
+
+	if global.SMTPHost == "" || global.EmailFrom == "" || len(to) == 0 {
+
+		return ErrNotConfigured
+
+	}
+
+	port := global.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+
+	if global.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", global.SMTPUsername, global.SMTPPassword, global.SMTPHost)
+	}
+
+	return sendMail(net.JoinHostPort(global.SMTPHost, strconv.Itoa(port)), auth, global.EmailFrom, to, compose(global.EmailFrom, to, subject, body, time.Now()))
+
+}
+
+/* Return the RFC 5322 message with headers */
+func compose(
+	from string,
+	to []string,
+	subject string,
+	body string,
+	date time.Time) []byte {
+
+	var buffer bytes.Buffer
+
+	buffer.WriteString("From: " + from + "\r\n")
+	buffer.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	buffer.WriteString("Subject: " + subject + "\r\n")
+	buffer.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buffer.WriteString("\r\n")
+	buffer.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return buffer.Bytes()
+
+}
+
+/* Return the comma separated email addresses */
+func recipients(emailTo string) (to []string) {
+
+	for _, address := range strings.Split(emailTo, ",") {
+
+		if address = strings.TrimSpace(address); address != "" {
+			to = append(to, address)
+		}
+
+	}
+
+	return to
+
+}
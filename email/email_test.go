@@ -0,0 +1,134 @@
+package email
+
+import (
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		global *types.ConfigGlobal
+		want   bool
+	}{
+		{name: "configured", global: &types.ConfigGlobal{SMTPHost: "smtp.example.com", EmailFrom: "bot@example.com", EmailTo: "me@example.com"}, want: true},
+		{name: "no recipients", global: &types.ConfigGlobal{SMTPHost: "smtp.example.com", EmailFrom: "bot@example.com", EmailTo: " , "}, want: false},
+		{name: "no host", global: &types.ConfigGlobal{EmailFrom: "bot@example.com", EmailTo: "me@example.com"}, want: false},
+		{name: "not configured", global: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(&types.Config{ConfigGlobal: tt.global}); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_allow(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+
+	if !allow("test-stoploss", now) {
+		t.Errorf("allow() first alert = false, want true")
+	}
+	if allow("test-stoploss", now.Add(5*time.Minute)) {
+		t.Errorf("allow() within 10 minutes = true, want false")
+	}
+	if !allow("test-exchange", now.Add(5*time.Minute)) {
+		t.Errorf("allow() other key = false, want true")
+	}
+	if !allow("test-stoploss", now.Add(10*time.Minute)) {
+		t.Errorf("allow() after 10 minutes = false, want true")
+	}
+}
+
+func Test_due(t *testing.T) {
+	now := time.Date(2021, 12, 6, 8, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		digestTime string
+		now        time.Time
+		last       time.Time
+		want       bool
+	}{
+		{name: "due", digestTime: "08:00", now: now, want: true},
+		{name: "before time", digestTime: "09:00", now: now, want: false},
+		{name: "sent today", digestTime: "08:00", now: now, last: now.Add(-20 * time.Minute), want: false},
+		{name: "sent yesterday", digestTime: "08:00", now: now, last: now.Add(-24 * time.Hour), want: true},
+		{name: "disabled", digestTime: "", now: now, want: false},
+		{name: "invalid", digestTime: "8am", now: now, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := due(tt.digestTime, tt.now, tt.last); got != tt.want {
+				t.Errorf("due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_digest(t *testing.T) {
+	to := time.Date(2021, 12, 6, 8, 0, 0, 0, time.UTC)
+
+	subject, body := digest([]types.ProfitSummary{
+		{Key: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Trades: 3, Profit: 12.5, ProfitPct: 0.012},
+		{Key: "c683ok5mk1u1120gnmn0", Symbol: "ETHUSDT", Trades: 1, Profit: -2.25, ProfitPct: -0.03},
+	}, to.Add(-24*time.Hour), to)
+
+	if subject != "[CryptoPump] Daily digest 2021-12-06 - profit 10.25" {
+		t.Errorf("digest() subject = %v", subject)
+	}
+	for _, want := range []string{
+		"from 2021-12-05 08:00 to 2021-12-06 08:00",
+		"c683ok5mk1u1120gnmmg   BTCUSDT           3        12.50     1.20",
+		"c683ok5mk1u1120gnmn0   ETHUSDT           1        -2.25    -3.00",
+		"Total                                    4        10.25",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("digest() body = %v, want %v", body, want)
+		}
+	}
+
+	if _, body := digest(nil, to.Add(-24*time.Hour), to); !strings.Contains(body, "No sales") {
+		t.Errorf("digest() empty body = %v", body)
+	}
+}
+
+func Test_send(t *testing.T) {
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotAuth smtp.Auth
+	var gotMsg []byte
+
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotAuth, gotFrom, gotTo, gotMsg = addr, a, from, to, msg
+		return nil
+	}
+	defer func() { sendMail = smtp.SendMail }()
+
+	if err := send(&types.ConfigGlobal{}, "subject", "body"); err != ErrNotConfigured {
+		t.Errorf("send() error = %v, want %v", err, ErrNotConfigured)
+	}
+
+	if err := send(&types.ConfigGlobal{SMTPHost: "smtp.example.com", EmailFrom: "bot@example.com", EmailTo: "me@example.com, you@example.com"}, "Stoploss hit BTCUSDT", "line 1\nline 2"); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || gotAuth != nil || gotFrom != "bot@example.com" || len(gotTo) != 2 || gotTo[1] != "you@example.com" {
+		t.Errorf("send() addr = %v, auth = %v, from = %v, to = %v", gotAddr, gotAuth, gotFrom, gotTo)
+	}
+	if msg := string(gotMsg); !strings.HasPrefix(msg, "From: bot@example.com\r\nTo: me@example.com, you@example.com\r\nSubject: Stoploss hit BTCUSDT\r\n") ||
+		!strings.HasSuffix(msg, "\r\n\r\nline 1\r\nline 2") {
+		t.Errorf("send() message = %q", msg)
+	}
+
+	if err := send(&types.ConfigGlobal{SMTPHost: "smtp.example.com", SMTPPort: 25, SMTPUsername: "bot", SMTPPassword: "secret", EmailFrom: "bot@example.com", EmailTo: "me@example.com"}, "subject", "body"); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+	if gotAddr != "smtp.example.com:25" || gotAuth == nil {
+		t.Errorf("send() addr = %v, auth = %v", gotAddr, gotAuth)
+	}
+}
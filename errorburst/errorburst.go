@@ -0,0 +1,177 @@
+package errorburst
+
+/* This package implements the error-rate monitor. Logged errors are counted by category (exchange, database and
+websocket) in a sliding window of ErrorBurstWindow minutes, and when a category reaches ErrorBurstMax errors within
+the window a single aggregated critical error notification is sent, instead of one notification per failure. A
+category alerts again only once a full window has passed since its last alert. */
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Error categories */
+const (
+	Exchange  = "exchange"
+	Database  = "database"
+	Websocket = "websocket"
+)
+
+const defaultWindow = 5 * time.Minute /* Sliding window when ErrorBurstWindow is 0 */
+
+// Monitor struct define the error counters of each category
+type Monitor struct {
+	mutex   sync.Mutex
+	errors  map[string][]time.Time /* Times of the errors of each category within the window */
+	alerted map[string]time.Time   /* Last alert time of each category */
+}
+
+var (
+	monitor = &Monitor{}
+	config  struct {
+		sync.Mutex
+		configData *types.Config /* Configuration of the thread, replaced on reload by Configure */
+	}
+)
+
+// Observe count the errors logged by this thread and send an aggregated alert when a category crosses ErrorBurstMax
+func Observe(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	Configure(configData)
+
+	logger.Observe(func(entry logger.LogEntry) {
+
+		config.Lock()
+		configData := config.configData
+		config.Unlock()
+
+		if !strings.EqualFold(entry.LogLevel, "DebugLevel") || configData.ConfigGlobal == nil {
+
+			return
+
+		}
+
+		category := Category(entry.Message)
+		if category == "" {
+
+			return
+
+		}
+
+		window := time.Duration(configData.ConfigGlobal.ErrorBurstWindow) * time.Minute
+		if window == 0 {
+			window = defaultWindow
+		}
+
+		if count, burst := monitor.Record(category, configData.ConfigGlobal.ErrorBurstMax, window, time.Now()); burst {
+
+			go alert(configData, sessionData, category, count, window, entry.Message) /* Not sent while logging */
+
+		}
+
+	})
+
+}
+
+// Configure replace the configuration used by the monitor after a configuration reload
+func Configure(configData *types.Config) {
+
+	config.Lock()
+	defer config.Unlock()
+
+	config.configData = configData
+
+}
+
+// Category return the error category of a log message, empty when it is not monitored
+func Category(message string) string {
+
+	switch {
+	case strings.Contains(message, "cryptopump/algorithms.Ws") || strings.Contains(message, "websocket"):
+		return Websocket
+	case strings.Contains(message, "cryptopump/mysql."):
+		return Database
+	case strings.Contains(message, "cryptopump/exchange."):
+		return Exchange
+	}
+
+	return ""
+
+}
+
+// Record an error of category at now, burst is true when the errors within window reach max and the category did
+// not alert within window. max 0 disables the alerts.
+func (monitor *Monitor) Record(
+	category string,
+	max int,
+	window time.Duration,
+	now time.Time) (count int, burst bool) {
+
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	if monitor.errors == nil {
+		monitor.errors = make(map[string][]time.Time)
+		monitor.alerted = make(map[string]time.Time)
+	}
+
+	var recent []time.Time
+
+	for _, t := range monitor.errors[category] {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+
+	monitor.errors[category] = append(recent, now)
+	count = len(monitor.errors[category])
+
+	if max <= 0 || count < max {
+
+		return count, false
+
+	}
+
+	if t, ok := monitor.alerted[category]; ok && now.Sub(t) < window {
+
+		return count, false
+
+	}
+
+	monitor.alerted[category] = now
+
+	return count, true
+
+}
+
+/* Send the aggregated alert of a category */
+func alert(
+	configData *types.Config,
+	sessionData *types.Session,
+	category string,
+	count int,
+	window time.Duration,
+	last string) {
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Critical,
+		Key:      "burst-" + category,
+		Title:    "Error burst " + category,
+		Data: messages.Data{
+			ThreadID: sessionData.ThreadID,
+			Symbol:   sessionData.Symbol,
+			Message:  fmt.Sprintf("%d %s errors in the last %.0f minutes, last: %s", count, category, window.Minutes(), last),
+		},
+	}.Send(configData, sessionData)
+
+}
@@ -0,0 +1,56 @@
+package errorburst
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "exchange", message: "github.com/aleibovici/cryptopump/exchange.binanceGetAccount - <APIError> code=-1021", want: Exchange},
+		{name: "database", message: "github.com/aleibovici/cryptopump/mysql.SaveOrder - dial tcp: connection refused", want: Database},
+		{name: "websocket handler", message: "github.com/aleibovici/cryptopump/algorithms.WsKline.func2 - websocket: close 1006", want: Websocket},
+		{name: "websocket disconnected", message: "github.com/aleibovici/cryptopump/algorithms.WsBookTicker - websocket channel disconnected, trying to re-establish", want: Websocket},
+		{name: "not monitored", message: "Restarting", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Category(tt.message); got != tt.want {
+				t.Errorf("Category() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_Record(t *testing.T) {
+	monitor := &Monitor{}
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+	window := 5 * time.Minute
+
+	for i := 0; i < 2; i++ {
+		if _, burst := monitor.Record(Database, 3, window, now.Add(time.Duration(i)*time.Minute)); burst {
+			t.Errorf("Record() below max burst = true, want false")
+		}
+	}
+	if count, burst := monitor.Record(Database, 3, window, now.Add(2*time.Minute)); count != 3 || !burst {
+		t.Errorf("Record() at max = %v, %v, want 3, true", count, burst)
+	}
+	if _, burst := monitor.Record(Database, 3, window, now.Add(3*time.Minute)); burst {
+		t.Errorf("Record() already alerted within window burst = true, want false")
+	}
+	if _, burst := monitor.Record(Exchange, 3, window, now.Add(3*time.Minute)); burst {
+		t.Errorf("Record() other category burst = true, want false")
+	}
+	monitor.Record(Database, 3, window, now.Add(8*time.Minute))
+	monitor.Record(Database, 3, window, now.Add(9*time.Minute))
+	if count, burst := monitor.Record(Database, 3, window, now.Add(10*time.Minute)); count != 3 || !burst {
+		t.Errorf("Record() new burst after window = %v, %v, want 3, true", count, burst)
+	}
+	if _, burst := monitor.Record(Websocket, 0, window, now); burst {
+		t.Errorf("Record() disabled burst = true, want false")
+	}
+}
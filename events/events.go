@@ -0,0 +1,191 @@
+package events
+
+/* This package implements the internal event bus of the thread running in this process. The trading code publishes
+typed events (order events, market events, session lifecycle and errors) without knowing who consumes them, and the
+notification, metrics and webhook subsystems subscribe to the topics they handle at startup. Subscribers are called
+synchronously in subscription order, so they must not block: slow deliveries (webhooks) are made asynchronously by the
+subscriber. A panic in a subscriber is logged and doesn't reach the publisher or the other subscribers. */
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Event topics */
+const (
+	OrderPlaced       = "order.placed"       /* Order accepted by the exchange, Data is Order */
+	OrderFilled       = "order.filled"       /* Order filled by the exchange, Data is Order */
+	OrderFailed       = "order.failed"       /* Order rejected with an exchange error, Data is Order */
+	ProfitRealized    = "profit.realized"    /* Sale closing a trade cycle, Data is Profit */
+	StoplossTriggered = "stoploss.triggered" /* Stoploss or stop price hit by the market price, Data is Stoploss */
+	SessionStarted    = "session.started"    /* Thread started, Data is Session */
+	SessionStopped    = "session.stopped"    /* Thread stopping, Data is Session */
+	ErrorRaised       = "error"              /* Error of a thread, Data is Error */
+)
+
+// Topics list the event topics
+var Topics = []string{OrderPlaced, OrderFilled, OrderFailed, ProfitRealized, StoplossTriggered, SessionStarted, SessionStopped, ErrorRaised}
+
+// Event struct define an event published on the bus
+type Event struct {
+	Topic   string
+	Time    time.Time
+	Config  *types.Config /* Configuration of the thread, nil for session.stopped */
+	Session *types.Session
+	Data    interface{} /* Order, Profit, Stoploss, Session or Error, see the topics */
+}
+
+// Order struct define the data of the order events
+type Order struct {
+	OrderID   int64
+	Side      string /* BUY or SELL */
+	Symbol    string
+	Price     float64
+	Quantity  float64
+	Status    string
+	Profit    float64 /* Running profit of the thread, order.filled */
+	ProfitPct float64 /* Running profit percentage of the thread, order.filled */
+	Err       error   /* Exchange error, order.failed */
+}
+
+// Profit struct define the data of the profit.realized event
+type Profit struct {
+	Symbol string
+	Profit float64 /* Profit of the trade cycle closed */
+}
+
+// Stoploss struct define the data of the stoploss.triggered event
+type Stoploss struct {
+	Order  types.Order /* Order being sold */
+	Symbol string
+	Price  float64 /* Market price */
+	Reason string  /* stoploss or stop price */
+	Title  string
+}
+
+// Session struct define the data of the session lifecycle events
+type Session struct {
+	Symbol  string
+	Port    string
+	Resumed bool   /* session.started of a resumed thread */
+	Reason  string /* session.stopped reason */
+}
+
+// Error struct define the data of the error event
+type Error struct {
+	Symbol  string
+	Side    string /* Side of the order that failed, if any */
+	Message string
+	Key     string /* Alert key, the same alert is notified at most once every 10 minutes */
+	Title   string /* Alert title */
+}
+
+// Handler is called with the events of the topics it subscribes to
+type Handler func(event Event)
+
+type subscription struct {
+	handler Handler
+	topics  map[string]bool /* Empty for all topics */
+}
+
+var bus = struct {
+	sync.RWMutex
+	subscriptions []subscription
+}{}
+
+// Subscribe register handler for the events of topics, all topics when none. Called at startup.
+func Subscribe(
+	handler Handler,
+	topics ...string) {
+
+	s := subscription{handler: handler, topics: make(map[string]bool)}
+
+	for _, topic := range topics {
+		s.topics[topic] = true
+	}
+
+	bus.Lock()
+	defer bus.Unlock()
+
+	bus.subscriptions = append(bus.subscriptions, s)
+
+}
+
+// Publish the event of topic with data to its subscribers
+func Publish(
+	configData *types.Config,
+	sessionData *types.Session,
+	topic string,
+	data interface{}) {
+
+	event := Event{
+		Topic:   topic,
+		Time:    time.Now(),
+		Config:  configData,
+		Session: sessionData,
+		Data:    data,
+	}
+
+	bus.RLock()
+	subscriptions := bus.subscriptions
+	bus.RUnlock()
+
+	for _, s := range subscriptions {
+
+		if len(s.topics) > 0 && !s.topics[topic] {
+			continue
+		}
+
+		deliver(s.handler, event)
+
+	}
+
+}
+
+// Reset remove the subscribers, used by tests
+func Reset() {
+
+	bus.Lock()
+	defer bus.Unlock()
+
+	bus.subscriptions = nil
+
+}
+
+/* Call handler with event, logging its panic */
+func deliver(
+	handler Handler,
+	event Event) {
+
+	defer func() {
+
+		if recovered := recover(); recovered != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  event.Session,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + fmt.Sprintf("subscriber panic on %s: %v", event, recovered),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+		}
+
+	}()
+
+	handler(event)
+
+}
+
+// String return the topic and data of the event, for logging
+func (event Event) String() string {
+
+	return fmt.Sprintf("%s %+v", event.Topic, event.Data)
+
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestPublish(t *testing.T) {
+
+	Reset()
+	defer Reset()
+
+	var got []string
+
+	Subscribe(func(event Event) { got = append(got, "orders "+event.Topic) }, OrderPlaced, OrderFilled)
+	Subscribe(func(event Event) { got = append(got, "all "+event.Topic) })
+
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg"}
+
+	Subscribe(func(event Event) {
+		if event.Session != sessionData || event.Data != (Order{OrderID: 42, Side: "BUY"}) || event.Time.IsZero() {
+			t.Errorf("Publish() event = %v", event)
+		}
+	}, OrderPlaced)
+
+	Publish(nil, sessionData, OrderPlaced, Order{OrderID: 42, Side: "BUY"})
+	Publish(nil, sessionData, SessionStopped, Session{Reason: "stopped"})
+
+	want := []string{"orders order.placed", "all order.placed", "all session.stopped"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Publish() delivered %v, want %v", got, want)
+	}
+
+}
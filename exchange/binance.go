@@ -2,15 +2,23 @@ package exchange
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/metrics"
 	"github.com/aleibovici/cryptopump/threads"
 	"github.com/aleibovici/cryptopump/types"
 
 	"github.com/adshao/go-binance/v2"
+	"github.com/adshao/go-binance/v2/common"
 )
 
 /* Map binance.Order types to Order type */
@@ -145,6 +153,8 @@ func binanceMapExchangeInfo(sessionData *types.Session, from *binance.ExchangeIn
 			to.MaxQuantity = from.Symbols[key].LotSizeFilter().MaxQuantity
 			to.MinQuantity = from.Symbols[key].LotSizeFilter().MinQuantity
 			to.StepSize = from.Symbols[key].LotSizeFilter().StepSize
+			to.MinNotional = binanceFilterValue(from.Symbols[key].Filters, "MIN_NOTIONAL", "minNotional")
+			to.MaxNumOrders = binanceFilterValue(from.Symbols[key].Filters, "MAX_NUM_ORDERS", "maxNumOrders")
 
 		}
 
@@ -154,6 +164,56 @@ func binanceMapExchangeInfo(sessionData *types.Session, from *binance.ExchangeIn
 
 }
 
+/* Map binance.DepthResponse types to OrderBook type */
+func binanceMapOrderBook(from *binance.DepthResponse) (to *types.OrderBook) {
+
+	to = &types.OrderBook{}
+
+	for key := range from.Bids {
+
+		to.Bids = append(to.Bids, types.OrderBookEntry{
+			Price:    functions.StrToFloat64(from.Bids[key].Price),
+			Quantity: functions.StrToFloat64(from.Bids[key].Quantity),
+		})
+
+	}
+
+	for key := range from.Asks {
+
+		to.Asks = append(to.Asks, types.OrderBookEntry{
+			Price:    functions.StrToFloat64(from.Asks[key].Price),
+			Quantity: functions.StrToFloat64(from.Asks[key].Quantity),
+		})
+
+	}
+
+	to.TimeStamp = time.Now()
+
+	return to
+
+}
+
+/* Retrieve a symbol filter value from binance.ExchangeInfo filters */
+func binanceFilterValue(filters []map[string]interface{}, filterType string, key string) string {
+
+	for _, filter := range filters {
+
+		if filter["filterType"] == filterType {
+
+			if value, ok := filter[key]; ok {
+
+				return fmt.Sprint(value)
+
+			}
+
+		}
+
+	}
+
+	return ""
+
+}
+
 /* Get Binance client */
 func binanceGetClient(
 	configData *types.Config) *binance.Client {
@@ -161,23 +221,106 @@ func binanceGetClient(
 	binance.WebsocketKeepalive = false           /* Disable websocket keepalive */
 	binance.WebsocketTimeout = time.Second * 100 /* Set websocket timeout */
 
-	/* If the -test.v flag is set, the testnet API is used */
-	if flag.Lookup("test.v") != nil {
+	var client *binance.Client
+
+	switch {
+	case flag.Lookup("test.v") != nil: /* If the -test.v flag is set, the testnet API is used */
 
 		binance.UseTestnet = true
-		return binance.NewClient(configData.ConfigGlobal.ApikeyTestNet, configData.ConfigGlobal.SecretkeyTestNet)
+		client = binance.NewClient(configData.ConfigGlobal.ApikeyTestNet, configData.ConfigGlobal.SecretkeyTestNet)
+
+	case configData.TestNet: /* Exchange test network, used with launch.json */
+
+		binance.UseTestnet = true
+		client = binance.NewClient(configData.ConfigGlobal.ApikeyTestNet, configData.ConfigGlobal.SecretkeyTestNet)
+
+	default:
+
+		client = binance.NewClient(configData.ConfigGlobal.Apikey, configData.ConfigGlobal.Secretkey)
 
 	}
 
-	/* Exchange test network, used with launch.json */
-	if configData.TestNet {
+	client.HTTPClient = &http.Client{Transport: binanceTransport{}} /* Record the used request weight, latency and errors */
 
-		binance.UseTestnet = true
-		return binance.NewClient(configData.ConfigGlobal.ApikeyTestNet, configData.ConfigGlobal.SecretkeyTestNet)
+	return client
+
+}
+
+var inflight int64 /* REST calls in progress */
+
+// Drain wait up to timeout for the REST calls in progress to complete, returning false on timeout
+func Drain(timeout time.Duration) bool {
+
+	deadline := time.Now().Add(timeout)
+
+	for atomic.LoadInt64(&inflight) > 0 {
+
+		if !time.Now().Before(deadline) {
+
+			return false
+
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+	}
+
+	return true
+
+}
+
+/* HTTP transport limiting the REST calls in progress, recording the request weight used in the last minute reported by Binance and the REST calls stats */
+type binanceTransport struct{}
+
+func (binanceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+
+	atomic.AddInt64(&inflight, 1)
+	defer atomic.AddInt64(&inflight, -1)
+
+	/* Wait for a slot of the process-wide concurrency limiter, by the priority lane of the call */
+	lane := requestLane(request)
+	queued := time.Now()
+
+	release, err := Acquire(request.Context(), lane)
+	if err != nil {
+
+		return nil, err
+
+	}
+	defer release()
+
+	metrics.ObserveExchangeWait(LaneNames[lane], time.Since(queued))
+
+	start := time.Now()
+	response, err := http.DefaultTransport.RoundTrip(request)
+	latency := time.Since(start)
+
+	endpoint := request.Method + " " + request.URL.Path
+
+	if Observe(endpoint, start, latency, err != nil || response.StatusCode >= 400) {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  nil,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - Slow exchange call " + endpoint + " " + latency.Round(time.Millisecond).String(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	if err == nil {
+
+		if weight, err := strconv.ParseFloat(response.Header.Get("X-Mbx-Used-Weight-1m"), 64); err == nil {
+
+			metrics.SetUsedWeight(weight)
+
+		}
 
 	}
 
-	return binance.NewClient(configData.ConfigGlobal.Apikey, configData.ConfigGlobal.Secretkey)
+	return response, err
 
 }
 
@@ -234,6 +377,74 @@ func binanceKeepAliveUserStreamServiceListenKey(
 
 }
 
+/* Return err classified into the exchange errors by its Binance error code */
+func binanceError(err error) error {
+
+	var apiErr *common.APIError
+
+	if err == nil {
+
+		return nil
+
+	}
+
+	if !errors.As(err, &apiErr) {
+
+		if isNetworkError(err) {
+
+			return classified(ErrUnavailable, err)
+
+		}
+
+		return err
+
+	}
+
+	switch {
+	case apiErr.Code == -1003, apiErr.Code == -1015: /* Too many requests, too many new orders */
+		return classified(ErrRateLimited, err)
+	case apiErr.Code == -1001, apiErr.Code == -1007, apiErr.Code == -1008: /* Disconnected, timeout, server busy */
+		return classified(ErrUnavailable, err)
+	case apiErr.Code == -1021: /* Timestamp for this request is outside of the recvWindow */
+		return classified(ErrTimestamp, err)
+	case apiErr.Code == -2011, apiErr.Code == -2013: /* Unknown order sent, order does not exist */
+		return classified(ErrOrderNotFound, err)
+	case binanceIsAuthError(err):
+		return classified(ErrUnauthorized, err)
+	case apiErr.Code == -2010 && strings.Contains(strings.ToLower(apiErr.Message), "insufficient balance"):
+		return classified(ErrInsufficientFunds, err) /* Account has insufficient balance for requested action */
+	case apiErr.Code == -1013 && strings.Contains(apiErr.Message, "LOT_SIZE"): /* Filter failure: LOT_SIZE */
+		return classified(ErrLotSize, err)
+	case apiErr.Code == -1013 && strings.Contains(apiErr.Message, "NOTIONAL"): /* Filter failure: MIN_NOTIONAL or NOTIONAL */
+		return classified(ErrMinNotional, err)
+	}
+
+	return err
+
+}
+
+/* Return true when err is a rejected API key, signature or permission */
+func binanceIsAuthError(err error) bool {
+
+	var apiErr *common.APIError
+
+	if !errors.As(err, &apiErr) {
+
+		return false
+
+	}
+
+	switch apiErr.Code {
+	case -1002, -1022, -2014, -2015: /* Unauthorized, invalid signature, API-key format invalid, invalid API-key, IP, or permissions */
+
+		return true
+
+	}
+
+	return false
+
+}
+
 /* Synchronize time */
 func binanceNewSetServerTimeService(
 	sessionData *types.Session) (err error) {
@@ -268,6 +479,87 @@ func binanceGetAccount(sessionData *types.Session) (account *binance.Account, er
 
 }
 
+/* Retrieve account permission to place orders */
+func binanceGetTradePermission(sessionData *types.Session) (canTrade bool, err error) {
+
+	var account *binance.Account
+
+	if account, err = binanceGetAccount(sessionData); err != nil {
+
+		return false, err
+
+	}
+
+	return account.CanTrade, err
+
+}
+
+// binanceGetCommission retrieve account commission rate per order as ratio. Binance returns commissions in
+// basis points, and the higher of maker and taker is used since orders may fill as either.
+func binanceGetCommission(sessionData *types.Session) (commission float64, err error) {
+
+	var account *binance.Account
+
+	if account, err = binanceGetAccount(sessionData); err != nil {
+
+		return 0, err
+
+	}
+
+	commission = float64(account.TakerCommission)
+
+	if account.MakerCommission > account.TakerCommission {
+
+		commission = float64(account.MakerCommission)
+
+	}
+
+	return commission / 10000, err
+
+}
+
+/* Retrieve number of open orders for symbol */
+func binanceGetOpenOrderCount(sessionData *types.Session) (count int, err error) {
+
+	var orders []*binance.Order
+
+	if orders, err = sessionData.Clients.Binance.NewListOpenOrdersService().Symbol(sessionData.Symbol).Do(context.Background()); err != nil {
+
+		return 0, err
+
+	}
+
+	return len(orders), err
+
+}
+
+/* Cancel all open orders for symbol */
+func binanceCancelOpenOrders(sessionData *types.Session) (count int, err error) {
+
+	var orders []*binance.Order
+
+	if orders, err = sessionData.Clients.Binance.NewListOpenOrdersService().Symbol(sessionData.Symbol).Do(context.Background()); err != nil {
+
+		return 0, err
+
+	}
+
+	for _, order := range orders {
+
+		if _, err = binanceCancelOrder(sessionData, order.OrderID); err != nil {
+
+			return count, err
+
+		}
+
+		count++
+
+	}
+
+	return count, err
+
+}
+
 /* Retrieve symbol fiat funds available */
 func binanceGetSymbolFiatFunds(
 	sessionData *types.Session) (balance float64, err error) {
@@ -332,6 +624,89 @@ func binanceGetSymbolFunds(
 
 }
 
+/* Retrieve free balances for all assets */
+func binanceGetBalances(
+	sessionData *types.Session) (balances map[string]float64, err error) {
+
+	var account *binance.Account
+
+	if account, err = binanceGetAccount(sessionData); err != nil {
+
+		return nil, err
+
+	}
+
+	balances = make(map[string]float64)
+
+	for key := range account.Balances { /* Loop through balances */
+
+		balances[account.Balances[key].Asset] = functions.StrToFloat64(account.Balances[key].Free)
+
+	}
+
+	return balances, err
+
+}
+
+/* Retrieve free and locked balances of the assets held */
+func binanceGetAccountBalances(
+	sessionData *types.Session) (balances []types.Balance, err error) {
+
+	var account *binance.Account
+
+	if account, err = binanceGetAccount(sessionData); err != nil {
+
+		return nil, err
+
+	}
+
+	for key := range account.Balances { /* Loop through balances */
+
+		balance := types.Balance{
+			Asset:  account.Balances[key].Asset,
+			Free:   functions.StrToFloat64(account.Balances[key].Free),
+			Locked: functions.StrToFloat64(account.Balances[key].Locked),
+		}
+
+		if balance.Free+balance.Locked > 0 { /* Only assets held */
+
+			balances = append(balances, balance)
+
+		}
+
+	}
+
+	return balances, err
+
+}
+
+/* Retrieve latest price for any symbol */
+func binanceGetSymbolPrice(
+	sessionData *types.Session,
+	symbol string) (price float64, err error) {
+
+	var tmp []*binance.SymbolPrice
+
+	if tmp, err = sessionData.Clients.Binance.NewListPricesService().Symbol(symbol).Do(context.Background()); err != nil {
+
+		return 0, err
+
+	}
+
+	for key := range tmp {
+
+		if tmp[key].Symbol == symbol {
+
+			return functions.StrToFloat64(tmp[key].Price), err
+
+		}
+
+	}
+
+	return 0, errors.New("Price not found for symbol " + symbol)
+
+}
+
 /* Minutely crypto currency open/close prices, high/low, trades and others */
 func binanceGetKlines(
 	sessionData *types.Session) (klines []*binance.Kline, err error) {
@@ -348,6 +723,22 @@ func binanceGetKlines(
 }
 
 /* 24hr ticker price change statistics */
+func binanceGetSymbolKlines(
+	sessionData *types.Session,
+	symbol string,
+	limit int) (klines []*binance.Kline, err error) {
+
+	if klines, err = sessionData.Clients.Binance.NewKlinesService().Symbol(symbol).
+		Interval("1h").Limit(limit).Do(context.Background()); err != nil {
+
+		return nil, err
+
+	}
+
+	return klines, err
+
+}
+
 func binanceGetPriceChangeStats(
 	sessionData *types.Session) (PriceChangeStats []*types.PriceChangeStats, err error) {
 
@@ -380,6 +771,22 @@ func binanceGetOrder(
 
 }
 
+/* Retrieve order book depth */
+func binanceGetOrderBook(
+	sessionData *types.Session) (orderBook *types.OrderBook, err error) {
+
+	var tmp *binance.DepthResponse
+
+	if tmp, err = sessionData.Clients.Binance.NewDepthService().Symbol(sessionData.Symbol).Limit(100).Do(context.Background()); err != nil {
+
+		return nil, err
+
+	}
+
+	return binanceMapOrderBook(tmp), err
+
+}
+
 /* CANCEL an order */
 func binanceCancelOrder(
 	sessionData *types.Session,
@@ -397,6 +804,23 @@ func binanceCancelOrder(
 
 }
 
+/* Return the create order service of a bot order of side, with the correlation ID of the trade cycle as client order ID */
+func binanceCreateOrderService(
+	sessionData *types.Session,
+	side binance.SideType) *binance.CreateOrderService {
+
+	service := sessionData.Clients.Binance.NewCreateOrderService().Symbol(sessionData.Symbol).Side(side)
+
+	if sessionData.CorrelationID != "" { /* Followed by the side to keep the buy and sell orders of a cycle distinct */
+
+		service.NewClientOrderID(sessionData.CorrelationID + "-" + string(side)[:1])
+
+	}
+
+	return service
+
+}
+
 /* Create order to BUY */
 func binanceBuyOrder(
 	sessionData *types.Session,
@@ -405,8 +829,8 @@ func binanceBuyOrder(
 	var tmp *binance.CreateOrderResponse
 
 	/* Execute OrderTypeMarket */
-	if tmp, err = sessionData.Clients.Binance.NewCreateOrderService().Symbol(sessionData.Symbol).
-		Side(binance.SideTypeBuy).Type(binance.OrderTypeMarket).
+	if tmp, err = binanceCreateOrderService(sessionData, binance.SideTypeBuy).
+		Type(binance.OrderTypeMarket).
 		Quantity(quantity).Do(context.Background()); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -426,6 +850,78 @@ func binanceBuyOrder(
 
 }
 
+/* Create manual order for sessionData.Symbol, MARKET when price is empty or LIMIT IOC at price */
+func binanceManualOrder(
+	sessionData *types.Session,
+	side string,
+	quantity string,
+	price string) (order *types.Order, err error) {
+
+	var tmp *binance.CreateOrderResponse
+
+	service := sessionData.Clients.Binance.NewCreateOrderService().Symbol(sessionData.Symbol).
+		Side(binance.SideType(side)).Quantity(quantity)
+
+	if price == "" {
+
+		service = service.Type(binance.OrderTypeMarket) /* Execute OrderTypeMarket */
+
+	} else {
+
+		service = service.Type(binance.OrderTypeLimit).Price(price).TimeInForce(binance.TimeInForceTypeIOC) /* Execute OrderTypeLimit, the unfilled quantity expires */
+
+	}
+
+	if tmp, err = service.Do(context.Background()); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	return binanceMapCreateOrderResponse(tmp), err
+
+}
+
+/* Create MARKET order for any symbol sized by quote (fiat) quantity */
+func binanceQuoteOrder(
+	sessionData *types.Session,
+	symbol string,
+	side string,
+	quoteQuantity string) (order *types.Order, err error) {
+
+	var tmp *binance.CreateOrderResponse
+
+	/* Execute OrderTypeMarket */
+	if tmp, err = sessionData.Clients.Binance.NewCreateOrderService().Symbol(symbol).
+		Side(binance.SideType(side)).Type(binance.OrderTypeMarket).
+		QuoteOrderQty(quoteQuantity).Do(context.Background()); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	return binanceMapCreateOrderResponse(tmp), err
+
+}
+
 /* WsBookTickerServe serve websocket that pushes updates to the best bid or ask price or quantity in real-time for a specified symbol. */
 func binanceWsBookTickerServe(
 	sessionData *types.Session,
@@ -472,7 +968,7 @@ func binanceSellOrder(
 	if !sessionData.ForceSell {
 
 		/* Execute OrderTypeLimit */
-		if tmp, err = sessionData.Clients.Binance.NewCreateOrderService().Symbol(sessionData.Symbol).Side(binance.SideTypeSell).Type(binance.OrderTypeLimit).Quantity(quantity).Price(functions.Float64ToStr(marketData.Price, 2)).TimeInForce(binance.TimeInForceTypeGTC).Do(context.Background()); err != nil {
+		if tmp, err = binanceCreateOrderService(sessionData, binance.SideTypeSell).Type(binance.OrderTypeLimit).Quantity(quantity).Price(functions.Float64ToStr(marketData.Price, 2)).TimeInForce(binance.TimeInForceTypeGTC).Do(context.Background()); err != nil {
 
 			return nil, err
 
@@ -485,7 +981,7 @@ func binanceSellOrder(
 		sessionData.ForceSell = false
 
 		/* Execute OrderTypeMarket */
-		if tmp, err = sessionData.Clients.Binance.NewCreateOrderService().Symbol(sessionData.Symbol).Side(binance.SideTypeSell).Type(binance.OrderTypeMarket).Quantity(quantity).Do(context.Background()); err != nil {
+		if tmp, err = binanceCreateOrderService(sessionData, binance.SideTypeSell).Type(binance.OrderTypeMarket).Quantity(quantity).Do(context.Background()); err != nil {
 
 			return nil, err
 
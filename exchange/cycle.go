@@ -0,0 +1,253 @@
+package exchange
+
+import (
+	"errors"
+	"math"
+
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// ErrCycleUnknown is returned when the order in flight of a trade cycle was not acknowledged by the exchange
+var ErrCycleUnknown = errors.New("Trade cycle order not acknowledged by the exchange, outcome unknown")
+
+// RecoverCycle resolve with the exchange the order in flight of the trade cycle, persisted when the thread stopped or
+// failed, and settle the cycle by the open transactions. Orders still open are canceled, a filled buy (or the filled
+// part of a canceled buy) is saved as an open transaction and a filled sale closes the transaction it sold. When the
+// exchange cannot be reached the cycle is left failed with its order, and buys and sells wait for the next recovery.
+func RecoverCycle(
+	configData *types.Config,
+	sessionData *types.Session,
+	marketData *types.Market) (err error) {
+
+	var order *types.Order
+
+	if !cycle.InFlight(sessionData) && !cycle.Unresolved(sessionData) {
+
+		return cycle.Settle(sessionData)
+
+	}
+
+	/* Enter and defer exiting busy mode */
+	sessionData.Busy = true
+	defer func() {
+		sessionData.Busy = false
+	}()
+
+	state, orderID, orderIDSource := cycle.State(sessionData), sessionData.CycleOrderID, sessionData.CycleOrderIDSource
+	buy := state == cycle.BuyPlaced || state == cycle.BuyFailed
+
+	failed := cycle.SellFailed
+	if buy {
+		failed = cycle.BuyFailed
+	}
+
+	/* Conditional defer logging when the order in flight cannot be resolved */
+	defer func() {
+		if err != nil {
+			recoveredCycle(configData, marketData, sessionData, orderID, "DebugLevel", functions.GetFunctionName()+" - trade cycle "+state+" not recovered - "+err.Error())
+		}
+	}()
+
+	if orderID == 0 { /* The thread stopped before the exchange acknowledged the order */
+
+		if err = cycle.Transition(sessionData, failed, 0, 0); err != nil {
+
+			return err
+
+		}
+
+		recoveredCycle(configData, marketData, sessionData, 0, "InfoLevel", "Trade cycle recovered from "+state+" - "+ErrCycleUnknown.Error()+", holdings not tracked can be adopted")
+
+		return cycle.Resolve(sessionData)
+
+	}
+
+	if order, err = GetOrder(configData, sessionData, orderID); err == nil && order != nil &&
+		(order.Status == "NEW" || order.Status == "PARTIALLY_FILLED") { /* Cancel the order still open */
+
+		if _, err = CancelOrder(configData, sessionData, orderID); err == nil || errors.Is(err, ErrOrderNotFound) {
+
+			order, err = GetOrder(configData, sessionData, orderID)
+
+		}
+
+	}
+
+	if err == nil && order == nil {
+
+		err = ErrUnavailable
+
+	}
+
+	if err != nil {
+
+		_ = cycle.Transition(sessionData, failed, orderID, orderIDSource) /* Resolved by the next recovery */
+
+		return err
+
+	}
+
+	if buy {
+
+		err = recoverBuy(sessionData, order)
+
+	} else {
+
+		err = recoverSell(sessionData, order, orderIDSource)
+
+	}
+
+	if err != nil {
+
+		_ = cycle.Transition(sessionData, failed, orderID, orderIDSource)
+
+		return err
+
+	}
+
+	recoveredCycle(configData, marketData, sessionData, orderID, "InfoLevel", "Trade cycle recovered from "+state+" - order "+order.Status)
+
+	return cycle.Resolve(sessionData)
+
+}
+
+/* Save the filled quantity of a recovered buy as an open transaction, unless already saved */
+func recoverBuy(
+	sessionData *types.Session,
+	order *types.Order) (err error) {
+
+	if order.ExecutedQuantity <= 0 { /* Canceled without fill */
+
+		return mysql.UpdateOrder(sessionData, order.OrderID, order.CumulativeQuoteQuantity, order.ExecutedQuantity, 0, order.Status)
+
+	}
+
+	if open, err := openTransaction(sessionData, order.OrderID); err != nil || open {
+
+		return err
+
+	}
+
+	price := order.CumulativeQuoteQuantity / order.ExecutedQuantity
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		price = 0
+	}
+
+	if err = mysql.SaveOrder(sessionData, order, 0, price); err != nil { /* Saved before the thread stopped */
+
+		if err = mysql.UpdateOrder(sessionData, order.OrderID, order.CumulativeQuoteQuantity, order.ExecutedQuantity, price, order.Status); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	if err = mysql.SaveThreadTransaction(sessionData, order.OrderID, order.CumulativeQuoteQuantity, price, order.ExecutedQuantity); err != nil {
+
+		return err
+
+	}
+
+	return cycle.Transition(sessionData, cycle.PositionOpen, 0, 0)
+
+}
+
+/* Close the open transaction orderIDSource sold by a recovered filled sale, unless already closed */
+func recoverSell(
+	sessionData *types.Session,
+	order *types.Order,
+	orderIDSource int64) (err error) {
+
+	price := order.CumulativeQuoteQuantity / order.ExecutedQuantity
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		price = 0
+	}
+
+	if err = mysql.SaveOrder(sessionData, order, orderIDSource, price); err != nil { /* Saved before the thread stopped */
+
+		if err = mysql.UpdateOrder(sessionData, order.OrderID, order.CumulativeQuoteQuantity, order.ExecutedQuantity, price, order.Status); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	if order.Status != "FILLED" { /* Canceled sales keep the transaction open */
+
+		return cycle.Transition(sessionData, cycle.PositionOpen, 0, 0)
+
+	}
+
+	if open, err := openTransaction(sessionData, orderIDSource); err != nil {
+
+		return err
+
+	} else if open {
+
+		if err = mysql.DeleteThreadTransactionByOrderID(sessionData, orderIDSource); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return cycle.Transition(sessionData, cycle.Closed, 0, 0)
+
+}
+
+/* Return true when orderID is an open transaction of the thread */
+func openTransaction(
+	sessionData *types.Session,
+	orderID int64) (bool, error) {
+
+	orders, err := mysql.GetThreadTransactionByThreadID(sessionData)
+	if err != nil {
+
+		return false, err
+
+	}
+
+	for _, order := range orders {
+
+		if order.OrderID == orderID {
+
+			return true, nil
+
+		}
+
+	}
+
+	return false, nil
+
+}
+
+/* Log the recovery of a trade cycle and save it in the thread timeline */
+func recoveredCycle(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	orderID int64,
+	logLevel string,
+	message string) {
+
+	_ = mysql.SaveThreadEvent(sessionData, "cycle", "recovery", message) /* Thread timeline */
+
+	logger.LogEntry{ /* Log Entry */
+		Config:  configData,
+		Market:  marketData,
+		Session: sessionData,
+		Order: &types.Order{
+			OrderID: orderID,
+		},
+		Message:  message,
+		LogLevel: logLevel,
+	}.Do()
+
+}
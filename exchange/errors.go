@@ -0,0 +1,72 @@
+package exchange
+
+/* Exchange error taxonomy. The errors of the exchange order calls are classified into the sentinel errors below (and
+ErrLotSize and ErrMinNotional of the pre-trade validation for the filter failures), so callers branch with errors.Is
+(i.e. errors.Is(err, ErrRateLimited)) instead of matching the error text. The classified error keeps the text of the
+exchange error, and errors.As still reaches it. */
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+/* Exchange errors */
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrOrderNotFound     = errors.New("order not found") /* Unknown order, i.e. filled in full before cancelling */
+	ErrTimestamp         = errors.New("timestamp outside of the recvWindow")
+	ErrUnauthorized      = errors.New("unauthorized")         /* Rejected API key, signature or permission */
+	ErrUnavailable       = errors.New("exchange unavailable") /* Network error or exchange outage */
+)
+
+const rateLimitBackoff = time.Minute /* Orders paused after the exchange rate limited the thread */
+
+/* Exchange error classified as kind */
+type classifiedError struct {
+	kind error /* One of the exchange errors */
+	err  error /* Exchange client error */
+}
+
+func (e *classifiedError) Error() string {
+
+	return e.err.Error()
+
+}
+
+func (e *classifiedError) Unwrap() error {
+
+	return e.err
+
+}
+
+func (e *classifiedError) Is(target error) bool {
+
+	return target == e.kind
+
+}
+
+/* Return err as kind, or err when kind is nil */
+func classified(
+	kind error,
+	err error) error {
+
+	if kind == nil {
+
+		return err
+
+	}
+
+	return &classifiedError{kind: kind, err: err}
+
+}
+
+/* Return true when err is a network error, the exchange couldn't be reached */
+func isNetworkError(err error) bool {
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+
+}
@@ -6,10 +6,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/events"
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
 	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/perf"
+	"github.com/aleibovici/cryptopump/shadow"
 	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/tracing"
 	"github.com/aleibovici/cryptopump/types"
 )
 
@@ -18,6 +25,9 @@ func GetClient(
 	configData *types.Config,
 	sessionData *types.Session) (err error) {
 
+	ConfigureStats(configData)   /* Slow call threshold of the REST calls stats */
+	ConfigureLimiter(configData) /* Concurrency limit of the REST calls of all the threads of the process */
+
 	switch strings.ToLower(configData.ExchangeName) {
 	case "binance":
 
@@ -36,6 +46,12 @@ func GetOrder(
 	sessionData *types.Session,
 	orderID int64) (order *types.Order, err error) {
 
+	span := tracing.Start(sessionData, "exchange.GetOrder") /* Traced when processing a trade decision */
+	defer func() {
+		err = classify(configData, err)
+		span.End(err)
+	}()
+
 	switch strings.ToLower(configData.ExchangeName) {
 	case "binance":
 
@@ -53,6 +69,13 @@ func BuyOrder(
 	sessionData *types.Session,
 	quantity string) (order *types.Order, err error) {
 
+	span := tracing.Start(sessionData, "exchange.BuyOrder") /* Traced when processing a trade decision */
+	defer func() {
+		err = classify(configData, err)
+		span.End(err)
+		perf.Acknowledge(sessionData.ThreadID, time.Now(), err) /* Trade signal to order acknowledgment latency */
+	}()
+
 	switch strings.ToLower(configData.ExchangeName) {
 	case "binance":
 
@@ -64,6 +87,44 @@ func BuyOrder(
 
 }
 
+// QuoteOrder Create MARKET order for any symbol sized by quote (fiat) quantity
+func QuoteOrder(
+	configData *types.Config,
+	sessionData *types.Session,
+	symbol string,
+	side string,
+	quoteQuantity string) (order *types.Order, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceQuoteOrder(sessionData, symbol, side, quoteQuantity)
+
+	}
+
+	return
+
+}
+
+// ManualOrder Create manual order for sessionData.Symbol, MARKET when price is empty or LIMIT IOC at price
+func ManualOrder(
+	configData *types.Config,
+	sessionData *types.Session,
+	side string,
+	quantity string,
+	price string) (order *types.Order, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceManualOrder(sessionData, side, quantity, price)
+
+	}
+
+	return
+
+}
+
 // SellOrder Create order to SELL
 func SellOrder(
 	configData *types.Config,
@@ -71,6 +132,13 @@ func SellOrder(
 	sessionData *types.Session,
 	quantity string) (order *types.Order, err error) {
 
+	span := tracing.Start(sessionData, "exchange.SellOrder") /* Traced when processing a trade decision */
+	defer func() {
+		err = classify(configData, err)
+		span.End(err)
+		perf.Acknowledge(sessionData.ThreadID, time.Now(), err) /* Trade signal to order acknowledgment latency */
+	}()
+
 	switch strings.ToLower(configData.ExchangeName) {
 	case "binance":
 
@@ -88,6 +156,12 @@ func CancelOrder(
 	sessionData *types.Session,
 	orderID int64) (order *types.Order, err error) {
 
+	span := tracing.Start(sessionData, "exchange.CancelOrder") /* Traced when processing a trade decision */
+	defer func() {
+		err = classify(configData, err)
+		span.End(err)
+	}()
+
 	switch strings.ToLower(configData.ExchangeName) {
 	case "binance":
 
@@ -125,6 +199,11 @@ func GetLotSize(
 		sessionData.MaxQuantity = functions.StrToFloat64(info.MaxQuantity)
 		sessionData.MinQuantity = functions.StrToFloat64(info.MinQuantity)
 		sessionData.StepSize = functions.StrToFloat64(info.StepSize)
+		sessionData.MinNotional = functions.StrToFloat64(info.MinNotional)
+
+		if info.MaxNumOrders != "" { /* Filter not defined for every symbol */
+			sessionData.MaxNumOrders = functions.StrToInt(info.MaxNumOrders)
+		}
 
 		return
 
@@ -132,6 +211,70 @@ func GetLotSize(
 
 }
 
+// GetTradePermission Retrieve account permission to place orders
+func GetTradePermission(
+	configData *types.Config,
+	sessionData *types.Session) (canTrade bool, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceGetTradePermission(sessionData)
+
+	}
+
+	return
+
+}
+
+// GetOpenOrderCount Retrieve number of open orders for sessionData.Symbol
+func GetOpenOrderCount(
+	configData *types.Config,
+	sessionData *types.Session) (count int, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceGetOpenOrderCount(sessionData)
+
+	}
+
+	return
+
+}
+
+// GetCommission Retrieve the account commission rate per order as ratio
+func GetCommission(
+	configData *types.Config,
+	sessionData *types.Session) (commission float64, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceGetCommission(sessionData)
+
+	}
+
+	return
+
+}
+
+// CancelOpenOrders CANCEL all open orders for sessionData.Symbol
+func CancelOpenOrders(
+	configData *types.Config,
+	sessionData *types.Session) (count int, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceCancelOpenOrders(sessionData)
+
+	}
+
+	return
+
+}
+
 // GetSymbolFiatFunds Retrieve symbol fiat funds available
 func GetSymbolFiatFunds(
 	configData *types.Config,
@@ -164,6 +307,55 @@ func GetSymbolFunds(
 
 }
 
+// GetBalances Retrieve free balances for all assets
+func GetBalances(
+	configData *types.Config,
+	sessionData *types.Session) (balances map[string]float64, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceGetBalances(sessionData)
+
+	}
+
+	return
+
+}
+
+// GetAccountBalances Retrieve free and locked balances of the assets held in the exchange account
+func GetAccountBalances(
+	configData *types.Config,
+	sessionData *types.Session) (balances []types.Balance, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceGetAccountBalances(sessionData)
+
+	}
+
+	return
+
+}
+
+// GetSymbolPrice Retrieve latest price for any symbol
+func GetSymbolPrice(
+	configData *types.Config,
+	sessionData *types.Session,
+	symbol string) (price float64, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceGetSymbolPrice(sessionData, symbol)
+
+	}
+
+	return
+
+}
+
 // GetKlines Retrieve KLines via REST API
 func GetKlines(
 	configData *types.Config,
@@ -186,6 +378,30 @@ func GetKlines(
 
 }
 
+// GetSymbolKlines Retrieve the last limit hourly KLines for any symbol via REST API
+func GetSymbolKlines(
+	configData *types.Config,
+	sessionData *types.Session,
+	symbol string,
+	limit int) (klines []*types.Kline, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		tmp, err := binanceGetSymbolKlines(sessionData, symbol, limit)
+
+		if err == nil {
+			return binanceMapKline(tmp), err
+		}
+
+		return nil, err
+
+	}
+
+	return
+
+}
+
 // GetPriceChangeStats Retrieve 24hs Rolling Price Statistics
 func GetPriceChangeStats(
 	configData *types.Config,
@@ -203,6 +419,22 @@ func GetPriceChangeStats(
 
 }
 
+// GetOrderBook Retrieve order book depth via REST API
+func GetOrderBook(
+	configData *types.Config,
+	sessionData *types.Session) (orderBook *types.OrderBook, err error) {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceGetOrderBook(sessionData)
+
+	}
+
+	return
+
+}
+
 /* Calculate the correct quantity to SELL according to the exchange lotSizeStep */
 func getSellQuantity(
 	order types.Order,
@@ -230,7 +462,10 @@ func GetUserStreamServiceListenKey(
 	switch strings.ToLower(configData.ExchangeName) {
 	case "binance":
 
-		return binanceGetUserStreamServiceListenKey(sessionData)
+		listenKey, err = binanceGetUserStreamServiceListenKey(sessionData)
+		notifyAuthError(configData, sessionData, err)
+
+		return listenKey, err
 
 	}
 
@@ -246,7 +481,10 @@ func KeepAliveUserStreamServiceListenKey(
 	switch strings.ToLower(configData.ExchangeName) {
 	case "binance":
 
-		return binanceKeepAliveUserStreamServiceListenKey(sessionData)
+		err = binanceKeepAliveUserStreamServiceListenKey(sessionData)
+		notifyAuthError(configData, sessionData, err)
+
+		return err
 
 	}
 
@@ -342,19 +580,50 @@ func BuyTicker(
 		sessionData.Busy = false
 	}()
 
-	/* Exit if DryRun mode set to true */
+	/* Simulate the buy in the shadow orders and exit if DryRun mode set to true */
 	if configData.DryRun {
 
-		logger.LogEntry{ /* Log Entry */
-			Config:  configData,
-			Market:  marketData,
-			Session: sessionData,
-			Order: &types.Order{
-				Price: marketData.Price,
-			},
-			Message:  "BUYDRYRUN",
-			LogLevel: "InfoLevel",
-		}.Do()
+		if _, err := shadow.Record(configData, marketData, sessionData, "BUY", getBuyQuantity(marketData, sessionData, quantity), types.Order{}); err == nil {
+
+			sessionData.LastBuyTransactTime = time.Now() /* Simulated buys wait configData.BuyWait as buys do */
+
+		}
+
+		return
+
+	}
+
+	buyQuantity := functions.Float64ToStr(getBuyQuantity(marketData, sessionData, quantity), 4) /* Get the correct quantity according to lotSizeMin and lotSizeStep */
+
+	/* Pre-trade sanity checks, avoid orders the exchange would reject */
+	if err := ValidateOrder(
+		configData,
+		sessionData,
+		"BUY",
+		functions.StrToFloat64(buyQuantity),
+		marketData.Price); err != nil {
+
+		rejectOrder(err, configData, marketData, sessionData)
+
+		return
+
+	}
+
+	/* Risk limits of every buy, including Force Buy and the buys of the SELL decision tree */
+	if err := checkBuy(
+		configData,
+		sessionData,
+		quantity); err != nil {
+
+		sessionData.BuyDecisionTreeResult = err.Error()
+		rejectOrder(err, configData, marketData, sessionData)
+
+		return
+
+	}
+
+	/* Persist the buy in flight before sending it, so a thread stopping before the response can be recovered */
+	if err := cycle.Transition(sessionData, cycle.BuyPlaced, 0, 0); err != nil {
 
 		return
 
@@ -363,27 +632,49 @@ func BuyTicker(
 	orderResponse, err := BuyOrder(
 		configData,
 		sessionData,
-		functions.Float64ToStr(getBuyQuantity(marketData, sessionData, quantity), 4)) /* Get the correct quantity according to lotSizeMin and lotSizeStep */
+		buyQuantity)
 
 	/* Test orderResponse for  errors */
 	if (orderResponse == nil && err != nil) ||
 		(orderResponse == nil && err == nil) {
 
+		_ = cycle.Transition(sessionData, cycle.BuyFailed, 0, 0) /* Settled by the next trade decision */
+
 		switch {
-		case strings.Contains(err.Error(), "1013"):
-			/* <APIError> code=-1013, msg=Filter failure: LOT_SIZE */
+		case errors.Is(err, ErrLotSize), errors.Is(err, ErrMinNotional):
 
 			/* Retrieve exchange lot size for ticker and store in sessionData */
 			GetLotSize(configData, sessionData)
 
 			return
 
+		case errors.Is(err, ErrRateLimited):
+
+			backoff(configData, marketData, sessionData, err)
+
+			return
+
+		case errors.Is(err, ErrInsufficientFunds):
+
+			/* Refresh the fiat funds so the next buy decisions use the funds available */
+			if funds, err := GetSymbolFiatFunds(configData, sessionData); err == nil {
+				sessionData.SymbolFiatFunds = funds
+			}
+
+		case err == nil:
+
+			err = ErrUnavailable /* No order and no error */
+
 		}
 
+		notifyOrderError(configData, sessionData, "BUY", err)
+
 		return
 
 	}
 
+	_ = cycle.Transition(sessionData, cycle.BuyPlaced, orderResponse.OrderID, 0) /* Order acknowledged by the exchange */
+
 	/* Check if result is nil and set as zero */
 	if orderPrice = orderResponse.CumulativeQuoteQuantity / orderResponse.ExecutedQuantity; math.IsNaN(orderPrice) {
 		orderPrice = 0
@@ -403,6 +694,15 @@ func BuyTicker(
 
 	}
 
+	publishOrder(configData, sessionData, events.OrderPlaced, orderResponse.OrderID, "BUY", orderPrice, functions.StrToFloat64(buyQuantity), orderResponse.Status)
+
+	/* Persist the weighted indicator score that triggered the order for audit */
+	if configData.BuyScoreThreshold > 0 {
+
+		_ = mysql.UpdateOrderScore(sessionData, orderResponse.OrderID, sessionData.BuyScore)
+
+	}
+
 	/* This session variable stores the time of the last buy */
 	sessionData.LastBuyTransactTime = time.Now()
 
@@ -476,6 +776,8 @@ S:
 
 		}
 
+		_ = cycle.Transition(sessionData, cycle.PositionOpen, 0, 0)
+
 		logger.LogEntry{ /* Log Entry */
 			Config:  configData,
 			Market:  marketData,
@@ -488,8 +790,12 @@ S:
 			LogLevel: "InfoLevel",
 		}.Do()
 
+		publishOrder(configData, sessionData, events.OrderFilled, orderResponse.OrderID, "BUY", orderPrice, orderExecutedQuantity, "FILLED")
+
 	} else if isCanceled {
 
+		_ = cycle.Resolve(sessionData) /* Back to the open transactions before the buy */
+
 		logger.LogEntry{ /* Log Entry */
 			Config:  configData,
 			Market:  marketData,
@@ -527,19 +833,33 @@ func SellTicker(
 		sessionData.Busy = false
 	}()
 
-	/* Exit if DryRun mode set to true */
+	/* Simulate the sale in the shadow orders and exit if DryRun mode set to true */
 	if configData.DryRun {
 
-		logger.LogEntry{ /* Log Entry */
-			Config:  configData,
-			Market:  marketData,
-			Session: sessionData,
-			Order: &types.Order{
-				Price: marketData.Price,
-			},
-			Message:  "SELLDRYRUN",
-			LogLevel: "InfoLevel",
-		}.Do()
+		_, _ = shadow.Record(configData, marketData, sessionData, "SELL", getSellQuantity(order, sessionData), order)
+
+		return
+
+	}
+
+	sellQuantity := functions.Float64ToStr(getSellQuantity(order, sessionData), 6) /* Get correct quantity to sell according to the lotSizeStep */
+
+	/* Pre-trade sanity checks, avoid orders the exchange would reject */
+	if err = ValidateOrder(
+		configData,
+		sessionData,
+		"SELL",
+		functions.StrToFloat64(sellQuantity),
+		marketData.Price); err != nil {
+
+		rejectOrder(err, configData, marketData, sessionData)
+
+		return
+
+	}
+
+	/* Persist the sale in flight before sending it, so a thread stopping before the response can be recovered */
+	if err = cycle.Transition(sessionData, cycle.SellPlaced, 0, order.OrderID); err != nil {
 
 		return
 
@@ -549,12 +869,18 @@ func SellTicker(
 		configData,
 		marketData,
 		sessionData,
-		functions.Float64ToStr(getSellQuantity(order, sessionData), 6) /* Get correct quantity to sell according to the lotSizeStep */)
+		sellQuantity)
 
 	/* Test orderResponse for  errors */
 	if (orderResponse == nil && err != nil) ||
 		(orderResponse == nil && err == nil) {
 
+		if err == nil {
+			err = ErrUnavailable /* No order and no error */
+		}
+
+		_ = cycle.Transition(sessionData, cycle.SellFailed, 0, 0) /* Settled by the next trade decision */
+
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
 			Market:   marketData,
@@ -564,10 +890,22 @@ func SellTicker(
 			LogLevel: "DebugLevel",
 		}.Do()
 
+		if errors.Is(err, ErrRateLimited) {
+
+			backoff(configData, marketData, sessionData, err)
+
+			return
+
+		}
+
+		notifyOrderError(configData, sessionData, "SELL", err)
+
 		return
 
 	}
 
+	_ = cycle.Transition(sessionData, cycle.SellPlaced, orderResponse.OrderID, order.OrderID) /* Order acknowledged by the exchange */
+
 	/* Save order to database */
 	if err := mysql.SaveOrder(
 		sessionData,
@@ -580,6 +918,8 @@ func SellTicker(
 
 	}
 
+	publishOrder(configData, sessionData, events.OrderPlaced, orderResponse.OrderID, "SELL", marketData.Price, functions.StrToFloat64(sellQuantity), orderResponse.Status)
+
 S:
 	switch orderResponse.Status {
 	case "FILLED":
@@ -631,10 +971,10 @@ S:
 					int64(orderResponse.OrderID)); err != nil {
 
 					switch {
-					case strings.Contains(err.Error(), "-2010"), strings.Contains(err.Error(), "-2011"), strings.Contains(err.Error(), "-1021"):
-						/* -2011 Order filled in full before cancelling */
-						/* -2010 Account has insufficient balance for requested action */
-						/* -1021 Timestamp for this request was 1000ms ahead of the server's time */
+					case errors.Is(err, ErrOrderNotFound), errors.Is(err, ErrInsufficientFunds), errors.Is(err, ErrTimestamp):
+						/* Order filled in full before cancelling */
+						/* Account has insufficient balance for requested action */
+						/* Timestamp for this request was 1000ms ahead of the server's time */
 
 						if orderStatus, err = GetOrder(
 							configData,
@@ -648,7 +988,7 @@ S:
 
 						break F
 
-					case strings.Contains(err.Error(), "connection reset by peer"):
+					case errors.Is(err, ErrUnavailable):
 						/* read tcp 192.168.110.110:54914->65.9.137.130:443: read: connection reset by peer */
 
 						if orderStatus, err = GetOrder(
@@ -756,6 +1096,8 @@ S:
 
 		}
 
+		_ = cycle.Transition(sessionData, cycle.Closed, 0, 0)
+
 		logger.LogEntry{ /* Log Entry */
 			Config:  configData,
 			Market:  marketData,
@@ -769,8 +1111,21 @@ S:
 			LogLevel: "InfoLevel",
 		}.Do()
 
+		publishOrder(configData, sessionData, events.OrderFilled, orderResponse.OrderID, "SELL", marketData.Price, functions.StrToFloat64(sellQuantity), "FILLED")
+
+		if profits, _, err := mysql.GetThreadCycleProfitLast(sessionData, 1); err == nil && len(profits) > 0 {
+
+			events.Publish(configData, sessionData, events.ProfitRealized, events.Profit{
+				Symbol: sessionData.Symbol,
+				Profit: profits[0],
+			})
+
+		}
+
 	} else if isCanceled {
 
+		_ = cycle.Transition(sessionData, cycle.PositionOpen, 0, 0) /* The transaction sold remains open */
+
 		logger.LogEntry{ /* Log Entry */
 			Config:  configData,
 			Market:  marketData,
@@ -787,3 +1142,145 @@ S:
 	}
 
 }
+
+/* Publish an exchange order error as a failed order and an error event */
+func notifyOrderError(
+	configData *types.Config,
+	sessionData *types.Session,
+	side string,
+	err error) {
+
+	events.Publish(configData, sessionData, events.OrderFailed, events.Order{
+		Side:   side,
+		Symbol: sessionData.Symbol,
+		Err:    err,
+	})
+
+	notifyAuthError(configData, sessionData, err)
+
+	events.Publish(configData, sessionData, events.ErrorRaised, events.Error{
+		Symbol:  sessionData.Symbol,
+		Side:    side,
+		Message: side + " order failed: " + err.Error(),
+		Key:     "exchange-" + sessionData.ThreadID + "-" + side,
+		Title:   "Exchange error " + sessionData.Symbol,
+	})
+
+}
+
+/* Send an exchange authentication failure as a critical error notification */
+func notifyAuthError(
+	configData *types.Config,
+	sessionData *types.Session,
+	err error) {
+
+	if !errors.Is(err, ErrUnauthorized) {
+
+		return
+
+	}
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Critical,
+		Key:      "exchange-auth",
+		Title:    "Exchange authentication failed",
+		Data: messages.Data{
+			ThreadID: sessionData.ThreadID,
+			Symbol:   sessionData.Symbol,
+			Message:  "Exchange authentication failed: " + err.Error(),
+		},
+	}.Send(configData, sessionData)
+
+}
+
+/* Return err classified into the exchange errors */
+func classify(
+	configData *types.Config,
+	err error) error {
+
+	switch strings.ToLower(configData.ExchangeName) {
+	case "binance":
+
+		return binanceError(err)
+
+	}
+
+	return err
+
+}
+
+/* Pause the orders of the thread for rateLimitBackoff after the exchange rate limited it */
+func backoff(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	err error) {
+
+	sessionData.RateLimitedUntil = time.Now().Add(rateLimitBackoff)
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   marketData,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Orders paused for " + rateLimitBackoff.String() + " - " + err.Error(),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+}
+
+/* Publish an order event, filled orders with the running profit of the thread */
+func publishOrder(
+	configData *types.Config,
+	sessionData *types.Session,
+	topic string,
+	orderID int64,
+	side string,
+	price float64,
+	quantity float64,
+	status string) {
+
+	order := events.Order{
+		OrderID:  orderID,
+		Side:     side,
+		Symbol:   sessionData.Symbol,
+		Price:    price,
+		Quantity: quantity,
+		Status:   status,
+	}
+
+	if topic == events.OrderFilled {
+
+		order.Profit, order.ProfitPct, _ = mysql.GetProfitByThreadID(sessionData) /* Errors are logged by mysql */
+
+	}
+
+	events.Publish(configData, sessionData, topic, order)
+
+}
+
+/* Log an order rejected by pre-trade validation */
+func rejectOrder(
+	err error,
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) {
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   marketData,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Pre-trade validation failed - " + err.Error(),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	/* Exchange filters may have changed, reload them for the next order */
+	if errors.Is(err, ErrLotSize) || errors.Is(err, ErrMinNotional) {
+
+		GetLotSize(configData, sessionData)
+
+	}
+
+}
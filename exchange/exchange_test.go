@@ -1,9 +1,14 @@
 package exchange
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
 	"reflect"
 	"testing"
 
+	"github.com/adshao/go-binance/v2/common"
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
 	"github.com/aleibovici/cryptopump/types"
@@ -364,3 +369,327 @@ func Test_getBuyQuantity(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateOrder(t *testing.T) {
+	limits := orderLimits{
+		CanTrade:     true,
+		OpenOrders:   1,
+		MaxNumOrders: 200,
+		MinQuantity:  0.0001,
+		MaxQuantity:  9000,
+		StepSize:     0.0001,
+		MinNotional:  10,
+		FreeBalance:  100,
+	}
+	type args struct {
+		side     string
+		quantity float64
+		price    float64
+		limits   orderLimits
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "success",
+			args: args{
+				side:     "BUY",
+				quantity: 0.0012,
+				price:    40000,
+				limits:   limits,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "trade permission",
+			args: args{
+				side:     "BUY",
+				quantity: 0.0012,
+				price:    40000,
+				limits: func() orderLimits {
+					l := limits
+					l.CanTrade = false
+					return l
+				}(),
+			},
+			wantErr: ErrTradePermission,
+		},
+		{
+			name: "open orders",
+			args: args{
+				side:     "BUY",
+				quantity: 0.0012,
+				price:    40000,
+				limits: func() orderLimits {
+					l := limits
+					l.OpenOrders = 200
+					return l
+				}(),
+			},
+			wantErr: ErrMaxNumOrders,
+		},
+		{
+			name: "lot size step",
+			args: args{
+				side:     "BUY",
+				quantity: 0.00125,
+				price:    40000,
+				limits:   limits,
+			},
+			wantErr: ErrLotSize,
+		},
+		{
+			name: "min notional",
+			args: args{
+				side:     "BUY",
+				quantity: 0.0002,
+				price:    40000,
+				limits:   limits,
+			},
+			wantErr: ErrMinNotional,
+		},
+		{
+			name: "insufficient fiat",
+			args: args{
+				side:     "BUY",
+				quantity: 0.003,
+				price:    40000,
+				limits:   limits,
+			},
+			wantErr: ErrInsufficientFunds,
+		},
+		{
+			name: "insufficient symbol",
+			args: args{
+				side:     "SELL",
+				quantity: 0.0012,
+				price:    40000,
+				limits: func() orderLimits {
+					l := limits
+					l.FreeBalance = 0.001
+					return l
+				}(),
+			},
+			wantErr: ErrInsufficientFunds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOrder(tt.args.side, tt.args.quantity, tt.args.price, tt.args.limits)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("validateOrder() error = %v, wantErr nil", err)
+				return
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkBuy(t *testing.T) {
+	errLimit := errors.New("limit")
+	defer func(checks []BuyCheck) { buyChecks = checks }(buyChecks)
+	buyChecks = nil
+	RegisterBuyCheck(func(configData *types.Config, sessionData *types.Session, buyQuantityFiat float64) error {
+		if buyQuantityFiat > 100 {
+			return errLimit
+		}
+		return nil
+	})
+	tests := []struct {
+		name            string
+		buyQuantityFiat float64
+		wantErr         error
+	}{
+		{
+			name:            "accepted",
+			buyQuantityFiat: 50,
+			wantErr:         nil,
+		},
+		{
+			name:            "rejected",
+			buyQuantityFiat: 150,
+			wantErr:         errLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkBuy(configData, sessionData, tt.buyQuantityFiat); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkBuy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_minimumSellPrice(t *testing.T) {
+	type args struct {
+		entryPrice   float64
+		commission   float64
+		profitNetMin float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "no commission",
+			args: args{
+				entryPrice:   100,
+				commission:   0,
+				profitNetMin: 0.01,
+			},
+			want: 101,
+		},
+		{
+			name: "break even",
+			args: args{
+				entryPrice:   100,
+				commission:   0.001,
+				profitNetMin: 0,
+			},
+			want: 100.2002002002002,
+		},
+		{
+			name: "net profit",
+			args: args{
+				entryPrice:   100,
+				commission:   0.001,
+				profitNetMin: 0.005,
+			},
+			want: 100.70120120120121,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := minimumSellPrice(tt.args.entryPrice, tt.args.commission, tt.args.profitNetMin); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("minimumSellPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseManualOrder(t *testing.T) {
+	type args struct {
+		side     string
+		quantity string
+		price    string
+		stepSize float64
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantSide     string
+		wantQuantity float64
+		wantPrice    float64
+		wantErr      error
+	}{
+		{
+			name:         "market buy",
+			args:         args{side: "buy", quantity: "0.0015", price: "", stepSize: 0.001},
+			wantSide:     "BUY",
+			wantQuantity: 0.001,
+			wantPrice:    0,
+			wantErr:      nil,
+		},
+		{
+			name:         "limit sell",
+			args:         args{side: "SELL", quantity: "0.25", price: "41000.5", stepSize: 0.0001},
+			wantSide:     "SELL",
+			wantQuantity: 0.25,
+			wantPrice:    41000.5,
+			wantErr:      nil,
+		},
+		{
+			name:    "invalid side",
+			args:    args{side: "HOLD", quantity: "1", price: "", stepSize: 0.001},
+			wantErr: ErrManualSide,
+		},
+		{
+			name:    "below lot size step",
+			args:    args{side: "BUY", quantity: "0.0005", price: "", stepSize: 0.001},
+			wantErr: ErrManualQuantity,
+		},
+		{
+			name:    "invalid quantity",
+			args:    args{side: "BUY", quantity: "abc", price: "", stepSize: 0.001},
+			wantErr: ErrManualQuantity,
+		},
+		{
+			name:    "negative price",
+			args:    args{side: "BUY", quantity: "1", price: "-1", stepSize: 0.001},
+			wantErr: ErrManualPrice,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSide, gotQuantity, gotPrice, err := ParseManualOrder(tt.args.side, tt.args.quantity, tt.args.price, tt.args.stepSize)
+			if err != tt.wantErr {
+				t.Errorf("ParseManualOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotSide != tt.wantSide || math.Abs(gotQuantity-tt.wantQuantity) > 1e-9 || gotPrice != tt.wantPrice {
+				t.Errorf("ParseManualOrder() = %v, %v, %v, want %v, %v, %v", gotSide, gotQuantity, gotPrice, tt.wantSide, tt.wantQuantity, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func Test_binanceIsAuthError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "invalid api key", err: &common.APIError{Code: -2015, Message: "Invalid API-key, IP, or permissions for action."}, want: true},
+		{name: "wrapped invalid signature", err: fmt.Errorf("order: %w", &common.APIError{Code: -1022, Message: "Signature for this request is not valid."}), want: true},
+		{name: "filter failure", err: &common.APIError{Code: -1013, Message: "Filter failure: LOT_SIZE"}, want: false},
+		{name: "not an api error", err: errors.New("connection refused"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := binanceIsAuthError(tt.err); got != tt.want {
+				t.Errorf("binanceIsAuthError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_binanceError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "rate limited", err: &common.APIError{Code: -1003, Message: "Too many requests."}, want: ErrRateLimited},
+		{name: "insufficient funds", err: &common.APIError{Code: -2010, Message: "Account has insufficient balance for requested action."}, want: ErrInsufficientFunds},
+		{name: "lot size", err: &common.APIError{Code: -1013, Message: "Filter failure: LOT_SIZE"}, want: ErrLotSize},
+		{name: "min notional", err: fmt.Errorf("order: %w", &common.APIError{Code: -1013, Message: "Filter failure: MIN_NOTIONAL"}), want: ErrMinNotional},
+		{name: "unknown order", err: &common.APIError{Code: -2011, Message: "Unknown order sent."}, want: ErrOrderNotFound},
+		{name: "unauthorized", err: &common.APIError{Code: -2015, Message: "Invalid API-key, IP, or permissions for action."}, want: ErrUnauthorized},
+		{name: "network", err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, want: ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := binanceError(tt.err)
+			if !errors.Is(got, tt.want) || got.Error() != tt.err.Error() {
+				t.Errorf("binanceError() = %v, want %v", got, tt.want)
+			}
+			var apiErr *common.APIError
+			if errors.As(tt.err, &apiErr) && !errors.As(got, &apiErr) {
+				t.Errorf("binanceError() = %v, doesn't wrap the API error", got)
+			}
+		})
+	}
+
+	if err := (&common.APIError{Code: -1100, Message: "Illegal characters found in a parameter."}); binanceError(err) != err {
+		t.Errorf("binanceError() classified an unknown error")
+	}
+
+	if binanceError(nil) != nil {
+		t.Errorf("binanceError(nil) != nil")
+	}
+}
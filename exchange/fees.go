@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// LoadCommission load the account commission rate per order from the exchange into sessionData.Commission
+func LoadCommission(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	commission, err := GetCommission(configData, sessionData)
+
+	if err != nil {
+
+		return
+
+	}
+
+	if commission != sessionData.Commission {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  fmt.Sprintf("Account commission rate %.4f%% per order", commission*100),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+	sessionData.Commission = commission
+
+}
+
+// CommissionRate return the account commission rate per order, or configData.ExchangeComission until it is loaded from the exchange
+func CommissionRate(
+	configData *types.Config,
+	sessionData *types.Session) float64 {
+
+	if sessionData.Commission > 0 {
+
+		return sessionData.Commission
+
+	}
+
+	return configData.ExchangeComission
+
+}
+
+// MinimumSellPrice return the lowest sell price for an order bought at entryPrice that covers round-trip
+// commissions and still returns configData.ProfitNetMin as net profit
+func MinimumSellPrice(
+	configData *types.Config,
+	sessionData *types.Session,
+	entryPrice float64) float64 {
+
+	return minimumSellPrice(entryPrice, CommissionRate(configData, sessionData), configData.ProfitNetMin)
+
+}
+
+// minimumSellPrice return the lowest sell price meeting profitNetMin net of commission. Buy cost is
+// entryPrice*(1+commission) and sell proceeds are price*(1-commission) per unit, so the break-even price plus net
+// profit is entryPrice*(1+commission)*(1+profitNetMin)/(1-commission)
+func minimumSellPrice(
+	entryPrice float64,
+	commission float64,
+	profitNetMin float64) float64 {
+
+	return entryPrice * (1 + commission) * (1 + profitNetMin) / (1 - commission)
+
+}
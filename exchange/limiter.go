@@ -0,0 +1,234 @@
+package exchange
+
+/* Process-wide concurrency limiter of the exchange REST calls. Threads running in one process share the exchange
+rate limits, so bursts of simultaneous calls (i.e. every thread checking balances at once) trip them. Every REST call
+made by the exchange client takes a slot from the HTTP transport before it is sent, and at most ExchangeConcurrency
+calls are in progress at once. Calls over the limit wait in a lane by priority: order placement first, then balance
+and order checks, then market data and stats. A freed slot goes to the oldest call of the highest priority lane. */
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aleibovici/cryptopump/metrics"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// Priority lanes of the exchange REST calls, highest priority first
+const (
+	LaneOrder   = iota /* Order placement and cancellation */
+	LaneBalance        /* Account balances, commission, order status and user data stream */
+	LaneStats          /* Market data, exchange information and stats */
+	lanes
+)
+
+// LaneNames are the names of the priority lanes of the exchange REST calls
+var LaneNames = [lanes]string{"order", "balance", "stats"}
+
+// LimiterStats struct define the exchange REST calls in progress and waiting for a slot
+type LimiterStats struct {
+	Limit   int        /* ExchangeConcurrency, 0 without limit */
+	Active  int        /* Calls in progress holding a slot */
+	Waiting [lanes]int /* Calls waiting for a slot by lane */
+}
+
+var limiter = struct {
+	sync.Mutex
+	limit   int                    /* ExchangeConcurrency, 0 disables the limit */
+	active  int                    /* Slots taken */
+	waiting [lanes][]chan struct{} /* Calls waiting for a slot by lane, oldest first */
+}{}
+
+// ConfigureLimiter apply the exchange concurrency limit after a configuration reload, granting the slots added to the
+// calls waiting
+func ConfigureLimiter(configData *types.Config) {
+
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	limiter.limit = 0
+
+	if configData.ConfigGlobal != nil && configData.ConfigGlobal.ExchangeConcurrency > 0 {
+
+		limiter.limit = configData.ConfigGlobal.ExchangeConcurrency
+
+	}
+
+	for limiter.active < limiter.limit || limiter.limit == 0 {
+
+		if !grant() {
+
+			break
+
+		}
+
+		limiter.active++
+
+	}
+
+}
+
+// Acquire take a slot for an exchange REST call of lane, waiting for a slot while ExchangeConcurrency calls are in
+// progress, and return the function releasing it. Returns the context error when ctx is done before a slot is free.
+func Acquire(
+	ctx context.Context,
+	lane int) (release func(), err error) {
+
+	limiter.Lock()
+
+	if limiter.limit == 0 { /* No limit, the call takes no slot */
+
+		limiter.Unlock()
+
+		return func() {}, nil
+
+	}
+
+	if limiter.active < limiter.limit {
+
+		limiter.active++
+		limiter.Unlock()
+
+		return releaseOnce(), nil
+
+	}
+
+	ready := make(chan struct{})
+	limiter.waiting[lane] = append(limiter.waiting[lane], ready)
+	waitingChanged(lane)
+	limiter.Unlock()
+
+	select {
+	case <-ready:
+
+		return releaseOnce(), nil
+
+	case <-ctx.Done():
+
+		limiter.Lock()
+		defer limiter.Unlock()
+
+		for i, waiting := range limiter.waiting[lane] {
+
+			if waiting == ready { /* Still waiting, leave the lane */
+
+				limiter.waiting[lane] = append(limiter.waiting[lane][:i], limiter.waiting[lane][i+1:]...)
+				waitingChanged(lane)
+
+				return nil, ctx.Err()
+
+			}
+
+		}
+
+		releaseSlot() /* Granted while ctx was done, hand the slot over */
+
+		return nil, ctx.Err()
+
+	}
+
+}
+
+// ReadLimiter return the exchange REST calls in progress and waiting for a slot
+func ReadLimiter() (stats LimiterStats) {
+
+	limiter.Lock()
+	defer limiter.Unlock()
+
+	stats.Limit = limiter.limit
+	stats.Active = limiter.active
+
+	for lane := range limiter.waiting {
+
+		stats.Waiting[lane] = len(limiter.waiting[lane])
+
+	}
+
+	return stats
+
+}
+
+/* Return the priority lane of an exchange REST call by its method and path */
+func requestLane(request *http.Request) int {
+
+	path := request.URL.Path
+
+	switch {
+	case strings.HasSuffix(path, "/order") || strings.HasSuffix(path, "/order/test") || strings.HasSuffix(path, "/openOrders"):
+
+		if request.Method == http.MethodGet { /* Order status */
+
+			return LaneBalance
+
+		}
+
+		return LaneOrder
+
+	case strings.HasSuffix(path, "/account") || strings.HasSuffix(path, "/tradeFee") || strings.HasSuffix(path, "/userDataStream"):
+
+		return LaneBalance
+
+	}
+
+	return LaneStats
+
+}
+
+/* Return a function releasing the slot taken once */
+func releaseOnce() func() {
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			limiter.Lock()
+			defer limiter.Unlock()
+
+			releaseSlot()
+		})
+	}
+
+}
+
+/* Hand the slot over to the oldest call of the highest priority lane waiting, or free it. Requires the limiter lock. */
+func releaseSlot() {
+
+	if limiter.active <= limiter.limit && grant() {
+
+		return
+
+	}
+
+	limiter.active--
+
+}
+
+/* Wake the oldest call of the highest priority lane waiting, returning false when no call waits. Requires the limiter lock. */
+func grant() bool {
+
+	for lane := range limiter.waiting {
+
+		if len(limiter.waiting[lane]) > 0 {
+
+			close(limiter.waiting[lane][0])
+			limiter.waiting[lane] = limiter.waiting[lane][1:]
+			waitingChanged(lane)
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Export the calls of lane waiting for a slot to the metrics endpoint. Requires the limiter lock. */
+func waitingChanged(lane int) {
+
+	metrics.SetExchangeWaiting(LaneNames[lane], len(limiter.waiting[lane]))
+
+}
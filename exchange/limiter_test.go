@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestAcquire(t *testing.T) {
+
+	ConfigureLimiter(&types.Config{ConfigGlobal: &types.ConfigGlobal{ExchangeConcurrency: 1}})
+	defer ConfigureLimiter(&types.Config{})
+
+	release, err := Acquire(context.Background(), LaneStats)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	granted := make(chan int, lanes)
+
+	for _, lane := range []int{LaneStats, LaneBalance, LaneOrder} { /* Queued in reverse priority */
+
+		go func(lane int) {
+			release, err := Acquire(context.Background(), lane)
+			if err != nil {
+				t.Errorf("Acquire() error = %v", err)
+				return
+			}
+			granted <- lane
+			release()
+		}(lane)
+
+		for ReadLimiter().Waiting[lane] == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+	}
+
+	if got := ReadLimiter(); !reflect.DeepEqual(got, LimiterStats{Limit: 1, Active: 1, Waiting: [lanes]int{1, 1, 1}}) {
+		t.Errorf("ReadLimiter() = %+v, want 1 active and 1 waiting by lane", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := Acquire(ctx, LaneOrder); err != context.DeadlineExceeded {
+		t.Errorf("Acquire() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	release()
+	release() /* Released once */
+
+	var got []int
+	for i := 0; i < lanes; i++ {
+		got = append(got, <-granted)
+	}
+
+	if want := []int{LaneOrder, LaneBalance, LaneStats}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Acquire() granted lanes %v, want %v", got, want)
+	}
+
+	for ReadLimiter().Active > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+}
+
+func Test_requestLane(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "create order", method: "POST", path: "/api/v3/order", want: LaneOrder},
+		{name: "cancel order", method: "DELETE", path: "/api/v3/order", want: LaneOrder},
+		{name: "cancel open orders", method: "DELETE", path: "/api/v3/openOrders", want: LaneOrder},
+		{name: "order status", method: "GET", path: "/api/v3/order", want: LaneBalance},
+		{name: "account", method: "GET", path: "/api/v3/account", want: LaneBalance},
+		{name: "commission", method: "GET", path: "/sapi/v1/asset/tradeFee", want: LaneBalance},
+		{name: "klines", method: "GET", path: "/api/v3/klines", want: LaneStats},
+		{name: "24hr stats", method: "GET", path: "/api/v3/ticker/24hr", want: LaneStats},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestLane(&http.Request{Method: tt.method, URL: &url.URL{Path: tt.path}}); got != tt.want {
+				t.Errorf("requestLane() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
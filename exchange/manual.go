@@ -0,0 +1,255 @@
+package exchange
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// OrderSourceOperator is the orders table source of orders entered manually by an operator
+const OrderSourceOperator = "operator"
+
+/* Manual order errors */
+var (
+	ErrManualSide     = errors.New("Side must be BUY or SELL")
+	ErrManualQuantity = errors.New("Quantity must be a positive number of at least one lot size step")
+	ErrManualPrice    = errors.New("Price must be empty for a market order or a positive number")
+	ErrManualDryRun   = errors.New("DryRun mode, manual order not placed")
+)
+
+// ParseManualOrder validate a manual order entered by an operator. quantity is rounded down to stepSize
+// and price is 0 for a market order.
+func ParseManualOrder(
+	side string,
+	quantity string,
+	price string,
+	stepSize float64) (orderSide string, orderQuantity float64, orderPrice float64, err error) {
+
+	if orderSide = strings.ToUpper(strings.TrimSpace(side)); orderSide != "BUY" && orderSide != "SELL" {
+
+		return "", 0, 0, ErrManualSide
+
+	}
+
+	if orderQuantity, err = strconv.ParseFloat(strings.TrimSpace(quantity), 64); err != nil || orderQuantity <= 0 {
+
+		return "", 0, 0, ErrManualQuantity
+
+	}
+
+	if stepSize > 0 { /* Round down according to the exchange lotSizeStep */
+
+		orderQuantity = math.Floor(orderQuantity/stepSize+1e-9) * stepSize
+
+	}
+
+	if orderQuantity <= 0 {
+
+		return "", 0, 0, ErrManualQuantity
+
+	}
+
+	if price = strings.TrimSpace(price); price != "" {
+
+		if orderPrice, err = strconv.ParseFloat(price, 64); err != nil || orderPrice <= 0 {
+
+			return "", 0, 0, ErrManualPrice
+
+		}
+
+	}
+
+	return orderSide, orderQuantity, orderPrice, nil
+
+}
+
+// ManualTicker place an order entered by an operator for sessionData.Symbol, MARKET when price is 0 or LIMIT IOC at price,
+// and record it with the operator source in the orders table. A filled BUY is saved as an open transaction of the thread
+// and sold by the bot as any other transaction, a SELL is recorded without closing any transaction. The order runs
+// through the trade cycle as the orders of the bot.
+func ManualTicker(
+	side string,
+	quantity float64,
+	price float64,
+	username string,
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) (order *types.Order, err error) {
+
+	var orderPrice float64
+	var limitPrice string
+
+	/* Enter and defer exiting busy mode */
+	sessionData.Busy = true
+	defer func() {
+		sessionData.Busy = false
+	}()
+
+	/* Exit if DryRun mode set to true */
+	if configData.DryRun {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:  configData,
+			Market:  marketData,
+			Session: sessionData,
+			Order: &types.Order{
+				Price: marketData.Price,
+			},
+			Message:  "MANUALDRYRUN " + side + " by " + username,
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		return nil, ErrManualDryRun
+
+	}
+
+	checkPrice := marketData.Price /* Market orders are validated at the last price */
+
+	if price > 0 {
+
+		checkPrice = price
+		limitPrice = strconv.FormatFloat(price, 'f', -1, 64)
+
+	}
+
+	/* Pre-trade sanity checks, avoid orders the exchange would reject */
+	if err = ValidateOrder(
+		configData,
+		sessionData,
+		side,
+		quantity,
+		checkPrice); err != nil {
+
+		rejectOrder(err, configData, marketData, sessionData)
+
+		return nil, err
+
+	}
+
+	/* Risk limits of every buy apply to the operator buys */
+	if side == "BUY" {
+
+		if err = checkBuy(configData, sessionData, quantity*checkPrice); err != nil {
+
+			rejectOrder(err, configData, marketData, sessionData)
+
+			return nil, err
+
+		}
+
+	}
+
+	placed, failed := cycle.BuyPlaced, cycle.BuyFailed
+	if side == "SELL" {
+		placed, failed = cycle.SellPlaced, cycle.SellFailed
+	}
+
+	/* Persist the order in flight before sending it, so a thread stopping before the response can be recovered. A
+	manual SELL sells no open transaction (orderIDSource 0). */
+	if err = cycle.Transition(sessionData, placed, 0, 0); err != nil {
+
+		return nil, err
+
+	}
+
+	if order, err = ManualOrder(
+		configData,
+		sessionData,
+		side,
+		functions.Float64ToStr(quantity, 8),
+		limitPrice); order == nil {
+
+		_ = cycle.Transition(sessionData, failed, 0, 0) /* Settled by the next trade decision */
+
+		if err == nil {
+			err = errors.New("Invalid Exchange Name")
+		}
+
+		return nil, err
+
+	}
+
+	_ = cycle.Transition(sessionData, placed, order.OrderID, 0) /* Order acknowledged by the exchange */
+
+	/* Conditional defer of the order of unknown outcome to the trade cycle recovery when it can't be recorded */
+	defer func() {
+		if err != nil {
+			_ = cycle.Transition(sessionData, failed, order.OrderID, 0) /* Resolved by the next recovery */
+		}
+	}()
+
+	/* Check if result is nil and set as zero */
+	if orderPrice = order.CumulativeQuoteQuantity / order.ExecutedQuantity; math.IsNaN(orderPrice) || math.IsInf(orderPrice, 0) {
+		orderPrice = 0
+	}
+
+	/* Save order to database */
+	if err = mysql.SaveOrder(
+		sessionData,
+		order,
+		0, /* OrderIDSource */
+		orderPrice /* OrderPrice */); err != nil {
+
+		return order, err
+
+	}
+
+	if err = mysql.UpdateOrderSource(sessionData, order.OrderID, OrderSourceOperator); err != nil {
+
+		return order, err
+
+	}
+
+	if side == "BUY" && order.ExecutedQuantity > 0 {
+
+		/* Save Thread Transaction */
+		if err = mysql.SaveThreadTransaction(
+			sessionData,
+			order.OrderID,
+			order.CumulativeQuoteQuantity,
+			orderPrice,
+			order.ExecutedQuantity); err != nil {
+
+			return order, err
+
+		}
+
+		/* This session variable stores the time of the last buy */
+		sessionData.LastBuyTransactTime = time.Now()
+
+	}
+
+	switch {
+	case side == "BUY" && order.ExecutedQuantity > 0:
+		_ = cycle.Transition(sessionData, cycle.PositionOpen, 0, 0)
+	case side == "SELL" && order.ExecutedQuantity > 0:
+		_ = cycle.Transition(sessionData, cycle.Closed, 0, 0)
+	case side == "SELL":
+		_ = cycle.Transition(sessionData, cycle.PositionOpen, 0, 0) /* Sale not filled */
+	}
+
+	_ = cycle.Resolve(sessionData) /* Back to the open transactions of the thread */
+
+	logger.LogEntry{ /* Log Entry */
+		Config:  configData,
+		Market:  marketData,
+		Session: sessionData,
+		Order: &types.Order{
+			OrderID: order.OrderID,
+			Price:   orderPrice,
+		},
+		Message:  "MANUAL " + side + " " + order.Status + " by " + username,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return order, nil
+
+}
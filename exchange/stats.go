@@ -0,0 +1,178 @@
+package exchange
+
+/* Exchange REST API latency and error-rate tracking. Every REST call made by the exchange client is recorded by its
+HTTP transport per endpoint (method and path): the latency histogram and the errors counter of the metrics endpoint,
+and the calls of the last statsWindow for the exchange health widget of the webui. A call fails on a network error or
+an error status, and calls slower than ExchangeSlowMs are logged. */
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/metrics"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const statsWindow = 5 * time.Minute /* Calls of the exchange health */
+
+/* Exchange health status */
+const (
+	HealthOK       = "OK"
+	HealthDegraded = "Degraded" /* Error rate of degradedRate or mean latency above ExchangeSlowMs */
+	HealthDown     = "Down"     /* Error rate of downRate */
+)
+
+/* Error rates of the exchange health status */
+const (
+	degradedRate = 0.1
+	downRate     = 0.5
+)
+
+/* REST call recorded for the exchange health */
+type call struct {
+	time    time.Time
+	latency time.Duration
+	failed  bool
+}
+
+var stats = struct {
+	sync.Mutex
+	calls map[string][]call /* Calls of the last statsWindow by endpoint, oldest first */
+	slow  time.Duration     /* ExchangeSlowMs, 0 disables the slow call logging */
+}{
+	calls: make(map[string][]call),
+}
+
+// EndpointStats struct define the calls of an exchange REST endpoint in the last statsWindow
+type EndpointStats struct {
+	Endpoint  string  /* Method and path, i.e. POST /api/v3/order */
+	Calls     int     /* Calls */
+	Errors    int     /* Failed calls */
+	ErrorRate float64 /* Failed calls ratio */
+	MeanMs    float64 /* Mean latency in milliseconds */
+	MaxMs     float64 /* Max latency in milliseconds */
+}
+
+// Health struct define the exchange health of the last statsWindow
+type Health struct {
+	Status    string /* OK, Degraded or Down, empty without calls */
+	Calls     int
+	ErrorRate float64
+	MeanMs    float64
+	Endpoints []EndpointStats /* Sorted by endpoint */
+}
+
+// ConfigureStats apply the slow call threshold after a configuration reload
+func ConfigureStats(configData *types.Config) {
+
+	stats.Lock()
+	defer stats.Unlock()
+
+	stats.slow = 0
+
+	if configData.ConfigGlobal != nil && configData.ConfigGlobal.ExchangeSlowMs > 0 {
+
+		stats.slow = time.Duration(configData.ConfigGlobal.ExchangeSlowMs) * time.Millisecond
+
+	}
+
+}
+
+// Observe record a REST call to endpoint started at start, returning true when it is slower than ExchangeSlowMs
+func Observe(
+	endpoint string,
+	start time.Time,
+	latency time.Duration,
+	failed bool) (slow bool) {
+
+	metrics.ObserveExchange(endpoint, latency, failed)
+
+	stats.Lock()
+	defer stats.Unlock()
+
+	calls := append(stats.calls[endpoint], call{time: start, latency: latency, failed: failed})
+
+	expired := 0
+	for expired < len(calls) && start.Sub(calls[expired].time) > statsWindow {
+		expired++
+	}
+
+	stats.calls[endpoint] = calls[expired:]
+
+	return stats.slow > 0 && latency > stats.slow
+
+}
+
+// ReadHealth return the exchange health of the calls in the statsWindow before now
+func ReadHealth(now time.Time) (health Health) {
+
+	stats.Lock()
+	defer stats.Unlock()
+
+	var errors int
+	var total time.Duration
+
+	for endpoint, calls := range stats.calls {
+
+		endpointStats := EndpointStats{Endpoint: endpoint}
+		var latency, max time.Duration
+
+		for _, c := range calls {
+
+			if now.Sub(c.time) > statsWindow {
+				continue
+			}
+
+			endpointStats.Calls++
+			latency += c.latency
+
+			if c.latency > max {
+				max = c.latency
+			}
+
+			if c.failed {
+				endpointStats.Errors++
+			}
+
+		}
+
+		if endpointStats.Calls == 0 {
+			continue
+		}
+
+		endpointStats.ErrorRate = functions.Round(float64(endpointStats.Errors)/float64(endpointStats.Calls), 2)
+		endpointStats.MeanMs = functions.Round(float64(latency)/float64(endpointStats.Calls)/float64(time.Millisecond), 2)
+		endpointStats.MaxMs = functions.Round(float64(max)/float64(time.Millisecond), 2)
+
+		health.Endpoints = append(health.Endpoints, endpointStats)
+		health.Calls += endpointStats.Calls
+		errors += endpointStats.Errors
+		total += latency
+
+	}
+
+	sort.Slice(health.Endpoints, func(i, j int) bool { return health.Endpoints[i].Endpoint < health.Endpoints[j].Endpoint })
+
+	if health.Calls == 0 {
+
+		return health
+
+	}
+
+	health.ErrorRate = functions.Round(float64(errors)/float64(health.Calls), 2)
+	health.MeanMs = functions.Round(float64(total)/float64(health.Calls)/float64(time.Millisecond), 2)
+
+	switch {
+	case health.ErrorRate >= downRate:
+		health.Status = HealthDown
+	case health.ErrorRate >= degradedRate || (stats.slow > 0 && health.MeanMs > float64(stats.slow/time.Millisecond)):
+		health.Status = HealthDegraded
+	default:
+		health.Status = HealthOK
+	}
+
+	return health
+
+}
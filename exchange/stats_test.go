@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestReadHealth(t *testing.T) {
+
+	/* Discard the calls recorded by the other tests of the package (i.e. GetClient and GetLotSize in init) */
+	stats.Lock()
+	stats.calls = make(map[string][]call)
+	stats.Unlock()
+
+	ConfigureStats(&types.Config{ConfigGlobal: &types.ConfigGlobal{ExchangeSlowMs: 500}})
+	defer ConfigureStats(&types.Config{})
+
+	now := time.Now()
+
+	Observe("GET /api/v3/depth", now.Add(-10*time.Minute), 900*time.Millisecond, true) /* Out of the window */
+
+	for i := 0; i < 8; i++ {
+		if Observe("GET /api/v3/order", now.Add(-time.Minute), 100*time.Millisecond, false) {
+			t.Errorf("Observe() slow = true, want false")
+		}
+	}
+
+	if !Observe("POST /api/v3/order", now.Add(-time.Minute), 600*time.Millisecond, true) {
+		t.Errorf("Observe() slow = false, want true")
+	}
+
+	Observe("POST /api/v3/order", now.Add(-time.Minute), 200*time.Millisecond, false)
+
+	want := Health{
+		Status:    HealthDegraded, /* 1 error in 10 calls */
+		Calls:     10,
+		ErrorRate: 0.1,
+		MeanMs:    160,
+		Endpoints: []EndpointStats{
+			{Endpoint: "GET /api/v3/order", Calls: 8, Errors: 0, ErrorRate: 0, MeanMs: 100, MaxMs: 100},
+			{Endpoint: "POST /api/v3/order", Calls: 2, Errors: 1, ErrorRate: 0.5, MeanMs: 400, MaxMs: 600},
+		},
+	}
+
+	if got := ReadHealth(now); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadHealth() = %v, want %v", got, want)
+	}
+
+	if got := ReadHealth(now.Add(10 * time.Minute)); got.Status != "" || got.Calls != 0 {
+		t.Errorf("ReadHealth() after the window = %v", got)
+	}
+
+}
+
+func TestDrain(t *testing.T) {
+
+	atomic.AddInt64(&inflight, 1)
+
+	if Drain(50 * time.Millisecond) {
+		t.Errorf("Drain() call in progress = true, want false")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt64(&inflight, -1)
+	}()
+
+	if !Drain(5 * time.Second) {
+		t.Errorf("Drain() call completed = false, want true")
+	}
+
+}
@@ -0,0 +1,205 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Pre-trade validation errors (and ErrInsufficientFunds) wrapped in ValidationError, callers test them with errors.Is */
+var (
+	ErrTradePermission = errors.New("API key not allowed to trade")
+	ErrMaxNumOrders    = errors.New("MAX_NUM_ORDERS")
+	ErrLotSize         = errors.New("LOT_SIZE")
+	ErrMinNotional     = errors.New("MIN_NOTIONAL")
+)
+
+// ValidationError define a pre-trade validation failure
+type ValidationError struct {
+	Err    error  /* One of the pre-trade validation errors */
+	Detail string /* Values that failed validation */
+}
+
+// Error return the failed check and the values that failed validation
+func (e *ValidationError) Error() string {
+
+	return e.Err.Error() + " - " + e.Detail
+
+}
+
+// Unwrap return the pre-trade validation error for errors.Is
+func (e *ValidationError) Unwrap() error {
+
+	return e.Err
+
+}
+
+/* Exchange filters and account state used by pre-trade validation */
+type orderLimits struct {
+	CanTrade     bool    /* API key trade permission */
+	OpenOrders   int     /* Number of open orders for symbol */
+	MaxNumOrders int     /* Maximum number of open orders, 0 when not defined */
+	MinQuantity  float64 /* LOT_SIZE minimum quantity */
+	MaxQuantity  float64 /* LOT_SIZE maximum quantity, 0 when not defined */
+	StepSize     float64 /* LOT_SIZE step */
+	MinNotional  float64 /* MIN_NOTIONAL minimum order value */
+	FreeBalance  float64 /* Free fiat balance for BUY, free symbol balance for SELL */
+}
+
+// ValidateOrder run pre-trade checks before every order: API key trade permission, open order count,
+// lot size quantization, minimum notional and free balance sufficiency. side is BUY or SELL and quantity
+// is in sessionData.Symbol units. A *ValidationError is returned for the first check that fails.
+func ValidateOrder(
+	configData *types.Config,
+	sessionData *types.Session,
+	side string,
+	quantity float64,
+	price float64) (err error) {
+
+	limits := orderLimits{}
+
+	/* Exchange filters are loaded once per session and refreshed after a LOT_SIZE failure */
+	if sessionData.StepSize == 0 {
+
+		GetLotSize(configData, sessionData)
+
+	}
+
+	limits.MinQuantity = sessionData.MinQuantity
+	limits.MaxQuantity = sessionData.MaxQuantity
+	limits.StepSize = sessionData.StepSize
+	limits.MinNotional = sessionData.MinNotional
+	limits.MaxNumOrders = sessionData.MaxNumOrders
+
+	if limits.CanTrade, err = GetTradePermission(configData, sessionData); err != nil {
+
+		return err
+
+	}
+
+	if limits.OpenOrders, err = GetOpenOrderCount(configData, sessionData); err != nil {
+
+		return err
+
+	}
+
+	if side == "BUY" {
+
+		limits.FreeBalance, err = GetSymbolFiatFunds(configData, sessionData)
+
+	} else {
+
+		limits.FreeBalance, err = GetSymbolFunds(configData, sessionData)
+
+	}
+
+	if err != nil {
+
+		return err
+
+	}
+
+	return validateOrder(side, quantity, price, limits)
+
+}
+
+// BuyCheck is a risk limit checked before every buy of buyQuantityFiat is sent to the exchange, whatever
+// decided the buy. A non nil error rejects the buy.
+type BuyCheck func(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error
+
+var buyChecks []BuyCheck
+
+// RegisterBuyCheck add a risk limit checked before every buy, used for the limits of the packages that
+// can't be imported by this package (risk)
+func RegisterBuyCheck(check BuyCheck) {
+
+	buyChecks = append(buyChecks, check)
+
+}
+
+/* Check the risk limits of a buy of buyQuantityFiat, returning the error of the first limit exceeded */
+func checkBuy(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error {
+
+	for _, check := range buyChecks {
+
+		if err := check(configData, sessionData, buyQuantityFiat); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return nil
+
+}
+
+/* Validate an order against exchange filters and account state */
+func validateOrder(
+	side string,
+	quantity float64,
+	price float64,
+	limits orderLimits) error {
+
+	if !limits.CanTrade {
+
+		return &ValidationError{Err: ErrTradePermission, Detail: "enable spot trading for the API key"}
+
+	}
+
+	if limits.MaxNumOrders > 0 && limits.OpenOrders >= limits.MaxNumOrders {
+
+		return &ValidationError{Err: ErrMaxNumOrders, Detail: fmt.Sprintf("%d open orders", limits.OpenOrders)}
+
+	}
+
+	if quantity <= 0 ||
+		quantity < limits.MinQuantity ||
+		(limits.MaxQuantity > 0 && quantity > limits.MaxQuantity) {
+
+		return &ValidationError{Err: ErrLotSize, Detail: fmt.Sprintf("quantity %g outside %g-%g", quantity, limits.MinQuantity, limits.MaxQuantity)}
+
+	}
+
+	/* Quantity must be a multiple of the step size, within float tolerance */
+	if limits.StepSize > 0 {
+
+		if steps := quantity / limits.StepSize; math.Abs(steps-math.Round(steps)) > 1e-6 {
+
+			return &ValidationError{Err: ErrLotSize, Detail: fmt.Sprintf("quantity %g not a multiple of step %g", quantity, limits.StepSize)}
+
+		}
+
+	}
+
+	if notional := quantity * price; notional < limits.MinNotional {
+
+		return &ValidationError{Err: ErrMinNotional, Detail: fmt.Sprintf("order value %.2f below %.2f", notional, limits.MinNotional)}
+
+	}
+
+	required := quantity /* SELL requires symbol balance */
+
+	if side == "BUY" {
+
+		required = quantity * price /* BUY requires fiat balance */
+
+	}
+
+	if required > limits.FreeBalance {
+
+		return &ValidationError{Err: ErrInsufficientFunds, Detail: fmt.Sprintf("%s requires %g, free %g", side, required, limits.FreeBalance)}
+
+	}
+
+	return nil
+
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"math"
 	"net"
 	"net/http"
 	"path/filepath"
@@ -15,11 +16,13 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/aleibovici/cryptopump/i18n"
 	"github.com/aleibovici/cryptopump/logger"
 	"github.com/aleibovici/cryptopump/types"
 	"github.com/tcnksm/go-httpstat"
 
 	"github.com/rs/xid"
+	"github.com/spf13/viper"
 )
 
 // StrToFloat64 function
@@ -54,6 +57,15 @@ func Float64ToStr(value float64, prec int) string {
 
 }
 
+// Round round value to decimals
+func Round(value float64, decimals int) float64 {
+
+	pow := math.Pow(10, float64(decimals))
+
+	return math.Round(value*pow) / pow
+
+}
+
 // IntToFloat64 convert Int to Float64
 func IntToFloat64(value int) float64 {
 
@@ -146,12 +158,25 @@ func GetThreadID() string {
 
 }
 
+// GetCorrelationID Return random correlation ID of a trade cycle
+func GetCorrelationID() string {
+
+	return xid.New().String()
+
+}
+
 /* Convert Strign to Time */
 func stringToTime(str string) (r time.Time) {
 
 	var err error
 
-	if r, err = time.Parse(time.Kitchen, str); err != nil {
+	if r, err = time.Parse(time.Kitchen, str); err == nil { /* Kitchen format (3:04PM) */
+
+		return r
+
+	}
+
+	if r, err = time.Parse("15:04", str); err != nil { /* 24 hour format (15:04) */
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -168,28 +193,42 @@ func stringToTime(str string) (r time.Time) {
 
 }
 
-// IsInTimeRange Check if time is in a specific range
-func IsInTimeRange(startTimeString string, endTimeString string) bool {
+// IsInTradingWindow Check if t is inside the configured trading hours.
+// Windows crossing midnight (i.e. 22:00 to 06:00) and weekend skipping are supported.
+func IsInTradingWindow(
+	configData *types.Config,
+	t time.Time) bool {
+
+	if configData.TimeUTC {
 
-	t := time.Now()
-	timeNowString := t.Format(time.Kitchen)
-	timeNow := stringToTime(timeNowString)
-	start := stringToTime(startTimeString)
-	end := stringToTime(endTimeString)
+		t = t.UTC()
+
+	}
 
-	if timeNow.Before(start) {
+	if configData.TimeSkipWeekends &&
+		(t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
 
 		return false
 
 	}
 
-	if timeNow.After(end) {
+	if !configData.TimeEnforce {
 
-		return false
+		return true
 
 	}
 
-	return true
+	timeNow := stringToTime(t.Format("15:04"))
+	start := stringToTime(configData.TimeStart)
+	end := stringToTime(configData.TimeStop)
+
+	if start.After(end) { /* Window crosses midnight */
+
+		return !timeNow.Before(start) || !timeNow.After(end)
+
+	}
+
+	return !timeNow.Before(start) && !timeNow.After(end)
 
 }
 
@@ -202,11 +241,12 @@ func IsFundsAvailable(
 
 }
 
-/* Select the correct html template based on sessionData */
+/* Select the correct html template based on configData and sessionData */
 func selectTemplate(
+	configData *types.Config,
 	sessionData *types.Session) (template string) {
 
-	if sessionData.Admin {
+	if configData.Admin {
 
 		template = "admin.html" /* Admin template */
 
@@ -227,19 +267,164 @@ func selectTemplate(
 // ExecuteTemplate is responsible for executing any templates
 func ExecuteTemplate(
 	wr io.Writer,
-	data interface{},
+	configData *types.Config,
 	sessionData *types.Session) {
 
+	executeTemplate(wr, selectTemplate(configData, sessionData), configData)
+
+}
+
+// ExecuteLoginTemplate is responsible for executing the login template
+func ExecuteLoginTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "login.html", data)
+
+}
+
+// ExecuteThreadTemplate is responsible for executing the thread detail template
+func ExecuteThreadTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "thread.html", data)
+
+}
+
+// ExecuteConfigTemplate is responsible for executing the config editor template
+func ExecuteConfigTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "config.html", data)
+
+}
+
+// ExecutePreferencesTemplate is responsible for executing the preferences template
+func ExecutePreferencesTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "preferences.html", data)
+
+}
+
+// ExecutePortfolioTemplate is responsible for executing the portfolio template
+func ExecutePortfolioTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "portfolio.html", data)
+
+}
+
+// ExecuteJournalTemplate is responsible for executing the trade journal template
+func ExecuteJournalTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "journal.html", data)
+
+}
+
+// ExecuteLogsTemplate is responsible for executing the log viewer template
+func ExecuteLogsTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "logs.html", data)
+
+}
+
+// ExecuteAlertsTemplate is responsible for executing the alert rules template
+func ExecuteAlertsTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "alerts.html", data)
+
+}
+
+// ExecuteSecurityTemplate is responsible for executing the two-factor authentication template
+func ExecuteSecurityTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "security.html", data)
+
+}
+
+// ExecuteOrdersTemplate is responsible for executing the order history template
+func ExecuteOrdersTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "orders.html", data)
+
+}
+
+// ExecuteReportsTemplate is responsible for executing the report export template
+func ExecuteReportsTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "reports.html", data)
+
+}
+
+// ExecuteTimelineTemplate is responsible for executing the thread timeline template
+func ExecuteTimelineTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "timeline.html", data)
+
+}
+
+// ExecuteWebhooksTemplate is responsible for executing the webhook destinations template
+func ExecuteWebhooksTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "webhooks.html", data)
+
+}
+
+// ExecuteBacktestsTemplate is responsible for executing the backtest results template
+func ExecuteBacktestsTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "backtests.html", data)
+
+}
+
+// ExecuteHeatmapTemplate is responsible for executing the profit heatmap template
+func ExecuteHeatmapTemplate(
+	wr io.Writer,
+	data interface{}) {
+
+	executeTemplate(wr, "heatmap.html", data)
+
+}
+
+/* Execute the html template name */
+func executeTemplate(
+	wr io.Writer,
+	name string,
+	data interface{}) {
+
 	var tlp *template.Template
 	var err error
 
-	if tlp, err = template.ParseGlob("./templates/*"); err != nil {
+	if tlp, err = template.New(name).Funcs(i18n.Funcs()).ParseGlob("./templates/*"); err != nil { /* Templates translate with i18n.Funcs */
 
 		defer os.Exit(1)
 
 	}
 
-	if err = tlp.ExecuteTemplate(wr, selectTemplate(sessionData), data); err != nil {
+	if err = tlp.ExecuteTemplate(wr, name, data); err != nil {
 
 		defer os.Exit(1)
 
@@ -332,11 +517,85 @@ func SaveConfigGlobalData(
 	r *http.Request,
 	sessionData *types.Session) {
 
-	viperData.V2.Set("config_global.apiKey", r.FormValue("Apikey"))                     /* Api Key */
-	viperData.V2.Set("config_global.secretKey", r.FormValue("Secretkey"))               /* Secret Key */
-	viperData.V2.Set("config_global.apiKeyTestNet", r.FormValue("ApikeyTestNet"))       /* Api Key TestNet */
-	viperData.V2.Set("config_global.secretKeyTestNet", r.FormValue("SecretkeyTestNet")) /* Secret Key TestNet */
-	viperData.V2.Set("config_global.tgbotapikey", r.FormValue("TgBotApikey"))           /* Tg Bot Api Key */
+	viperData.V2.Set("config_global.apiKey", r.FormValue("Apikey"))                           /* Api Key */
+	viperData.V2.Set("config_global.secretKey", r.FormValue("Secretkey"))                     /* Secret Key */
+	viperData.V2.Set("config_global.apiKeyTestNet", r.FormValue("ApikeyTestNet"))             /* Api Key TestNet */
+	viperData.V2.Set("config_global.secretKeyTestNet", r.FormValue("SecretkeyTestNet"))       /* Secret Key TestNet */
+	viperData.V2.Set("config_global.tgbotapikey", r.FormValue("TgBotApikey"))                 /* Tg Bot Api Key */
+	viperData.V2.Set("config_global.tgchatids", r.FormValue("TgChatIDs"))                     /* Tg chat IDs allowed to send commands */
+	viperData.V2.Set("config_global.discordwebhookurl", r.FormValue("DiscordWebhookURL"))     /* Discord webhook URL */
+	viperData.V2.Set("config_global.discordbottoken", r.FormValue("DiscordBotToken"))         /* Discord bot token */
+	viperData.V2.Set("config_global.discordchannelid", r.FormValue("DiscordChannelID"))       /* Discord channel ID */
+	viperData.V2.Set("config_global.discordevents", r.FormValue("DiscordEvents"))             /* Discord event types */
+	viperData.V2.Set("config_global.slackwebhookurl", r.FormValue("SlackWebhookURL"))         /* Slack webhook URL */
+	viperData.V2.Set("config_global.slackbottoken", r.FormValue("SlackBotToken"))             /* Slack bot token */
+	viperData.V2.Set("config_global.slackchannel", r.FormValue("SlackChannel"))               /* Slack channel */
+	viperData.V2.Set("config_global.matrixserverurl", r.FormValue("MatrixServerURL"))         /* Matrix homeserver URL */
+	viperData.V2.Set("config_global.matrixaccesstoken", r.FormValue("MatrixAccessToken"))     /* Matrix access token */
+	viperData.V2.Set("config_global.matrixroomid", r.FormValue("MatrixRoomID"))               /* Matrix room ID */
+	viperData.V2.Set("config_global.smtphost", r.FormValue("SMTPHost"))                       /* SMTP server host */
+	viperData.V2.Set("config_global.smtpport", r.FormValue("SMTPPort"))                       /* SMTP server port */
+	viperData.V2.Set("config_global.smtpusername", r.FormValue("SMTPUsername"))               /* SMTP username */
+	viperData.V2.Set("config_global.smtppassword", r.FormValue("SMTPPassword"))               /* SMTP password */
+	viperData.V2.Set("config_global.emailfrom", r.FormValue("EmailFrom"))                     /* Email sender */
+	viperData.V2.Set("config_global.emailto", r.FormValue("EmailTo"))                         /* Email recipients */
+	viperData.V2.Set("config_global.emaildigesttime", r.FormValue("EmailDigestTime"))         /* Daily digest email time */
+	viperData.V2.Set("config_global.pushovertoken", r.FormValue("PushoverToken"))             /* Pushover API token */
+	viperData.V2.Set("config_global.pushoveruser", r.FormValue("PushoverUser"))               /* Pushover user key */
+	viperData.V2.Set("config_global.ntfyurl", r.FormValue("NtfyURL"))                         /* ntfy server URL */
+	viperData.V2.Set("config_global.ntfytopic", r.FormValue("NtfyTopic"))                     /* ntfy topic */
+	viperData.V2.Set("config_global.ntfytoken", r.FormValue("NtfyToken"))                     /* ntfy access token */
+	viperData.V2.Set("config_global.twilioaccountsid", r.FormValue("TwilioAccountSID"))       /* Twilio account SID */
+	viperData.V2.Set("config_global.twilioauthtoken", r.FormValue("TwilioAuthToken"))         /* Twilio auth token */
+	viperData.V2.Set("config_global.twiliofrom", r.FormValue("TwilioFrom"))                   /* Twilio sender number */
+	viperData.V2.Set("config_global.smsto", r.FormValue("SMSTo"))                             /* SMS alert recipients */
+	viperData.V2.Set("config_global.smsratemax", r.FormValue("SMSRateMax"))                   /* SMS alerts per hour */
+	viperData.V2.Set("config_global.dbdownminutes", r.FormValue("DbDownMinutes"))             /* Database down alert delay */
+	viperData.V2.Set("config_global.notifyratemax", r.FormValue("NotifyRateMax"))             /* Notifications per minute and channel */
+	viperData.V2.Set("config_global.notifyroutes", r.FormValue("NotifyRoutes"))               /* Notification routing rules */
+	viperData.V2.Set("config_global.errorburstmax", r.FormValue("ErrorBurstMax"))             /* Error burst alert threshold */
+	viperData.V2.Set("config_global.errorburstwindow", r.FormValue("ErrorBurstWindow"))       /* Error burst window in minutes */
+	viperData.V2.Set("config_global.summaryschedules", r.FormValue("SummarySchedules"))       /* Performance summary schedules */
+	viperData.V2.Set("config_global.otlpendpoint", r.FormValue("OtlpEndpoint"))               /* OTLP collector endpoint for traces */
+	viperData.V2.Set("config_global.sentrydsn", r.FormValue("SentryDsn"))                     /* Sentry DSN for error reporting */
+	viperData.V2.Set("config_global.exchangeslowms", r.FormValue("ExchangeSlowMs"))           /* Exchange slow call threshold */
+	viperData.V2.Set("config_global.exchangeconcurrency", r.FormValue("ExchangeConcurrency")) /* Exchange Concurrency */
+	viperData.V2.Set("config_global.watchdoginterval", r.FormValue("WatchdogInterval"))       /* Watchdog sample interval in minutes */
+	viperData.V2.Set("config_global.watchdogsamples", r.FormValue("WatchdogSamples"))         /* Watchdog growing samples */
+	viperData.V2.Set("config_global.watchdoggoroutines", r.FormValue("WatchdogGoroutines"))   /* Watchdog goroutines threshold */
+	viperData.V2.Set("config_global.watchdogheapmb", r.FormValue("WatchdogHeapMB"))           /* Watchdog heap threshold in MB */
+	viperData.V2.Set("config_global.watchdogrestart", r.FormValue("WatchdogRestart"))         /* Watchdog restart */
+	viperData.V2.Set("config_global.supervisortimeout", r.FormValue("SupervisorTimeout"))     /* Supervisor heartbeat timeout in seconds */
+	viperData.V2.Set("config_global.supervisorbackoff", r.FormValue("SupervisorBackoff"))     /* Supervisor restart backoff in seconds */
+	viperData.V2.Set("config_global.supervisormaxdelay", r.FormValue("SupervisorMaxDelay"))   /* Supervisor maximum restart delay in seconds */
+	viperData.V2.Set("config_global.loglevel", r.FormValue("LogLevel"))                       /* Log level */
+	viperData.V2.Set("config_global.loglevelexchange", r.FormValue("LogLevelExchange"))       /* Log level of the exchange subsystem */
+	viperData.V2.Set("config_global.loglevelmysql", r.FormValue("LogLevelMysql"))             /* Log level of the mysql subsystem */
+	viperData.V2.Set("config_global.loglevelthreads", r.FormValue("LogLevelThreads"))         /* Log level of the threads subsystem */
+	viperData.V2.Set("config_global.loglevelalgorithms", r.FormValue("LogLevelAlgorithms"))   /* Log level of the algorithms subsystem */
+	viperData.V2.Set("config_global.logmaxsize", r.FormValue("LogMaxSize"))                   /* Log file rotation size in MB */
+	viperData.V2.Set("config_global.logrotatehours", r.FormValue("LogRotateHours"))           /* Log file rotation interval */
+	viperData.V2.Set("config_global.logmaxbackups", r.FormValue("LogMaxBackups"))             /* Rotated log files kept */
+	viperData.V2.Set("config_global.logmaxdays", r.FormValue("LogMaxDays"))                   /* Rotated log files retention */
+	viperData.V2.Set("config_global.logcompress", r.FormValue("LogCompress"))                 /* Compress rotated log files */
+	viperData.V2.Set("config_global.logsyslog", r.FormValue("LogSyslog"))                     /* Syslog target */
+	viperData.V2.Set("config_global.logsyslogfacility", r.FormValue("LogSyslogFacility"))     /* Syslog facility */
+	viperData.V2.Set("config_global.logsyslogtag", r.FormValue("LogSyslogTag"))               /* Syslog tag */
+	viperData.V2.Set("config_global.logsyslogonly", r.FormValue("LogSyslogOnly"))             /* Log to syslog only */
+	viperData.V2.Set("config_global.logdatabase", r.FormValue("LogDatabase"))                 /* Save log entries to the log table */
+	viperData.V2.Set("config_global.logdatabasedays", r.FormValue("LogDatabaseDays"))         /* Log table retention */
+	viperData.V2.Set("config_global.eventfeedurl", r.FormValue("EventFeedURL"))               /* Economic events calendar feed URL */
+	viperData.V2.Set("config_global.drawdownmax", r.FormValue("DrawdownMax"))                 /* Drawdown kill switch threshold */
+	viperData.V2.Set("config_global.drawdownliquidate", r.FormValue("DrawdownLiquidate"))     /* Liquidate on drawdown kill switch */
+	viperData.V2.Set("config_global.dailylossmax", r.FormValue("DailyLossMax"))               /* Daily realized loss limit */
+	viperData.V2.Set("config_global.fiatreserve", r.FormValue("FiatReserve"))                 /* Fiat reserve floor amount */
+	viperData.V2.Set("config_global.fiatreservepct", r.FormValue("FiatReservePct"))           /* Fiat reserve floor ratio */
+	viperData.V2.Set("config_global.allocatormode", r.FormValue("AllocatorMode"))             /* Allocator Mode */
+	viperData.V2.Set("config_global.allocatorweights", r.FormValue("AllocatorWeights"))       /* Allocator Weights */
+	viperData.V2.Set("config_global.allocatorinterval", r.FormValue("AllocatorInterval"))     /* Allocator Interval */
+	viperData.V2.Set("config_global.allocatorwindow", r.FormValue("AllocatorWindow"))         /* Allocator Window */
+	viperData.V2.Set("config_global.sessionidletimeout", r.FormValue("SessionIdleTimeout"))   /* UI session idle timeout in minutes */
+	viperData.V2.Set("config_global.sessionmax", r.FormValue("SessionMax"))                   /* Concurrent UI sessions per user */
 
 	if err := viperData.V2.WriteConfig(); err != nil { /* Write configuration file */
 
@@ -362,12 +621,12 @@ func SaveConfigGlobalData(
 
 }
 
-// GetConfigData Retrieve or create config file based on ThreadID
+// GetConfigData Retrieve or create config file based on ThreadID, with the overrides of its symbol applied
 func GetConfigData(
 	viperData *types.ViperData,
 	sessionData *types.Session) *types.Config {
 
-	configData := loadConfigData(viperData, sessionData)
+	configData := loadConfigData(symbolOverrides(viperData), sessionData)
 
 	if sessionData.ThreadID != "" {
 
@@ -392,7 +651,7 @@ func GetConfigData(
 
 			}
 
-			configData = loadConfigData(viperData, sessionData)
+			configData = loadConfigData(symbolOverrides(viperData), sessionData)
 
 		} else if os.IsNotExist(err) {
 
@@ -425,7 +684,7 @@ func GetConfigData(
 
 			}
 
-			configData = loadConfigData(viperData, sessionData)
+			configData = loadConfigData(symbolOverrides(viperData), sessionData)
 
 		}
 
@@ -435,6 +694,50 @@ func GetConfigData(
 
 }
 
+/* Settings of the session configurations that can't be overridden for a symbol */
+var symbolOverridesExcluded = map[string]bool{"symbol": true, "symbol_fiat": true}
+
+/* Return viperData with the settings of the symbols.<symbol> section of the session configurations layered over its config section */
+func symbolOverrides(viperData *types.ViperData) *types.ViperData {
+
+	overrides := viperData.V1.GetStringMap("symbols." + strings.ToLower(viperData.V1.GetString("config.symbol")))
+	if len(overrides) == 0 {
+
+		return viperData
+
+	}
+
+	v1 := viper.New()
+
+	if err := v1.MergeConfigMap(viperData.V1.AllSettings()); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  nil,
+			Order:    &types.Order{},
+			Message:  GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return viperData
+
+	}
+
+	for key, value := range overrides {
+
+		if !symbolOverridesExcluded[key] {
+
+			v1.Set("config."+key, value)
+
+		}
+
+	}
+
+	return &types.ViperData{V1: v1, V2: viperData.V2}
+
+}
+
 /*
 	This function retrieve the list of configuration files under the root config folder.
 
@@ -548,33 +851,146 @@ func loadConfigData(
 		BuyRepeatThresholdUp:                   viperData.V1.GetFloat64("config.buy_repeat_threshold_up"),
 		BuyRsi7Entry:                           viperData.V1.GetFloat64("config.buy_rsi7_entry"),
 		BuyWait:                                viperData.V1.GetInt64("config.buy_wait"),
+		BuyOrderBookDepthBps:                   viperData.V1.GetFloat64("config.buy_orderbook_depth_bps"),
+		BuyOrderBookAskBidRatio:                viperData.V1.GetFloat64("config.buy_orderbook_ask_bid_ratio"),
+		BuyVolatilityReturnSigma:               viperData.V1.GetFloat64("config.buy_volatility_return_sigma"),
+		BuyVolatilitySpreadSigma:               viperData.V1.GetFloat64("config.buy_volatility_spread_sigma"),
+		BuyVolatilityResume:                    viperData.V1.GetInt64("config.buy_volatility_resume"),
+		MarketDataStaleTimeout:                 viperData.V1.GetInt64("config.market_data_stale_timeout"),
+		BuySlippageMaxBps:                      viperData.V1.GetFloat64("config.buy_slippage_max_bps"),
+		BuyLossStreakCount:                     viperData.V1.GetInt("config.buy_loss_streak_count"),
+		BuyLossStreakCooldown:                  viperData.V1.GetInt64("config.buy_loss_streak_cooldown"),
+		BuyLossStreakTrendExit:                 viperData.V1.GetBool("config.buy_loss_streak_trend_exit"),
+		BuyEventBlackout:                       viperData.V1.GetInt64("config.buy_event_blackout"),
+		BuyCorrelationMax:                      viperData.V1.GetFloat64("config.buy_correlation_max"),
+		BuyCorrelationExposureMax:              viperData.V1.GetFloat64("config.buy_correlation_exposure_max"),
+		BuyCorrelationWindow:                   viperData.V1.GetInt("config.buy_correlation_window"),
+		BuyExposureMax:                         viperData.V1.GetFloat64("config.buy_exposure_max"),
+		BuySizingMode:                          viperData.V1.GetString("config.buy_sizing_mode"),
+		BuySizingFraction:                      viperData.V1.GetFloat64("config.buy_sizing_fraction"),
+		BuySizingVolatilityTarget:              viperData.V1.GetFloat64("config.buy_sizing_volatility_target"),
+		BuySizingKellyFraction:                 viperData.V1.GetFloat64("config.buy_sizing_kelly_fraction"),
+		BuyScoreThreshold:                      viperData.V1.GetFloat64("config.buy_score_threshold"),
+		BuyScoreRsi7:                           viperData.V1.GetFloat64("config.buy_score_rsi7"),
+		BuyScoreMacd:                           viperData.V1.GetFloat64("config.buy_score_macd"),
+		BuyScoreDirection:                      viperData.V1.GetFloat64("config.buy_score_direction"),
+		BuyScoreMa:                             viperData.V1.GetFloat64("config.buy_score_ma"),
+		BuyScoreOrderBook:                      viperData.V1.GetFloat64("config.buy_score_orderbook"),
 		ExchangeComission:                      viperData.V1.GetFloat64("config.exchange_comission"),
 		ProfitMin:                              viperData.V1.GetFloat64("config.profit_min"),
+		ProfitNetMin:                           viperData.V1.GetFloat64("config.profit_net_min"),
 		SellWaitBeforeCancel:                   viperData.V1.GetInt64("config.sellwaitbeforecancel"),
 		SellWaitAfterCancel:                    viperData.V1.GetInt64("config.sellwaitaftercancel"),
 		SellToCover:                            viperData.V1.GetBool("config.selltocover"),
 		SellHoldOnRSI3:                         viperData.V1.GetFloat64("config.sellholdonrsi3"),
 		Stoploss:                               viperData.V1.GetFloat64("config.stoploss"),
+		SellVolatilityStoploss:                 viperData.V1.GetFloat64("config.sell_volatility_stoploss"),
+		SellTrailingActivation:                 viperData.V1.GetFloat64("config.selltrailingactivation"),
+		SellTrailingDistance:                   viperData.V1.GetFloat64("config.selltrailingdistance"),
+		SellConfirmNotional:                    viperData.V1.GetFloat64("config.sell_confirm_notional"),
 		SymbolFiat:                             viperData.V1.GetString("config.symbol_fiat"),
 		SymbolFiatStash:                        viperData.V1.GetFloat64("config.symbol_fiat_stash"),
 		Symbol:                                 viperData.V1.GetString("config.symbol"),
 		TimeEnforce:                            viperData.V1.GetBool("config.time_enforce"),
 		TimeStart:                              viperData.V1.GetString("config.time_start"),
 		TimeStop:                               viperData.V1.GetString("config.time_stop"),
+		TimeUTC:                                viperData.V1.GetBool("config.time_utc"),
+		TimeSkipWeekends:                       viperData.V1.GetBool("config.time_skip_weekends"),
+		ScheduleStart:                          viperData.V1.GetString("config.schedule_start"),
+		ScheduleStop:                           viperData.V1.GetString("config.schedule_stop"),
+		SchedulePause:                          viperData.V1.GetString("config.schedule_pause"),
+		ScheduleResume:                         viperData.V1.GetString("config.schedule_resume"),
 		Debug:                                  viperData.V1.GetBool("config.debug"),
 		Exit:                                   viperData.V1.GetBool("config.exit"),
 		DryRun:                                 viperData.V1.GetBool("config.dryrun"),
+		Rebalance:                              viperData.V1.GetBool("config.rebalance"),
+		RebalanceWeights:                       viperData.V1.GetString("config.rebalance_weights"),
+		RebalanceBand:                          viperData.V1.GetFloat64("config.rebalance_band"),
 		NewSession:                             viperData.V1.GetBool("config.newsession"),
 		ConfigTemplateList:                     getConfigTemplateList(sessionData),
 		ExchangeName:                           viperData.V1.GetString("config.exchangename"),
 		TestNet:                                viperData.V1.GetBool("config.testnet"),
 		HTMLSnippet:                            nil,
 		ConfigGlobal: &types.ConfigGlobal{
-			Apikey:           viperData.V2.GetString("config_global.apiKey"),
-			Secretkey:        viperData.V2.GetString("config_global.secretKey"),
-			ApikeyTestNet:    viperData.V2.GetString("config_global.apiKeyTestNet"),
-			SecretkeyTestNet: viperData.V2.GetString("config_global.secretKeyTestNet"),
-			TgBotApikey:      viperData.V2.GetString("config_global.tgbotapikey")},
+			Apikey:              viperData.V2.GetString("config_global.apiKey"),
+			Secretkey:           viperData.V2.GetString("config_global.secretKey"),
+			ApikeyTestNet:       viperData.V2.GetString("config_global.apiKeyTestNet"),
+			SecretkeyTestNet:    viperData.V2.GetString("config_global.secretKeyTestNet"),
+			TgBotApikey:         viperData.V2.GetString("config_global.tgbotapikey"),
+			TgChatIDs:           viperData.V2.GetString("config_global.tgchatids"),
+			DiscordWebhookURL:   viperData.V2.GetString("config_global.discordwebhookurl"),
+			DiscordBotToken:     viperData.V2.GetString("config_global.discordbottoken"),
+			DiscordChannelID:    viperData.V2.GetString("config_global.discordchannelid"),
+			DiscordEvents:       viperData.V2.GetString("config_global.discordevents"),
+			SlackWebhookURL:     viperData.V2.GetString("config_global.slackwebhookurl"),
+			SlackBotToken:       viperData.V2.GetString("config_global.slackbottoken"),
+			SlackChannel:        viperData.V2.GetString("config_global.slackchannel"),
+			MatrixServerURL:     viperData.V2.GetString("config_global.matrixserverurl"),
+			MatrixAccessToken:   viperData.V2.GetString("config_global.matrixaccesstoken"),
+			MatrixRoomID:        viperData.V2.GetString("config_global.matrixroomid"),
+			SMTPHost:            viperData.V2.GetString("config_global.smtphost"),
+			SMTPPort:            viperData.V2.GetInt("config_global.smtpport"),
+			SMTPUsername:        viperData.V2.GetString("config_global.smtpusername"),
+			SMTPPassword:        viperData.V2.GetString("config_global.smtppassword"),
+			EmailFrom:           viperData.V2.GetString("config_global.emailfrom"),
+			EmailTo:             viperData.V2.GetString("config_global.emailto"),
+			EmailDigestTime:     viperData.V2.GetString("config_global.emaildigesttime"),
+			PushoverToken:       viperData.V2.GetString("config_global.pushovertoken"),
+			PushoverUser:        viperData.V2.GetString("config_global.pushoveruser"),
+			NtfyURL:             viperData.V2.GetString("config_global.ntfyurl"),
+			NtfyTopic:           viperData.V2.GetString("config_global.ntfytopic"),
+			NtfyToken:           viperData.V2.GetString("config_global.ntfytoken"),
+			TwilioAccountSID:    viperData.V2.GetString("config_global.twilioaccountsid"),
+			TwilioAuthToken:     viperData.V2.GetString("config_global.twilioauthtoken"),
+			TwilioFrom:          viperData.V2.GetString("config_global.twiliofrom"),
+			SMSTo:               viperData.V2.GetString("config_global.smsto"),
+			SMSRateMax:          viperData.V2.GetInt("config_global.smsratemax"),
+			DbDownMinutes:       viperData.V2.GetInt("config_global.dbdownminutes"),
+			NotifyRateMax:       viperData.V2.GetInt("config_global.notifyratemax"),
+			NotifyRoutes:        viperData.V2.GetString("config_global.notifyroutes"),
+			ErrorBurstMax:       viperData.V2.GetInt("config_global.errorburstmax"),
+			ErrorBurstWindow:    viperData.V2.GetInt("config_global.errorburstwindow"),
+			SummarySchedules:    viperData.V2.GetString("config_global.summaryschedules"),
+			OtlpEndpoint:        viperData.V2.GetString("config_global.otlpendpoint"),
+			SentryDsn:           viperData.V2.GetString("config_global.sentrydsn"),
+			ExchangeSlowMs:      viperData.V2.GetInt("config_global.exchangeslowms"),
+			ExchangeConcurrency: viperData.V2.GetInt("config_global.exchangeconcurrency"),
+			WatchdogInterval:    viperData.V2.GetInt("config_global.watchdoginterval"),
+			WatchdogSamples:     viperData.V2.GetInt("config_global.watchdogsamples"),
+			WatchdogGoroutines:  viperData.V2.GetInt("config_global.watchdoggoroutines"),
+			WatchdogHeapMB:      viperData.V2.GetInt("config_global.watchdogheapmb"),
+			WatchdogRestart:     viperData.V2.GetBool("config_global.watchdogrestart"),
+			SupervisorTimeout:   viperData.V2.GetInt("config_global.supervisortimeout"),
+			SupervisorBackoff:   viperData.V2.GetInt("config_global.supervisorbackoff"),
+			SupervisorMaxDelay:  viperData.V2.GetInt("config_global.supervisormaxdelay"),
+			LogLevel:            viperData.V2.GetString("config_global.loglevel"),
+			LogLevelExchange:    viperData.V2.GetString("config_global.loglevelexchange"),
+			LogLevelMysql:       viperData.V2.GetString("config_global.loglevelmysql"),
+			LogLevelThreads:     viperData.V2.GetString("config_global.loglevelthreads"),
+			LogLevelAlgorithms:  viperData.V2.GetString("config_global.loglevelalgorithms"),
+			LogMaxSize:          viperData.V2.GetInt("config_global.logmaxsize"),
+			LogRotateHours:      viperData.V2.GetInt("config_global.logrotatehours"),
+			LogMaxBackups:       viperData.V2.GetInt("config_global.logmaxbackups"),
+			LogMaxDays:          viperData.V2.GetInt("config_global.logmaxdays"),
+			LogCompress:         viperData.V2.GetBool("config_global.logcompress"),
+			LogSyslog:           viperData.V2.GetString("config_global.logsyslog"),
+			LogSyslogFacility:   viperData.V2.GetString("config_global.logsyslogfacility"),
+			LogSyslogTag:        viperData.V2.GetString("config_global.logsyslogtag"),
+			LogSyslogOnly:       viperData.V2.GetBool("config_global.logsyslogonly"),
+			LogDatabase:         viperData.V2.GetBool("config_global.logdatabase"),
+			LogDatabaseDays:     viperData.V2.GetInt("config_global.logdatabasedays"),
+			EventFeedURL:        viperData.V2.GetString("config_global.eventfeedurl"),
+			DrawdownMax:         viperData.V2.GetFloat64("config_global.drawdownmax"),
+			DrawdownLiquidate:   viperData.V2.GetBool("config_global.drawdownliquidate"),
+			DailyLossMax:        viperData.V2.GetFloat64("config_global.dailylossmax"),
+			FiatReserve:         viperData.V2.GetFloat64("config_global.fiatreserve"),
+			FiatReservePct:      viperData.V2.GetFloat64("config_global.fiatreservepct"),
+			AllocatorMode:       viperData.V2.GetString("config_global.allocatormode"),
+			AllocatorWeights:    viperData.V2.GetString("config_global.allocatorweights"),
+			AllocatorInterval:   viperData.V2.GetInt("config_global.allocatorinterval"),
+			AllocatorWindow:     viperData.V2.GetInt("config_global.allocatorwindow"),
+			SessionIdleTimeout:  viperData.V2.GetInt("config_global.sessionidletimeout"),
+			SessionMax:          viperData.V2.GetInt("config_global.sessionmax")},
 	}
 
 	return configData
@@ -595,6 +1011,31 @@ func SaveConfigData(
 	viperData.V1.Set("config.buy_quantity_fiat_init", r.PostFormValue("buyQuantityFiatInit"))
 	viperData.V1.Set("config.buy_rsi7_entry", r.PostFormValue("buyRsi7Entry"))
 	viperData.V1.Set("config.buy_wait", r.PostFormValue("buyWait"))
+	viperData.V1.Set("config.buy_orderbook_depth_bps", r.PostFormValue("buyOrderBookDepthBps"))
+	viperData.V1.Set("config.buy_orderbook_ask_bid_ratio", r.PostFormValue("buyOrderBookAskBidRatio"))
+	viperData.V1.Set("config.buy_slippage_max_bps", r.PostFormValue("buySlippageMaxBps"))
+	viperData.V1.Set("config.buy_volatility_return_sigma", r.PostFormValue("buyVolatilityReturnSigma"))
+	viperData.V1.Set("config.buy_volatility_spread_sigma", r.PostFormValue("buyVolatilitySpreadSigma"))
+	viperData.V1.Set("config.buy_volatility_resume", r.PostFormValue("buyVolatilityResume"))
+	viperData.V1.Set("config.market_data_stale_timeout", r.PostFormValue("marketDataStaleTimeout"))
+	viperData.V1.Set("config.buy_loss_streak_count", r.PostFormValue("buyLossStreakCount"))
+	viperData.V1.Set("config.buy_loss_streak_cooldown", r.PostFormValue("buyLossStreakCooldown"))
+	viperData.V1.Set("config.buy_loss_streak_trend_exit", r.PostFormValue("buyLossStreakTrendExit"))
+	viperData.V1.Set("config.buy_event_blackout", r.PostFormValue("buyEventBlackout"))
+	viperData.V1.Set("config.buy_correlation_max", r.PostFormValue("buyCorrelationMax"))
+	viperData.V1.Set("config.buy_correlation_exposure_max", r.PostFormValue("buyCorrelationExposureMax"))
+	viperData.V1.Set("config.buy_correlation_window", r.PostFormValue("buyCorrelationWindow"))
+	viperData.V1.Set("config.buy_exposure_max", r.PostFormValue("buyExposureMax"))
+	viperData.V1.Set("config.buy_sizing_mode", r.PostFormValue("buySizingMode"))
+	viperData.V1.Set("config.buy_sizing_fraction", r.PostFormValue("buySizingFraction"))
+	viperData.V1.Set("config.buy_sizing_volatility_target", r.PostFormValue("buySizingVolatilityTarget"))
+	viperData.V1.Set("config.buy_sizing_kelly_fraction", r.PostFormValue("buySizingKellyFraction"))
+	viperData.V1.Set("config.buy_score_threshold", r.PostFormValue("buyScoreThreshold"))
+	viperData.V1.Set("config.buy_score_rsi7", r.PostFormValue("buyScoreRsi7"))
+	viperData.V1.Set("config.buy_score_macd", r.PostFormValue("buyScoreMacd"))
+	viperData.V1.Set("config.buy_score_direction", r.PostFormValue("buyScoreDirection"))
+	viperData.V1.Set("config.buy_score_ma", r.PostFormValue("buyScoreMa"))
+	viperData.V1.Set("config.buy_score_orderbook", r.PostFormValue("buyScoreOrderBook"))
 	viperData.V1.Set("config.buy_repeat_threshold_down", r.PostFormValue("buyRepeatThresholdDown"))
 	viperData.V1.Set("config.buy_repeat_threshold_down_second", r.PostFormValue("buyRepeatThresholdDownSecond"))
 	viperData.V1.Set("config.buy_repeat_threshold_down_second_start_count", r.PostFormValue("buyRepeatThresholdDownSecondStartCount"))
@@ -604,11 +1045,16 @@ func SaveConfigData(
 		viperData.V1.Set("config.exchangename", r.PostFormValue("exchangename"))
 	}
 	viperData.V1.Set("config.profit_min", r.PostFormValue("profitMin"))
+	viperData.V1.Set("config.profit_net_min", r.PostFormValue("profitNetMin"))
 	viperData.V1.Set("config.sellwaitbeforecancel", r.PostFormValue("sellwaitbeforecancel"))
 	viperData.V1.Set("config.sellwaitaftercancel", r.PostFormValue("sellwaitaftercancel"))
 	viperData.V1.Set("config.selltocover", r.PostFormValue("selltocover"))
 	viperData.V1.Set("config.sellholdonrsi3", r.PostFormValue("sellholdonrsi3"))
 	viperData.V1.Set("config.Stoploss", r.PostFormValue("stoploss"))
+	viperData.V1.Set("config.sell_volatility_stoploss", r.PostFormValue("sellVolatilityStoploss"))
+	viperData.V1.Set("config.selltrailingactivation", r.PostFormValue("selltrailingactivation"))
+	viperData.V1.Set("config.selltrailingdistance", r.PostFormValue("selltrailingdistance"))
+	viperData.V1.Set("config.sell_confirm_notional", r.PostFormValue("sellConfirmNotional"))
 	if r.PostFormValue("exchangename") != "" { /* Test for disabled input in index_nostart.html where return is nil */
 		viperData.V1.Set("config.symbol", r.PostFormValue("symbol"))
 	}
@@ -619,12 +1065,17 @@ func SaveConfigData(
 	viperData.V1.Set("config.time_enforce", r.PostFormValue("timeEnforce"))
 	viperData.V1.Set("config.time_start", r.PostFormValue("timeStart"))
 	viperData.V1.Set("config.time_stop", r.PostFormValue("timeStop"))
+	viperData.V1.Set("config.time_utc", r.PostFormValue("timeUTC"))
+	viperData.V1.Set("config.time_skip_weekends", r.PostFormValue("timeSkipWeekends"))
 	if r.PostFormValue("exchangename") != "" { /* Test for disabled input in index_nostart.html where return is nil */
 		viperData.V1.Set("config.testnet", r.PostFormValue("testnet"))
 	}
 	viperData.V1.Set("config.debug", r.PostFormValue("debug"))
 	viperData.V1.Set("config.exit", r.PostFormValue("exit"))
 	viperData.V1.Set("config.dryrun", r.PostFormValue("dryrun"))
+	viperData.V1.Set("config.rebalance", r.PostFormValue("rebalance"))
+	viperData.V1.Set("config.rebalance_weights", r.PostFormValue("rebalanceWeights"))
+	viperData.V1.Set("config.rebalance_band", r.PostFormValue("rebalanceBand"))
 	if r.PostFormValue("exchangename") != "" { /* Test for disabled input in index_nostart.html where return is nil */
 		viperData.V1.Set("config.newsession", r.PostFormValue(("newsession")))
 	}
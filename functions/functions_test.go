@@ -2,6 +2,10 @@ package functions
 
 import (
 	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+	"github.com/spf13/viper"
 )
 
 func TestFloat64ToStr(t *testing.T) {
@@ -32,6 +36,50 @@ func TestFloat64ToStr(t *testing.T) {
 	}
 }
 
+func TestRound(t *testing.T) {
+	type args struct {
+		value    float64
+		decimals int
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "2 decimals",
+			args: args{
+				value:    1.23456,
+				decimals: 2,
+			},
+			want: 1.23,
+		},
+		{
+			name: "negative",
+			args: args{
+				value:    -2.345,
+				decimals: 1,
+			},
+			want: -2.3,
+		},
+		{
+			name: "integer",
+			args: args{
+				value:    2.5,
+				decimals: 0,
+			},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Round(tt.args.value, tt.args.decimals); got != tt.want {
+				t.Errorf("Round() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIntToFloat64(t *testing.T) {
 	type args struct {
 		value int
@@ -128,3 +176,77 @@ func TestGetThreadID(t *testing.T) {
 		})
 	}
 }
+
+func TestIsInTradingWindow(t *testing.T) {
+	type args struct {
+		configData *types.Config
+		t          time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "inside",
+			args: args{
+				configData: &types.Config{TimeEnforce: true, TimeUTC: true, TimeStart: "12:00AM", TimeStop: "8:00AM"},
+				t:          time.Date(2021, 11, 17, 5, 0, 0, 0, time.UTC),
+			},
+			want: true,
+		},
+		{
+			name: "outside",
+			args: args{
+				configData: &types.Config{TimeEnforce: true, TimeUTC: true, TimeStart: "00:00", TimeStop: "08:00"},
+				t:          time.Date(2021, 11, 17, 9, 0, 0, 0, time.UTC),
+			},
+			want: false,
+		},
+		{
+			name: "overnight",
+			args: args{
+				configData: &types.Config{TimeEnforce: true, TimeUTC: true, TimeStart: "22:00", TimeStop: "06:00"},
+				t:          time.Date(2021, 11, 17, 23, 30, 0, 0, time.UTC),
+			},
+			want: true,
+		},
+		{
+			name: "weekend",
+			args: args{
+				configData: &types.Config{TimeEnforce: false, TimeSkipWeekends: true},
+				t:          time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsInTradingWindow(tt.args.configData, tt.args.t); got != tt.want {
+				t.Errorf("IsInTradingWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_symbolOverrides(t *testing.T) {
+
+	v1 := viper.New()
+	v1.Set("config.symbol", "SHIBUSDT")
+	v1.Set("config.buy_quantity_fiat_up", "50")
+	v1.Set("config.stoploss", "0.05")
+	v1.Set("symbols.shibusdt.buy_quantity_fiat_up", "10")
+	v1.Set("symbols.shibusdt.symbol", "BTCUSDT")
+	v1.Set("symbols.btcusdt.stoploss", "0.02")
+
+	got := symbolOverrides(&types.ViperData{V1: v1, V2: viper.New()}).V1
+
+	if got.GetFloat64("config.buy_quantity_fiat_up") != 10 || got.GetFloat64("config.stoploss") != 0.05 || got.GetString("config.symbol") != "SHIBUSDT" {
+		t.Errorf("symbolOverrides() = %v, want buy_quantity_fiat_up 10, stoploss 0.05 and symbol SHIBUSDT", got.AllSettings()["config"])
+	}
+
+	if v1.GetFloat64("config.buy_quantity_fiat_up") != 50 {
+		t.Errorf("symbolOverrides() changed the session configurations")
+	}
+
+}
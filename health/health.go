@@ -0,0 +1,236 @@
+package health
+
+/* This package implements the liveness (/healthz) and readiness (/readyz) endpoints for container orchestrator probes
+and external uptime monitors. Both return a JSON report of the database connectivity, the exchange API reachability,
+the websocket status of the running thread and the heartbeat age of every thread in the session table, with status
+200 when healthy and 503 otherwise. /healthz fails only when the running thread received no websocket data for
+livenessTimeout, a stuck process that a restart recovers. /readyz also fails when the database or the exchange API is
+unreachable or the websocket data is stale. The endpoints are not authenticated so probes can reach them. */
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/markets"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* URI paths of the health endpoints */
+const (
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+)
+
+/* Health check limits */
+const (
+	livenessTimeout  = 5 * time.Minute  /* Websocket data age that fails the liveness check */
+	exchangeTimeout  = 30 * time.Second /* Age of the last successful exchange latency check, checked every 5 seconds */
+	heartbeatTimeout = time.Minute      /* Heartbeat age of a stalled thread, updated every 10 seconds */
+	pingTimeout      = 2 * time.Second  /* Database ping timeout */
+)
+
+/* Report status */
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// ErrNotConnected is returned when the database connection is not initialized
+var ErrNotConnected = errors.New("Database not connected")
+
+// Check struct define the result of a health check
+type Check struct {
+	Name       string  `json:"name"` /* database, exchange, websocket.kline or websocket.bookticker */
+	OK         bool    `json:"ok"`
+	AgeSeconds float64 `json:"ageSeconds,omitempty"` /* Seconds since the last successful check or update */
+	Error      string  `json:"error,omitempty"`
+}
+
+// Heartbeat struct define the heartbeat age of a thread in the session table
+type Heartbeat struct {
+	ThreadID   string  `json:"threadId"`
+	AgeSeconds float64 `json:"ageSeconds"` /* -1 when the thread has no heartbeat */
+	OK         bool    `json:"ok"`
+}
+
+// Report struct define the response of the health endpoints
+type Report struct {
+	Status     string      `json:"status"`   /* ok or unavailable */
+	ThreadID   string      `json:"threadId"` /* ThreadID running in this process, empty when not running */
+	Checks     []Check     `json:"checks"`
+	Heartbeats []Heartbeat `json:"heartbeats"`
+}
+
+// State struct define the inputs of the health checks
+type State struct {
+	ThreadID         string
+	DbErr            error            /* Database ping error */
+	LastExchangeTime time.Time        /* Last successful exchange latency check */
+	LastKlineTime    time.Time        /* Last websocket kline */
+	LastTickerTime   time.Time        /* Last websocket book ticker */
+	StaleTimeout     time.Duration    /* Websocket data age that fails the readiness check */
+	Heartbeats       map[string]int64 /* Heartbeat time in milliseconds of each ThreadID */
+}
+
+// Handler serve the health endpoints of the thread running in this process
+type Handler struct {
+	SessionData *types.Session
+	ViperData   *types.ViperData
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")  /* Set the Content-Type header */
+	w.Header().Set("X-Content-Type-Options", "nosniff") /* Add X-Content-Type-Options header */
+	w.Header().Set("Cache-Control", "no-store")         /* Probes always get the current status */
+
+	if r.Method != "GET" && r.Method != "HEAD" {
+
+		w.Header().Set("Allow", "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+
+	}
+
+	report := Evaluate(h.state(), time.Now(), r.URL.Path == ReadyPath)
+
+	status := http.StatusOK
+	if report.Status != statusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+
+}
+
+/* Collect the state of the thread running in this process and the heartbeats of the session table */
+func (h *Handler) state() (state State) {
+
+	configData := functions.GetConfigData(h.ViperData, h.SessionData) /* Get configuration data */
+
+	state = State{
+		ThreadID:         h.SessionData.ThreadID,
+		LastExchangeTime: h.SessionData.LastExchangeTime,
+		LastKlineTime:    h.SessionData.LastWsKlineTime,
+		LastTickerTime:   h.SessionData.LastWsBookTickerTime,
+		StaleTimeout:     markets.StaleTimeout(configData),
+	}
+
+	if h.SessionData.Db == nil {
+
+		state.DbErr = ErrNotConnected
+		return state
+
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	if state.DbErr = h.SessionData.Db.PingContext(ctx); state.DbErr == nil {
+
+		state.Heartbeats, state.DbErr = mysql.GetSessionHeartbeats(h.SessionData)
+
+	}
+
+	return state
+
+}
+
+// Evaluate the health checks of state at now. The liveness report fails only when the running thread received no
+// websocket data for livenessTimeout, the readiness report (ready true) fails when any check fails.
+func Evaluate(
+	state State,
+	now time.Time,
+	ready bool) (report Report) {
+
+	report = Report{Status: statusOK, ThreadID: state.ThreadID, Checks: []Check{}, Heartbeats: []Heartbeat{}}
+
+	database := Check{Name: "database", OK: state.DbErr == nil}
+	if state.DbErr != nil {
+		database.Error = state.DbErr.Error()
+	}
+
+	report.Checks = append(report.Checks, database)
+
+	if state.ThreadID != "" { /* The exchange and websockets are only connected while the thread is running */
+
+		report.Checks = append(report.Checks,
+			ageCheck("exchange", state.LastExchangeTime, exchangeTimeout, now),
+			ageCheck("websocket.kline", state.LastKlineTime, state.StaleTimeout, now),
+			ageCheck("websocket.bookticker", state.LastTickerTime, state.StaleTimeout, now))
+
+		lastData := state.LastKlineTime
+		if state.LastTickerTime.After(lastData) {
+			lastData = state.LastTickerTime
+		}
+
+		if !lastData.IsZero() && now.Sub(lastData) >= livenessTimeout { /* Websockets connecting at startup are not stuck */
+			report.Status = statusUnavailable
+		}
+
+	}
+
+	if ready {
+
+		for _, check := range report.Checks {
+
+			if !check.OK {
+				report.Status = statusUnavailable
+			}
+
+		}
+
+	}
+
+	for threadID, heartbeat := range state.Heartbeats {
+
+		beat := Heartbeat{ThreadID: threadID, AgeSeconds: -1}
+
+		if heartbeat > 0 {
+
+			age := now.Sub(time.Unix(0, heartbeat*int64(time.Millisecond)))
+			beat.AgeSeconds = age.Seconds()
+			beat.OK = age < heartbeatTimeout
+
+		}
+
+		report.Heartbeats = append(report.Heartbeats, beat)
+
+	}
+
+	sort.Slice(report.Heartbeats, func(i, j int) bool { return report.Heartbeats[i].ThreadID < report.Heartbeats[j].ThreadID })
+
+	return report
+
+}
+
+/* Return a check that fails when last is zero or older than timeout */
+func ageCheck(
+	name string,
+	last time.Time,
+	timeout time.Duration,
+	now time.Time) Check {
+
+	if last.IsZero() {
+
+		return Check{Name: name, OK: false, Error: "no update yet"}
+
+	}
+
+	age := now.Sub(last)
+
+	check := Check{Name: name, OK: age < timeout, AgeSeconds: age.Seconds()}
+	if !check.OK {
+		check.Error = "no update for " + age.Truncate(time.Second).String()
+	}
+
+	return check
+
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2021, 12, 1, 10, 15, 0, 0, time.UTC)
+	running := State{
+		ThreadID:         "c683ok5mk1u1120gnmmg",
+		LastExchangeTime: now.Add(-5 * time.Second),
+		LastKlineTime:    now.Add(-20 * time.Second),
+		LastTickerTime:   now.Add(-time.Second),
+		StaleTimeout:     100 * time.Second,
+	}
+	stale := running
+	stale.LastTickerTime = now.Add(-2 * time.Minute)
+	stale.LastKlineTime = now.Add(-2 * time.Minute)
+	stuck := running
+	stuck.LastTickerTime = now.Add(-10 * time.Minute)
+	stuck.LastKlineTime = now.Add(-10 * time.Minute)
+	dbDown := running
+	dbDown.DbErr = errors.New("dial tcp: connection refused")
+	starting := State{ThreadID: "c683ok5mk1u1120gnmmg", StaleTimeout: 100 * time.Second}
+	tests := []struct {
+		name  string
+		state State
+		ready bool
+		want  string
+	}{
+		{name: "ready", state: running, ready: true, want: statusOK},
+		{name: "not running", state: State{}, ready: true, want: statusOK},
+		{name: "stale websocket not ready", state: stale, ready: true, want: statusUnavailable},
+		{name: "stale websocket alive", state: stale, ready: false, want: statusOK},
+		{name: "stuck websocket not alive", state: stuck, ready: false, want: statusUnavailable},
+		{name: "database down not ready", state: dbDown, ready: true, want: statusUnavailable},
+		{name: "database down alive", state: dbDown, ready: false, want: statusOK},
+		{name: "starting not ready", state: starting, ready: true, want: statusUnavailable},
+		{name: "starting alive", state: starting, ready: false, want: statusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Evaluate(tt.state, now, tt.ready); got.Status != tt.want {
+				t.Errorf("Evaluate() status = %v, want %v, checks %+v", got.Status, tt.want, got.Checks)
+			}
+		})
+	}
+}
+
+func TestEvaluate_heartbeats(t *testing.T) {
+	now := time.Date(2021, 12, 1, 10, 15, 0, 0, time.UTC)
+	state := State{Heartbeats: map[string]int64{
+		"c683ok5mk1u1120gnmng": now.Add(-5*time.Minute).UnixNano() / int64(time.Millisecond),
+		"c683ok5mk1u1120gnmmg": now.Add(-10*time.Second).UnixNano() / int64(time.Millisecond),
+		"c683ok5mk1u1120gnmo0": 0,
+	}}
+	want := []Heartbeat{
+		{ThreadID: "c683ok5mk1u1120gnmmg", AgeSeconds: 10, OK: true},
+		{ThreadID: "c683ok5mk1u1120gnmng", AgeSeconds: 300, OK: false},
+		{ThreadID: "c683ok5mk1u1120gnmo0", AgeSeconds: -1, OK: false},
+	}
+	got := Evaluate(state, now, true).Heartbeats
+	if len(got) != len(want) {
+		t.Fatalf("Evaluate() heartbeats = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Evaluate() heartbeat = %+v, want %+v", got[i], want[i])
+		}
+	}
+}
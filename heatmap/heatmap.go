@@ -0,0 +1,272 @@
+package heatmap
+
+/* This package implements the profit heatmap. The realized profit of the sales of each thread is aggregated by day
+of week and hour of day, in UTC or local time as the trading window of the configuration (TimeUTC), so the hours
+and days where a strategy makes or loses money can be compared with the configured trading window (TimeStart,
+TimeStop and TimeSkipWeekends). */
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/labels"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	defaultDays = 90 /* Days aggregated when From is empty */
+	dateLayout  = "2006-01-02"
+)
+
+// Days list the heatmap rows, Monday first
+var Days = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+/* Heatmap errors */
+var (
+	ErrInvalidDate  = errors.New("Dates must be YYYY-MM-DD")
+	ErrInvalidRange = errors.New("From must be before To")
+)
+
+// Cell struct define the realized profit of the sales in an hour of a day of week
+type Cell struct {
+	Profit   float64
+	Trades   int
+	InWindow bool         /* Hour inside the configured trading window */
+	Style    template.CSS `json:"-"` /* Background color scaled by profit */
+}
+
+// Heatmap struct define the realized profit of a thread, or of all threads, by day of week and hour of day
+type Heatmap struct {
+	ThreadID string /* Empty for all threads */
+	Symbol   string
+	Profit   float64
+	Trades   int
+	Cells    [7][24]Cell /* Days, Monday first, by hour */
+}
+
+// Page struct define the profit heatmap page (heatmap.html) and the heatmap API response
+type Page struct {
+	ThreadID string /* Empty for all threads */
+	From     string /* YYYY-MM-DD */
+	To       string
+	UTC      bool   /* Hours in UTC, otherwise local time */
+	Window   string /* Configured trading window, empty when not enforced */
+	Days     []string
+	Heatmaps []Heatmap /* All threads first, then each thread by profit */
+	Message  string    `json:",omitempty"`
+	Theme    string    `json:"-"` /* UI theme of the logged in user */
+}
+
+// Load the heatmaps of the sales between from and to (YYYY-MM-DD, To inclusive) of threadID, or of the sessions named
+// or tagged threadID, all threads when empty.
+// An empty To is today and an empty From is 90 days before To.
+func Load(
+	configData *types.Config,
+	sessionData *types.Session,
+	threadID string,
+	from string,
+	to string) (page Page, err error) {
+
+	var start, end time.Time
+	var trades []types.Trade
+
+	page.ThreadID = strings.TrimSpace(threadID)
+	page.UTC = configData.TimeUTC
+	page.Days = Days
+
+	if configData.TimeEnforce {
+		page.Window = configData.TimeStart + " - " + configData.TimeStop
+	}
+
+	if start, end, err = dateRange(from, to, time.Now()); err != nil {
+
+		return page, err
+
+	}
+
+	page.From = start.Format(dateLayout)
+	page.To = end.AddDate(0, 0, -1).Format(dateLayout)
+
+	threadIDs := make(map[string]bool)
+
+	for _, id := range labels.Resolve(sessionData, page.ThreadID) {
+		threadIDs[id] = true
+	}
+
+	if err = mysql.ExportTrades(sessionData, start.UnixNano()/int64(time.Millisecond), end.UnixNano()/int64(time.Millisecond), func(trade types.Trade) error {
+
+		if trade.Side == "SELL" && (page.ThreadID == "" || threadIDs[trade.ThreadID]) {
+			trades = append(trades, trade)
+		}
+
+		return nil
+
+	}); err != nil {
+
+		return page, err
+
+	}
+
+	page.Heatmaps = build(configData, trades)
+
+	return page, nil
+
+}
+
+/* Parse the heatmap date range, to is exclusive */
+func dateRange(
+	fromText string,
+	toText string,
+	now time.Time) (from time.Time, to time.Time, err error) {
+
+	to = now
+
+	if toText != "" {
+
+		if to, err = time.ParseInLocation(dateLayout, toText, time.Local); err != nil {
+
+			return from, to, ErrInvalidDate
+
+		}
+
+	}
+
+	y, m, d := to.Date()
+	to = time.Date(y, m, d+1, 0, 0, 0, 0, time.Local) /* Include the whole To day */
+
+	from = to.AddDate(0, 0, -defaultDays)
+
+	if fromText != "" {
+
+		if from, err = time.ParseInLocation(dateLayout, fromText, time.Local); err != nil {
+
+			return from, to, ErrInvalidDate
+
+		}
+
+	}
+
+	if !from.Before(to) {
+
+		return from, to, ErrInvalidRange
+
+	}
+
+	return from, to, nil
+
+}
+
+/* Aggregate the profit of sales into a heatmap of all threads followed by a heatmap of each thread by profit */
+func build(
+	configData *types.Config,
+	sales []types.Trade) (heatmaps []Heatmap) {
+
+	all := &Heatmap{}
+	threads := make(map[string]*Heatmap)
+
+	for _, sale := range sales {
+
+		t := time.Unix(0, sale.TransactTime*int64(time.Millisecond)).Local()
+		if configData.TimeUTC {
+			t = t.UTC()
+		}
+
+		thread, ok := threads[sale.ThreadID]
+		if !ok {
+			thread = &Heatmap{ThreadID: sale.ThreadID, Symbol: sale.Symbol}
+			threads[sale.ThreadID] = thread
+		}
+
+		day := (int(t.Weekday()) + 6) % 7 /* Monday first */
+
+		for _, heatmap := range []*Heatmap{all, thread} {
+
+			heatmap.Profit += sale.Profit
+			heatmap.Trades++
+			heatmap.Cells[day][t.Hour()].Profit += sale.Profit
+			heatmap.Cells[day][t.Hour()].Trades++
+
+		}
+
+	}
+
+	heatmaps = append(heatmaps, *all)
+
+	for _, thread := range threads {
+		heatmaps = append(heatmaps, *thread)
+	}
+
+	sort.SliceStable(heatmaps[1:], func(i, j int) bool { return heatmaps[i+1].Profit > heatmaps[j+1].Profit })
+
+	for i := range heatmaps {
+		heatmaps[i].finish(configData)
+	}
+
+	return heatmaps
+
+}
+
+/* Round the profits, mark the trading window and color the cells relative to the largest hourly profit or loss */
+func (heatmap *Heatmap) finish(configData *types.Config) {
+
+	var max float64
+
+	location := time.Local
+	if configData.TimeUTC {
+		location = time.UTC
+	}
+
+	for day := range heatmap.Cells {
+		for hour := range heatmap.Cells[day] {
+			max = math.Max(max, math.Abs(heatmap.Cells[day][hour].Profit))
+		}
+	}
+
+	heatmap.Profit = functions.Round(heatmap.Profit, 2)
+
+	for day := range heatmap.Cells {
+
+		for hour := range heatmap.Cells[day] {
+
+			cell := &heatmap.Cells[day][hour]
+
+			/* Middle of the hour in a reference week starting on Monday 2021-01-04 */
+			cell.InWindow = functions.IsInTradingWindow(configData, time.Date(2021, 1, 4+day, hour, 30, 0, 0, location))
+			cell.Style = style(cell.Profit, max)
+			cell.Profit = functions.Round(cell.Profit, 2)
+
+		}
+
+	}
+
+}
+
+/* Return the background color of a cell, green for a profit and red for a loss with an opacity scaled by max */
+func style(
+	profit float64,
+	max float64) template.CSS {
+
+	if profit == 0 || max == 0 {
+
+		return ""
+
+	}
+
+	alpha := math.Round((0.15+0.85*math.Abs(profit)/max)*100) / 100
+
+	if profit > 0 {
+
+		return template.CSS(fmt.Sprintf("background-color: rgba(40, 167, 69, %.2f)", alpha))
+
+	}
+
+	return template.CSS(fmt.Sprintf("background-color: rgba(220, 53, 69, %.2f)", alpha))
+
+}
@@ -0,0 +1,109 @@
+package heatmap
+
+import (
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_dateRange(t *testing.T) {
+	now := time.Date(2021, 12, 15, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  error
+	}{
+		{
+			name:     "default",
+			wantFrom: time.Date(2021, 9, 17, 0, 0, 0, 0, time.Local),
+			wantTo:   time.Date(2021, 12, 16, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "range",
+			from:     "2021-12-01",
+			to:       "2021-12-07",
+			wantFrom: time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local),
+			wantTo:   time.Date(2021, 12, 8, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:    "invalid date",
+			from:    "01/12/2021",
+			wantErr: ErrInvalidDate,
+		},
+		{
+			name:    "invalid range",
+			from:    "2021-12-08",
+			to:      "2021-12-07",
+			wantErr: ErrInvalidRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := dateRange(tt.from, tt.to, now)
+			if err != tt.wantErr {
+				t.Errorf("dateRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (!from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo)) {
+				t.Errorf("dateRange() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func Test_build(t *testing.T) {
+	configData := &types.Config{TimeUTC: true, TimeEnforce: true, TimeStart: "09:00", TimeStop: "17:00", TimeSkipWeekends: true}
+	monday := time.Date(2021, 12, 6, 10, 15, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+	sunday := time.Date(2021, 12, 12, 23, 45, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+
+	heatmaps := build(configData, []types.Trade{
+		{ThreadID: "a", Symbol: "BTCUSDT", TransactTime: monday, Profit: 2.5},
+		{ThreadID: "a", Symbol: "BTCUSDT", TransactTime: monday, Profit: 1.5},
+		{ThreadID: "b", Symbol: "ETHUSDT", TransactTime: sunday, Profit: -1},
+		{ThreadID: "c", Symbol: "BNBUSDT", TransactTime: monday, Profit: 6},
+	})
+
+	if len(heatmaps) != 4 {
+		t.Fatalf("build() = %v heatmaps, want 4", len(heatmaps))
+	}
+	if all := heatmaps[0]; all.ThreadID != "" || all.Profit != 9 || all.Trades != 4 || all.Cells[0][10].Profit != 10 || all.Cells[6][23].Profit != -1 {
+		t.Errorf("build() all threads = %v profit, %v trades", all.Profit, all.Trades)
+	}
+	if heatmaps[1].ThreadID != "c" || heatmaps[2].ThreadID != "a" || heatmaps[3].ThreadID != "b" {
+		t.Errorf("build() threads = %v, %v, %v, want c, a, b", heatmaps[1].ThreadID, heatmaps[2].ThreadID, heatmaps[3].ThreadID)
+	}
+	if cell := heatmaps[2].Cells[0][10]; cell.Profit != 4 || cell.Trades != 2 || !cell.InWindow || cell.Style != "background-color: rgba(40, 167, 69, 1.00)" {
+		t.Errorf("build() thread a Monday 10:00 = %v", cell)
+	}
+	if cell := heatmaps[3].Cells[6][23]; cell.InWindow || cell.Style != "background-color: rgba(220, 53, 69, 1.00)" {
+		t.Errorf("build() thread b Sunday 23:00 = %v", cell)
+	}
+	if cell := heatmaps[0].Cells[0][8]; cell.InWindow || cell.Style != "" {
+		t.Errorf("build() Monday 08:00 = %v", cell)
+	}
+}
+
+func Test_style(t *testing.T) {
+	tests := []struct {
+		name   string
+		profit float64
+		max    float64
+		want   template.CSS
+	}{
+		{name: "profit", profit: 5, max: 10, want: "background-color: rgba(40, 167, 69, 0.57)"},
+		{name: "loss", profit: -10, max: 10, want: "background-color: rgba(220, 53, 69, 1.00)"},
+		{name: "none", profit: 0, max: 10, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := style(tt.profit, tt.max); got != tt.want {
+				t.Errorf("style() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
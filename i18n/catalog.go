@@ -0,0 +1,93 @@
+package i18n
+
+// catalogs are the message catalogs keyed by the English message. Add a locale to Locales, formats and catalogs to
+// support a new language; messages missing from its catalog are displayed in English.
+var catalogs = map[string]map[string]string{
+	"pt": {
+		/* Navigation */
+		"Admin":       "Administração",
+		"Thread":      "Thread",
+		"Config":      "Configuração",
+		"Portfolio":   "Carteira",
+		"Orders":      "Ordens",
+		"Journal":     "Diário",
+		"Logs":        "Registos",
+		"Alerts":      "Alertas",
+		"Webhooks":    "Webhooks",
+		"Reports":     "Relatórios",
+		"Backtests":   "Backtests",
+		"Preferences": "Preferências",
+		"Security":    "Segurança",
+		"Logout":      "Terminar Sessão",
+		"New":         "Novo",
+		"Start":       "Iniciar",
+		"Stop":        "Parar",
+		"Update":      "Atualizar",
+		"Save":        "Guardar",
+		"Back":        "Voltar",
+		"Thread configuration, indicators, open transactions and closed cycles":             "Configuração, indicadores, transações abertas e ciclos fechados da thread",
+		"Edit and validate the configuration, applied to running threads within 10 seconds": "Editar e validar a configuração, aplicada às threads em execução em 10 segundos",
+		"Balances and open transactions across all exchange accounts and threads":           "Saldos e transações abertas de todas as contas de exchange e threads",
+		"Order history sorted, filtered and paginated by the database":                      "Histórico de ordens ordenado, filtrado e paginado pela base de dados",
+		"Operator notes and tags attached to orders and sessions":                           "Notas e etiquetas do operador associadas a ordens e sessões",
+		"Tail and filter the info and debug logs":                                           "Acompanhar e filtrar os registos de informação e depuração",
+		"Define alert rules and their notification channels":                                "Definir regras de alerta e os seus canais de notificação",
+		"Define outbound webhook destinations and the events they receive":                  "Definir os destinos de webhook e os eventos que recebem",
+		"Export trades, profit per thread and monthly performance as CSV or PDF":            "Exportar negociações, lucro por thread e desempenho mensal em CSV ou PDF",
+		"Compare backtest runs and their equity against buy-and-hold":                       "Comparar execuções de backtest e o seu capital face a comprar e manter",
+		"Theme, refresh interval, currency, language and visible columns of %s":             "Tema, intervalo de atualização, moeda, idioma e colunas visíveis de %s",
+		"Two-factor authentication of %s":                                                   "Autenticação de dois fatores de %s",
+		"Logout %s":                                                                         "Terminar a sessão de %s",
+
+		/* Login */
+		"Authentication Code": "Código de Autenticação",
+		"Authenticator app code or recovery code, only when two-factor authentication is enabled": "Código da aplicação de autenticação ou código de recuperação, apenas com a autenticação de dois fatores ativa",
+		"No users exist. Create the first user to secure the dashboard.":                          "Não existem utilizadores. Crie o primeiro utilizador para proteger o painel.",
+		"Username":         "Utilizador",
+		"Password":         "Palavra-passe",
+		"Confirm Password": "Confirmar Palavra-passe",
+		"Create User":      "Criar Utilizador",
+		"Login":            "Entrar",
+
+		/* Preferences */
+		"Theme":            "Tema",
+		"light":            "claro",
+		"dark":             "escuro",
+		"Refresh Interval": "Intervalo de Atualização",
+		"Seconds between live data updates (1 to 60)": "Segundos entre atualizações dos dados em tempo real (1 a 60)",
+		"Currency":                 "Moeda",
+		"Language":                 "Idioma",
+		"Browser language":         "Idioma do navegador",
+		"Open Transaction Columns": "Colunas das Transações Abertas",
+		"Preferences saved":        "Preferências guardadas",
+
+		/* Errors */
+		"Unauthenticated":                                "Não autenticado",
+		"Invalid username or password":                   "Utilizador ou palavra-passe inválidos",
+		"Too many failed logins, try again later":        "Demasiadas tentativas de início de sessão falhadas, tente mais tarde",
+		"Username cannot be empty":                       "O utilizador não pode estar vazio",
+		"Password must have at least 8 characters":       "A palavra-passe deve ter pelo menos 8 caracteres",
+		"Passwords do not match":                         "As palavras-passe não coincidem",
+		"Unknown user":                                   "Utilizador desconhecido",
+		"Invalid or missing CSRF token, reload the page": "Token CSRF inválido ou em falta, recarregue a página",
+		"Forbidden":                            "Proibido",
+		"Role must be viewer, trader or admin": "A função deve ser viewer, trader ou admin",
+		"Invalid authentication code":          "Código de autenticação inválido",
+		"Unauthorized":                         "Não autorizado",
+		"Not found":                            "Não encontrado",
+		"Method not allowed":                   "Método não permitido",
+		"Thread not running":                   "A thread não está em execução",
+		"Thread already running":               "A thread já está em execução",
+		"Invalid request body":                 "Corpo do pedido inválido",
+		"Unknown configuration key":            "Chave de configuração desconhecida",
+		"Configuration key cannot be changed while the thread is running": "A chave de configuração não pode ser alterada com a thread em execução",
+		"Invalid configuration value":                                     "Valor de configuração inválido",
+		"Invalid pending action ID":                                       "ID de ação pendente inválido",
+		"Invalid log level":                                               "Nível de log inválido",
+		"Invalid theme":                                                   "Tema inválido",
+		"Refresh interval must be 1 to 60 seconds":                        "O intervalo de atualização deve ser de 1 a 60 segundos",
+		"Invalid currency":                                                "Moeda inválida",
+		"Invalid column":                                                  "Coluna inválida",
+		"Invalid language":                                                "Idioma inválido",
+	},
+}
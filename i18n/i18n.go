@@ -0,0 +1,224 @@
+package i18n
+
+/* This package implements the localization of the UI templates and REST API messages. Messages are translated
+from catalogs keyed by the English text, so English needs no catalog and any message missing from a catalog is
+displayed in English. The locale is the language saved in the user preferences or, when empty, negotiated from
+the browser Accept-Language header. Templates call the functions returned by Funcs, i.e. {{ T .Locale "Back" }}. */
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default is the locale used when no supported locale is requested
+const Default = "en"
+
+// Locale struct define a supported locale
+type Locale struct {
+	Code string /* ISO 639-1 language code */
+	Name string /* Language name in the language itself */
+}
+
+// Locales list the supported locales, the first is the default
+var Locales = []Locale{
+	{Code: "en", Name: "English"},
+	{Code: "pt", Name: "Português"},
+}
+
+/* Number and date formats of a locale */
+type localeFormats struct {
+	decimal   string
+	thousands string
+	date      string
+	dateTime  string
+}
+
+var formats = map[string]localeFormats{
+	"en": {decimal: ".", thousands: ",", date: "2006-01-02", dateTime: "2006-01-02 15:04:05"},
+	"pt": {decimal: ",", thousands: ".", date: "02/01/2006", dateTime: "02/01/2006 15:04:05"},
+}
+
+// Supported return true when code is a supported locale
+func Supported(code string) bool {
+
+	_, ok := formats[code]
+
+	return ok
+
+}
+
+// Negotiate return the locale of language when supported, otherwise the supported locale with the highest
+// quality in the acceptLanguage header (i.e. "pt-BR,pt;q=0.9,en;q=0.8"), otherwise Default
+func Negotiate(
+	language string,
+	acceptLanguage string) string {
+
+	if Supported(language) {
+
+		return language
+
+	}
+
+	type candidate struct {
+		code    string
+		quality float64
+	}
+
+	var candidates []candidate
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		quality := 1.0
+
+		for _, field := range fields[1:] {
+
+			if q := strings.TrimSpace(field); strings.HasPrefix(q, "q=") {
+
+				var err error
+				if quality, err = strconv.ParseFloat(q[2:], 64); err != nil {
+					quality = 0
+				}
+
+			}
+
+		}
+
+		code := strings.SplitN(tag, "-", 2)[0] /* Primary language subtag, pt-BR is pt */
+
+		if quality > 0 && Supported(code) {
+			candidates = append(candidates, candidate{code: code, quality: quality})
+		}
+
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	if len(candidates) > 0 {
+
+		return candidates[0].code
+
+	}
+
+	return Default
+
+}
+
+// T return message translated to locale, formatted with args as in fmt.Sprintf when args are given
+func T(
+	locale string,
+	message string,
+	args ...interface{}) string {
+
+	if translated, ok := catalogs[locale][message]; ok {
+
+		message = translated
+
+	}
+
+	if len(args) > 0 {
+
+		return fmt.Sprintf(message, args...)
+
+	}
+
+	return message
+
+}
+
+// FormatNumber return f with decimals digits and the decimal and thousands separators of locale
+func FormatNumber(
+	locale string,
+	f float64,
+	decimals int) string {
+
+	format := localeFormat(locale)
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+
+		return strconv.FormatFloat(f, 'f', -1, 64)
+
+	}
+
+	text := strconv.FormatFloat(math.Abs(f), 'f', decimals, 64)
+	integer, fraction := text, ""
+
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		integer, fraction = text[:i], text[i+1:]
+	}
+
+	var b strings.Builder
+
+	if f < 0 && strings.Trim(text, "0.") != "" {
+		b.WriteByte('-')
+	}
+
+	for i, digit := range integer {
+
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(format.thousands)
+		}
+
+		b.WriteRune(digit)
+
+	}
+
+	if fraction != "" {
+
+		b.WriteString(format.decimal)
+		b.WriteString(fraction)
+
+	}
+
+	return b.String()
+
+}
+
+// FormatDate return the date of t in the format of locale
+func FormatDate(
+	locale string,
+	t time.Time) string {
+
+	return t.Format(localeFormat(locale).date)
+
+}
+
+// FormatDateTime return the date and time of t in the format of locale
+func FormatDateTime(
+	locale string,
+	t time.Time) string {
+
+	return t.Format(localeFormat(locale).dateTime)
+
+}
+
+// Funcs return the template functions T, number, date and datetime
+func Funcs() template.FuncMap {
+
+	return template.FuncMap{
+		"T":        T,
+		"number":   FormatNumber,
+		"date":     FormatDate,
+		"datetime": FormatDateTime,
+	}
+
+}
+
+/* Return the formats of locale, or of Default for unsupported locales */
+func localeFormat(locale string) localeFormats {
+
+	if format, ok := formats[locale]; ok {
+
+		return format
+
+	}
+
+	return formats[Default]
+
+}
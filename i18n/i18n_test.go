@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		language       string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "saved language", language: "pt", acceptLanguage: "en-US,en;q=0.9", want: "pt"},
+		{name: "region subtag", language: "", acceptLanguage: "pt-BR,pt;q=0.9,en;q=0.8", want: "pt"},
+		{name: "quality order", language: "", acceptLanguage: "en;q=0.5, pt;q=0.8", want: "pt"},
+		{name: "unsupported languages", language: "", acceptLanguage: "fr-FR,de;q=0.9", want: "en"},
+		{name: "rejected language", language: "", acceptLanguage: "pt;q=0, en;q=0.1", want: "en"},
+		{name: "unsupported saved language", language: "xx", acceptLanguage: "", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.language, tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		message string
+		args    []interface{}
+		want    string
+	}{
+		{name: "english", locale: "en", message: "Back", want: "Back"},
+		{name: "portuguese", locale: "pt", message: "Back", want: "Voltar"},
+		{name: "missing message", locale: "pt", message: "Missing message", want: "Missing message"},
+		{name: "arguments", locale: "pt", message: "Logout %s", args: []interface{}{"admin"}, want: "Terminar a sessão de admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := T(tt.locale, tt.message, tt.args...); got != tt.want {
+				t.Errorf("T() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		f        float64
+		decimals int
+		want     string
+	}{
+		{name: "english", locale: "en", f: 1234567.891, decimals: 2, want: "1,234,567.89"},
+		{name: "portuguese", locale: "pt", f: 1234567.891, decimals: 2, want: "1.234.567,89"},
+		{name: "negative", locale: "pt", f: -1234.6, decimals: 0, want: "-1.235"},
+		{name: "negative zero", locale: "en", f: -0.001, decimals: 2, want: "0.00"},
+		{name: "small", locale: "en", f: 0.00012345, decimals: 8, want: "0.00012345"},
+		{name: "unsupported locale", locale: "xx", f: 1000, decimals: 1, want: "1,000.0"},
+		{name: "not a number", locale: "en", f: math.NaN(), decimals: 2, want: "NaN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatNumber(tt.locale, tt.f, tt.decimals); got != tt.want {
+				t.Errorf("FormatNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	date := time.Date(2021, 11, 30, 14, 5, 9, 0, time.UTC)
+	if got, want := FormatDate("pt", date), "30/11/2021"; got != want {
+		t.Errorf("FormatDate() = %v, want %v", got, want)
+	}
+	if got, want := FormatDateTime("en", date), "2021-11-30 14:05:09"; got != want {
+		t.Errorf("FormatDateTime() = %v, want %v", got, want)
+	}
+}
+
+func Test_catalogs(t *testing.T) {
+	for locale := range catalogs {
+		if !Supported(locale) {
+			t.Errorf("catalog %v has no formats", locale)
+		}
+	}
+	for _, locale := range Locales {
+		if !Supported(locale.Code) {
+			t.Errorf("locale %v has no formats", locale.Code)
+		}
+	}
+}
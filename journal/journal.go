@@ -0,0 +1,225 @@
+package journal
+
+/* This package implements the trade journal. Operators attach free-text notes and tags to an order, or to
+a session when no order is selected, to record why they intervened manually. Notes are saved in the note
+table and the journal page lists them most recent first, filtered by tag. */
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Note limits */
+const (
+	noteLimit = 200  /* Notes listed in the journal page */
+	textMax   = 2000 /* Note text length */
+	tagsMax   = 10   /* Tags per note */
+)
+
+/* Journal errors */
+var (
+	ErrNoText      = errors.New("Note text is required")
+	ErrTextTooLong = errors.New("Note text must have at most 2000 characters")
+	ErrInvalidTag  = errors.New("Tags must have 1 to 32 letters, digits, '-' or '_', at most 10 per note")
+	ErrNoTarget    = errors.New("Note requires an OrderID or a running thread")
+	ErrInvalidID   = errors.New("OrderID must be a number")
+)
+
+var validTag = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// Entry struct define a note in the journal page
+type Entry struct {
+	types.Note
+	Date string
+}
+
+// Journal struct define the journal page (journal.html)
+type Journal struct {
+	ThreadID string   /* Running thread, default target of new notes */
+	OrderID  string   /* OrderID prefilled in the new note form */
+	Tag      string   /* Selected tag filter */
+	Tags     []string /* Tags of all notes */
+	Notes    []Entry
+	Message  string
+	CanTrade bool
+	Theme    string /* UI theme of the logged in user */
+}
+
+// ParseTags return the tags separated by commas or spaces, lowercase and without duplicates
+func ParseTags(s string) (tags []string, err error) {
+
+	seen := make(map[string]bool)
+
+	for _, tag := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == ',' || r == ' ' }) {
+
+		if !validTag.MatchString(tag) {
+
+			return nil, ErrInvalidTag
+
+		}
+
+		if !seen[tag] {
+
+			seen[tag] = true
+			tags = append(tags, tag)
+
+		}
+
+	}
+
+	if len(tags) > tagsMax {
+
+		return nil, ErrInvalidTag
+
+	}
+
+	return tags, nil
+
+}
+
+// Save attach a note to orderID, or to the session threadID when orderID is empty
+func Save(
+	sessionData *types.Session,
+	username string,
+	threadID string,
+	orderID string,
+	tags string,
+	text string) (err error) {
+
+	note := types.Note{
+		ThreadID: threadID,
+		Username: username,
+		Time:     time.Now().UnixNano() / int64(time.Millisecond),
+		Text:     strings.TrimSpace(text),
+	}
+
+	if note.Text == "" {
+
+		return ErrNoText
+
+	}
+
+	if len([]rune(note.Text)) > textMax {
+
+		return ErrTextTooLong
+
+	}
+
+	if note.Tags, err = ParseTags(tags); err != nil {
+
+		return err
+
+	}
+
+	if orderID = strings.TrimSpace(orderID); orderID != "" {
+
+		if note.OrderID, err = strconv.ParseInt(orderID, 10, 64); err != nil || note.OrderID <= 0 {
+
+			return ErrInvalidID
+
+		}
+
+	}
+
+	if note.OrderID == 0 && note.ThreadID == "" {
+
+		return ErrNoTarget
+
+	}
+
+	if err = mysql.SaveNote(sessionData, note); err != nil {
+
+		return err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   nil,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{OrderID: note.OrderID},
+		Message:  "Note added by " + username + " [" + strings.Join(note.Tags, ",") + "] " + note.Text,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Load the notes with tag, or all notes when tag is empty, and the tags of all notes for the journal page
+func Load(
+	sessionData *types.Session,
+	tag string) (journal Journal, err error) {
+
+	var notes []types.Note
+	var all []types.Note
+
+	journal.ThreadID = sessionData.ThreadID
+	journal.Tag = strings.ToLower(strings.TrimSpace(tag))
+
+	if notes, err = mysql.GetNotes(sessionData, journal.Tag, noteLimit); err != nil {
+
+		return journal, err
+
+	}
+
+	all = notes
+
+	if journal.Tag != "" {
+
+		if all, err = mysql.GetNotes(sessionData, "", noteLimit); err != nil {
+
+			return journal, err
+
+		}
+
+	}
+
+	journal.Tags = noteTags(all)
+
+	for _, note := range notes {
+
+		journal.Notes = append(journal.Notes, Entry{
+			Note: note,
+			Date: time.Unix((note.Time / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+		})
+
+	}
+
+	return journal, nil
+
+}
+
+/* Return the tags of notes sorted */
+func noteTags(notes []types.Note) (tags []string) {
+
+	seen := make(map[string]bool)
+
+	for _, note := range notes {
+
+		for _, tag := range note.Tags {
+
+			if !seen[tag] {
+
+				seen[tag] = true
+				tags = append(tags, tag)
+
+			}
+
+		}
+
+	}
+
+	sort.Strings(tags)
+
+	return tags
+
+}
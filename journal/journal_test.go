@@ -0,0 +1,72 @@
+package journal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "commas and spaces",
+			s:       "Manual, news  stoploss",
+			want:    []string{"manual", "news", "stoploss"},
+			wantErr: nil,
+		},
+		{
+			name:    "duplicates",
+			s:       "manual,MANUAL,manual",
+			want:    []string{"manual"},
+			wantErr: nil,
+		},
+		{
+			name:    "empty",
+			s:       " , ",
+			want:    nil,
+			wantErr: nil,
+		},
+		{
+			name:    "invalid character",
+			s:       "manual,fed;cpi",
+			want:    nil,
+			wantErr: ErrInvalidTag,
+		},
+		{
+			name:    "too many tags",
+			s:       "a,b,c,d,e,f,g,h,i,j,k",
+			want:    nil,
+			wantErr: ErrInvalidTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTags(tt.s)
+			if err != tt.wantErr {
+				t.Errorf("ParseTags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_noteTags(t *testing.T) {
+	notes := []types.Note{
+		{Tags: []string{"news", "manual"}},
+		{Tags: nil},
+		{Tags: []string{"manual", "maintenance"}},
+	}
+	want := []string{"maintenance", "manual", "news"}
+	if got := noteTags(notes); !reflect.DeepEqual(got, want) {
+		t.Errorf("noteTags() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package labels
+
+/* This package implements session names and tags. ThreadIDs are opaque, so operators name and tag sessions
+(i.e. BTC grid tagged grid and majors) in the thread detail page or the REST API. Labels are saved by ThreadID
+in the sessionlabel table, kept when the thread stops and resumes, and every list filtered by ThreadID also
+matches the name or a tag of a session. */
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Label limits */
+const (
+	nameMax = 45 /* Name length, the length of the sessionlabel Name column */
+	tagsMax = 10 /* Tags per session */
+)
+
+/* Label errors */
+var (
+	ErrNoThread    = errors.New("Session label requires a ThreadID")
+	ErrNameTooLong = errors.New("Session name must have at most 45 characters")
+	ErrInvalidTag  = errors.New("Tags must have 1 to 24 letters, digits, '-' or '_', at most 10 per session")
+)
+
+var validTag = regexp.MustCompile(`^[a-z0-9_-]{1,24}$`)
+
+// Parse return the label of threadID with name and the tags separated by commas or spaces, lowercase and without
+// duplicates. Empty name and tags remove the label.
+func Parse(
+	threadID string,
+	name string,
+	tags string) (label types.SessionLabel, err error) {
+
+	label.ThreadID = strings.TrimSpace(threadID)
+	label.Name = strings.Join(strings.Fields(name), " ")
+
+	if label.ThreadID == "" {
+
+		return label, ErrNoThread
+
+	}
+
+	if len([]rune(label.Name)) > nameMax {
+
+		return label, ErrNameTooLong
+
+	}
+
+	seen := make(map[string]bool)
+
+	for _, tag := range strings.FieldsFunc(strings.ToLower(tags), func(r rune) bool { return r == ',' || r == ' ' }) {
+
+		if !validTag.MatchString(tag) {
+
+			return label, ErrInvalidTag
+
+		}
+
+		if !seen[tag] {
+
+			seen[tag] = true
+			label.Tags = append(label.Tags, tag)
+
+		}
+
+	}
+
+	if len(label.Tags) > tagsMax {
+
+		return label, ErrInvalidTag
+
+	}
+
+	return label, nil
+
+}
+
+// Save the name and tags of a session set by username
+func Save(
+	sessionData *types.Session,
+	username string,
+	label types.SessionLabel) (err error) {
+
+	if err = mysql.SaveSessionLabel(sessionData, label); err != nil {
+
+		return err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   nil,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Session " + label.ThreadID + " labeled by " + username + " - " + label.Name + " [" + strings.Join(label.Tags, ",") + "]",
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Get return the label of threadID, empty when the session is not named or tagged
+func Get(
+	sessionData *types.Session,
+	threadID string) types.SessionLabel {
+
+	labels, _ := mysql.GetSessionLabels(sessionData)
+
+	for _, label := range labels {
+
+		if label.ThreadID == threadID {
+
+			return label
+
+		}
+
+	}
+
+	return types.SessionLabel{ThreadID: threadID}
+
+}
+
+// Match return true when term is empty or matches the ThreadID, the name (case insensitive) or a tag of label
+func Match(
+	label types.SessionLabel,
+	term string) bool {
+
+	if term = strings.TrimSpace(term); term == "" {
+
+		return true
+
+	}
+
+	if strings.EqualFold(label.ThreadID, term) || (label.Name != "" && strings.EqualFold(label.Name, term)) {
+
+		return true
+
+	}
+
+	for _, tag := range label.Tags {
+
+		if tag == strings.ToLower(term) {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+// Resolve return the ThreadIDs named or tagged term in name order, followed by term itself as a ThreadID, or nil
+// when term is empty
+func Resolve(
+	sessionData *types.Session,
+	term string) []string {
+
+	if term = strings.TrimSpace(term); term == "" {
+
+		return nil
+
+	}
+
+	labels, _ := mysql.GetSessionLabels(sessionData) /* Only term matches when labels cannot be loaded */
+
+	return resolve(labels, term)
+
+}
+
+/* Return the ThreadIDs of labels matching term, followed by term itself as a ThreadID */
+func resolve(
+	labels []types.SessionLabel,
+	term string) (threadIDs []string) {
+
+	seen := make(map[string]bool)
+
+	for _, label := range labels {
+
+		if Match(label, term) && !seen[label.ThreadID] {
+
+			seen[label.ThreadID] = true
+			threadIDs = append(threadIDs, label.ThreadID)
+
+		}
+
+	}
+
+	if !seen[term] {
+
+		threadIDs = append(threadIDs, term)
+
+	}
+
+	return threadIDs
+
+}
@@ -0,0 +1,111 @@
+package labels
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		threadID string
+		name     string
+		tags     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    types.SessionLabel
+		wantErr error
+	}{
+		{
+			name: "label",
+			args: args{threadID: "c683ok5mk1u1120gnmmg", name: "  BTC   grid ", tags: "Grid, majors grid"},
+			want: types.SessionLabel{ThreadID: "c683ok5mk1u1120gnmmg", Name: "BTC grid", Tags: []string{"grid", "majors"}},
+		},
+		{
+			name: "remove",
+			args: args{threadID: "c683ok5mk1u1120gnmmg", name: "", tags: ""},
+			want: types.SessionLabel{ThreadID: "c683ok5mk1u1120gnmmg"},
+		},
+		{
+			name:    "no thread",
+			args:    args{threadID: " ", name: "BTC grid"},
+			wantErr: ErrNoThread,
+		},
+		{
+			name:    "name",
+			args:    args{threadID: "c683ok5mk1u1120gnmmg", name: "A session name much longer than forty five characters"},
+			wantErr: ErrNameTooLong,
+		},
+		{
+			name:    "tag",
+			args:    args{threadID: "c683ok5mk1u1120gnmmg", tags: "grid,major$"},
+			wantErr: ErrInvalidTag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.threadID, tt.args.name, tt.args.tags)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+
+	label := types.SessionLabel{ThreadID: "c683ok5mk1u1120gnmmg", Name: "BTC grid", Tags: []string{"grid", "majors"}}
+
+	tests := []struct {
+		name string
+		term string
+		want bool
+	}{
+		{name: "empty", term: "", want: true},
+		{name: "thread", term: "c683ok5mk1u1120gnmmg", want: true},
+		{name: "name", term: "btc GRID", want: true},
+		{name: "tag", term: "Majors", want: true},
+		{name: "partial name", term: "BTC", want: false},
+		{name: "other", term: "c683ok5mk1u1120gnmng", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Match(label, tt.term); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_resolve(t *testing.T) {
+
+	labels := []types.SessionLabel{
+		{ThreadID: "c683ok5mk1u1120gnmmg", Name: "BTC grid", Tags: []string{"grid", "majors"}},
+		{ThreadID: "c683ok5mk1u1120gnmng", Name: "ETH grid", Tags: []string{"grid"}},
+	}
+
+	tests := []struct {
+		name string
+		term string
+		want []string
+	}{
+		{name: "tag", term: "grid", want: []string{"c683ok5mk1u1120gnmmg", "c683ok5mk1u1120gnmng", "grid"}},
+		{name: "name", term: "ETH grid", want: []string{"c683ok5mk1u1120gnmng", "ETH grid"}},
+		{name: "labeled thread", term: "c683ok5mk1u1120gnmmg", want: []string{"c683ok5mk1u1120gnmmg"}},
+		{name: "thread", term: "c683ok5mk1u1120gnmo0", want: []string{"c683ok5mk1u1120gnmo0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolve(labels, tt.term); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
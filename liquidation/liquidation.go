@@ -0,0 +1,646 @@
+package liquidation
+
+/* This package implements the emergency liquidation of all threads. The operation is requested from
+the admin page, the command line or Telegram and must be confirmed with a one-time code. Once confirmed
+a flag is set in the global table, and every thread cancels its open orders, sells all thread transactions
+at market and writes a report of the executed exits to the liquidation table. The master node also sells the
+holdings no running thread sells: the transactions of the threads stopped or failed, and the exchange balances
+not tied to any thread. */
+
+import (
+	"bufio"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const confirmTimeout = 60 * time.Second /* Time allowed between request and confirmation */
+
+const heartbeatTimeout = time.Minute /* Heartbeat age of a thread not running, updated every 10 seconds */
+
+// OrderSource is the orders table source of the sales of the holdings no running thread sells
+const OrderSource = "liquidation"
+
+/* Confirmation errors */
+var (
+	ErrNotRequested = errors.New("Emergency liquidation not requested")
+	ErrExpired      = errors.New("Emergency liquidation confirmation expired")
+	ErrCode         = errors.New("Emergency liquidation confirmation code invalid")
+)
+
+// Request start the emergency liquidation returning the one-time code required by Confirm
+func Request(
+	sessionData *types.Session) (code string, err error) {
+
+	var n *big.Int
+
+	if n, err = rand.Int(rand.Reader, big.NewInt(1000000)); err != nil {
+
+		return "", err
+
+	}
+
+	sessionData.LiquidationCode = fmt.Sprintf("%06d", n.Int64())
+	sessionData.LiquidationCodeTime = time.Now()
+
+	return sessionData.LiquidationCode, nil
+
+}
+
+// Confirm the emergency liquidation with the code returned by Request. All threads cancel
+// their open orders and sell all thread transactions at market once the global flag is set,
+// and the master node sells the holdings of the threads not running and not tied to any thread.
+func Confirm(
+	configData *types.Config,
+	sessionData *types.Session,
+	code string) (err error) {
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "InfoLevel",
+			}.Do()
+		}
+	}()
+
+	err = verifyCode(sessionData.LiquidationCode, strings.TrimSpace(code), sessionData.LiquidationCodeTime, time.Now())
+
+	sessionData.LiquidationCode = "" /* Codes are single use */
+
+	if err != nil {
+
+		return err
+
+	}
+
+	liquidateTime := time.Now().UnixNano() / int64(time.Millisecond)
+
+	if err = mysql.UpdateGlobalLiquidate(sessionData, true, liquidateTime); err != nil {
+
+		return err
+
+	}
+
+	sessionData.Global.Liquidate = true
+	sessionData.Global.LiquidateTime = liquidateTime
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Emergency liquidation confirmed - cancelling open orders and selling all thread transactions",
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Cancel the emergency liquidation requested with code before it is confirmed
+func Cancel(
+	configData *types.Config,
+	sessionData *types.Session,
+	code string) error {
+
+	if sessionData.LiquidationCode == "" || sessionData.LiquidationCode != strings.TrimSpace(code) {
+
+		return ErrNotRequested
+
+	}
+
+	sessionData.LiquidationCode = ""
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Emergency liquidation cancelled",
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Load the emergency liquidation status. While active the thread cancels its open orders once,
+// and when all thread transactions are sold a report of the executed exits is saved.
+func Load(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if sessionData.Global.Liquidate, sessionData.Global.LiquidateTime, err = mysql.GetGlobalLiquidate(sessionData); err != nil {
+
+		return
+
+	}
+
+	if !sessionData.Global.Liquidate {
+
+		sessionData.LiquidationOrdersCanceled = false
+		sessionData.LiquidationReported = false
+		sessionData.LiquidationSwept = false
+		return
+
+	}
+
+	/* Only threads that are trading own orders and transactions */
+	if sessionData.ThreadID == "" || sessionData.Symbol == "" {
+
+		return
+
+	}
+
+	if !sessionData.LiquidationOrdersCanceled {
+
+		var count int
+
+		if count, err = exchange.CancelOpenOrders(configData, sessionData); err != nil {
+
+			return
+
+		}
+
+		sessionData.LiquidationOrdersCanceled = true
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  fmt.Sprintf("Emergency liquidation - %d open orders cancelled", count),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+	/* The holdings no running thread sells are sold once by the master node, retried until all sales succeed */
+	if sessionData.MasterNode && !sessionData.LiquidationSwept {
+
+		if err = sweep(configData, sessionData); err != nil {
+
+			return
+
+		}
+
+		sessionData.LiquidationSwept = true
+
+	}
+
+	if sessionData.ThreadCount == 0 && !sessionData.LiquidationReported {
+
+		if err = mysql.SaveLiquidationReport(
+			sessionData,
+			sessionData.Global.LiquidateTime,
+			time.Now().UnixNano()/int64(time.Millisecond)); err != nil {
+
+			return
+
+		}
+
+		sessionData.LiquidationReported = true
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Emergency liquidation complete - report saved",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Sell at market the holdings no running thread sells and save a report of the sales of each thread and symbol */
+func sweep(
+	configData *types.Config,
+	sessionData *types.Session) (err error) {
+
+	var transactions []types.ThreadTransaction
+	var heartbeats map[string]int64
+	var balances map[string]float64
+	var sold bool
+	var failed error /* First sale failed, the sweep is retried */
+
+	if transactions, err = mysql.GetThreadTransactions(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if heartbeats, err = mysql.GetSessionHeartbeats(sessionData); err != nil {
+
+		return err
+
+	}
+
+	now := time.Now()
+	tracked := make(map[string]float64)         /* Quantity of the open transactions not sold by the sweep by symbol */
+	lotSizes := make(map[string]*types.Session) /* Lot size of the symbols sold */
+	reports := make(map[string]*types.Session)  /* Thread and symbol of the sales */
+
+	for _, transaction := range transactions {
+
+		if running(heartbeats[transaction.ThreadID], now) { /* Sold by the thread */
+
+			tracked[transaction.Symbol] += transaction.ExecutedQuantity
+			continue
+
+		}
+
+		thread := threadSession(sessionData, transaction.ThreadID, transaction.ThreadIDSession, transaction.Symbol)
+
+		if sold, err = sell(configData, sessionData, thread, lotSizes, transaction.ExecutedQuantity, transaction.OrderID); err != nil || !sold {
+
+			if err != nil && failed == nil {
+				failed = err
+			}
+
+			tracked[transaction.Symbol] += transaction.ExecutedQuantity /* Still held by the transaction */
+			continue
+
+		}
+
+		if err = mysql.DeleteThreadTransactionByOrderID(thread, transaction.OrderID); err != nil {
+
+			return err
+
+		}
+
+		reports[thread.ThreadID+thread.Symbol] = thread
+
+	}
+
+	if balances, err = exchange.GetBalances(configData, sessionData); err != nil { /* Read after the transactions, a sale in between is not sold twice */
+
+		return err
+
+	}
+
+	for asset, free := range balances {
+
+		symbol := asset + configData.SymbolFiat
+
+		if asset == configData.SymbolFiat || free-tracked[symbol] <= 0 {
+
+			continue
+
+		}
+
+		unassigned := threadSession(sessionData, "", "", symbol) /* Sale not tied to any thread */
+
+		if sold, err = sell(configData, sessionData, unassigned, lotSizes, free-tracked[symbol], 0); err != nil || !sold {
+
+			if err != nil && failed == nil {
+				failed = err
+			}
+
+			continue
+
+		}
+
+		reports[symbol] = unassigned
+
+	}
+
+	end := time.Now().UnixNano() / int64(time.Millisecond)
+
+	for _, report := range reports {
+
+		if err = mysql.SaveLiquidationReport(report, sessionData.Global.LiquidateTime, end); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return failed
+
+}
+
+/* Sell quantity of thread.Symbol at market rounded down to the lot size, recording the sale of the transaction orderIDSource (sold false when skipped) */
+func sell(
+	configData *types.Config,
+	sessionData *types.Session,
+	thread *types.Session,
+	lotSizes map[string]*types.Session,
+	quantity float64,
+	orderIDSource int64) (sold bool, err error) {
+
+	var price float64
+	var order *types.Order
+
+	lotSize := lotSizes[thread.Symbol]
+
+	if lotSize == nil {
+
+		if lotSize, err = getLotSize(configData, sessionData, thread.Symbol); err != nil { /* Not cached, retried by the next sweep */
+
+			return false, err
+
+		}
+
+		lotSizes[thread.Symbol] = lotSize
+
+	}
+
+	if lotSize.StepSize == 0 { /* Symbol not traded in the exchange */
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{OrderIDSource: orderIDSource},
+			Message:  "Emergency liquidation - " + thread.Symbol + " not traded in the exchange, not sold",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		return false, nil
+
+	}
+
+	if price, err = exchange.GetSymbolPrice(configData, sessionData, thread.Symbol); err != nil {
+
+		return false, err
+
+	}
+
+	if quantity = sellQuantity(quantity, lotSize.StepSize, lotSize.MinQuantity, lotSize.MinNotional, price); quantity == 0 {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{OrderIDSource: orderIDSource},
+			Message:  "Emergency liquidation - " + thread.Symbol + " below the exchange minimum, not sold",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		return false, nil
+
+	}
+
+	if order, err = exchange.ManualOrder(configData, thread, "SELL", functions.Float64ToStr(quantity, 8), ""); order == nil {
+
+		if err == nil {
+			err = errors.New("Invalid Exchange Name")
+		}
+
+		return false, err
+
+	}
+
+	/* Check if result is nil and set as zero */
+	if price = order.CumulativeQuoteQuantity / order.ExecutedQuantity; math.IsNaN(price) || math.IsInf(price, 0) {
+		price = 0
+	}
+
+	if err = mysql.SaveOrder(thread, order, orderIDSource, price); err != nil {
+
+		return true, err
+
+	}
+
+	if err = mysql.UpdateOrderSource(thread, order.OrderID, OrderSource); err != nil {
+
+		return true, err
+
+	}
+
+	owner := "not tied to any thread"
+	if thread.ThreadID != "" {
+		owner = "of thread " + thread.ThreadID + " not running"
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:  configData,
+		Market:  nil,
+		Session: sessionData,
+		Order: &types.Order{
+			OrderID:       order.OrderID,
+			OrderIDSource: orderIDSource,
+			Price:         price,
+		},
+		Message:  "Emergency liquidation - " + functions.Float64ToStr(quantity, 8) + " " + thread.Symbol + " " + owner + " sold",
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return true, nil
+
+}
+
+/* Return the lot size of symbol, StepSize 0 when the symbol is not traded in the exchange */
+func getLotSize(
+	configData *types.Config,
+	sessionData *types.Session,
+	symbol string) (lotSize *types.Session, err error) {
+
+	var info *types.ExchangeInfo
+
+	lotSize = threadSession(sessionData, "", "", symbol)
+
+	if info, err = exchange.GetInfo(configData, lotSize); err != nil {
+
+		return nil, err
+
+	}
+
+	if info == nil {
+
+		return nil, errors.New("Invalid Exchange Name")
+
+	}
+
+	lotSize.MinQuantity = functions.StrToFloat64(info.MinQuantity)
+	lotSize.StepSize = functions.StrToFloat64(info.StepSize)
+	lotSize.MinNotional = functions.StrToFloat64(info.MinNotional)
+
+	return lotSize, nil
+
+}
+
+/* Return a session of threadID trading symbol, not running in this process, with the database and exchange clients of sessionData */
+func threadSession(
+	sessionData *types.Session,
+	threadID string,
+	threadIDSession string,
+	symbol string) *types.Session {
+
+	return &types.Session{
+		ThreadID:        threadID,
+		ThreadIDSession: threadIDSession,
+		Symbol:          symbol,
+		Db:              sessionData.Db,
+		Clients:         sessionData.Clients,
+		Global:          sessionData.Global,
+	}
+
+}
+
+/* Return true when a thread heartbeat (milliseconds) is recent, the thread is running and sells its transactions */
+func running(
+	heartbeat int64,
+	now time.Time) bool {
+
+	return heartbeat > 0 && now.Sub(time.Unix(0, heartbeat*int64(time.Millisecond))) < heartbeatTimeout
+
+}
+
+/* Return quantity rounded down to stepSize, 0 when below minQuantity or minNotional at price */
+func sellQuantity(
+	quantity float64,
+	stepSize float64,
+	minQuantity float64,
+	minNotional float64,
+	price float64) float64 {
+
+	if stepSize > 0 {
+
+		quantity = math.Floor(quantity/stepSize+1e-9) * stepSize
+
+	}
+
+	if quantity <= 0 || quantity < minQuantity || quantity*price < minNotional {
+
+		return 0
+
+	}
+
+	return quantity
+
+}
+
+// Resume clear the emergency liquidation flag re-enabling buys on all threads
+func Resume(
+	configData *types.Config,
+	sessionData *types.Session) (err error) {
+
+	if err = mysql.UpdateGlobalLiquidate(sessionData, false, 0); err != nil {
+
+		return err
+
+	}
+
+	if sessionData.Global.Liquidate {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Emergency liquidation reset by operator",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+	sessionData.Global.Liquidate = false
+	sessionData.Global.LiquidateTime = 0
+
+	return nil
+
+}
+
+// Command run the emergency liquidation from the command line, reading the confirmation code from in
+func Command(
+	configData *types.Config,
+	sessionData *types.Session,
+	in io.Reader,
+	out io.Writer) (err error) {
+
+	var code string
+	var input string
+
+	if code, err = Request(sessionData); err != nil {
+
+		return err
+
+	}
+
+	fmt.Fprintln(out, "Emergency liquidation cancels all open orders and sells all thread transactions at market across all threads.")
+	fmt.Fprintf(out, "Type %s to confirm: ", code)
+
+	if input, err = bufio.NewReader(in).ReadString('\n'); err != nil && input == "" {
+
+		return err
+
+	}
+
+	if err = Confirm(configData, sessionData, input); err != nil {
+
+		fmt.Fprintln(out, err.Error())
+		return err
+
+	}
+
+	fmt.Fprintln(out, "Emergency liquidation confirmed. Running threads and the master node liquidate within seconds, see the liquidation table for the report.")
+
+	return nil
+
+}
+
+/* Verify the confirmation code against the requested code and its expiry */
+func verifyCode(
+	requested string,
+	code string,
+	requestTime time.Time,
+	now time.Time) error {
+
+	if requested == "" {
+
+		return ErrNotRequested
+
+	}
+
+	if now.Sub(requestTime) > confirmTimeout {
+
+		return ErrExpired
+
+	}
+
+	if code != requested {
+
+		return ErrCode
+
+	}
+
+	return nil
+
+}
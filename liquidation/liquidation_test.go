@@ -0,0 +1,230 @@
+package liquidation
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/adshao/go-binance/v2"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_verifyCode(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		requested   string
+		code        string
+		requestTime time.Time
+		now         time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "success",
+			args: args{
+				requested:   "042137",
+				code:        "042137",
+				requestTime: now.Add(-10 * time.Second),
+				now:         now,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "not requested",
+			args: args{
+				requested:   "",
+				code:        "042137",
+				requestTime: time.Time{},
+				now:         now,
+			},
+			wantErr: ErrNotRequested,
+		},
+		{
+			name: "expired",
+			args: args{
+				requested:   "042137",
+				code:        "042137",
+				requestTime: now.Add(-61 * time.Second),
+				now:         now,
+			},
+			wantErr: ErrExpired,
+		},
+		{
+			name: "invalid code",
+			args: args{
+				requested:   "042137",
+				code:        "042138",
+				requestTime: now.Add(-10 * time.Second),
+				now:         now,
+			},
+			wantErr: ErrCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyCode(tt.args.requested, tt.args.code, tt.args.requestTime, tt.args.now); err != tt.wantErr {
+				t.Errorf("verifyCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_running(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		heartbeat int64
+		now       time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "recent heartbeat",
+			args: args{
+				heartbeat: now.Add(-10*time.Second).UnixNano() / int64(time.Millisecond),
+				now:       now,
+			},
+			want: true,
+		},
+		{
+			name: "stale heartbeat",
+			args: args{
+				heartbeat: now.Add(-2*time.Minute).UnixNano() / int64(time.Millisecond),
+				now:       now,
+			},
+			want: false,
+		},
+		{
+			name: "no heartbeat",
+			args: args{
+				heartbeat: 0,
+				now:       now,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := running(tt.args.heartbeat, tt.args.now); got != tt.want {
+				t.Errorf("running() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sellQuantity(t *testing.T) {
+	type args struct {
+		quantity    float64
+		stepSize    float64
+		minQuantity float64
+		minNotional float64
+		price       float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "rounded down to step",
+			args: args{
+				quantity:    1.23456,
+				stepSize:    0.01,
+				minQuantity: 0.01,
+				minNotional: 10,
+				price:       100,
+			},
+			want: 1.23,
+		},
+		{
+			name: "exact step",
+			args: args{
+				quantity:    0.3,
+				stepSize:    0.1,
+				minQuantity: 0.1,
+				minNotional: 10,
+				price:       100,
+			},
+			want: 0.3,
+		},
+		{
+			name: "below min quantity",
+			args: args{
+				quantity:    0.009,
+				stepSize:    0.001,
+				minQuantity: 0.01,
+				minNotional: 0,
+				price:       100,
+			},
+			want: 0,
+		},
+		{
+			name: "below min notional",
+			args: args{
+				quantity:    0.05,
+				stepSize:    0.01,
+				minQuantity: 0.01,
+				minNotional: 10,
+				price:       100,
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sellQuantity(tt.args.quantity, tt.args.stepSize, tt.args.minQuantity, tt.args.minNotional, tt.args.price); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("sellQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sweep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/exchangeInfo") { /* Lot size lookup fails */
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":-1001,"msg":"exchange info unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"balances":[]}`))
+	}))
+	defer server.Close()
+
+	client := binance.NewClient("", "")
+	client.BaseURL = server.URL
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadTransactions()")).
+		WillReturnRows(sqlmock.NewRows([]string{"ThreadID", "ThreadIDSession", "OrderID", "Symbol", "ExecutedQuantity"}).
+			AddRow("c683ok5mk1u1120gnmmg", "c683ok5mk1u1120gnmn0", 1, "BTCUSDT", 0.5)) /* Thread not running */
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionHeartbeats()")).
+		WillReturnRows(sqlmock.NewRows([]string{"ThreadID", "Heartbeat"}))
+
+	err = sweep(
+		&types.Config{ConfigGlobal: &types.ConfigGlobal{}, ExchangeName: "binance", SymbolFiat: "USDT"},
+		&types.Session{ThreadID: "c683ok5mk1u1120gnmmh", MasterNode: true, Db: db, Clients: types.Client{Binance: client}, Global: &types.Global{}})
+
+	if err == nil || !strings.Contains(err.Error(), "exchange info unavailable") { /* Transaction kept, sweep retried */
+		t.Errorf("sweep() error = %v, want exchange info unavailable", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("sweep() %v", err)
+	}
+}
@@ -10,9 +10,12 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/exchange"
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
 	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/risk"
 	"github.com/aleibovici/cryptopump/types"
 )
 
@@ -60,6 +63,16 @@ func LoadSessionDataAdditionalComponents(
 		SellDecisionTreeResult string  /* Hold SellDecisionTree result */
 		QuantityOffset         float64 /* Quantity offset */
 		DiffTotal              float64 /* Total difference between target and market price */
+		CooldownUntil          string  /* Loss streak cooldown end time */
+		Drawdown               string  /* Global drawdown or kill switch status */
+		ExposureHeadroom       float64 /* Fiat amount available under thread exposure cap */
+		StopPrice              float64 /* Absolute stop price */
+		Paused                 bool    /* Thread paused by an operator */
+		CycleState             string  /* Trade cycle state */
+		Reservation            float64 /* Fiat funds reserved by the funds allocator */
+		ReservationAvailable   float64 /* Fiat funds available under the funds allocator */
+		Exchange               string  /* Exchange health status of the last 5 minutes */
+		ExchangeDetail         string  /* Exchange health mean latency and error rate */
 		Orders                 []Order
 	}
 
@@ -89,6 +102,38 @@ func LoadSessionDataAdditionalComponents(
 	sessiondata.Session.SellDecisionTreeResult = sessionData.SellDecisionTreeResult /* Hold SellDecisionTree result */
 	sessiondata.Session.QuantityOffset = sessiondata.Session.SymbolFunds            /* Quantity offset */
 
+	if time.Now().Before(sessionData.CooldownUntil) { /* Only display loss streak cooldown while active */
+		sessiondata.Session.CooldownUntil = sessionData.CooldownUntil.Format("15:04:05")
+	}
+
+	sessiondata.Session.Reservation = sessionData.Reservation /* Funds allocator loaded via loadSessionDataAdditionalComponentsAsync */
+	sessiondata.Session.ReservationAvailable = math.Round(sessionData.ReservationAvailable*100) / 100
+	sessiondata.Session.StopPrice = sessionData.StopPrice                                                             /* Absolute stop price */
+	sessiondata.Session.Paused = sessionData.Paused                                                                   /* Thread paused by an operator */
+	sessiondata.Session.CycleState = cycle.State(sessionData)                                                         /* Trade cycle state */
+	sessiondata.Session.ExposureHeadroom = math.Round(risk.ThreadExposureHeadroom(configData, sessionData)*100) / 100 /* Thread exposure loaded via loadSessionDataAdditionalComponentsAsync */
+
+	if health := exchange.ReadHealth(time.Now()); health.Status != "" { /* Exchange health widget */
+
+		sessiondata.Session.Exchange = health.Status
+		sessiondata.Session.ExchangeDetail = strconv.FormatFloat(health.MeanMs, 'f', 0, 64) + "ms mean, " +
+			strconv.FormatFloat(health.ErrorRate*100, 'f', 1, 64) + "% errors, " + strconv.Itoa(health.Calls) + " calls in 5 minutes"
+
+		if limiter := exchange.ReadLimiter(); limiter.Limit > 0 { /* Process-wide concurrency limiter */
+
+			sessiondata.Session.ExchangeDetail += ", " + strconv.Itoa(limiter.Active) + "/" + strconv.Itoa(limiter.Limit) + " calls in progress, " +
+				strconv.Itoa(limiter.Waiting[exchange.LaneOrder]+limiter.Waiting[exchange.LaneBalance]+limiter.Waiting[exchange.LaneStats]) + " waiting"
+
+		}
+
+	}
+
+	if sessionData.Global.DrawdownHalt { /* Display drawdown kill switch status, or drawdown when tracked by the Master Node */
+		sessiondata.Session.Drawdown = "Halted"
+	} else if sessionData.Global.Drawdown > 0 {
+		sessiondata.Session.Drawdown = strconv.FormatFloat(sessionData.Global.Drawdown*100, 'f', 2, 64) + "%"
+	}
+
 	sessiondata.Session.Profit = math.Round(sessionData.Global.Profit*100) / 100                       /* Sessions.Global loaded from mySQL via loadSessionDataAdditionalComponentsAsync */
 	sessiondata.Session.ProfitNet = math.Round(sessionData.Global.ProfitNet*100) / 100                 /* Sessions.Global loaded from mySQL via loadSessionDataAdditionalComponentsAsync */
 	sessiondata.Session.ProfitPct = math.Round(sessionData.Global.ProfitPct*100) / 100                 /* Sessions.Global loaded from mySQL via loadSessionDataAdditionalComponentsAsync */
@@ -106,7 +151,7 @@ func LoadSessionDataAdditionalComponents(
 			tmp.Quantity = key.ExecutedQuantity                                                                                                             /* Order Quantity */
 			tmp.Quote = math.Round(key.CumulativeQuoteQuantity*100) / 100                                                                                   /* Quote price */
 			tmp.Price = math.Round(key.Price*10000) / 10000                                                                                                 /* Acquisition Price */
-			tmp.Target = math.Round((tmp.Price*(1+configData.ProfitMin))*1000) / 1000                                                                       /* Target price */
+			tmp.Target = orderTarget(configData, sessionData, key.Price)                                                                                    /* Target price */
 			tmp.Diff = math.Round((((key.ExecutedQuantity*sessiondata.Market.Price)*(1+configData.ExchangeComission))-key.CumulativeQuoteQuantity)*10) / 10 /* Difference between target and market price */
 
 			sessiondata.Session.Orders = append(sessiondata.Session.Orders, tmp)
@@ -221,6 +266,20 @@ func LoadSessionDataAdditionalComponentsAsync(sessionData *types.Session) {
 
 	}
 
+	/* Load thread open exposure for thread exposure cap headroom */
+	if sessionData.ThreadExposure, err = mysql.GetThreadAmountByThreadID(sessionData); err != nil {
+
+		return
+
+	}
+
+	/* Load fiat funds reserved and available for the thread under the funds allocator */
+	if err = risk.LoadReservation(sessionData); err != nil {
+
+		return
+
+	}
+
 	/* Load total thread dollar amount */
 	if sessionData.Global.ThreadAmount, err = mysql.GetThreadAmount(sessionData); err != nil {
 
@@ -229,3 +288,13 @@ func LoadSessionDataAdditionalComponentsAsync(sessionData *types.Session) {
 	}
 
 }
+
+/* Target price of an order, not below round-trip commissions plus minimum net profit */
+func orderTarget(
+	configData *types.Config,
+	sessionData *types.Session,
+	price float64) float64 {
+
+	return math.Round(math.Max((math.Round(price*10000)/10000)*(1+configData.ProfitMin), exchange.MinimumSellPrice(configData, sessionData, price))*1000) / 1000
+
+}
@@ -0,0 +1,216 @@
+package loader
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	orderPageSize    = 50             /* Orders per page in the orders page */
+	orderSortDefault = "TransactTime" /* Newest orders first */
+	orderSearchMax   = 45             /* Search text characters, the length of the GetOrders parameter */
+)
+
+// OrderColumns list the sortable columns of the orders page in display order
+var OrderColumns = []OrderColumn{
+	{Name: "TransactTime", Label: "Date"},
+	{Name: "ThreadID", Label: "ThreadID"},
+	{Name: "Symbol", Label: "Symbol"},
+	{Name: "Side", Label: "Side"},
+	{Name: "Status", Label: "Status"},
+	{Name: "OrderID", Label: "OrderID"},
+	{Name: "Price", Label: "Price"},
+	{Name: "Quantity", Label: "Quantity"},
+	{Name: "Quote", Label: "Quote"},
+	{Name: "Source", Label: "Source"},
+}
+
+// OrderStatuses list the order statuses available as filter
+var OrderStatuses = []string{"FILLED", "NEW", "PARTIALLY_FILLED", "CANCELED", "EXPIRED", "REJECTED"}
+
+// OrderColumn struct define a sortable column of the orders page
+type OrderColumn struct {
+	Name  string /* Sort name accepted by GetOrders */
+	Label string
+}
+
+// OrderRow struct define an order in the orders page
+type OrderRow struct {
+	types.Trade
+	Date string
+}
+
+// OrdersPage struct define the orders page (orders.html), sorted, filtered and paginated by the database
+type OrdersPage struct {
+	Filter   types.OrderFilter
+	Orders   []OrderRow
+	Columns  []OrderColumn
+	Statuses []string
+	Count    int /* Orders matching Filter */
+	Page     int
+	Pages    int
+	PrevPage int /* 0 on the first page */
+	NextPage int /* 0 on the last page */
+	Message  string
+	Theme    string /* UI theme of the logged in user */
+}
+
+// ParseOrderFilter return the order filter and page number of the orders page query string. Unknown sort
+// columns sort by TransactTime and the direction defaults to descending.
+func ParseOrderFilter(query url.Values) (filter types.OrderFilter, page int) {
+
+	filter.ThreadID = strings.TrimSpace(query.Get("threadID"))
+	filter.Symbol = strings.ToUpper(strings.TrimSpace(query.Get("symbol")))
+	filter.Side = strings.ToUpper(strings.TrimSpace(query.Get("side")))
+	filter.Status = strings.ToUpper(strings.TrimSpace(query.Get("status")))
+	filter.Search = strings.TrimSpace(query.Get("search"))
+	filter.Sort = orderSortDefault
+	filter.Descending = query.Get("dir") != "asc"
+	filter.Limit = orderPageSize
+
+	if search := []rune(filter.Search); len(search) > orderSearchMax {
+		filter.Search = string(search[:orderSearchMax])
+	}
+
+	for _, column := range OrderColumns {
+
+		if column.Name == query.Get("sort") {
+			filter.Sort = column.Name
+		}
+
+	}
+
+	page, _ = strconv.Atoi(query.Get("page"))
+
+	return filter, page
+
+}
+
+// LoadOrders Load the page of the order history selected by the orders page query string
+func LoadOrders(
+	sessionData *types.Session,
+	query url.Values) (page OrdersPage, err error) {
+
+	var orders []types.Trade
+	var number int
+
+	page.Filter, number = ParseOrderFilter(query)
+	page.Columns = OrderColumns
+	page.Statuses = OrderStatuses
+
+	if page.Count, err = mysql.GetOrderCount(sessionData, page.Filter); err != nil {
+
+		return page, err
+
+	}
+
+	page.Pages = pageCount(page.Count, orderPageSize)
+	page.Page = pageNumber(number, page.Pages)
+
+	if page.Page > 1 {
+		page.PrevPage = page.Page - 1
+	}
+
+	if page.Page < page.Pages {
+		page.NextPage = page.Page + 1
+	}
+
+	page.Filter.Offset = (page.Page - 1) * orderPageSize
+
+	if orders, err = mysql.GetOrders(sessionData, page.Filter); err != nil {
+
+		return page, err
+
+	}
+
+	for _, order := range orders {
+
+		page.Orders = append(page.Orders, OrderRow{
+			Trade: order,
+			Date:  time.Unix((order.TransactTime / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+		})
+
+	}
+
+	return page, nil
+
+}
+
+// SortURL return the orders page URL sorted by column, reversing the direction when already sorted by column
+func (page OrdersPage) SortURL(column string) string {
+
+	query := page.query()
+	query.Set("sort", column)
+	query.Del("page") /* A new sort starts on the first page */
+
+	if column == page.Filter.Sort && page.Filter.Descending {
+		query.Set("dir", "asc")
+	} else if column == page.Filter.Sort || column == orderSortDefault {
+		query.Set("dir", "desc")
+	} else {
+		query.Set("dir", "asc")
+	}
+
+	return "/orders?" + query.Encode()
+
+}
+
+// SortIndicator return the sort direction arrow of column, empty when not sorted by column
+func (page OrdersPage) SortIndicator(column string) string {
+
+	switch {
+	case column != page.Filter.Sort:
+		return ""
+	case page.Filter.Descending:
+		return "▼"
+	default:
+		return "▲"
+	}
+
+}
+
+// PageURL return the orders page URL of page number with the current filters and sort
+func (page OrdersPage) PageURL(number int) string {
+
+	query := page.query()
+	query.Set("page", strconv.Itoa(number))
+
+	return "/orders?" + query.Encode()
+
+}
+
+/* Return the query string of the current filters and sort */
+func (page OrdersPage) query() url.Values {
+
+	query := url.Values{}
+
+	for key, value := range map[string]string{
+		"threadID": page.Filter.ThreadID,
+		"symbol":   page.Filter.Symbol,
+		"side":     page.Filter.Side,
+		"status":   page.Filter.Status,
+		"search":   page.Filter.Search,
+	} {
+
+		if value != "" {
+			query.Set(key, value)
+		}
+
+	}
+
+	query.Set("sort", page.Filter.Sort)
+
+	if page.Filter.Descending {
+		query.Set("dir", "desc")
+	} else {
+		query.Set("dir", "asc")
+	}
+
+	return query
+
+}
@@ -0,0 +1,86 @@
+package loader
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParseOrderFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		want     types.OrderFilter
+		wantPage int
+	}{
+		{
+			name:     "defaults",
+			query:    "",
+			want:     types.OrderFilter{Sort: "TransactTime", Descending: true, Limit: orderPageSize},
+			wantPage: 0,
+		},
+		{
+			name:     "filters and sort",
+			query:    "symbol=btcusdt&side=sell&status=filled&search=+123+&sort=Price&dir=asc&page=3",
+			want:     types.OrderFilter{Symbol: "BTCUSDT", Side: "SELL", Status: "FILLED", Search: "123", Sort: "Price", Descending: false, Limit: orderPageSize},
+			wantPage: 3,
+		},
+		{
+			name:     "unknown sort column",
+			query:    "sort=ClientOrderId;DROP",
+			want:     types.OrderFilter{Sort: "TransactTime", Descending: true, Limit: orderPageSize},
+			wantPage: 0,
+		},
+		{
+			name:     "long search",
+			query:    "search=" + strings.Repeat("é", 50),
+			want:     types.OrderFilter{Search: strings.Repeat("é", orderSearchMax), Sort: "TransactTime", Descending: true, Limit: orderPageSize},
+			wantPage: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			got, page := ParseOrderFilter(query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseOrderFilter() = %v, want %v", got, tt.want)
+			}
+			if page != tt.wantPage {
+				t.Errorf("ParseOrderFilter() page = %v, want %v", page, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestOrdersPage_SortURL(t *testing.T) {
+	page := OrdersPage{Filter: types.OrderFilter{Symbol: "BTCUSDT", Sort: "Price", Descending: true}, Page: 2}
+	tests := []struct {
+		name   string
+		column string
+		want   string
+	}{
+		{name: "reverse current column", column: "Price", want: "/orders?dir=asc&sort=Price&symbol=BTCUSDT"},
+		{name: "new column", column: "Symbol", want: "/orders?dir=asc&sort=Symbol&symbol=BTCUSDT"},
+		{name: "date newest first", column: "TransactTime", want: "/orders?dir=desc&sort=TransactTime&symbol=BTCUSDT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := page.SortURL(tt.column); got != tt.want {
+				t.Errorf("SortURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrdersPage_PageURL(t *testing.T) {
+	page := OrdersPage{Filter: types.OrderFilter{Search: "a&b", Sort: "OrderID", Descending: false}}
+	if got, want := page.PageURL(4), "/orders?dir=asc&page=4&search=a%26b&sort=OrderID"; got != want {
+		t.Errorf("PageURL() = %v, want %v", got, want)
+	}
+	if got, want := page.SortIndicator("OrderID"), "▲"; got != want {
+		t.Errorf("SortIndicator() = %v, want %v", got, want)
+	}
+}
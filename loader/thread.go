@@ -0,0 +1,278 @@
+package loader
+
+import (
+	"math"
+	"time"
+
+	"github.com/aleibovici/cryptopump/adoption"
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/labels"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/perf"
+	"github.com/aleibovici/cryptopump/scheduler"
+	"github.com/aleibovici/cryptopump/shadow"
+	"github.com/aleibovici/cryptopump/types"
+	"github.com/aleibovici/cryptopump/wsstats"
+)
+
+const (
+	threadCyclePageSize  = 20 /* Closed cycles per page in the thread detail page */
+	threadWsHistoryLimit = 20 /* Websocket connections in the thread detail page */
+)
+
+// ThreadDetail struct define the thread detail page (thread.html)
+type ThreadDetail struct {
+	ThreadID               string
+	Label                  types.SessionLabel /* Name and tags of the thread */
+	Symbol                 string
+	CycleState             string                 /* Trade cycle state */
+	CycleOrderID           int64                  /* Order in flight of the trade cycle */
+	Config                 map[string]interface{} /* Thread configuration */
+	Market                 types.Market           /* Live indicator values */
+	BuyDecisionTreeResult  string
+	SellDecisionTreeResult string
+	Performance            perf.Stats           /* Cycle performance metrics of the thread loop */
+	Websockets             []wsstats.Stream     /* Connection statistics of the websocket streams */
+	Schedule               []scheduler.Run      /* Next runs of the thread schedule */
+	WebsocketHistory       []ThreadWsConnection /* Latest websocket connections, most recently disconnected first */
+	Orders                 []ThreadOrder        /* Open BUY transactions */
+	Adoption               adoption.Status      /* Exchange holdings reconciled with the open transactions */
+	DryRun                 bool                 /* Orders simulated in the shadow orders, never sent to the exchange */
+	Shadow                 shadow.Status        /* Simulated orders of the thread in dry-run mode */
+	ShadowOrders           []ThreadShadowOrder  /* Latest simulated orders, most recent first */
+	Cycles                 []ThreadCycle        /* Page of closed BUY/SELL cycles */
+	Page                   int
+	Pages                  int
+	PrevPage               int    /* 0 on the first page */
+	NextPage               int    /* 0 on the last page */
+	Theme                  string /* UI theme of the logged in user */
+	CanTrade               bool   /* Clone form population */
+	Message                string /* Result of the last action */
+}
+
+// ThreadOrder struct define an open BUY transaction in the thread detail page
+type ThreadOrder struct {
+	OrderID  int64
+	Quantity float64
+	Quote    float64
+	Price    float64
+	Target   float64
+	Distance float64 /* Market price change to reach target as percentage */
+}
+
+// ThreadShadowOrder struct define a simulated order of a thread in dry-run mode in the thread detail page
+type ThreadShadowOrder struct {
+	types.ShadowOrder
+	Date string /* Order date */
+}
+
+// ThreadWsConnection struct define a websocket connection in the thread detail page
+type ThreadWsConnection struct {
+	types.WsConnection
+	Connected    string  /* Connection date */
+	Disconnected string  /* Disconnection date */
+	Duration     string  /* Connection duration */
+	MessageRate  float64 /* Messages per second */
+}
+
+// ThreadCycle struct define a closed BUY/SELL cycle and its realized profit in the thread detail page
+type ThreadCycle struct {
+	types.ThreadCycle
+	Profit    float64
+	ProfitPct float64
+	Date      string /* Sale date */
+}
+
+// LoadThreadDetail Load configuration, live indicators, open transactions and a page of closed cycles for the thread detail page
+func LoadThreadDetail(
+	configData *types.Config,
+	sessionData *types.Session,
+	marketData *types.Market,
+	settings map[string]interface{},
+	page int) (detail ThreadDetail, err error) {
+
+	var orders []types.Order
+	var cycles []types.ThreadCycle
+	var connections []types.WsConnection
+	var count int
+
+	detail.ThreadID = sessionData.ThreadID
+	detail.Symbol = sessionData.Symbol
+	detail.CycleState = cycle.State(sessionData)
+	detail.CycleOrderID = sessionData.CycleOrderID
+	detail.Config = settings
+	detail.Market = *marketData
+	detail.BuyDecisionTreeResult = sessionData.BuyDecisionTreeResult
+	detail.SellDecisionTreeResult = sessionData.SellDecisionTreeResult
+	detail.Performance = perf.Read(sessionData.ThreadID, time.Now())
+	detail.Websockets = wsstats.Read(sessionData.ThreadID, time.Now())
+	detail.Schedule = scheduler.Runs(configData, time.Now())
+
+	if sessionData.ThreadID == "" { /* No thread running in this session */
+
+		return detail, nil
+
+	}
+
+	detail.Label = labels.Get(sessionData, sessionData.ThreadID)
+
+	if orders, err = mysql.GetThreadTransactionByThreadID(sessionData); err != nil {
+
+		return detail, err
+
+	}
+
+	for _, order := range orders {
+
+		target := orderTarget(configData, sessionData, order.Price)
+
+		detail.Orders = append(detail.Orders, ThreadOrder{
+			OrderID:  order.OrderID,
+			Quantity: order.ExecutedQuantity,
+			Quote:    math.Round(order.CumulativeQuoteQuantity*100) / 100,
+			Price:    math.Round(order.Price*10000) / 10000,
+			Target:   target,
+			Distance: math.Round(targetDistance(target, marketData.Price)*100) / 100,
+		})
+
+	}
+
+	if detail.Adoption, err = adoption.Check(sessionData, marketData); err != nil {
+
+		return detail, err
+
+	}
+
+	if detail.DryRun = configData.DryRun; detail.DryRun {
+
+		if detail.Shadow, err = shadow.Load(sessionData, marketData.Price); err != nil {
+
+			return detail, err
+
+		}
+
+		for _, order := range detail.Shadow.Orders {
+
+			detail.ShadowOrders = append(detail.ShadowOrders, ThreadShadowOrder{
+				ShadowOrder: order,
+				Date:        time.Unix((order.TransactTime / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+			})
+
+		}
+
+	}
+
+	if connections, err = mysql.GetWsConnections(sessionData, threadWsHistoryLimit); err != nil {
+
+		return detail, err
+
+	}
+
+	for _, connection := range connections {
+
+		duration := time.Duration(connection.DisconnectedTime-connection.ConnectedTime) * time.Millisecond
+
+		tmp := ThreadWsConnection{
+			WsConnection: connection,
+			Connected:    time.Unix((connection.ConnectedTime / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+			Disconnected: time.Unix((connection.DisconnectedTime / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+			Duration:     duration.Round(time.Second).String(),
+		}
+
+		if duration > 0 {
+			tmp.MessageRate = math.Round(float64(connection.Messages)/duration.Seconds()*100) / 100
+		}
+
+		detail.WebsocketHistory = append(detail.WebsocketHistory, tmp)
+
+	}
+
+	if count, err = mysql.GetThreadCycleCount(sessionData); err != nil {
+
+		return detail, err
+
+	}
+
+	detail.Pages = pageCount(count, threadCyclePageSize)
+	detail.Page = pageNumber(page, detail.Pages)
+
+	if detail.Page > 1 {
+		detail.PrevPage = detail.Page - 1
+	}
+
+	if detail.Page < detail.Pages {
+		detail.NextPage = detail.Page + 1
+	}
+
+	if cycles, err = mysql.GetThreadCycles(sessionData, threadCyclePageSize, (detail.Page-1)*threadCyclePageSize); err != nil {
+
+		return detail, err
+
+	}
+
+	for _, cycle := range cycles {
+
+		profit := cycle.SellQuote - cycle.BuyQuote
+
+		tmp := ThreadCycle{
+			ThreadCycle: cycle,
+			Profit:      math.Round(profit*100) / 100,
+			Date:        time.Unix((cycle.TransactTime / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+		}
+
+		if cycle.BuyQuote > 0 {
+			tmp.ProfitPct = math.Round((profit/cycle.BuyQuote)*10000) / 100
+		}
+
+		detail.Cycles = append(detail.Cycles, tmp)
+
+	}
+
+	return detail, nil
+
+}
+
+/* Market price change required to reach target as percentage */
+func targetDistance(target float64, price float64) float64 {
+
+	if price <= 0 {
+
+		return 0
+
+	}
+
+	return ((target - price) / price) * 100
+
+}
+
+/* Number of pages for count items, at least 1 */
+func pageCount(count int, pageSize int) int {
+
+	if count <= 0 {
+
+		return 1
+
+	}
+
+	return (count + pageSize - 1) / pageSize
+
+}
+
+/* Page number limited to 1 through pages */
+func pageNumber(page int, pages int) int {
+
+	if page < 1 {
+
+		return 1
+
+	}
+
+	if page > pages {
+
+		return pages
+
+	}
+
+	return page
+
+}
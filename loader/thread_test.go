@@ -0,0 +1,108 @@
+package loader
+
+import "testing"
+
+func Test_targetDistance(t *testing.T) {
+	type args struct {
+		target float64
+		price  float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "below target",
+			args: args{target: 110, price: 100},
+			want: 10,
+		},
+		{
+			name: "above target",
+			args: args{target: 100, price: 125},
+			want: -20,
+		},
+		{
+			name: "no market price",
+			args: args{target: 100, price: 0},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := targetDistance(tt.args.target, tt.args.price); got != tt.want {
+				t.Errorf("targetDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pageCount(t *testing.T) {
+	type args struct {
+		count    int
+		pageSize int
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "no items",
+			args: args{count: 0, pageSize: 20},
+			want: 1,
+		},
+		{
+			name: "full page",
+			args: args{count: 40, pageSize: 20},
+			want: 2,
+		},
+		{
+			name: "partial page",
+			args: args{count: 41, pageSize: 20},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageCount(tt.args.count, tt.args.pageSize); got != tt.want {
+				t.Errorf("pageCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pageNumber(t *testing.T) {
+	type args struct {
+		page  int
+		pages int
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "valid page",
+			args: args{page: 2, pages: 3},
+			want: 2,
+		},
+		{
+			name: "page zero",
+			args: args{page: 0, pages: 3},
+			want: 1,
+		},
+		{
+			name: "page after last",
+			args: args{page: 9, pages: 3},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageNumber(tt.args.page, tt.args.pages); got != tt.want {
+				t.Errorf("pageNumber() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package loader
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/labels"
+	"github.com/aleibovici/cryptopump/logviewer"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	timelineLimit  = 500            /* Events listed in the timeline page */
+	timelinePeriod = 24 * time.Hour /* Default time range ending now */
+	timelineLayout = "2006-01-02T15:04"
+)
+
+// TimelineKinds list the event kinds of the timeline page
+var TimelineKinds = []string{"buy", "sell", "config", "pause", "resume", "note", "approval", "liquidation", "adoption", "cycle", "warning", "error"}
+
+// TimelineEvent struct define an event in the timeline page
+type TimelineEvent struct {
+	types.ThreadEvent
+	Date string
+}
+
+// TimelinePage struct define the timeline page (timeline.html), the history of a thread as an ordered event feed
+type TimelinePage struct {
+	ThreadID  string
+	Label     types.SessionLabel /* Name and tags of the thread */
+	From      string             /* 2006-01-02T15:04 */
+	To        string
+	Kind      string /* Empty lists all kinds */
+	Kinds     []string
+	Events    []TimelineEvent
+	Truncated bool /* More events matched than listed */
+	Message   string
+	Theme     string /* UI theme of the logged in user */
+}
+
+// LoadTimeline Load the orders, configuration changes, pauses, notes, approvals, liquidations and logged warnings
+// and errors of a thread selected by the timeline page query string. The thread defaults to the session thread
+// and the time range to the last 24 hours. A session name or tag selects the first thread named or tagged.
+func LoadTimeline(
+	sessionData *types.Session,
+	query url.Values) (page TimelinePage, err error) {
+
+	var events []types.ThreadEvent
+	var from, to time.Time
+
+	page.ThreadID = strings.TrimSpace(query.Get("threadID"))
+	page.Kind = strings.ToLower(query.Get("kind"))
+	page.Kinds = TimelineKinds
+
+	if page.ThreadID == "" {
+		page.ThreadID = sessionData.ThreadID
+	} else {
+		page.ThreadID = labels.Resolve(sessionData, page.ThreadID)[0]
+	}
+
+	if from, to, err = timelineRange(query.Get("from"), query.Get("to"), time.Now()); err != nil {
+
+		return page, err
+
+	}
+
+	page.From = from.Format(timelineLayout)
+	page.To = to.Format(timelineLayout)
+
+	if page.ThreadID == "" { /* No thread running in this session */
+
+		return page, nil
+
+	}
+
+	page.Label = labels.Get(sessionData, page.ThreadID)
+
+	if events, err = mysql.GetThreadTimeline(sessionData, page.ThreadID, from.UnixNano()/int64(time.Millisecond), to.UnixNano()/int64(time.Millisecond), timelineLimit+1); err != nil {
+
+		return page, err
+
+	}
+
+	viewer := logviewer.Load(logviewer.Filter{ /* Warnings and errors are only stored in the log files */
+		ThreadID: page.ThreadID,
+		From:     page.From,
+		To:       page.To,
+	})
+
+	events = mergeTimeline(events, viewer.Lines)
+
+	for _, event := range events {
+
+		if page.Kind != "" && event.Kind != page.Kind {
+			continue
+		}
+
+		if len(page.Events) == timelineLimit {
+
+			page.Truncated = true
+			break
+
+		}
+
+		page.Events = append(page.Events, TimelineEvent{
+			ThreadEvent: event,
+			Date:        time.Unix((event.Time / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+		})
+
+	}
+
+	return page, nil
+
+}
+
+/* Parse the timeline time range, from defaults to timelinePeriod before to and to defaults to now */
+func timelineRange(
+	fromText string,
+	toText string,
+	now time.Time) (from time.Time, to time.Time, err error) {
+
+	to = now.Truncate(time.Minute).Add(time.Minute - time.Millisecond)
+
+	if toText != "" {
+
+		if to, err = time.ParseInLocation(timelineLayout, toText, time.Local); err != nil {
+
+			return from, to, err
+
+		}
+
+		to = to.Add(time.Minute - time.Millisecond) /* Include the whole minute */
+
+	}
+
+	from = to.Add(-timelinePeriod).Truncate(time.Minute)
+
+	if fromText != "" {
+
+		if from, err = time.ParseInLocation(timelineLayout, fromText, time.Local); err != nil {
+
+			return from, to, err
+
+		}
+
+	}
+
+	return from, to, nil
+
+}
+
+/* Add the warning and error log lines to the database events, ordered by time */
+func mergeTimeline(
+	events []types.ThreadEvent,
+	lines []logviewer.Line) []types.ThreadEvent {
+
+	for _, line := range lines {
+
+		var kind string
+
+		switch strings.ToLower(line.Level) {
+		case "warning":
+			kind = "warning"
+		case "error", "fatal", "panic":
+			kind = "error"
+		default:
+			continue
+		}
+
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", line.Time, time.Local)
+		if err != nil {
+			continue
+		}
+
+		detail := line.Message
+		if line.Fields != "" {
+			detail += " " + line.Fields
+		}
+
+		events = append(events, types.ThreadEvent{
+			Time:   t.UnixNano() / int64(time.Millisecond),
+			Kind:   kind,
+			Source: "log",
+			Detail: detail,
+		})
+
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time < events[j].Time })
+
+	return events
+
+}
@@ -0,0 +1,90 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logviewer"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_timelineRange(t *testing.T) {
+	now := time.Date(2021, 12, 1, 10, 30, 45, 0, time.Local)
+	tests := []struct {
+		name     string
+		fromText string
+		toText   string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			wantFrom: "2021-11-30T10:30",
+			wantTo:   "2021-12-01T10:30",
+		},
+		{
+			name:     "to only",
+			toText:   "2021-11-15T08:00",
+			wantFrom: "2021-11-14T08:00",
+			wantTo:   "2021-11-15T08:00",
+		},
+		{
+			name:     "from and to",
+			fromText: "2021-11-01T00:00",
+			toText:   "2021-11-02T00:00",
+			wantFrom: "2021-11-01T00:00",
+			wantTo:   "2021-11-02T00:00",
+		},
+		{
+			name:     "invalid",
+			fromText: "yesterday",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := timelineRange(tt.fromText, tt.toText, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("timelineRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := from.Format(timelineLayout); got != tt.wantFrom {
+				t.Errorf("timelineRange() from = %v, want %v", got, tt.wantFrom)
+			}
+			if got := to.Format(timelineLayout); got != tt.wantTo {
+				t.Errorf("timelineRange() to = %v, want %v", got, tt.wantTo)
+			}
+		})
+	}
+}
+
+func Test_mergeTimeline(t *testing.T) {
+	at := func(value string) int64 {
+		t, _ := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+	events := []types.ThreadEvent{
+		{Time: at("2021-12-01 10:00:00"), Kind: "buy", Source: "bot", OrderID: 1, Detail: "FILLED"},
+		{Time: at("2021-12-01 10:10:00"), Kind: "pause", Source: "admin", Detail: "Thread paused by admin"},
+	}
+	lines := []logviewer.Line{
+		{Time: "2021-12-01 10:05:00", Level: "error", Message: "Order rejected", Fields: "symbol=BTCUSDT"},
+		{Time: "2021-12-01 10:06:00", Level: "info", Message: "BUY"},
+		{Time: "2021-12-01 10:07:00", Level: "warning", Message: "Slow exchange"},
+		{Time: "invalid", Level: "error", Message: "Ignored"},
+	}
+	want := []types.ThreadEvent{
+		{Time: at("2021-12-01 10:00:00"), Kind: "buy", Source: "bot", OrderID: 1, Detail: "FILLED"},
+		{Time: at("2021-12-01 10:05:00"), Kind: "error", Source: "log", Detail: "Order rejected symbol=BTCUSDT"},
+		{Time: at("2021-12-01 10:07:00"), Kind: "warning", Source: "log", Detail: "Slow exchange"},
+		{Time: at("2021-12-01 10:10:00"), Kind: "pause", Source: "admin", Detail: "Thread paused by admin"},
+	}
+	if got := mergeTimeline(events, lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeTimeline() = %v, want %v", got, want)
+	}
+}
@@ -2,14 +2,171 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aleibovici/cryptopump/types"
 
 	log "github.com/sirupsen/logrus"
 )
 
+/* Log files, written to the working directory */
+const (
+	InfoFile  = "cryptopump.log"       /* InfoLevel entries */
+	DebugFile = "cryptopump_debug.log" /* DebugLevel entries */
+)
+
+var observers []func(LogEntry) /* Called with every log entry, registered at startup */
+
+var mutex sync.Mutex /* Serialize the log entries */
+
+/* Log levels, from the most to the least verbose */
+const (
+	LevelDebug = "debug" /* InfoLevel and DebugLevel entries, errors are logged as DebugLevel */
+	LevelInfo  = "info"  /* InfoLevel entries */
+	LevelOff   = "off"   /* No entries */
+)
+
+// Levels list the log levels
+var Levels = []string{LevelDebug, LevelInfo, LevelOff}
+
+/* Subsystems with their own log level, identified by the function name logged in the message */
+const (
+	SubsystemExchange   = "exchange"
+	SubsystemMysql      = "mysql"
+	SubsystemThreads    = "threads"
+	SubsystemAlgorithms = "algorithms"
+)
+
+// Subsystems list the subsystems with their own log level
+var Subsystems = []string{SubsystemExchange, SubsystemMysql, SubsystemThreads, SubsystemAlgorithms}
+
+var levels = struct {
+	sync.Mutex
+	global    string            /* Level of the entries of other subsystems */
+	subsystem map[string]string /* Level of each subsystem, the global level when not set */
+}{global: LevelDebug, subsystem: make(map[string]string)}
+
+// Configure set the log levels, the log file rotation and retention, and the syslog output of the global
+// configuration. Called when the configuration is loaded and reloaded so changes apply without restarting.
+func Configure(configData *types.Config) {
+
+	global := configData.ConfigGlobal
+	if global == nil {
+
+		return
+
+	}
+
+	configureLevels(map[string]string{
+		"":                  global.LogLevel,
+		SubsystemExchange:   global.LogLevelExchange,
+		SubsystemMysql:      global.LogLevelMysql,
+		SubsystemThreads:    global.LogLevelThreads,
+		SubsystemAlgorithms: global.LogLevelAlgorithms,
+	})
+
+	configureRotation(global)
+	configureSyslog(global)
+
+}
+
+// ValidLevel return true when level is a log level, or empty when empty is allowed (subsystem using the global level)
+func ValidLevel(
+	level string,
+	empty bool) bool {
+
+	if level == "" {
+
+		return empty
+
+	}
+
+	for _, valid := range Levels {
+
+		if level == valid {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+// Subsystem return the subsystem of a log message from the function name it starts with, empty for other messages
+func Subsystem(message string) string {
+
+	for _, subsystem := range Subsystems {
+
+		if strings.Contains(message, "cryptopump/"+subsystem+".") {
+
+			return subsystem
+
+		}
+
+	}
+
+	return ""
+
+}
+
+/* Set the global level (key "") and the subsystem levels, invalid levels are ignored */
+func configureLevels(configured map[string]string) {
+
+	levels.Lock()
+	defer levels.Unlock()
+
+	levels.global = LevelDebug
+	if level := strings.ToLower(configured[""]); ValidLevel(level, false) {
+		levels.global = level
+	}
+
+	for _, subsystem := range Subsystems {
+
+		levels.subsystem[subsystem] = ""
+		if level := strings.ToLower(configured[subsystem]); ValidLevel(level, true) {
+			levels.subsystem[subsystem] = level
+		}
+
+	}
+
+}
+
+// Enabled return true when the log level of the subsystem of the entry writes it
+func (logEntry LogEntry) Enabled() bool {
+
+	levels.Lock()
+	level := levels.global
+	if subsystem := levels.subsystem[Subsystem(logEntry.Message)]; subsystem != "" {
+		level = subsystem
+	}
+	levels.Unlock()
+
+	switch level {
+	case LevelOff:
+		return false
+	case LevelInfo:
+		return strings.EqualFold(logEntry.LogLevel, "InfoLevel")
+	}
+
+	return true
+
+}
+
+// Observe register a function called with every log entry, used to monitor errors without importing the
+// packages that log them
+func Observe(observer func(LogEntry)) {
+
+	observers = append(observers, observer)
+
+}
+
 // LogEntry struct
 type LogEntry struct {
 	Config   *types.Config  /* Config struct */
@@ -27,19 +184,32 @@ func (logEntry LogEntry) level() (filename string) {
 	switch strings.ToLower(logEntry.LogLevel) {
 	case "infolevel":
 		log.SetLevel(log.InfoLevel)
-		filename = "cryptopump.log"
+		filename = InfoFile
 	case "debuglevel":
 		log.SetLevel(log.DebugLevel)
-		filename = "cryptopump_debug.log"
+		filename = DebugFile
 	default:
 		log.SetLevel(log.DebugLevel)
-		filename = "cryptopump_debug.log"
+		filename = DebugFile
 	}
 
 	return filename
 
 }
 
+/* Return fields with the correlation ID of the trade cycle being processed */
+func (logEntry LogEntry) fields(fields log.Fields) log.Fields {
+
+	if logEntry.Session != nil && logEntry.Session.CorrelationID != "" {
+
+		fields["correlationID"] = logEntry.Session.CorrelationID
+
+	}
+
+	return fields
+
+}
+
 /* Set the log formatter */
 func (logEntry LogEntry) formatter() {
 
@@ -59,17 +229,46 @@ func (logEntry LogEntry) Do() {
 	var err error
 	var file *os.File
 
+	for _, observer := range observers {
+
+		observer(logEntry)
+
+	}
+
+	if !logEntry.Enabled() { /* Observers receive the entries filtered by the log levels */
+
+		return
+
+	}
+
+	only := syslogOnly() /* Entries sent to syslog only are not written to the log files */
+
+	mutex.Lock() /* The logrus standard logger is shared, entries are written one at a time */
+	defer mutex.Unlock()
+
 	logEntry.formatter()         /* Set the log formatter */
 	filename := logEntry.level() /* Define the log level for the entry */
 
-	/* io.Writer output set for file */
-	if file, err = os.OpenFile(filename, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0666); err != nil { /* Open the file */
+	if only {
 
-		log.Fatal(err) /* Log the error */
+		log.SetOutput(io.Discard) /* Sent by the syslog hook */
 
-	}
+	} else {
+
+		rotate(filename, time.Now()) /* Rotate the log file when due before writing */
+
+		/* io.Writer output set for file */
+		if file, err = os.OpenFile(filename, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0666); err != nil { /* Open the file */
 
-	log.SetOutput(file) /* Set the output for the logger */
+			log.Fatal(err) /* Log the error */
+
+		}
+
+		defer file.Close() /* Reopened for each entry so a rotated file is not written */
+
+		log.SetOutput(file) /* Set the output for the logger */
+
+	}
 
 	switch {
 	case log.StandardLogger().GetLevel() == log.InfoLevel:
@@ -77,7 +276,7 @@ func (logEntry LogEntry) Do() {
 		switch logEntry.Message {
 		case "UP", "DOWN", "INIT":
 
-			log.WithFields(log.Fields{
+			log.WithFields(logEntry.fields(log.Fields{
 				"threadID":  logEntry.Session.ThreadID,
 				"rsi3":      fmt.Sprintf("%.2f", logEntry.Market.Rsi3),
 				"rsi7":      fmt.Sprintf("%.2f", logEntry.Market.Rsi7),
@@ -85,57 +284,58 @@ func (logEntry LogEntry) Do() {
 				"MACD":      fmt.Sprintf("%.2f", logEntry.Market.MACD),
 				"high":      logEntry.Market.PriceChangeStatsHighPrice,
 				"direction": logEntry.Market.Direction,
-			}).Info(logEntry.Message)
+				"score":     fmt.Sprintf("%.2f", logEntry.Session.BuyScore),
+			})).Info(logEntry.Message)
 
 		case "BUY":
 
-			log.WithFields(log.Fields{
+			log.WithFields(logEntry.fields(log.Fields{
 				"threadID":   logEntry.Session.ThreadID,
 				"orderID":    logEntry.Order.OrderID,
 				"orderPrice": fmt.Sprintf("%.4f", logEntry.Order.Price),
-			}).Info(logEntry.Message)
+			})).Info(logEntry.Message)
 
 		case "BUYDRYRUN":
 
-			log.WithFields(log.Fields{
+			log.WithFields(logEntry.fields(log.Fields{
 				"threadID":   logEntry.Session.ThreadID,
 				"orderPrice": fmt.Sprintf("%.4f", logEntry.Order.Price),
-			}).Info(logEntry.Message)
+			})).Info(logEntry.Message)
 
 		case "SELL":
 
-			log.WithFields(log.Fields{
+			log.WithFields(logEntry.fields(log.Fields{
 				"threadID":      logEntry.Session.ThreadID,
 				"OrderIDSource": logEntry.Order.OrderIDSource,
 				"orderID":       logEntry.Order.OrderID,
 				"orderPrice":    fmt.Sprintf("%.4f", logEntry.Order.Price),
-			}).Info(logEntry.Message)
+			})).Info(logEntry.Message)
 
 		case "SELLDRYRUN":
 
-			log.WithFields(log.Fields{
+			log.WithFields(logEntry.fields(log.Fields{
 				"threadID":   logEntry.Session.ThreadID,
 				"orderPrice": fmt.Sprintf("%.4f", logEntry.Order.Price),
-			}).Info(logEntry.Message)
+			})).Info(logEntry.Message)
 
 		case "CANCELED":
 
 			if logEntry.Config.Debug {
 
-				log.WithFields(log.Fields{
+				log.WithFields(logEntry.fields(log.Fields{
 					"threadID":      logEntry.Session.ThreadID,
 					"OrderIDSource": logEntry.Order.OrderIDSource,
 					"orderID":       logEntry.Order.OrderID,
-				}).Info(logEntry.Message)
+				})).Info(logEntry.Message)
 
 			}
 
 		case "STOPLOSS":
 
-			log.WithFields(log.Fields{
+			log.WithFields(logEntry.fields(log.Fields{
 				"threadID": logEntry.Session.ThreadID,
 				"orderID":  logEntry.Order.OrderID,
-			}).Info(logEntry.Message)
+			})).Info(logEntry.Message)
 
 		default:
 
@@ -145,9 +345,9 @@ func (logEntry LogEntry) Do() {
 
 			} else {
 
-				log.WithFields(log.Fields{
+				log.WithFields(logEntry.fields(log.Fields{
 					"threadID": logEntry.Session.ThreadID,
-				}).Info(logEntry.Message)
+				})).Info(logEntry.Message)
 
 			}
 
@@ -161,10 +361,10 @@ func (logEntry LogEntry) Do() {
 
 		} else {
 
-			log.WithFields(log.Fields{
+			log.WithFields(logEntry.fields(log.Fields{
 				"threadID": logEntry.Session.ThreadID,
 				"orderID":  logEntry.Order.OrderID,
-			}).Debug(logEntry.Message)
+			})).Debug(logEntry.Message)
 
 		}
 
@@ -1,9 +1,17 @@
 package logger
 
 import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aleibovici/cryptopump/types"
+
+	log "github.com/sirupsen/logrus"
 )
 
 func TestLogEntry_Do(t *testing.T) {
@@ -115,3 +123,188 @@ func TestLogEntry_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestRotation_due(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 30, 0, 0, time.Local)
+	dir := t.TempDir()
+	filename := filepath.Join(dir, InfoFile)
+	if err := os.WriteFile(filename, make([]byte, 2048), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		rotation Rotation
+		modTime  time.Time
+		want     bool
+	}{
+		{name: "disabled", rotation: Rotation{}, modTime: now.Add(-72 * time.Hour), want: false},
+		{name: "size reached", rotation: Rotation{MaxSize: 1024}, modTime: now, want: true},
+		{name: "size not reached", rotation: Rotation{MaxSize: 4096}, modTime: now, want: false},
+		{name: "daily before midnight", rotation: Rotation{Period: 24 * time.Hour}, modTime: now.Add(-11 * time.Hour), want: true},
+		{name: "daily same day", rotation: Rotation{Period: 24 * time.Hour}, modTime: now.Add(-10 * time.Hour), want: false},
+		{name: "hourly", rotation: Rotation{Period: time.Hour}, modTime: now.Add(-31 * time.Minute), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.Chtimes(filename, tt.modTime, tt.modTime); err != nil {
+				t.Fatal(err)
+			}
+			info, _ := os.Stat(filename)
+			if got := tt.rotation.due(info, now); got != tt.want {
+				t.Errorf("due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_prune(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 30, 0, 0, time.Local)
+	dir := t.TempDir()
+	filename := filepath.Join(dir, DebugFile)
+
+	for _, name := range []string{
+		filename,
+		filename + ".20211206-000000.gz",
+		filename + ".20211205-000000.gz",
+		filename + ".20211204-000000",
+		filename + ".20211101-000000.gz",
+		filename + ".notes",
+	} {
+		if err := os.WriteFile(name, []byte("log"), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prune(filename, Rotation{MaxBackups: 2, MaxAge: 30 * 24 * time.Hour}, now)
+
+	for name, want := range map[string]bool{
+		filename:                         true,
+		filename + ".20211206-000000.gz": true,
+		filename + ".20211205-000000.gz": true,
+		filename + ".20211204-000000":    false,
+		filename + ".20211101-000000.gz": false,
+		filename + ".notes":              true,
+	} {
+		if _, err := os.Stat(name); (err == nil) != want {
+			t.Errorf("prune() %v exists = %v, want %v", name, err == nil, want)
+		}
+	}
+}
+
+func Test_rotate(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 30, 0, 0, time.Local)
+	dir := t.TempDir()
+	filename := filepath.Join(dir, InfoFile)
+	if err := os.WriteFile(filename, []byte(strings.Repeat("time=\"2021-12-06 10:29:59\" level=info msg=UP\n", 100)), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	rotation.Lock()
+	rotation.Rotation = Rotation{MaxSize: 1024}
+	rotation.Unlock()
+	defer func() {
+		rotation.Lock()
+		rotation.Rotation = Rotation{}
+		rotation.Unlock()
+	}()
+
+	rotate(filename, now)
+
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("rotate() log file not rotated, error = %v", err)
+	}
+	if _, err := os.Stat(filename + ".20211206-103000"); err != nil {
+		t.Errorf("rotate() rotated file error = %v", err)
+	}
+
+	if err := compress(filename + ".20211206-103000"); err != nil {
+		t.Fatalf("compress() error = %v", err)
+	}
+	file, err := os.Open(filename + ".20211206-103000.gz")
+	if err != nil {
+		t.Fatalf("compress() error = %v", err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	if text, _ := io.ReadAll(reader); !strings.HasPrefix(string(text), "time=\"2021-12-06 10:29:59\"") {
+		t.Errorf("compress() = %v", string(text))
+	}
+}
+
+func TestLogEntry_Enabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured map[string]string
+		message    string
+		logLevel   string
+		want       bool
+	}{
+		{
+			name:       "debug level",
+			configured: map[string]string{"": "debug"},
+			message:    "github.com/aleibovici/cryptopump/mysql.SaveSession - timeout",
+			logLevel:   "DebugLevel",
+			want:       true,
+		},
+		{
+			name:       "info level filters debug entries",
+			configured: map[string]string{"": "info"},
+			message:    "github.com/aleibovici/cryptopump/mysql.SaveSession - timeout",
+			logLevel:   "DebugLevel",
+			want:       false,
+		},
+		{
+			name:       "info level writes info entries",
+			configured: map[string]string{"": "info"},
+			message:    "BUY",
+			logLevel:   "InfoLevel",
+			want:       true,
+		},
+		{
+			name:       "subsystem overrides global level",
+			configured: map[string]string{"": "info", SubsystemExchange: "debug"},
+			message:    "github.com/aleibovici/cryptopump/exchange.BuyOrder - insufficient balance",
+			logLevel:   "DebugLevel",
+			want:       true,
+		},
+		{
+			name:       "subsystem off",
+			configured: map[string]string{"": "debug", SubsystemThreads: "off"},
+			message:    "github.com/aleibovici/cryptopump/threads.Thread.Lock - locked",
+			logLevel:   "InfoLevel",
+			want:       false,
+		},
+		{
+			name:       "invalid level defaults to debug",
+			configured: map[string]string{"": "trace", SubsystemMysql: "verbose"},
+			message:    "github.com/aleibovici/cryptopump/mysql.SaveSession - timeout",
+			logLevel:   "DebugLevel",
+			want:       true,
+		},
+	}
+	defer configureLevels(map[string]string{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configureLevels(tt.configured)
+			if got := (LogEntry{Message: tt.message, LogLevel: tt.logLevel}).Enabled(); got != tt.want {
+				t.Errorf("LogEntry.Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogEntry_fields(t *testing.T) {
+	got := LogEntry{Session: &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", CorrelationID: "c683oqdmk1u1120gnmn0"}}.fields(log.Fields{"orderID": 42})
+	if got["correlationID"] != "c683oqdmk1u1120gnmn0" || got["orderID"] != 42 {
+		t.Errorf("fields() = %v", got)
+	}
+
+	got = LogEntry{Session: &types.Session{ThreadID: "c683ok5mk1u1120gnmmg"}}.fields(log.Fields{})
+	if _, ok := got["correlationID"]; ok {
+		t.Errorf("fields() = %v, want no correlationID between trade cycles", got)
+	}
+}
@@ -0,0 +1,196 @@
+package logger
+
+/* Log file rotation and retention. Before each entry is written, the log file is rotated when it reached LogMaxSize
+MB or when a LogRotateHours period (aligned to local midnight) started since its last write: it is renamed to
+<log file>.<yyyymmdd-hhmmss> and, with LogCompress, compressed with gzip in the background. Rotated files beyond
+LogMaxBackups per log file or older than LogMaxDays are deleted. Threads share the log files, a file already rotated
+by another thread is left alone. */
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const archiveLayout = "20060102-150405" /* Rotated log file suffix */
+
+// Rotation struct define the log file rotation and retention
+type Rotation struct {
+	MaxSize    int64         /* Bytes that rotate the log file, 0 disables */
+	Period     time.Duration /* Period between rotations aligned to local midnight, 0 disables */
+	MaxBackups int           /* Rotated files kept per log file, 0 keeps all */
+	MaxAge     time.Duration /* Age of the rotated files kept, 0 keeps all */
+	Compress   bool          /* Compress rotated files with gzip */
+}
+
+var rotation struct {
+	sync.Mutex
+	Rotation
+}
+
+/* Set the log file rotation and retention of the global configuration */
+func configureRotation(global *types.ConfigGlobal) {
+
+	rotation.Lock()
+	defer rotation.Unlock()
+
+	rotation.Rotation = Rotation{
+		MaxSize:    int64(global.LogMaxSize) << 20,
+		Period:     time.Duration(global.LogRotateHours) * time.Hour,
+		MaxBackups: global.LogMaxBackups,
+		MaxAge:     time.Duration(global.LogMaxDays) * 24 * time.Hour,
+		Compress:   global.LogCompress,
+	}
+
+}
+
+/* Rotate filename when due, the rotated files are compressed and pruned in the background */
+func rotate(
+	filename string,
+	now time.Time) {
+
+	rotation.Lock()
+	r := rotation.Rotation
+	rotation.Unlock()
+
+	info, err := os.Stat(filename)
+	if err != nil || !r.due(info, now) {
+
+		return
+
+	}
+
+	archive := filename + "." + now.Format(archiveLayout)
+
+	if err := os.Rename(filename, archive); err != nil { /* Already rotated by another thread */
+
+		return
+
+	}
+
+	go func() {
+
+		if r.Compress {
+			_ = compress(archive)
+		}
+
+		prune(filename, r, time.Now())
+
+	}()
+
+}
+
+/* Return true when the log file must be rotated at now */
+func (r Rotation) due(
+	info os.FileInfo,
+	now time.Time) bool {
+
+	if r.MaxSize > 0 && info.Size() >= r.MaxSize {
+
+		return true
+
+	}
+
+	return r.Period > 0 && info.ModTime().Before(periodStart(now, r.Period))
+
+}
+
+/* Return the start of the rotation period containing now, periods are aligned to local midnight */
+func periodStart(
+	now time.Time,
+	period time.Duration) time.Time {
+
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, now.Location())
+
+	return epoch.Add(now.Sub(epoch) / period * period)
+
+}
+
+/* Compress archive to archive.gz and remove archive */
+func compress(archive string) (err error) {
+
+	var in, out *os.File
+
+	if in, err = os.Open(archive); err != nil {
+
+		return err
+
+	}
+
+	defer in.Close()
+
+	if out, err = os.OpenFile(archive+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666); err != nil {
+
+		return err
+
+	}
+
+	writer := gzip.NewWriter(out)
+
+	if _, err = io.Copy(writer, in); err == nil {
+		err = writer.Close()
+	}
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+
+		os.Remove(archive + ".gz")
+		return err
+
+	}
+
+	return os.Remove(archive)
+
+}
+
+/* Delete the rotated files of filename beyond MaxBackups, newest kept first, or older than MaxAge */
+func prune(
+	filename string,
+	r Rotation,
+	now time.Time) {
+
+	archives, err := filepath.Glob(filename + ".*")
+	if err != nil {
+
+		return
+
+	}
+
+	/* Newest first, the suffix sorts by rotation time */
+	sort.Sort(sort.Reverse(sort.StringSlice(archives)))
+
+	kept := 0
+
+	for _, archive := range archives {
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(archive, filename+"."), ".gz")
+
+		rotated, err := time.ParseInLocation(archiveLayout, stamp, now.Location())
+		if err != nil { /* Not a rotated file */
+
+			continue
+
+		}
+
+		if (r.MaxBackups > 0 && kept >= r.MaxBackups) || (r.MaxAge > 0 && now.Sub(rotated) > r.MaxAge) {
+
+			os.Remove(archive)
+			continue
+
+		}
+
+		kept++
+
+	}
+
+}
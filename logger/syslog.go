@@ -0,0 +1,210 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logger
+
+/* Syslog output. With LogSyslog set, the log entries are also sent to syslog with LogSyslogFacility and
+LogSyslogTag, either to the local syslog daemon (journald reads the same socket) or to a remote collector, and with
+LogSyslogOnly they are no longer written to the log files. InfoLevel entries are sent with the info severity and
+DebugLevel entries, the errors, with the debug severity. A syslog target that can't be reached falls back to the log
+files and is retried when the configuration is reloaded. */
+
+import (
+	"errors"
+	"fmt"
+	"log/syslog"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aleibovici/cryptopump/types"
+
+	log "github.com/sirupsen/logrus"
+	lsyslog "github.com/sirupsen/logrus/hooks/syslog"
+)
+
+/* Syslog defaults */
+const (
+	defaultFacility = "daemon"
+	defaultTag      = "cryptopump"
+)
+
+/* Syslog errors */
+var (
+	errSyslogTarget   = errors.New("invalid syslog target")
+	errSyslogFacility = errors.New("invalid syslog facility")
+)
+
+var facilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"mail":     syslog.LOG_MAIL,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"lpr":      syslog.LOG_LPR,
+	"news":     syslog.LOG_NEWS,
+	"uucp":     syslog.LOG_UUCP,
+	"cron":     syslog.LOG_CRON,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"ftp":      syslog.LOG_FTP,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+var output = struct {
+	sync.Mutex
+	settings string              /* Target, facility and tag of the hook, or of the last failed attempt */
+	hook     *lsyslog.SyslogHook /* nil when syslog is disabled or unreachable */
+	only     bool                /* LogSyslogOnly */
+}{}
+
+/* Connect the syslog output of the global configuration when its settings changed */
+func configureSyslog(global *types.ConfigGlobal) {
+
+	target := strings.TrimSpace(global.LogSyslog)
+	facility := strings.ToLower(strings.TrimSpace(global.LogSyslogFacility))
+	tag := strings.TrimSpace(global.LogSyslogTag)
+
+	if facility == "" {
+		facility = defaultFacility
+	}
+
+	if tag == "" {
+		tag = defaultTag
+	}
+
+	settings := target + " " + facility + " " + tag
+
+	output.Lock()
+
+	output.only = global.LogSyslogOnly
+
+	if settings == output.settings && (output.hook != nil || target == "") {
+
+		output.Unlock()
+		return
+
+	}
+
+	retry := settings == output.settings /* Failed settings are retried without logging the error again */
+	output.settings = settings
+
+	output.Unlock()
+
+	var hook *lsyslog.SyslogHook
+	var err error
+
+	if target != "" { /* Dialed without the lock so the entries are not blocked meanwhile */
+		hook, err = dialSyslog(target, facility, tag)
+	}
+
+	output.Lock()
+	mutex.Lock() /* Hooks are not replaced while an entry is written */
+
+	log.StandardLogger().ReplaceHooks(make(log.LevelHooks))
+
+	if output.hook != nil {
+
+		_ = output.hook.Writer.Close()
+
+	}
+
+	output.hook = hook
+
+	if hook != nil {
+
+		log.AddHook(hook)
+
+	}
+
+	mutex.Unlock()
+	output.Unlock()
+
+	if err != nil && !retry {
+
+		LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  nil,
+			Order:    &types.Order{},
+			Message:  fmt.Sprintf("Syslog output %s disabled, logging to files - %s", target, err.Error()),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Return true when the log entries are written to syslog only */
+func syslogOnly() bool {
+
+	output.Lock()
+	defer output.Unlock()
+
+	return output.only && output.hook != nil
+
+}
+
+/* Return the syslog hook of target with facility and tag */
+func dialSyslog(
+	target string,
+	facility string,
+	tag string) (*lsyslog.SyslogHook, error) {
+
+	network, address, err := syslogTarget(target)
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	priority, ok := facilities[facility]
+	if !ok {
+
+		return nil, fmt.Errorf("%w: %s", errSyslogFacility, facility)
+
+	}
+
+	return lsyslog.NewSyslogHook(network, address, priority|syslog.LOG_INFO, tag)
+
+}
+
+/* Return the network and address of a syslog target, both empty for the local syslog daemon */
+func syslogTarget(target string) (network string, address string, err error) {
+
+	if target == "local" {
+
+		return "", "", nil
+
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+
+		return "", "", fmt.Errorf("%w: %s", errSyslogTarget, target)
+
+	}
+
+	switch u.Scheme {
+	case "udp", "tcp":
+		if u.Host == "" || u.Port() == "" {
+			return "", "", fmt.Errorf("%w: %s", errSyslogTarget, target)
+		}
+		return u.Scheme, u.Host, nil
+	case "unix", "unixgram":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("%w: %s", errSyslogTarget, target)
+		}
+		return u.Scheme, u.Path, nil
+	}
+
+	return "", "", fmt.Errorf("%w: %s", errSyslogTarget, target)
+
+}
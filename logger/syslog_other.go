@@ -0,0 +1,20 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package logger
+
+/* Syslog output is not available on this platform, LogSyslog is ignored and the entries are written to the log files */
+
+import (
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Ignore the syslog output of the global configuration */
+func configureSyslog(global *types.ConfigGlobal) {}
+
+/* Return true when the log entries are written to syslog only */
+func syslogOnly() bool {
+
+	return false
+
+}
@@ -0,0 +1,82 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logger
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_syslogTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{name: "local", target: "local", wantNetwork: "", wantAddress: ""},
+		{name: "udp", target: "udp://logs.example.com:514", wantNetwork: "udp", wantAddress: "logs.example.com:514"},
+		{name: "tcp", target: "tcp://10.0.0.5:601", wantNetwork: "tcp", wantAddress: "10.0.0.5:601"},
+		{name: "unix", target: "unix:///dev/log", wantNetwork: "unix", wantAddress: "/dev/log"},
+		{name: "missing port", target: "udp://logs.example.com", wantErr: true},
+		{name: "unknown scheme", target: "http://logs.example.com:514", wantErr: true},
+		{name: "not a target", target: "syslog", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network, address, err := syslogTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("syslogTarget() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if network != tt.wantNetwork || address != tt.wantAddress {
+				t.Errorf("syslogTarget() = %v, %v, want %v, %v", network, address, tt.wantNetwork, tt.wantAddress)
+			}
+		})
+	}
+}
+
+func Test_configureSyslog(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "syslog.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram() error = %v", err)
+	}
+	defer conn.Close()
+
+	configureSyslog(&types.ConfigGlobal{LogSyslog: "unixgram://" + path, LogSyslogFacility: "local3", LogSyslogTag: "pump", LogSyslogOnly: true})
+	defer configureSyslog(&types.ConfigGlobal{})
+
+	if !syslogOnly() {
+		t.Fatalf("syslogOnly() = false, want true")
+	}
+
+	LogEntry{Session: &types.Session{ThreadID: "c683ok5mk1u1120gnmmg"}, Order: &types.Order{}, Message: "Syslog test", LogLevel: "InfoLevel"}.Do()
+
+	buffer := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	n, err := conn.Read(buffer)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	message := string(buffer[:n])
+
+	/* Priority of local3 (19) and info (6) is 19*8+6 */
+	for _, want := range []string{"<158>", "pump[", "Syslog test", "threadID=c683ok5mk1u1120gnmmg"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("syslog message = %v, want %v", message, want)
+		}
+	}
+
+}
@@ -0,0 +1,351 @@
+package logviewer
+
+/* This package implements the log viewer page. The log files written by the logger package are the persistent
+log storage: the viewer reads the tail of cryptopump.log and cryptopump_debug.log, parses the logrus text format
+(time="..." level=... msg=... threadID=...) and filters the entries by thread, level, component, text and time range.
+The entries can also be loaded from the log table, see store.go. */
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+)
+
+/* Log viewer limits */
+const (
+	tailBytes  = 4 << 20 /* Bytes read from the end of each log file */
+	lineLimit  = 500     /* Entries listed in the log viewer page */
+	timeLayout = "2006-01-02 15:04:05"
+)
+
+// Levels list the log levels available to filter the log viewer
+var Levels = []string{"info", "debug", "warning", "error", "fatal"}
+
+// Components list the packages available to filter the log viewer
+var Components = []string{"exchange", "mysql", "threads", "algorithms", "markets", "nodes", "telegram", "api"}
+
+// Line struct define a log entry
+type Line struct {
+	Time      string
+	Level     string
+	ThreadID  string
+	Component string /* Package that logged the entry, i.e. exchange, empty when unknown */
+	Message   string
+	Fields    string /* Other fields as key=value */
+	time      time.Time
+}
+
+// Filter struct define the log viewer filters, empty values match all entries
+type Filter struct {
+	ThreadID  string   /* ThreadID, session name or tag */
+	ThreadIDs []string /* ThreadIDs matching ThreadID resolved by labels.Resolve, ThreadID itself when empty */
+	Level     string
+	Component string
+	Text      string /* Case insensitive text contained in the message or fields */
+	From      string /* 2006-01-02T15:04 */
+	To        string
+	Source    string /* SourceFile or SourceDatabase */
+}
+
+// Viewer struct define the log viewer page (logs.html)
+type Viewer struct {
+	Filter
+	Levels     []string
+	Components []string
+	Lines      []Line
+	Truncated  bool /* More entries matched than listed */
+	Follow     bool /* Refresh the page to tail the logs */
+	Database   bool /* LogDatabase, the log table can be selected as source */
+	Message    string
+	Theme      string /* UI theme of the logged in user */
+}
+
+// Load the most recent log entries matching filter from the info and debug log files, oldest first
+func Load(filter Filter) (viewer Viewer) {
+
+	var lines []Line
+
+	viewer.Filter = filter
+	viewer.Levels = Levels
+	viewer.Components = Components
+
+	from, to, err := timeRange(filter.From, filter.To)
+	if err != nil {
+
+		viewer.Message = err.Error()
+		return viewer
+
+	}
+
+	for _, filename := range []string{logger.InfoFile, logger.DebugFile} {
+
+		tail, err := readTail(filename, tailBytes)
+		if err != nil {
+
+			if !os.IsNotExist(err) {
+				viewer.Message = err.Error()
+			}
+
+			continue
+
+		}
+
+		for _, text := range strings.Split(tail, "\n") {
+
+			if line, ok := Parse(text); ok && match(line, filter, from, to) {
+
+				lines = append(lines, line)
+
+			}
+
+		}
+
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].time.Before(lines[j].time) })
+
+	if len(lines) > lineLimit {
+
+		viewer.Truncated = true
+		lines = lines[len(lines)-lineLimit:]
+
+	}
+
+	viewer.Lines = lines
+
+	return viewer
+
+}
+
+// Parse a log entry written by logrus TextFormatter, i.e. time="2006-01-02 15:04:05" level=info msg=BUY threadID=c683ok5mk1u1120gnmmg
+func Parse(text string) (line Line, ok bool) {
+
+	var fields []string
+
+	for _, pair := range splitFields(strings.TrimSpace(text)) {
+
+		i := strings.Index(pair, "=")
+		if i <= 0 {
+			return line, false
+		}
+
+		key, value := pair[:i], pair[i+1:]
+
+		if strings.HasPrefix(value, `"`) {
+
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+
+		}
+
+		switch key {
+		case "time":
+			line.Time = value
+		case "level":
+			line.Level = value
+		case "msg":
+			line.Message = value
+		case "threadID":
+			line.ThreadID = value
+		default:
+			fields = append(fields, pair)
+		}
+
+	}
+
+	if line.time, ok = parseTime(line.Time); !ok || line.Level == "" {
+
+		return Line{}, false
+
+	}
+
+	line.Fields = strings.Join(fields, " ")
+	line.Component = Component(line.Message)
+
+	return line, true
+
+}
+
+/* Split a log entry in key=value pairs, values may be quoted and contain spaces */
+func splitFields(text string) (pairs []string) {
+
+	var quoted, escaped bool
+	start := 0
+
+	for i, r := range text {
+
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == ' ' && !quoted:
+			if i > start {
+				pairs = append(pairs, text[start:i])
+			}
+			start = i + 1
+		}
+
+	}
+
+	if start < len(text) {
+		pairs = append(pairs, text[start:])
+	}
+
+	return pairs
+
+}
+
+/* Return true when line matches filter and the time range */
+func match(
+	line Line,
+	filter Filter,
+	from time.Time,
+	to time.Time) bool {
+
+	switch {
+	case filter.ThreadID != "" && !matchThread(line.ThreadID, filter):
+		return false
+	case filter.Level != "" && !strings.EqualFold(line.Level, filter.Level):
+		return false
+	case filter.Component != "" && line.Component != filter.Component:
+		return false
+	case !from.IsZero() && line.time.Before(from):
+		return false
+	case !to.IsZero() && line.time.After(to):
+		return false
+	case filter.Text != "" && !strings.Contains(strings.ToLower(line.Message+" "+line.Fields), strings.ToLower(filter.Text)):
+		return false
+	}
+
+	return true
+
+}
+
+/* Return true when threadID is one of the ThreadIDs matching the ThreadID filter */
+func matchThread(
+	threadID string,
+	filter Filter) bool {
+
+	if len(filter.ThreadIDs) == 0 {
+
+		return threadID == strings.TrimSpace(filter.ThreadID)
+
+	}
+
+	for _, id := range filter.ThreadIDs {
+
+		if threadID == id {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Parse the time range filter, an empty value is an open range */
+func timeRange(
+	fromText string,
+	toText string) (from time.Time, to time.Time, err error) {
+
+	if fromText != "" {
+
+		if from, err = time.ParseInLocation("2006-01-02T15:04", fromText, time.Local); err != nil {
+
+			return from, to, err
+
+		}
+
+	}
+
+	if toText != "" {
+
+		if to, err = time.ParseInLocation("2006-01-02T15:04", toText, time.Local); err != nil {
+
+			return from, to, err
+
+		}
+
+		to = to.Add(time.Minute - time.Second) /* Include the whole minute */
+
+	}
+
+	return from, to, nil
+
+}
+
+/* Parse a log entry time */
+func parseTime(value string) (t time.Time, ok bool) {
+
+	var err error
+
+	if t, err = time.ParseInLocation(timeLayout, value, time.Local); err != nil {
+
+		return t, false
+
+	}
+
+	return t, true
+
+}
+
+/* Read up to size bytes from the end of filename, starting at the first complete line */
+func readTail(
+	filename string,
+	size int64) (tail string, err error) {
+
+	var file *os.File
+	var info os.FileInfo
+
+	if file, err = os.Open(filename); err != nil {
+
+		return "", err
+
+	}
+
+	defer file.Close()
+
+	if info, err = file.Stat(); err != nil {
+
+		return "", err
+
+	}
+
+	offset := info.Size() - size
+	if offset < 0 {
+		offset = 0
+	}
+
+	buffer := make([]byte, info.Size()-offset)
+
+	if _, err = file.ReadAt(buffer, offset); err != nil && err != io.EOF {
+
+		return "", err
+
+	}
+
+	tail = string(buffer)
+
+	if offset > 0 { /* Skip the partial first line */
+
+		if i := strings.Index(tail, "\n"); i >= 0 {
+			tail = tail[i+1:]
+		}
+
+	}
+
+	return tail, nil
+
+}
@@ -0,0 +1,135 @@
+package logviewer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   Line
+		wantOk bool
+	}{
+		{
+			name:   "buy",
+			text:   `time="2021-12-01 10:15:00" level=info msg=BUY orderID=123 orderPrice=40000.0000 threadID=c683ok5mk1u1120gnmmg`,
+			want:   Line{Time: "2021-12-01 10:15:00", Level: "info", ThreadID: "c683ok5mk1u1120gnmmg", Message: "BUY", Fields: "orderID=123 orderPrice=40000.0000"},
+			wantOk: true,
+		},
+		{
+			name:   "quoted message",
+			text:   `time="2021-12-01 10:16:00" level=debug msg="BuyTicker - <APIError> code=-2010, msg=\"Account has insufficient balance\"" orderID=0`,
+			want:   Line{Time: "2021-12-01 10:16:00", Level: "debug", Message: `BuyTicker - <APIError> code=-2010, msg="Account has insufficient balance"`, Fields: "orderID=0"},
+			wantOk: true,
+		},
+		{
+			name:   "not a log entry",
+			text:   "panic: runtime error",
+			want:   Line{},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.text)
+			if ok != tt.wantOk {
+				t.Errorf("Parse() ok = %v, want %v", ok, tt.wantOk)
+				return
+			}
+			got.time = time.Time{}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_match(t *testing.T) {
+	line, _ := Parse(`time="2021-12-01 10:15:00" level=info msg="Thread paused by admin" threadID=c683ok5mk1u1120gnmmg`)
+	from, to, _ := timeRange("2021-12-01T10:00", "2021-12-01T10:15")
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{
+			name:   "all",
+			filter: Filter{},
+			want:   true,
+		},
+		{
+			name:   "thread, level and text",
+			filter: Filter{ThreadID: "c683ok5mk1u1120gnmmg", Level: "INFO", Text: "paused"},
+			want:   true,
+		},
+		{
+			name:   "other thread",
+			filter: Filter{ThreadID: "c683ok5mk1u1120gnmmh"},
+			want:   false,
+		},
+		{
+			name:   "tagged threads",
+			filter: Filter{ThreadID: "grid", ThreadIDs: []string{"c683ok5mk1u1120gnmmh", "c683ok5mk1u1120gnmmg", "grid"}},
+			want:   true,
+		},
+		{
+			name:   "other tagged threads",
+			filter: Filter{ThreadID: "grid", ThreadIDs: []string{"c683ok5mk1u1120gnmmh", "grid"}},
+			want:   false,
+		},
+		{
+			name:   "other level",
+			filter: Filter{Level: "debug"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := match(line, tt.filter, from, to); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if match(line, Filter{}, from.Add(time.Hour), time.Time{}) {
+		t.Errorf("match() = true before the time range")
+	}
+}
+
+func Test_readTail(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(filename, []byte("first line\nsecond line\nthird line\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		size int64
+		want string
+	}{
+		{
+			name: "whole file",
+			size: 1024,
+			want: "first line\nsecond line\nthird line\n",
+		},
+		{
+			name: "skip partial line",
+			size: 15,
+			want: "third line\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTail(filename, tt.size)
+			if err != nil {
+				t.Errorf("readTail() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("readTail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package logviewer
+
+/* Log entries are also saved to the log table when LogDatabase is enabled, so the log viewer and the REST API can
+query the entries of every thread sharing the database, across hosts. Entries written to the log files are queued
+as they are logged and saved by Flush, so logging doesn't wait for the database. Entries logged by this package are
+not saved, so a database failure doesn't loop. */
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Log database limits */
+const (
+	queueMax = 1000 /* Entries waiting to be saved, newer entries are dropped when full */
+	queryMax = 1000 /* Entries retrieved from the log table */
+)
+
+/* Sources of the log viewer entries */
+const (
+	SourceFile     = "file"
+	SourceDatabase = "database"
+)
+
+var queue struct {
+	sync.Mutex
+	enabled bool              /* LogDatabase */
+	records []types.LogRecord /* Entries waiting to be saved */
+	dropped int               /* Entries dropped since the last Flush */
+}
+
+// Store queue the entries logged by this thread to be saved to the log table while LogDatabase is enabled. Called
+// once at startup.
+func Store(configData *types.Config) {
+
+	Configure(configData)
+
+	logger.Observe(func(entry logger.LogEntry) {
+
+		if !entry.Enabled() || Component(entry.Message) == "logviewer" { /* Only the entries written to the log files are saved */
+
+			return
+
+		}
+
+		queue.Lock()
+		defer queue.Unlock()
+
+		if !queue.enabled {
+
+			return
+
+		}
+
+		if len(queue.records) >= queueMax {
+
+			queue.dropped++
+			return
+
+		}
+
+		queue.records = append(queue.records, Record(entry, time.Now()))
+
+	})
+
+}
+
+// Configure enable or disable saving the log entries after a configuration reload
+func Configure(configData *types.Config) {
+
+	queue.Lock()
+	defer queue.Unlock()
+
+	queue.enabled = configData.ConfigGlobal != nil && configData.ConfigGlobal.LogDatabase
+
+	if !queue.enabled {
+
+		queue.records = nil
+
+	}
+
+}
+
+// Flush save the queued log entries to the log table
+func Flush(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	queue.Lock()
+	records, dropped := queue.records, queue.dropped
+	queue.records, queue.dropped = nil, 0
+	queue.Unlock()
+
+	if sessionData.Db == nil {
+
+		return
+
+	}
+
+	for i, record := range records {
+
+		if err := mysql.SaveLog(sessionData, record); err != nil {
+
+			log(configData, sessionData, fmt.Sprintf("%s - %d log entries not saved - %s", functions.GetFunctionName(), len(records)-i, err.Error()))
+			return
+
+		}
+
+	}
+
+	if dropped > 0 {
+
+		log(configData, sessionData, fmt.Sprintf("%s - %d log entries dropped, queue full", functions.GetFunctionName(), dropped))
+
+	}
+
+}
+
+// Prune delete the log entries older than LogDatabaseDays from the log table (only Master Node)
+func Prune(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !sessionData.MasterNode || sessionData.Db == nil || configData.ConfigGlobal == nil || configData.ConfigGlobal.LogDatabaseDays <= 0 {
+
+		return
+
+	}
+
+	before := time.Now().AddDate(0, 0, -configData.ConfigGlobal.LogDatabaseDays)
+
+	_ = mysql.DeleteLogBefore(sessionData, before.UnixNano()/int64(time.Millisecond))
+
+}
+
+// Record return the log table entry of a log entry logged at now
+func Record(
+	entry logger.LogEntry,
+	now time.Time) types.LogRecord {
+
+	record := types.LogRecord{
+		Time:      now.UnixNano() / int64(time.Millisecond),
+		Level:     "debug", /* Entries of other levels are written as debug */
+		Component: Component(entry.Message),
+		Message:   entry.Message,
+	}
+
+	if strings.EqualFold(entry.LogLevel, "InfoLevel") {
+		record.Level = "info"
+	}
+
+	if entry.Session != nil {
+		record.ThreadID = entry.Session.ThreadID
+	}
+
+	return record
+
+}
+
+// Component return the package that logged message from the function name it starts with, i.e. exchange for
+// github.com/aleibovici/cryptopump/exchange.BuyOrder, empty when unknown
+func Component(message string) string {
+
+	i := strings.Index(message, "cryptopump/")
+	if i < 0 {
+
+		return ""
+
+	}
+
+	component := message[i+len("cryptopump/"):]
+
+	if j := strings.IndexAny(component, ". "); j >= 0 {
+		component = component[:j]
+	}
+
+	return component
+
+}
+
+// Query return the most recent log entries matching filter from the log table, most recent first
+func Query(
+	sessionData *types.Session,
+	filter Filter,
+	limit int) (records []types.LogRecord, err error) {
+
+	from, to, err := timeRange(filter.From, filter.To)
+	if err != nil {
+
+		return nil, err
+
+	}
+
+	if limit <= 0 || limit > queryMax {
+		limit = queryMax
+	}
+
+	return mysql.GetLogs(sessionData, types.LogFilter{
+		ThreadID:  strings.TrimSpace(filter.ThreadID),
+		Level:     strings.ToLower(filter.Level),
+		Component: filter.Component,
+		Text:      filter.Text,
+		From:      milliseconds(from),
+		To:        milliseconds(to),
+	}, limit)
+
+}
+
+// LoadDatabase load the most recent log entries matching filter from the log table, oldest first
+func LoadDatabase(
+	sessionData *types.Session,
+	filter Filter) (viewer Viewer) {
+
+	viewer.Filter = filter
+	viewer.Levels = Levels
+	viewer.Components = Components
+
+	records, err := Query(sessionData, filter, lineLimit+1)
+	if err != nil {
+
+		viewer.Message = err.Error()
+		return viewer
+
+	}
+
+	if len(records) > lineLimit {
+
+		viewer.Truncated = true
+		records = records[:lineLimit]
+
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+
+		viewer.Lines = append(viewer.Lines, Line{
+			Time:      time.Unix(0, records[i].Time*int64(time.Millisecond)).Format(timeLayout),
+			Level:     records[i].Level,
+			ThreadID:  records[i].ThreadID,
+			Component: records[i].Component,
+			Message:   records[i].Message,
+		})
+
+	}
+
+	return viewer
+
+}
+
+/* Return t in milliseconds, 0 for the zero time of an open range */
+func milliseconds(t time.Time) int64 {
+
+	if t.IsZero() {
+
+		return 0
+
+	}
+
+	return t.UnixNano() / int64(time.Millisecond)
+
+}
+
+/* Log an error saving the log entries, written to the log files only */
+func log(
+	configData *types.Config,
+	sessionData *types.Session,
+	message string) {
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "DebugLevel",
+	}.Do()
+
+}
@@ -0,0 +1,55 @@
+package logviewer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestComponent(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{name: "function", message: "github.com/aleibovici/cryptopump/exchange.BuyOrder - insufficient balance", want: "exchange"},
+		{name: "method", message: "github.com/aleibovici/cryptopump/threads.Thread.Lock - locked", want: "threads"},
+		{name: "info message", message: "BUY", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Component(tt.message); got != tt.want {
+				t.Errorf("Component() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2021, 12, 1, 10, 15, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		entry logger.LogEntry
+		want  types.LogRecord
+	}{
+		{
+			name:  "info",
+			entry: logger.LogEntry{Session: &types.Session{ThreadID: "c683ok5mk1u1120gnmmg"}, Message: "BUY", LogLevel: "InfoLevel"},
+			want:  types.LogRecord{Time: 1638353700000, Level: "info", ThreadID: "c683ok5mk1u1120gnmmg", Message: "BUY"},
+		},
+		{
+			name:  "debug without session",
+			entry: logger.LogEntry{Message: "github.com/aleibovici/cryptopump/mysql.SaveSession - timeout", LogLevel: "DebugLevel"},
+			want:  types.LogRecord{Time: 1638353700000, Level: "debug", Component: "mysql", Message: "github.com/aleibovici/cryptopump/mysql.SaveSession - timeout"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Record(tt.entry, now); got != tt.want {
+				t.Errorf("Record() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,27 +2,80 @@ package main
 
 import (
 	"database/sql"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/aleibovici/cryptopump/adoption"
+	"github.com/aleibovici/cryptopump/alerts"
 	"github.com/aleibovici/cryptopump/algorithms"
+	"github.com/aleibovici/cryptopump/api"
+	"github.com/aleibovici/cryptopump/approval"
+	"github.com/aleibovici/cryptopump/audit"
+	"github.com/aleibovici/cryptopump/auth"
+	"github.com/aleibovici/cryptopump/backtest"
+	"github.com/aleibovici/cryptopump/calendar"
+	"github.com/aleibovici/cryptopump/cli"
+	"github.com/aleibovici/cryptopump/commands"
+	"github.com/aleibovici/cryptopump/cycle"
+	"github.com/aleibovici/cryptopump/diagnostics"
+	"github.com/aleibovici/cryptopump/discord"
+	"github.com/aleibovici/cryptopump/email"
+	"github.com/aleibovici/cryptopump/errorburst"
+	"github.com/aleibovici/cryptopump/events"
 	"github.com/aleibovici/cryptopump/exchange"
 	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/health"
+	"github.com/aleibovici/cryptopump/heatmap"
+	"github.com/aleibovici/cryptopump/i18n"
+	"github.com/aleibovici/cryptopump/journal"
+	"github.com/aleibovici/cryptopump/labels"
+	"github.com/aleibovici/cryptopump/liquidation"
 	"github.com/aleibovici/cryptopump/loader"
 	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/logviewer"
 	"github.com/aleibovici/cryptopump/markets"
+	"github.com/aleibovici/cryptopump/matrix"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/metrics"
 	"github.com/aleibovici/cryptopump/mysql"
 	"github.com/aleibovici/cryptopump/nodes"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/outbox"
+	"github.com/aleibovici/cryptopump/perf"
 	"github.com/aleibovici/cryptopump/plotter"
+	"github.com/aleibovici/cryptopump/pnl"
+	"github.com/aleibovici/cryptopump/portfolio"
+	"github.com/aleibovici/cryptopump/preferences"
+	"github.com/aleibovici/cryptopump/presets"
+	"github.com/aleibovici/cryptopump/rebalancer"
+	"github.com/aleibovici/cryptopump/reload"
+	"github.com/aleibovici/cryptopump/report"
+	"github.com/aleibovici/cryptopump/risk"
+	"github.com/aleibovici/cryptopump/scheduler"
+	"github.com/aleibovici/cryptopump/sentry"
+	"github.com/aleibovici/cryptopump/settings"
+	"github.com/aleibovici/cryptopump/shutdown"
+	"github.com/aleibovici/cryptopump/slack"
+	"github.com/aleibovici/cryptopump/snapshot"
+	"github.com/aleibovici/cryptopump/summary"
+	"github.com/aleibovici/cryptopump/supervisor"
 	"github.com/aleibovici/cryptopump/telegram"
 	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/tracing"
 	"github.com/aleibovici/cryptopump/types"
-	"github.com/jtaczanowski/go-scheduler"
+	"github.com/aleibovici/cryptopump/watchdog"
+	"github.com/aleibovici/cryptopump/webhooks"
+	"github.com/aleibovici/cryptopump/wsstats"
 	"github.com/paulbellamy/ratecounter"
 	"github.com/sdcoffey/techan"
 	"github.com/skratchdot/open-golang/open"
@@ -31,15 +84,80 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
+var (
+	starting  sync.Mutex /* Resume selection and lock of the starting threads */
+	observing sync.Once  /* Log entries observers of the process */
+)
+
 type myHandler struct {
 	sessionData *types.Session
 	marketData  *types.Market
-	configData  *types.Config
 	viperData   *types.ViperData
+	api         *api.Handler    /* REST API of the thread of the web UI */
+	metrics     *api.Metrics    /* Prometheus metrics of the thread of the web UI */
+	health      *health.Handler /* Probes of the thread of the web UI */
 }
 
 func main() {
 
+	liquidate := flag.Bool("liquidate", false, "Cancel all open orders and sell all holdings across all threads")                          /* Emergency liquidation from the command line */
+	verifyAudit := flag.Bool("verifyaudit", false, "Verify the hash chain of the order audit trail")                                       /* Audit trail verification from the command line */
+	debugAddress := flag.String("debug", "", "Start the pprof debug server at address, a port alone binds to localhost (i.e. 6060)")       /* Opt-in debug server */
+	grpcAddress := flag.String("grpc", "", "Start the gRPC control plane at address, a port alone binds to localhost (i.e. 9090)")         /* Opt-in gRPC control plane */
+	resume := flag.String(threads.ResumeFlag, "", "Resume the comma-separated ThreadIDs at startup, used by the watchdog restart")         /* Threads resumed by a restarted process */
+	symbols := flag.String(threads.SymbolsFlag, "", "Run a thread for each comma-separated symbol in this process (i.e. BTCUSDT,ETHUSDT)") /* Threads of the process */
+
+	/* Subcommand of the command line, i.e. ./cryptopump threads list. No subcommand runs the bot. */
+	command, args, err := cli.Parse(os.Args[1:])
+	if err != nil {
+
+		fmt.Fprintln(os.Stderr, err.Error())
+		cli.Usage(os.Stderr)
+		os.Exit(2)
+
+	}
+
+	if command == cli.Help {
+
+		cli.Usage(os.Stdout)
+		os.Exit(0)
+
+	}
+
+	flag.Usage = func() {
+		cli.Usage(flag.CommandLine.Output())
+		fmt.Fprintln(flag.CommandLine.Output(), "\nFlags of run:")
+		flag.PrintDefaults()
+	}
+
+	if command == cli.Run {
+
+		_ = flag.CommandLine.Parse(args) /* Exits on error */
+
+	}
+
+	notify.Register(messages.Telegram, telegram.Notification) /* Telegram can't be imported by notify */
+	notify.Observe(sentry.Notification)                       /* Report the critical notifications to Sentry */
+
+	exchange.RegisterBuyCheck(risk.CheckThreadExposure) /* Risk limits of every buy, risk can't be imported by exchange */
+	exchange.RegisterBuyCheck(risk.CheckReservation)
+	exchange.RegisterBuyCheck(risk.CheckFiatReserve)
+	exchange.RegisterBuyCheck(risk.CheckHalt)
+	exchange.RegisterBuyCheck(risk.CheckDailyLoss)
+	exchange.RegisterBuyCheck(risk.CheckSymbol)
+
+	/* Subscribers of the event bus, in delivery order */
+	events.Subscribe(metrics.Handle, events.OrderPlaced, events.OrderFilled, events.OrderFailed)
+	events.Subscribe(notify.Handle, events.OrderFilled, events.ProfitRealized, events.StoplossTriggered, events.ErrorRaised)
+	events.Subscribe(webhooks.Handle, events.OrderPlaced, events.OrderFilled, events.StoplossTriggered, events.SessionStarted, events.SessionStopped, events.ErrorRaised)
+
+	snapshot.Observe() /* Keep the last errors for the state snapshot */
+
+	outbox.Register(messages.Telegram, telegram.Deliver) /* Retry of the notifications that failed to deliver */
+	outbox.Register(messages.Discord, discord.Deliver)
+	outbox.Register(messages.Slack, slack.Deliver)
+	outbox.Register(messages.Matrix, matrix.Deliver)
+
 	viperData := &types.ViperData{ /* Viper Configuration */
 		V1: viper.New(), /* Session configurations file */
 		V2: viper.New(), /* Global configurations file */
@@ -117,7 +235,6 @@ func main() {
 		QuantityOffsetFlag:      false,
 		DiffTotal:               0,
 		Global:                  &types.Global{},
-		Admin:                   false,
 		Port:                    "",
 	}
 
@@ -140,13 +257,67 @@ func main() {
 
 	sessionData.Db = mysql.DBInit() /* Initialize DB connection */
 
+	logger.Configure(functions.GetConfigData(viperData, sessionData)) /* Log file rotation and retention */
+
+	/* Run a subcommand without the web UI and exit, i.e. ./cryptopump sell -order 123456 */
+	if command != cli.Run {
+
+		if err := cli.Execute(command, args, cli.Env{
+			Config:  functions.GetConfigData(viperData, sessionData),
+			Session: sessionData,
+			Out:     os.Stdout,
+		}); err != nil {
+
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+
+		}
+
+		os.Exit(0)
+
+	}
+
+	/* Run the emergency liquidation with two-step confirmation and exit, i.e. ./cryptopump -liquidate */
+	if *liquidate {
+
+		if err := liquidation.Command(functions.GetConfigData(viperData, sessionData), sessionData, os.Stdin, os.Stdout); err != nil {
+
+			os.Exit(1)
+
+		}
+
+		os.Exit(0)
+
+	}
+
+	/* Verify the order audit trail and exit, i.e. ./cryptopump -verifyaudit */
+	if *verifyAudit {
+
+		if err := audit.Command(sessionData, os.Stdout); err != nil {
+
+			os.Exit(1)
+
+		}
+
+		os.Exit(0)
+
+	}
+
+	/* Start the opt-in debug server, i.e. ./cryptopump -debug 6060 */
+	if address := diagnostics.Address(*debugAddress); address != "" {
+
+		diagnostics.Start(functions.GetConfigData(viperData, sessionData), sessionData, address)
+
+	}
+
 	myHandler := &myHandler{
 		sessionData: sessionData,
 		marketData:  marketData,
-		configData:  configData,
 		viperData:   viperData,
 	}
 
+	shutdown.Listen(viperData, sessionData) /* Graceful shutdown on SIGINT and SIGTERM */
+
 	sessionData.Port = functions.GetPort() /* Determine port for HTTP service. */
 
 	logger.LogEntry{ /* Log Entry */
@@ -158,10 +329,40 @@ func main() {
 		LogLevel: "InfoLevel",
 	}.Do()
 
+	myHandler.api = &api.Handler{ /* Versioned JSON REST API */
+		SessionData: sessionData,
+		MarketData:  marketData,
+		ViperData:   viperData,
+		Start: func(configData *types.Config) {
+			execution(viperData, configData, myHandler.sessionData, myHandler.marketData) /* Start the execution process */
+		},
+		Clone: myHandler.clone, /* Start threads with the configuration of a thread */
+	}
+	myHandler.metrics = &api.Metrics{SessionData: sessionData}
+	myHandler.health = &health.Handler{SessionData: sessionData, ViperData: viperData}
+
+	/* Start the opt-in gRPC control plane on the REST API handler, i.e. ./cryptopump -grpc 9090 */
+	if address := diagnostics.Address(*grpcAddress); address != "" {
+
+		api.StartControlPlane(configData, myHandler.api, address)
+
+	}
+
 	http.HandleFunc("/", myHandler.handler)
+	http.Handle(api.Prefix, myHandler.api)
+	http.Handle(api.MetricsPath, myHandler.metrics) /* Prometheus metrics */
+	http.Handle(health.LivePath, myHandler.health)  /* Liveness probe */
+	http.Handle(health.ReadyPath, myHandler.health) /* Readiness probe */
 	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
 
-	open.Run("http://localhost:" + sessionData.Port) /* Open URI using the OS's default browser */
+	supervisor.Register(myHandler.restart) /* Threads failed are started again by the supervisor */
+	scheduler.Register(myHandler.restart)  /* Threads stopped by their schedule are started again by the scheduler */
+
+	if !startThreads(viperData, sessionData, marketData, *resume, *symbols) { /* Threads of the flags start without opening the browser */
+
+		open.Run("http://localhost:" + sessionData.Port) /* Open URI using the OS's default browser */
+
+	}
 
 	http.ListenAndServe(fmt.Sprintf(":%s", sessionData.Port), nil) /* Start HTTP service. */
 
@@ -174,7 +375,29 @@ func (fh *myHandler) handler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Strict-Transport-Security", "max-age=63072000; includeSubDomains") /* Add Strict-Transport-Security header */
 	w.Header().Add("X-Frame-Options", "DENY")                                          /* Add X-Frame-Options header */
 
-	fh.configData = functions.GetConfigData(fh.viperData, fh.sessionData) /* Get configuration data */
+	configData := functions.GetConfigData(fh.viperData, fh.sessionData) /* Get configuration data of the request */
+	logger.Configure(configData)                                        /* Apply log rotation changes saved in the Admin page */
+
+	var user types.AuthToken
+	var err error
+
+	if user, err = auth.SessionUser(configData, fh.sessionData, r); err != nil { /* Require an authenticated UI session */
+
+		fh.login(w, r, configData)
+		return
+
+	}
+
+	if !auth.HasCSRFCookie(r) { /* Sessions created before CSRF protection receive their CSRF token cookie */
+
+		auth.SetCSRFCookie(w, r, auth.SessionToken(r))
+
+	}
+
+	setUser(configData, user)                                              /* Load user and role permissions for html population */
+	configData.Preference = preferences.Get(fh.sessionData, user.Username) /* Load UI preferences of the user */
+
+	configData.Preference.Locale = i18n.Negotiate(configData.Preference.Language, r.Header.Get("Accept-Language")) /* UI locale of the user */
 
 	switch r.Method {
 	case "GET":
@@ -183,20 +406,51 @@ func (fh *myHandler) handler(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/":
 
-			fh.configData.HTMLSnippet = plotter.Data{}.Plot(fh.sessionData) /* Load dynamic components in configData */
-			functions.ExecuteTemplate(w, fh.configData, fh.sessionData)     /* This is the template execution for 'index' */
+			configData.HTMLSnippet = plotter.Data{}.Plot(configData, fh.sessionData)  /* Load dynamic components in configData */
+			configData.EquityRange = plotter.EquityRange(r.URL.Query().Get("equity")) /* Equity curve time range */
+			configData.EquitySnippet = plotter.Data{}.PlotEquity(fh.sessionData, configData.EquityRange)
+			configData.PresetList, _ = mysql.GetPresetNames(fh.sessionData) /* Strategy presets */
 
-		case "/sessiondata":
+			if fh.sessionData.ThreadID != "" { /* Load manual sale pending confirmation */
 
-			var tmp []byte
-			var err error
+				configData.PendingAction, _ = approval.GetPending(fh.sessionData)
 
-			w.Header().Set("Content-Type", "application/json") /* Set the Content-Type header */
+			}
+
+			functions.ExecuteTemplate(w, configData, fh.sessionData) /* This is the template execution for 'index' */
+
+		case "/thread":
+
+			fh.thread(w, r, configData, "")
+
+		case "/config":
+
+			editor := settings.LoadEditor(configData, fh.sessionData, fh.viperData.V1.GetStringMap("config"), nil, nil)
+			editor.Theme = configData.Preference.Theme
+
+			if r.URL.Query().Get("saved") != "" {
+
+				editor.Message = "Configuration saved, running threads apply it within 10 seconds"
+
+			}
+
+			functions.ExecuteConfigTemplate(w, editor) /* This is the template execution for 'config' */
+
+		case "/portfolio":
+
+			currency := r.URL.Query().Get("currency") /* Valuation currency, defaults to the currency of the user preferences */
+
+			if currency == "" {
+
+				currency = configData.Preference.Currency
 
-			if tmp, err = loader.LoadSessionDataAdditionalComponents(fh.sessionData, fh.marketData, fh.configData); err != nil { /* Load dynamic components for javascript autoloader for html output */
+			}
+
+			overview, err := portfolio.Load(fh.sessionData, currency)
+			if err != nil {
 
 				logger.LogEntry{ /* Log Entry */
-					Config:   fh.configData,
+					Config:   configData,
 					Market:   fh.marketData,
 					Session:  fh.sessionData,
 					Order:    &types.Order{},
@@ -204,14 +458,60 @@ func (fh *myHandler) handler(w http.ResponseWriter, r *http.Request) {
 					LogLevel: "DebugLevel",
 				}.Do()
 
-				return
+			}
+
+			overview.Theme = configData.Preference.Theme
+			functions.ExecutePortfolioTemplate(w, overview) /* This is the template execution for 'portfolio' */
+
+		case "/orders":
+
+			page, err := loader.LoadOrders(fh.sessionData, r.URL.Query())
+			if err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   fh.marketData,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+				page.Message = err.Error()
 
 			}
 
-			if _, err := w.Write(tmp); err != nil { /* Write writes the data to the connection as part of an HTTP reply. */
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteOrdersTemplate(w, page) /* This is the template execution for 'orders' */
+
+		case "/timeline":
+
+			page, err := loader.LoadTimeline(fh.sessionData, r.URL.Query())
+			if err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   fh.marketData,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+				page.Message = err.Error()
+
+			}
+
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteTimelineTemplate(w, page) /* This is the template execution for 'timeline' */
+
+		case "/journal":
+
+			notes, err := journal.Load(fh.sessionData, r.URL.Query().Get("tag"))
+			if err != nil {
 
 				logger.LogEntry{ /* Log Entry */
-					Config:   fh.configData,
+					Config:   configData,
 					Market:   fh.marketData,
 					Session:  fh.sessionData,
 					Order:    &types.Order{},
@@ -219,23 +519,170 @@ func (fh *myHandler) handler(w http.ResponseWriter, r *http.Request) {
 					LogLevel: "DebugLevel",
 				}.Do()
 
+			}
+
+			notes.OrderID = r.URL.Query().Get("orderID") /* Prefill the note form from the thread detail page */
+			notes.CanTrade = configData.CanTrade
+			notes.Theme = configData.Preference.Theme
+			functions.ExecuteJournalTemplate(w, notes) /* This is the template execution for 'journal' */
+
+		case "/logs":
+
+			query := r.URL.Query()
+
+			filter := logviewer.Filter{
+				ThreadID:  query.Get("threadID"),
+				Level:     query.Get("level"),
+				Component: query.Get("component"),
+				Text:      query.Get("text"),
+				From:      query.Get("from"),
+				To:        query.Get("to"),
+				Source:    query.Get("source"),
+			}
+
+			filter.ThreadIDs = labels.Resolve(fh.sessionData, filter.ThreadID) /* Sessions named or tagged ThreadID */
+
+			var viewer logviewer.Viewer
+
+			if filter.Source == logviewer.SourceDatabase { /* Entries of all the threads sharing the database */
+
+				viewer = logviewer.LoadDatabase(fh.sessionData, filter)
+
+			} else {
+
+				viewer = logviewer.Load(filter)
+
+			}
+
+			viewer.Database = configData.ConfigGlobal != nil && configData.ConfigGlobal.LogDatabase
+			viewer.Follow = query.Get("follow") != ""
+			viewer.Theme = configData.Preference.Theme
+			functions.ExecuteLogsTemplate(w, viewer) /* This is the template execution for 'logs' */
+
+		case "/alerts":
+
+			var message string
+
+			if r.URL.Query().Get("saved") != "" {
+
+				message = "Alert rules saved, running threads evaluate them every minute"
+
+			}
+
+			page := alerts.LoadPage(fh.sessionData, message)
+			page.CanAdmin = configData.CanAdmin
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteAlertsTemplate(w, page) /* This is the template execution for 'alerts' */
+
+		case "/webhooks":
+
+			var message string
+
+			if r.URL.Query().Get("saved") != "" {
+
+				message = "Webhooks saved"
+
+			}
+
+			page := webhooks.LoadPage(fh.sessionData, functions.StrToInt64(r.URL.Query().Get("id")), message)
+			page.CanAdmin = configData.CanAdmin
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteWebhooksTemplate(w, page) /* This is the template execution for 'webhooks' */
+
+		case "/backtests":
+
+			page := backtest.LoadPage(fh.sessionData, r.URL.Query())
+			page.CanAdmin = configData.CanAdmin
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteBacktestsTemplate(w, page) /* This is the template execution for 'backtests' */
+
+		case "/heatmap":
+
+			query := r.URL.Query()
+
+			page, err := heatmap.Load(configData, fh.sessionData, query.Get("threadID"), query.Get("from"), query.Get("to"))
+			if err != nil {
+				page.Message = err.Error()
+			}
+
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteHeatmapTemplate(w, page) /* This is the template execution for 'heatmap' */
+
+		case "/reports":
+
+			page := report.LoadPage("")
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteReportsTemplate(w, page) /* This is the template execution for 'reports' */
+
+		case "/reports/export":
+
+			query := r.URL.Query()
+
+			request, err := report.Parse(query.Get("kind"), query.Get("format"), query.Get("from"), query.Get("to")) /* Validate report request */
+			if err != nil {
+
+				page := report.LoadPage(err.Error())
+				page.Theme = configData.Preference.Theme
+				functions.ExecuteReportsTemplate(w, page) /* This is the template execution for 'reports' */
+
 				return
 
 			}
 
-		}
+			w.Header().Set("Content-Type", request.ContentType())
+			w.Header().Set("Content-Disposition", `attachment; filename="`+request.Filename()+`"`)
 
-	case "POST":
+			if err := report.Write(w, fh.sessionData, request); err != nil {
 
-		switch r.URL.Path { /* Determine the URI path to de taken */
-		case "/":
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   fh.marketData,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
 
-			/* This function reads and parse the html form */
-			if err := r.ParseForm(); err != nil {
+			}
+
+		case "/preferences":
+
+			var message string
+
+			if r.URL.Query().Get("saved") != "" {
+
+				message = "Preferences saved"
+
+			}
+
+			functions.ExecutePreferencesTemplate(w, preferences.LoadPage(configData.Preference, message)) /* This is the template execution for 'preferences' */
+
+		case "/security":
+
+			var message string
+
+			if r.URL.Query().Get("disabled") != "" {
+
+				message = "Two-factor authentication disabled"
+
+			}
+
+			page := auth.LoadSecurityPage(fh.sessionData, configData.Username, message)
+			page.Theme = configData.Preference.Theme
+			functions.ExecuteSecurityTemplate(w, page) /* This is the template execution for 'security' */
+
+		case "/sessiondata":
+
+			var tmp []byte
+			var err error
+
+			w.Header().Set("Content-Type", "application/json") /* Set the Content-Type header */
+
+			if tmp, err = loader.LoadSessionDataAdditionalComponents(fh.sessionData, fh.marketData, configData); err != nil { /* Load dynamic components for javascript autoloader for html output */
 
 				logger.LogEntry{ /* Log Entry */
-					Config:   fh.configData,
-					Market:   nil,
+					Config:   configData,
+					Market:   fh.marketData,
 					Session:  fh.sessionData,
 					Order:    &types.Order{},
 					Message:  functions.GetFunctionName() + " - " + err.Error(),
@@ -246,106 +693,1011 @@ func (fh *myHandler) handler(w http.ResponseWriter, r *http.Request) {
 
 			}
 
-			/* This function uses a hidden field 'submitselect' in each HTML template to detect the actions triggered by users.
-			HTML action must include 'document.getElementById('submitselect').value='about';this.form.submit()' */
-			switch r.PostFormValue("submitselect") {
-			case "adminEnter":
+			if _, err := w.Write(tmp); err != nil { /* Write writes the data to the connection as part of an HTTP reply. */
 
-				fh.sessionData.Admin = true                                 /* Set admin flag */
-				functions.ExecuteTemplate(w, fh.configData, fh.sessionData) /* This is the template execution for 'admin' */
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   fh.marketData,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
 
-			case "adminExit":
+				return
 
-				fh.sessionData.Admin = false                                    /* Unset admin flag */
-				functions.SaveConfigGlobalData(fh.viperData, r, fh.sessionData) /* Save global data */
-				functions.GetConfigData(fh.viperData, fh.sessionData)           /* Get Config Data */
-				functions.ExecuteTemplate(w, fh.configData, fh.sessionData)     /* This is the template execution for 'index' */
+			}
 
-			case "new":
+		case "/chart/annotations":
 
-				var path string /* Path to the executable */
-				var err error
+			w.Header().Set("Content-Type", "application/json") /* Set the Content-Type header */
 
-				/* Spawn a new process  */
-				if path, err = os.Executable(); err != nil { /* Get the path of the executable */
+			feed, err := plotter.LoadAnnotations(configData, fh.sessionData) /* Load the thread chart feed for the lightweight-charts widget */
+			if err == nil {
 
-					logger.LogEntry{ /* Log Entry */
-						Config:   fh.configData,
-						Market:   nil,
-						Session:  fh.sessionData,
-						Order:    &types.Order{},
-						Message:  functions.GetFunctionName() + " - " + err.Error(),
-						LogLevel: "DebugLevel",
-					}.Do()
+				err = json.NewEncoder(w).Encode(feed)
 
-				}
+			}
 
-				cmd := exec.Command(path) /* Spawn a new process */
-				cmd.Stdout = os.Stdout    /* Redirect stdout to os.Stdout */
-				cmd.Stderr = os.Stderr    /* Redirect stderr to os.Stderr */
+			if err != nil {
 
-				if err = cmd.Start(); err != nil { /* Start the new process */
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   fh.marketData,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+			}
+
+		case "/pnl":
+
+			w.Header().Set("Content-Type", "application/json") /* Set the Content-Type header */
+
+			ticker, err := pnl.Load(fh.sessionData, pnl.ParseMinutes(r.URL.Query().Get("minutes"))) /* Load the P&L ticker for the dashboard header */
+			if err == nil {
+
+				err = json.NewEncoder(w).Encode(ticker)
+
+			}
+
+			if err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   fh.marketData,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+			}
+
+		}
+
+	case "POST":
+
+		switch r.URL.Path { /* Determine the URI path to de taken */
+		case "/":
+
+			/* This function reads and parse the html form */
+			if err := r.ParseForm(); err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   nil,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+				return
+
+			}
+
+			/* This function uses a hidden field 'submitselect' in each HTML template to detect the actions triggered by users.
+			HTML action must include 'document.getElementById('submitselect').value='about';this.form.submit()' */
+			action := r.PostFormValue("submitselect")
+
+			if !auth.VerifyCSRF(r) { /* Reject form posts without the CSRF token of the UI session */
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   nil,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  "Invalid CSRF token for " + action + " by user " + configData.Username,
+					LogLevel: "InfoLevel",
+				}.Do()
+
+				http.Error(w, auth.ErrInvalidCSRF.Error(), http.StatusForbidden)
+				return
+
+			}
+
+			if !auth.Allowed(configData.Role, auth.ActionRole(action)) { /* Enforce the role required by the action */
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   nil,
+					Session:  fh.sessionData,
+					Order:    &types.Order{},
+					Message:  "Forbidden " + action + " for user " + configData.Username + " with role " + configData.Role,
+					LogLevel: "InfoLevel",
+				}.Do()
+
+				http.Error(w, auth.ErrForbidden.Error(), http.StatusForbidden)
+				return
+
+			}
+
+			if auth.RequiresCode(action) { /* Require the authentication code of users with two-factor authentication enabled */
+
+				if err := auth.VerifyCode(configData, fh.sessionData, configData.Username, r.PostFormValue("totpCode")); err != nil {
+
+					logger.LogEntry{ /* Log Entry */
+						Config:   configData,
+						Market:   nil,
+						Session:  fh.sessionData,
+						Order:    &types.Order{},
+						Message:  "Invalid authentication code for " + action + " by user " + configData.Username,
+						LogLevel: "InfoLevel",
+					}.Do()
+
+					http.Error(w, err.Error(), http.StatusForbidden)
+					return
+
+				}
+
+			}
+
+			switch action {
+			case "adminEnter":
+
+				selectAdmin(configData, fh.sessionData)                  /* Select the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData) /* This is the template execution for 'admin' */
+
+			case "adminExit":
+
+				functions.SaveConfigGlobalData(fh.viperData, r, fh.sessionData)                                                   /* Save global data */
+				_ = risk.SetSymbolList(configData, fh.sessionData, r.PostFormValue("SymbolAllow"), r.PostFormValue("SymbolDeny")) /* Save symbol allow/deny list */
+				functions.GetConfigData(fh.viperData, fh.sessionData)                                                             /* Get Config Data */
+				functions.ExecuteTemplate(w, configData, fh.sessionData)                                                          /* This is the template execution for 'index' */
+
+			case "login":
+
+				http.Redirect(w, r, "/", http.StatusSeeOther) /* Already authenticated, redirect to root 'index' */
+
+			case "logout":
+
+				_ = auth.Logout(fh.sessionData, auth.SessionToken(r)) /* Revoke UI session */
+				auth.ClearSessionCookie(w)                            /* Remove UI session cookie */
+				http.Redirect(w, r, "/", http.StatusSeeOther)         /* Redirect to root 'login' */
+
+			case "userSave":
+
+				_ = auth.CreateUser(configData, fh.sessionData, r.PostFormValue("userName"), r.PostFormValue("userPassword"), r.PostFormValue("userRole")) /* Add user, or reset password, role and login lockout */
+				selectAdmin(configData, fh.sessionData)                                                                                                    /* Render the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData)                                                                                   /* This is the template execution for 'admin' */
+
+			case "apiTokenCreate":
+
+				username := r.PostFormValue("userName") /* Create REST API token for the logged in user when userName is empty */
+
+				if username == "" {
+
+					username = configData.Username
+
+				}
+
+				configData.APIToken, _ = auth.CreateAPIToken(configData, fh.sessionData, username) /* Create REST API token, displayed once */
+				selectAdmin(configData, fh.sessionData)                                            /* Render the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData)                           /* This is the template execution for 'admin' */
+
+			case "apiTokenRevoke":
+
+				username := r.PostFormValue("userName") /* Revoke REST API tokens of the logged in user when userName is empty */
+
+				if username == "" {
+
+					username = configData.Username
+
+				}
+
+				_ = auth.RevokeAPITokens(configData, fh.sessionData, username) /* Revoke all REST API tokens of the user */
+				selectAdmin(configData, fh.sessionData)                        /* Render the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData)       /* This is the template execution for 'admin' */
+
+			case "drawdownResume":
+
+				_ = risk.ResumeDrawdown(configData, fh.sessionData)      /* Re-enable buys after drawdown kill switch */
+				_ = liquidation.Resume(configData, fh.sessionData)       /* Re-enable buys after emergency liquidation */
+				selectAdmin(configData, fh.sessionData)                  /* Render the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData) /* This is the template execution for 'admin' */
+
+			case "liquidateRequest":
+
+				configData.LiquidationCode, _ = liquidation.Request(fh.sessionData) /* Issue emergency liquidation confirmation code */
+				selectAdmin(configData, fh.sessionData)                             /* Render the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData)            /* This is the template execution for 'admin' */
+
+			case "liquidateConfirm":
+
+				_ = liquidation.Confirm(configData, fh.sessionData, r.PostFormValue("liquidationCode")) /* Cancel open orders and sell all holdings across all threads */
+				selectAdmin(configData, fh.sessionData)                                                 /* Render the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData)                                /* This is the template execution for 'admin' */
+
+			case "new":
+
+				var path string /* Path to the executable */
+				var err error
+
+				/* Spawn a new process  */
+				if path, err = os.Executable(); err != nil { /* Get the path of the executable */
+
+					logger.LogEntry{ /* Log Entry */
+						Config:   configData,
+						Market:   nil,
+						Session:  fh.sessionData,
+						Order:    &types.Order{},
+						Message:  functions.GetFunctionName() + " - " + err.Error(),
+						LogLevel: "DebugLevel",
+					}.Do()
+
+				}
+
+				cmd := exec.Command(path) /* Spawn a new process */
+				cmd.Stdout = os.Stdout    /* Redirect stdout to os.Stdout */
+				cmd.Stderr = os.Stderr    /* Redirect stderr to os.Stderr */
+
+				if err = cmd.Start(); err != nil { /* Start the new process */
+
+					logger.LogEntry{ /* Log error */
+						Config:   configData,
+						Market:   nil,
+						Session:  fh.sessionData,
+						Order:    &types.Order{},
+						Message:  functions.GetFunctionName() + " - " + err.Error(),
+						LogLevel: "DebugLevel",
+					}.Do()
+
+				}
+
+				functions.ExecuteTemplate(w, configData, fh.sessionData) /* This is the template execution for 'index' */
+
+			case "start":
+
+				go execution(fh.viperData, configData, fh.sessionData, fh.marketData) /* Start the execution process */
+				time.Sleep(2 * time.Second)                                           /* Sleep time to wait for ThreadID to start */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)                     /* Redirect to root 'index' */
+
+			case "stop":
+
+				threads.Thread{}.Terminate(fh.sessionData, "") /* Terminate ThreadID */
+
+			case "update":
+
+				functions.SaveConfigData(fh.viperData, r, fh.sessionData) /* Save the configuration data */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)         /* Redirect to root 'index' */
+
+			case "buy":
+
+				fh.sessionData.ForceBuy = true                    /* Force buy */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301) /* Redirect to root 'index' */
+
+			case "sell":
+
+				var orderID int64 /* Force sell most recent order when orderID is empty */
+
+				if r.PostFormValue("orderID") != "" { /* Check if the orderID is empty */
+
+					orderID = functions.StrToInt64(r.PostFormValue("orderID")) /* Force sell a specific orderID */
+
+				}
+
+				_, _ = approval.ForceSell(configData, fh.marketData, fh.sessionData, orderID) /* Force sell, or request confirmation above SellConfirmNotional */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)                             /* Redirect to root 'index' */
+
+			case "sellConfirm":
+
+				_ = approval.Approve(configData, fh.sessionData, functions.StrToInt64(r.PostFormValue("pendingActionID"))) /* Execute pending manual sale */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)                                                          /* Redirect to root 'index' */
+
+			case "sellReject":
+
+				_ = approval.Reject(configData, fh.sessionData, functions.StrToInt64(r.PostFormValue("pendingActionID"))) /* Cancel pending manual sale */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)                                                         /* Redirect to root 'index' */
+
+			case "pause", "resume":
+
+				if fh.sessionData.ThreadID != "" {
+
+					_ = threads.Thread{}.Pause(fh.sessionData, action == "pause", configData.Username) /* Pause or resume new buys, exits are still managed */
+
+				}
+
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301) /* Redirect to root 'index' */
+
+			case "stopPrice":
+
+				fh.sessionData.StopPrice = functions.StrToFloat64(r.PostFormValue("stopPrice")) /* Set absolute stop price, 0 disables */
+				_ = mysql.UpdateSessionStopPrice(fh.sessionData)                                /* Persist stop price across restarts */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)                               /* Redirect to root 'index' */
+
+			case "manualOrder":
+
+				side, quantity, price, err := exchange.ParseManualOrder(r.PostFormValue("manualSide"), r.PostFormValue("manualQuantity"), r.PostFormValue("manualPrice"), fh.sessionData.StepSize) /* Validate manual order */
+
+				if err == nil && fh.sessionData.ThreadID == "" {
+
+					err = api.ErrNotRunning
+
+				}
+
+				if err == nil {
+
+					_, err = exchange.ManualTicker(side, quantity, price, configData.Username, configData, fh.marketData, fh.sessionData) /* Place manual order recorded with the operator source */
+
+				}
+
+				if err != nil {
+
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+
+				}
+
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301) /* Redirect to root 'index' */
+
+			case "reservation":
+
+				_ = risk.SetReservation(configData, fh.sessionData, functions.StrToFloat64(r.PostFormValue("reservation"))) /* Reserve fiat funds for ThreadID, 0 releases */
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)                                                           /* Redirect to root 'index' */
+
+			case "reservationRebalance":
+
+				_ = risk.RebalanceReservations(configData, fh.sessionData) /* Split fiat funds equally between threads */
+				selectAdmin(configData, fh.sessionData)                    /* Render the admin page */
+				functions.ExecuteTemplate(w, configData, fh.sessionData)   /* This is the template execution for 'admin' */
+
+			case "configSave":
+
+				current := fh.viperData.V1.GetStringMap("config")
+				submitted := make(map[string]string)
+
+				for _, parameter := range settings.Parameters { /* Retrieve the config editor values */
+
+					if _, ok := r.PostForm[parameter.Key]; ok {
+
+						submitted[parameter.Key] = r.PostFormValue(parameter.Key)
+
+					}
+
+				}
+
+				changes, errs := settings.Validate(current, submitted, fh.sessionData.ThreadID != "") /* Validate ranges and conflicting values */
+
+				if len(errs) == 0 {
+
+					if err := settings.Save(fh.viperData, fh.sessionData, configData.Username, changes); err != nil { /* Save configuration and configuration version */
+
+						errs = append(errs, err)
+
+					}
+
+				}
+
+				if len(errs) > 0 {
+
+					editor := settings.LoadEditor(configData, fh.sessionData, current, submitted, errs)
+					editor.Theme = configData.Preference.Theme
+					functions.ExecuteConfigTemplate(w, editor) /* This is the template execution for 'config' */
+					return
+
+				}
+
+				http.Redirect(w, r, "/config?saved=1", http.StatusSeeOther) /* Redirect to 'config' */
+
+			case "preferencesSave":
+
+				preference, err := preferences.Parse(r.PostFormValue("theme"), r.PostFormValue("refreshInterval"), r.PostFormValue("currency"), r.PostFormValue("language"), r.PostForm["columns"]) /* Validate UI preferences */
+
+				if err == nil {
+
+					err = preferences.Save(fh.sessionData, configData.Username, preference) /* Save UI preferences of the user */
+
+				}
+
+				if err != nil {
+
+					functions.ExecutePreferencesTemplate(w, preferences.LoadPage(configData.Preference, err.Error())) /* This is the template execution for 'preferences' */
+					return
+
+				}
+
+				http.Redirect(w, r, "/preferences?saved=1", http.StatusSeeOther) /* Redirect to 'preferences' */
+
+			case "totpEnroll":
+
+				var message string
+
+				if err := auth.EnrollTOTP(fh.sessionData, configData.Username); err != nil { /* Save a new TOTP secret pending confirmation */
+
+					message = err.Error()
+
+				}
+
+				page := auth.LoadSecurityPage(fh.sessionData, configData.Username, message)
+				page.Theme = configData.Preference.Theme
+				functions.ExecuteSecurityTemplate(w, page) /* This is the template execution for 'security' */
+
+			case "totpEnable":
+
+				var message string
+
+				codes, err := auth.EnableTOTP(configData, fh.sessionData, configData.Username, r.PostFormValue("totpCode")) /* Enable two-factor authentication and issue recovery codes */
+
+				if err != nil {
+
+					message = err.Error()
+
+				}
+
+				page := auth.LoadSecurityPage(fh.sessionData, configData.Username, message)
+				page.RecoveryCodes = codes
+				page.Theme = configData.Preference.Theme
+				functions.ExecuteSecurityTemplate(w, page) /* This is the template execution for 'security' */
+
+			case "totpDisable":
+
+				_ = auth.DisableTOTP(configData, fh.sessionData, configData.Username) /* Disable two-factor authentication, the code was verified above */
+				http.Redirect(w, r, "/security?disabled=1", http.StatusSeeOther)      /* Redirect to 'security' */
+
+			case "threadClone":
+
+				var cloned, skipped []string
+
+				for _, clone := range fh.clone(r.PostFormValue("threadID"), []string{r.PostFormValue("symbols")}, r.PostFormValue("dryRun") == "true") { /* Start a thread for each symbol with the configuration of the thread */
+
+					if clone.Error == "" {
+						cloned = append(cloned, clone.Symbol)
+					} else {
+						skipped = append(skipped, clone.Symbol+" ("+clone.Error+")")
+					}
+
+				}
+
+				var results []string
+
+				if len(cloned) > 0 {
+					results = append(results, "Starting "+strings.Join(cloned, ", "))
+				}
+
+				if len(skipped) > 0 {
+					results = append(results, "Skipped "+strings.Join(skipped, ", "))
+				}
+
+				message := strings.Join(results, ". ")
+				if message == "" {
+					message = "No symbols to clone"
+				}
+
+				fh.thread(w, r, configData, message) /* This is the template execution for 'thread' */
+
+			case "sessionLabel":
+
+				message := "Session label saved"
+
+				label, err := labels.Parse(r.PostFormValue("threadID"), r.PostFormValue("name"), r.PostFormValue("tags")) /* Validate the session name and tags */
+				if err == nil {
+					err = labels.Save(fh.sessionData, configData.Username, label)
+				}
+
+				if err != nil {
+					message = err.Error()
+				}
+
+				fh.thread(w, r, configData, message) /* This is the template execution for 'thread' */
+
+			case "adopt":
+
+				message := "Position adopted"
+
+				quantity, costBasis, err := adoption.Parse(r.PostFormValue("adoptQuantity"), r.PostFormValue("adoptCostBasis")) /* Validate the quantity and cost basis */
+				if err == nil {
+					_, err = adoption.Adopt(configData, fh.sessionData, fh.marketData, configData.Username, quantity, costBasis) /* Import orphaned holdings as an open transaction */
+				}
+
+				if err != nil {
+					message = err.Error()
+				}
+
+				fh.thread(w, r, configData, message) /* This is the template execution for 'thread' */
+
+			case "release":
+
+				message := "Transaction released"
+
+				if err := adoption.Release(configData, fh.sessionData, fh.marketData, configData.Username, functions.StrToInt64(r.PostFormValue("orderID"))); err != nil { /* Remove an open transaction not backed by holdings */
+					message = err.Error()
+				}
+
+				fh.thread(w, r, configData, message) /* This is the template execution for 'thread' */
+
+			case "noteSave":
+
+				if err := journal.Save(fh.sessionData, configData.Username, fh.sessionData.ThreadID, r.PostFormValue("orderID"), r.PostFormValue("tags"), r.PostFormValue("text")); err != nil { /* Attach an operator note to an order or to the session */
+
+					notes, _ := journal.Load(fh.sessionData, "")
+					notes.OrderID = r.PostFormValue("orderID")
+					notes.CanTrade = configData.CanTrade
+					notes.Theme = configData.Preference.Theme
+					notes.Message = err.Error()
+					functions.ExecuteJournalTemplate(w, notes) /* This is the template execution for 'journal' */
+					return
+
+				}
+
+				http.Redirect(w, r, "/journal", http.StatusSeeOther) /* Redirect to 'journal' */
+
+			case "alertSave":
+
+				rule, err := alerts.Parse(r.PostFormValue("name"), r.PostFormValue("threadID"), r.PostFormValue("metric"), r.PostFormValue("operator"), r.PostFormValue("threshold"), r.PostFormValue("channel"), r.PostFormValue("target")) /* Validate alert rule */
+
+				if err == nil {
+
+					err = mysql.SaveAlertRule(fh.sessionData, rule)
+
+				}
+
+				if err != nil {
+
+					page := alerts.LoadPage(fh.sessionData, err.Error())
+					page.CanAdmin = configData.CanAdmin
+					page.Theme = configData.Preference.Theme
+					functions.ExecuteAlertsTemplate(w, page) /* This is the template execution for 'alerts' */
+					return
+
+				}
+
+				http.Redirect(w, r, "/alerts?saved=1", http.StatusSeeOther) /* Redirect to 'alerts' */
+
+			case "alertDelete":
+
+				_ = mysql.DeleteAlertRule(fh.sessionData, functions.StrToInt64(r.PostFormValue("alertID"))) /* Delete alert rule */
+				http.Redirect(w, r, "/alerts?saved=1", http.StatusSeeOther)                                 /* Redirect to 'alerts' */
+
+			case "webhookSave":
+
+				webhook, err := webhooks.Parse(functions.StrToInt64(r.PostFormValue("webhookID")), r.PostFormValue("name"), r.PostFormValue("url"), r.PostForm["events"], r.PostFormValue("secret"), r.PostFormValue("enabled") != "") /* Validate webhook */
+
+				if err == nil {
+
+					err = mysql.SaveWebhook(fh.sessionData, webhook)
+
+				}
+
+				if err != nil {
+
+					page := webhooks.LoadPage(fh.sessionData, webhook.ID, err.Error())
+					page.Edit = webhook /* Keep the values entered */
+					page.CanAdmin = configData.CanAdmin
+					page.Theme = configData.Preference.Theme
+					functions.ExecuteWebhooksTemplate(w, page) /* This is the template execution for 'webhooks' */
+					return
+
+				}
+
+				http.Redirect(w, r, "/webhooks?saved=1", http.StatusSeeOther) /* Redirect to 'webhooks' */
+
+			case "webhookDelete":
+
+				_ = mysql.DeleteWebhook(fh.sessionData, functions.StrToInt64(r.PostFormValue("webhookID"))) /* Delete webhook */
+				http.Redirect(w, r, "/webhooks?saved=1", http.StatusSeeOther)                               /* Redirect to 'webhooks' */
+
+			case "webhookTest":
+
+				message := "Test event sent"
+
+				if err := webhooks.Test(fh.sessionData, functions.StrToInt64(r.PostFormValue("webhookID"))); err != nil { /* Send a signed test event */
+
+					message = err.Error()
+
+				}
+
+				page := webhooks.LoadPage(fh.sessionData, 0, message)
+				page.CanAdmin = configData.CanAdmin
+				page.Theme = configData.Preference.Theme
+				functions.ExecuteWebhooksTemplate(w, page) /* This is the template execution for 'webhooks' */
+
+			case "backtestDelete":
+
+				_ = mysql.DeleteBacktest(fh.sessionData, functions.StrToInt64(r.PostFormValue("backtestID"))) /* Delete backtest run */
+				http.Redirect(w, r, "/backtests", http.StatusSeeOther)                                        /* Redirect to 'backtests' */
+
+			case "presetSave":
+
+				if err := presets.Save(fh.viperData, fh.sessionData, configData.Username, r.PostFormValue("presetName")); err != nil { /* Save the configuration as a named preset */
+
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+
+				}
+
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301) /* Redirect to root 'index' */
+
+			case "presetLoad":
+
+				if err := presets.Load(fh.viperData, fh.sessionData, configData.Username, r.PostFormValue("presetList")); err != nil { /* Apply a preset to the configuration of the thread to be started */
+
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+
+				}
+
+				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301) /* Redirect to root 'index' */
+
+			case "configTemplate":
+
+				fh.sessionData.ConfigTemplate = functions.StrToInt(r.PostFormValue("configTemplateList")) /* Retrieve Configuration Template Key selection */
+				templateData := functions.LoadConfigTemplate(fh.viperData, fh.sessionData)                /* Load the configuration data */
+				setUser(templateData, user)                                                               /* Load user and role permissions for html population */
+				templateData.Preference = configData.Preference                                           /* Load UI preferences of the user */
+				functions.ExecuteTemplate(w, templateData, fh.sessionData)                                /* This is the template execution for 'index' */
+
+			}
+		}
+
+	}
+
+}
+
+/* Authenticate the UI user, creating the first user when no users exist */
+func (fh *myHandler) login(w http.ResponseWriter, r *http.Request, configData *types.Config) {
+
+	if r.URL.Path != "/" { /* Only the root 'login' is available without authentication */
+
+		http.Error(w, auth.ErrUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+
+	}
+
+	configData.LoginSetup, _ = auth.Setup(fh.sessionData)                              /* No users exist */
+	configData.Preference.Locale = i18n.Negotiate("", r.Header.Get("Accept-Language")) /* Browser language before login */
+
+	if r.Method == "POST" && r.PostFormValue("submitselect") == "login" {
+
+		var token string
+		var err error
+
+		username := r.PostFormValue("username")
+		password := r.PostFormValue("password")
+
+		if configData.LoginSetup {
+
+			if password != r.PostFormValue("passwordConfirm") {
+
+				err = auth.ErrPasswordMismatch
+
+			} else {
+
+				err = auth.CreateUser(configData, fh.sessionData, username, password, auth.RoleAdmin) /* Create the first user as admin */
+
+			}
+
+		}
+
+		if err == nil {
+
+			if token, err = auth.Login(configData, fh.sessionData, username, password, r.PostFormValue("code")); err == nil {
+
+				auth.SetSessionCookie(w, r, token)            /* Set UI session cookie */
+				http.Redirect(w, r, "/", http.StatusSeeOther) /* Redirect to root 'index' */
+				return
+
+			}
+
+		}
+
+		configData.LoginMessage = err.Error()
+
+	}
+
+	functions.ExecuteLoginTemplate(w, configData) /* This is the template execution for 'login' */
+
+}
+
+/* Select the admin page in configData and load the symbol allow/deny list for admin.html population */
+func selectAdmin(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	configData.Admin = true
+	configData.SymbolAllow, configData.SymbolDeny, _ = risk.GetSymbolList(sessionData)
+
+}
+
+/* Load the authenticated user and role permissions in configData for html population */
+func setUser(
+	configData *types.Config,
+	user types.AuthToken) {
+
+	configData.Username = user.Username
+	configData.Role = user.Role
+	configData.CanTrade = auth.Allowed(user.Role, auth.RoleTrader)
+	configData.CanAdmin = auth.Allowed(user.Role, auth.RoleAdmin)
+
+}
+
+/* Start the threads of the -resume or -symbols flag, the first one on the session of the web UI, false when none */
+func startThreads(
+	viperData *types.ViperData,
+	sessionData *types.Session,
+	marketData *types.Market,
+	resume string,
+	symbols string) bool {
+
+	var starts []*types.Session /* ThreadID resumed or Symbol of each thread */
+
+	for _, threadID := range splitList(resume) {
+
+		starts = append(starts, &types.Session{ThreadID: threadID})
+
+	}
+
+	if len(starts) == 0 {
+
+		for _, symbol := range splitList(strings.ToUpper(symbols)) {
+
+			starts = append(starts, &types.Session{Symbol: symbol})
+
+		}
+
+	}
+
+	started := 0
+
+	for _, start := range starts {
+
+		threadViperData, threadSessionData, threadMarketData := viperData, sessionData, marketData
+
+		if started > 0 {
+
+			threadViperData, threadSessionData, threadMarketData = newThread(viperData, sessionData)
+
+		}
+
+		if startThread(threadViperData, threadSessionData, threadMarketData, start.ThreadID, start.Symbol, false) {
+
+			started++
+
+		}
+
+	}
+
+	return started > 0
+
+}
+
+/* Start a thread resuming threadID, or of symbol when not empty, as a new thread with newSession, false when the symbol is not valid */
+func startThread(
+	viperData *types.ViperData,
+	sessionData *types.Session,
+	marketData *types.Market,
+	threadID string,
+	symbol string,
+	newSession bool) bool {
+
+	if symbol != "" { /* Symbol of the thread overrides the session configurations */
+
+		symbolFiat, err := algorithms.ParseSymbolFiat(&types.Session{Symbol: symbol})
+		if err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  nil,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + symbol + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+			return false
+
+		}
+
+		viperData.V1.Set("config.symbol", symbol)
+		viperData.V1.Set("config.symbol_fiat", symbolFiat)
+
+	}
+
+	sessionData.ThreadID = threadID
+	sessionData.Symbol = symbol
+
+	configData := functions.GetConfigData(viperData, sessionData)
+	configData.NewSession = configData.NewSession || newSession /* Not saved in the session configurations */
+
+	go execution(viperData, configData, sessionData, marketData) /* Start the execution process */
+
+	return true
+
+}
+
+/* Start a new thread for each symbol with the session configurations of threadID, of the thread of the web UI when empty, in dry-run mode when dryRun */
+func (fh *myHandler) clone(
+	threadID string,
+	symbols []string,
+	dryRun bool) (clones []threads.Clone) {
+
+	source := fh.viperData.V1.ConfigFileUsed() /* Session configurations of the web UI */
+
+	if threadID == "" {
+		threadID = fh.sessionData.ThreadID
+	}
+
+	if threadID != "" {
+		source = "./config/" + threadID + ".yml"
+	}
+
+	from := filepath.Base(source)
+	_, err := os.Stat(source)
+
+	for _, symbol := range threads.ParseSymbols(symbols) {
+
+		clone := threads.Clone{Symbol: symbol}
+
+		switch {
+		case err != nil:
+			clone.Error = from + " not found"
+		case threads.Running(symbol):
+			clone.Error = "already running in this process"
+		case !risk.IsSymbolAllowed(fh.sessionData, symbol):
+			clone.Error = "denied by symbol list"
+		default:
+
+			viperData, sessionData, marketData := newThread(fh.viperData, fh.sessionData)
+			viperData.V1.SetConfigFile(source)
+
+			if err := viperData.V1.ReadInConfig(); err != nil {
+
+				clone.Error = err.Error()
+
+			} else if dryRun { /* Trial the symbol with simulated orders alongside the live threads */
+
+				viperData.V1.Set("config.dryrun", true)
+
+			}
+
+			if clone.Error == "" && !startThread(viperData, sessionData, marketData, "", symbol, true) {
+
+				clone.Error = "invalid symbol"
+
+			}
+
+		}
+
+		clones = append(clones, clone)
+
+		message := "Configuration " + from + " cloned to " + symbol
+		if dryRun {
+			message += " in dry-run mode"
+		}
+		if clone.Error != "" {
+			message += " failed - " + clone.Error
+		}
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  fh.sessionData,
+			Order:    &types.Order{},
+			Message:  message,
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+	return clones
+
+}
+
+/* Execute the thread detail template with a message of the last action */
+func (fh *myHandler) thread(
+	w http.ResponseWriter,
+	r *http.Request,
+	configData *types.Config,
+	message string) {
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page")) /* Closed cycles page, defaults to the first page */
+
+	detail, err := loader.LoadThreadDetail(configData, fh.sessionData, fh.marketData, fh.viperData.V1.GetStringMap("config"), page)
+	if err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   fh.marketData,
+			Session:  fh.sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	detail.Theme = configData.Preference.Theme
+	detail.CanTrade = configData.CanTrade
+	detail.Message = message
+	functions.ExecuteThreadTemplate(w, detail) /* This is the template execution for 'thread' */
+
+}
+
+/* Start a thread failed under the supervisor again, the thread of the web UI on the handlers of the port */
+func (fh *myHandler) restart(failed *types.Session) {
+
+	viperData, sessionData, marketData := newThread(fh.viperData, failed)
 
-					logger.LogEntry{ /* Log error */
-						Config:   fh.configData,
-						Market:   nil,
-						Session:  fh.sessionData,
-						Order:    &types.Order{},
-						Message:  functions.GetFunctionName() + " - " + err.Error(),
-						LogLevel: "DebugLevel",
-					}.Do()
+	if failed == fh.sessionData { /* Thread of the web UI, on its session configurations */
 
-				}
+		viperData = fh.viperData
 
-				functions.ExecuteTemplate(w, fh.configData, fh.sessionData) /* This is the template execution for 'index' */
+		fh.sessionData, fh.marketData = sessionData, marketData
+		fh.api.SessionData, fh.api.MarketData = sessionData, marketData
+		fh.metrics.SessionData = sessionData
+		fh.health.SessionData = sessionData
 
-			case "start":
+	}
 
-				go execution(fh.viperData, fh.configData, fh.sessionData, fh.marketData) /* Start the execution process */
-				time.Sleep(2 * time.Second)                                              /* Sleep time to wait for ThreadID to start */
-				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)                        /* Redirect to root 'index' */
+	symbol := ""
+	if failed.ThreadID == "" { /* Thread of the -symbols flag failed before its ThreadID */
+		symbol = failed.Symbol
+	}
 
-			case "stop":
+	startThread(viperData, sessionData, marketData, failed.ThreadID, symbol, false)
 
-				threads.Thread{}.Terminate(fh.sessionData, "") /* Terminate ThreadID */
+}
 
-			case "update":
+/* Return the viper, session and market data of an additional thread, sharing the global configurations */
+func newThread(
+	viperData *types.ViperData,
+	sessionData *types.Session) (*types.ViperData, *types.Session, *types.Market) {
 
-				functions.SaveConfigData(fh.viperData, r, fh.sessionData) /* Save the configuration data */
-				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301)         /* Redirect to root 'index' */
+	threadViperData := &types.ViperData{
+		V1: viper.New(), /* Session configurations file */
+		V2: viperData.V2,
+	}
 
-			case "buy":
+	threadViperData.V1.SetConfigType("yml")      /* Set the type of the configurations file */
+	threadViperData.V1.AddConfigPath("./config") /* Set the path to look for the configurations file */
+	threadViperData.V1.SetConfigName("config")   /* Set the file name of the configurations file */
+	if err := threadViperData.V1.ReadInConfig(); err != nil {
 
-				fh.sessionData.ForceBuy = true                    /* Force buy */
-				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301) /* Redirect to root 'index' */
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  nil,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
 
-			case "sell":
+	}
 
-				if r.PostFormValue("orderID") == "" { /* Check if the orderID is empty */
+	threadSessionData := &types.Session{
+		TgBotAPI:    &tgbotapi.BotAPI{},
+		Db:          sessionData.Db, /* Connection pool of the process */
+		KlineData:   []types.KlineData{},
+		RateCounter: ratecounter.NewRateCounter(5 * time.Second),
+		Global:      &types.Global{},
+		Port:        sessionData.Port,
+	}
 
-					fh.sessionData.ForceSellOrderID = 0 /* Force sell most recent order */
-					fh.sessionData.ForceSell = true     /* Force sell */
+	threadMarketData := &types.Market{
+		Series: &techan.TimeSeries{},
+	}
 
-				} else {
+	return threadViperData, threadSessionData, threadMarketData
 
-					fh.sessionData.ForceSellOrderID = functions.StrToInt64(r.PostFormValue("orderID")) /* Force sell a specific orderID */
-					fh.sessionData.ForceSell = true                                                    /* Force sell */
+}
 
-				}
+/* Split a comma-separated list, without the empty items */
+func splitList(list string) (items []string) {
 
-				http.Redirect(w, r, fmt.Sprintf(r.URL.Path), 301) /* Redirect to root 'index' */
+	for _, item := range strings.Split(list, ",") {
 
-			case "configTemplate":
+		if item = strings.TrimSpace(item); item != "" {
 
-				fh.sessionData.ConfigTemplate = functions.StrToInt(r.PostFormValue("configTemplateList")) /* Retrieve Configuration Template Key selection */
-				configData := functions.LoadConfigTemplate(fh.viperData, fh.sessionData)                  /* Load the configuration data */
-				functions.ExecuteTemplate(w, configData, fh.sessionData)                                  /* This is the template execution for 'index' */
+			items = append(items, item)
 
-			}
 		}
 
 	}
 
+	return items
+
 }
 
 func execution(
@@ -354,6 +1706,8 @@ func execution(
 	sessionData *types.Session,
 	marketData *types.Market) {
 
+	defer supervisor.Recover(configData, sessionData) /* Restart the thread on the panics of the execution process */
+
 	var err error /* Error handling */
 
 	/* Connect to Exchange */
@@ -366,15 +1720,44 @@ func execution(
 	/* Routine to resume operations */
 	var threadIDSessionDB string
 
-	if sessionData.ThreadID, threadIDSessionDB, err = mysql.GetThreadTransactionDistinct(sessionData); err != nil { /* GetThreadTransactionDistinct returns an error if the connection to the database is not successful */
+	starting.Lock() /* Threads starting together don't resume the same ThreadID */
+
+	if sessionData.ThreadID != "" { /* ThreadID of the -resume flag, with its ThreadIDSession from the Session table */
+
+		sessions, _ := mysql.GetSessions(sessionData)
+
+		for _, session := range sessions {
+
+			if session.ThreadID == sessionData.ThreadID {
+
+				threadIDSessionDB = session.ThreadIDSession
 
+			}
+
+		}
+
+	} else if sessionData.Symbol != "" { /* Symbol of the -symbols flag, resuming a thread of the symbol */
+
+		if sessionData.ThreadID, threadIDSessionDB, err = mysql.GetThreadTransactionDistinctBySymbol(sessionData); err != nil { /* GetThreadTransactionDistinctBySymbol returns an error if the connection to the database is not successful */
+
+			starting.Unlock()
+			threads.Thread{}.Terminate(sessionData, functions.GetFunctionName()+" - "+err.Error()) /* Terminate ThreadID */
+
+		}
+
+	} else if sessionData.ThreadID, threadIDSessionDB, err = mysql.GetThreadTransactionDistinct(sessionData); err != nil { /* GetThreadTransactionDistinct returns an error if the connection to the database is not successful */
+
+		starting.Unlock()
 		threads.Thread{}.Terminate(sessionData, functions.GetFunctionName()+" - "+err.Error()) /* Terminate ThreadID */
 
 	}
 
-	if sessionData.ThreadID != "" && !configData.NewSession { /* If ThreadID is not empty and NewSession is false */
+	resumed := sessionData.ThreadID != "" && !configData.NewSession
+
+	if resumed { /* If ThreadID is not empty and NewSession is false */
 
 		threads.Thread{}.Lock(sessionData) /* Lock thread file */
+		starting.Unlock()
 
 		configData = functions.GetConfigData(viperData, sessionData) /* Get Config Data */
 
@@ -384,6 +1767,12 @@ func execution(
 
 		}
 
+		if sessionData.Symbol == "" { /* Thread without orders resumed with the -resume flag */
+
+			sessionData.Symbol = configData.Symbol
+
+		}
+
 		/* Select the symbol coin to be used from sessionData.Symbol */
 		if sessionData.SymbolFiat, err = algorithms.ParseSymbolFiat(sessionData); err != nil { /* ParseSymbolFiat returns an error if the symbol is not valid */
 
@@ -391,12 +1780,49 @@ func execution(
 
 		}
 
+		/* Restore loss streak cooldown period from Session table */
+		if cooldownStart, cooldownUntil, err := mysql.GetSessionCooldown(sessionData); err == nil {
+
+			sessionData.CooldownStart = time.Unix(cooldownStart, 0)
+			sessionData.CooldownUntil = time.Unix(cooldownUntil, 0)
+
+		}
+
+		/* Restore aggregate trailing stop high-water mark from Session table */
+		if trailingHigh, err := mysql.GetSessionTrailingHigh(sessionData); err == nil {
+
+			sessionData.TrailingHigh = trailingHigh
+
+		}
+
+		/* Restore absolute stop price from Session table */
+		if stopPrice, err := mysql.GetSessionStopPrice(sessionData); err == nil {
+
+			sessionData.StopPrice = stopPrice
+
+		}
+
+		/* Restore paused state from Session table */
+		if paused, err := mysql.GetSessionPaused(sessionData); err == nil {
+
+			sessionData.Paused = paused
+
+		}
+
+		/* Restore the trade cycle state and order in flight from Session table */
+		_ = cycle.Restore(sessionData)
+
+		message := "Resuming on port " + sessionData.Port
+		if sessionData.Paused {
+			message += ", paused"
+		}
+
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
 			Market:   marketData,
 			Session:  sessionData,
 			Order:    &types.Order{},
-			Message:  "Resuming on port " + sessionData.Port,
+			Message:  message,
 			LogLevel: "InfoLevel",
 		}.Do()
 
@@ -410,6 +1836,8 @@ func execution(
 
 		}
 
+		starting.Unlock()
+
 		/* Select the symbol coin to be used from Config option */
 		sessionData.Symbol = configData.Symbol
 		sessionData.SymbolFiat = configData.SymbolFiat
@@ -425,7 +1853,25 @@ func execution(
 
 	}
 
-	asyncFunctions(viperData, configData, sessionData) /* Starts async functions that are executed at specific intervals */
+	/* Refuse to start on a symbol denied by the symbol allow/deny list */
+	if !risk.IsSymbolAllowed(sessionData, sessionData.Symbol) {
+
+		threads.Thread{}.Terminate(sessionData, sessionData.Symbol+" denied by symbol list") /* Terminate ThreadID */
+
+	}
+
+	perf.Reset(sessionData.ThreadID)    /* Cycle performance metrics of the new thread */
+	wsstats.Reset(sessionData.ThreadID) /* Websocket connection statistics of the new thread */
+
+	threads.Register(sessionData) /* Stopped with the other threads of the process */
+
+	events.Publish(configData, sessionData, events.SessionStarted, events.Session{
+		Symbol:  sessionData.Symbol,
+		Port:    sessionData.Port,
+		Resumed: resumed,
+	})
+
+	asyncFunctions(viperData, configData, sessionData, marketData) /* Starts async functions that are executed at specific intervals */
 
 	/* Retrieve available fiat funds and update database
 	This is only used for retrieving balances for the first time, and is then followed by
@@ -446,28 +1892,14 @@ func execution(
 	/* Retrieve exchange lot size for ticker and store in sessionData */
 	exchange.GetLotSize(configData, sessionData)
 
-	sum := 0
-	for {
-
-		/* Check start/stop times of operation */
-		if configData.TimeEnforce { /* If TimeEnforce is true */
-
-			for !functions.IsInTimeRange(configData.TimeStart, configData.TimeStop) { /* If current time is not in the time range */
-
-				logger.LogEntry{ /* Log Entry */
-					Config:   configData,
-					Market:   marketData,
-					Session:  sessionData,
-					Order:    &types.Order{},
-					Message:  "Sleeping",
-					LogLevel: "InfoLevel",
-				}.Do()
-
-				time.Sleep(300000 * time.Millisecond) /* Sleep for 5 minutes */
+	/* Resolve the order in flight of the trade cycle when the thread stopped, then settle the cycle */
+	_ = exchange.RecoverCycle(configData, sessionData, marketData)
 
-			}
+	/* Detect exchange holdings not tracked by thread transactions, or transactions not backed by holdings */
+	adoption.Detect(configData, sessionData, marketData)
 
-		}
+	sum := 0
+	for {
 
 		/* Update ThreadCount */
 		sessionData.ThreadCount, err = mysql.GetThreadTransactionCount(sessionData)
@@ -560,6 +1992,12 @@ func execution(
 
 		wg.Wait() /* Wait for the goroutines to finish */
 
+		if sessionData.Stopping { /* Websockets stopped by a graceful shutdown */
+
+			return
+
+		}
+
 		logger.LogEntry{ /* Log Entry */
 			Config:   configData,
 			Market:   nil,
@@ -596,24 +2034,76 @@ func execution(
 func asyncFunctions(
 	viperData *types.ViperData,
 	configData *types.Config,
-	sessionData *types.Session) {
+	sessionData *types.Session,
+	marketData *types.Market) {
 
 	/* Synchronize time with Binance every 5 minutes */
 	_ = exchange.NewSetServerTimeService(configData, sessionData)
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() { _ = exchange.NewSetServerTimeService(configData, sessionData) },
 		time.Second*300,
 		time.Second*0)
 
+	/* Observers of the log entries, once for the threads of the process */
+	observing.Do(func() {
+
+		/* Count logged errors by category and send an aggregated alert on error bursts */
+		errorburst.Observe(configData, sessionData)
+
+		/* Queue the log entries to be saved to the log table when LogDatabase is enabled */
+		logviewer.Store(configData)
+
+	})
+
+	/* Check the heartbeat of the thread for the supervisor every 10 seconds */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() { supervisor.Check(configData, sessionData, time.Now()) },
+		time.Second*10,
+		time.Second*0)
+
+	/* Stop, pause and resume the thread on its schedule every 10 seconds */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() { scheduler.Check(configData, sessionData, time.Now()) },
+		time.Second*10,
+		time.Second*0)
+
+	/* Sample the goroutines and heap every WatchdogInterval minutes, warning of leaks */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() { watchdog.Check(configData, sessionData, time.Now()) },
+		time.Second*60,
+		time.Second*0)
+
+	/* Reload the configuration when the configuration files change, and every 60 seconds when file events are not delivered */
+	reload.Watch(viperData, sessionData)
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() { _ = reload.Load(viperData, sessionData) },
+		time.Second*60,
+		time.Second*60)
+
 	/* Retrieve config data every 10 seconds. */
-	scheduler.RunTaskAtInterval(
-		func() { configData = functions.GetConfigData(viperData, sessionData) },
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			configData = reload.Config(viperData, sessionData)
+			risk.ApplyLimits(configData, sessionData)
+			errorburst.Configure(configData)
+			logger.Configure(configData)
+			logviewer.Configure(configData)
+			exchange.ConfigureStats(configData)
+			exchange.ConfigureLimiter(configData)
+		},
 		time.Second*10,
 		time.Second*0)
 
 	/* run function UpdatePendingOrders() every 180 seconds */
 	rand.Seed(time.Now().UnixNano())
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() { algorithms.UpdatePendingOrders(configData, sessionData) },
 		time.Second*180,
 		time.Second*time.Duration(rand.Intn(180-1+1)+1),
@@ -621,7 +2111,8 @@ func asyncFunctions(
 
 	/* Retrieve initial node role and then every 60 seconds */
 	nodes.Node{}.GetRole(configData, sessionData)
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() {
 			nodes.Node{}.GetRole(configData, sessionData)
 		},
@@ -629,14 +2120,16 @@ func asyncFunctions(
 		time.Second*0)
 
 	/* Keep user stream service alive every 60 seconds */
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() { _ = exchange.KeepAliveUserStreamServiceListenKey(configData, sessionData) },
 		time.Second*60,
 		time.Second*0)
 
 	/* Update Number of Sale Transactions per hour every 3 minutes.
 	The same function is executed after each sale, and when initiating cycle. */
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() {
 			sessionData.SellTransactionCount, _ = mysql.GetOrderTransactionCount(sessionData, "SELL")
 		},
@@ -644,38 +2137,283 @@ func asyncFunctions(
 		time.Second*0)
 
 	/* Update exchange latency every 5 seconds. */
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() {
-			sessionData.Latency, _ = functions.GetExchangeLatency(sessionData)
+			var err error
+			if sessionData.Latency, err = functions.GetExchangeLatency(sessionData); err == nil {
+				sessionData.LastExchangeTime = time.Now() /* Exchange API reachable */
+			}
 		},
 		time.Second*5,
 		time.Second*0)
 
 	/* Check system status every 10 seconds. */
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() {
 			nodes.Node{}.CheckStatus(configData, sessionData)
 		},
 		time.Second*10,
 		time.Second*0)
 
-	/* Send Telegram message with system error (only Master Node) every 60 seconds. */
-	scheduler.RunTaskAtInterval(
+	/* Export the trade pipeline traces to the OTLP collector every 5 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			tracing.Export(configData, sessionData)
+		},
+		time.Second*5,
+		time.Second*0)
+
+	/* Save the queued log entries to the log table every 5 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			logviewer.Flush(configData, sessionData)
+		},
+		time.Second*5,
+		time.Second*0)
+
+	/* Delete the log entries older than LogDatabaseDays (only Master Node) every hour. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			logviewer.Prune(configData, sessionData)
+		},
+		time.Hour,
+		time.Second*0)
+
+	/* Retry the queued notifications that failed to deliver (only Master Node) every 10 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			outbox.Run(configData, sessionData)
+		},
+		time.Second*10,
+		time.Second*0)
+
+	/* Send the scheduled performance summaries (only Master Node) every 30 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			summary.Run(configData, sessionData)
+		},
+		time.Second*30,
+		time.Second*0)
+
+	/* Recover a trade cycle with an order of unknown outcome (exchange unreachable during the recovery) every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			if cycle.Unresolved(sessionData) && !sessionData.Busy {
+				_ = exchange.RecoverCycle(configData, sessionData, marketData)
+			}
+		},
+		time.Second*60,
+		time.Second*60) /* After the recovery of the thread start */
+
+	/* Rebalance the capital allocator reservations every AllocatorInterval minutes (only Master Node), checked every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			risk.Allocate(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Check database connectivity (only Master Node) every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			if sessionData.MasterNode {
+				nodes.Node{}.CheckDatabase(configData, sessionData)
+			}
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Send a critical error notification with system error (only Master Node) every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() {
-			if sessionData.MasterNode && sessionData.TgBotAPIChatID != 0 {
+			if sessionData.MasterNode {
 				if threadID, err := mysql.GetSessionStatus(sessionData); err == nil {
 					if threadID != "" {
-						telegram.Message{
-							Text: "\f" + "System Fault @ " + threadID,
-						}.Send(sessionData)
+						notify.Notification{
+							Event:    messages.Error,
+							Severity: notify.Critical,
+							Key:      "fault-" + threadID,
+							Title:    "System fault",
+							Data:     messages.Data{ThreadID: threadID, Message: "System Fault @ " + threadID},
+						}.Send(configData, sessionData)
 					}
 				}
 			}
 		}, time.Second*60,
 		time.Second*0)
 
+	/* Load order book depth every 5 seconds when the order book imbalance rule or score is enabled. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			if configData.BuyOrderBookAskBidRatio > 0 ||
+				(configData.BuyScoreThreshold > 0 && configData.BuyScoreOrderBook > 0) {
+				markets.Data{}.LoadOrderBook(configData, sessionData, marketData)
+			}
+		},
+		time.Second*5,
+		time.Second*0)
+
+	/* Load high-impact economic events calendar every 60 minutes. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			calendar.Load(configData, sessionData)
+		},
+		time.Second*3600,
+		time.Second*0)
+
+	/* Calculate exposure of correlated symbols across threads every 5 minutes when the correlation guard is enabled. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			if configData.BuyCorrelationMax > 0 {
+				risk.LoadCorrelatedExposure(configData, sessionData)
+			}
+		},
+		time.Second*300,
+		time.Second*0)
+
+	/* Load drawdown kill switch status and track global equity every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			risk.LoadDrawdown(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Save a portfolio valuation snapshot for the equity curve every 5 minutes. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			risk.SaveEquity(configData, sessionData)
+		},
+		time.Second*300,
+		time.Second*0)
+
+	/* Save the realized and unrealized profit of every thread for the P&L ticker every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			pnl.Snapshot(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Evaluate alert rules every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			alerts.Evaluate(configData, marketData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Save a balances snapshot of the exchange account for the portfolio page every 5 minutes. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			portfolio.Snapshot(configData, sessionData)
+		},
+		time.Second*300,
+		time.Second*0)
+
+	/* Load realized profit for the UTC day and apply daily loss limit every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			risk.LoadDailyLoss(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Execute a confirmed emergency liquidation every 5 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			liquidation.Load(configData, sessionData)
+		},
+		time.Second*5,
+		time.Second*0)
+
+	/* Execute the commands queued for the thread by other threads every 5 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			commands.Load(configData, marketData, sessionData)
+		},
+		time.Second*5,
+		time.Second*0)
+
+	/* Send the daily digest email at the configured time (only Master Node), checked every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			email.Digest(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Calculate the fiat reserve floor every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			risk.LoadFiatReserve(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Load the account commission rate every 60 minutes. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			exchange.LoadCommission(configData, sessionData)
+		},
+		time.Second*3600,
+		time.Second*0)
+
+	/* Reload the symbol allow/deny list every 60 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			risk.LoadSymbolList(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
+	/* Evaluate the volatility circuit breaker every 5 seconds. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			risk.LoadVolatility(configData, marketData, sessionData)
+		},
+		time.Second*5,
+		time.Second*0)
+
+	/* Rebalance the basket of assets every 60 seconds when rebalance mode is enabled. */
+	threads.RunTaskAtInterval(
+		sessionData,
+		func() {
+			rebalancer.Run(configData, sessionData)
+		},
+		time.Second*60,
+		time.Second*0)
+
 	/* Load mySQL dynamic components for javascript autoloader every 10 seconds. */
-	scheduler.RunTaskAtInterval(
+	threads.RunTaskAtInterval(
+		sessionData,
 		func() {
 			loader.LoadSessionDataAdditionalComponentsAsync(sessionData)
 		},
@@ -14,6 +14,8 @@ import (
 	"github.com/sdcoffey/techan"
 )
 
+const defaultStaleTimeout = 100 * time.Second /* Used when configData.MarketDataStaleTimeout is not set */
+
 // Data struct host temporal market data
 type Data struct {
 	Kline types.WsKline
@@ -168,6 +170,88 @@ func (d Data) LoadKlinePast(
 
 }
 
+// LoadOrderBook process order book depth via REST API and calculate bid/ask imbalance
+func (d Data) LoadOrderBook(
+	configData *types.Config,
+	sessionData *types.Session,
+	marketData *types.Market) {
+
+	var err error
+	var orderBook *types.OrderBook
+
+	if orderBook, err = exchange.GetOrderBook(configData, sessionData); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   marketData,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		/* Clear the previous order book, so the buy filter and the buy score do not use a stale one */
+		marketData.OrderBook = nil
+		marketData.OrderBookBidDepth, marketData.OrderBookAskDepth, marketData.OrderBookImbalance = 0, 0, 0
+
+		return
+
+	}
+
+	marketData.OrderBook = orderBook
+	marketData.OrderBookBidDepth, marketData.OrderBookAskDepth, marketData.OrderBookImbalance = calculateOrderBookImbalance(
+		orderBook,
+		configData.BuyOrderBookDepthBps)
+
+}
+
+/* Calculate Bid/Ask volume within bps basis points of mid price and the imbalance from -1 (asks only) to 1 (bids only) */
+func calculateOrderBookImbalance(
+	orderBook *types.OrderBook,
+	bps float64) (bidDepth float64, askDepth float64, imbalance float64) {
+
+	if orderBook == nil ||
+		len(orderBook.Bids) == 0 ||
+		len(orderBook.Asks) == 0 {
+
+		return 0, 0, 0
+
+	}
+
+	mid := (orderBook.Bids[0].Price + orderBook.Asks[0].Price) / 2
+	low := mid * (1 - bps/10000)
+	high := mid * (1 + bps/10000)
+
+	for _, bid := range orderBook.Bids {
+
+		if bid.Price < low {
+			break
+		}
+
+		bidDepth += bid.Quantity
+
+	}
+
+	for _, ask := range orderBook.Asks {
+
+		if ask.Price > high {
+			break
+		}
+
+		askDepth += ask.Quantity
+
+	}
+
+	if (bidDepth + askDepth) == 0 {
+
+		return bidDepth, askDepth, 0
+
+	}
+
+	return bidDepth, askDepth, (bidDepth - askDepth) / (bidDepth + askDepth)
+
+}
+
 /* Calculate Relative Strength Index */
 func calculateRSI(
 	closePrices techan.Indicator,
@@ -207,3 +291,43 @@ func calculatePriceChangeStatsLowPrice(
 
 	return functions.StrToFloat64(priceChangeStats[0].LowPrice)
 }
+
+// IsStale check if price or kline data was not updated within configData.MarketDataStaleTimeout and mark marketData accordingly
+func (d Data) IsStale(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) bool {
+
+	now := time.Now()
+	timeout := StaleTimeout(configData)
+
+	marketData.Stale = isStale(sessionData.LastWsBookTickerTime, timeout, now) ||
+		isStale(marketData.TimeStamp, timeout, now)
+
+	return marketData.Stale
+
+}
+
+// StaleTimeout return the duration without updates after which market data is considered stale
+func StaleTimeout(
+	configData *types.Config) time.Duration {
+
+	if configData.MarketDataStaleTimeout <= 0 {
+
+		return defaultStaleTimeout
+
+	}
+
+	return time.Duration(configData.MarketDataStaleTimeout) * time.Second
+
+}
+
+/* Check if last update is older than timeout. Data never updated is stale. */
+func isStale(
+	last time.Time,
+	timeout time.Duration,
+	now time.Time) bool {
+
+	return now.Sub(last) > timeout
+
+}
@@ -2,6 +2,7 @@ package markets
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aleibovici/cryptopump/exchange"
 	"github.com/aleibovici/cryptopump/functions"
@@ -124,3 +125,104 @@ func TestData_LoadKline(t *testing.T) {
 		})
 	}
 }
+
+func Test_calculateOrderBookImbalance(t *testing.T) {
+	type args struct {
+		orderBook *types.OrderBook
+		bps       float64
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantBidDepth  float64
+		wantAskDepth  float64
+		wantImbalance float64
+	}{
+		{
+			name: "empty",
+			args: args{
+				orderBook: &types.OrderBook{},
+				bps:       10,
+			},
+			wantBidDepth:  0,
+			wantAskDepth:  0,
+			wantImbalance: 0,
+		},
+		{
+			name: "asks dominate",
+			args: args{
+				orderBook: &types.OrderBook{
+					Bids: []types.OrderBookEntry{{Price: 99.99, Quantity: 1}, {Price: 90, Quantity: 50}},
+					Asks: []types.OrderBookEntry{{Price: 100.01, Quantity: 3}, {Price: 110, Quantity: 50}},
+				},
+				bps: 10,
+			},
+			wantBidDepth:  1,
+			wantAskDepth:  3,
+			wantImbalance: -0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBidDepth, gotAskDepth, gotImbalance := calculateOrderBookImbalance(tt.args.orderBook, tt.args.bps)
+			if gotBidDepth != tt.wantBidDepth {
+				t.Errorf("calculateOrderBookImbalance() gotBidDepth = %v, want %v", gotBidDepth, tt.wantBidDepth)
+			}
+			if gotAskDepth != tt.wantAskDepth {
+				t.Errorf("calculateOrderBookImbalance() gotAskDepth = %v, want %v", gotAskDepth, tt.wantAskDepth)
+			}
+			if gotImbalance != tt.wantImbalance {
+				t.Errorf("calculateOrderBookImbalance() gotImbalance = %v, want %v", gotImbalance, tt.wantImbalance)
+			}
+		})
+	}
+}
+
+func Test_isStale(t *testing.T) {
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		last    time.Time
+		timeout time.Duration
+		now     time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "fresh",
+			args: args{
+				last:    now.Add(-10 * time.Second),
+				timeout: 30 * time.Second,
+				now:     now,
+			},
+			want: false,
+		},
+		{
+			name: "stale",
+			args: args{
+				last:    now.Add(-31 * time.Second),
+				timeout: 30 * time.Second,
+				now:     now,
+			},
+			want: true,
+		},
+		{
+			name: "never updated",
+			args: args{
+				last:    time.Time{},
+				timeout: 30 * time.Second,
+				now:     now,
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStale(tt.args.last, tt.args.timeout, tt.args.now); got != tt.want {
+				t.Errorf("isStale() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package matrix
+
+/* This package implements the Matrix notification channel, a self-hosted alternative to Telegram. Notifications
+routed to Matrix are posted as plain text messages to a room of the configured homeserver through the client-server
+API, with the access token of a bot account that joined the room. Messages are posted in the background so
+notifications never delay trading. */
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/outbox"
+	"github.com/aleibovici/cryptopump/throttle"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+var throttled = &throttle.Channel{} /* Notification rate limit */
+
+var txnCount int64 /* Transaction ID counter, the homeserver ignores a repeated transaction ID */
+
+// ErrNotConfigured is returned when the homeserver URL, access token or room ID are not configured
+var ErrNotConfigured = errors.New("Matrix homeserver URL, access token and room ID required")
+
+// Message defines a plain text notification
+type Message struct {
+	Text     string
+	Critical bool /* Never throttled */
+}
+
+// Send the message via Matrix when Matrix is configured, batched into a digest when the notification rate limit is
+// exceeded unless critical
+func (message Message) Send(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !Enabled(configData) {
+
+		return
+
+	}
+
+	if !throttled.Allow(throttle.Rate(configData), message.Critical, message.Text, func(text string) {
+		send(configData.ConfigGlobal, sessionData, text)
+	}) {
+
+		return
+
+	}
+
+	send(configData.ConfigGlobal, sessionData, message.Text)
+
+}
+
+// Deliver post the text of a queued notification, registered to outbox to retry failed messages
+func Deliver(
+	configData *types.Config,
+	sessionData *types.Session,
+	text string) error {
+
+	return post(configData.ConfigGlobal, fmt.Sprintf("cryptopump-%d-%d", time.Now().UnixNano(), atomic.AddInt64(&txnCount, 1)), text)
+
+}
+
+// Enabled return true when the Matrix homeserver URL, access token and room ID are configured
+func Enabled(configData *types.Config) bool {
+
+	global := configData.ConfigGlobal
+
+	return global != nil && global.MatrixServerURL != "" && global.MatrixAccessToken != "" && global.MatrixRoomID != ""
+
+}
+
+/* Post the message in the background, errors are logged and the message queued for retry */
+func send(
+	global *types.ConfigGlobal,
+	sessionData *types.Session,
+	text string) {
+
+	txnID := fmt.Sprintf("cryptopump-%d-%d", time.Now().UnixNano(), atomic.AddInt64(&txnCount, 1))
+
+	go func() {
+
+		if err := post(global, txnID, text); err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+			outbox.Enqueue(sessionData, messages.Matrix, text, err) /* Retried by the Master Node */
+
+		}
+
+	}()
+
+}
+
+/* Send a m.text message event to the room with transaction ID txnID */
+func post(
+	global *types.ConfigGlobal,
+	txnID string,
+	text string) (err error) {
+
+	var body []byte
+	var request *http.Request
+	var response *http.Response
+
+	if global.MatrixServerURL == "" || global.MatrixAccessToken == "" || global.MatrixRoomID == "" {
+
+		return ErrNotConfigured
+
+	}
+
+	if body, err = json.Marshal(map[string]string{
+		"msgtype": "m.text",
+		"body":    text,
+	}); err != nil {
+
+		return err
+
+	}
+
+	if request, err = http.NewRequest(
+		http.MethodPut,
+		strings.TrimRight(global.MatrixServerURL, "/")+"/_matrix/client/v3/rooms/"+
+			url.PathEscape(global.MatrixRoomID)+"/send/m.room.message/"+url.PathEscape(txnID),
+		bytes.NewReader(body)); err != nil {
+
+		return err
+
+	}
+
+	request.Header.Set("Authorization", "Bearer "+global.MatrixAccessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	if response, err = client.Do(request); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+
+		return fmt.Errorf("Matrix returned %s", response.Status)
+
+	}
+
+	return nil
+
+}
@@ -0,0 +1,65 @@
+package matrix
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		global *types.ConfigGlobal
+		want   bool
+	}{
+		{name: "configured", global: &types.ConfigGlobal{MatrixServerURL: "https://matrix.example.org", MatrixAccessToken: "syt_token", MatrixRoomID: "!trading:example.org"}, want: true},
+		{name: "without room", global: &types.ConfigGlobal{MatrixServerURL: "https://matrix.example.org", MatrixAccessToken: "syt_token"}, want: false},
+		{name: "not configured", global: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(&types.Config{ConfigGlobal: tt.global}); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_post(t *testing.T) {
+	var method, path, authorization string
+	var message map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, authorization = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		if authorization != "Bearer syt_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"event_id":"$event"}`))
+	}))
+	defer server.Close()
+
+	if err := post(&types.ConfigGlobal{}, "txn-1", "text"); err != ErrNotConfigured {
+		t.Errorf("post() error = %v, want %v", err, ErrNotConfigured)
+	}
+
+	global := &types.ConfigGlobal{MatrixServerURL: server.URL + "/", MatrixAccessToken: "syt_token", MatrixRoomID: "!trading:example.org"}
+	if err := post(global, "txn-1", "SELL BTCUSDT 0.002100 @ 57600.0000"); err != nil {
+		t.Fatalf("post() error = %v", err)
+	}
+	if method != http.MethodPut || path != "/_matrix/client/v3/rooms/!trading:example.org/send/m.room.message/txn-1" {
+		t.Errorf("post() %v %v", method, path)
+	}
+	if message["msgtype"] != "m.text" || message["body"] != "SELL BTCUSDT 0.002100 @ 57600.0000" {
+		t.Errorf("post() message = %v", message)
+	}
+
+	global.MatrixAccessToken = "invalid"
+	if err := post(global, "txn-2", "text"); err == nil {
+		t.Errorf("post() error = nil, want unauthorized")
+	}
+}
@@ -0,0 +1,165 @@
+package messages
+
+/* This package implements the notification message templates. The text of each notification event is rendered
+with a Go text/template, so operators can change what the messages contain without code changes. A template file
+config/messages/<channel>.<event>.tmpl applies to one channel and overrides config/messages/<event>.tmpl, which
+applies to all channels and overrides the built-in default. Template files are read for each notification so edits
+apply without restarting the threads. */
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Notification events */
+const (
+	Buy      = "buy"      /* Filled BUY order */
+	Sell     = "sell"     /* Filled SELL order */
+	Profit   = "profit"   /* Realized profit of a sale */
+	Stoploss = "stoploss" /* Stoploss or stop price sale */
+	Error    = "error"    /* Exchange errors, system faults and risk limits */
+	Summary  = "summary"  /* Scheduled performance summary */
+)
+
+/* Notification channels */
+const (
+	Telegram = "telegram"
+	Discord  = "discord"
+	Slack    = "slack"
+	Matrix   = "matrix"
+	Email    = "email"
+	Push     = "push" /* Pushover and ntfy */
+	SMS      = "sms"  /* Twilio, critical notifications only */
+)
+
+// Dir is the directory of the template files
+var Dir = "./config/messages"
+
+// Data struct define the fields available to the templates
+type Data struct {
+	Event      string
+	Channel    string
+	ThreadID   string
+	Symbol     string
+	Fiat       string
+	Side       string /* BUY or SELL */
+	OrderID    int64
+	Price      float64 /* Order price, or market price for stoploss */
+	OrderPrice float64 /* Buy price of the order sold at stoploss */
+	Quantity   float64
+	Profit     float64 /* Realized profit in fiat of the sale (profit) or of the thread (buy and sell) */
+	ProfitPct  float64 /* Average transaction profit of the thread as percentage */
+	Reason     string  /* stoploss or stop price */
+	Message    string  /* Error text */
+}
+
+/* Built-in templates by channel.event or event */
+var defaults = map[string]string{
+	Buy:                   `BUY {{.Symbol}} {{fixed .Quantity 6}} @ {{fixed .Price 4}} - {{.ThreadID}}`,
+	Sell:                  `SELL {{.Symbol}} {{fixed .Quantity 6}} @ {{fixed .Price 4}} - {{.ThreadID}}`,
+	Profit:                `Profit {{.Symbol}} {{fixed .Profit 2}} {{.Fiat}} - {{.ThreadID}}`,
+	Stoploss:              `Thread {{.ThreadID}} {{.Symbol}} price {{.Price}} triggered the {{.Reason}} of order {{.OrderID}} bought at {{.OrderPrice}}. Selling at market.`,
+	Push + "." + Stoploss: `{{.Symbol}} {{.Price}} triggered the {{.Reason}} of order {{.OrderID}}, selling at market`,
+	Error:                 `{{.Message}}`,
+	Email + "." + Error:   `Thread {{.ThreadID}} {{.Symbol}}: {{.Message}}`,
+	Summary:               `{{.Message}}`,
+}
+
+var funcs = template.FuncMap{
+	"fixed": functions.Float64ToStr, /* Format a number with a fixed number of decimals */
+}
+
+// Text return the message of event for channel rendered with the template of the channel and event. Template
+// errors are logged and the built-in default is used.
+func Text(
+	sessionData *types.Session,
+	channel string,
+	event string,
+	data Data) string {
+
+	data.Event = event
+	data.Channel = channel
+
+	text, err := render(load(channel, event), data)
+	if err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + channel + "." + event + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		text, _ = render(builtin(channel, event), data)
+
+	}
+
+	return text
+
+}
+
+/* Return the template file of the channel and event, the template file of the event or the built-in default */
+func load(
+	channel string,
+	event string) string {
+
+	for _, name := range []string{channel + "." + event + ".tmpl", event + ".tmpl"} {
+
+		if data, err := os.ReadFile(filepath.Join(Dir, name)); err == nil {
+
+			return string(data)
+
+		}
+
+	}
+
+	return builtin(channel, event)
+
+}
+
+/* Return the built-in template of the channel and event */
+func builtin(
+	channel string,
+	event string) string {
+
+	if text, ok := defaults[channel+"."+event]; ok {
+
+		return text
+
+	}
+
+	return defaults[event]
+
+}
+
+/* Render the template text with data */
+func render(
+	text string,
+	data Data) (string, error) {
+
+	var buffer bytes.Buffer
+
+	tmpl, err := template.New("message").Funcs(funcs).Parse(text)
+	if err != nil {
+
+		return "", err
+
+	}
+
+	if err = tmpl.Execute(&buffer, data); err != nil {
+
+		return "", err
+
+	}
+
+	return string(bytes.TrimRight(buffer.Bytes(), "\r\n")), nil /* Template files usually end with a newline */
+
+}
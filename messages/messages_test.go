@@ -0,0 +1,56 @@
+package messages
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestText(t *testing.T) {
+	dir, err := ioutil.TempDir("", "messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	saved := Dir
+	Dir = dir
+	defer func() { Dir = saved }()
+
+	for name, text := range map[string]string{
+		"sell.tmpl":           "{{.Side}} {{.Symbol}} at {{fixed .Price 2}} {{.Fiat}}\n",
+		"discord.sell.tmpl":   "**{{.Side}}** {{.Symbol}} {{.Quantity}}",
+		"telegram.error.tmpl": "{{.Unknown}}",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	order := Data{ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Fiat: "USDT", Side: "SELL", Price: 57600.456, Quantity: 0.0021}
+	stoploss := Data{ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", OrderID: 42, Price: 48000, OrderPrice: 50000, Reason: "stoploss"}
+
+	tests := []struct {
+		name    string
+		channel string
+		event   string
+		data    Data
+		want    string
+	}{
+		{name: "default", channel: Discord, event: Buy, data: order, want: "BUY BTCUSDT 0.002100 @ 57600.4560 - c683ok5mk1u1120gnmmg"},
+		{name: "event template", channel: Slack, event: Sell, data: order, want: "SELL BTCUSDT at 57600.46 USDT"},
+		{name: "channel template", channel: Discord, event: Sell, data: order, want: "**SELL** BTCUSDT 0.0021"},
+		{name: "channel default", channel: Push, event: Stoploss, data: stoploss, want: "BTCUSDT 48000 triggered the stoploss of order 42, selling at market"},
+		{name: "invalid template", channel: Telegram, event: Error, data: Data{Message: "System Fault @ c683ok5mk1u1120gnmmg"}, want: "System Fault @ c683ok5mk1u1120gnmmg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(&types.Session{}, tt.channel, tt.event, tt.data); got != tt.want {
+				t.Errorf("Text() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package metrics
+
+/* This package implements the Prometheus metrics of the thread running in this process, served at /metrics in the
+Prometheus text exposition format. Every series has the thread and symbol labels: the open exposure and realized
+profit gauges, the orders placed, filled and failed counters by side, the websocket reconnects and messages counters
+and connected gauge by stream, the database query latency histogram by stored procedure, the exchange REST call
+latency histogram and errors counter by endpoint, the exchange REST call slot wait histogram and waiting gauge by
+priority lane and the exchange API request weight used in the last minute. */
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Websocket streams */
+const (
+	StreamKline      = "kline"
+	StreamBookTicker = "bookticker"
+	StreamUserData   = "userdata"
+)
+
+// ContentType is the Content-Type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+/* Upper bounds in seconds of the database query and exchange call latency histogram buckets */
+var buckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+/* Latency histogram of a stored procedure */
+type histogram struct {
+	counts []uint64 /* Observations of each bucket, not cumulative */
+	sum    float64  /* Seconds */
+	count  uint64
+}
+
+var registry = struct {
+	sync.Mutex
+	placed     map[string]float64 /* Orders placed by side */
+	filled     map[string]float64 /* Orders filled by side */
+	failed     map[string]float64 /* Orders failed by side */
+	reconnects map[string]float64 /* Websocket reconnects by stream */
+	messages   map[string]float64 /* Websocket messages received by stream */
+	connected  map[string]float64 /* Websocket connected (1) or disconnected (0) by stream */
+	queries    map[string]*histogram
+	calls      map[string]*histogram /* Exchange REST call latency by endpoint */
+	errors     map[string]float64    /* Exchange REST calls failed by endpoint */
+	waits      map[string]*histogram /* Exchange REST call slot wait by lane */
+	waiting    map[string]float64    /* Exchange REST calls waiting for a slot by lane */
+	weight     float64               /* Exchange API request weight used in the last minute */
+}{
+	placed:     make(map[string]float64),
+	filled:     make(map[string]float64),
+	failed:     make(map[string]float64),
+	reconnects: make(map[string]float64),
+	messages:   make(map[string]float64),
+	connected:  make(map[string]float64),
+	queries:    make(map[string]*histogram),
+	calls:      make(map[string]*histogram),
+	errors:     make(map[string]float64),
+	waits:      make(map[string]*histogram),
+	waiting:    make(map[string]float64),
+}
+
+// OrderPlaced count an order of side (BUY or SELL) accepted by the exchange
+func OrderPlaced(side string) {
+
+	add(registry.placed, side)
+
+}
+
+// OrderFilled count an order of side (BUY or SELL) filled by the exchange
+func OrderFilled(side string) {
+
+	add(registry.filled, side)
+
+}
+
+// OrderFailed count an order of side (BUY or SELL) that failed with an exchange error
+func OrderFailed(side string) {
+
+	add(registry.failed, side)
+
+}
+
+// Handle count the orders of the order events published on the event bus
+func Handle(event events.Event) {
+
+	order, ok := event.Data.(events.Order)
+	if !ok {
+
+		return
+
+	}
+
+	switch event.Topic {
+	case events.OrderPlaced:
+		OrderPlaced(order.Side)
+	case events.OrderFilled:
+		OrderFilled(order.Side)
+	case events.OrderFailed:
+		OrderFailed(order.Side)
+	}
+
+}
+
+// WebsocketReconnect count a websocket stream disconnection being re-established
+func WebsocketReconnect(stream string) {
+
+	add(registry.reconnects, stream)
+
+}
+
+// WebsocketMessage count a message received on a websocket stream
+func WebsocketMessage(stream string) {
+
+	add(registry.messages, stream)
+
+}
+
+// WebsocketConnected set whether a websocket stream is connected
+func WebsocketConnected(
+	stream string,
+	connected bool) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.connected[stream] = 0
+
+	if connected {
+		registry.connected[stream] = 1
+	}
+
+}
+
+// ObserveQuery record the latency of a database stored procedure call
+func ObserveQuery(
+	procedure string,
+	latency time.Duration) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	observe(registry.queries, procedure, latency)
+
+}
+
+// ObserveExchange record the latency of an exchange REST call to endpoint, and count it when failed
+func ObserveExchange(
+	endpoint string,
+	latency time.Duration,
+	failed bool) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	observe(registry.calls, endpoint, latency)
+
+	if failed {
+		registry.errors[endpoint]++
+	}
+
+}
+
+// ObserveExchangeWait record the time an exchange REST call of lane waited for a slot of the concurrency limiter
+func ObserveExchangeWait(
+	lane string,
+	wait time.Duration) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	observe(registry.waits, lane, wait)
+
+}
+
+// SetExchangeWaiting set the exchange REST calls of lane waiting for a slot of the concurrency limiter
+func SetExchangeWaiting(
+	lane string,
+	waiting int) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.waiting[lane] = float64(waiting)
+
+}
+
+// SetUsedWeight set the exchange API request weight used in the last minute, as reported by the exchange
+func SetUsedWeight(weight float64) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.weight = weight
+
+}
+
+// Write the metrics of the thread in the Prometheus text exposition format
+func Write(
+	w io.Writer,
+	sessionData *types.Session) {
+
+	var profit float64
+
+	if sessionData.Global != nil {
+		profit = sessionData.Global.ProfitThreadID
+	}
+
+	labels := "thread=\"" + escape(sessionData.ThreadID) + "\",symbol=\"" + escape(sessionData.Symbol) + "\""
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	header(w, "cryptopump_open_exposure", "gauge", "Open exposure of the thread in fiat.")
+	fmt.Fprintf(w, "cryptopump_open_exposure{%s} %s\n", labels, format(sessionData.ThreadExposure))
+
+	header(w, "cryptopump_realized_profit", "gauge", "Realized profit of the thread in fiat.")
+	fmt.Fprintf(w, "cryptopump_realized_profit{%s} %s\n", labels, format(profit))
+
+	counter(w, "cryptopump_orders_placed_total", "Orders accepted by the exchange.", labels, "side", registry.placed)
+	counter(w, "cryptopump_orders_filled_total", "Orders filled by the exchange.", labels, "side", registry.filled)
+	counter(w, "cryptopump_orders_failed_total", "Orders failed with an exchange error.", labels, "side", registry.failed)
+	counter(w, "cryptopump_websocket_reconnects_total", "Websocket disconnections re-established.", labels, "stream", registry.reconnects)
+	counter(w, "cryptopump_websocket_messages_total", "Websocket messages received.", labels, "stream", registry.messages)
+	gauges(w, "cryptopump_websocket_connected", "Websocket stream connected (1) or disconnected (0).", labels, "stream", registry.connected)
+
+	histograms(w, "cryptopump_db_query_duration_seconds", "Database stored procedure call latency.", labels, "procedure", registry.queries)
+	histograms(w, "cryptopump_exchange_request_duration_seconds", "Exchange REST call latency.", labels, "endpoint", registry.calls)
+	counter(w, "cryptopump_exchange_request_errors_total", "Exchange REST calls failed with a network error or an error status.", labels, "endpoint", registry.errors)
+
+	histograms(w, "cryptopump_exchange_queue_wait_seconds", "Exchange REST call wait for a slot of the concurrency limiter.", labels, "lane", registry.waits)
+	gauges(w, "cryptopump_exchange_queue_waiting", "Exchange REST calls waiting for a slot of the concurrency limiter.", labels, "lane", registry.waiting)
+
+	header(w, "cryptopump_exchange_used_weight", "gauge", "Exchange API request weight used in the last minute.")
+	fmt.Fprintf(w, "cryptopump_exchange_used_weight{%s} %s\n", labels, format(registry.weight))
+
+}
+
+/* Increment the counter of label value in values */
+func add(
+	values map[string]float64,
+	value string) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	values[value]++
+
+}
+
+/* Write the HELP and TYPE lines of a metric */
+func header(
+	w io.Writer,
+	name string,
+	kind string,
+	help string) {
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+
+}
+
+/* Write a counter with a series for each value of label */
+func counter(
+	w io.Writer,
+	name string,
+	help string,
+	labels string,
+	label string,
+	values map[string]float64) {
+
+	header(w, name, "counter", help)
+
+	for _, value := range keys(values) {
+
+		fmt.Fprintf(w, "%s{%s,%s=\"%s\"} %s\n", name, labels, label, escape(value), format(values[value]))
+
+	}
+
+}
+
+/* Write a gauge with a series for each value of label */
+func gauges(
+	w io.Writer,
+	name string,
+	help string,
+	labels string,
+	label string,
+	values map[string]float64) {
+
+	header(w, name, "gauge", help)
+
+	for _, value := range keys(values) {
+
+		fmt.Fprintf(w, "%s{%s,%s=\"%s\"} %s\n", name, labels, label, escape(value), format(values[value]))
+
+	}
+
+}
+
+/* Record latency in the histogram of key in values, the registry must be locked */
+func observe(
+	values map[string]*histogram,
+	key string,
+	latency time.Duration) {
+
+	h, ok := values[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(buckets))}
+		values[key] = h
+	}
+
+	seconds := latency.Seconds()
+
+	for i, bound := range buckets {
+
+		if seconds <= bound {
+
+			h.counts[i]++
+			break
+
+		}
+
+	}
+
+	h.sum += seconds
+	h.count++
+
+}
+
+/* Write a histogram with a series for each value of label */
+func histograms(
+	w io.Writer,
+	name string,
+	help string,
+	labels string,
+	label string,
+	values map[string]*histogram) {
+
+	header(w, name, "histogram", help)
+
+	for _, value := range keys(values) {
+
+		h := values[value]
+		series := labels + "," + label + "=\"" + escape(value) + "\""
+
+		var cumulative uint64
+
+		for i, bound := range buckets {
+
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, series, format(bound), cumulative)
+
+		}
+
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, series, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, series, format(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, series, h.count)
+
+	}
+
+}
+
+/* Return the sorted keys of a map of counters or histograms */
+func keys(m interface{}) (sorted []string) {
+
+	switch m := m.(type) {
+	case map[string]float64:
+		for key := range m {
+			sorted = append(sorted, key)
+		}
+	case map[string]*histogram:
+		for key := range m {
+			sorted = append(sorted, key)
+		}
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+
+}
+
+/* Escape a label value */
+func escape(value string) string {
+
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+
+}
+
+/* Format a sample value */
+func format(value float64) string {
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+
+}
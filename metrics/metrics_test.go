@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestWrite(t *testing.T) {
+	OrderPlaced("BUY")
+	OrderPlaced("BUY")
+	Handle(events.Event{Topic: events.OrderFilled, Data: events.Order{Side: "SELL"}})
+	Handle(events.Event{Topic: events.OrderFailed, Data: events.Order{Side: "SELL"}})
+	Handle(events.Event{Topic: events.SessionStarted, Data: events.Session{}})
+	WebsocketReconnect(StreamKline)
+	WebsocketMessage(StreamBookTicker)
+	WebsocketConnected(StreamBookTicker, true)
+	WebsocketConnected(StreamKline, false)
+	ObserveQuery("GetPendingActions", 3*time.Millisecond)
+	ObserveQuery("GetPendingActions", 2*time.Second)
+	ObserveQuery("GetPendingActions", 10*time.Second)
+	ObserveExchange("POST /api/v3/order", 80*time.Millisecond, false)
+	ObserveExchange("POST /api/v3/order", 3*time.Second, true)
+	ObserveExchangeWait("order", 20*time.Millisecond)
+	SetExchangeWaiting("stats", 3)
+	SetUsedWeight(42)
+
+	var buffer bytes.Buffer
+	Write(&buffer, &types.Session{
+		ThreadID:       "c683ok5mk1u1120gnmmg",
+		Symbol:         "BTCUSDT",
+		ThreadExposure: 1500.5,
+		Global:         &types.Global{ProfitThreadID: 12.25},
+	})
+
+	labels := `thread="c683ok5mk1u1120gnmmg",symbol="BTCUSDT"`
+	for _, want := range []string{
+		"# TYPE cryptopump_open_exposure gauge\ncryptopump_open_exposure{" + labels + "} 1500.5\n",
+		"cryptopump_realized_profit{" + labels + "} 12.25\n",
+		"# TYPE cryptopump_orders_placed_total counter\ncryptopump_orders_placed_total{" + labels + `,side="BUY"} 2` + "\n",
+		"cryptopump_orders_filled_total{" + labels + `,side="SELL"} 1` + "\n",
+		"cryptopump_orders_failed_total{" + labels + `,side="SELL"} 1` + "\n",
+		"cryptopump_websocket_reconnects_total{" + labels + `,stream="kline"} 1` + "\n",
+		"cryptopump_websocket_messages_total{" + labels + `,stream="bookticker"} 1` + "\n",
+		"# TYPE cryptopump_websocket_connected gauge\ncryptopump_websocket_connected{" + labels + `,stream="bookticker"} 1` + "\ncryptopump_websocket_connected{" + labels + `,stream="kline"} 0` + "\n",
+		"cryptopump_db_query_duration_seconds_bucket{" + labels + `,procedure="GetPendingActions",le="0.001"} 0` + "\n",
+		"cryptopump_db_query_duration_seconds_bucket{" + labels + `,procedure="GetPendingActions",le="0.005"} 1` + "\n",
+		"cryptopump_db_query_duration_seconds_bucket{" + labels + `,procedure="GetPendingActions",le="2.5"} 2` + "\n",
+		"cryptopump_db_query_duration_seconds_bucket{" + labels + `,procedure="GetPendingActions",le="5"} 2` + "\n",
+		"cryptopump_db_query_duration_seconds_bucket{" + labels + `,procedure="GetPendingActions",le="+Inf"} 3` + "\n",
+		"cryptopump_db_query_duration_seconds_sum{" + labels + `,procedure="GetPendingActions"} 12.003` + "\n",
+		"cryptopump_db_query_duration_seconds_count{" + labels + `,procedure="GetPendingActions"} 3` + "\n",
+		"cryptopump_exchange_request_duration_seconds_bucket{" + labels + `,endpoint="POST /api/v3/order",le="0.1"} 1` + "\n",
+		"cryptopump_exchange_request_duration_seconds_count{" + labels + `,endpoint="POST /api/v3/order"} 2` + "\n",
+		"cryptopump_exchange_request_errors_total{" + labels + `,endpoint="POST /api/v3/order"} 1` + "\n",
+		"cryptopump_exchange_queue_wait_seconds_bucket{" + labels + `,lane="order",le="0.025"} 1` + "\n",
+		"cryptopump_exchange_queue_waiting{" + labels + `,lane="stats"} 3` + "\n",
+		"cryptopump_exchange_used_weight{" + labels + "} 42\n",
+	} {
+		if !strings.Contains(buffer.String(), want) {
+			t.Errorf("Write() = %v, want %v", buffer.String(), want)
+		}
+	}
+}
+
+func Test_escape(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain", value: "BTCUSDT", want: "BTCUSDT"},
+		{name: "quote", value: `a"b`, want: `a\"b`},
+		{name: "backslash and newline", value: "a\\b\nc", want: `a\\b\nc`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escape(tt.value); got != tt.want {
+				t.Errorf("escape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
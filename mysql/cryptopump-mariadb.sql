@@ -23,6 +23,283 @@ CREATE DATABASE /*!32312 IF NOT EXISTS*/ `cryptopump` /*!40100 DEFAULT CHARACTER
 
 USE `cryptopump`;
 
+--
+-- Table structure for table `alertrule`
+--
+
+DROP TABLE IF EXISTS `alertrule`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `alertrule` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `Name` varchar(64) NOT NULL,
+  `ThreadID` varchar(45) NOT NULL DEFAULT '',
+  `Metric` varchar(45) NOT NULL,
+  `Operator` varchar(2) NOT NULL,
+  `Threshold` double NOT NULL,
+  `Channel` varchar(45) NOT NULL,
+  `Target` varchar(255) NOT NULL DEFAULT '',
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `alertrule`
+--
+
+LOCK TABLES `alertrule` WRITE;
+/*!40000 ALTER TABLE `alertrule` DISABLE KEYS */;
+/*!40000 ALTER TABLE `alertrule` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `assetprice`
+--
+
+DROP TABLE IF EXISTS `assetprice`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `assetprice` (
+  `Asset` varchar(20) NOT NULL,
+  `Price` double NOT NULL,
+  `Time` bigint(20) NOT NULL,
+  PRIMARY KEY (`Asset`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `assetprice`
+--
+
+LOCK TABLES `assetprice` WRITE;
+/*!40000 ALTER TABLE `assetprice` DISABLE KEYS */;
+/*!40000 ALTER TABLE `assetprice` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `audit`
+--
+
+DROP TABLE IF EXISTS `audit`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `audit` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `Time` bigint(20) NOT NULL,
+  `ThreadID` varchar(45) NOT NULL,
+  `Event` varchar(45) NOT NULL,
+  `OrderID` bigint(20) NOT NULL,
+  `Payload` text NOT NULL,
+  `PrevHash` char(64) NOT NULL,
+  `Hash` char(64) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `audit_idx_orderid` (`OrderID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `audit`
+--
+
+LOCK TABLES `audit` WRITE;
+/*!40000 ALTER TABLE `audit` DISABLE KEYS */;
+/*!40000 ALTER TABLE `audit` ENABLE KEYS */;
+UNLOCK TABLES;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` TRIGGER `audit_before_update` BEFORE UPDATE ON `audit` FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit table is append-only';
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` TRIGGER `audit_before_delete` BEFORE DELETE ON `audit` FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit table is append-only';
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+
+--
+-- Table structure for table `authtoken`
+--
+
+DROP TABLE IF EXISTS `authtoken`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `authtoken` (
+  `TokenHash` varchar(64) NOT NULL,
+  `Username` varchar(45) NOT NULL,
+  `Kind` varchar(45) NOT NULL,
+  `Expires` bigint(20) NOT NULL DEFAULT '0',
+  `LastSeen` bigint(20) NOT NULL DEFAULT '0',
+  PRIMARY KEY (`TokenHash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `authtoken`
+--
+
+LOCK TABLES `authtoken` WRITE;
+/*!40000 ALTER TABLE `authtoken` DISABLE KEYS */;
+/*!40000 ALTER TABLE `authtoken` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `backtest`
+--
+
+DROP TABLE IF EXISTS `backtest`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `backtest` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `Name` varchar(64) NOT NULL,
+  `Symbol` varchar(45) NOT NULL,
+  `StartTime` bigint(20) NOT NULL,
+  `EndTime` bigint(20) NOT NULL,
+  `CreatedTime` bigint(20) NOT NULL,
+  `Parameters` text NOT NULL,
+  `Trades` int(11) NOT NULL DEFAULT 0,
+  `ProfitPct` float NOT NULL DEFAULT 0,
+  `BuyHoldPct` float NOT NULL DEFAULT 0,
+  `MaxDrawdownPct` float NOT NULL DEFAULT 0,
+  `WinRatePct` float NOT NULL DEFAULT 0,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `backtest`
+--
+
+LOCK TABLES `backtest` WRITE;
+/*!40000 ALTER TABLE `backtest` DISABLE KEYS */;
+/*!40000 ALTER TABLE `backtest` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `backtestequity`
+--
+
+DROP TABLE IF EXISTS `backtestequity`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `backtestequity` (
+  `BacktestID` int(11) NOT NULL,
+  `Time` bigint(20) NOT NULL,
+  `Equity` float NOT NULL,
+  `BuyHold` float NOT NULL,
+  PRIMARY KEY (`BacktestID`,`Time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `backtestequity`
+--
+
+LOCK TABLES `backtestequity` WRITE;
+/*!40000 ALTER TABLE `backtestequity` DISABLE KEYS */;
+/*!40000 ALTER TABLE `backtestequity` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `balance`
+--
+
+DROP TABLE IF EXISTS `balance`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `balance` (
+  `Account` varchar(45) NOT NULL,
+  `Asset` varchar(20) NOT NULL,
+  `Free` double NOT NULL,
+  `Locked` double NOT NULL,
+  `Time` bigint(20) NOT NULL,
+  PRIMARY KEY (`Account`,`Asset`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `balance`
+--
+
+LOCK TABLES `balance` WRITE;
+/*!40000 ALTER TABLE `balance` DISABLE KEYS */;
+/*!40000 ALTER TABLE `balance` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `configaudit`
+--
+
+DROP TABLE IF EXISTS `configaudit`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `configaudit` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Version` int(11) NOT NULL,
+  `Username` varchar(45) NOT NULL,
+  `Time` bigint(20) NOT NULL,
+  `Config` text NOT NULL,
+  `Changes` text NOT NULL,
+  PRIMARY KEY (`ID`),
+  UNIQUE KEY `configaudit_idx_threadid_version` (`ThreadID`,`Version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `configaudit`
+--
+
+LOCK TABLES `configaudit` WRITE;
+/*!40000 ALTER TABLE `configaudit` DISABLE KEYS */;
+/*!40000 ALTER TABLE `configaudit` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `equity`
+--
+
+DROP TABLE IF EXISTS `equity`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `equity` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `Time` bigint(20) NOT NULL,
+  `Equity` float NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `equity_idx_time` (`Time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `equity`
+--
+
+LOCK TABLES `equity` WRITE;
+/*!40000 ALTER TABLE `equity` DISABLE KEYS */;
+/*!40000 ALTER TABLE `equity` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `global`
 --
@@ -36,116 +313,2622 @@ CREATE TABLE `global` (
   `ProfitNet` float NOT NULL,
   `ProfitPct` float NOT NULL,
   `TransactTime` varchar(45) NOT NULL,
+  `EquityPeak` float NOT NULL DEFAULT 0,
+  `DrawdownHalt` tinyint(4) NOT NULL DEFAULT 0,
+  `Liquidate` tinyint(4) NOT NULL DEFAULT 0,
+  `LiquidateTime` bigint(20) NOT NULL DEFAULT 0,
   PRIMARY KEY (`ID`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 MAX_ROWS=1;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
---
--- Dumping data for table `global`
---
+--
+-- Dumping data for table `global`
+--
+
+LOCK TABLES `global` WRITE;
+/*!40000 ALTER TABLE `global` DISABLE KEYS */;
+/*!40000 ALTER TABLE `global` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `kline`
+--
+
+DROP TABLE IF EXISTS `kline`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `kline` (
+  `ThreadID` varchar(45) NOT NULL,
+  `OpenTime` bigint(20) NOT NULL,
+  `CloseTime` bigint(20) NOT NULL,
+  `Open` float NOT NULL,
+  `Close` float NOT NULL,
+  `Low` float NOT NULL,
+  `High` float NOT NULL,
+  `Volume` float NOT NULL,
+  `Ma7` float NOT NULL DEFAULT 0,
+  `Ma14` float NOT NULL DEFAULT 0,
+  PRIMARY KEY (`ThreadID`,`OpenTime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `kline`
+--
+
+LOCK TABLES `kline` WRITE;
+/*!40000 ALTER TABLE `kline` DISABLE KEYS */;
+/*!40000 ALTER TABLE `kline` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `liquidation`
+--
+
+DROP TABLE IF EXISTS `liquidation`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `liquidation` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Symbol` varchar(45) NOT NULL,
+  `Orders` int(11) NOT NULL,
+  `ExecutedQuantity` float NOT NULL,
+  `CummulativeQuoteQty` float NOT NULL,
+  `StartTime` bigint(20) NOT NULL,
+  `EndTime` bigint(20) NOT NULL,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `liquidation`
+--
+
+LOCK TABLES `liquidation` WRITE;
+/*!40000 ALTER TABLE `liquidation` DISABLE KEYS */;
+/*!40000 ALTER TABLE `liquidation` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `log`
+--
+
+DROP TABLE IF EXISTS `log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `log` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `Time` bigint(20) NOT NULL,
+  `Level` varchar(45) NOT NULL,
+  `ThreadID` varchar(45) NOT NULL,
+  `Component` varchar(45) NOT NULL,
+  `Message` text NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `log_idx_time` (`Time`),
+  KEY `log_idx_threadid` (`ThreadID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `log`
+--
+
+LOCK TABLES `log` WRITE;
+/*!40000 ALTER TABLE `log` DISABLE KEYS */;
+/*!40000 ALTER TABLE `log` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `note`
+--
+
+DROP TABLE IF EXISTS `note`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `note` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `OrderID` bigint(20) NOT NULL DEFAULT '0',
+  `Username` varchar(45) NOT NULL,
+  `Time` bigint(20) NOT NULL,
+  `Tags` varchar(255) NOT NULL,
+  `Text` text NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `note_idx_threadid` (`ThreadID`),
+  KEY `note_idx_orderid` (`OrderID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `note`
+--
+
+LOCK TABLES `note` WRITE;
+/*!40000 ALTER TABLE `note` DISABLE KEYS */;
+/*!40000 ALTER TABLE `note` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `notificationqueue`
+--
+
+DROP TABLE IF EXISTS `notificationqueue`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `notificationqueue` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Channel` varchar(45) NOT NULL,
+  `Text` text NOT NULL,
+  `Attempts` int(11) NOT NULL,
+  `NextAttempt` bigint(20) NOT NULL,
+  `LastError` varchar(255) NOT NULL,
+  `CreatedTime` bigint(20) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `NextAttempt` (`NextAttempt`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `notificationqueue`
+--
+
+LOCK TABLES `notificationqueue` WRITE;
+/*!40000 ALTER TABLE `notificationqueue` DISABLE KEYS */;
+/*!40000 ALTER TABLE `notificationqueue` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `orders`
+--
+
+DROP TABLE IF EXISTS `orders`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `orders` (
+  `ClientOrderId` varchar(45) NOT NULL,
+  `CummulativeQuoteQty` float NOT NULL,
+  `ExecutedQuantity` float NOT NULL,
+  `OrderID` bigint(20) NOT NULL,
+  `OrderIDSource` bigint(20) NOT NULL,
+  `Price` float NOT NULL,
+  `Side` varchar(45) NOT NULL,
+  `Status` varchar(45) NOT NULL,
+  `Symbol` varchar(45) NOT NULL,
+  `TransactTime` bigint(20) NOT NULL,
+  `ThreadID` varchar(45) NOT NULL,
+  `ThreadIDSession` varchar(45) NOT NULL,
+  `Type` varchar(45) NOT NULL DEFAULT 'TRADE',
+  `Score` float NOT NULL DEFAULT 0,
+  `Source` varchar(45) NOT NULL DEFAULT 'bot',
+  `CorrelationID` varchar(45) NOT NULL DEFAULT '',
+  PRIMARY KEY (`OrderID`),
+  UNIQUE KEY `OrderID_UNIQUE` (`OrderID`),
+  KEY `orders_idx_side_status` (`Side`,`Status`),
+  KEY `orders_idx_correlation` (`CorrelationID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `orders`
+--
+
+LOCK TABLES `orders` WRITE;
+/*!40000 ALTER TABLE `orders` DISABLE KEYS */;
+/*!40000 ALTER TABLE `orders` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `pendingaction`
+--
+
+DROP TABLE IF EXISTS `pendingaction`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `pendingaction` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Action` varchar(45) NOT NULL,
+  `OrderID` bigint(20) NOT NULL,
+  `Notional` float NOT NULL,
+  `Status` varchar(45) NOT NULL,
+  `CreatedTime` bigint(20) NOT NULL,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `pendingaction`
+--
+
+LOCK TABLES `pendingaction` WRITE;
+/*!40000 ALTER TABLE `pendingaction` DISABLE KEYS */;
+/*!40000 ALTER TABLE `pendingaction` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `pnl`
+--
+
+DROP TABLE IF EXISTS `pnl`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `pnl` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `Time` bigint(20) NOT NULL,
+  `ThreadID` varchar(45) NOT NULL,
+  `Realized` float NOT NULL,
+  `Unrealized` float NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `pnl_idx_time` (`Time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `pnl`
+--
+
+LOCK TABLES `pnl` WRITE;
+/*!40000 ALTER TABLE `pnl` DISABLE KEYS */;
+/*!40000 ALTER TABLE `pnl` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `preference`
+--
+
+DROP TABLE IF EXISTS `preference`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `preference` (
+  `Username` varchar(45) NOT NULL,
+  `Theme` varchar(10) NOT NULL,
+  `RefreshInterval` int(11) NOT NULL,
+  `Currency` varchar(10) NOT NULL,
+  `Columns` varchar(255) NOT NULL,
+  `Language` varchar(10) NOT NULL DEFAULT '',
+  PRIMARY KEY (`Username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `preference`
+--
+
+LOCK TABLES `preference` WRITE;
+/*!40000 ALTER TABLE `preference` DISABLE KEYS */;
+/*!40000 ALTER TABLE `preference` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `preset`
+--
+
+DROP TABLE IF EXISTS `preset`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `preset` (
+  `Name` varchar(64) NOT NULL,
+  `Username` varchar(45) NOT NULL,
+  `Time` bigint(20) NOT NULL,
+  `Config` text NOT NULL,
+  PRIMARY KEY (`Name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `preset`
+--
+
+LOCK TABLES `preset` WRITE;
+/*!40000 ALTER TABLE `preset` DISABLE KEYS */;
+/*!40000 ALTER TABLE `preset` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `recoverycode`
+--
+
+DROP TABLE IF EXISTS `recoverycode`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `recoverycode` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `Username` varchar(45) NOT NULL,
+  `CodeHash` varchar(64) NOT NULL,
+  PRIMARY KEY (`ID`),
+  UNIQUE KEY `Username_CodeHash_UNIQUE` (`Username`,`CodeHash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `recoverycode`
+--
+
+LOCK TABLES `recoverycode` WRITE;
+/*!40000 ALTER TABLE `recoverycode` DISABLE KEYS */;
+/*!40000 ALTER TABLE `recoverycode` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `session`
+--
+
+DROP TABLE IF EXISTS `session`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `session` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `ThreadIDSession` varchar(45) NOT NULL,
+  `Exchange` varchar(45) NOT NULL,
+  `FiatSymbol` varchar(45) NOT NULL,
+  `FiatFunds` float NOT NULL,
+  `DiffTotal` float NOT NULL,
+  `Status` tinyint(4) NOT NULL,
+  `CooldownStart` bigint(20) NOT NULL DEFAULT '0',
+  `CooldownUntil` bigint(20) NOT NULL DEFAULT '0',
+  `StopPrice` float NOT NULL DEFAULT 0,
+  `Paused` tinyint NOT NULL DEFAULT 0,
+  `TrailingHigh` float NOT NULL DEFAULT 0,
+  `Reservation` float NOT NULL DEFAULT 0,
+  `Heartbeat` bigint(20) NOT NULL DEFAULT '0',
+  `State` varchar(16) NOT NULL DEFAULT 'RUNNING',
+  `CycleState` varchar(16) NOT NULL DEFAULT 'IDLE',
+  `CycleOrderID` bigint(20) NOT NULL DEFAULT '0',
+  `CycleOrderIDSource` bigint(20) NOT NULL DEFAULT '0',
+  PRIMARY KEY (`ID`),
+  UNIQUE KEY `ThreadID_UNIQUE` (`ThreadID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `session`
+--
+
+LOCK TABLES `session` WRITE;
+/*!40000 ALTER TABLE `session` DISABLE KEYS */;
+/*!40000 ALTER TABLE `session` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `sessionlabel`
+--
+
+DROP TABLE IF EXISTS `sessionlabel`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `sessionlabel` (
+  `ThreadID` varchar(45) NOT NULL,
+  `Name` varchar(45) NOT NULL,
+  `Tags` varchar(255) NOT NULL,
+  `UpdatedTime` bigint(20) NOT NULL,
+  PRIMARY KEY (`ThreadID`),
+  KEY `sessionlabel_idx_name` (`Name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `sessionlabel`
+--
+
+LOCK TABLES `sessionlabel` WRITE;
+/*!40000 ALTER TABLE `sessionlabel` DISABLE KEYS */;
+/*!40000 ALTER TABLE `sessionlabel` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `shadoworder`
+--
+
+DROP TABLE IF EXISTS `shadoworder`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `shadoworder` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Symbol` varchar(45) NOT NULL,
+  `Side` varchar(8) NOT NULL,
+  `Price` double NOT NULL,
+  `Quantity` double NOT NULL,
+  `CumulativeQuoteQuantity` double NOT NULL,
+  `OrderIDSource` bigint(20) NOT NULL DEFAULT '0',
+  `Profit` double NOT NULL DEFAULT '0',
+  `Decision` varchar(255) NOT NULL,
+  `TransactTime` bigint(20) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `shadoworder_idx_threadid` (`ThreadID`,`Side`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `shadoworder`
+--
+
+LOCK TABLES `shadoworder` WRITE;
+/*!40000 ALTER TABLE `shadoworder` DISABLE KEYS */;
+/*!40000 ALTER TABLE `shadoworder` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `symbollist`
+--
+
+DROP TABLE IF EXISTS `symbollist`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `symbollist` (
+  `Symbol` varchar(45) NOT NULL,
+  `List` varchar(45) NOT NULL,
+  PRIMARY KEY (`Symbol`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `symbollist`
+--
+
+LOCK TABLES `symbollist` WRITE;
+/*!40000 ALTER TABLE `symbollist` DISABLE KEYS */;
+/*!40000 ALTER TABLE `symbollist` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `thread`
+--
+
+DROP TABLE IF EXISTS `thread`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `thread` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `ThreadIDSession` varchar(45) NOT NULL,
+  `OrderID` bigint(20) DEFAULT NULL,
+  `CummulativeQuoteQty` float NOT NULL,
+  `Price` float NOT NULL,
+  `ExecutedQuantity` float NOT NULL,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `thread`
+--
+
+LOCK TABLES `thread` WRITE;
+/*!40000 ALTER TABLE `thread` DISABLE KEYS */;
+/*!40000 ALTER TABLE `thread` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `threadcommand`
+--
+
+DROP TABLE IF EXISTS `threadcommand`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `threadcommand` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Command` varchar(45) NOT NULL,
+  `OrderID` bigint(20) NOT NULL DEFAULT '0',
+  `Source` varchar(45) NOT NULL,
+  `CreatedTime` bigint(20) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `threadid` (`ThreadID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `threadcommand`
+--
+
+LOCK TABLES `threadcommand` WRITE;
+/*!40000 ALTER TABLE `threadcommand` DISABLE KEYS */;
+/*!40000 ALTER TABLE `threadcommand` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `threadevent`
+--
+
+DROP TABLE IF EXISTS `threadevent`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `threadevent` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Time` bigint(20) NOT NULL,
+  `Kind` varchar(45) NOT NULL,
+  `Source` varchar(45) NOT NULL,
+  `Message` varchar(255) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `threadevent_idx_threadid_time` (`ThreadID`,`Time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `threadevent`
+--
+
+LOCK TABLES `threadevent` WRITE;
+/*!40000 ALTER TABLE `threadevent` DISABLE KEYS */;
+/*!40000 ALTER TABLE `threadevent` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `user`
+--
+
+DROP TABLE IF EXISTS `user`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `user` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `Username` varchar(45) NOT NULL,
+  `PasswordHash` varchar(255) NOT NULL,
+  `Role` varchar(45) NOT NULL DEFAULT 'viewer',
+  `FailedLogins` int(11) NOT NULL DEFAULT '0',
+  `LockedUntil` bigint(20) NOT NULL DEFAULT '0',
+  `TOTPSecret` varchar(64) NOT NULL DEFAULT '',
+  `TOTPEnabled` tinyint NOT NULL DEFAULT 0,
+  PRIMARY KEY (`ID`),
+  UNIQUE KEY `Username_UNIQUE` (`Username`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `user`
+--
+
+LOCK TABLES `user` WRITE;
+/*!40000 ALTER TABLE `user` DISABLE KEYS */;
+/*!40000 ALTER TABLE `user` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `volatility`
+--
+
+DROP TABLE IF EXISTS `volatility`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `volatility` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Symbol` varchar(45) NOT NULL,
+  `Reason` varchar(45) NOT NULL,
+  `Sigma` float NOT NULL,
+  `Threshold` float NOT NULL,
+  `TransactTime` bigint(20) NOT NULL,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `volatility`
+--
+
+LOCK TABLES `volatility` WRITE;
+/*!40000 ALTER TABLE `volatility` DISABLE KEYS */;
+/*!40000 ALTER TABLE `volatility` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `webhook`
+--
+
+DROP TABLE IF EXISTS `webhook`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `webhook` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `Name` varchar(64) NOT NULL,
+  `URL` varchar(255) NOT NULL,
+  `Events` varchar(255) NOT NULL,
+  `Secret` varchar(64) NOT NULL,
+  `Enabled` tinyint(4) NOT NULL DEFAULT 1,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `webhook`
+--
+
+LOCK TABLES `webhook` WRITE;
+/*!40000 ALTER TABLE `webhook` DISABLE KEYS */;
+/*!40000 ALTER TABLE `webhook` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `wsconnection`
+--
+
+DROP TABLE IF EXISTS `wsconnection`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `wsconnection` (
+  `ID` int(11) NOT NULL AUTO_INCREMENT,
+  `ThreadID` varchar(45) NOT NULL,
+  `Stream` varchar(20) NOT NULL,
+  `ConnectedTime` bigint(20) NOT NULL,
+  `DisconnectedTime` bigint(20) NOT NULL,
+  `Messages` bigint(20) NOT NULL,
+  `Reason` varchar(20) NOT NULL,
+  `Error` varchar(255) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `wsconnection_idx_threadid_disconnectedtime` (`ThreadID`,`DisconnectedTime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `wsconnection`
+--
+
+LOCK TABLES `wsconnection` WRITE;
+/*!40000 ALTER TABLE `wsconnection` DISABLE KEYS */;
+/*!40000 ALTER TABLE `wsconnection` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Dumping routines for database 'cryptopump'
+--
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteAlertRule` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteAlertRule`(IN in_ID int) BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`alertrule` WHERE `ID` = in_ID; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteAuthToken` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteAuthToken`(IN in_TokenHash varchar(64)) BEGIN DELETE FROM `cryptopump`.`authtoken` WHERE `authtoken`.`TokenHash` = in_TokenHash; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteAuthTokenByUsername` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteAuthTokenByUsername`(IN in_Username varchar(45), IN in_Kind varchar(45)) BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`authtoken` WHERE `authtoken`.`Username` = in_Username AND `authtoken`.`Kind` = in_Kind; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteBacktest` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteBacktest`(IN in_ID int) BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`backtestequity` WHERE `BacktestID` = in_ID; DELETE FROM `cryptopump`.`backtest` WHERE `ID` = in_ID; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteBalanceBefore` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteBalanceBefore`(IN in_Account varchar(45), IN in_Time bigint) BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`balance` WHERE `balance`.`Account` = in_Account AND `balance`.`Time` < in_Time; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteExcessAuthTokens` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteExcessAuthTokens`(IN in_Username varchar(45), IN in_Kind varchar(45), IN in_Keep int) BEGIN DELETE FROM `cryptopump`.`authtoken` WHERE `Username` = in_Username AND `Kind` = in_Kind AND `TokenHash` NOT IN (SELECT `TokenHash` FROM (SELECT `TokenHash` FROM `cryptopump`.`authtoken` WHERE `Username` = in_Username AND `Kind` = in_Kind ORDER BY `LastSeen` DESC LIMIT in_Keep) AS `newest`); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteLogBefore` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteLogBefore`(IN in_Time bigint) BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`log` WHERE `Time` < in_Time; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteQueuedNotification` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteQueuedNotification`(IN in_ID int) BEGIN DELETE FROM `cryptopump`.`notificationqueue` WHERE `notificationqueue`.`ID` = in_ID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteRecoveryCodes` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteRecoveryCodes`(IN in_Username varchar(45)) BEGIN DELETE FROM `cryptopump`.`recoverycode` WHERE `Username` = in_Username; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteSession` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteSession`(IN in_ThreadID varchar(45)) BEGIN DECLARE ThreadID varchar(45); SET SQL_SAFE_UPDATES = 0; SET ThreadID = in_ThreadID; DELETE FROM session WHERE session.ThreadID = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteSymbolList` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteSymbolList`() BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`symbollist`; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteThreadCommand` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteThreadCommand`(IN in_ID int) BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`threadcommand` WHERE `ID` = in_ID; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteThreadTransactionAll` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteThreadTransactionAll`() BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM thread; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteThreadTransactionByOrderID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteThreadTransactionByOrderID`(IN in_param_OrderID bigint) BEGIN DECLARE declared_in_param_OrderID bigint; SET SQL_SAFE_UPDATES = 0; SET declared_in_param_OrderID = in_param_OrderID; DELETE FROM thread WHERE thread.OrderID = in_param_OrderID; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `DeleteWebhook` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `DeleteWebhook`(IN in_ID int) BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`webhook` WHERE `ID` = in_ID; SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `ExportMonthlyProfit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `ExportMonthlyProfit`(IN in_From bigint, IN in_To bigint) BEGIN SELECT DATE_FORMAT(FROM_UNIXTIME(`sell`.`TransactTime` DIV 1000), '%Y-%m') AS `Month`, COUNT(*) AS `Trades`, SUM(`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) AS `Profit`, AVG((`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) / NULLIF(`buy`.`CummulativeQuoteQty`, 0)) AS `ProfitPct` FROM `cryptopump`.`orders` `buy` INNER JOIN `cryptopump`.`orders` `sell` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `buy`.`Side` = 'BUY' AND `sell`.`Side` = 'SELL' AND `buy`.`Status` = 'FILLED' AND `sell`.`Status` = 'FILLED' AND `sell`.`TransactTime` >= in_From AND `sell`.`TransactTime` < in_To GROUP BY `Month` ORDER BY `Month`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `ExportThreadProfit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `ExportThreadProfit`(IN in_From bigint, IN in_To bigint) BEGIN SELECT `buy`.`ThreadID`, `buy`.`Symbol`, COUNT(*) AS `Trades`, SUM(`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) AS `Profit`, AVG((`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) / NULLIF(`buy`.`CummulativeQuoteQty`, 0)) AS `ProfitPct` FROM `cryptopump`.`orders` `buy` INNER JOIN `cryptopump`.`orders` `sell` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `buy`.`Side` = 'BUY' AND `sell`.`Side` = 'SELL' AND `buy`.`Status` = 'FILLED' AND `sell`.`Status` = 'FILLED' AND `sell`.`TransactTime` >= in_From AND `sell`.`TransactTime` < in_To GROUP BY `buy`.`ThreadID` , `buy`.`Symbol` ORDER BY `Profit` DESC; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `ExportTrades` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `ExportTrades`(IN in_From bigint, IN in_To bigint) BEGIN SELECT `orders`.`TransactTime`, `orders`.`ThreadID`, `orders`.`Symbol`, `orders`.`Side`, `orders`.`OrderID`, `orders`.`OrderIDSource`, `orders`.`Price`, `orders`.`ExecutedQuantity`, `orders`.`CummulativeQuoteQty`, `orders`.`Source`, IFNULL(`orders`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`, 0) AS `Profit` FROM `cryptopump`.`orders` LEFT JOIN `cryptopump`.`orders` `buy` ON `orders`.`Side` = 'SELL' AND `buy`.`OrderID` = `orders`.`OrderIDSource` AND `buy`.`Side` = 'BUY' WHERE `orders`.`Status` = 'FILLED' AND `orders`.`TransactTime` >= in_From AND `orders`.`TransactTime` < in_To ORDER BY `orders`.`TransactTime`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetAlertRules` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetAlertRules`() BEGIN SELECT `ID`, `Name`, `ThreadID`, `Metric`, `Operator`, `Threshold`, `Channel`, `Target` FROM `cryptopump`.`alertrule` ORDER BY `ID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetAssetPrices` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetAssetPrices`() BEGIN SELECT `assetprice`.`Asset`, `assetprice`.`Price` FROM `cryptopump`.`assetprice`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetAudit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetAudit`(IN in_ID bigint, IN in_Limit int) BEGIN SELECT `ID`, `Time`, `ThreadID`, `Event`, `OrderID`, `Payload`, `PrevHash`, `Hash` FROM `cryptopump`.`audit` WHERE `ID` > in_ID ORDER BY `ID` LIMIT in_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetAuthToken` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetAuthToken`(IN in_TokenHash varchar(64)) BEGIN SELECT `authtoken`.`Username`, `user`.`Role`, `authtoken`.`Kind`, `authtoken`.`Expires`, `authtoken`.`LastSeen` FROM `cryptopump`.`authtoken` INNER JOIN `cryptopump`.`user` ON `user`.`Username` = `authtoken`.`Username` WHERE `authtoken`.`TokenHash` = in_TokenHash; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetBacktestEquity` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetBacktestEquity`(IN in_BacktestID int) BEGIN SELECT `Time`, `Equity`, `BuyHold` FROM `cryptopump`.`backtestequity` WHERE `BacktestID` = in_BacktestID ORDER BY `Time`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetBacktests` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetBacktests`() BEGIN SELECT `ID`, `Name`, `Symbol`, `StartTime`, `EndTime`, `CreatedTime`, `Parameters`, `Trades`, `ProfitPct`, `BuyHoldPct`, `MaxDrawdownPct`, `WinRatePct` FROM `cryptopump`.`backtest` ORDER BY `CreatedTime` DESC; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetBalances` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetBalances`() BEGIN SELECT `balance`.`Account`, `balance`.`Asset`, `balance`.`Free`, `balance`.`Locked`, `balance`.`Time` FROM `cryptopump`.`balance` ORDER BY `balance`.`Account`, `balance`.`Asset`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetConfigAudit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetConfigAudit`(IN in_param_ThreadID varchar(45), IN in_param_Limit int) BEGIN SELECT `configaudit`.`Version`, `configaudit`.`Username`, `configaudit`.`Time`, `configaudit`.`Changes` FROM `cryptopump`.`configaudit` WHERE `configaudit`.`ThreadID` = in_param_ThreadID ORDER BY `configaudit`.`Version` DESC LIMIT in_param_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetEquitySince` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetEquitySince`(IN in_param_Time bigint) BEGIN SELECT `equity`.`Time`, `equity`.`Equity` FROM `cryptopump`.`equity` WHERE `equity`.`Time` >= in_param_Time ORDER BY `equity`.`Time` ASC; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetGlobal` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetGlobal`() BEGIN SELECT `global`.`Profit` AS `Profit`, `global`.`ProfitNet` AS `ProfitNet`, `global`.`ProfitPct` AS `ProfitPct`, `global`.`TransactTime` AS `TransactTime` FROM `global` WHERE `global`.`ID` = 1 LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetGlobalDrawdown` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetGlobalDrawdown`() BEGIN SELECT `global`.`EquityPeak` AS `EquityPeak`, `global`.`DrawdownHalt` AS `DrawdownHalt` FROM `global` WHERE `global`.`ID` = 1 LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetGlobalEquity` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetGlobalEquity`() BEGIN SELECT (SELECT IFNULL(MAX(`session`.`FiatFunds`), 0) FROM `session`) + (SELECT IFNULL(SUM(`thread`.`CummulativeQuoteQty`), 0) FROM `thread`) + (SELECT IFNULL(SUM(`session`.`DiffTotal`), 0) FROM `session`) AS `Equity`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetGlobalLiquidate` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetGlobalLiquidate`() BEGIN SELECT `global`.`Liquidate` AS `Liquidate`, `global`.`LiquidateTime` AS `LiquidateTime` FROM `global` WHERE `global`.`ID` = 1 LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetKline` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetKline`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `kline`.`CloseTime`, `kline`.`Open`, `kline`.`Close`, `kline`.`Low`, `kline`.`High`, `kline`.`Volume`, `kline`.`Ma7`, `kline`.`Ma14` FROM `cryptopump`.`kline` WHERE `kline`.`ThreadID` = in_param_ThreadID ORDER BY `kline`.`OpenTime` ASC; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetLastOrderTransactionPrice` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetLastOrderTransactionPrice`(IN in_param_ThreadID varchar(45), IN in_param_Side varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); DECLARE declared_in_param_Side CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Side = in_param_Side; SELECT `orders`.`Price` AS `Price` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND `orders`.`Side` = declared_in_param_Side AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetLastOrderTransactionSide` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetLastOrderTransactionSide`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `orders`.`Side` AS `Side` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND `orders`.`Status` = 'FILLED') ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetLogs` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetLogs`(IN in_ThreadID varchar(45), IN in_Level varchar(45), IN in_Component varchar(45), IN in_Text varchar(255), IN in_From bigint, IN in_To bigint, IN in_Limit int) BEGIN SELECT `log`.`ID`, `log`.`Time`, `log`.`Level`, `log`.`ThreadID`, `log`.`Component`, `log`.`Message` FROM `cryptopump`.`log` WHERE (in_ThreadID = '' OR `log`.`ThreadID` = in_ThreadID OR `log`.`ThreadID` IN (SELECT `sessionlabel`.`ThreadID` FROM `cryptopump`.`sessionlabel` WHERE `sessionlabel`.`Name` = in_ThreadID OR FIND_IN_SET(in_ThreadID, `sessionlabel`.`Tags`) > 0)) AND (in_Level = '' OR `log`.`Level` = in_Level) AND (in_Component = '' OR `log`.`Component` = in_Component) AND (in_Text = '' OR LOCATE(in_Text, `log`.`Message`) > 0) AND (in_From = 0 OR `log`.`Time` >= in_From) AND (in_To = 0 OR `log`.`Time` <= in_To) ORDER BY `log`.`Time` DESC, `log`.`ID` DESC LIMIT in_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetNotes` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetNotes`(IN in_param_Tag varchar(45), IN in_param_Limit int) BEGIN SELECT `note`.`ID`, `note`.`ThreadID`, `note`.`OrderID`, `note`.`Username`, `note`.`Time`, `note`.`Tags`, `note`.`Text`, IFNULL(`orders`.`Symbol`, '') AS `Symbol`, IFNULL(`orders`.`Side`, '') AS `Side`, IFNULL(`orders`.`Price`, 0) AS `Price`, IFNULL(`orders`.`ExecutedQuantity`, 0) AS `ExecutedQuantity` FROM `cryptopump`.`note` LEFT JOIN `cryptopump`.`orders` ON `note`.`OrderID` = `orders`.`OrderID` WHERE in_param_Tag = '' OR FIND_IN_SET(in_param_Tag, `note`.`Tags`) > 0 ORDER BY `note`.`Time` DESC LIMIT in_param_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOpenPositions` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOpenPositions`() BEGIN SELECT `thread`.`ThreadID`, `session`.`Exchange`, `orders`.`Symbol`, `session`.`FiatSymbol`, SUM(`thread`.`ExecutedQuantity`) AS `Quantity`, SUM(`thread`.`CummulativeQuoteQty`) AS `Cost` FROM `cryptopump`.`thread` INNER JOIN `cryptopump`.`orders` ON `thread`.`OrderID` = `orders`.`OrderID` INNER JOIN `cryptopump`.`session` ON `thread`.`ThreadID` = `session`.`ThreadID` GROUP BY `thread`.`ThreadID`, `session`.`Exchange`, `orders`.`Symbol`, `session`.`FiatSymbol` ORDER BY `thread`.`ThreadID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderByOrderID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderByOrderID`(IN in_param_OrderID bigint, IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_orderid BIGINT; DECLARE declared_in_param_threadid CHAR(50); SET declared_in_param_orderid = in_param_orderid; SET declared_in_param_threadid = in_param_threadid; SELECT `orders`.`orderid` AS `OrderID`, `orders`.`price` AS `Price`, `orders`.`executedquantity` AS `ExecutedQuantity`, `orders`.`cummulativequoteqty` AS `CummulativeQuoteQty`, `orders`.`transacttime` AS `TransactTime` FROM `orders` WHERE (`orders`.`orderid` = declared_in_param_orderid AND `orders`.`threadid` = declared_in_param_threadid) LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderCorrelationID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderCorrelationID`(IN in_OrderID bigint) BEGIN SELECT `CorrelationID` FROM `cryptopump`.`orders` WHERE `OrderID` = in_OrderID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderCount`(IN in_ThreadID varchar(45), IN in_Symbol varchar(45), IN in_Side varchar(45), IN in_Status varchar(45), IN in_Search varchar(45)) BEGIN SELECT COUNT(*) AS `Count` FROM `cryptopump`.`orders` WHERE (in_ThreadID = '' OR `orders`.`ThreadID` = in_ThreadID OR `orders`.`ThreadID` IN (SELECT `sessionlabel`.`ThreadID` FROM `cryptopump`.`sessionlabel` WHERE `sessionlabel`.`Name` = in_ThreadID OR FIND_IN_SET(in_ThreadID, `sessionlabel`.`Tags`) > 0)) AND (in_Symbol = '' OR `orders`.`Symbol` = in_Symbol) AND (in_Side = '' OR `orders`.`Side` = in_Side) AND (in_Status = '' OR `orders`.`Status` = in_Status) AND (in_Search = '' OR CAST(`orders`.`OrderID` AS CHAR) LIKE CONCAT('%', in_Search, '%') OR `orders`.`ClientOrderId` LIKE CONCAT('%', in_Search, '%') OR `orders`.`Symbol` LIKE CONCAT('%', in_Search, '%') OR `orders`.`ThreadID` LIKE CONCAT('%', in_Search, '%') OR `orders`.`ThreadID` IN (SELECT `sessionlabel`.`ThreadID` FROM `cryptopump`.`sessionlabel` WHERE `sessionlabel`.`Name` LIKE CONCAT('%', in_Search, '%') OR `sessionlabel`.`Tags` LIKE CONCAT('%', in_Search, '%')) OR `orders`.`Source` LIKE CONCAT('%', in_Search, '%')); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrders` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrders`(IN in_ThreadID varchar(45), IN in_Symbol varchar(45), IN in_Side varchar(45), IN in_Status varchar(45), IN in_Search varchar(45), IN in_Sort varchar(45), IN in_Descending tinyint, IN in_Limit int, IN in_Offset int) BEGIN SELECT `orders`.`TransactTime`, `orders`.`ThreadID`, `orders`.`Symbol`, `orders`.`Side`, `orders`.`Status`, `orders`.`OrderID`, `orders`.`OrderIDSource`, `orders`.`Price`, `orders`.`ExecutedQuantity`, `orders`.`CummulativeQuoteQty`, `orders`.`Source` FROM `cryptopump`.`orders` WHERE (in_ThreadID = '' OR `orders`.`ThreadID` = in_ThreadID OR `orders`.`ThreadID` IN (SELECT `sessionlabel`.`ThreadID` FROM `cryptopump`.`sessionlabel` WHERE `sessionlabel`.`Name` = in_ThreadID OR FIND_IN_SET(in_ThreadID, `sessionlabel`.`Tags`) > 0)) AND (in_Symbol = '' OR `orders`.`Symbol` = in_Symbol) AND (in_Side = '' OR `orders`.`Side` = in_Side) AND (in_Status = '' OR `orders`.`Status` = in_Status) AND (in_Search = '' OR CAST(`orders`.`OrderID` AS CHAR) LIKE CONCAT('%', in_Search, '%') OR `orders`.`ClientOrderId` LIKE CONCAT('%', in_Search, '%') OR `orders`.`Symbol` LIKE CONCAT('%', in_Search, '%') OR `orders`.`ThreadID` LIKE CONCAT('%', in_Search, '%') OR `orders`.`ThreadID` IN (SELECT `sessionlabel`.`ThreadID` FROM `cryptopump`.`sessionlabel` WHERE `sessionlabel`.`Name` LIKE CONCAT('%', in_Search, '%') OR `sessionlabel`.`Tags` LIKE CONCAT('%', in_Search, '%')) OR `orders`.`Source` LIKE CONCAT('%', in_Search, '%')) ORDER BY IF(in_Descending = 0, CASE in_Sort WHEN 'TransactTime' THEN `orders`.`TransactTime` WHEN 'OrderID' THEN `orders`.`OrderID` WHEN 'Price' THEN `orders`.`Price` WHEN 'Quantity' THEN `orders`.`ExecutedQuantity` WHEN 'Quote' THEN `orders`.`CummulativeQuoteQty` END, NULL) ASC, IF(in_Descending = 0, CASE in_Sort WHEN 'ThreadID' THEN `orders`.`ThreadID` WHEN 'Symbol' THEN `orders`.`Symbol` WHEN 'Side' THEN `orders`.`Side` WHEN 'Status' THEN `orders`.`Status` WHEN 'Source' THEN `orders`.`Source` END, NULL) ASC, IF(in_Descending = 1, CASE in_Sort WHEN 'TransactTime' THEN `orders`.`TransactTime` WHEN 'OrderID' THEN `orders`.`OrderID` WHEN 'Price' THEN `orders`.`Price` WHEN 'Quantity' THEN `orders`.`ExecutedQuantity` WHEN 'Quote' THEN `orders`.`CummulativeQuoteQty` END, NULL) DESC, IF(in_Descending = 1, CASE in_Sort WHEN 'ThreadID' THEN `orders`.`ThreadID` WHEN 'Symbol' THEN `orders`.`Symbol` WHEN 'Side' THEN `orders`.`Side` WHEN 'Status' THEN `orders`.`Status` WHEN 'Source' THEN `orders`.`Source` END, NULL) DESC, `orders`.`TransactTime` DESC, `orders`.`OrderID` DESC LIMIT in_Limit OFFSET in_Offset; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderSymbol` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderSymbol`(IN in_param varchar(45)) BEGIN DECLARE declared_in_param CHAR(45); SET declared_in_param = in_param; SELECT Symbol from orders WHERE orders.ThreadID = declared_in_param ORDER BY TransactTime DESC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionCount`(IN in_param_ThreadID varchar(45), IN in_param_Side varchar(45), IN in_param_Minutes int) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); DECLARE declared_in_param_Side CHAR(45); DECLARE declared_in_param_Minutes int; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Side = in_param_Side; SET declared_in_param_Minutes = in_param_Minutes; SELECT COALESCE(count(*),0) AS `count` FROM `orders` WHERE (`orders`.`Side` = declared_in_param_Side AND `orders`.`Status` = 'FILLED' AND str_to_date(date_format(CAST(from_unixtime((`orders`.`TransactTime` / 1000)) AS DATETIME), '%Y-%m-%d %H:%i'), '%Y-%m-%d %H:%i') BETWEEN str_to_date(date_format(CAST(date_add(now(6), INTERVAL declared_in_param_Minutes minute) AS DATETIME), '%Y-%m-%d %H:%i'), '%Y-%m-%d %H:%i') AND str_to_date(date_format(CAST(now(6) AS DATETIME), '%Y-%m-%d %H:%i'), '%Y-%m-%d %H:%i') AND `orders`.`ThreadID` = declared_in_param_ThreadID); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionPending` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionPending`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `orders`.`OrderID` AS `OrderID`, `orders`.`Symbol` AS `Symbol` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND (`orders`.`Status` <> 'FILLED' OR `orders`.`Status` IS NULL) AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL) AND `orders`.`Status` IS NOT NULL AND (`orders`.`Status` <> '' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) ASC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionSideLastTwo` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionSideLastTwo`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `A`.`Side` AS `Last`, `B`.`Side` AS `SecondLast` FROM ( (SELECT `orders`.`Side` AS `Side` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1) A INNER JOIN (SELECT `orders`.`Side` AS `Side` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1,1) B ); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionTimeByOrderID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionTimeByOrderID`(IN in_param_OrderID bigint) BEGIN DECLARE declared_in_param_OrderID bigint; SET declared_in_param_OrderID = in_param_OrderID; SELECT `orders`.`TransactTime` AS `TransactTime` FROM `orders` WHERE `orders`.`OrderID` = declared_in_param_OrderID LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetPendingAction` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetPendingAction`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `pendingaction`.`ID`, `pendingaction`.`Action`, `pendingaction`.`OrderID`, `pendingaction`.`Notional`, `pendingaction`.`CreatedTime` FROM `cryptopump`.`pendingaction` WHERE `pendingaction`.`ThreadID` = in_param_ThreadID AND `pendingaction`.`Status` = 'PENDING' ORDER BY `pendingaction`.`ID` DESC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetPendingActions` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetPendingActions`() BEGIN SELECT `pendingaction`.`ID`, `pendingaction`.`ThreadID`, `pendingaction`.`Action`, `pendingaction`.`OrderID`, `pendingaction`.`Notional`, `pendingaction`.`CreatedTime` FROM `cryptopump`.`pendingaction` WHERE `pendingaction`.`Status` = 'PENDING' ORDER BY `pendingaction`.`ID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetPnlSince` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetPnlSince`(IN in_Since bigint) BEGIN SELECT `pnl`.`Time`, `pnl`.`ThreadID`, `pnl`.`Realized`, `pnl`.`Unrealized` FROM `cryptopump`.`pnl` WHERE `pnl`.`Time` >= in_Since ORDER BY `pnl`.`Time`, `pnl`.`ThreadID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetPreference` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetPreference`(IN in_param_Username varchar(45)) BEGIN SELECT `preference`.`Theme`, `preference`.`RefreshInterval`, `preference`.`Currency`, `preference`.`Columns`, `preference`.`Language` FROM `cryptopump`.`preference` WHERE `preference`.`Username` = in_param_Username; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetPreset` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetPreset`(IN in_param_Name varchar(64)) BEGIN SELECT `preset`.`Config` FROM `cryptopump`.`preset` WHERE `preset`.`Name` = in_param_Name; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetPresetNames` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetPresetNames`() BEGIN SELECT `preset`.`Name` FROM `cryptopump`.`preset` ORDER BY `preset`.`Name`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetProfit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetProfit`() BEGIN SELECT SUM(`source`.`Profit`) AS `profit`, SUM(`source`.`Profit`) + (`source`.`Diff`) AS `netprofit`, AVG(`source`.`Percentage`) AS `avg` FROM (SELECT `orders`.`Side` AS `Side`, `Orders`.`Side` AS `Orders__Side`, `orders`.`Status` AS `Status`, `Orders`.`Status` AS `Orders__Status`, `orders`.`ThreadID` AS `ThreadID`, `Orders`.`CummulativeQuoteQty` AS `Orders__CummulativeQuoteQty`, `orders`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, (`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) AS `Profit`, ((`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) / CASE WHEN `Orders`.`CummulativeQuoteQty` = 0 THEN NULL ELSE `Orders`.`CummulativeQuoteQty` END) AS `Percentage`, (SELECT sum(`session`.`DiffTotal`) AS `sum` FROM `session`) AS `Diff` FROM `orders` INNER JOIN `orders` `Orders` ON `orders`.`OrderID` = `Orders`.`OrderIDSource` WHERE ( `orders`.`Side` = 'BUY' ) AND ( `orders`.`Status` = 'FILLED' ) ) `source` WHERE ( 1 = 1 AND `source`.`Orders__Side` = 'SELL' AND 1 = 1 AND `source`.`Orders__Status` = 'FILLED' ); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetProfitByThreadID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetProfitByThreadID`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT SUM(`source`.`Profit`) + (`source`.`Diff`) AS `sum`, AVG(`source`.`Percentage`) AS `avg` FROM (SELECT `orders`.`Side` AS `Side`, `Orders`.`Side` AS `Orders__Side`, `orders`.`Status` AS `Status`, `Orders`.`Status` AS `Orders__Status`, `orders`.`ThreadID` AS `ThreadID`, `Orders`.`CummulativeQuoteQty` AS `Orders__CummulativeQuoteQty`, `orders`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, (`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) AS `Profit`, ((`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) / CASE WHEN `Orders`.`CummulativeQuoteQty` = 0 THEN NULL ELSE `Orders`.`CummulativeQuoteQty` END) AS `Percentage`, (SELECT SUM(`session`.`DiffTotal`) AS `sum` FROM `session` WHERE `session`.`ThreadID` = declared_in_param_ThreadID) AS `Diff` FROM `orders` INNER JOIN `orders` `Orders` ON `orders`.`OrderID` = `Orders`.`OrderIDSource`) `source` WHERE (`source`.`Side` = 'BUY' AND `source`.`Orders__Side` = 'SELL' AND `source`.`Status` = 'FILLED' AND `source`.`Orders__Status` = 'FILLED' AND `source`.`ThreadID` = declared_in_param_ThreadID); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetProfitSince` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetProfitSince`(IN in_param_TransactTime bigint) BEGIN SELECT SUM(`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) AS `Profit` FROM `orders` `buy` INNER JOIN `orders` `sell` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `buy`.`Side` = 'BUY' AND `sell`.`Side` = 'SELL' AND `buy`.`Status` = 'FILLED' AND `sell`.`Status` = 'FILLED' AND `sell`.`TransactTime` >= in_param_TransactTime; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetQueuedNotifications` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetQueuedNotifications`(IN in_Time bigint, IN in_Limit int) BEGIN SELECT `notificationqueue`.`ID`, `notificationqueue`.`ThreadID`, `notificationqueue`.`Channel`, `notificationqueue`.`Text`, `notificationqueue`.`Attempts`, `notificationqueue`.`NextAttempt`, `notificationqueue`.`LastError`, `notificationqueue`.`CreatedTime` FROM `cryptopump`.`notificationqueue` WHERE `notificationqueue`.`NextAttempt` <= in_Time ORDER BY `notificationqueue`.`ID` LIMIT in_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionAllocations` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionAllocations`(IN in_param_TransactTime bigint) BEGIN SELECT `session`.`ThreadID`, IFNULL((SELECT `orders`.`Symbol` FROM `cryptopump`.`orders` WHERE `orders`.`ThreadID` = `session`.`ThreadID` ORDER BY `orders`.`TransactTime` DESC LIMIT 1), '') AS `Symbol`, `session`.`Reservation`, IFNULL(`amount`.`sum`, 0) AS `Amount`, IFNULL(`profit`.`Profit`, 0) AS `Profit`, IFNULL(`profit`.`Cost`, 0) AS `Cost` FROM `cryptopump`.`session` LEFT JOIN (SELECT `thread`.`ThreadID` AS `ThreadID`, SUM(`thread`.`CummulativeQuoteQty`) AS `sum` FROM `cryptopump`.`thread` GROUP BY `thread`.`ThreadID`) AS `amount` ON `amount`.`ThreadID` = `session`.`ThreadID` LEFT JOIN (SELECT `buy`.`ThreadID` AS `ThreadID`, SUM(`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) AS `Profit`, SUM(`buy`.`CummulativeQuoteQty`) AS `Cost` FROM `cryptopump`.`orders` `buy` INNER JOIN `cryptopump`.`orders` `sell` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `buy`.`Side` = 'BUY' AND `sell`.`Side` = 'SELL' AND `buy`.`Status` = 'FILLED' AND `sell`.`Status` = 'FILLED' AND `sell`.`TransactTime` >= in_param_TransactTime GROUP BY `buy`.`ThreadID`) AS `profit` ON `profit`.`ThreadID` = `session`.`ThreadID` WHERE `session`.`State` = 'RUNNING' ORDER BY `session`.`ThreadID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionCooldown` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionCooldown`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `session`.`CooldownStart` AS `CooldownStart`, `session`.`CooldownUntil` AS `CooldownUntil` FROM `session` WHERE `session`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionCount`() BEGIN SELECT COUNT(*) AS `count` FROM `cryptopump`.`session` WHERE `session`.`State` = 'RUNNING'; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionCycle` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionCycle`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `session`.`CycleState` AS `CycleState`, `session`.`CycleOrderID` AS `CycleOrderID`, `session`.`CycleOrderIDSource` AS `CycleOrderIDSource` FROM `session` WHERE `session`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionHeartbeats` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionHeartbeats`() BEGIN SELECT `session`.`ThreadID`, `session`.`Heartbeat` FROM `cryptopump`.`session` WHERE `session`.`State` = 'RUNNING'; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionLabels` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionLabels`() BEGIN SELECT `sessionlabel`.`ThreadID`, `sessionlabel`.`Name`, `sessionlabel`.`Tags` FROM `cryptopump`.`sessionlabel` ORDER BY `sessionlabel`.`Name`, `sessionlabel`.`ThreadID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionPaused` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionPaused`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `session`.`Paused` AS `Paused` FROM `session` WHERE `session`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionReservation` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionReservation`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `session`.`Reservation` AS `Reservation` FROM `session` WHERE `session`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionReservedFunds` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionReservedFunds`(IN in_param_ThreadID varchar(45)) BEGIN SELECT SUM(GREATEST(`session`.`Reservation` - IFNULL(`amount`.`sum`, 0), 0)) AS `sum` FROM `cryptopump`.`session` LEFT JOIN (SELECT `thread`.`ThreadID` AS `ThreadID`, SUM(`thread`.`CummulativeQuoteQty`) AS `sum` FROM `cryptopump`.`thread` GROUP BY `thread`.`ThreadID`) AS `amount` ON `amount`.`ThreadID` = `session`.`ThreadID` WHERE `session`.`ThreadID` <> in_param_ThreadID AND `session`.`Reservation` > 0 AND `session`.`State` = 'RUNNING'; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessions` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessions`() BEGIN SELECT `session`.`ThreadID`, `session`.`ThreadIDSession`, `session`.`Exchange`, `session`.`FiatSymbol`, `session`.`FiatFunds`, `session`.`DiffTotal`, `session`.`Status`, IFNULL(`sessionlabel`.`Name`, '') AS `Name`, IFNULL(`sessionlabel`.`Tags`, '') AS `Tags` FROM `cryptopump`.`session` LEFT JOIN `cryptopump`.`sessionlabel` ON `sessionlabel`.`ThreadID` = `session`.`ThreadID` WHERE `session`.`State` = 'RUNNING'; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionStatus` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionStatus`() BEGIN SELECT `session`.`ThreadID` AS `ThreadID`, `session`.`Status` AS `Status` FROM cryptopump.session WHERE `session`.`Status` = 1 AND `session`.`State` = 'RUNNING'; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionStopPrice` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionStopPrice`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `session`.`StopPrice` AS `StopPrice` FROM `session` WHERE `session`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSessionTrailingHigh` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionTrailingHigh`(IN in_param_ThreadID varchar(45)) BEGIN SELECT `session`.`TrailingHigh` AS `TrailingHigh` FROM `session` WHERE `session`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetShadowOpenOrders` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetShadowOpenOrders`(IN in_ThreadID varchar(45)) BEGIN SELECT `b`.`ID`, `b`.`Symbol`, `b`.`Side`, `b`.`Price`, `b`.`Quantity`, `b`.`CumulativeQuoteQuantity`, `b`.`OrderIDSource`, `b`.`Profit`, `b`.`Decision`, `b`.`TransactTime` FROM `cryptopump`.`shadoworder` `b` WHERE `b`.`ThreadID` = in_ThreadID AND `b`.`Side` = 'BUY' AND NOT EXISTS (SELECT 1 FROM `cryptopump`.`shadoworder` `s` WHERE `s`.`ThreadID` = `b`.`ThreadID` AND `s`.`Side` = 'SELL' AND `s`.`OrderIDSource` = `b`.`ID`) ORDER BY `b`.`Price` ASC; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetShadowOrders` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetShadowOrders`(IN in_ThreadID varchar(45), IN in_Limit int) BEGIN SELECT `shadoworder`.`ID`, `shadoworder`.`Symbol`, `shadoworder`.`Side`, `shadoworder`.`Price`, `shadoworder`.`Quantity`, `shadoworder`.`CumulativeQuoteQuantity`, `shadoworder`.`OrderIDSource`, `shadoworder`.`Profit`, `shadoworder`.`Decision`, `shadoworder`.`TransactTime` FROM `cryptopump`.`shadoworder` WHERE `shadoworder`.`ThreadID` = in_ThreadID ORDER BY `shadoworder`.`ID` DESC LIMIT in_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetSymbolList` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetSymbolList`() BEGIN SELECT `symbollist`.`Symbol`, `symbollist`.`List` FROM `cryptopump`.`symbollist`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadAverageEntry` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadAverageEntry`(IN in_param_ThreadID varchar(45)) BEGIN SELECT SUM(`thread`.`CummulativeQuoteQty`) / SUM(`thread`.`ExecutedQuantity`) AS `AverageEntry` FROM `thread` WHERE `thread`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadCommands` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadCommands`(IN in_ThreadID varchar(45)) BEGIN SELECT `threadcommand`.`ID`, `threadcommand`.`ThreadID`, `threadcommand`.`Command`, `threadcommand`.`OrderID`, `threadcommand`.`Source`, `threadcommand`.`CreatedTime` FROM `cryptopump`.`threadcommand` WHERE `threadcommand`.`ThreadID` = in_ThreadID ORDER BY `threadcommand`.`ID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadCount`() BEGIN SELECT COUNT(DISTINCT `session`.`ThreadID`) AS `count` FROM `cryptopump`.`session`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadCycleCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadCycleCount`(IN in_param_ThreadID varchar(45)) BEGIN SELECT COUNT(*) AS `Count` FROM `orders` `buy` INNER JOIN `orders` `sell` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `sell`.`ThreadID` = in_param_ThreadID AND `buy`.`Side` = 'BUY' AND `sell`.`Side` = 'SELL' AND `buy`.`Status` = 'FILLED' AND `sell`.`Status` = 'FILLED'; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadCycleProfitLast` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadCycleProfitLast`(IN in_param_ThreadID varchar(45), IN in_param_Limit int) BEGIN SELECT (`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) AS `Profit`, `sell`.`TransactTime` AS `TransactTime` FROM `orders` `buy` INNER JOIN `orders` `sell` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `buy`.`Side` = 'BUY' AND `sell`.`Side` = 'SELL' AND `buy`.`Status` = 'FILLED' AND `sell`.`Status` = 'FILLED' AND `sell`.`ThreadID` = in_param_ThreadID ORDER BY `sell`.`TransactTime` DESC LIMIT in_param_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadCycles` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadCycles`(IN in_param_ThreadID varchar(45), IN in_param_Limit int, IN in_param_Offset int) BEGIN SELECT `buy`.`OrderID` AS `BuyOrderID`, `sell`.`OrderID` AS `SellOrderID`, `sell`.`ExecutedQuantity` AS `Quantity`, `buy`.`Price` AS `BuyPrice`, `sell`.`Price` AS `SellPrice`, `buy`.`CummulativeQuoteQty` AS `BuyQuote`, `sell`.`CummulativeQuoteQty` AS `SellQuote`, `sell`.`TransactTime` AS `TransactTime` FROM `orders` `buy` INNER JOIN `orders` `sell` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `sell`.`ThreadID` = in_param_ThreadID AND `buy`.`Side` = 'BUY' AND `sell`.`Side` = 'SELL' AND `buy`.`Status` = 'FILLED' AND `sell`.`Status` = 'FILLED' ORDER BY `sell`.`TransactTime` DESC LIMIT in_param_Limit OFFSET in_param_Offset; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadIDByOrderID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadIDByOrderID`(IN in_OrderID bigint) BEGIN SELECT `thread`.`ThreadID` FROM `cryptopump`.`thread` WHERE `thread`.`OrderID` = in_OrderID LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadLastOrderTime` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadLastOrderTime`(IN in_param_ThreadID varchar(45)) BEGIN SELECT IFNULL(MAX(`orders`.`TransactTime`), 0) AS `TransactTime` FROM `orders` WHERE `orders`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadLastTransaction` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadLastTransaction`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`OrderID` AS `OrderID`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity`, `Orders`.`TransactTime` AS `TransactTime` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE (`thread`.`ThreadID` = declared_in_param_ThreadID) ORDER BY `thread`.`Price` ASC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadOrdersSince` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadOrdersSince`(IN in_param_ThreadID varchar(45), IN in_param_TransactTime bigint) BEGIN SELECT `orders`.`OrderID`, `orders`.`Price`, `orders`.`Side`, `orders`.`TransactTime` FROM `cryptopump`.`orders` WHERE `orders`.`ThreadID` = in_param_ThreadID AND `orders`.`TransactTime` >= in_param_TransactTime AND `orders`.`Status` IN ('FILLED', 'PARTIALLY_FILLED') ORDER BY `orders`.`TransactTime` ASC; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadSymbolExposure` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadSymbolExposure`() BEGIN SELECT `orders`.`Symbol` AS `Symbol`, SUM(`thread`.`CummulativeQuoteQty`) AS `sum` FROM `cryptopump`.`thread` INNER JOIN `cryptopump`.`orders` ON `thread`.`OrderID` = `orders`.`OrderID` GROUP BY `orders`.`Symbol`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadSymbolQuantity` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadSymbolQuantity`(IN in_Symbol varchar(45)) BEGIN SELECT SUM(`thread`.`ExecutedQuantity`) AS `sum` FROM `cryptopump`.`thread` INNER JOIN `cryptopump`.`orders` ON `thread`.`OrderID` = `orders`.`OrderID` WHERE `orders`.`Symbol` = in_Symbol; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTimeline` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTimeline`(IN in_ThreadID varchar(45), IN in_From bigint, IN in_To bigint, IN in_Limit int) BEGIN SELECT * FROM ( SELECT `orders`.`TransactTime` AS `Time`, LOWER(`orders`.`Side`) AS `Kind`, `orders`.`Source` AS `Source`, `orders`.`OrderID` AS `OrderID`, CONCAT(`orders`.`Status`, ' ', `orders`.`ExecutedQuantity`, ' ', `orders`.`Symbol`, ' at ', `orders`.`Price`, ' for ', `orders`.`CummulativeQuoteQty`) AS `Detail` FROM `cryptopump`.`orders` WHERE `orders`.`ThreadID` = in_ThreadID AND `orders`.`TransactTime` BETWEEN in_From AND in_To UNION ALL SELECT `configaudit`.`Time`, 'config', `configaudit`.`Username`, 0, CONCAT('Version ', `configaudit`.`Version`, ': ', `configaudit`.`Changes`) FROM `cryptopump`.`configaudit` WHERE `configaudit`.`ThreadID` = in_ThreadID AND `configaudit`.`Time` BETWEEN in_From AND in_To UNION ALL SELECT `threadevent`.`Time`, `threadevent`.`Kind`, `threadevent`.`Source`, 0, `threadevent`.`Message` FROM `cryptopump`.`threadevent` WHERE `threadevent`.`ThreadID` = in_ThreadID AND `threadevent`.`Time` BETWEEN in_From AND in_To UNION ALL SELECT `note`.`Time`, 'note', `note`.`Username`, `note`.`OrderID`, `note`.`Text` FROM `cryptopump`.`note` WHERE `note`.`ThreadID` = in_ThreadID AND `note`.`Time` BETWEEN in_From AND in_To UNION ALL SELECT `pendingaction`.`CreatedTime`, 'approval', `pendingaction`.`Status`, `pendingaction`.`OrderID`, CONCAT(`pendingaction`.`Action`, ' requested for ', `pendingaction`.`Notional`) FROM `cryptopump`.`pendingaction` WHERE `pendingaction`.`ThreadID` = in_ThreadID AND `pendingaction`.`CreatedTime` BETWEEN in_From AND in_To UNION ALL SELECT `liquidation`.`StartTime`, 'liquidation', 'liquidation', 0, CONCAT(`liquidation`.`Orders`, ' orders sold ', `liquidation`.`ExecutedQuantity`, ' ', `liquidation`.`Symbol`, ' for ', `liquidation`.`CummulativeQuoteQty`) FROM `cryptopump`.`liquidation` WHERE `liquidation`.`ThreadID` = in_ThreadID AND `liquidation`.`StartTime` BETWEEN in_From AND in_To ) AS `timeline` ORDER BY `timeline`.`Time` LIMIT in_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionAmount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionAmount`() BEGIN SELECT SUM(`thread`.`CummulativeQuoteQty`) AS `sum` FROM `cryptopump`.`thread`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionAmountByThreadID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionAmountByThreadID`(in_param_ThreadID varchar(45)) BEGIN SELECT SUM(`thread`.`CummulativeQuoteQty`) AS `sum` FROM `cryptopump`.`thread` WHERE `thread`.`ThreadID` = in_param_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionByPrice` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByPrice`(IN in_param_ThreadID varchar(45), IN in_param_Price float) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); DECLARE declared_in_param_Price FLOAT; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Price = in_param_Price; SELECT `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`OrderID` AS `OrderID`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity`, `Orders`.`TransactTime` AS `TransactTime` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE (`thread`.`ThreadID` = declared_in_param_ThreadID AND `thread`.`Price` < declared_in_param_Price) ORDER BY `thread`.`Price` ASC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionByPriceHigher` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByPriceHigher`(IN in_param_ThreadID varchar(45), IN in_param_Price float) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); DECLARE declared_in_param_Price FLOAT; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Price = in_param_Price; SELECT `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`OrderID` AS `OrderID`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity`, `Orders`.`TransactTime` AS `TransactTime` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE (`thread`.`ThreadID` = declared_in_param_ThreadID AND `thread`.`Price` > declared_in_param_Price) ORDER BY `thread`.`Price` DESC LIMIT 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionByThreadID` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByThreadID`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `thread`.`OrderID` AS `OrderID`, `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE `thread`.`ThreadID` = declared_in_param_ThreadID ORDER BY `thread`.`Price` ASC; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionCount`(IN in_param varchar(45)) BEGIN DECLARE declared_in_param CHAR(50); SET declared_in_param = in_param; SELECT count(*) AS count FROM thread WHERE thread.ThreadID = declared_in_param; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionDistinct` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionDistinct`() BEGIN SELECT DISTINCT ThreadID, ThreadIDSession FROM thread; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionDistinctBySymbol` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionDistinctBySymbol`(in_Symbol varchar(45)) BEGIN SELECT DISTINCT thread.ThreadID, thread.ThreadIDSession FROM thread INNER JOIN orders ON orders.OrderID = thread.OrderID WHERE orders.Symbol = in_Symbol; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactions` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactions`() BEGIN SELECT `thread`.`ThreadID`, `thread`.`ThreadIDSession`, `thread`.`OrderID`, `orders`.`Symbol`, `thread`.`ExecutedQuantity` FROM `cryptopump`.`thread` INNER JOIN `cryptopump`.`orders` ON `thread`.`OrderID` = `orders`.`OrderID` ORDER BY `thread`.`ThreadID`, `thread`.`Price`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactiontUpmarketPriceCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactiontUpmarketPriceCount`(IN in_param_ThreadID varchar(45), IN in_param_Price float) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); DECLARE declared_in_param_Price float; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Price = in_param_Price; SELECT count(*) AS `count` FROM `thread` WHERE (`thread`.`Price` < declared_in_param_Price AND `thread`.`ThreadID` = declared_in_param_ThreadID); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetUser` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetUser`(IN in_Username varchar(45)) BEGIN SELECT `user`.`Username`, `user`.`PasswordHash`, `user`.`Role`, `user`.`FailedLogins`, `user`.`LockedUntil`, `user`.`TOTPSecret`, `user`.`TOTPEnabled` FROM `cryptopump`.`user` WHERE `user`.`Username` = in_Username; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetUserCount` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetUserCount`() BEGIN SELECT COUNT(*) AS `count` FROM `cryptopump`.`user`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetWebhooks` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetWebhooks`() BEGIN SELECT `ID`, `Name`, `URL`, `Events`, `Secret`, `Enabled` FROM `cryptopump`.`webhook` ORDER BY `ID`; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `GetWsConnections` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `GetWsConnections`(IN in_param_ThreadID varchar(45), IN in_param_Limit int) BEGIN SELECT `wsconnection`.`ThreadID`, `wsconnection`.`Stream`, `wsconnection`.`ConnectedTime`, `wsconnection`.`DisconnectedTime`, `wsconnection`.`Messages`, `wsconnection`.`Reason`, `wsconnection`.`Error` FROM `cryptopump`.`wsconnection` WHERE `wsconnection`.`ThreadID` = in_param_ThreadID ORDER BY `wsconnection`.`DisconnectedTime` DESC LIMIT in_param_Limit; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveAlertRule` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveAlertRule`(IN in_Name varchar(64), IN in_ThreadID varchar(45), IN in_Metric varchar(45), IN in_Operator varchar(2), IN in_Threshold double, IN in_Channel varchar(45), IN in_Target varchar(255)) BEGIN INSERT INTO `cryptopump`.`alertrule` (`Name`, `ThreadID`, `Metric`, `Operator`, `Threshold`, `Channel`, `Target`) VALUES (in_Name, in_ThreadID, in_Metric, in_Operator, in_Threshold, in_Channel, in_Target); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveAssetPrice` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveAssetPrice`(IN in_Asset varchar(20), IN in_Price double, IN in_Time bigint) BEGIN REPLACE INTO `cryptopump`.`assetprice` (`Asset`, `Price`, `Time`) VALUES (in_Asset, in_Price, in_Time); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveAudit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveAudit`(IN in_Time bigint, IN in_ThreadID varchar(45), IN in_Event varchar(45), IN in_OrderID bigint, IN in_Payload text) BEGIN DECLARE prev char(64); DO GET_LOCK('cryptopump.audit', 10); SELECT COALESCE((SELECT `Hash` FROM `cryptopump`.`audit` ORDER BY `ID` DESC LIMIT 1), REPEAT('0', 64)) INTO prev; INSERT INTO `cryptopump`.`audit` (`Time`, `ThreadID`, `Event`, `OrderID`, `Payload`, `PrevHash`, `Hash`) VALUES (in_Time, in_ThreadID, in_Event, in_OrderID, in_Payload, prev, SHA2(CONCAT(prev, in_Payload), 256)); DO RELEASE_LOCK('cryptopump.audit'); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveAuthToken` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveAuthToken`(IN in_TokenHash varchar(64), IN in_Username varchar(45), IN in_Kind varchar(45), IN in_Expires bigint, IN in_LastSeen bigint) BEGIN INSERT INTO `cryptopump`.`authtoken` (`TokenHash`, `Username`, `Kind`, `Expires`, `LastSeen`) VALUES (in_TokenHash, in_Username, in_Kind, in_Expires, in_LastSeen); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveBacktest` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveBacktest`(IN in_Name varchar(64), IN in_Symbol varchar(45), IN in_StartTime bigint, IN in_EndTime bigint, IN in_CreatedTime bigint, IN in_Parameters text, IN in_Trades int, IN in_ProfitPct float, IN in_BuyHoldPct float, IN in_MaxDrawdownPct float, IN in_WinRatePct float) BEGIN INSERT INTO `cryptopump`.`backtest` (`Name`, `Symbol`, `StartTime`, `EndTime`, `CreatedTime`, `Parameters`, `Trades`, `ProfitPct`, `BuyHoldPct`, `MaxDrawdownPct`, `WinRatePct`) VALUES (in_Name, in_Symbol, in_StartTime, in_EndTime, in_CreatedTime, in_Parameters, in_Trades, in_ProfitPct, in_BuyHoldPct, in_MaxDrawdownPct, in_WinRatePct); SELECT LAST_INSERT_ID(); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveBacktestEquity` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveBacktestEquity`(IN in_BacktestID int, IN in_Time bigint, IN in_Equity float, IN in_BuyHold float) BEGIN INSERT INTO `cryptopump`.`backtestequity` (`BacktestID`, `Time`, `Equity`, `BuyHold`) VALUES (in_BacktestID, in_Time, in_Equity, in_BuyHold) ON DUPLICATE KEY UPDATE `Equity` = in_Equity, `BuyHold` = in_BuyHold; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveBalance` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveBalance`(IN in_Account varchar(45), IN in_Asset varchar(20), IN in_Free double, IN in_Locked double, IN in_Time bigint) BEGIN REPLACE INTO `cryptopump`.`balance` (`Account`, `Asset`, `Free`, `Locked`, `Time`) VALUES (in_Account, in_Asset, in_Free, in_Locked, in_Time); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveConfigAudit` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveConfigAudit`(IN in_ThreadID varchar(45), IN in_Username varchar(45), IN in_Time bigint, IN in_Config text, IN in_Changes text) BEGIN INSERT INTO `cryptopump`.`configaudit` (`ThreadID`, `Version`, `Username`, `Time`, `Config`, `Changes`) SELECT in_ThreadID, IFNULL(MAX(`configaudit`.`Version`), 0) + 1, in_Username, in_Time, in_Config, in_Changes FROM `cryptopump`.`configaudit` WHERE `configaudit`.`ThreadID` = in_ThreadID; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveEquity` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveEquity`(IN in_Time bigint, IN in_Equity float) BEGIN INSERT INTO `cryptopump`.`equity` (`Time`, `Equity`) VALUES (in_Time, in_Equity); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveGlobal` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveGlobal`(in_Profit float, in_ProfitNet float, in_ProfitPct float, in_TransactTime bigint) BEGIN INSERT INTO global (Profit, ProfitNet, ProfitPct, TransactTime) VALUES (in_Profit, in_ProfitNet, in_ProfitPct, in_TransactTime); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveKline` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveKline`(IN in_ThreadID varchar(45), IN in_OpenTime bigint, IN in_CloseTime bigint, IN in_Open float, IN in_Close float, IN in_Low float, IN in_High float, IN in_Volume float, IN in_Ma7 float, IN in_Ma14 float) BEGIN REPLACE INTO `cryptopump`.`kline` (`ThreadID`, `OpenTime`, `CloseTime`, `Open`, `Close`, `Low`, `High`, `Volume`, `Ma7`, `Ma14`) VALUES (in_ThreadID, in_OpenTime, in_CloseTime, in_Open, in_Close, in_Low, in_High, in_Volume, in_Ma7, in_Ma14); SET SQL_SAFE_UPDATES = 0; DELETE FROM `cryptopump`.`kline` WHERE `kline`.`ThreadID` = in_ThreadID AND `kline`.`OpenTime` < (in_OpenTime - 86400000); SET SQL_SAFE_UPDATES = 1; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveLiquidationReport` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveLiquidationReport`(IN in_ThreadID varchar(45), IN in_Symbol varchar(45), IN in_StartTime bigint, IN in_EndTime bigint) BEGIN INSERT INTO `cryptopump`.`liquidation` (`ThreadID`, `Symbol`, `Orders`, `ExecutedQuantity`, `CummulativeQuoteQty`, `StartTime`, `EndTime`) SELECT in_ThreadID, in_Symbol, COUNT(`orders`.`OrderID`), IFNULL(SUM(`orders`.`ExecutedQuantity`), 0), IFNULL(SUM(`orders`.`CummulativeQuoteQty`), 0), in_StartTime, in_EndTime FROM `orders` WHERE `orders`.`ThreadID` = in_ThreadID AND `orders`.`Symbol` = in_Symbol AND `orders`.`Side` = 'SELL' AND `orders`.`Status` = 'FILLED' AND `orders`.`TransactTime` BETWEEN in_StartTime AND in_EndTime; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveLog` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveLog`(IN in_Time bigint, IN in_Level varchar(45), IN in_ThreadID varchar(45), IN in_Component varchar(45), IN in_Message text) BEGIN INSERT INTO `cryptopump`.`log` (`Time`, `Level`, `ThreadID`, `Component`, `Message`) VALUES (in_Time, in_Level, in_ThreadID, in_Component, in_Message); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveNote` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveNote`(IN in_ThreadID varchar(45), IN in_OrderID bigint, IN in_Username varchar(45), IN in_Time bigint, IN in_Tags varchar(255), IN in_Text text) BEGIN INSERT INTO `cryptopump`.`note` (`ThreadID`, `OrderID`, `Username`, `Time`, `Tags`, `Text`) VALUES (in_ThreadID, in_OrderID, in_Username, in_Time, in_Tags, in_Text); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveOrder` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveOrder`(ClientOrderId varchar(45), CummulativeQuoteQty float, ExecutedQuantity float, OrderID bigint, OrderIDSource bigint, Price float, Side varchar(45), Status varchar(45), Symbol varchar(45), TransactTime bigint, ThreadID varchar(45), ThreadIDSession varchar(45), CorrelationID varchar(45)) BEGIN INSERT INTO orders (ClientOrderId, CummulativeQuoteQty, ExecutedQuantity, OrderID, OrderIDSource, Price, Side, Status, Symbol, TransactTime, ThreadID, ThreadIDSession, CorrelationID) VALUES (ClientOrderId, CummulativeQuoteQty, ExecutedQuantity, OrderID, OrderIDSource, Price, Side, Status, Symbol, TransactTime, ThreadID, ThreadIDSession, CorrelationID); END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SavePendingAction` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
+
+CREATE DEFINER=`root`@`%` PROCEDURE `SavePendingAction`(IN in_ThreadID varchar(45), IN in_Action varchar(45), IN in_OrderID bigint, IN in_Notional float, IN in_CreatedTime bigint) BEGIN INSERT INTO `cryptopump`.`pendingaction` (`ThreadID`, `Action`, `OrderID`, `Notional`, `Status`, `CreatedTime`) VALUES (in_ThreadID, in_Action, in_OrderID, in_Notional, 'PENDING', in_CreatedTime); END;
 
-LOCK TABLES `global` WRITE;
-/*!40000 ALTER TABLE `global` DISABLE KEYS */;
-/*!40000 ALTER TABLE `global` ENABLE KEYS */;
-UNLOCK TABLES;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SavePnl` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
---
--- Table structure for table `orders`
---
+CREATE DEFINER=`root`@`%` PROCEDURE `SavePnl`(IN in_Time bigint, IN in_Expire bigint) BEGIN DELETE FROM `cryptopump`.`pnl` WHERE `pnl`.`Time` < in_Expire; INSERT INTO `cryptopump`.`pnl` (`Time`, `ThreadID`, `Realized`, `Unrealized`) SELECT in_Time, `session`.`ThreadID`, IFNULL((SELECT SUM(`sell`.`CummulativeQuoteQty` - `buy`.`CummulativeQuoteQty`) FROM `cryptopump`.`orders` `sell` INNER JOIN `cryptopump`.`orders` `buy` ON `buy`.`OrderID` = `sell`.`OrderIDSource` WHERE `sell`.`ThreadID` = `session`.`ThreadID` AND `sell`.`Side` = 'SELL' AND `sell`.`Status` = 'FILLED' AND `buy`.`Side` = 'BUY'), 0), `session`.`DiffTotal` FROM `cryptopump`.`session`; END;
 
-DROP TABLE IF EXISTS `orders`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `orders` (
-  `ClientOrderId` varchar(45) NOT NULL,
-  `CummulativeQuoteQty` float NOT NULL,
-  `ExecutedQuantity` float NOT NULL,
-  `OrderID` bigint(20) NOT NULL,
-  `OrderIDSource` bigint(20) NOT NULL,
-  `Price` float NOT NULL,
-  `Side` varchar(45) NOT NULL,
-  `Status` varchar(45) NOT NULL,
-  `Symbol` varchar(45) NOT NULL,
-  `TransactTime` bigint(20) NOT NULL,
-  `ThreadID` varchar(45) NOT NULL,
-  `ThreadIDSession` varchar(45) NOT NULL,
-  PRIMARY KEY (`OrderID`),
-  UNIQUE KEY `OrderID_UNIQUE` (`OrderID`),
-  KEY `orders_idx_side_status` (`Side`,`Status`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-/*!40101 SET character_set_client = @saved_cs_client */;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SavePreference` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
---
--- Dumping data for table `orders`
---
+CREATE DEFINER=`root`@`%` PROCEDURE `SavePreference`(IN in_Username varchar(45), IN in_Theme varchar(10), IN in_RefreshInterval int, IN in_Currency varchar(10), IN in_Columns varchar(255), IN in_Language varchar(10)) BEGIN REPLACE INTO `cryptopump`.`preference` (`Username`, `Theme`, `RefreshInterval`, `Currency`, `Columns`, `Language`) VALUES (in_Username, in_Theme, in_RefreshInterval, in_Currency, in_Columns, in_Language); END;
 
-LOCK TABLES `orders` WRITE;
-/*!40000 ALTER TABLE `orders` DISABLE KEYS */;
-/*!40000 ALTER TABLE `orders` ENABLE KEYS */;
-UNLOCK TABLES;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SavePreset` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
---
--- Table structure for table `session`
---
+CREATE DEFINER=`root`@`%` PROCEDURE `SavePreset`(IN in_Name varchar(64), IN in_Username varchar(45), IN in_Time bigint, IN in_Config text) BEGIN REPLACE INTO `cryptopump`.`preset` (`Name`, `Username`, `Time`, `Config`) VALUES (in_Name, in_Username, in_Time, in_Config); END;
 
-DROP TABLE IF EXISTS `session`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `session` (
-  `ID` int(11) NOT NULL AUTO_INCREMENT,
-  `ThreadID` varchar(45) NOT NULL,
-  `ThreadIDSession` varchar(45) NOT NULL,
-  `Exchange` varchar(45) NOT NULL,
-  `FiatSymbol` varchar(45) NOT NULL,
-  `FiatFunds` float NOT NULL,
-  `DiffTotal` float NOT NULL,
-  `Status` tinyint(4) NOT NULL,
-  PRIMARY KEY (`ID`),
-  UNIQUE KEY `ThreadID_UNIQUE` (`ThreadID`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-/*!40101 SET character_set_client = @saved_cs_client */;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveQueuedNotification` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
---
--- Dumping data for table `session`
---
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveQueuedNotification`(IN in_ThreadID varchar(45), IN in_Channel varchar(45), IN in_Text text, IN in_Attempts int, IN in_NextAttempt bigint, IN in_LastError varchar(255), IN in_CreatedTime bigint) BEGIN INSERT INTO `cryptopump`.`notificationqueue` (`ThreadID`, `Channel`, `Text`, `Attempts`, `NextAttempt`, `LastError`, `CreatedTime`) VALUES (in_ThreadID, in_Channel, in_Text, in_Attempts, in_NextAttempt, LEFT(in_LastError, 255), in_CreatedTime); END;
 
-LOCK TABLES `session` WRITE;
-/*!40000 ALTER TABLE `session` DISABLE KEYS */;
-/*!40000 ALTER TABLE `session` ENABLE KEYS */;
-UNLOCK TABLES;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveRecoveryCode` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
---
--- Table structure for table `thread`
---
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveRecoveryCode`(IN in_Username varchar(45), IN in_CodeHash varchar(64)) BEGIN INSERT INTO `cryptopump`.`recoverycode` (`Username`, `CodeHash`) VALUES (in_Username, in_CodeHash); END;
 
-DROP TABLE IF EXISTS `thread`;
-/*!40101 SET @saved_cs_client     = @@character_set_client */;
-/*!50503 SET character_set_client = utf8mb4 */;
-CREATE TABLE `thread` (
-  `ID` int(11) NOT NULL AUTO_INCREMENT,
-  `ThreadID` varchar(45) NOT NULL,
-  `ThreadIDSession` varchar(45) NOT NULL,
-  `OrderID` bigint(20) DEFAULT NULL,
-  `CummulativeQuoteQty` float NOT NULL,
-  `Price` float NOT NULL,
-  `ExecutedQuantity` float NOT NULL,
-  PRIMARY KEY (`ID`)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-/*!40101 SET character_set_client = @saved_cs_client */;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveSession` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
---
--- Dumping data for table `thread`
---
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveSession`(in_ThreadID varchar(45), in_ThreadIDSession varchar(45), in_Exchange varchar(45), in_FiatSymbol varchar(45), in_FiatFunds float, in_DiffTotal float, in_Status tinyint(1)) BEGIN INSERT INTO session (ThreadID, ThreadIDSession, Exchange, FiatSymbol, FiatFunds, DiffTotal, Status) VALUES (in_ThreadID, in_ThreadIDSession, in_Exchange, in_FiatSymbol, in_FiatFunds, in_DiffTotal, in_Status); END;
 
-LOCK TABLES `thread` WRITE;
-/*!40000 ALTER TABLE `thread` DISABLE KEYS */;
-/*!40000 ALTER TABLE `thread` ENABLE KEYS */;
-UNLOCK TABLES;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveSessionLabel` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET @saved_cs_results     = @@character_set_results */ ;
+/*!50003 SET @saved_col_connection = @@collation_connection */ ;
+/*!50003 SET character_set_client  = utf8mb4 */ ;
+/*!50003 SET character_set_results = utf8mb4 */ ;
+/*!50003 SET collation_connection  = utf8mb4_general_ci */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
---
--- Dumping routines for database 'cryptopump'
---
-/*!50003 DROP PROCEDURE IF EXISTS `DeleteSession` */;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveSessionLabel`(IN in_ThreadID varchar(45), IN in_Name varchar(45), IN in_Tags varchar(255), IN in_UpdatedTime bigint) BEGIN IF in_Name = '' AND in_Tags = '' THEN DELETE FROM `cryptopump`.`sessionlabel` WHERE `sessionlabel`.`ThreadID` = in_ThreadID; ELSE INSERT INTO `cryptopump`.`sessionlabel` (`ThreadID`, `Name`, `Tags`, `UpdatedTime`) VALUES (in_ThreadID, in_Name, in_Tags, in_UpdatedTime) ON DUPLICATE KEY UPDATE `Name` = in_Name, `Tags` = in_Tags, `UpdatedTime` = in_UpdatedTime; END IF; END;
+
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 SET character_set_client  = @saved_cs_client */ ;
+/*!50003 SET character_set_results = @saved_cs_results */ ;
+/*!50003 SET collation_connection  = @saved_col_connection */ ;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveShadowOrder` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -155,13 +2938,13 @@ UNLOCK TABLES;
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `DeleteSession`(IN in_ThreadID varchar(45)) BEGIN DECLARE ThreadID varchar(45); SET SQL_SAFE_UPDATES = 0; SET ThreadID = in_ThreadID; DELETE FROM session WHERE session.ThreadID = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveShadowOrder`(IN in_ThreadID varchar(45), IN in_Symbol varchar(45), IN in_Side varchar(8), IN in_Price double, IN in_Quantity double, IN in_CumulativeQuoteQuantity double, IN in_OrderIDSource bigint, IN in_Profit double, IN in_Decision varchar(255), IN in_TransactTime bigint) BEGIN INSERT INTO `cryptopump`.`shadoworder` ( `ThreadID`, `Symbol`, `Side`, `Price`, `Quantity`, `CumulativeQuoteQuantity`, `OrderIDSource`, `Profit`, `Decision`, `TransactTime`) VALUES ( in_ThreadID, in_Symbol, in_Side, in_Price, in_Quantity, in_CumulativeQuoteQuantity, in_OrderIDSource, in_Profit, in_Decision, in_TransactTime); END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `DeleteThreadTransactionAll` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveSymbolList` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -171,13 +2954,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `DeleteSession`(IN in_ThreadID varchar(45))
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `DeleteThreadTransactionAll`() BEGIN SET SQL_SAFE_UPDATES = 0; DELETE FROM thread; SET SQL_SAFE_UPDATES = 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveSymbolList`(IN in_Symbol varchar(45), IN in_List varchar(45)) BEGIN INSERT INTO `cryptopump`.`symbollist` (`Symbol`, `List`) VALUES (in_Symbol, in_List) ON DUPLICATE KEY UPDATE `List` = in_List; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `DeleteThreadTransactionByOrderID` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveThreadCommand` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -187,13 +2970,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `DeleteThreadTransactionAll`() BEGIN SET SQL
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `DeleteThreadTransactionByOrderID`(IN in_param_OrderID bigint) BEGIN DECLARE declared_in_param_OrderID bigint; SET SQL_SAFE_UPDATES = 0; SET declared_in_param_OrderID = in_param_OrderID; DELETE FROM thread WHERE thread.OrderID = in_param_OrderID; SET SQL_SAFE_UPDATES = 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveThreadCommand`(IN in_ThreadID varchar(45), IN in_Command varchar(45), IN in_OrderID bigint, IN in_Source varchar(45), IN in_CreatedTime bigint) BEGIN INSERT INTO `cryptopump`.`threadcommand` ( `ThreadID`, `Command`, `OrderID`, `Source`, `CreatedTime`) VALUES ( in_ThreadID, in_Command, in_OrderID, in_Source, in_CreatedTime); END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetGlobal` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveThreadEvent` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -203,13 +2986,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `DeleteThreadTransactionByOrderID`(IN in_par
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetGlobal`() BEGIN SELECT `global`.`Profit` AS `Profit`, `global`.`ProfitNet` AS `ProfitNet`, `global`.`ProfitPct` AS `ProfitPct`, `global`.`TransactTime` AS `TransactTime` FROM `global` WHERE `global`.`ID` = 1 LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveThreadEvent`(IN in_ThreadID varchar(45), IN in_Time bigint, IN in_Kind varchar(45), IN in_Source varchar(45), IN in_Message varchar(255)) BEGIN INSERT INTO `cryptopump`.`threadevent` ( `ThreadID`, `Time`, `Kind`, `Source`, `Message`) VALUES ( in_ThreadID, in_Time, in_Kind, in_Source, in_Message); END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetLastOrderTransactionPrice` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveThreadTransaction` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -219,13 +3002,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetGlobal`() BEGIN SELECT `global`.`Profit`
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetLastOrderTransactionPrice`(IN in_param_ThreadID varchar(45), IN in_param_Side varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); DECLARE declared_in_param_Side CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Side = in_param_Side; SELECT `orders`.`Price` AS `Price` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND `orders`.`Side` = declared_in_param_Side AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveThreadTransaction`(ThreadID varchar(45), ThreadIDSession varchar(45), OrderID bigint, CummulativeQuoteQty float, Price float, ExecutedQuantity float) BEGIN INSERT INTO thread (ThreadID, ThreadIDSession, OrderID, CummulativeQuoteQty, Price, ExecutedQuantity) VALUES (ThreadID, ThreadIDSession, OrderID, CummulativeQuoteQty, Price, ExecutedQuantity); END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetLastOrderTransactionSide` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveUser` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -235,13 +3018,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetLastOrderTransactionPrice`(IN in_param_T
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetLastOrderTransactionSide`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `orders`.`Side` AS `Side` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND `orders`.`Status` = 'FILLED') ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveUser`(IN in_Username varchar(45), IN in_PasswordHash varchar(255), IN in_Role varchar(45)) BEGIN INSERT INTO `cryptopump`.`user` (`Username`, `PasswordHash`, `Role`) VALUES (in_Username, in_PasswordHash, in_Role) ON DUPLICATE KEY UPDATE `PasswordHash` = in_PasswordHash, `Role` = in_Role, `FailedLogins` = 0, `LockedUntil` = 0; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetOrderByOrderID` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveVolatilityTrip` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -251,13 +3034,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetLastOrderTransactionSide`(IN in_param_Th
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderByOrderID`(IN in_param_OrderID bigint, IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_orderid BIGINT; DECLARE declared_in_param_threadid CHAR(50); SET declared_in_param_orderid = in_param_orderid; SET declared_in_param_threadid = in_param_threadid; SELECT `orders`.`orderid` AS `OrderID`, `orders`.`price` AS `Price`, `orders`.`executedquantity` AS `ExecutedQuantity`, `orders`.`cummulativequoteqty` AS `CummulativeQuoteQty`, `orders`.`transacttime` AS `TransactTime` FROM `orders` WHERE (`orders`.`orderid` = declared_in_param_orderid AND `orders`.`threadid` = declared_in_param_threadid) LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveVolatilityTrip`(IN in_ThreadID varchar(45), IN in_Symbol varchar(45), IN in_Reason varchar(45), IN in_Sigma float, IN in_Threshold float, IN in_TransactTime bigint) BEGIN INSERT INTO `cryptopump`.`volatility` (`ThreadID`, `Symbol`, `Reason`, `Sigma`, `Threshold`, `TransactTime`) VALUES (in_ThreadID, in_Symbol, in_Reason, in_Sigma, in_Threshold, in_TransactTime); END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetOrderSymbol` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveWebhook` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -267,13 +3050,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderByOrderID`(IN in_param_OrderID bigi
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderSymbol`(IN in_param varchar(45)) BEGIN DECLARE declared_in_param CHAR(45); SET declared_in_param = in_param; SELECT Symbol from orders WHERE orders.ThreadID = declared_in_param ORDER BY TransactTime DESC LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveWebhook`(IN in_ID int, IN in_Name varchar(64), IN in_URL varchar(255), IN in_Events varchar(255), IN in_Secret varchar(64), IN in_Enabled tinyint) BEGIN IF in_ID = 0 THEN INSERT INTO `cryptopump`.`webhook` (`Name`, `URL`, `Events`, `Secret`, `Enabled`) VALUES (in_Name, in_URL, in_Events, in_Secret, in_Enabled); ELSE SET SQL_SAFE_UPDATES = 0; UPDATE `cryptopump`.`webhook` SET `Name` = in_Name, `URL` = in_URL, `Events` = in_Events, `Secret` = IF(in_Secret = '', `Secret`, in_Secret), `Enabled` = in_Enabled WHERE `ID` = in_ID; SET SQL_SAFE_UPDATES = 1; END IF; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionCount` */;
+/*!50003 DROP PROCEDURE IF EXISTS `SaveWsConnection` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -283,13 +3066,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderSymbol`(IN in_param varchar(45)) BE
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionCount`(IN in_param_ThreadID varchar(45), IN in_param_Side varchar(45), IN in_param_Minutes int) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); DECLARE declared_in_param_Side CHAR(45); DECLARE declared_in_param_Minutes int; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Side = in_param_Side; SET declared_in_param_Minutes = in_param_Minutes; SELECT COALESCE(count(*),0) AS `count` FROM `orders` WHERE (`orders`.`Side` = declared_in_param_Side AND `orders`.`Status` = 'FILLED' AND str_to_date(date_format(CAST(from_unixtime((`orders`.`TransactTime` / 1000)) AS DATETIME), '%Y-%m-%d %H:%i'), '%Y-%m-%d %H:%i') BETWEEN str_to_date(date_format(CAST(date_add(now(6), INTERVAL declared_in_param_Minutes minute) AS DATETIME), '%Y-%m-%d %H:%i'), '%Y-%m-%d %H:%i') AND str_to_date(date_format(CAST(now(6) AS DATETIME), '%Y-%m-%d %H:%i'), '%Y-%m-%d %H:%i') AND `orders`.`ThreadID` = declared_in_param_ThreadID); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `SaveWsConnection`(IN in_ThreadID varchar(45), IN in_Stream varchar(20), IN in_ConnectedTime bigint, IN in_DisconnectedTime bigint, IN in_Messages bigint, IN in_Reason varchar(20), IN in_Error varchar(255)) BEGIN INSERT INTO `cryptopump`.`wsconnection` (`ThreadID`, `Stream`, `ConnectedTime`, `DisconnectedTime`, `Messages`, `Reason`, `Error`) VALUES (in_ThreadID, in_Stream, in_ConnectedTime, in_DisconnectedTime, in_Messages, in_Reason, LEFT(in_Error, 255)); END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionPending` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateAuthTokenLastSeen` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -299,13 +3082,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionCount`(IN in_param_Threa
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionPending`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `orders`.`OrderID` AS `OrderID`, `orders`.`Symbol` AS `Symbol` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND (`orders`.`Status` <> 'FILLED' OR `orders`.`Status` IS NULL) AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL) AND `orders`.`Status` IS NOT NULL AND (`orders`.`Status` <> '' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) ASC LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateAuthTokenLastSeen`(IN in_TokenHash varchar(64), IN in_LastSeen bigint) BEGIN UPDATE `cryptopump`.`authtoken` SET `LastSeen` = in_LastSeen WHERE `TokenHash` = in_TokenHash; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionSideLastTwo` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateGlobal` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -315,13 +3098,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionPending`(IN in_param_Thr
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionSideLastTwo`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `A`.`Side` AS `Last`, `B`.`Side` AS `SecondLast` FROM ( (SELECT `orders`.`Side` AS `Side` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1) A INNER JOIN (SELECT `orders`.`Side` AS `Side` FROM `orders` WHERE (`orders`.`ThreadID` = declared_in_param_ThreadID AND (`orders`.`Status` <> 'CANCELED' OR `orders`.`Status` IS NULL)) ORDER BY from_unixtime((`orders`.`TransactTime` / 1000)) DESC LIMIT 1,1) B ); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateGlobal`(in_Profit float, in_ProfitNet float, in_ProfitPct float, in_TransactTime bigint) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE global SET Profit = in_Profit, ProfitNet = in_ProfitNet, ProfitPct = in_ProfitPct, TransactTime = in_TransactTime WHERE ID = 1; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetOrderTransactionTimeByOrderID` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateGlobalDrawdown` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -331,13 +3114,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionSideLastTwo`(IN in_param
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionTimeByOrderID`(IN in_param_OrderID bigint) BEGIN DECLARE declared_in_param_OrderID bigint; SET declared_in_param_OrderID = in_param_OrderID; SELECT `orders`.`TransactTime` AS `TransactTime` FROM `orders` WHERE `orders`.`OrderID` = declared_in_param_OrderID LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateGlobalDrawdown`(in_EquityPeak float, in_DrawdownHalt tinyint(1)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE global SET EquityPeak = in_EquityPeak, DrawdownHalt = in_DrawdownHalt WHERE ID = 1; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetProfit` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateGlobalLiquidate` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -347,13 +3130,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetOrderTransactionTimeByOrderID`(IN in_par
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetProfit`() BEGIN SELECT SUM(`source`.`Profit`) AS `profit`, SUM(`source`.`Profit`) + (`source`.`Diff`) AS `netprofit`, AVG(`source`.`Percentage`) AS `avg` FROM (SELECT `orders`.`Side` AS `Side`, `Orders`.`Side` AS `Orders__Side`, `orders`.`Status` AS `Status`, `Orders`.`Status` AS `Orders__Status`, `orders`.`ThreadID` AS `ThreadID`, `Orders`.`CummulativeQuoteQty` AS `Orders__CummulativeQuoteQty`, `orders`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, (`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) AS `Profit`, ((`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) / CASE WHEN `Orders`.`CummulativeQuoteQty` = 0 THEN NULL ELSE `Orders`.`CummulativeQuoteQty` END) AS `Percentage`, (SELECT sum(`session`.`DiffTotal`) AS `sum` FROM `session`) AS `Diff` FROM `orders` INNER JOIN `orders` `Orders` ON `orders`.`OrderID` = `Orders`.`OrderIDSource` WHERE ( `orders`.`Side` = 'BUY' ) AND ( `orders`.`Status` = 'FILLED' ) ) `source` WHERE ( 1 = 1 AND `source`.`Orders__Side` = 'SELL' AND 1 = 1 AND `source`.`Orders__Status` = 'FILLED' ); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateGlobalLiquidate`(in_Liquidate tinyint(1), in_LiquidateTime bigint) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE global SET Liquidate = in_Liquidate, LiquidateTime = in_LiquidateTime WHERE ID = 1; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetProfitByThreadID` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateOrder` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -363,13 +3146,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetProfit`() BEGIN SELECT SUM(`source`.`Pro
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetProfitByThreadID`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT SUM(`source`.`Profit`) + (`source`.`Diff`) AS `sum`, AVG(`source`.`Percentage`) AS `avg` FROM (SELECT `orders`.`Side` AS `Side`, `Orders`.`Side` AS `Orders__Side`, `orders`.`Status` AS `Status`, `Orders`.`Status` AS `Orders__Status`, `orders`.`ThreadID` AS `ThreadID`, `Orders`.`CummulativeQuoteQty` AS `Orders__CummulativeQuoteQty`, `orders`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, (`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) AS `Profit`, ((`Orders`.`CummulativeQuoteQty` - `orders`.`CummulativeQuoteQty`) / CASE WHEN `Orders`.`CummulativeQuoteQty` = 0 THEN NULL ELSE `Orders`.`CummulativeQuoteQty` END) AS `Percentage`, (SELECT SUM(`session`.`DiffTotal`) AS `sum` FROM `session` WHERE `session`.`ThreadID` = declared_in_param_ThreadID) AS `Diff` FROM `orders` INNER JOIN `orders` `Orders` ON `orders`.`OrderID` = `Orders`.`OrderIDSource`) `source` WHERE (`source`.`Side` = 'BUY' AND `source`.`Orders__Side` = 'SELL' AND `source`.`Status` = 'FILLED' AND `source`.`Orders__Status` = 'FILLED' AND `source`.`ThreadID` = declared_in_param_ThreadID); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateOrder`(in_OrderID bigint, CummulativeQuoteQty float, ExecutedQuantity float, Price float, Status varchar(45)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE orders SET CummulativeQuoteQty = CummulativeQuoteQty, ExecutedQuantity = ExecutedQuantity, Price = Price, Status = Status WHERE OrderID = in_OrderID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetSessionStatus` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateOrderScore` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -379,13 +3162,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetProfitByThreadID`(IN in_param_ThreadID v
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionStatus`() BEGIN SELECT `session`.`ThreadID` AS `ThreadID`, `session`.`Status` AS `Status` FROM cryptopump.session WHERE `session`.`Status` = 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateOrderScore`(in_OrderID bigint, in_Score float) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE orders SET Score = in_Score WHERE OrderID = in_OrderID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadCount` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateOrderSource` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -395,13 +3178,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetSessionStatus`() BEGIN SELECT `session`.
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadCount`() BEGIN SELECT COUNT(DISTINCT `session`.`ThreadID`) AS `count` FROM `cryptopump`.`session`; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateOrderSource`(in_OrderID bigint, in_Source varchar(45)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE orders SET Source = in_Source WHERE OrderID = in_OrderID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadLastTransaction` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateOrderType` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -411,13 +3194,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadCount`() BEGIN SELECT COUNT(DISTIN
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadLastTransaction`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`OrderID` AS `OrderID`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity`, `Orders`.`TransactTime` AS `TransactTime` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE (`thread`.`ThreadID` = declared_in_param_ThreadID) ORDER BY `thread`.`Price` ASC LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateOrderType`(in_OrderID bigint, in_Type varchar(45)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE orders SET Type = in_Type WHERE OrderID = in_OrderID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionAmount` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdatePendingAction` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -427,13 +3210,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadLastTransaction`(IN in_param_Threa
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionAmount`() BEGIN SELECT SUM(`thread`.`CummulativeQuoteQty`) AS `sum` FROM `cryptopump`.`thread`; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdatePendingAction`(IN in_ID int, IN in_Status varchar(45)) BEGIN UPDATE `cryptopump`.`pendingaction` SET `pendingaction`.`Status` = in_Status WHERE `pendingaction`.`ID` = in_ID; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionByPrice` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateQueuedNotification` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -443,13 +3226,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionAmount`() BEGIN SELECT
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByPrice`(IN in_param_ThreadID varchar(45), IN in_param_Price float) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); DECLARE declared_in_param_Price FLOAT; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Price = in_param_Price; SELECT `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`OrderID` AS `OrderID`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity`, `Orders`.`TransactTime` AS `TransactTime` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE (`thread`.`ThreadID` = declared_in_param_ThreadID AND `thread`.`Price` < declared_in_param_Price) ORDER BY `thread`.`Price` ASC LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateQueuedNotification`(IN in_ID int, IN in_Attempts int, IN in_NextAttempt bigint, IN in_LastError varchar(255)) BEGIN UPDATE `cryptopump`.`notificationqueue` SET `notificationqueue`.`Attempts` = in_Attempts, `notificationqueue`.`NextAttempt` = in_NextAttempt, `notificationqueue`.`LastError` = LEFT(in_LastError, 255) WHERE `notificationqueue`.`ID` = in_ID; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionByPriceHigher` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSession` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -459,13 +3242,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByPrice`(IN in_param_Th
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByPriceHigher`(IN in_param_ThreadID varchar(45), IN in_param_Price float) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); DECLARE declared_in_param_Price FLOAT; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Price = in_param_Price; SELECT `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`OrderID` AS `OrderID`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity`, `Orders`.`TransactTime` AS `TransactTime` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE (`thread`.`ThreadID` = declared_in_param_ThreadID AND `thread`.`Price` > declared_in_param_Price) ORDER BY `thread`.`Price` DESC LIMIT 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSession`(in_ThreadID varchar(45), in_ThreadIDSession varchar(45), in_Exchange varchar(45), in_FiatSymbol varchar(45), in_FiatFunds float, in_DiffTotal float, in_Status tinyint(1)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`FiatFunds` = in_FiatFunds, `session`.`DiffTotal` = in_DiffTotal, `session`.`Status` = in_Status, `session`.`Heartbeat` = ROUND(UNIX_TIMESTAMP(NOW(3)) * 1000), `session`.`State` = 'RUNNING' WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionByThreadID` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionCooldown` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -475,13 +3258,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByPriceHigher`(IN in_pa
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByThreadID`(IN in_param_ThreadID varchar(45)) BEGIN DECLARE declared_in_param_ThreadID CHAR(50); SET declared_in_param_ThreadID = in_param_ThreadID; SELECT `thread`.`OrderID` AS `OrderID`, `thread`.`CummulativeQuoteQty` AS `CummulativeQuoteQty`, `thread`.`Price` AS `Price`, `thread`.`ExecutedQuantity` AS `ExecutedQuantity` FROM `thread` LEFT JOIN `orders` `Orders` ON `thread`.`OrderID` = `Orders`.`OrderID` WHERE `thread`.`ThreadID` = declared_in_param_ThreadID ORDER BY `thread`.`Price` ASC; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionCooldown`(in_ThreadID varchar(45), in_CooldownStart bigint, in_CooldownUntil bigint) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`CooldownStart` = in_CooldownStart, `session`.`CooldownUntil` = in_CooldownUntil WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionCount` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionCycle` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -491,13 +3274,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionByThreadID`(IN in_param
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionCount`(IN in_param varchar(45)) BEGIN DECLARE declared_in_param CHAR(50); SET declared_in_param = in_param; SELECT count(*) AS count FROM thread WHERE thread.ThreadID = declared_in_param; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionCycle`(in_ThreadID varchar(45), in_CycleState varchar(16), in_CycleOrderID bigint, in_CycleOrderIDSource bigint) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`CycleState` = in_CycleState, `session`.`CycleOrderID` = in_CycleOrderID, `session`.`CycleOrderIDSource` = in_CycleOrderIDSource WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactionDistinct` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionPaused` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -507,13 +3290,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionCount`(IN in_param varc
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionDistinct`() BEGIN SELECT DISTINCT ThreadID, ThreadIDSession FROM thread; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionPaused`(in_ThreadID varchar(45), in_Paused tinyint) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`Paused` = in_Paused WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `GetThreadTransactiontUpmarketPriceCount` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionReservation` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -523,13 +3306,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactionDistinct`() BEGIN SELEC
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactiontUpmarketPriceCount`(IN in_param_ThreadID varchar(45), IN in_param_Price float) BEGIN DECLARE declared_in_param_ThreadID CHAR(45); DECLARE declared_in_param_Price float; SET declared_in_param_ThreadID = in_param_ThreadID; SET declared_in_param_Price = in_param_Price; SELECT count(*) AS `count` FROM `thread` WHERE (`thread`.`Price` < declared_in_param_Price AND `thread`.`ThreadID` = declared_in_param_ThreadID); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionReservation`(in_ThreadID varchar(45), in_Reservation float) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`Reservation` = in_Reservation WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `SaveGlobal` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionReservationAll` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -539,13 +3322,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `GetThreadTransactiontUpmarketPriceCount`(IN
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `SaveGlobal`(in_Profit float, in_ProfitNet float, in_ProfitPct float, in_TransactTime bigint) BEGIN INSERT INTO global (Profit, ProfitNet, ProfitPct, TransactTime) VALUES (in_Profit, in_ProfitNet, in_ProfitPct, in_TransactTime); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionReservationAll`(in_Reservation float) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`Reservation` = in_Reservation; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `SaveOrder` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionState` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -555,13 +3338,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `SaveGlobal`(in_Profit float, in_ProfitNet f
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `SaveOrder`(ClientOrderId varchar(45), CummulativeQuoteQty float, ExecutedQuantity float, OrderID bigint, OrderIDSource bigint, Price float, Side varchar(45), Status varchar(45), Symbol varchar(45), TransactTime bigint, ThreadID varchar(45), ThreadIDSession varchar(45)) BEGIN INSERT INTO orders (ClientOrderId, CummulativeQuoteQty, ExecutedQuantity, OrderID, OrderIDSource, Price, Side, Status, Symbol, TransactTime, ThreadID, ThreadIDSession) VALUES (ClientOrderId, CummulativeQuoteQty, ExecutedQuantity, OrderID, OrderIDSource, Price, Side, Status, Symbol, TransactTime, ThreadID, ThreadIDSession); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionState`(in_ThreadID varchar(45), in_State varchar(16)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`State` = in_State WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `SaveSession` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionStopPrice` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -571,13 +3354,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `SaveOrder`(ClientOrderId varchar(45), Cummu
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `SaveSession`(in_ThreadID varchar(45), in_ThreadIDSession varchar(45), in_Exchange varchar(45), in_FiatSymbol varchar(45), in_FiatFunds float, in_DiffTotal float, in_Status tinyint(1)) BEGIN INSERT INTO session (ThreadID, ThreadIDSession, Exchange, FiatSymbol, FiatFunds, DiffTotal, Status) VALUES (in_ThreadID, in_ThreadIDSession, in_Exchange, in_FiatSymbol, in_FiatFunds, in_DiffTotal, in_Status); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionStopPrice`(in_ThreadID varchar(45), in_StopPrice float) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`StopPrice` = in_StopPrice WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `SaveThreadTransaction` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateSessionTrailingHigh` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -587,13 +3370,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `SaveSession`(in_ThreadID varchar(45), in_Th
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `SaveThreadTransaction`(ThreadID varchar(45), ThreadIDSession varchar(45), OrderID bigint, CummulativeQuoteQty float, Price float, ExecutedQuantity float) BEGIN INSERT INTO thread (ThreadID, ThreadIDSession, OrderID, CummulativeQuoteQty, Price, ExecutedQuantity) VALUES (ThreadID, ThreadIDSession, OrderID, CummulativeQuoteQty, Price, ExecutedQuantity); END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSessionTrailingHigh`(in_ThreadID varchar(45), in_TrailingHigh float) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`TrailingHigh` = in_TrailingHigh WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `UpdateGlobal` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateUserLogin` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -603,13 +3386,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `SaveThreadTransaction`(ThreadID varchar(45)
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `UpdateGlobal`(in_Profit float, in_ProfitNet float, in_ProfitPct float, in_TransactTime bigint) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE global SET Profit = in_Profit, ProfitNet = in_ProfitNet, ProfitPct = in_ProfitPct, TransactTime = in_TransactTime WHERE ID = 1; SET SQL_SAFE_UPDATES = 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateUserLogin`(IN in_Username varchar(45), IN in_FailedLogins int, IN in_LockedUntil bigint) BEGIN UPDATE `cryptopump`.`user` SET `user`.`FailedLogins` = in_FailedLogins, `user`.`LockedUntil` = in_LockedUntil WHERE `user`.`Username` = in_Username; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `UpdateOrder` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UpdateUserTOTP` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -619,13 +3402,13 @@ CREATE DEFINER=`root`@`%` PROCEDURE `UpdateGlobal`(in_Profit float, in_ProfitNet
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `UpdateOrder`(in_OrderID bigint, CummulativeQuoteQty float, ExecutedQuantity float, Price float, Status varchar(45)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE orders SET CummulativeQuoteQty = CummulativeQuoteQty, ExecutedQuantity = ExecutedQuantity, Price = Price, Status = Status WHERE OrderID = in_OrderID; SET SQL_SAFE_UPDATES = 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UpdateUserTOTP`(IN in_Username varchar(45), IN in_TOTPSecret varchar(64), IN in_TOTPEnabled tinyint) BEGIN UPDATE `cryptopump`.`user` SET `TOTPSecret` = in_TOTPSecret, `TOTPEnabled` = in_TOTPEnabled WHERE `Username` = in_Username; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
 /*!50003 SET character_set_results = @saved_cs_results */ ;
 /*!50003 SET collation_connection  = @saved_col_connection */ ;
-/*!50003 DROP PROCEDURE IF EXISTS `UpdateSession` */;
+/*!50003 DROP PROCEDURE IF EXISTS `UseRecoveryCode` */;
 /*!50003 SET @saved_cs_client      = @@character_set_client */ ;
 /*!50003 SET @saved_cs_results     = @@character_set_results */ ;
 /*!50003 SET @saved_col_connection = @@collation_connection */ ;
@@ -635,7 +3418,7 @@ CREATE DEFINER=`root`@`%` PROCEDURE `UpdateOrder`(in_OrderID bigint, Cummulative
 /*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
 /*!50003 SET sql_mode              = 'ONLY_FULL_GROUP_BY,STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION' */ ;
 
-CREATE DEFINER=`root`@`%` PROCEDURE `UpdateSession`(in_ThreadID varchar(45), in_ThreadIDSession varchar(45), in_Exchange varchar(45), in_FiatSymbol varchar(45), in_FiatFunds float, in_DiffTotal float, in_Status tinyint(1)) BEGIN SET SQL_SAFE_UPDATES = 0; UPDATE `session` SET `session`.`FiatFunds` = in_FiatFunds, `session`.`DiffTotal` = in_DiffTotal, `session`.`Status` = in_Status WHERE `session`.`ThreadID` = in_ThreadID; SET SQL_SAFE_UPDATES = 1; END;
+CREATE DEFINER=`root`@`%` PROCEDURE `UseRecoveryCode`(IN in_Username varchar(45), IN in_CodeHash varchar(64)) BEGIN DELETE FROM `cryptopump`.`recoverycode` WHERE `Username` = in_Username AND `CodeHash` = in_CodeHash; SELECT ROW_COUNT() AS `Used`; END;
 
 /*!50003 SET sql_mode              = @saved_sql_mode */ ;
 /*!50003 SET character_set_client  = @saved_cs_client */ ;
@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// ErrDBUnavailable is matched with errors.Is by the errors of the calls that couldn't reach the database, so callers
+// can retry later instead of failing the thread
+var ErrDBUnavailable = errors.New("database unavailable")
+
+/* Database error of a call that couldn't reach the database */
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string {
+
+	return e.err.Error()
+
+}
+
+func (e *unavailableError) Unwrap() error {
+
+	return e.err
+
+}
+
+func (e *unavailableError) Is(target error) bool {
+
+	return target == ErrDBUnavailable
+
+}
+
+/* Return err matching ErrDBUnavailable when it is a connection failure */
+func classify(err error) error {
+
+	var netErr net.Error
+	var mysqlErr *mysqldriver.MySQLError
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone), errors.Is(err, mysqldriver.ErrInvalidConn),
+		errors.As(err, &netErr):
+		return &unavailableError{err: err}
+	case errors.As(err, &mysqlErr) && mysqlErr.Number == 1040: /* Too many connections */
+		return &unavailableError{err: err}
+	}
+
+	return err
+
+}
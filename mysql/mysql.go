@@ -2,14 +2,19 @@ package mysql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"math"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/metrics"
+	"github.com/aleibovici/cryptopump/tracing"
 	"github.com/aleibovici/cryptopump/types"
 
 	_ "github.com/go-sql-driver/mysql" // This blank entry is required to enable mysql connectivity
@@ -154,6 +159,70 @@ func InitTCPConnectionPool() (*sql.DB, error) {
 	// [END cloud_sql_mysql_databasesql_create_tcp]
 }
 
+/* Call a stored procedure, counted in progress until it returns, its latency is recorded for the metrics endpoint and traced when processing a trade */
+func query(
+	sessionData *types.Session,
+	call string,
+	args ...interface{}) (*sql.Rows, error) {
+
+	atomic.AddInt64(&inflight, 1)
+	defer atomic.AddInt64(&inflight, -1)
+
+	span := tracing.Start(sessionData, "mysql."+procedure(call))
+
+	start := time.Now()
+	rows, err := sessionData.Db.Query(call, args...)
+	err = classify(err) /* Connection failures match ErrDBUnavailable */
+
+	metrics.ObserveQuery(procedure(call), time.Since(start))
+	span.End(err)
+
+	return rows, err
+
+}
+
+var inflight int64 /* Stored procedure calls in progress */
+
+// Drain wait up to timeout for the stored procedure calls in progress to complete, returning false on timeout
+func Drain(timeout time.Duration) bool {
+
+	deadline := time.Now().Add(timeout)
+
+	for atomic.LoadInt64(&inflight) > 0 {
+
+		if !time.Now().Before(deadline) {
+
+			return false
+
+		}
+
+		time.Sleep(50 * time.Millisecond)
+
+	}
+
+	return true
+
+}
+
+/* Return the stored procedure name of a call, i.e. GetPendingActions for call cryptopump.GetPendingActions() */
+func procedure(call string) string {
+
+	name := strings.TrimPrefix(call, "call cryptopump.")
+
+	if i := strings.Index(name, "("); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+
+}
+
+/* Order events of the audit table */
+const (
+	AuditOrderCreate = "order.create"
+	AuditOrderUpdate = "order.update"
+)
+
 // SaveOrder Save order to database
 func SaveOrder(
 	sessionData *types.Session,
@@ -167,7 +236,7 @@ func SaveOrder(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.SaveOrder(?,?,?,?,?,?,?,?,?,?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.SaveOrder(?,?,?,?,?,?,?,?,?,?,?,?,?)",
 		order.ClientOrderID,
 		order.CumulativeQuoteQuantity,
 		order.ExecutedQuantity,
@@ -179,7 +248,8 @@ func SaveOrder(
 		order.Symbol,
 		order.TransactTime,
 		sessionData.ThreadID,
-		sessionData.ThreadIDSession); err != nil {
+		sessionData.ThreadIDSession,
+		sessionData.CorrelationID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:  nil,
@@ -199,6 +269,13 @@ func SaveOrder(
 
 	defer rows.Close() /* Close rows */
 
+	audited := *order
+	audited.Price = orderPrice
+	audited.OrderIDSource = orderIDSource
+	audited.CorrelationID = sessionData.CorrelationID
+
+	auditOrder(sessionData, AuditOrderCreate, audited)
+
 	return nil
 
 }
@@ -218,7 +295,7 @@ func UpdateOrder(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.UpdateOrder(?,?,?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.UpdateOrder(?,?,?,?,?)",
 		OrderID,
 		CumulativeQuoteQuantity,
 		ExecutedQuantity,
@@ -243,6 +320,125 @@ func UpdateOrder(
 
 	defer rows.Close() /* Close rows */
 
+	auditOrder(sessionData, AuditOrderUpdate, types.Order{
+		CumulativeQuoteQuantity: CumulativeQuoteQuantity,
+		ExecutedQuantity:        ExecutedQuantity,
+		OrderID:                 OrderID,
+		Price:                   Price,
+		Status:                  Status,
+	})
+
+	return nil
+
+}
+
+// UpdateOrderType Update order type (TRADE, REBALANCE)
+func UpdateOrderType(
+	sessionData *types.Session,
+	OrderID int64,
+	Type string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateOrderType(?,?)",
+		OrderID,
+		Type); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:  nil,
+			Market:  nil,
+			Session: sessionData,
+			Order: &types.Order{
+				OrderID: OrderID,
+			},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateOrderSource Update order source (bot, operator)
+func UpdateOrderSource(
+	sessionData *types.Session,
+	OrderID int64,
+	Source string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateOrderSource(?,?)",
+		OrderID,
+		Source); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:  nil,
+			Market:  nil,
+			Session: sessionData,
+			Order: &types.Order{
+				OrderID: OrderID,
+			},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateOrderScore Update the weighted indicator score that triggered the order
+func UpdateOrderScore(
+	sessionData *types.Session,
+	OrderID int64,
+	Score float64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateOrderScore(?,?)",
+		OrderID,
+		Score); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:  nil,
+			Market:  nil,
+			Session: sessionData,
+			Order: &types.Order{
+				OrderID: OrderID,
+			},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
 	return nil
 
 }
@@ -258,7 +454,7 @@ func UpdateSession(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.UpdateSession(?,?,?,?,?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.UpdateSession(?,?,?,?,?,?,?)",
 		sessionData.ThreadID,
 		sessionData.ThreadIDSession,
 		configData.ExchangeName,
@@ -296,7 +492,7 @@ func UpdateGlobal(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.UpdateGlobal(?,?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.UpdateGlobal(?,?,?,?)",
 		sessionData.Global.Profit,
 		sessionData.Global.ProfitNet,
 		sessionData.Global.ProfitPct,
@@ -331,7 +527,7 @@ func SaveGlobal(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.SaveGlobal(?,?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.SaveGlobal(?,?,?,?)",
 		sessionData.Global.Profit,
 		sessionData.Global.ProfitNet,
 		sessionData.Global.ProfitPct,
@@ -367,7 +563,7 @@ func SaveSession(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.SaveSession(?,?,?,?,?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.SaveSession(?,?,?,?,?,?,?)",
 		sessionData.ThreadID,
 		sessionData.ThreadIDSession,
 		configData.ExchangeName,
@@ -405,7 +601,7 @@ func DeleteSession(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.DeleteSession(?)",
+	if rows, err = query(sessionData, "call cryptopump.DeleteSession(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -437,7 +633,7 @@ func GetSessionStatus(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetSessionStatus()"); err != nil {
+	if rows, err = query(sessionData, "call cryptopump.GetSessionStatus()"); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -480,7 +676,7 @@ func SaveThreadTransaction(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.SaveThreadTransaction(?,?,?,?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.SaveThreadTransaction(?,?,?,?,?,?)",
 		sessionData.ThreadID,
 		sessionData.ThreadIDSession,
 		OrderID,
@@ -521,7 +717,7 @@ func DeleteThreadTransactionByOrderID(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.DeleteThreadTransactionByOrderID(?)",
+	if rows, err = query(sessionData, "call cryptopump.DeleteThreadTransactionByOrderID(?)",
 		orderID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -555,7 +751,7 @@ func GetThreadTransactionCount(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadTransactionCount(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionCount(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -592,7 +788,7 @@ func GetLastOrderTransactionPrice(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetLastOrderTransactionPrice(?,?)",
+	if rows, err = query(sessionData, "call cryptopump.GetLastOrderTransactionPrice(?,?)",
 		sessionData.ThreadID,
 		Side); err != nil {
 
@@ -629,7 +825,7 @@ func GetLastOrderTransactionSide(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetLastOrderTransactionSide(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetLastOrderTransactionSide(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -665,7 +861,7 @@ func GetOrderTransactionSideLastTwo(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetOrderTransactionSideLastTwo(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetOrderTransactionSideLastTwo(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -701,7 +897,7 @@ func GetOrderSymbol(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetOrderSymbol(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetOrderSymbol(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -727,6 +923,43 @@ func GetOrderSymbol(
 
 }
 
+// GetOrderCorrelationID Get the correlation ID of the trade cycle of orderID, empty for the orders saved without one
+func GetOrderCorrelationID(
+	sessionData *types.Session,
+	orderID int64) (correlationID string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetOrderCorrelationID(?)",
+		orderID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{OrderID: orderID},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return "", err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&correlationID)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return correlationID, err
+
+}
+
 // GetThreadTransactionDistinct Get Thread Distinct
 func GetThreadTransactionDistinct(
 	sessionData *types.Session) (threadID string, threadIDSession string, err error) {
@@ -737,7 +970,7 @@ func GetThreadTransactionDistinct(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadTransactionDistinct()"); err != nil {
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionDistinct()"); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -777,6 +1010,91 @@ func GetThreadTransactionDistinct(
 
 }
 
+// GetThreadTransactionDistinctBySymbol Get the first thread with transactions of sessionData.Symbol without a lock file
+func GetThreadTransactionDistinctBySymbol(
+	sessionData *types.Session) (threadID string, threadIDSession string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionDistinctBySymbol(?)",
+		sessionData.Symbol); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return "", "", err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	for rows.Next() {
+		err = rows.Scan(
+			&threadID,
+			&threadIDSession)
+
+		/* Verify if lock file for thread exist. If lock file doesn't exist leave function with the thread */
+		if _, err := os.Stat(threadID + ".lock"); err != nil {
+
+			return threadID, threadIDSession, nil
+
+		}
+
+	}
+
+	return "", "", err
+
+}
+
+// GetThreadTransactions retrieve the open transactions of all threads with the symbol of the order that opened them
+func GetThreadTransactions(
+	sessionData *types.Session) (transactions []types.ThreadTransaction, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactions()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		transaction := types.ThreadTransaction{}
+		err = rows.Scan(&transaction.ThreadID, &transaction.ThreadIDSession, &transaction.OrderID, &transaction.Symbol, &transaction.ExecutedQuantity)
+		transactions = append(transactions, transaction)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return transactions, err
+
+}
+
 // GetOrderTransactionPending Get 1 order with pending FILLED status
 func GetOrderTransactionPending(
 	sessionData *types.Session) (order types.Order, err error) {
@@ -787,7 +1105,7 @@ func GetOrderTransactionPending(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetOrderTransactionPending(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetOrderTransactionPending(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -826,7 +1144,7 @@ func GetThreadTransactionByPrice(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadTransactionByPrice(?,?)",
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionByPrice(?,?)",
 		sessionData.ThreadID,
 		marketData.Price); err != nil {
 
@@ -870,7 +1188,7 @@ func GetThreadTransactionByPriceHigher(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadTransactionByPriceHigher(?,?)",
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionByPriceHigher(?,?)",
 		sessionData.ThreadID,
 		marketData.Price); err != nil {
 
@@ -912,7 +1230,7 @@ func GetThreadLastTransaction(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadLastTransaction(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetThreadLastTransaction(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -953,7 +1271,7 @@ func GetOrderByOrderID(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetOrderByOrderID(?,?)",
+	if rows, err = query(sessionData, "call cryptopump.GetOrderByOrderID(?,?)",
 		sessionData.ForceSellOrderID,
 		sessionData.ThreadID); err != nil {
 
@@ -998,7 +1316,7 @@ func GetThreadTransactiontUpmarketPriceCount(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadTransactiontUpmarketPriceCount(?,?)",
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactiontUpmarketPriceCount(?,?)",
 		sessionData.ThreadID,
 		price); err != nil {
 
@@ -1036,7 +1354,7 @@ func GetOrderTransactionCount(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetOrderTransactionCount(?,?,?)",
+	if rows, err = query(sessionData, "call cryptopump.GetOrderTransactionCount(?,?,?)",
 		sessionData.ThreadID,
 		side,
 		(60 * -1)); err != nil {
@@ -1076,7 +1394,7 @@ func GetThreadTransactionByThreadID(
 
 	order := types.Order{}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadTransactionByThreadID(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionByThreadID(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -1123,7 +1441,7 @@ func GetProfitByThreadID(sessionData *types.Session) (fiat float64, percentage f
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetProfitByThreadID(?)",
+	if rows, err = query(sessionData, "call cryptopump.GetProfitByThreadID(?)",
 		sessionData.ThreadID); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
@@ -1162,7 +1480,7 @@ func GetProfit(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetProfit()"); err != nil {
+	if rows, err = query(sessionData, "call cryptopump.GetProfit()"); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -1195,7 +1513,7 @@ func GetGlobal(sessionData *types.Session) (profit float64, profitNet float64, p
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetGlobal()"); err != nil {
+	if rows, err = query(sessionData, "call cryptopump.GetGlobal()"); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -1229,7 +1547,7 @@ func GetThreadCount(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadCount()"); err != nil {
+	if rows, err = query(sessionData, "call cryptopump.GetThreadCount()"); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -1265,7 +1583,7 @@ func GetThreadAmount(
 		sessionData.Db.Begin() /* Start transaction */
 	}
 
-	if rows, err = sessionData.Db.Query("call cryptopump.GetThreadTransactionAmount()"); err != nil {
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionAmount()"); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -1289,3 +1607,4662 @@ func GetThreadAmount(
 	return math.Round(amountNullFloat64.Float64*100) / 100, err
 
 }
+
+// GetThreadAmountByThreadID Retrieve Thread Dollar Amount for a ThreadID
+func GetThreadAmountByThreadID(
+	sessionData *types.Session) (amount float64, err error) {
+
+	var rows *sql.Rows                    /* Rows */
+	var amountNullFloat64 sql.NullFloat64 /* handle null mysql returns */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTransactionAmountByThreadID(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return amountNullFloat64.Float64, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&amountNullFloat64)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return math.Round(amountNullFloat64.Float64*100) / 100, err
+
+}
+
+// GetThreadCycleProfitLast retrieve profit and sell time of the last completed BUY/SELL cycles for a ThreadID
+func GetThreadCycleProfitLast(
+	sessionData *types.Session,
+	limit int) (profits []float64, transactTimes []int64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadCycleProfitLast(?,?)",
+		sessionData.ThreadID,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, nil, err
+
+	}
+
+	for rows.Next() {
+
+		var profit float64
+		var transactTime int64
+		err = rows.Scan(&profit, &transactTime)
+
+		profits = append(profits, profit)
+		transactTimes = append(transactTimes, transactTime)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return profits, transactTimes, err
+
+}
+
+// GetSessionCooldown retrieve loss streak cooldown start and end (unix seconds) for a ThreadID
+func GetSessionCooldown(
+	sessionData *types.Session) (cooldownStart int64, cooldownUntil int64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionCooldown(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&cooldownStart, &cooldownUntil)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return cooldownStart, cooldownUntil, err
+
+}
+
+// UpdateSessionCooldown Update loss streak cooldown period on Session table
+func UpdateSessionCooldown(
+	sessionData *types.Session) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionCooldown(?,?,?)",
+		sessionData.ThreadID,
+		sessionData.CooldownStart.Unix(),
+		sessionData.CooldownUntil.Unix()); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetSessionStopPrice retrieve absolute stop price for a ThreadID
+func GetSessionStopPrice(
+	sessionData *types.Session) (stopPrice float64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionStopPrice(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&stopPrice)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return stopPrice, err
+
+}
+
+// UpdateSessionStopPrice Update absolute stop price on Session table
+func UpdateSessionStopPrice(
+	sessionData *types.Session) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionStopPrice(?,?)",
+		sessionData.ThreadID,
+		sessionData.StopPrice); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetSessionPaused retrieve the paused state of a ThreadID
+func GetSessionPaused(
+	sessionData *types.Session) (paused bool, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionPaused(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return false, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&paused)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return paused, err
+
+}
+
+// GetSessionCycle retrieve the trade cycle state and order in flight of a ThreadID
+func GetSessionCycle(
+	sessionData *types.Session) (state string, orderID int64, orderIDSource int64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionCycle(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return "", 0, 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&state, &orderID, &orderIDSource)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return state, orderID, orderIDSource, err
+
+}
+
+// UpdateSessionCycle Update the trade cycle state and order in flight on Session table
+func UpdateSessionCycle(
+	sessionData *types.Session) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionCycle(?,?,?,?)",
+		sessionData.ThreadID,
+		sessionData.CycleState,
+		sessionData.CycleOrderID,
+		sessionData.CycleOrderIDSource); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateSessionPaused Update the paused state on Session table
+func UpdateSessionPaused(
+	sessionData *types.Session) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionPaused(?,?)",
+		sessionData.ThreadID,
+		sessionData.Paused); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// Session states, sessions STOPPED by a graceful shutdown are kept for the thread to resume
+const (
+	SessionRunning = "RUNNING"
+	SessionStopped = "STOPPED"
+)
+
+// UpdateSessionState Update the state on Session table, set back to RUNNING by UpdateSession
+func UpdateSessionState(
+	sessionData *types.Session,
+	state string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionState(?,?)",
+		sessionData.ThreadID,
+		state); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetSessionReservation retrieve fiat funds reserved for a ThreadID
+func GetSessionReservation(
+	sessionData *types.Session) (reservation float64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionReservation(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&reservation)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return reservation, err
+
+}
+
+// UpdateSessionReservation Update fiat funds reserved for a ThreadID on Session table
+func UpdateSessionReservation(
+	sessionData *types.Session) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionReservation(?,?)",
+		sessionData.ThreadID,
+		sessionData.Reservation); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateSessionReservationByThreadID Update fiat funds reserved for threadID on Session table
+func UpdateSessionReservationByThreadID(
+	sessionData *types.Session,
+	threadID string,
+	reservation float64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionReservation(?,?)",
+		threadID,
+		reservation); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateSessionReservationAll Update fiat funds reserved for all ThreadIDs on Session table
+func UpdateSessionReservationAll(
+	sessionData *types.Session,
+	reservation float64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionReservationAll(?)",
+		reservation); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetSessionReservedFunds retrieve fiat funds reserved and not yet used by ThreadIDs other than sessionData.ThreadID
+func GetSessionReservedFunds(
+	sessionData *types.Session) (reserved float64, err error) {
+
+	var rows *sql.Rows                      /* Rows */
+	var reservedNullFloat64 sql.NullFloat64 /* handle null mysql returns */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionReservedFunds(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return reservedNullFloat64.Float64, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&reservedNullFloat64)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return math.Round(reservedNullFloat64.Float64*100) / 100, err
+
+}
+
+// GetSessionCount retrieve the number of ThreadIDs in Session table
+func GetSessionCount(
+	sessionData *types.Session) (count int, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionCount()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&count)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return count, err
+
+}
+
+// GetSessions retrieve all sessions
+func GetSessions(
+	sessionData *types.Session) (sessions []types.SessionSummary, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessions()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		var tags string
+
+		session := types.SessionSummary{}
+		err = rows.Scan(&session.ThreadID, &session.ThreadIDSession, &session.Exchange, &session.FiatSymbol, &session.FiatFunds, &session.DiffTotal, &session.Status, &session.Name, &tags)
+
+		if tags != "" {
+			session.Tags = strings.Split(tags, ",")
+		}
+
+		sessions = append(sessions, session)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return sessions, err
+
+}
+
+// GetSessionAllocations retrieve the reservation, open transactions amount and profit realized since transactTime
+// (milliseconds) of each running ThreadID in Session table
+func GetSessionAllocations(
+	sessionData *types.Session,
+	transactTime int64) (allocations []types.SessionAllocation, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionAllocations(?)",
+		transactTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		allocation := types.SessionAllocation{}
+		err = rows.Scan(&allocation.ThreadID, &allocation.Symbol, &allocation.Reservation, &allocation.Amount, &allocation.Profit, &allocation.Cost)
+		allocations = append(allocations, allocation)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return allocations, err
+
+}
+
+// SaveSessionLabel save the name and tags of label.ThreadID, deleting them when both are empty
+func SaveSessionLabel(
+	sessionData *types.Session,
+	label types.SessionLabel) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveSessionLabel(?,?,?,?)",
+		label.ThreadID,
+		label.Name,
+		strings.Join(label.Tags, ","),
+		time.Now().UnixNano()/int64(time.Millisecond)); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetSessionLabels retrieve the name and tags of the named or tagged ThreadIDs ordered by name
+func GetSessionLabels(
+	sessionData *types.Session) (labels []types.SessionLabel, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionLabels()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		var tags string
+
+		label := types.SessionLabel{}
+		err = rows.Scan(&label.ThreadID, &label.Name, &tags)
+
+		if tags != "" {
+			label.Tags = strings.Split(tags, ",")
+		}
+
+		labels = append(labels, label)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return labels, err
+
+}
+
+// GetSessionHeartbeats retrieve the last heartbeat time in milliseconds of each ThreadID in Session table, updated
+// by UpdateSession
+func GetSessionHeartbeats(
+	sessionData *types.Session) (heartbeats map[string]int64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionHeartbeats()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	heartbeats = make(map[string]int64)
+
+	for rows.Next() {
+
+		var threadID string
+		var heartbeat int64
+
+		err = rows.Scan(&threadID, &heartbeat)
+		heartbeats[threadID] = heartbeat
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return heartbeats, err
+
+}
+
+// GetSessionTrailingHigh retrieve aggregate trailing stop high-water mark for a ThreadID
+func GetSessionTrailingHigh(
+	sessionData *types.Session) (trailingHigh float64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSessionTrailingHigh(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&trailingHigh)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return trailingHigh, err
+
+}
+
+// UpdateSessionTrailingHigh Update aggregate trailing stop high-water mark on Session table
+func UpdateSessionTrailingHigh(
+	sessionData *types.Session) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateSessionTrailingHigh(?,?)",
+		sessionData.ThreadID,
+		sessionData.TrailingHigh); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetThreadAverageEntry retrieve weighted average entry price of all open transactions for a ThreadID
+func GetThreadAverageEntry(
+	sessionData *types.Session) (averageEntry float64, err error) {
+
+	var rows *sql.Rows                          /* Rows */
+	var averageEntryNullFloat64 sql.NullFloat64 /* handle null mysql returns */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadAverageEntry(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&averageEntryNullFloat64)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return averageEntryNullFloat64.Float64, err
+
+}
+
+// GetThreadSymbolExposure retrieve open transaction amount by Symbol across all threads
+func GetThreadSymbolExposure(
+	sessionData *types.Session) (exposure map[string]float64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadSymbolExposure()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	exposure = make(map[string]float64)
+
+	for rows.Next() {
+
+		var symbol string
+		var amount sql.NullFloat64
+		err = rows.Scan(&symbol, &amount)
+
+		exposure[symbol] = amount.Float64
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return exposure, err
+
+}
+
+// GetThreadSymbolQuantity retrieve open transaction quantity of sessionData.Symbol across all threads
+func GetThreadSymbolQuantity(
+	sessionData *types.Session) (quantity float64, err error) {
+
+	var rows *sql.Rows                      /* Rows */
+	var quantityNullFloat64 sql.NullFloat64 /* handle null mysql returns */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadSymbolQuantity(?)", sessionData.Symbol); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&quantityNullFloat64)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return quantityNullFloat64.Float64, err
+
+}
+
+// GetGlobalEquity retrieve equity across all threads (fiat funds plus cost and unrealized difference of open transactions)
+func GetGlobalEquity(
+	sessionData *types.Session) (equity float64, err error) {
+
+	var rows *sql.Rows                    /* Rows */
+	var equityNullFloat64 sql.NullFloat64 /* handle null mysql returns */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetGlobalEquity()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&equityNullFloat64)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return equityNullFloat64.Float64, err
+
+}
+
+// GetGlobalDrawdown retrieve equity peak and drawdown kill switch status
+func GetGlobalDrawdown(
+	sessionData *types.Session) (equityPeak float64, drawdownHalt bool, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetGlobalDrawdown()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, false, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&equityPeak, &drawdownHalt)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return equityPeak, drawdownHalt, err
+
+}
+
+// UpdateGlobalDrawdown Update equity peak and drawdown kill switch status
+func UpdateGlobalDrawdown(
+	sessionData *types.Session,
+	equityPeak float64,
+	drawdownHalt bool) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateGlobalDrawdown(?,?)",
+		equityPeak,
+		drawdownHalt); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetProfitSince retrieve realized profit of BUY/SELL cycles sold since a given time across all threads
+func GetProfitSince(
+	sessionData *types.Session,
+	since time.Time) (profit float64, err error) {
+
+	var rows *sql.Rows                    /* Rows */
+	var profitNullFloat64 sql.NullFloat64 /* handle null mysql returns */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetProfitSince(?)",
+		since.UnixNano()/int64(time.Millisecond)); err != nil { /* Exchange TransactTime is in milliseconds */
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&profitNullFloat64)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return profitNullFloat64.Float64, err
+
+}
+
+// SaveVolatilityTrip Save volatility circuit breaker trip
+func SaveVolatilityTrip(
+	sessionData *types.Session,
+	reason string,
+	sigma float64,
+	threshold float64,
+	transactTime int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveVolatilityTrip(?,?,?,?,?,?)",
+		sessionData.ThreadID,
+		sessionData.Symbol,
+		reason,
+		sigma,
+		threshold,
+		transactTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetGlobalLiquidate retrieve emergency liquidation status and the time it was confirmed
+func GetGlobalLiquidate(
+	sessionData *types.Session) (liquidate bool, liquidateTime int64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetGlobalLiquidate()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return false, 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&liquidate, &liquidateTime)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return liquidate, liquidateTime, err
+
+}
+
+// UpdateGlobalLiquidate Update emergency liquidation status and the time it was confirmed
+func UpdateGlobalLiquidate(
+	sessionData *types.Session,
+	liquidate bool,
+	liquidateTime int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateGlobalLiquidate(?,?)",
+		liquidate,
+		liquidateTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// SaveLiquidationReport Save a report of the sales executed by emergency liquidation for ThreadID between startTime and endTime
+func SaveLiquidationReport(
+	sessionData *types.Session,
+	startTime int64,
+	endTime int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveLiquidationReport(?,?,?,?)",
+		sessionData.ThreadID,
+		sessionData.Symbol,
+		startTime,
+		endTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetSymbolList retrieve the symbol allow/deny list as a map of Symbol to List (ALLOW or DENY)
+func GetSymbolList(
+	sessionData *types.Session) (symbolList map[string]string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetSymbolList()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	symbolList = make(map[string]string)
+
+	for rows.Next() {
+
+		var symbol string
+		var list string
+		err = rows.Scan(&symbol, &list)
+
+		symbolList[symbol] = list
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return symbolList, err
+
+}
+
+// SaveSymbolList Save Symbol to the symbol allow/deny list (ALLOW or DENY)
+func SaveSymbolList(
+	sessionData *types.Session,
+	symbol string,
+	list string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveSymbolList(?,?)",
+		symbol,
+		list); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteSymbolList Delete all symbols from the symbol allow/deny list
+func DeleteSymbolList(
+	sessionData *types.Session) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteSymbolList()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// SavePendingAction Save a manual action awaiting operator confirmation to pendingaction table
+func SavePendingAction(
+	sessionData *types.Session,
+	action types.PendingAction) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SavePendingAction(?,?,?,?,?)",
+		action.ThreadID,
+		action.Action,
+		action.OrderID,
+		action.Notional,
+		action.CreatedTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{OrderID: action.OrderID},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetPendingAction retrieve the most recent manual action awaiting operator confirmation for sessionData.ThreadID
+func GetPendingAction(
+	sessionData *types.Session) (action types.PendingAction, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetPendingAction(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return types.PendingAction{}, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(
+			&action.ID,
+			&action.Action,
+			&action.OrderID,
+			&action.Notional,
+			&action.CreatedTime)
+	}
+
+	action.ThreadID = sessionData.ThreadID
+
+	defer rows.Close() /* Close rows */
+
+	return action, err
+
+}
+
+// GetPendingActions retrieve the manual actions of all threads awaiting operator confirmation
+func GetPendingActions(
+	sessionData *types.Session) (actions []types.PendingAction, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetPendingActions()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		action := types.PendingAction{}
+		err = rows.Scan(&action.ID, &action.ThreadID, &action.Action, &action.OrderID, &action.Notional, &action.CreatedTime)
+		actions = append(actions, action)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return actions, err
+
+}
+
+// UpdatePendingAction Update the status of a manual action (APPROVED, REJECTED or EXPIRED)
+func UpdatePendingAction(
+	sessionData *types.Session,
+	id int64,
+	status string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdatePendingAction(?,?)",
+		id,
+		status); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetUser retrieve a dashboard user by username
+func GetUser(
+	sessionData *types.Session,
+	username string) (user types.User, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetUser(?)",
+		username); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return types.User{}, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&user.Username, &user.PasswordHash, &user.Role, &user.FailedLogins, &user.LockedUntil, &user.TOTPSecret, &user.TOTPEnabled)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return user, err
+
+}
+
+// GetUserCount retrieve the number of dashboard users
+func GetUserCount(
+	sessionData *types.Session) (count int, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetUserCount()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&count)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return count, err
+
+}
+
+// SaveUser Save a dashboard user, or reset its password, role and lockout when it exists
+func SaveUser(
+	sessionData *types.Session,
+	user types.User) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveUser(?,?,?)",
+		user.Username,
+		user.PasswordHash,
+		user.Role); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateUserLogin Update the failed login count and lockout of a dashboard user
+func UpdateUserLogin(
+	sessionData *types.Session,
+	username string,
+	failedLogins int,
+	lockedUntil int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateUserLogin(?,?,?)",
+		username,
+		failedLogins,
+		lockedUntil); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateUserTOTP Save the TOTP secret and two-factor authentication state of a dashboard user
+func UpdateUserTOTP(
+	sessionData *types.Session,
+	username string,
+	secret string,
+	enabled bool) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateUserTOTP(?,?,?)",
+		username,
+		secret,
+		enabled); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// SaveRecoveryCode Save the hash of a two-factor authentication recovery code
+func SaveRecoveryCode(
+	sessionData *types.Session,
+	username string,
+	codeHash string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveRecoveryCode(?,?)",
+		username,
+		codeHash); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteRecoveryCodes Delete all two-factor authentication recovery codes of a dashboard user
+func DeleteRecoveryCodes(
+	sessionData *types.Session,
+	username string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteRecoveryCodes(?)",
+		username); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UseRecoveryCode Delete a two-factor authentication recovery code and return true when it existed
+func UseRecoveryCode(
+	sessionData *types.Session,
+	username string,
+	codeHash string) (used bool, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UseRecoveryCode(?,?)",
+		username,
+		codeHash); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return false, err
+
+	}
+
+	var count int64
+
+	for rows.Next() {
+		err = rows.Scan(&count)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return count > 0, err
+
+}
+
+// SaveAuthToken Save a UI session or REST API token hash
+func SaveAuthToken(
+	sessionData *types.Session,
+	tokenHash string,
+	token types.AuthToken) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveAuthToken(?,?,?,?,?)",
+		tokenHash,
+		token.Username,
+		token.Kind,
+		token.Expires,
+		token.LastSeen); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// UpdateAuthTokenLastSeen Save the last request time of a UI session token
+func UpdateAuthTokenLastSeen(
+	sessionData *types.Session,
+	tokenHash string,
+	lastSeen int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateAuthTokenLastSeen(?,?)",
+		tokenHash,
+		lastSeen); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteExcessAuthTokens Delete the tokens of kind of a user except the keep most recently used
+func DeleteExcessAuthTokens(
+	sessionData *types.Session,
+	username string,
+	kind string,
+	keep int) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteExcessAuthTokens(?,?,?)",
+		username,
+		kind,
+		keep); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetAuthToken retrieve a UI session or REST API token by token hash
+func GetAuthToken(
+	sessionData *types.Session,
+	tokenHash string) (token types.AuthToken, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetAuthToken(?)",
+		tokenHash); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return types.AuthToken{}, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&token.Username, &token.Role, &token.Kind, &token.Expires, &token.LastSeen)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return token, err
+
+}
+
+// DeleteAuthToken Delete a UI session or REST API token by token hash
+func DeleteAuthToken(
+	sessionData *types.Session,
+	tokenHash string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteAuthToken(?)",
+		tokenHash); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteAuthTokenByUsername Delete all tokens of kind issued to username
+func DeleteAuthTokenByUsername(
+	sessionData *types.Session,
+	username string,
+	kind string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteAuthTokenByUsername(?,?)",
+		username,
+		kind); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// SaveKline save a final kline for a ThreadID, retaining the last 24 hours
+func SaveKline(
+	sessionData *types.Session,
+	openTime int64,
+	kline types.KlineData) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveKline(?,?,?,?,?,?,?,?,?,?)",
+		sessionData.ThreadID,
+		openTime,
+		kline.Date,
+		kline.Data[0],
+		kline.Data[1],
+		kline.Data[2],
+		kline.Data[3],
+		kline.Volumes,
+		kline.Ma7,
+		kline.Ma14); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetKline retrieve the persisted klines for a ThreadID ordered by time
+func GetKline(
+	sessionData *types.Session) (klines []types.KlineData, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetKline(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		kline := types.KlineData{}
+		err = rows.Scan(&kline.Date, &kline.Data[0], &kline.Data[1], &kline.Data[2], &kline.Data[3], &kline.Volumes, &kline.Ma7, &kline.Ma14)
+		klines = append(klines, kline)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return klines, err
+
+}
+
+// GetThreadOrdersSince retrieve the filled BUY and SELL orders of a ThreadID since transactTime
+func GetThreadOrdersSince(
+	sessionData *types.Session,
+	transactTime int64) (orders []types.Order, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadOrdersSince(?,?)",
+		sessionData.ThreadID,
+		transactTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		order := types.Order{}
+		err = rows.Scan(&order.OrderID, &order.Price, &order.Side, &order.TransactTime)
+		orders = append(orders, order)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return orders, err
+
+}
+
+// SaveEquity save a portfolio valuation snapshot across all threads
+func SaveEquity(
+	sessionData *types.Session,
+	snapshot types.EquitySnapshot) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveEquity(?,?)",
+		snapshot.Time,
+		snapshot.Equity); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetEquitySince retrieve portfolio valuation snapshots since a given time in milliseconds ordered by time
+func GetEquitySince(
+	sessionData *types.Session,
+	since int64) (snapshots []types.EquitySnapshot, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetEquitySince(?)",
+		since); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		snapshot := types.EquitySnapshot{}
+		err = rows.Scan(&snapshot.Time, &snapshot.Equity)
+		snapshots = append(snapshots, snapshot)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return snapshots, err
+
+}
+
+// GetThreadCycles retrieve a page of closed BUY/SELL cycles for a ThreadID, most recent sale first
+func GetThreadCycles(
+	sessionData *types.Session,
+	limit int,
+	offset int) (cycles []types.ThreadCycle, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadCycles(?,?,?)",
+		sessionData.ThreadID,
+		limit,
+		offset); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		cycle := types.ThreadCycle{}
+		err = rows.Scan(&cycle.BuyOrderID, &cycle.SellOrderID, &cycle.Quantity, &cycle.BuyPrice, &cycle.SellPrice, &cycle.BuyQuote, &cycle.SellQuote, &cycle.TransactTime)
+		cycles = append(cycles, cycle)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return cycles, err
+
+}
+
+// GetThreadCycleCount retrieve the number of closed BUY/SELL cycles for a ThreadID
+func GetThreadCycleCount(
+	sessionData *types.Session) (count int, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadCycleCount(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&count)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return count, err
+
+}
+
+// SaveConfigAudit save a new version of the configuration for a ThreadID
+func SaveConfigAudit(
+	sessionData *types.Session,
+	username string,
+	config string,
+	changes string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveConfigAudit(?,?,?,?,?)",
+		sessionData.ThreadID,
+		username,
+		time.Now().UnixNano()/int64(time.Millisecond),
+		config,
+		changes); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetConfigAudit retrieve the latest configuration versions for a ThreadID, most recent first
+func GetConfigAudit(
+	sessionData *types.Session,
+	limit int) (versions []types.ConfigVersion, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetConfigAudit(?,?)",
+		sessionData.ThreadID,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		version := types.ConfigVersion{}
+		err = rows.Scan(&version.Version, &version.Username, &version.Time, &version.Changes)
+		versions = append(versions, version)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return versions, err
+
+}
+
+// SavePreset save a named strategy preset, replacing an existing preset with the same name
+func SavePreset(
+	sessionData *types.Session,
+	name string,
+	username string,
+	config string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SavePreset(?,?,?,?)",
+		name,
+		username,
+		time.Now().UnixNano()/int64(time.Millisecond),
+		config); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetPresetNames retrieve the names of the strategy presets ordered by name
+func GetPresetNames(
+	sessionData *types.Session) (names []string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetPresetNames()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		var name string
+		err = rows.Scan(&name)
+		names = append(names, name)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return names, err
+
+}
+
+// GetPreset retrieve the configuration of a strategy preset, empty when the preset does not exist
+func GetPreset(
+	sessionData *types.Session,
+	name string) (config string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetPreset(?)",
+		name); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return "", err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&config)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return config, err
+
+}
+
+// SavePreference save the UI preferences of username
+func SavePreference(
+	sessionData *types.Session,
+	username string,
+	preference types.Preference) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SavePreference(?,?,?,?,?,?)",
+		username,
+		preference.Theme,
+		preference.RefreshInterval,
+		preference.Currency,
+		strings.Join(preference.Columns, ","),
+		preference.Language); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetPreference retrieve the UI preferences of username, found is false when not saved
+func GetPreference(
+	sessionData *types.Session,
+	username string) (preference types.Preference, found bool, err error) {
+
+	var rows *sql.Rows /* Rows */
+	var columns string
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetPreference(?)",
+		username); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return preference, false, err
+
+	}
+
+	for rows.Next() {
+
+		err = rows.Scan(&preference.Theme, &preference.RefreshInterval, &preference.Currency, &columns, &preference.Language)
+		found = true
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	if columns != "" {
+		preference.Columns = strings.Split(columns, ",")
+	}
+
+	return preference, found, err
+
+}
+
+// SaveBalance save the balance snapshot of an asset in an exchange account
+func SaveBalance(
+	sessionData *types.Session,
+	balance types.Balance) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveBalance(?,?,?,?,?)",
+		balance.Account,
+		balance.Asset,
+		balance.Free,
+		balance.Locked,
+		balance.Time); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteBalanceBefore delete the balances of an exchange account older than the latest snapshot time (assets no longer held)
+func DeleteBalanceBefore(
+	sessionData *types.Session,
+	account string,
+	snapshotTime int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteBalanceBefore(?,?)",
+		account,
+		snapshotTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetBalances retrieve the latest balance snapshot of all exchange accounts
+func GetBalances(
+	sessionData *types.Session) (balances []types.Balance, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetBalances()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		balance := types.Balance{}
+		err = rows.Scan(&balance.Account, &balance.Asset, &balance.Free, &balance.Locked, &balance.Time)
+		balances = append(balances, balance)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return balances, err
+
+}
+
+// SaveAssetPrice save the latest price of an asset in USDT
+func SaveAssetPrice(
+	sessionData *types.Session,
+	asset string,
+	price float64,
+	snapshotTime int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveAssetPrice(?,?,?)",
+		asset,
+		price,
+		snapshotTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetAssetPrices retrieve the latest price in USDT of all assets
+func GetAssetPrices(
+	sessionData *types.Session) (prices map[string]float64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetAssetPrices()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	prices = make(map[string]float64)
+
+	for rows.Next() {
+
+		var asset string
+		var price float64
+		err = rows.Scan(&asset, &price)
+
+		prices[asset] = price
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return prices, err
+
+}
+
+// GetOpenPositions retrieve the open transactions of all threads by ThreadID and Symbol
+func GetOpenPositions(
+	sessionData *types.Session) (positions []types.Position, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetOpenPositions()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		position := types.Position{}
+		err = rows.Scan(&position.ThreadID, &position.Exchange, &position.Symbol, &position.FiatSymbol, &position.Quantity, &position.Cost)
+		positions = append(positions, position)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return positions, err
+
+}
+
+// SaveNote save an operator note attached to an order or a session
+func SaveNote(
+	sessionData *types.Session,
+	note types.Note) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveNote(?,?,?,?,?,?)",
+		note.ThreadID,
+		note.OrderID,
+		note.Username,
+		note.Time,
+		strings.Join(note.Tags, ","),
+		note.Text); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetNotes retrieve the latest operator notes with tag, or all notes when tag is empty, most recent first
+func GetNotes(
+	sessionData *types.Session,
+	tag string,
+	limit int) (notes []types.Note, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetNotes(?,?)",
+		tag,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		var tags string
+
+		note := types.Note{}
+		err = rows.Scan(&note.ID, &note.ThreadID, &note.OrderID, &note.Username, &note.Time, &tags, &note.Text, &note.Symbol, &note.Side, &note.Price, &note.Quantity)
+
+		if tags != "" {
+			note.Tags = strings.Split(tags, ",")
+		}
+
+		notes = append(notes, note)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return notes, err
+
+}
+
+// SaveAlertRule Save alert rule to alertrule table
+func SaveAlertRule(
+	sessionData *types.Session,
+	rule types.AlertRule) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveAlertRule(?,?,?,?,?,?,?)",
+		rule.Name,
+		rule.ThreadID,
+		rule.Metric,
+		rule.Operator,
+		rule.Threshold,
+		rule.Channel,
+		rule.Target); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteAlertRule Delete alert rule from alertrule table
+func DeleteAlertRule(
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteAlertRule(?)",
+		id); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetAlertRules retrieve all alert rules
+func GetAlertRules(
+	sessionData *types.Session) (rules []types.AlertRule, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetAlertRules()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		rule := types.AlertRule{}
+		err = rows.Scan(&rule.ID, &rule.Name, &rule.ThreadID, &rule.Metric, &rule.Operator, &rule.Threshold, &rule.Channel, &rule.Target)
+		rules = append(rules, rule)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return rules, err
+
+}
+
+// GetThreadLastOrderTime retrieve the TransactTime of the last order of a ThreadID, 0 when the thread has no orders
+func GetThreadLastOrderTime(
+	sessionData *types.Session) (transactTime int64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadLastOrderTime(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&transactTime)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return transactTime, err
+
+}
+
+// ExportTrades stream the filled orders between from and to (milliseconds) to fn, oldest first
+func ExportTrades(
+	sessionData *types.Session,
+	from int64,
+	to int64,
+	fn func(types.Trade) error) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.ExportTrades(?,?)",
+		from,
+		to); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	for rows.Next() {
+
+		var trade types.Trade
+
+		if err = rows.Scan(&trade.TransactTime, &trade.ThreadID, &trade.Symbol, &trade.Side, &trade.OrderID, &trade.OrderIDSource, &trade.Price, &trade.Quantity, &trade.Quote, &trade.Source, &trade.Profit); err != nil {
+
+			return err
+
+		}
+
+		if err = fn(trade); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return rows.Err()
+
+}
+
+// ExportThreadProfit stream the realized profit per thread of the sales between from and to (milliseconds) to fn
+func ExportThreadProfit(
+	sessionData *types.Session,
+	from int64,
+	to int64,
+	fn func(types.ProfitSummary) error) error {
+
+	return exportProfit(sessionData, "call cryptopump.ExportThreadProfit(?,?)", true, from, to, fn)
+
+}
+
+// ExportMonthlyProfit stream the realized profit per month of the sales between from and to (milliseconds) to fn
+func ExportMonthlyProfit(
+	sessionData *types.Session,
+	from int64,
+	to int64,
+	fn func(types.ProfitSummary) error) error {
+
+	return exportProfit(sessionData, "call cryptopump.ExportMonthlyProfit(?,?)", false, from, to, fn)
+
+}
+
+/* Stream the profit summaries returned by procedure to fn, withSymbol when the summary includes the symbol column */
+func exportProfit(
+	sessionData *types.Session,
+	procedure string,
+	withSymbol bool,
+	from int64,
+	to int64,
+	fn func(types.ProfitSummary) error) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, procedure,
+		from,
+		to); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	for rows.Next() {
+
+		var summary types.ProfitSummary
+		var profit, profitPct sql.NullFloat64
+
+		if withSymbol {
+			err = rows.Scan(&summary.Key, &summary.Symbol, &summary.Trades, &profit, &profitPct)
+		} else {
+			err = rows.Scan(&summary.Key, &summary.Trades, &profit, &profitPct)
+		}
+
+		if err != nil {
+
+			return err
+
+		}
+
+		summary.Profit = profit.Float64
+		summary.ProfitPct = profitPct.Float64
+
+		if err = fn(summary); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return rows.Err()
+
+}
+
+// GetOrders retrieve a page of the order history matching filter, sorted by filter.Sort
+func GetOrders(
+	sessionData *types.Session,
+	filter types.OrderFilter) (orders []types.Trade, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetOrders(?,?,?,?,?,?,?,?,?)",
+		filter.ThreadID,
+		filter.Symbol,
+		filter.Side,
+		filter.Status,
+		filter.Search,
+		filter.Sort,
+		filter.Descending,
+		filter.Limit,
+		filter.Offset); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	for rows.Next() {
+
+		var order types.Trade
+
+		if err = rows.Scan(&order.TransactTime, &order.ThreadID, &order.Symbol, &order.Side, &order.Status, &order.OrderID, &order.OrderIDSource, &order.Price, &order.Quantity, &order.Quote, &order.Source); err != nil {
+
+			return nil, err
+
+		}
+
+		orders = append(orders, order)
+
+	}
+
+	return orders, rows.Err()
+
+}
+
+// GetOrderCount retrieve the number of orders in the order history matching filter
+func GetOrderCount(
+	sessionData *types.Session,
+	filter types.OrderFilter) (count int, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetOrderCount(?,?,?,?,?)",
+		filter.ThreadID,
+		filter.Symbol,
+		filter.Side,
+		filter.Status,
+		filter.Search); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&count)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return count, err
+
+}
+
+// SavePnl save the realized and unrealized profit of every thread at time and delete snapshots older than expire (milliseconds)
+func SavePnl(
+	sessionData *types.Session,
+	time int64,
+	expire int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SavePnl(?,?)",
+		time,
+		expire); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetPnlSince retrieve the P&L snapshots of all threads since a given time in milliseconds ordered by time
+func GetPnlSince(
+	sessionData *types.Session,
+	since int64) (snapshots []types.PnlSnapshot, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetPnlSince(?)",
+		since); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		snapshot := types.PnlSnapshot{}
+		err = rows.Scan(&snapshot.Time, &snapshot.ThreadID, &snapshot.Realized, &snapshot.Unrealized)
+		snapshots = append(snapshots, snapshot)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return snapshots, err
+
+}
+
+// SaveThreadEvent save an event in the history of a ThreadID, i.e. pause or resume
+func SaveThreadEvent(
+	sessionData *types.Session,
+	kind string,
+	source string,
+	message string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveThreadEvent(?,?,?,?,?)",
+		sessionData.ThreadID,
+		time.Now().UnixNano()/int64(time.Millisecond),
+		kind,
+		source,
+		message); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetThreadTimeline retrieve the orders, configuration changes, events, notes, approvals and liquidations of a ThreadID between from and to (milliseconds) ordered by time
+func GetThreadTimeline(
+	sessionData *types.Session,
+	threadID string,
+	from int64,
+	to int64,
+	limit int) (events []types.ThreadEvent, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadTimeline(?,?,?,?)",
+		threadID,
+		from,
+		to,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		event := types.ThreadEvent{}
+		err = rows.Scan(&event.Time, &event.Kind, &event.Source, &event.OrderID, &event.Detail)
+		events = append(events, event)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return events, err
+
+}
+
+// SaveShadowOrder save a simulated order of a thread in dry-run mode in the shadoworder table
+func SaveShadowOrder(
+	sessionData *types.Session,
+	order types.ShadowOrder) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveShadowOrder(?,?,?,?,?,?,?,?,?,?)",
+		sessionData.ThreadID,
+		order.Symbol,
+		order.Side,
+		order.Price,
+		order.Quantity,
+		order.CumulativeQuoteQuantity,
+		order.OrderIDSource,
+		order.Profit,
+		order.Decision,
+		order.TransactTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetShadowOrders retrieve the latest limit simulated orders of a ThreadID, most recent first
+func GetShadowOrders(
+	sessionData *types.Session,
+	threadID string,
+	limit int) (orders []types.ShadowOrder, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetShadowOrders(?,?)",
+		threadID,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return scanShadowOrders(rows)
+
+}
+
+// GetShadowOpenOrders retrieve the simulated BUY orders of sessionData.ThreadID not sold by a simulated SELL, lowest price first
+func GetShadowOpenOrders(sessionData *types.Session) (orders []types.ShadowOrder, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetShadowOpenOrders(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return scanShadowOrders(rows)
+
+}
+
+/* Scan the rows of the shadoworder table */
+func scanShadowOrders(rows *sql.Rows) (orders []types.ShadowOrder, err error) {
+
+	for rows.Next() {
+
+		order := types.ShadowOrder{}
+		if err = rows.Scan(
+			&order.ID,
+			&order.Symbol,
+			&order.Side,
+			&order.Price,
+			&order.Quantity,
+			&order.CumulativeQuoteQuantity,
+			&order.OrderIDSource,
+			&order.Profit,
+			&order.Decision,
+			&order.TransactTime); err != nil {
+
+			return orders, err
+
+		}
+
+		orders = append(orders, order)
+
+	}
+
+	return orders, rows.Err()
+
+}
+
+// SaveWebhook Save a new webhook, or update the webhook with the same ID keeping its secret when Secret is empty
+func SaveWebhook(
+	sessionData *types.Session,
+	webhook types.Webhook) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveWebhook(?,?,?,?,?,?)",
+		webhook.ID,
+		webhook.Name,
+		webhook.URL,
+		strings.Join(webhook.Events, ","),
+		webhook.Secret,
+		webhook.Enabled); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteWebhook Delete webhook from webhook table
+func DeleteWebhook(
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteWebhook(?)",
+		id); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetWebhooks retrieve all webhooks
+func GetWebhooks(
+	sessionData *types.Session) (webhooks []types.Webhook, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetWebhooks()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		var events string
+
+		webhook := types.Webhook{}
+		err = rows.Scan(&webhook.ID, &webhook.Name, &webhook.URL, &events, &webhook.Secret, &webhook.Enabled)
+
+		if events != "" {
+			webhook.Events = strings.Split(events, ",")
+		}
+
+		webhooks = append(webhooks, webhook)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return webhooks, err
+
+}
+
+// SaveBacktest save a backtest run and return its ID
+func SaveBacktest(
+	sessionData *types.Session,
+	backtest types.Backtest) (id int64, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveBacktest(?,?,?,?,?,?,?,?,?,?,?)",
+		backtest.Name,
+		backtest.Symbol,
+		backtest.StartTime,
+		backtest.EndTime,
+		backtest.CreatedTime,
+		backtest.Parameters,
+		backtest.Trades,
+		backtest.ProfitPct,
+		backtest.BuyHoldPct,
+		backtest.MaxDrawdownPct,
+		backtest.WinRatePct); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return 0, err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&id)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return id, err
+
+}
+
+// SaveBacktestEquity save the simulated equity and buy-and-hold value of a backtest at a time
+func SaveBacktestEquity(
+	sessionData *types.Session,
+	backtestID int64,
+	point types.BacktestEquity) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveBacktestEquity(?,?,?,?)",
+		backtestID,
+		point.Time,
+		point.Equity,
+		point.BuyHold); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetBacktests retrieve all backtest runs, most recent first
+func GetBacktests(
+	sessionData *types.Session) (backtests []types.Backtest, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetBacktests()"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		backtest := types.Backtest{}
+		err = rows.Scan(&backtest.ID, &backtest.Name, &backtest.Symbol, &backtest.StartTime, &backtest.EndTime, &backtest.CreatedTime, &backtest.Parameters, &backtest.Trades, &backtest.ProfitPct, &backtest.BuyHoldPct, &backtest.MaxDrawdownPct, &backtest.WinRatePct)
+		backtests = append(backtests, backtest)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return backtests, err
+
+}
+
+// GetBacktestEquity retrieve the simulated equity and buy-and-hold values of a backtest ordered by time
+func GetBacktestEquity(
+	sessionData *types.Session,
+	backtestID int64) (equity []types.BacktestEquity, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetBacktestEquity(?)",
+		backtestID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		point := types.BacktestEquity{}
+		err = rows.Scan(&point.Time, &point.Equity, &point.BuyHold)
+		equity = append(equity, point)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return equity, err
+
+}
+
+// DeleteBacktest Delete a backtest run and its equity from backtest and backtestequity tables
+func DeleteBacktest(
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteBacktest(?)",
+		id); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// SaveThreadCommand Queue a command for a ThreadID, executed by the thread on its next commands check
+func SaveThreadCommand(
+	sessionData *types.Session,
+	command types.ThreadCommand) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveThreadCommand(?,?,?,?,?)",
+		command.ThreadID,
+		command.Command,
+		command.OrderID,
+		command.Source,
+		command.CreatedTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetThreadCommands retrieve the commands queued for a ThreadID ordered by ID
+func GetThreadCommands(
+	sessionData *types.Session) (commands []types.ThreadCommand, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadCommands(?)",
+		sessionData.ThreadID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		command := types.ThreadCommand{}
+		err = rows.Scan(&command.ID, &command.ThreadID, &command.Command, &command.OrderID, &command.Source, &command.CreatedTime)
+		commands = append(commands, command)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return commands, err
+
+}
+
+// DeleteThreadCommand Delete a queued thread command
+func DeleteThreadCommand(
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteThreadCommand(?)",
+		id); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetThreadIDByOrderID retrieve the ThreadID holding orderID as an open thread transaction, empty if none
+func GetThreadIDByOrderID(
+	sessionData *types.Session,
+	orderID int64) (threadID string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetThreadIDByOrderID(?)",
+		orderID); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return "", err
+
+	}
+
+	for rows.Next() {
+		err = rows.Scan(&threadID)
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return threadID, err
+
+}
+
+// SaveQueuedNotification Save a notification awaiting delivery retry to notificationqueue table
+func SaveQueuedNotification(
+	sessionData *types.Session,
+	notification types.QueuedNotification) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveQueuedNotification(?,?,?,?,?,?,?)",
+		notification.ThreadID,
+		notification.Channel,
+		notification.Text,
+		notification.Attempts,
+		notification.NextAttempt,
+		notification.LastError,
+		notification.CreatedTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetQueuedNotifications retrieve up to limit queued notifications of all threads due for retry at time (milliseconds)
+func GetQueuedNotifications(
+	sessionData *types.Session,
+	time int64,
+	limit int) (notifications []types.QueuedNotification, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetQueuedNotifications(?,?)",
+		time,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		notification := types.QueuedNotification{}
+		err = rows.Scan(&notification.ID, &notification.ThreadID, &notification.Channel, &notification.Text, &notification.Attempts, &notification.NextAttempt, &notification.LastError, &notification.CreatedTime)
+		notifications = append(notifications, notification)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return notifications, err
+
+}
+
+// UpdateQueuedNotification Update the attempts, next attempt time and last error of a queued notification
+func UpdateQueuedNotification(
+	sessionData *types.Session,
+	notification types.QueuedNotification) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.UpdateQueuedNotification(?,?,?,?)",
+		notification.ID,
+		notification.Attempts,
+		notification.NextAttempt,
+		notification.LastError); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// DeleteQueuedNotification Delete a delivered or dead-lettered notification from notificationqueue table
+func DeleteQueuedNotification(
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteQueuedNotification(?)",
+		id); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// SaveLog Save a log entry to log table. The error is returned and not logged, so saving log entries doesn't log.
+func SaveLog(
+	sessionData *types.Session,
+	record types.LogRecord) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveLog(?,?,?,?,?)",
+		record.Time,
+		record.Level,
+		record.ThreadID,
+		record.Component,
+		record.Message); err != nil {
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetLogs retrieve the latest log entries matching filter from log table, most recent first
+func GetLogs(
+	sessionData *types.Session,
+	filter types.LogFilter,
+	limit int) (records []types.LogRecord, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetLogs(?,?,?,?,?,?,?)",
+		filter.ThreadID,
+		filter.Level,
+		filter.Component,
+		filter.Text,
+		filter.From,
+		filter.To,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		record := types.LogRecord{}
+		err = rows.Scan(&record.ID, &record.Time, &record.Level, &record.ThreadID, &record.Component, &record.Message)
+		records = append(records, record)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return records, err
+
+}
+
+// DeleteLogBefore Delete the log entries older than logTime from log table
+func DeleteLogBefore(
+	sessionData *types.Session,
+	logTime int64) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.DeleteLogBefore(?)",
+		logTime); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// SaveAudit Append an order event to the audit table, chained to the hash of the previous record by the procedure
+func SaveAudit(
+	sessionData *types.Session,
+	record types.AuditRecord) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveAudit(?,?,?,?,?)",
+		record.Time,
+		record.ThreadID,
+		record.Event,
+		record.OrderID,
+		record.Payload); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{OrderID: record.OrderID},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetAudit retrieve up to limit audit table records with ID greater than id, in ID order
+func GetAudit(
+	sessionData *types.Session,
+	id int64,
+	limit int) (records []types.AuditRecord, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetAudit(?,?)",
+		id,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		record := types.AuditRecord{}
+		err = rows.Scan(&record.ID, &record.Time, &record.ThreadID, &record.Event, &record.OrderID, &record.Payload, &record.PrevHash, &record.Hash)
+		records = append(records, record)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return records, err
+
+}
+
+/* Append an order event to the audit table. A failure is logged by SaveAudit and doesn't fail the order. */
+func auditOrder(
+	sessionData *types.Session,
+	event string,
+	order types.Order) {
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+
+	payload, err := json.Marshal(types.AuditEvent{
+		Event:    event,
+		Time:     now,
+		ThreadID: sessionData.ThreadID,
+		Order:    order,
+	})
+	if err != nil {
+
+		return
+
+	}
+
+	_ = SaveAudit(sessionData, types.AuditRecord{
+		Time:     now,
+		ThreadID: sessionData.ThreadID,
+		Event:    event,
+		OrderID:  order.OrderID,
+		Payload:  string(payload),
+	})
+
+}
+
+// SaveWsConnection save a websocket connection of the thread once disconnected
+func SaveWsConnection(
+	sessionData *types.Session,
+	connection types.WsConnection) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.SaveWsConnection(?,?,?,?,?,?,?)",
+		connection.ThreadID,
+		connection.Stream,
+		connection.ConnectedTime,
+		connection.DisconnectedTime,
+		connection.Messages,
+		connection.Reason,
+		connection.Error); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
+
+// GetWsConnections retrieve the latest websocket connections of a ThreadID, most recently disconnected first
+func GetWsConnections(
+	sessionData *types.Session,
+	limit int) (connections []types.WsConnection, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "call cryptopump.GetWsConnections(?,?)",
+		sessionData.ThreadID,
+		limit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	for rows.Next() {
+
+		connection := types.WsConnection{}
+		err = rows.Scan(&connection.ThreadID, &connection.Stream, &connection.ConnectedTime, &connection.DisconnectedTime, &connection.Messages, &connection.Reason, &connection.Error)
+		connections = append(connections, connection)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return connections, err
+
+}
+
+// GetSchemaColumns retrieve the column names of each table of the database, in column order
+func GetSchemaColumns(
+	sessionData *types.Session) (columns map[string][]string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "SELECT TABLE_NAME, COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() ORDER BY TABLE_NAME, ORDINAL_POSITION"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	columns = make(map[string][]string)
+
+	for rows.Next() {
+
+		var table, column string
+		err = rows.Scan(&table, &column)
+		columns[table] = append(columns[table], column)
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return columns, err
+
+}
+
+// GetSchemaProcedures retrieve the body (BEGIN ... END) of each stored procedure of the database by name, empty
+// without privileges on the procedure
+func GetSchemaProcedures(
+	sessionData *types.Session) (procedures map[string]string, err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, "SELECT ROUTINE_NAME, ROUTINE_DEFINITION FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = DATABASE() AND ROUTINE_TYPE = 'PROCEDURE'"); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return nil, err
+
+	}
+
+	procedures = make(map[string]string)
+
+	for rows.Next() {
+
+		var name string
+		var definition sql.NullString /* NULL without privileges on the procedure */
+		err = rows.Scan(&name, &definition)
+		procedures[name] = definition.String
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return procedures, err
+
+}
+
+// ExecSchema execute a schema change statement, i.e. CREATE TABLE or CREATE PROCEDURE
+func ExecSchema(
+	sessionData *types.Session,
+	statement string) (err error) {
+
+	var rows *sql.Rows /* Rows */
+
+	if flag.Lookup("test.v") != nil { /* If the -test.v flag is set, the test database is used */
+		sessionData.Db.Begin() /* Start transaction */
+	}
+
+	if rows, err = query(sessionData, statement); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return err
+
+	}
+
+	defer rows.Close() /* Close rows */
+
+	return nil
+
+}
@@ -2,14 +2,19 @@ package mysql
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"log"
+	"net"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aleibovici/cryptopump/types"
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 )
 
 // NewMock returns a new mock database and sqlmock.Sqlmock
@@ -114,6 +119,57 @@ func TestGetThreadAmount(t *testing.T) {
 	}
 }
 
+func TestGetThreadAmountByThreadID(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		wantAmount float64
+		wantErr    bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			wantAmount: 0,
+			wantErr:    false,
+		},
+	}
+
+	columns := []string{"amountNullFloat64"}
+	mock.ExpectBegin()                                                                             /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadTransactionAmountByThreadID(?)")). /* call procedure */
+													WithArgs(tests[0].args.sessionData.ThreadID). /* with args */
+													WillReturnRows(sqlmock.NewRows(columns))      /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAmount, err := GetThreadAmountByThreadID(tt.args.sessionData)
+			if (err == nil) && gotAmount > 0 {
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadAmountByThreadID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotAmount != tt.wantAmount {
+				t.Errorf("GetThreadAmountByThreadID() = %v, want %v", gotAmount, tt.wantAmount)
+			}
+		})
+	}
+}
+
 func TestGetSessionStatus(t *testing.T) {
 
 	db, mock := NewMock()
@@ -698,6 +754,48 @@ func TestGetThreadTransactionDistinct(t *testing.T) {
 	}
 }
 
+func TestGetThreadTransactionDistinctBySymbol(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db:     db,
+					Symbol: "BTCUSDT",
+				},
+			},
+		},
+	}
+
+	columns := []string{"threadID", "threadIDSession"}
+	mock.ExpectBegin()                                                                             /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadTransactionDistinctBySymbol(?)")). /* call procedure */
+													WithArgs("BTCUSDT").
+													WillReturnRows(sqlmock.NewRows(columns)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := GetThreadTransactionDistinctBySymbol(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadTransactionDistinctBySymbol() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
 func TestGetOrderSymbol(t *testing.T) {
 
 	db, mock := NewMock()
@@ -741,6 +839,55 @@ func TestGetOrderSymbol(t *testing.T) {
 	}
 }
 
+func TestGetOrderCorrelationID(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		orderID     int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				orderID: 42,
+			},
+			want:    "c683oqdmk1u1120gnmn0",
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetOrderCorrelationID(?)")).
+		WithArgs(tests[0].args.orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"CorrelationID"}).AddRow(tests[0].want))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOrderCorrelationID(tt.args.sessionData, tt.args.orderID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetOrderCorrelationID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetOrderCorrelationID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetOrderTransactionSideLastTwo(t *testing.T) {
 
 	db, mock := NewMock()
@@ -925,7 +1072,7 @@ func TestDeleteThreadTransactionByOrderID(t *testing.T) {
 
 	type args struct {
 		sessionData *types.Session
-		orderID     int
+		orderID     int64
 	}
 
 	tests := []struct {
@@ -982,8 +1129,9 @@ func TestSaveOrder(t *testing.T) {
 			name: "success",
 			args: args{
 				sessionData: &types.Session{
-					ThreadID: "c683ok5mk1u1120gnmmg",
-					Db:       db,
+					ThreadID:      "c683ok5mk1u1120gnmmg",
+					CorrelationID: "c683oqdmk1u1120gnmn0",
+					Db:            db,
 				},
 				order: &types.Order{
 					ClientOrderID:           "0",
@@ -1006,8 +1154,8 @@ func TestSaveOrder(t *testing.T) {
 		},
 	}
 
-	mock.ExpectBegin()                                                                        /* begin transaction */
-	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveOrder(?,?,?,?,?,?,?,?,?,?,?,?)")). /* call procedure */
+	mock.ExpectBegin()                                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveOrder(?,?,?,?,?,?,?,?,?,?,?,?,?)")). /* call procedure */
 													WithArgs( /* with args */
 			tests[0].args.order.ClientOrderID,
 			tests[0].args.order.CumulativeQuoteQuantity,
@@ -1020,7 +1168,13 @@ func TestSaveOrder(t *testing.T) {
 			tests[0].args.order.Symbol,
 			tests[0].args.order.TransactTime,
 			tests[0].args.sessionData.ThreadID,
-			tests[0].args.sessionData.ThreadIDSession).
+			tests[0].args.sessionData.ThreadIDSession,
+			tests[0].args.sessionData.CorrelationID).
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+	/* order event appended to the audit table */
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveAudit(?,?,?,?,?)")).
+		WithArgs(sqlmock.AnyArg(), tests[0].args.sessionData.ThreadID, AuditOrderCreate, tests[0].args.order.OrderID, sqlmock.AnyArg()).
 		WillReturnRows(sqlmock.NewRows([]string{""}))
 	mock.ExpectCommit()
 
@@ -1078,6 +1232,11 @@ func TestUpdateOrder(t *testing.T) {
 								tests[0].args.Price,
 								tests[0].args.Status).
 		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	/* order event appended to the audit table */
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveAudit(?,?,?,?,?)")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), AuditOrderUpdate, tests[0].args.OrderID, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{""}))
 	mock.ExpectCommit()
 
 	for _, tt := range tests {
@@ -1089,6 +1248,147 @@ func TestUpdateOrder(t *testing.T) {
 	}
 }
 
+func TestUpdateOrderType(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		OrderID     int64
+		Type        string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				OrderID: 0,
+				Type:    "REBALANCE",
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateOrderType(?,?)")). /* call procedure */
+											WithArgs( /* with args */
+								tests[0].args.OrderID,
+								tests[0].args.Type).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateOrderType(tt.args.sessionData, tt.args.OrderID, tt.args.Type); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateOrderType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderSource(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		OrderID     int64
+		Source      string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				OrderID: 0,
+				Source:  "operator",
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                            /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateOrderSource(?,?)")). /* call procedure */
+											WithArgs( /* with args */
+								tests[0].args.OrderID,
+								tests[0].args.Source).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateOrderSource(tt.args.sessionData, tt.args.OrderID, tt.args.Source); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateOrderSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderScore(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		OrderID     int64
+		Score       float64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				OrderID: 0,
+				Score:   3.5,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                           /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateOrderScore(?,?)")). /* call procedure */
+											WithArgs( /* with args */
+								tests[0].args.OrderID,
+								tests[0].args.Score).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateOrderScore(tt.args.sessionData, tt.args.OrderID, tt.args.Score); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateOrderScore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestUpdateSession(t *testing.T) {
 
 	db, mock := NewMock()
@@ -1396,3 +1696,4062 @@ func TestSaveThreadTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestGetThreadCycleProfitLast(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		limit       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				limit: 3,
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Profit", "TransactTime"}
+	mock.ExpectBegin()                                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadCycleProfitLast(?,?)")). /* call procedure */
+												WithArgs(tests[0].args.sessionData.ThreadID, tests[0].args.limit).   /* with args */
+												WillReturnRows(sqlmock.NewRows(columns).AddRow(-1.5, 1637000000000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := GetThreadCycleProfitLast(tt.args.sessionData, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadCycleProfitLast() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestGetSessionCooldown(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"CooldownStart", "CooldownUntil"}
+	mock.ExpectBegin()                                                           /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionCooldown(?)")). /* call procedure */
+											WithArgs(tests[0].args.sessionData.ThreadID). /* with args */
+											WillReturnRows(sqlmock.NewRows(columns))      /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := GetSessionCooldown(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionCooldown() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestUpdateSessionCooldown(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db:            db,
+					ThreadID:      "c683ok5mk1u1120gnmmg",
+					CooldownStart: time.Unix(1637000000, 0),
+					CooldownUntil: time.Unix(1637003600, 0),
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                  /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionCooldown(?,?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.CooldownStart.Unix(),
+								tests[0].args.sessionData.CooldownUntil.Unix()).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionCooldown(tt.args.sessionData); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionCooldown() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetThreadSymbolExposure(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Symbol", "sum"}
+	mock.ExpectBegin()                                                               /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadSymbolExposure()")). /* call procedure */
+												WillReturnRows(sqlmock.NewRows(columns).AddRow("BTCUSDT", 150.5)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetThreadSymbolExposure(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadSymbolExposure() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestGetThreadSymbolQuantity(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db:     db,
+					Symbol: "BTCUSDT",
+				},
+			},
+			want:    0.0035,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"sum"}
+	mock.ExpectBegin()                                                                /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadSymbolQuantity(?)")). /* call procedure */
+												WithArgs("BTCUSDT").
+												WillReturnRows(sqlmock.NewRows(columns).AddRow(0.0035)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetThreadSymbolQuantity(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadSymbolQuantity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetThreadSymbolQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGlobalEquity(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    1250.5,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Equity"}
+	mock.ExpectBegin()                                                       /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetGlobalEquity()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(1250.5)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetGlobalEquity(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetGlobalEquity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetGlobalEquity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetGlobalDrawdown(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"EquityPeak", "DrawdownHalt"}
+	mock.ExpectBegin()                                                         /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetGlobalDrawdown()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows(columns)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := GetGlobalDrawdown(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetGlobalDrawdown() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestUpdateGlobalDrawdown(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData  *types.Session
+		equityPeak   float64
+		drawdownHalt bool
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				equityPeak:   1000,
+				drawdownHalt: true,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                               /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateGlobalDrawdown(?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.equityPeak,
+								tests[0].args.drawdownHalt).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateGlobalDrawdown(tt.args.sessionData, tt.args.equityPeak, tt.args.drawdownHalt); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateGlobalDrawdown() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetProfitSince(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		since       time.Time
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				since: time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+			},
+			want:    -12.5,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Profit"}
+	mock.ExpectBegin()                                                       /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetProfitSince(?)")). /* call procedure */
+											WithArgs(int64(1638316800000)).                        /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(-12.5)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetProfitSince(tt.args.sessionData, tt.args.since)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetProfitSince() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetProfitSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSessionStopPrice(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    52000,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"StopPrice"}
+	mock.ExpectBegin()                                                            /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionStopPrice(?)")). /* call procedure */
+											WithArgs(tests[0].args.sessionData.ThreadID).          /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(52000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionStopPrice(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionStopPrice() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSessionStopPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionStopPrice(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID:  "c683ok5mk1u1120gnmmg",
+					Db:        db,
+					StopPrice: 52000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                 /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionStopPrice(?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.StopPrice).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionStopPrice(tt.args.sessionData); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionStopPrice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSessionPaused(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    true,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Paused"}
+	mock.ExpectBegin()                                                         /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionPaused(?)")). /* call procedure */
+											WithArgs(tests[0].args.sessionData.ThreadID).         /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(true)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionPaused(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionPaused() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSessionPaused() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSessionCycle(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name              string
+		args              args
+		wantState         string
+		wantOrderID       int64
+		wantOrderIDSource int64
+		wantErr           bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			wantState:         "SELL_PLACED",
+			wantOrderID:       2217134963,
+			wantOrderIDSource: 2217098215,
+			wantErr:           false,
+		},
+	}
+
+	columns := []string{"CycleState", "CycleOrderID", "CycleOrderIDSource"}
+	mock.ExpectBegin()                                                        /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionCycle(?)")). /* call procedure */
+											WithArgs(tests[0].args.sessionData.ThreadID).                                          /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow("SELL_PLACED", 2217134963, 2217098215)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotState, gotOrderID, gotOrderIDSource, err := GetSessionCycle(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionCycle() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotState != tt.wantState || gotOrderID != tt.wantOrderID || gotOrderIDSource != tt.wantOrderIDSource {
+				t.Errorf("GetSessionCycle() = %v, %v, %v, want %v, %v, %v", gotState, gotOrderID, gotOrderIDSource, tt.wantState, tt.wantOrderID, tt.wantOrderIDSource)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionCycle(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID:           "c683ok5mk1u1120gnmmg",
+					Db:                 db,
+					CycleState:         "SELL_PLACED",
+					CycleOrderID:       2217134963,
+					CycleOrderIDSource: 2217098215,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                 /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionCycle(?,?,?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.CycleState,
+								tests[0].args.sessionData.CycleOrderID,
+								tests[0].args.sessionData.CycleOrderIDSource).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionCycle(tt.args.sessionData); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionCycle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionPaused(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+					Paused:   true,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionPaused(?,?)")). /* call procedure */
+											WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.Paused).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionPaused(tt.args.sessionData); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionPaused() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionState(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		state       string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				state: SessionStopped,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                             /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionState(?,?)")). /* call procedure */
+											WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.state).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionState(tt.args.sessionData, tt.args.state); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSessionTrailingHigh(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    53500,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"TrailingHigh"}
+	mock.ExpectBegin()                                                               /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionTrailingHigh(?)")). /* call procedure */
+												WithArgs(tests[0].args.sessionData.ThreadID).          /* with args */
+												WillReturnRows(sqlmock.NewRows(columns).AddRow(53500)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionTrailingHigh(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionTrailingHigh() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSessionTrailingHigh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionTrailingHigh(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID:     "c683ok5mk1u1120gnmmg",
+					Db:           db,
+					TrailingHigh: 53500,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                    /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionTrailingHigh(?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.TrailingHigh).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionTrailingHigh(tt.args.sessionData); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionTrailingHigh() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetThreadAverageEntry(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    51250.5,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"AverageEntry"}
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadAverageEntry(?)")). /* call procedure */
+											WithArgs(tests[0].args.sessionData.ThreadID).            /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(51250.5)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetThreadAverageEntry(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadAverageEntry() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetThreadAverageEntry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSessionReservation(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    1500,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Reservation"}
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionReservation(?)")). /* call procedure */
+											WithArgs(tests[0].args.sessionData.ThreadID).         /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(1500)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionReservation(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionReservation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSessionReservation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionReservation(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID:    "c683ok5mk1u1120gnmmg",
+					Db:          db,
+					Reservation: 1500,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionReservation(?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.Reservation).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionReservation(tt.args.sessionData); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionReservation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionReservationAll(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		reservation float64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				reservation: 750,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                    /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionReservationAll(?)")). /* call procedure */
+												WithArgs(tests[0].args.reservation).          /* with args */
+												WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionReservationAll(tt.args.sessionData, tt.args.reservation); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionReservationAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateSessionReservationByThreadID(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		threadID    string
+		reservation float64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				threadID:    "c683ok5mk1u1120gnmng",
+				reservation: 400,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateSessionReservation(?,?)")). /* call procedure */
+												WithArgs(tests[0].args.threadID, tests[0].args.reservation). /* with args */
+												WillReturnRows(sqlmock.NewRows([]string{""}))                /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateSessionReservationByThreadID(tt.args.sessionData, tt.args.threadID, tt.args.reservation); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateSessionReservationByThreadID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSessionReservedFunds(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    820.45,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"sum"}
+	mock.ExpectBegin()                                                                /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionReservedFunds(?)")). /* call procedure */
+												WithArgs(tests[0].args.sessionData.ThreadID).             /* with args */
+												WillReturnRows(sqlmock.NewRows(columns).AddRow(820.4512)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionReservedFunds(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionReservedFunds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSessionReservedFunds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSessionCount(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    3,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"count"}
+	mock.ExpectBegin()                                                       /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionCount()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(3)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionCount(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionCount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetSessionCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.SessionSummary
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want: []types.SessionSummary{
+				{ThreadID: "c683ok5mk1u1120gnmmg", ThreadIDSession: "c683ok5mk1u1120gnmn0", Exchange: "BINANCE", FiatSymbol: "USDT", FiatFunds: 1000, DiffTotal: 12.5, Status: false, Name: "BTC grid", Tags: []string{"grid", "majors"}},
+				{ThreadID: "c683ok5mk1u1120gnmng", ThreadIDSession: "c683ok5mk1u1120gnmo0", Exchange: "BINANCE", FiatSymbol: "USDT", FiatFunds: 500, DiffTotal: -3.2, Status: true, Name: "", Tags: nil},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ThreadID", "ThreadIDSession", "Exchange", "FiatSymbol", "FiatFunds", "DiffTotal", "Status", "Name", "Tags"}
+	mock.ExpectBegin()                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessions()")). /* call procedure */
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow("c683ok5mk1u1120gnmmg", "c683ok5mk1u1120gnmn0", "BINANCE", "USDT", 1000, 12.5, false, "BTC grid", "grid,majors").
+											AddRow("c683ok5mk1u1120gnmng", "c683ok5mk1u1120gnmo0", "BINANCE", "USDT", 500, -3.2, true, "", "")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessions(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSessions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSessionAllocations(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData  *types.Session
+		transactTime int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.SessionAllocation
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				transactTime: 1641387000000,
+			},
+			want: []types.SessionAllocation{
+				{ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Reservation: 600, Amount: 250, Profit: 12.5, Cost: 500},
+				{ThreadID: "c683ok5mk1u1120gnmng", Symbol: "", Reservation: 0, Amount: 0, Profit: 0, Cost: 0},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ThreadID", "Symbol", "Reservation", "Amount", "Profit", "Cost"}
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionAllocations(?)")). /* call procedure */
+											WithArgs(tests[0].args.transactTime). /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow("c683ok5mk1u1120gnmmg", "BTCUSDT", 600, 250, 12.5, 500).
+												AddRow("c683ok5mk1u1120gnmng", "", 0, 0, 0, 0)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionAllocations(tt.args.sessionData, tt.args.transactTime)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionAllocations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSessionAllocations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveSessionLabel(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		label       types.SessionLabel
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				label: types.SessionLabel{ThreadID: "c683ok5mk1u1120gnmmg", Name: "BTC grid", Tags: []string{"grid", "majors"}},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                               /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveSessionLabel(?,?,?,?)")). /* call procedure */
+												WithArgs("c683ok5mk1u1120gnmmg", "BTC grid", "grid,majors", sqlmock.AnyArg()). /* with args */
+												WillReturnRows(sqlmock.NewRows([]string{""}))                                  /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveSessionLabel(tt.args.sessionData, tt.args.label); (err != nil) != tt.wantErr {
+				t.Errorf("SaveSessionLabel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSessionLabels(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.SessionLabel
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want: []types.SessionLabel{
+				{ThreadID: "c683ok5mk1u1120gnmmg", Name: "BTC grid", Tags: []string{"grid", "majors"}},
+				{ThreadID: "c683ok5mk1u1120gnmng", Name: "", Tags: []string{"test"}},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                        /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionLabels()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows([]string{"ThreadID", "Name", "Tags"}).
+												AddRow("c683ok5mk1u1120gnmmg", "BTC grid", "grid,majors").
+												AddRow("c683ok5mk1u1120gnmng", "", "test")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionLabels(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionLabels() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSessionLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSessionHeartbeats(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]int64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    map[string]int64{"c683ok5mk1u1120gnmmg": 1638230400000, "c683ok5mk1u1120gnmng": 0},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ThreadID", "Heartbeat"}
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSessionHeartbeats()")).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("c683ok5mk1u1120gnmmg", 1638230400000).
+			AddRow("c683ok5mk1u1120gnmng", 0)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSessionHeartbeats(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionHeartbeats() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSessionHeartbeats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+func TestSaveVolatilityTrip(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData  *types.Session
+		reason       string
+		sigma        float64
+		threshold    float64
+		transactTime int64
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db:       db,
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Symbol:   "BTCUSDT",
+				},
+				reason:       "Return",
+				sigma:        5.2,
+				threshold:    4,
+				transactTime: 1638316800000,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                     /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveVolatilityTrip(?,?,?,?,?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.Symbol,
+								tests[0].args.reason,
+								tests[0].args.sigma,
+								tests[0].args.threshold,
+								tests[0].args.transactTime).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveVolatilityTrip(tt.args.sessionData, tt.args.reason, tt.args.sigma, tt.args.threshold, tt.args.transactTime); (err != nil) != tt.wantErr {
+				t.Errorf("SaveVolatilityTrip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetGlobalLiquidate(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name              string
+		args              args
+		wantLiquidate     bool
+		wantLiquidateTime int64
+		wantErr           bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			wantLiquidate:     true,
+			wantLiquidateTime: 1638316800000,
+			wantErr:           false,
+		},
+	}
+
+	columns := []string{"Liquidate", "LiquidateTime"}
+	mock.ExpectBegin()                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetGlobalLiquidate()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(true, 1638316800000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLiquidate, gotLiquidateTime, err := GetGlobalLiquidate(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetGlobalLiquidate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotLiquidate != tt.wantLiquidate || gotLiquidateTime != tt.wantLiquidateTime {
+				t.Errorf("GetGlobalLiquidate() = %v, %v, want %v, %v", gotLiquidate, gotLiquidateTime, tt.wantLiquidate, tt.wantLiquidateTime)
+			}
+		})
+	}
+}
+
+func TestUpdateGlobalLiquidate(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData   *types.Session
+		liquidate     bool
+		liquidateTime int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				liquidate:     true,
+				liquidateTime: 1638316800000,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateGlobalLiquidate(?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.liquidate,
+								tests[0].args.liquidateTime).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateGlobalLiquidate(tt.args.sessionData, tt.args.liquidate, tt.args.liquidateTime); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateGlobalLiquidate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveLiquidationReport(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		startTime   int64
+		endTime     int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Symbol:   "BTCUSDT",
+					Db:       db,
+				},
+				startTime: 1638316800000,
+				endTime:   1638320400000,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                    /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveLiquidationReport(?,?,?,?)")). /* call procedure */
+												WithArgs( /* with args */
+								tests[0].args.sessionData.ThreadID,
+								tests[0].args.sessionData.Symbol,
+								tests[0].args.startTime,
+								tests[0].args.endTime).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveLiquidationReport(tt.args.sessionData, tt.args.startTime, tt.args.endTime); (err != nil) != tt.wantErr {
+				t.Errorf("SaveLiquidationReport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSymbolList(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Symbol", "List"}
+	mock.ExpectBegin()                                                     /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetSymbolList()")). /* call procedure */
+										WillReturnRows(sqlmock.NewRows(columns).AddRow("BTCUSDT", "ALLOW").AddRow("LUNAUSDT", "DENY")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetSymbolList(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSymbolList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestSaveSymbolList(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		symbol      string
+		list        string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				symbol: "LUNAUSDT",
+				list:   "DENY",
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                         /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveSymbolList(?,?)")). /* call procedure */
+											WithArgs(tests[0].args.symbol, tests[0].args.list). /* with args */
+											WillReturnRows(sqlmock.NewRows([]string{""}))       /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveSymbolList(tt.args.sessionData, tt.args.symbol, tt.args.list); (err != nil) != tt.wantErr {
+				t.Errorf("SaveSymbolList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteSymbolList(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                        /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.DeleteSymbolList()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DeleteSymbolList(tt.args.sessionData); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteSymbolList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSavePendingAction(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		action      types.PendingAction
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				action: types.PendingAction{
+					ThreadID:    "c683ok5mk1u1120gnmmg",
+					Action:      "SELL",
+					OrderID:     1234567,
+					Notional:    1500,
+					CreatedTime: 1638230400000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                  /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SavePendingAction(?,?,?,?,?)")). /* call procedure */
+												WithArgs(
+								tests[0].args.action.ThreadID,
+								tests[0].args.action.Action,
+								tests[0].args.action.OrderID,
+								tests[0].args.action.Notional,
+								tests[0].args.action.CreatedTime). /* with args */
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SavePendingAction(tt.args.sessionData, tt.args.action); (err != nil) != tt.wantErr {
+				t.Errorf("SavePendingAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetPendingAction(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "Action", "OrderID", "Notional", "CreatedTime"}
+	mock.ExpectBegin()                                                         /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPendingAction(?)")). /* call procedure */
+											WithArgs(tests[0].args.sessionData.ThreadID).                                            /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "SELL", 1234567, 1500, 1638230400000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetPendingAction(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPendingAction() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+		})
+	}
+}
+
+func TestGetPendingActions(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    2,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "ThreadID", "Action", "OrderID", "Notional", "CreatedTime"}
+	mock.ExpectBegin()                                                         /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPendingActions()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(1, "c683ok5mk1u1120gnmmg", "SELL", 1234567, 1500, 1638230400000).
+												AddRow(2, "c683ok5mk1u1120gnmmh", "SELL", 0, 2500, 1638230460000)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPendingActions(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPendingActions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.want {
+				t.Errorf("GetPendingActions() = %v, want %v actions", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdatePendingAction(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		id          int64
+		status      string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				id:     1,
+				status: "APPROVED",
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdatePendingAction(?,?)")). /* call procedure */
+											WithArgs(tests[0].args.id, tests[0].args.status). /* with args */
+											WillReturnRows(sqlmock.NewRows([]string{""}))     /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdatePendingAction(tt.args.sessionData, tt.args.id, tt.args.status); (err != nil) != tt.wantErr {
+				t.Errorf("UpdatePendingAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		username    string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    types.User
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				username: "admin",
+			},
+			want: types.User{
+				Username:     "admin",
+				PasswordHash: "pbkdf2-sha256$100000$c2FsdA$aGFzaA",
+				Role:         "trader",
+				FailedLogins: 2,
+				LockedUntil:  0,
+				TOTPSecret:   "JBSWY3DPEHPK3PXP",
+				TOTPEnabled:  true,
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Username", "PasswordHash", "Role", "FailedLogins", "LockedUntil", "TOTPSecret", "TOTPEnabled"}
+	mock.ExpectBegin()                                                /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetUser(?)")). /* call procedure */
+										WithArgs(tests[0].args.username).                                                                                                        /* with args */
+										WillReturnRows(sqlmock.NewRows(columns).AddRow("admin", "pbkdf2-sha256$100000$c2FsdA$aGFzaA", "trader", 2, 0, "JBSWY3DPEHPK3PXP", true)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetUser(tt.args.sessionData, tt.args.username)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetUser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveUser(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		user        types.User
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				user: types.User{
+					Username:     "admin",
+					PasswordHash: "pbkdf2-sha256$100000$c2FsdA$aGFzaA",
+					Role:         "admin",
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                     /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveUser(?,?,?)")). /* call procedure */
+										WithArgs(tests[0].args.user.Username, tests[0].args.user.PasswordHash, tests[0].args.user.Role). /* with args */
+										WillReturnRows(sqlmock.NewRows([]string{""}))                                                    /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveUser(tt.args.sessionData, tt.args.user); (err != nil) != tt.wantErr {
+				t.Errorf("SaveUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateUserLogin(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData  *types.Session
+		username     string
+		failedLogins int
+		lockedUntil  int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				username:     "admin",
+				failedLogins: 5,
+				lockedUntil:  1638360000000,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                            /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateUserLogin(?,?,?)")). /* call procedure */
+											WithArgs(tests[0].args.username, tests[0].args.failedLogins, tests[0].args.lockedUntil). /* with args */
+											WillReturnRows(sqlmock.NewRows([]string{""}))                                            /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateUserLogin(tt.args.sessionData, tt.args.username, tt.args.failedLogins, tt.args.lockedUntil); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateUserLogin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveAuthToken(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		tokenHash   string
+		token       types.AuthToken
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				tokenHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+				token: types.AuthToken{
+					Username: "admin",
+					Kind:     "SESSION",
+					Expires:  1638360000000,
+					LastSeen: 1638273600000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveAuthToken(?,?,?,?,?)")). /* call procedure */
+											WithArgs(tests[0].args.tokenHash, tests[0].args.token.Username, tests[0].args.token.Kind, tests[0].args.token.Expires, tests[0].args.token.LastSeen). /* with args */
+											WillReturnRows(sqlmock.NewRows([]string{""}))                                                                                                         /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveAuthToken(tt.args.sessionData, tt.args.tokenHash, tt.args.token); (err != nil) != tt.wantErr {
+				t.Errorf("SaveAuthToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetAuthToken(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		tokenHash   string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    types.AuthToken
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				tokenHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			},
+			want: types.AuthToken{
+				Username: "admin",
+				Role:     "admin",
+				Kind:     "API",
+				Expires:  0,
+				LastSeen: 1638273600000,
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Username", "Role", "Kind", "Expires", "LastSeen"}
+	mock.ExpectBegin()                                                     /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetAuthToken(?)")). /* call procedure */
+										WithArgs(tests[0].args.tokenHash).                                                         /* with args */
+										WillReturnRows(sqlmock.NewRows(columns).AddRow("admin", "admin", "API", 0, 1638273600000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAuthToken(tt.args.sessionData, tt.args.tokenHash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetAuthToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetAuthToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetThreadOrdersSince(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData  *types.Session
+		transactTime int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.Order
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				transactTime: 1638316800000,
+			},
+			want: []types.Order{
+				{OrderID: 7557471, Price: 48000.5, Side: "BUY", TransactTime: 1638317400000},
+				{OrderID: 7557512, Price: 48650.1, Side: "SELL", TransactTime: 1638321000000},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"OrderID", "Price", "Side", "TransactTime"}
+	mock.ExpectBegin()                                                               /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadOrdersSince(?,?)")). /* call procedure */
+												WithArgs("c683ok5mk1u1120gnmmg", 1638316800000).
+												WillReturnRows(sqlmock.NewRows(columns).
+													AddRow(7557471, 48000.5, "BUY", 1638317400000).
+													AddRow(7557512, 48650.1, "SELL", 1638321000000)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetThreadOrdersSince(tt.args.sessionData, tt.args.transactTime)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadOrdersSince() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetThreadOrdersSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetEquitySince(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		since       int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.EquitySnapshot
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				since: 1638316800000,
+			},
+			want: []types.EquitySnapshot{
+				{Time: 1638316800000, Equity: 1500.25},
+				{Time: 1638317100000, Equity: 1490.5},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Time", "Equity"}
+	mock.ExpectBegin()                                                       /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetEquitySince(?)")). /* call procedure */
+											WithArgs(1638316800000).
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(1638316800000, 1500.25).
+												AddRow(1638317100000, 1490.5)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetEquitySince(tt.args.sessionData, tt.args.since)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetEquitySince() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetEquitySince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetThreadCycles(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		limit       int
+		offset      int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.ThreadCycle
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				limit:  20,
+				offset: 20,
+			},
+			want: []types.ThreadCycle{
+				{BuyOrderID: 7557471, SellOrderID: 7557512, Quantity: 0.002, BuyPrice: 48000.5, SellPrice: 48650.1, BuyQuote: 96, SellQuote: 97.3, TransactTime: 1638321000000},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"BuyOrderID", "SellOrderID", "Quantity", "BuyPrice", "SellPrice", "BuyQuote", "SellQuote", "TransactTime"}
+	mock.ExpectBegin()                                                            /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadCycles(?,?,?)")). /* call procedure */
+											WithArgs("c683ok5mk1u1120gnmmg", 20, 20).
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(7557471, 7557512, 0.002, 48000.5, 48650.1, 96, 97.3, 1638321000000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetThreadCycles(tt.args.sessionData, tt.args.limit, tt.args.offset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadCycles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetThreadCycles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetConfigAudit(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		limit       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.ConfigVersion
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				limit: 20,
+			},
+			want: []types.ConfigVersion{
+				{Version: 2, Username: "admin", Time: 1638321000000, Changes: "stoploss: 0 -> 0.05"},
+				{Version: 1, Username: "admin", Time: 1638317400000, Changes: "buy_wait: 60 -> 30"},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Version", "Username", "Time", "Changes"}
+	mock.ExpectBegin()                                                         /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetConfigAudit(?,?)")). /* call procedure */
+											WithArgs("c683ok5mk1u1120gnmmg", 20).
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(2, "admin", 1638321000000, "stoploss: 0 -> 0.05").
+												AddRow(1, "admin", 1638317400000, "buy_wait: 60 -> 30")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetConfigAudit(tt.args.sessionData, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetConfigAudit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetConfigAudit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetPresetNames(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    []string{"dca-conservative", "scalper"},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Name"}
+	mock.ExpectBegin()                                                      /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPresetNames()")). /* call procedure */
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow("dca-conservative").
+											AddRow("scalper")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPresetNames(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPresetNames() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPresetNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetPreset(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		name        string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				name: "scalper",
+			},
+			want:    `{"buy_wait":"30","stoploss":"0.05"}`,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Config"}
+	mock.ExpectBegin()                                                  /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPreset(?)")). /* call procedure */
+										WithArgs("scalper").
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow(`{"buy_wait":"30","stoploss":"0.05"}`)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPreset(tt.args.sessionData, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPreset() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetPreset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetPreference(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		username    string
+	}
+
+	tests := []struct {
+		name      string
+		args      args
+		want      types.Preference
+		wantFound bool
+		wantErr   bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				username: "admin",
+			},
+			want: types.Preference{
+				Theme:           "dark",
+				RefreshInterval: 5,
+				Currency:        "EUR",
+				Columns:         []string{"Quantity", "Price", "Target"},
+				Language:        "pt",
+			},
+			wantFound: true,
+			wantErr:   false,
+		},
+	}
+
+	columns := []string{"Theme", "RefreshInterval", "Currency", "Columns", "Language"}
+	mock.ExpectBegin()                                                      /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPreference(?)")). /* call procedure */
+										WithArgs("admin").
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow("dark", 5, "EUR", "Quantity,Price,Target", "pt")) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := GetPreference(tt.args.sessionData, tt.args.username)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPreference() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if found != tt.wantFound {
+				t.Errorf("GetPreference() found = %v, want %v", found, tt.wantFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPreference() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetBalances(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.Balance
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want: []types.Balance{
+				{Account: "binance", Asset: "BTC", Free: 0.5, Locked: 0.1, Time: 1638321000000},
+				{Account: "binance", Asset: "USDT", Free: 1500, Locked: 0, Time: 1638321000000},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Account", "Asset", "Free", "Locked", "Time"}
+	mock.ExpectBegin()                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetBalances()")). /* call procedure */
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow("binance", "BTC", 0.5, 0.1, 1638321000000).
+											AddRow("binance", "USDT", 1500, 0, 1638321000000)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetBalances(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBalances() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBalances() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetOpenPositions(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.Position
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want: []types.Position{
+				{ThreadID: "c683ok5mk1u1120gnmmg", Exchange: "binance", Symbol: "BTCUSDT", FiatSymbol: "USDT", Quantity: 0.01, Cost: 480},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ThreadID", "Exchange", "Symbol", "FiatSymbol", "Quantity", "Cost"}
+	mock.ExpectBegin()                                                        /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetOpenPositions()")). /* call procedure */
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow("c683ok5mk1u1120gnmmg", "binance", "BTCUSDT", "USDT", 0.01, 480)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOpenPositions(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetOpenPositions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOpenPositions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetNotes(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		tag         string
+		limit       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.Note
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				tag:   "manual",
+				limit: 100,
+			},
+			want: []types.Note{
+				{ID: 2, ThreadID: "c683ok5mk1u1120gnmmg", OrderID: 5000, Username: "admin", Time: 1638321000000, Tags: []string{"manual", "news"}, Text: "Sold ahead of CPI release", Symbol: "BTCUSDT", Side: "SELL", Price: 48000, Quantity: 0.01},
+				{ID: 1, ThreadID: "c683ok5mk1u1120gnmmg", OrderID: 0, Username: "admin", Time: 1638317400000, Tags: []string{"manual"}, Text: "Paused buys during maintenance", Symbol: "", Side: "", Price: 0, Quantity: 0},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "ThreadID", "OrderID", "Username", "Time", "Tags", "Text", "Symbol", "Side", "Price", "ExecutedQuantity"}
+	mock.ExpectBegin()                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetNotes(?,?)")). /* call procedure */
+										WithArgs("manual", 100).
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow(2, "c683ok5mk1u1120gnmmg", 5000, "admin", 1638321000000, "manual,news", "Sold ahead of CPI release", "BTCUSDT", "SELL", 48000, 0.01).
+											AddRow(1, "c683ok5mk1u1120gnmmg", 0, "admin", 1638317400000, "manual", "Paused buys during maintenance", "", "", 0, 0)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetNotes(tt.args.sessionData, tt.args.tag, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNotes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetNotes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetAlertRules(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.AlertRule
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want: []types.AlertRule{
+				{ID: 1, Name: "Unrealized loss", ThreadID: "", Metric: "unrealized_loss_pct", Operator: ">", Threshold: 5, Channel: "telegram", Target: ""},
+				{ID: 2, Name: "Idle thread", ThreadID: "c683ok5mk1u1120gnmmg", Metric: "hours_since_trade", Operator: ">", Threshold: 6, Channel: "webhook", Target: "https://example.com/hook"},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "Name", "ThreadID", "Metric", "Operator", "Threshold", "Channel", "Target"}
+	mock.ExpectBegin()                                                     /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetAlertRules()")). /* call procedure */
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow(1, "Unrealized loss", "", "unrealized_loss_pct", ">", 5, "telegram", "").
+											AddRow(2, "Idle thread", "c683ok5mk1u1120gnmmg", "hours_since_trade", ">", 6, "webhook", "https://example.com/hook")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAlertRules(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetAlertRules() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAlertRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetThreadLastOrderTime(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want:    1638321000000,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"TransactTime"}
+	mock.ExpectBegin()                                                               /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadLastOrderTime(?)")). /* call procedure */
+												WithArgs(tests[0].args.sessionData.ThreadID).                  /* with args */
+												WillReturnRows(sqlmock.NewRows(columns).AddRow(1638321000000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetThreadLastOrderTime(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadLastOrderTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetThreadLastOrderTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		username    string
+		codeHash    string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				username: "admin",
+				codeHash: "5d41402abc4b2a76b9719d911017c592",
+			},
+			want:    true,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Used"}
+	mock.ExpectBegin()                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UseRecoveryCode(?,?)")). /* call procedure */
+											WithArgs(tests[0].args.username, tests[0].args.codeHash). /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow(1))        /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UseRecoveryCode(tt.args.sessionData, tt.args.username, tt.args.codeHash)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UseRecoveryCode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UseRecoveryCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteExcessAuthTokens(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		username    string
+		kind        string
+		keep        int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				username: "admin",
+				kind:     "SESSION",
+				keep:     3,
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.DeleteExcessAuthTokens(?,?,?)")). /* call procedure */
+												WithArgs(tests[0].args.username, tests[0].args.kind, tests[0].args.keep). /* with args */
+												WillReturnRows(sqlmock.NewRows([]string{""}))                             /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DeleteExcessAuthTokens(tt.args.sessionData, tt.args.username, tt.args.kind, tt.args.keep); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteExcessAuthTokens() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExportMonthlyProfit(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		from        int64
+		to          int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.ProfitSummary
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				from: 1635724800000,
+				to:   1640995200000,
+			},
+			want: []types.ProfitSummary{
+				{Key: "2021-11", Trades: 12, Profit: 35.5, ProfitPct: 0.012},
+				{Key: "2021-12", Trades: 3, Profit: -4.25, ProfitPct: 0},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Month", "Trades", "Profit", "ProfitPct"}
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.ExportMonthlyProfit(?,?)")). /* call procedure */
+											WithArgs(tests[0].args.from, tests[0].args.to).                                                              /* with args */
+											WillReturnRows(sqlmock.NewRows(columns).AddRow("2021-11", 12, 35.5, 0.012).AddRow("2021-12", 3, -4.25, nil)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []types.ProfitSummary
+			err := ExportMonthlyProfit(tt.args.sessionData, tt.args.from, tt.args.to, func(summary types.ProfitSummary) error {
+				got = append(got, summary)
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExportMonthlyProfit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExportMonthlyProfit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		filter      types.OrderFilter
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.Trade
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				filter: types.OrderFilter{Symbol: "BTCUSDT", Search: "123", Sort: "Price", Descending: true, Limit: 50, Offset: 50},
+			},
+			want: []types.Trade{
+				{TransactTime: 1638353700000, ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Side: "SELL", Status: "FILLED", OrderID: 1234, OrderIDSource: 1230, Price: 41000, Quantity: 0.0025, Quote: 102.5, Source: "bot"},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"TransactTime", "ThreadID", "Symbol", "Side", "Status", "OrderID", "OrderIDSource", "Price", "ExecutedQuantity", "CummulativeQuoteQty", "Source"}
+	mock.ExpectBegin()                                                                  /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetOrders(?,?,?,?,?,?,?,?,?)")). /* call procedure */
+												WithArgs("", "BTCUSDT", "", "", "123", "Price", true, 50, 50).                                                                                               /* with args */
+												WillReturnRows(sqlmock.NewRows(columns).AddRow(1638353700000, "c683ok5mk1u1120gnmmg", "BTCUSDT", "SELL", "FILLED", 1234, 1230, 41000, 0.0025, 102.5, "bot")) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOrders(tt.args.sessionData, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetOrders() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetOrders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOrderCount(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		filter      types.OrderFilter
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				filter: types.OrderFilter{Side: "BUY", Status: "FILLED"},
+			},
+			want:    321,
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetOrderCount(?,?,?,?,?)")). /* call procedure */
+											WithArgs("", "", "BUY", "FILLED", "").                         /* with args */
+											WillReturnRows(sqlmock.NewRows([]string{"Count"}).AddRow(321)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOrderCount(tt.args.sessionData, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetOrderCount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetOrderCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPnlSince(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		since       int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.PnlSnapshot
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				since: 1638316800000,
+			},
+			want: []types.PnlSnapshot{
+				{Time: 1638316800000, ThreadID: "c683ok5mk1u1120gnmmg", Realized: 12.5, Unrealized: -3.25},
+				{Time: 1638316860000, ThreadID: "c683ok5mk1u1120gnmmg", Realized: 12.5, Unrealized: -2},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Time", "ThreadID", "Realized", "Unrealized"}
+	mock.ExpectBegin()                                                    /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetPnlSince(?)")). /* call procedure */
+										WithArgs(1638316800000).
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow(1638316800000, "c683ok5mk1u1120gnmmg", 12.5, -3.25).
+											AddRow(1638316860000, "c683ok5mk1u1120gnmmg", 12.5, -2)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetPnlSince(tt.args.sessionData, tt.args.since)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPnlSince() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPnlSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetThreadTimeline(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		threadID    string
+		from        int64
+		to          int64
+		limit       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.ThreadEvent
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				threadID: "c683ok5mk1u1120gnmmg",
+				from:     1638316800000,
+				to:       1638403200000,
+				limit:    500,
+			},
+			want: []types.ThreadEvent{
+				{Time: 1638316800000, Kind: "pause", Source: "admin", OrderID: 0, Detail: "Thread paused by admin"},
+				{Time: 1638316860000, Kind: "sell", Source: "bot", OrderID: 1212121, Detail: "FILLED 0.5 BTCUSDT at 57000 for 28500"},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Time", "Kind", "Source", "OrderID", "Detail"}
+	mock.ExpectBegin()                                                                /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadTimeline(?,?,?,?)")). /* call procedure */
+												WithArgs("c683ok5mk1u1120gnmmg", 1638316800000, 1638403200000, 500).
+												WillReturnRows(sqlmock.NewRows(columns).
+													AddRow(1638316800000, "pause", "admin", 0, "Thread paused by admin").
+													AddRow(1638316860000, "sell", "bot", 1212121, "FILLED 0.5 BTCUSDT at 57000 for 28500")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetThreadTimeline(tt.args.sessionData, tt.args.threadID, tt.args.from, tt.args.to, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadTimeline() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetThreadTimeline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestSaveShadowOrder(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		order       types.ShadowOrder
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				order: types.ShadowOrder{Symbol: "BTCUSDT", Side: "SELL", Price: 57000, Quantity: 0.5, CumulativeQuoteQuantity: 28500, OrderIDSource: 12, Profit: 500, Decision: "Profit target reached", TransactTime: 1638316860000},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveShadowOrder(?,?,?,?,?,?,?,?,?,?)")). /* call procedure */
+													WithArgs("c683ok5mk1u1120gnmmg", "BTCUSDT", "SELL", 57000.0, 0.5, 28500.0, 12, 500.0, "Profit target reached", 1638316860000). /* with args */
+													WillReturnRows(sqlmock.NewRows([]string{""}))                                                                                  /* return empty row */
+	mock.ExpectCommit()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveShadowOrder(tt.args.sessionData, tt.args.order); (err != nil) != tt.wantErr {
+				t.Errorf("SaveShadowOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetShadowOrders(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		threadID    string
+		limit       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.ShadowOrder
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				threadID: "c683ok5mk1u1120gnmmg",
+				limit:    100,
+			},
+			want: []types.ShadowOrder{
+				{ID: 13, Symbol: "BTCUSDT", Side: "SELL", Price: 57000, Quantity: 0.5, CumulativeQuoteQuantity: 28500, OrderIDSource: 12, Profit: 500, Decision: "Profit target reached", TransactTime: 1638316860000},
+				{ID: 12, Symbol: "BTCUSDT", Side: "BUY", Price: 56000, Quantity: 0.5, CumulativeQuoteQuantity: 28000, OrderIDSource: 0, Profit: 0, Decision: "Buy initial", TransactTime: 1638316800000},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "Symbol", "Side", "Price", "Quantity", "CumulativeQuoteQuantity", "OrderIDSource", "Profit", "Decision", "TransactTime"}
+	mock.ExpectBegin()                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetShadowOrders(?,?)")). /* call procedure */
+											WithArgs("c683ok5mk1u1120gnmmg", 100).
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(13, "BTCUSDT", "SELL", 57000, 0.5, 28500, 12, 500, "Profit target reached", 1638316860000).
+												AddRow(12, "BTCUSDT", "BUY", 56000, 0.5, 28000, 0, 0, "Buy initial", 1638316800000)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetShadowOrders(tt.args.sessionData, tt.args.threadID, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetShadowOrders() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetShadowOrders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetShadowOpenOrders(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.ShadowOrder
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want: []types.ShadowOrder{
+				{ID: 14, Symbol: "BTCUSDT", Side: "BUY", Price: 55000, Quantity: 0.5, CumulativeQuoteQuantity: 27500, OrderIDSource: 0, Profit: 0, Decision: "Buy downmarket", TransactTime: 1638317000000},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "Symbol", "Side", "Price", "Quantity", "CumulativeQuoteQuantity", "OrderIDSource", "Profit", "Decision", "TransactTime"}
+	mock.ExpectBegin()                                                            /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetShadowOpenOrders(?)")). /* call procedure */
+											WithArgs("c683ok5mk1u1120gnmmg").
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(14, "BTCUSDT", "BUY", 55000, 0.5, 27500, 0, 0, "Buy downmarket", 1638317000000)) /* return 1 row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetShadowOpenOrders(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetShadowOpenOrders() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetShadowOpenOrders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetWebhooks(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.Webhook
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+			},
+			want: []types.Webhook{
+				{ID: 1, Name: "Fills", URL: "https://example.com/hook", Events: []string{"order.filled", "error"}, Secret: "0123456789abcdef", Enabled: true},
+				{ID: 2, Name: "Disabled", URL: "https://example.com/other", Events: nil, Secret: "fedcba9876543210", Enabled: false},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "Name", "URL", "Events", "Secret", "Enabled"}
+	mock.ExpectBegin()                                                   /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetWebhooks()")). /* call procedure */
+										WillReturnRows(sqlmock.NewRows(columns).
+											AddRow(1, "Fills", "https://example.com/hook", "order.filled,error", "0123456789abcdef", true).
+											AddRow(2, "Disabled", "https://example.com/other", "", "fedcba9876543210", false)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetWebhooks(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetWebhooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetWebhooks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestSaveBacktest(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		backtest    types.Backtest
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				backtest: types.Backtest{Name: "RSI 30", Symbol: "BTCUSDT", StartTime: 1638316800000, EndTime: 1638403200000, CreatedTime: 1638403260000, Parameters: `{"buy_rsi7_entry":30}`, Trades: 12, ProfitPct: 2.5, BuyHoldPct: -1.25, MaxDrawdownPct: 3, WinRatePct: 75},
+			},
+			want:    7,
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                         /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveBacktest(?,?,?,?,?,?,?,?,?,?,?)")). /* call procedure */
+													WithArgs("RSI 30", "BTCUSDT", 1638316800000, 1638403200000, 1638403260000, `{"buy_rsi7_entry":30}`, 12, 2.5, -1.25, float64(3), float64(75)).
+													WillReturnRows(sqlmock.NewRows([]string{"LAST_INSERT_ID()"}).AddRow(7)) /* return the new ID */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SaveBacktest(tt.args.sessionData, tt.args.backtest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SaveBacktest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SaveBacktest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetBacktestEquity(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		backtestID  int64
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.BacktestEquity
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				backtestID: 7,
+			},
+			want: []types.BacktestEquity{
+				{Time: 1638316800000, Equity: 1000, BuyHold: 1000},
+				{Time: 1638320400000, Equity: 1012.5, BuyHold: 990},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"Time", "Equity", "BuyHold"}
+	mock.ExpectBegin()                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetBacktestEquity(?)")). /* call procedure */
+											WithArgs(7).
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(1638316800000, 1000, 1000).
+												AddRow(1638320400000, 1012.5, 990)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetBacktestEquity(tt.args.sessionData, tt.args.backtestID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBacktestEquity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBacktestEquity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestGetThreadCommands(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.ThreadCommand
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+			},
+			want: []types.ThreadCommand{
+				{ID: 1, ThreadID: "c683ok5mk1u1120gnmmg", Command: "pause", Source: "Telegram", CreatedTime: 1638316800000},
+				{ID: 2, ThreadID: "c683ok5mk1u1120gnmmg", Command: "sell", OrderID: 1234567, Source: "Telegram", CreatedTime: 1638316860000},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "ThreadID", "Command", "OrderID", "Source", "CreatedTime"}
+	mock.ExpectBegin()                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetThreadCommands(?)")). /* call procedure */
+											WithArgs("c683ok5mk1u1120gnmmg").
+											WillReturnRows(sqlmock.NewRows(columns).
+												AddRow(1, "c683ok5mk1u1120gnmmg", "pause", 0, "Telegram", 1638316800000).
+												AddRow(2, "c683ok5mk1u1120gnmmg", "sell", 1234567, "Telegram", 1638316860000)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetThreadCommands(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetThreadCommands() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetThreadCommands() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestSaveQueuedNotification(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData  *types.Session
+		notification types.QueuedNotification
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				notification: types.QueuedNotification{
+					ThreadID:    "c683ok5mk1u1120gnmmg",
+					Channel:     "telegram",
+					Text:        "SELL BTCUSDT 0.002100 @ 57600.0000",
+					Attempts:    1,
+					NextAttempt: 1638230430000,
+					LastError:   "Post \"https://api.telegram.org\": dial tcp: i/o timeout",
+					CreatedTime: 1638230400000,
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveQueuedNotification(?,?,?,?,?,?,?)")).
+		WithArgs(
+								tests[0].args.notification.ThreadID,
+								tests[0].args.notification.Channel,
+								tests[0].args.notification.Text,
+								tests[0].args.notification.Attempts,
+								tests[0].args.notification.NextAttempt,
+								tests[0].args.notification.LastError,
+								tests[0].args.notification.CreatedTime).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveQueuedNotification(tt.args.sessionData, tt.args.notification); (err != nil) != tt.wantErr {
+				t.Errorf("SaveQueuedNotification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetQueuedNotifications(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		time        int64
+		limit       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				time:  1638230460000,
+				limit: 50,
+			},
+			want:    2,
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "ThreadID", "Channel", "Text", "Attempts", "NextAttempt", "LastError", "CreatedTime"}
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetQueuedNotifications(?,?)")).
+		WithArgs(tests[0].args.time, tests[0].args.limit).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "c683ok5mk1u1120gnmmg", "telegram", "SELL BTCUSDT", 1, 1638230430000, "timeout", 1638230400000).
+			AddRow(2, "c683ok5mk1u1120gnmmh", "discord", "BUY ETHUSDT", 2, 1638230460000, "502 Bad Gateway", 1638230300000)) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetQueuedNotifications(tt.args.sessionData, tt.args.time, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetQueuedNotifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.want {
+				t.Errorf("GetQueuedNotifications() = %v, want %v notifications", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_procedure(t *testing.T) {
+	tests := []struct {
+		name string
+		call string
+		want string
+	}{
+		{name: "with args", call: "call cryptopump.SavePendingAction(?,?,?,?,?)", want: "SavePendingAction"},
+		{name: "without args", call: "call cryptopump.GetPendingActions()", want: "GetPendingActions"},
+		{name: "not a call", call: "SELECT 1", want: "SELECT 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := procedure(tt.call); got != tt.want {
+				t.Errorf("procedure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveLog(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		record      types.LogRecord
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				record: types.LogRecord{
+					Time:      1638230400000,
+					Level:     "debug",
+					ThreadID:  "c683ok5mk1u1120gnmmg",
+					Component: "exchange",
+					Message:   "github.com/aleibovici/cryptopump/exchange.BuyOrder - insufficient balance",
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveLog(?,?,?,?,?)")).
+		WithArgs(
+								tests[0].args.record.Time,
+								tests[0].args.record.Level,
+								tests[0].args.record.ThreadID,
+								tests[0].args.record.Component,
+								tests[0].args.record.Message).
+		WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveLog(tt.args.sessionData, tt.args.record); (err != nil) != tt.wantErr {
+				t.Errorf("SaveLog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetLogs(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		filter      types.LogFilter
+		limit       int
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    []types.LogRecord
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					ThreadID: "c683ok5mk1u1120gnmmg",
+					Db:       db,
+				},
+				filter: types.LogFilter{Component: "exchange", From: 1638230400000},
+				limit:  500,
+			},
+			want: []types.LogRecord{
+				{ID: 2, Time: 1638230460000, Level: "debug", ThreadID: "c683ok5mk1u1120gnmmh", Component: "exchange", Message: "github.com/aleibovici/cryptopump/exchange.GetOrder - timeout"},
+				{ID: 1, Time: 1638230400000, Level: "info", ThreadID: "c683ok5mk1u1120gnmmg", Component: "exchange", Message: "github.com/aleibovici/cryptopump/exchange.BuyOrder - order placed"},
+			},
+			wantErr: false,
+		},
+	}
+
+	columns := []string{"ID", "Time", "Level", "ThreadID", "Component", "Message"}
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetLogs(?,?,?,?,?,?,?)")).
+		WithArgs("", "", "exchange", "", int64(1638230400000), int64(0), 500).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, 1638230460000, "debug", "c683ok5mk1u1120gnmmh", "exchange", "github.com/aleibovici/cryptopump/exchange.GetOrder - timeout").
+			AddRow(1, 1638230400000, "info", "c683ok5mk1u1120gnmmg", "exchange", "github.com/aleibovici/cryptopump/exchange.BuyOrder - order placed")) /* return 2 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetLogs(tt.args.sessionData, tt.args.filter, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetLogs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetLogs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveAudit(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Db: db}
+	record := types.AuditRecord{Time: 1638230400000, ThreadID: "c683ok5mk1u1120gnmmg", Event: AuditOrderCreate, OrderID: 42, Payload: `{"event":"order.create"}`}
+
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveAudit(?,?,?,?,?)")).
+		WithArgs(record.Time, record.ThreadID, record.Event, record.OrderID, record.Payload).
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+
+	if err := SaveAudit(sessionData, record); err != nil {
+		t.Errorf("SaveAudit() error = %v", err)
+	}
+
+}
+
+func TestGetAudit(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Db: db}
+
+	want := []types.AuditRecord{
+		{ID: 1, Time: 1638230400000, ThreadID: "c683ok5mk1u1120gnmmg", Event: AuditOrderCreate, OrderID: 42, Payload: "{}", PrevHash: strings.Repeat("0", 64), Hash: strings.Repeat("a", 64)},
+		{ID: 2, Time: 1638230460000, ThreadID: "c683ok5mk1u1120gnmmg", Event: AuditOrderUpdate, OrderID: 42, Payload: "{}", PrevHash: strings.Repeat("a", 64), Hash: strings.Repeat("b", 64)},
+	}
+
+	columns := []string{"ID", "Time", "ThreadID", "Event", "OrderID", "Payload", "PrevHash", "Hash"}
+	rows := sqlmock.NewRows(columns)
+	for _, record := range want {
+		rows.AddRow(record.ID, record.Time, record.ThreadID, record.Event, record.OrderID, record.Payload, record.PrevHash, record.Hash)
+	}
+
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetAudit(?,?)")).
+		WithArgs(int64(0), 1000).
+		WillReturnRows(rows)
+
+	got, err := GetAudit(sessionData, 0, 1000)
+	if err != nil {
+		t.Fatalf("GetAudit() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAudit() = %v, want %v", got, want)
+	}
+
+}
+
+func TestSaveWsConnection(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Db: db}
+	connection := types.WsConnection{ThreadID: "c683ok5mk1u1120gnmmg", Stream: "kline", ConnectedTime: 1638230400000, DisconnectedTime: 1638234000000, Messages: 3600, Reason: "closed", Error: "websocket: close 1006 (abnormal closure): unexpected EOF"}
+
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.SaveWsConnection(?,?,?,?,?,?,?)")).
+		WithArgs(connection.ThreadID, connection.Stream, connection.ConnectedTime, connection.DisconnectedTime, connection.Messages, connection.Reason, connection.Error).
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+
+	if err := SaveWsConnection(sessionData, connection); err != nil {
+		t.Errorf("SaveWsConnection() error = %v", err)
+	}
+
+}
+
+func TestGetWsConnections(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Db: db}
+
+	want := []types.WsConnection{
+		{ThreadID: "c683ok5mk1u1120gnmmg", Stream: "bookticker", ConnectedTime: 1638234000000, DisconnectedTime: 1638237600000, Messages: 360000, Reason: "stale", Error: ""},
+		{ThreadID: "c683ok5mk1u1120gnmmg", Stream: "kline", ConnectedTime: 1638230400000, DisconnectedTime: 1638234000000, Messages: 3600, Reason: "closed", Error: "websocket: close 1006 (abnormal closure): unexpected EOF"},
+	}
+
+	columns := []string{"ThreadID", "Stream", "ConnectedTime", "DisconnectedTime", "Messages", "Reason", "Error"}
+	rows := sqlmock.NewRows(columns)
+	for _, connection := range want {
+		rows.AddRow(connection.ThreadID, connection.Stream, connection.ConnectedTime, connection.DisconnectedTime, connection.Messages, connection.Reason, connection.Error)
+	}
+
+	mock.ExpectBegin() /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetWsConnections(?,?)")).
+		WithArgs("c683ok5mk1u1120gnmmg", 20).
+		WillReturnRows(rows)
+
+	got, err := GetWsConnections(sessionData, 20)
+	if err != nil {
+		t.Fatalf("GetWsConnections() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetWsConnections() = %v, want %v", got, want)
+	}
+
+}
+
+func Test_classify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "bad connection", err: driver.ErrBadConn, want: true},
+		{name: "invalid connection", err: mysqldriver.ErrInvalidConn, want: true},
+		{name: "network", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, want: true},
+		{name: "too many connections", err: &mysqldriver.MySQLError{Number: 1040, Message: "Too many connections"}, want: true},
+		{name: "procedure", err: &mysqldriver.MySQLError{Number: 1305, Message: "PROCEDURE cryptopump.Get does not exist"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classify(tt.err)
+			if errors.Is(got, ErrDBUnavailable) != tt.want || got.Error() != tt.err.Error() || !errors.Is(got, tt.err) {
+				t.Errorf("classify() = %v, want ErrDBUnavailable %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSchemaColumns(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string][]string
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+			},
+			want:    map[string][]string{"session": {"ThreadID", "ThreadIDSession"}, "symbollist": {"Symbol", "List"}},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                                    /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TABLE_NAME, COLUMN_NAME FROM information_schema.COLUMNS")). /* query information schema */
+														WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME"}).
+															AddRow("session", "ThreadID").
+															AddRow("session", "ThreadIDSession").
+															AddRow("symbollist", "Symbol").
+															AddRow("symbollist", "List")) /* return 4 rows */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSchemaColumns(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSchemaColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSchemaColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSchemaProcedures(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+			},
+			want:    map[string]string{"GetSymbolList": "BEGIN\nSELECT `symbollist`.`Symbol`, `symbollist`.`List` FROM `cryptopump`.`symbollist`;\nEND", "GetThreadCount": ""},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                                              /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT ROUTINE_NAME, ROUTINE_DEFINITION FROM information_schema.ROUTINES")). /* query information schema */
+															WillReturnRows(sqlmock.NewRows([]string{"ROUTINE_NAME", "ROUTINE_DEFINITION"}).
+																AddRow("GetSymbolList", "BEGIN\nSELECT `symbollist`.`Symbol`, `symbollist`.`List` FROM `cryptopump`.`symbollist`;\nEND").
+																AddRow("GetThreadCount", nil)) /* return 2 rows, one without privileges */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSchemaProcedures(tt.args.sessionData)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSchemaProcedures() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSchemaProcedures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecSchema(t *testing.T) {
+
+	db, mock := NewMock()
+	defer db.Close()
+
+	type args struct {
+		sessionData *types.Session
+		statement   string
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				sessionData: &types.Session{
+					Db: db,
+				},
+				statement: "ALTER TABLE `thread` ADD COLUMN `DryRun` tinyint NOT NULL DEFAULT '0' AFTER `Symbol`",
+			},
+			wantErr: false,
+		},
+	}
+
+	mock.ExpectBegin()                                                                                                          /* begin transaction */
+	mock.ExpectQuery(regexp.QuoteMeta("ALTER TABLE `thread` ADD COLUMN `DryRun` tinyint NOT NULL DEFAULT '0' AFTER `Symbol`")). /* execute statement */
+																	WillReturnRows(sqlmock.NewRows([]string{""})) /* return empty row */
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ExecSchema(tt.args.sessionData, tt.args.statement); (err != nil) != tt.wantErr {
+				t.Errorf("ExecSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
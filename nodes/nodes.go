@@ -1,12 +1,15 @@
 package nodes
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
 	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/notify"
 	"github.com/aleibovici/cryptopump/types"
 )
 
@@ -146,3 +149,63 @@ func (Node) CheckStatus(configData *types.Config,
 
 	sessionData.Status = false
 }
+
+// CheckDatabase check database connectivity and send a critical error notification when the database is
+// unreachable for longer than DbDownMinutes
+func (Node) CheckDatabase(configData *types.Config,
+	sessionData *types.Session) {
+
+	if err := sessionData.Db.Ping(); err == nil {
+
+		if !sessionData.DbDownTime.IsZero() {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  "Database reachable again",
+				LogLevel: "InfoLevel",
+			}.Do()
+
+		}
+
+		sessionData.DbDownTime = time.Time{}
+		return
+
+	}
+
+	if sessionData.DbDownTime.IsZero() {
+
+		sessionData.DbDownTime = time.Now()
+
+	}
+
+	if configData.ConfigGlobal == nil || !isDbDown(sessionData.DbDownTime, configData.ConfigGlobal.DbDownMinutes, time.Now()) {
+
+		return
+
+	}
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Critical,
+		Key:      "database-down",
+		Title:    "Database down",
+		Data: messages.Data{
+			ThreadID: sessionData.ThreadID,
+			Message:  fmt.Sprintf("Database unreachable for %.0f minutes @ %s", time.Since(sessionData.DbDownTime).Minutes(), sessionData.ThreadID),
+		},
+	}.Send(configData, sessionData)
+
+}
+
+/* Return true when the database unreachable since downTime is down for at least minutes, 0 minutes disables */
+func isDbDown(
+	downTime time.Time,
+	minutes int,
+	now time.Time) bool {
+
+	return minutes > 0 && !downTime.IsZero() && now.Sub(downTime) >= time.Duration(minutes)*time.Minute
+
+}
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aleibovici/cryptopump/types"
 )
@@ -85,3 +86,25 @@ func TestNode_ReleaseMasterRole(t *testing.T) {
 		})
 	}
 }
+
+func Test_isDbDown(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		downTime time.Time
+		minutes  int
+		want     bool
+	}{
+		{name: "reachable", downTime: time.Time{}, minutes: 5, want: false},
+		{name: "down less than minutes", downTime: now.Add(-4 * time.Minute), minutes: 5, want: false},
+		{name: "down for minutes", downTime: now.Add(-5 * time.Minute), minutes: 5, want: true},
+		{name: "disabled", downTime: now.Add(-time.Hour), minutes: 0, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDbDown(tt.downTime, tt.minutes, now); got != tt.want {
+				t.Errorf("isDbDown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package notify
+
+/* This package implements the notification routing. A notification of an event (buy, sell, profit, stoploss or
+error) with a severity (info, warning or critical) is sent to the channels selected by the routing rules of the
+global configuration (NotifyRoutes), one rule per line: <event> <channel> [<minimum severity>] [<ThreadID>], where
+event and channel can be * for all. A channel receives the notification when any rule matches its event, severity
+and thread, i.e. "* * info c683ok5mk1u1120gnmmg" sends everything of a thread to every channel. Without rules the
+default routes apply. Channels must also be configured, Discord only sends the event types in DiscordEvents and SMS
+only critical notifications. */
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/aleibovici/cryptopump/discord"
+	"github.com/aleibovici/cryptopump/email"
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/matrix"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/push"
+	"github.com/aleibovici/cryptopump/slack"
+	"github.com/aleibovici/cryptopump/sms"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Notification severities, from lowest to highest */
+const (
+	Info     = "info"
+	Warning  = "warning"
+	Critical = "critical"
+)
+
+const all = "*" /* Any event or channel */
+
+// Events list the notification events that can be routed
+var Events = []string{messages.Buy, messages.Sell, messages.Profit, messages.Stoploss, messages.Error}
+
+// Channels list the notification channels
+var Channels = []string{messages.Telegram, messages.Discord, messages.Slack, messages.Matrix, messages.Email, messages.Push, messages.SMS}
+
+var severities = map[string]int{Info: 0, Warning: 1, Critical: 2}
+
+// ErrInvalidRoute is returned for a routing rule with an unknown event, channel or severity
+var ErrInvalidRoute = errors.New("Routes must be <event> <channel> [<minimum severity>] [<ThreadID>]")
+
+// Route struct define a notification routing rule
+type Route struct {
+	Event    string /* Event or * */
+	Channel  string /* Channel or * */
+	Severity string /* Minimum severity */
+	ThreadID string /* Empty for all threads */
+}
+
+/* Routes applied when NotifyRoutes is empty */
+var defaultRoutes = []Route{
+	{Event: messages.Buy, Channel: messages.Discord, Severity: Info},
+	{Event: messages.Buy, Channel: messages.Slack, Severity: Info},
+	{Event: messages.Sell, Channel: messages.Discord, Severity: Info},
+	{Event: messages.Sell, Channel: messages.Slack, Severity: Info},
+	{Event: messages.Profit, Channel: messages.Discord, Severity: Info},
+	{Event: messages.Stoploss, Channel: messages.Email, Severity: Info},
+	{Event: messages.Stoploss, Channel: messages.Push, Severity: Info},
+	{Event: messages.Error, Channel: messages.Telegram, Severity: Info},
+	{Event: messages.Error, Channel: messages.Discord, Severity: Info},
+	{Event: messages.Error, Channel: messages.Matrix, Severity: Info},
+	{Event: messages.Error, Channel: messages.Email, Severity: Info},
+	{Event: messages.Error, Channel: messages.Push, Severity: Info},
+	{Event: messages.Error, Channel: messages.SMS, Severity: Critical},
+}
+
+// Sender sends the rendered text of a notification to a channel
+type Sender func(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification,
+	text string)
+
+var senders = map[string]Sender{
+	messages.Discord: sendDiscord,
+	messages.Slack:   sendSlack,
+	messages.Matrix:  sendMatrix,
+	messages.Email:   sendEmail,
+	messages.Push:    sendPush,
+	messages.SMS:     sendSMS,
+}
+
+// Register the sender of a channel, used for the channels that can't be imported by this package (Telegram)
+func Register(
+	channel string,
+	sender Sender) {
+
+	senders[channel] = sender
+
+}
+
+// Observer is called with every notification sent with Send, i.e. to report the critical notifications
+type Observer func(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification)
+
+var observers []Observer
+
+// Observe register an observer of the notifications sent with Send. Called at startup.
+func Observe(observer Observer) {
+
+	observers = append(observers, observer)
+
+}
+
+// Notification struct define a notification of an event
+type Notification struct {
+	Event    string /* messages event */
+	Severity string
+	Key      string /* Alert key, the same alert is emailed and pushed at most once every 10 minutes */
+	Title    string /* Email subject and push title */
+	Data     messages.Data
+}
+
+// Send the notification to the channels its routes select, rendered with the message template of each channel
+func (notification Notification) Send(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if notification.Data.ThreadID == "" {
+		notification.Data.ThreadID = sessionData.ThreadID
+	}
+
+	for _, observer := range observers {
+		observer(configData, sessionData, notification)
+	}
+
+	routes := Routes(configData, sessionData)
+
+	for _, channel := range Channels {
+
+		sender, ok := senders[channel]
+		if !ok || !Routed(routes, notification.Event, channel, notification.Severity, notification.Data.ThreadID) {
+			continue
+		}
+
+		sender(configData, sessionData, notification, messages.Text(sessionData, channel, notification.Event, notification.Data))
+
+	}
+
+}
+
+// Handle send the notification of the order, market and error events published on the event bus
+func Handle(event events.Event) {
+
+	if notification, ok := NewNotification(event); ok {
+
+		notification.Send(event.Config, event.Session)
+
+	}
+
+}
+
+// NewNotification return the notification of event, ok is false for the events that are not notified
+func NewNotification(event events.Event) (notification Notification, ok bool) {
+
+	data := messages.Data{ThreadID: event.Session.ThreadID}
+
+	switch d := event.Data.(type) {
+	case events.Order:
+
+		if event.Topic != events.OrderFilled {
+
+			return notification, false
+
+		}
+
+		notification = Notification{Event: messages.Buy, Severity: Info}
+		if d.Side == "SELL" {
+			notification.Event = messages.Sell
+		}
+
+		data.Symbol, data.Fiat, data.Side, data.Price, data.Quantity = d.Symbol, event.Session.SymbolFiat, d.Side, d.Price, d.Quantity
+		data.Profit, data.ProfitPct = d.Profit, d.ProfitPct
+
+	case events.Profit:
+
+		notification = Notification{Event: messages.Profit, Severity: Info}
+		data.Symbol, data.Fiat, data.Profit = d.Symbol, event.Session.SymbolFiat, d.Profit
+
+	case events.Stoploss:
+
+		notification = Notification{Event: messages.Stoploss, Severity: Warning, Key: "stoploss-" + event.Session.ThreadID, Title: d.Title}
+		data.Symbol, data.Fiat, data.Side, data.OrderID, data.Price = d.Symbol, event.Session.SymbolFiat, "SELL", d.Order.OrderID, d.Price
+		data.OrderPrice, data.Quantity, data.Reason = d.Order.Price, d.Order.ExecutedQuantity, d.Reason
+
+	case events.Error:
+
+		notification = Notification{Event: messages.Error, Severity: Critical, Key: d.Key, Title: d.Title}
+		data.Symbol, data.Side, data.Message = d.Symbol, d.Side, d.Message
+
+	default:
+
+		return notification, false
+
+	}
+
+	notification.Data = data
+
+	return notification, true
+
+}
+
+// SendTo send the notification to channels whatever the routes, rendered with the message template of each channel
+func (notification Notification) SendTo(
+	configData *types.Config,
+	sessionData *types.Session,
+	channels []string) {
+
+	if notification.Data.ThreadID == "" {
+		notification.Data.ThreadID = sessionData.ThreadID
+	}
+
+	for _, channel := range Channels {
+
+		sender, ok := senders[channel]
+		if !ok || !slices.Contains(channels, channel) {
+			continue
+		}
+
+		sender(configData, sessionData, notification, messages.Text(sessionData, channel, notification.Event, notification.Data))
+
+	}
+
+}
+
+// Routes return the routing rules of the global configuration, or the default routes when there are none. Invalid
+// rules are logged and ignored.
+func Routes(
+	configData *types.Config,
+	sessionData *types.Session) []Route {
+
+	if configData.ConfigGlobal == nil {
+
+		return defaultRoutes
+
+	}
+
+	routes, err := ParseRoutes(configData.ConfigGlobal.NotifyRoutes)
+	if err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	if len(routes) == 0 {
+
+		return defaultRoutes
+
+	}
+
+	return routes
+
+}
+
+// ParseRoutes parse the routing rules, one per line. Empty lines and lines starting with # are skipped, the valid
+// rules are returned with an error naming the first invalid line.
+func ParseRoutes(text string) (routes []Route, err error) {
+
+	for _, line := range strings.Split(text, "\n") {
+
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		route := Route{Severity: Info}
+
+		if len(fields) >= 2 {
+			route.Event = strings.ToLower(fields[0])
+			route.Channel = strings.ToLower(fields[1])
+		}
+
+		if len(fields) >= 3 {
+			route.Severity = strings.ToLower(fields[2])
+		}
+
+		if len(fields) == 4 {
+			route.ThreadID = fields[3]
+		}
+
+		if _, ok := severities[route.Severity]; !ok || len(fields) < 2 || len(fields) > 4 ||
+			(route.Event != all && !slices.Contains(Events, route.Event)) ||
+			(route.Channel != all && !slices.Contains(Channels, route.Channel)) {
+
+			if err == nil {
+				err = errors.New(ErrInvalidRoute.Error() + ": " + strings.TrimSpace(line))
+			}
+
+			continue
+
+		}
+
+		routes = append(routes, route)
+
+	}
+
+	return routes, err
+
+}
+
+// Routed return true when a route sends event with severity of threadID to channel
+func Routed(
+	routes []Route,
+	event string,
+	channel string,
+	severity string,
+	threadID string) bool {
+
+	for _, route := range routes {
+
+		if (route.Event == all || route.Event == event) &&
+			(route.Channel == all || route.Channel == channel) &&
+			severities[severity] >= severities[route.Severity] &&
+			(route.ThreadID == "" || route.ThreadID == threadID) {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Send to Discord as the Discord event type of the notification event */
+func sendDiscord(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification,
+	text string) {
+
+	event := discord.EventError
+
+	switch notification.Event {
+	case messages.Buy, messages.Sell:
+		event = discord.EventOrder
+	case messages.Profit, messages.Summary:
+		event = discord.EventProfit
+	}
+
+	discord.Message{
+		Event: event,
+		Text:  text,
+	}.Send(configData, sessionData)
+
+}
+
+/* Send filled orders to Slack with Block Kit formatting and other notifications as plain text */
+func sendSlack(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification,
+	text string) {
+
+	data := notification.Data
+
+	switch notification.Event {
+	case messages.Buy, messages.Sell:
+
+		slack.Trade{
+			Side:            data.Side,
+			Symbol:          data.Symbol,
+			Price:           data.Price,
+			Quantity:        data.Quantity,
+			ThreadID:        data.ThreadID,
+			ThreadProfit:    data.Profit,
+			ThreadProfitPct: data.ProfitPct,
+			Fiat:            data.Fiat,
+			Text:            text,
+		}.Send(configData, sessionData)
+
+	default:
+
+		slack.Message{
+			Text:     text,
+			Critical: notification.Severity == Critical,
+		}.Send(configData, sessionData)
+
+	}
+
+}
+
+/* Send to the Matrix room as plain text */
+func sendMatrix(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification,
+	text string) {
+
+	matrix.Message{
+		Text:     text,
+		Critical: notification.Severity == Critical,
+	}.Send(configData, sessionData)
+
+}
+
+/* Send an email with the notification title as subject */
+func sendEmail(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification,
+	text string) {
+
+	email.Send(configData, sessionData, notification.Key, notification.title(), text)
+
+}
+
+/* Push with high priority from warning severity */
+func sendPush(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification,
+	text string) {
+
+	push.Send(configData, sessionData, notification.Key, notification.title(), text, notification.Severity != Info)
+
+}
+
+/* Send critical notifications by SMS, the channel is reserved for critical alerts whatever the routes */
+func sendSMS(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification Notification,
+	text string) {
+
+	if notification.Severity != Critical {
+
+		return
+
+	}
+
+	key := notification.Key
+	if key == "" {
+		key = notification.title()
+	}
+
+	sms.Send(configData, sessionData, key, text)
+
+}
+
+/* Return the title of the notification, the event and symbol when empty */
+func (notification Notification) title() string {
+
+	if notification.Title != "" {
+
+		return notification.Title
+
+	}
+
+	return strings.ToUpper(notification.Event[:1]) + notification.Event[1:] + " " + notification.Data.Symbol
+
+}
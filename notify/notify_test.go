@@ -0,0 +1,176 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParseRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []Route
+		wantErr bool
+	}{
+		{
+			name: "routes",
+			text: "# fills to Discord\nsell discord\n\nERROR Email critical\n* * info c683ok5mk1u1120gnmmg\n",
+			want: []Route{
+				{Event: "sell", Channel: "discord", Severity: Info},
+				{Event: "error", Channel: "email", Severity: Critical},
+				{Event: "*", Channel: "*", Severity: Info, ThreadID: "c683ok5mk1u1120gnmmg"},
+			},
+		},
+		{
+			name:    "invalid lines skipped",
+			text:    "sell discord\nfill discord\nerror pager\nerror telegram urgent\nerror\nerror email info c683ok5mk1u1120gnmmg extra",
+			want:    []Route{{Event: "sell", Channel: "discord", Severity: Info}},
+			wantErr: true,
+		},
+		{name: "empty", text: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRoutes(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRoutes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRoutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouted(t *testing.T) {
+	routes := []Route{
+		{Event: "sell", Channel: "discord", Severity: Info},
+		{Event: "error", Channel: "email", Severity: Critical},
+		{Event: "*", Channel: "*", Severity: Info, ThreadID: "c683ok5mk1u1120gnmmg"},
+	}
+	tests := []struct {
+		name     string
+		event    string
+		channel  string
+		severity string
+		threadID string
+		want     bool
+	}{
+		{name: "event and channel", event: "sell", channel: "discord", severity: Info, threadID: "c683ok5mk1u1120gnmn0", want: true},
+		{name: "other channel", event: "sell", channel: "slack", severity: Info, threadID: "c683ok5mk1u1120gnmn0", want: false},
+		{name: "severity reached", event: "error", channel: "email", severity: Critical, threadID: "c683ok5mk1u1120gnmn0", want: true},
+		{name: "severity below minimum", event: "error", channel: "email", severity: Warning, threadID: "c683ok5mk1u1120gnmn0", want: false},
+		{name: "thread routes", event: "buy", channel: "push", severity: Info, threadID: "c683ok5mk1u1120gnmmg", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Routed(routes, tt.event, tt.channel, tt.severity, tt.threadID); got != tt.want {
+				t.Errorf("Routed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotification_Send(t *testing.T) {
+	got := make(map[string]string)
+
+	saved := senders
+	senders = make(map[string]Sender)
+	defer func() { senders = saved }()
+
+	for _, channel := range Channels {
+		channel := channel
+		Register(channel, func(configData *types.Config, sessionData *types.Session, notification Notification, text string) {
+			got[channel] = notification.title() + ": " + text
+		})
+	}
+
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg"}
+	notification := Notification{
+		Event:    messages.Error,
+		Severity: Critical,
+		Data:     messages.Data{Symbol: "BTCUSDT", Message: "SELL order failed"},
+	}
+
+	/* Default routes send errors to Telegram, Discord, Matrix, email and push, and critical errors by SMS */
+	notification.Send(&types.Config{ConfigGlobal: &types.ConfigGlobal{}}, sessionData)
+	want := map[string]string{
+		messages.Telegram: "Error BTCUSDT: SELL order failed",
+		messages.Discord:  "Error BTCUSDT: SELL order failed",
+		messages.Matrix:   "Error BTCUSDT: SELL order failed",
+		messages.Email:    "Error BTCUSDT: Thread c683ok5mk1u1120gnmmg BTCUSDT: SELL order failed",
+		messages.Push:     "Error BTCUSDT: SELL order failed",
+		messages.SMS:      "Error BTCUSDT: SELL order failed",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Send() default routes = %v, want %v", got, want)
+	}
+
+	got = make(map[string]string)
+	notification.Severity = Warning
+	notification.Send(&types.Config{ConfigGlobal: &types.ConfigGlobal{NotifyRoutes: "error email critical\nerror slack warning"}}, sessionData)
+	if len(got) != 1 || got[messages.Slack] == "" {
+		t.Errorf("Send() routes = %v, want slack", got)
+	}
+
+	/* SendTo ignores the routes */
+	got = make(map[string]string)
+	notification.SendTo(&types.Config{ConfigGlobal: &types.ConfigGlobal{NotifyRoutes: "error email critical"}}, sessionData, []string{messages.Telegram, messages.Slack})
+	if len(got) != 2 || got[messages.Telegram] == "" || got[messages.Slack] == "" {
+		t.Errorf("SendTo() = %v, want telegram and slack", got)
+	}
+}
+
+func TestNewNotification(t *testing.T) {
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", SymbolFiat: "USDT"}
+	tests := []struct {
+		name   string
+		event  events.Event
+		want   Notification
+		wantOk bool
+	}{
+		{
+			name:  "filled",
+			event: events.Event{Topic: events.OrderFilled, Session: sessionData, Data: events.Order{OrderID: 42, Side: "SELL", Symbol: "BTCUSDT", Price: 100, Quantity: 0.5, Profit: 12.5, ProfitPct: 1.25}},
+			want: Notification{Event: messages.Sell, Severity: Info, Data: messages.Data{
+				ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Fiat: "USDT", Side: "SELL", Price: 100, Quantity: 0.5, Profit: 12.5, ProfitPct: 1.25}},
+			wantOk: true,
+		},
+		{
+			name:   "placed",
+			event:  events.Event{Topic: events.OrderPlaced, Session: sessionData, Data: events.Order{OrderID: 42, Side: "BUY"}},
+			wantOk: false,
+		},
+		{
+			name:  "stoploss",
+			event: events.Event{Topic: events.StoplossTriggered, Session: sessionData, Data: events.Stoploss{Order: types.Order{OrderID: 42, Price: 120, ExecutedQuantity: 0.5}, Symbol: "BTCUSDT", Price: 100, Reason: "stoploss", Title: "Stoploss hit BTCUSDT"}},
+			want: Notification{Event: messages.Stoploss, Severity: Warning, Key: "stoploss-c683ok5mk1u1120gnmmg", Title: "Stoploss hit BTCUSDT", Data: messages.Data{
+				ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Fiat: "USDT", Side: "SELL", OrderID: 42, Price: 100, OrderPrice: 120, Quantity: 0.5, Reason: "stoploss"}},
+			wantOk: true,
+		},
+		{
+			name:  "error",
+			event: events.Event{Topic: events.ErrorRaised, Session: sessionData, Data: events.Error{Symbol: "BTCUSDT", Side: "BUY", Message: "BUY order failed: timeout", Key: "exchange-c683ok5mk1u1120gnmmg-BUY", Title: "Exchange error BTCUSDT"}},
+			want: Notification{Event: messages.Error, Severity: Critical, Key: "exchange-c683ok5mk1u1120gnmmg-BUY", Title: "Exchange error BTCUSDT", Data: messages.Data{
+				ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Side: "BUY", Message: "BUY order failed: timeout"}},
+			wantOk: true,
+		},
+		{
+			name:   "session",
+			event:  events.Event{Topic: events.SessionStarted, Session: sessionData, Data: events.Session{Symbol: "BTCUSDT"}},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NewNotification(tt.event)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewNotification() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package outbox
+
+/* This package implements the durable notification delivery queue. A notification a channel fails to deliver, i.e.
+Telegram or Discord unreachable, is saved to the notificationqueue table instead of being lost. The Master Node
+retries the queued notifications of all threads when due, with exponential backoff from 30 seconds doubling up to 1
+hour. After 10 failed attempts, or when it can't be saved, the notification is dead-lettered: logged with its channel,
+text and last error, and removed from the queue. */
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	attemptsMax = 10               /* Delivery attempts before the notification is dead-lettered */
+	retryBase   = 30 * time.Second /* Wait before the first retry, doubled after each failed retry */
+	retryMax    = time.Hour        /* Maximum wait between retries */
+	batchMax    = 50               /* Queued notifications retried per run */
+)
+
+// ErrNoDeliverer is returned when no deliverer is registered for the channel of a queued notification
+var ErrNoDeliverer = errors.New("No deliverer registered for channel")
+
+// Deliverer delivers the text of a queued notification to a channel, returning the delivery error
+type Deliverer func(
+	configData *types.Config,
+	sessionData *types.Session,
+	text string) error
+
+var deliverers = struct {
+	sync.Mutex
+	channel map[string]Deliverer
+}{channel: make(map[string]Deliverer)}
+
+// Register the deliverer retrying the queued notifications of a channel
+func Register(
+	channel string,
+	deliverer Deliverer) {
+
+	deliverers.Lock()
+	defer deliverers.Unlock()
+
+	deliverers.channel[channel] = deliverer
+
+}
+
+// Enqueue save a notification channel failed to deliver with err for retry, it is dead-lettered when it can't be saved
+func Enqueue(
+	sessionData *types.Session,
+	channel string,
+	text string,
+	err error) {
+
+	now := time.Now()
+
+	notification := types.QueuedNotification{
+		ThreadID:    sessionData.ThreadID,
+		Channel:     channel,
+		Text:        text,
+		Attempts:    1,
+		NextAttempt: milliseconds(now.Add(delay(1))),
+		LastError:   err.Error(),
+		CreatedTime: milliseconds(now),
+	}
+
+	if sessionData.Db == nil {
+
+		deadLetter(sessionData, notification, "database not connected")
+		return
+
+	}
+
+	if saveErr := mysql.SaveQueuedNotification(sessionData, notification); saveErr != nil {
+
+		deadLetter(sessionData, notification, saveErr.Error())
+
+	}
+
+}
+
+// Run retry the queued notifications of all threads due now, only the Master Node retries notifications
+func Run(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !sessionData.MasterNode || sessionData.Db == nil || configData.ConfigGlobal == nil {
+
+		return
+
+	}
+
+	now := time.Now()
+
+	notifications, err := mysql.GetQueuedNotifications(sessionData, milliseconds(now), batchMax)
+	if err != nil { /* Errors are logged by mysql */
+
+		return
+
+	}
+
+	for _, notification := range notifications {
+
+		deliverers.Lock()
+		deliverer, ok := deliverers.channel[notification.Channel]
+		deliverers.Unlock()
+
+		err = ErrNoDeliverer
+		if ok {
+			err = deliverer(configData, sessionData, notification.Text)
+		}
+
+		if err == nil {
+
+			_ = mysql.DeleteQueuedNotification(sessionData, notification.ID)
+			continue
+
+		}
+
+		notification.Attempts++
+		notification.LastError = err.Error()
+
+		if notification.Attempts >= attemptsMax {
+
+			deadLetter(sessionData, notification, "")
+			_ = mysql.DeleteQueuedNotification(sessionData, notification.ID)
+			continue
+
+		}
+
+		notification.NextAttempt = milliseconds(now.Add(delay(notification.Attempts)))
+		_ = mysql.UpdateQueuedNotification(sessionData, notification)
+
+	}
+
+}
+
+/* Return the wait before the next attempt after attempts failed attempts */
+func delay(attempts int) time.Duration {
+
+	wait := retryBase
+
+	for i := 1; i < attempts && wait < retryMax; i++ {
+
+		wait *= 2
+
+	}
+
+	if wait > retryMax {
+
+		return retryMax
+
+	}
+
+	return wait
+
+}
+
+/* Log a notification that will never be delivered, with reason when it was not queued */
+func deadLetter(
+	sessionData *types.Session,
+	notification types.QueuedNotification,
+	reason string) {
+
+	message := fmt.Sprintf("dead letter %s notification of thread %s after %d attempts: %s", notification.Channel, notification.ThreadID, notification.Attempts, notification.LastError)
+	if reason != "" {
+		message += " (not queued: " + reason + ")"
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   nil,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  functions.GetFunctionName() + " - " + message + " - " + notification.Text,
+		LogLevel: "DebugLevel",
+	}.Do()
+
+}
+
+/* Return t in milliseconds */
+func milliseconds(t time.Time) int64 {
+
+	return t.UnixNano() / int64(time.Millisecond)
+
+}
@@ -0,0 +1,83 @@
+package outbox
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_delay(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first retry", attempts: 1, want: 30 * time.Second},
+		{name: "second retry", attempts: 2, want: time.Minute},
+		{name: "fifth retry", attempts: 5, want: 8 * time.Minute},
+		{name: "capped", attempts: 8, want: time.Hour},
+		{name: "last retry", attempts: attemptsMax - 1, want: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := delay(tt.attempts); got != tt.want {
+				t.Errorf("delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	var delivered []string
+	Register("test", func(configData *types.Config, sessionData *types.Session, text string) error {
+		if text == "unreachable" {
+			return errors.New("dial tcp: i/o timeout")
+		}
+		delivered = append(delivered, text)
+		return nil
+	})
+
+	columns := []string{"ID", "ThreadID", "Channel", "Text", "Attempts", "NextAttempt", "LastError", "CreatedTime"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.GetQueuedNotifications(?,?)")).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "c683ok5mk1u1120gnmmg", "test", "delivered", 1, 1638230430000, "timeout", 1638230400000).
+			AddRow(2, "c683ok5mk1u1120gnmmg", "test", "unreachable", 1, 1638230430000, "timeout", 1638230400000).
+			AddRow(3, "c683ok5mk1u1120gnmmg", "test", "unreachable", attemptsMax-1, 1638230430000, "timeout", 1638230400000))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.DeleteQueuedNotification(?)")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.UpdateQueuedNotification(?,?,?,?)")).
+		WithArgs(2, 2, sqlmock.AnyArg(), "dial tcp: i/o timeout").
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("call cryptopump.DeleteQueuedNotification(?)")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{""}))
+
+	Run(&types.Config{ConfigGlobal: &types.ConfigGlobal{}}, &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", MasterNode: true, Db: db})
+
+	if len(delivered) != 1 || delivered[0] != "delivered" {
+		t.Errorf("Run() delivered = %v, want [delivered]", delivered)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Run() %v", err)
+	}
+
+	Run(&types.Config{ConfigGlobal: &types.ConfigGlobal{}}, &types.Session{MasterNode: false, Db: db}) /* Not the Master Node */
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Run() %v", err)
+	}
+}
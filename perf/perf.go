@@ -0,0 +1,252 @@
+package perf
+
+/* This package implements the cycle performance metrics of each thread running in this process. The threads loop
+(the book ticker websocket handler) records every tick processed, the latency of the buy and sell decision
+algorithms of each tick, and the time from a trade signal (a decision to buy or sell) to the acknowledgment of the
+order by the exchange. Latencies are kept as rolling aggregates of the latest samplesMax samples and ticks as the
+count of each second of the last tickWindow seconds, and displayed on the thread detail page. */
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+)
+
+const (
+	samplesMax = 1000 /* Latest latency samples of each rolling aggregate */
+	tickWindow = 60   /* Seconds of the ticks per second aggregate */
+)
+
+// Aggregate struct define the rolling aggregate of the latest latency samples in milliseconds
+type Aggregate struct {
+	Count int /* Samples in the aggregate */
+	Mean  float64
+	P50   float64
+	P95   float64
+	Max   float64
+}
+
+// Stats struct define the cycle performance metrics of the thread
+type Stats struct {
+	Decision       Aggregate /* Buy and sell decision algorithms latency of each tick */
+	SignalToAck    Aggregate /* Trade signal to exchange order acknowledgment latency */
+	TicksPerSecond float64   /* Mean ticks processed per second over the last tickWindow seconds */
+	TicksPeak      int       /* Most ticks processed in one second over the last tickWindow seconds */
+}
+
+/* Ring buffer of the latest samples */
+type samples struct {
+	values []float64
+	next   int
+}
+
+/* Metrics of a thread */
+type recorder struct {
+	decision    samples
+	signalToAck samples
+	ticks       [tickWindow]int   /* Ticks of each second, indexed by unix second modulo tickWindow */
+	tickSeconds [tickWindow]int64 /* Unix second of each ticks count */
+	signal      time.Time         /* Trade signal awaiting the order acknowledgment, zero when none */
+}
+
+var recorders = struct {
+	sync.Mutex
+	threads map[string]*recorder /* Metrics of each ThreadID */
+}{
+	threads: make(map[string]*recorder),
+}
+
+// Tick record a tick processed by the loop of threadID at now. The orders of a trade signal are created while its
+// tick is processed, so a signal not acknowledged by then is dropped.
+func Tick(
+	threadID string,
+	now time.Time) {
+
+	recorders.Lock()
+	defer recorders.Unlock()
+
+	recorder := get(threadID)
+	recorder.signal = time.Time{}
+
+	second := now.Unix()
+	i := second % tickWindow
+
+	if recorder.tickSeconds[i] != second {
+
+		recorder.tickSeconds[i] = second
+		recorder.ticks[i] = 0
+
+	}
+
+	recorder.ticks[i]++
+
+}
+
+// Decision record the latency of the decision algorithms of a tick of threadID
+func Decision(
+	threadID string,
+	latency time.Duration) {
+
+	recorders.Lock()
+	defer recorders.Unlock()
+
+	get(threadID).decision.add(milliseconds(latency))
+
+}
+
+// Signal record a trade signal of threadID at now, measured until the order is acknowledged by the exchange
+func Signal(
+	threadID string,
+	now time.Time) {
+
+	recorders.Lock()
+	defer recorders.Unlock()
+
+	get(threadID).signal = now
+
+}
+
+// Acknowledge record the signal to acknowledgment latency when the order of the pending trade signal of threadID
+// was created at now without error, and clear the signal
+func Acknowledge(
+	threadID string,
+	now time.Time,
+	err error) {
+
+	recorders.Lock()
+	defer recorders.Unlock()
+
+	recorder := get(threadID)
+
+	if !recorder.signal.IsZero() && err == nil {
+
+		recorder.signalToAck.add(milliseconds(now.Sub(recorder.signal)))
+
+	}
+
+	recorder.signal = time.Time{}
+
+}
+
+// Read return the cycle performance metrics of threadID at now
+func Read(
+	threadID string,
+	now time.Time) (stats Stats) {
+
+	recorders.Lock()
+	defer recorders.Unlock()
+
+	recorder := get(threadID)
+
+	stats.Decision = recorder.decision.aggregate()
+	stats.SignalToAck = recorder.signalToAck.aggregate()
+
+	total := 0
+	for i, second := range recorder.tickSeconds {
+
+		if age := now.Unix() - second; age >= 1 && age <= tickWindow { /* Complete seconds of the window */
+
+			total += recorder.ticks[i]
+
+			if recorder.ticks[i] > stats.TicksPeak {
+				stats.TicksPeak = recorder.ticks[i]
+			}
+
+		}
+
+	}
+
+	stats.TicksPerSecond = math.Round(float64(total)/tickWindow*100) / 100
+
+	return stats
+
+}
+
+// Reset clear the metrics of threadID for a new thread
+func Reset(threadID string) {
+
+	recorders.Lock()
+	defer recorders.Unlock()
+
+	delete(recorders.threads, threadID)
+
+}
+
+/* Return the metrics of threadID, created when missing. Called with recorders locked. */
+func get(threadID string) *recorder {
+
+	r, ok := recorders.threads[threadID]
+	if !ok {
+		r = &recorder{}
+		recorders.threads[threadID] = r
+	}
+
+	return r
+
+}
+
+/* Add a sample, replacing the oldest when full */
+func (s *samples) add(value float64) {
+
+	if len(s.values) < samplesMax {
+
+		s.values = append(s.values, value)
+		return
+
+	}
+
+	s.values[s.next] = value
+	s.next = (s.next + 1) % samplesMax
+
+}
+
+/* Return the aggregate of the samples */
+func (s *samples) aggregate() (aggregate Aggregate) {
+
+	if len(s.values) == 0 {
+
+		return aggregate
+
+	}
+
+	sorted := append([]float64(nil), s.values...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+
+	aggregate.Count = len(sorted)
+	aggregate.Mean = functions.Round(sum/float64(len(sorted)), 3)
+	aggregate.P50 = functions.Round(percentile(sorted, 0.5), 3)
+	aggregate.P95 = functions.Round(percentile(sorted, 0.95), 3)
+	aggregate.Max = functions.Round(sorted[len(sorted)-1], 3)
+
+	return aggregate
+
+}
+
+/* Return the nearest-rank percentile p of sorted */
+func percentile(
+	sorted []float64,
+	p float64) float64 {
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return sorted[rank]
+
+}
+
+/* Return duration in milliseconds */
+func milliseconds(duration time.Duration) float64 {
+
+	return float64(duration) / float64(time.Millisecond)
+
+}
@@ -0,0 +1,87 @@
+package perf
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRead(t *testing.T) {
+
+	threadID := "c683ok5mk1u1120gnmmg"
+	Reset(threadID)
+
+	now := time.Unix(1638230400, 0)
+
+	for i := 0; i < 10; i++ { /* 10 ticks per second during 3 seconds */
+
+		Tick(threadID, now.Add(time.Duration(i)*time.Second/10))
+		Tick(threadID, now.Add(time.Second+time.Duration(i)*time.Second/10))
+		Tick(threadID, now.Add(2*time.Second+time.Duration(i)*time.Second/10))
+
+	}
+
+	Tick(threadID, now.Add(3*time.Second)) /* Current second, not complete */
+
+	for i := 1; i <= 100; i++ {
+		Decision(threadID, time.Duration(i)*time.Millisecond)
+	}
+
+	Signal(threadID, now)
+	Acknowledge(threadID, now.Add(250*time.Millisecond), nil)
+
+	Signal(threadID, now)
+	Acknowledge(threadID, now.Add(time.Second), errors.New("order rejected")) /* Failed orders are not acknowledged */
+
+	Acknowledge(threadID, now.Add(time.Second), nil) /* No pending signal */
+
+	Signal(threadID, now)
+	Tick(threadID, now.Add(3*time.Second)) /* Signal without order dropped by the next tick */
+	Acknowledge(threadID, now.Add(5*time.Second), nil)
+
+	got := Read(threadID, now.Add(3*time.Second+time.Second/2))
+
+	if got.Decision != (Aggregate{Count: 100, Mean: 50.5, P50: 50, P95: 95, Max: 100}) {
+		t.Errorf("Read() Decision = %v", got.Decision)
+	}
+
+	if got.SignalToAck != (Aggregate{Count: 1, Mean: 250, P50: 250, P95: 250, Max: 250}) {
+		t.Errorf("Read() SignalToAck = %v", got.SignalToAck)
+	}
+
+	if got.TicksPerSecond != 0.5 || got.TicksPeak != 10 { /* 30 ticks in the 60 seconds window */
+		t.Errorf("Read() TicksPerSecond = %v, TicksPeak = %v", got.TicksPerSecond, got.TicksPeak)
+	}
+
+	if got := Read(threadID, now.Add(2*time.Minute)); got.TicksPerSecond != 0 || got.TicksPeak != 0 {
+		t.Errorf("Read() after the window = %v", got)
+	}
+
+	Tick("c683ok5mk1u1120gnmn0", now.Add(3*time.Second)) /* Tick of another thread of the process */
+	Signal(threadID, now)
+	Tick("c683ok5mk1u1120gnmn0", now.Add(3*time.Second))
+	Acknowledge(threadID, now.Add(100*time.Millisecond), nil)
+
+	if got := Read(threadID, now.Add(3*time.Second)); got.SignalToAck.Count != 2 {
+		t.Errorf("Read() SignalToAck with another thread = %v", got.SignalToAck)
+	}
+
+	if got := Read("c683ok5mk1u1120gnmn0", now.Add(3*time.Second)); got.Decision.Count != 0 || got.SignalToAck.Count != 0 {
+		t.Errorf("Read() of another thread = %v", got)
+	}
+
+}
+
+func Test_samples_add(t *testing.T) {
+
+	s := samples{}
+
+	for i := 0; i < samplesMax+10; i++ {
+		s.add(float64(i))
+	}
+
+	if got := s.aggregate(); got.Count != samplesMax || got.Max != samplesMax+9 || got.P50 != 509 {
+		t.Errorf("aggregate() = %v", got)
+	}
+
+}
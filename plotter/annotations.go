@@ -0,0 +1,253 @@
+package plotter
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Line styles of lightweight-charts price lines */
+const (
+	lineSolid  = 0
+	lineDotted = 1
+	lineDashed = 2
+)
+
+// Annotations struct define the thread chart feed consumed by an embedded TradingView lightweight-charts widget:
+// the candles, the executed orders as series markers and the pending levels as price lines. Times are in seconds
+// as the widget expects.
+type Annotations struct {
+	ThreadID string             `json:"threadId"`
+	Symbol   string             `json:"symbol"`
+	Candles  []AnnotationCandle `json:"candles"`
+	Markers  []AnnotationMarker `json:"markers"`
+	Lines    []AnnotationLine   `json:"priceLines"`
+}
+
+// AnnotationCandle struct define a candlestick series bar
+type AnnotationCandle struct {
+	Time  int64   `json:"time"` /* Kline open time */
+	Open  float64 `json:"open"`
+	High  float64 `json:"high"`
+	Low   float64 `json:"low"`
+	Close float64 `json:"close"`
+}
+
+// AnnotationMarker struct define a series marker for an executed order
+type AnnotationMarker struct {
+	ID       string  `json:"id"` /* OrderID */
+	Time     int64   `json:"time"`
+	Position string  `json:"position"`
+	Color    string  `json:"color"`
+	Shape    string  `json:"shape"`
+	Text     string  `json:"text"`
+	Price    float64 `json:"price"`
+}
+
+// AnnotationLine struct define a price line for a pending level
+type AnnotationLine struct {
+	Price            float64 `json:"price"`
+	Color            string  `json:"color"`
+	LineWidth        int     `json:"lineWidth"`
+	LineStyle        int     `json:"lineStyle"`
+	AxisLabelVisible bool    `json:"axisLabelVisible"`
+	Title            string  `json:"title"`
+}
+
+// LoadAnnotations load the thread chart feed with the klines of the thread chart, the orders filled within the
+// chart period and the entry and target of the open transactions, stoploss, next DCA and stop price levels
+func LoadAnnotations(
+	configData *types.Config,
+	sessionData *types.Session) (feed Annotations, err error) {
+
+	var orders []types.Order
+
+	feed = Annotations{
+		ThreadID: sessionData.ThreadID,
+		Symbol:   sessionData.Symbol,
+		Candles:  []AnnotationCandle{},
+		Markers:  []AnnotationMarker{},
+		Lines:    []AnnotationLine{},
+	}
+
+	klineData := sessionData.KlineData
+
+	if sessionData.ThreadID == "" { /* No thread running in this session */
+
+		feed.Candles = annotationCandles(klineData)
+		return feed, nil
+
+	}
+
+	/* Persisted klines for the thread, falling back to the in-memory klines as Plot */
+	if persisted, err := mysql.GetKline(sessionData); err == nil && len(persisted) > 0 {
+
+		klineData = persisted
+
+	}
+
+	feed.Candles = annotationCandles(klineData)
+
+	if len(klineData) > 0 {
+
+		if orders, err = mysql.GetThreadOrdersSince(sessionData, klineData[0].Date-60000); err != nil {
+
+			return feed, err
+
+		}
+
+		feed.Markers = annotationMarkers(orders)
+
+	}
+
+	if orders, err = mysql.GetThreadTransactionByThreadID(sessionData); err != nil {
+
+		return feed, err
+
+	}
+
+	for _, order := range orders {
+
+		target := math.Max(order.Price*(1+configData.ProfitMin), exchange.MinimumSellPrice(configData, sessionData, order.Price)) /* As the thread detail page target */
+		feed.Lines = append(feed.Lines, entryLines(order, target)...)
+
+	}
+
+	if level := stoplossLevel(orders, stoplossRatio(configData, sessionData)); level > 0 {
+
+		feed.Lines = append(feed.Lines, priceLine(level, "#dc3545", lineDashed, "Stoploss"))
+
+	}
+
+	/* Next DCA level as Plot */
+	if price, err := mysql.GetLastOrderTransactionPrice(sessionData, "BUY"); err == nil {
+
+		thresholdDown := configData.BuyRepeatThresholdDown
+		if side1, side2, err := mysql.GetOrderTransactionSideLastTwo(sessionData); err == nil &&
+			side1 == "BUY" && side2 == "BUY" {
+
+			thresholdDown = configData.BuyRepeatThresholdDownSecond
+
+		}
+
+		if level := dcaLevel(price, thresholdDown); level > 0 {
+
+			feed.Lines = append(feed.Lines, priceLine(level, "#fd7e14", lineDashed, "Next DCA"))
+
+		}
+
+	}
+
+	if sessionData.StopPrice > 0 {
+
+		feed.Lines = append(feed.Lines, priceLine(sessionData.StopPrice, "#dc3545", lineSolid, "Stop price"))
+
+	}
+
+	return feed, nil
+
+}
+
+/* Return the candles of klineData at the open time of each kline, klineData dates are the kline close times */
+func annotationCandles(klineData []types.KlineData) (candles []AnnotationCandle) {
+
+	candles = []AnnotationCandle{}
+
+	for _, kline := range klineData {
+
+		candles = append(candles, AnnotationCandle{
+			Time:  minute(kline.Date),
+			Open:  kline.Data[0],
+			Close: kline.Data[1],
+			Low:   kline.Data[2],
+			High:  kline.Data[3],
+		})
+
+	}
+
+	return candles
+
+}
+
+/* Return a BUY marker below and a SELL marker above the candle of each order, sorted by time as the widget requires */
+func annotationMarkers(orders []types.Order) (markers []AnnotationMarker) {
+
+	markers = []AnnotationMarker{}
+
+	for _, order := range orders {
+
+		if order.Price <= 0 || order.Side == "" {
+			continue
+		}
+
+		marker := AnnotationMarker{
+			ID:       strconv.FormatInt(order.OrderID, 10),
+			Time:     minute(order.TransactTime),
+			Position: "belowBar",
+			Color:    "#28a745",
+			Shape:    "arrowUp",
+			Text:     "B " + strconv.FormatFloat(order.Price, 'f', -1, 64),
+			Price:    order.Price,
+		}
+
+		if order.Side == "SELL" {
+
+			marker.Position = "aboveBar"
+			marker.Color = "#dc3545"
+			marker.Shape = "arrowDown"
+			marker.Text = "S " + strconv.FormatFloat(order.Price, 'f', -1, 64)
+
+		}
+
+		markers = append(markers, marker)
+
+	}
+
+	sort.SliceStable(markers, func(i, j int) bool { return markers[i].Time < markers[j].Time })
+
+	return markers
+
+}
+
+/* Return the entry and target price lines of an open transaction */
+func entryLines(
+	order types.Order,
+	target float64) []AnnotationLine {
+
+	id := strconv.FormatInt(order.OrderID, 10)
+
+	return []AnnotationLine{
+		priceLine(order.Price, "#007bff", lineDotted, "Entry "+id),
+		priceLine(math.Round(target*1000)/1000, "#28a745", lineDashed, "Target "+id),
+	}
+
+}
+
+/* Return a price line with the axis label visible */
+func priceLine(
+	price float64,
+	color string,
+	style int,
+	title string) AnnotationLine {
+
+	return AnnotationLine{
+		Price:            price,
+		Color:            color,
+		LineWidth:        1,
+		LineStyle:        style,
+		AxisLabelVisible: true,
+		Title:            title,
+	}
+
+}
+
+/* Return the start of the minute of a time in milliseconds, in seconds */
+func minute(t int64) int64 {
+
+	return t / 60000 * 60
+
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"html/template"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
 	"github.com/aleibovici/cryptopump/types"
 
 	"github.com/go-echarts/go-echarts/v2/charts"
@@ -45,10 +47,18 @@ func (d Data) LoadKline(
 
 	sessionData.KlineData = append(sessionData.KlineData, kd...)
 
+	if sessionData.ThreadID != "" {
+
+		_ = mysql.SaveKline(sessionData, d.Kline.StartTime, kd[0]) /* Persist kline for the thread chart */
+
+	}
+
 }
 
-// Plot is responsible for rending e-chart
-func (d Data) Plot(sessionData *types.Session) (htmlSnippet template.HTML) {
+// Plot is responsible for rending e-chart with the thread buy and sell markers, stoploss level and next DCA level
+func (d Data) Plot(
+	configData *types.Config,
+	sessionData *types.Session) (htmlSnippet template.HTML) {
 
 	x := make([]string, 0)
 	y := make([]opts.KlineData, 0)
@@ -56,21 +66,407 @@ func (d Data) Plot(sessionData *types.Session) (htmlSnippet template.HTML) {
 	ma7 := make([]opts.LineData, 0)  /* Simple Moving Average for 7 periods */
 	ma14 := make([]opts.LineData, 0) /* Simple Moving Average for 14 periods */
 
-	for i := 0; i < len(sessionData.KlineData); i++ {
-		x = append(x, time.Unix((sessionData.KlineData[i].Date/1000), 0).UTC().Local().Format("15:04"))
-		y = append(y, opts.KlineData{Value: sessionData.KlineData[i].Data})
-		v = append(v, opts.BarData{Value: sessionData.KlineData[i].Volumes})
-		ma7 = append(ma7, opts.LineData{Value: sessionData.KlineData[i].Ma7})    /* Simple Moving Average for 7 periods */
-		ma14 = append(ma14, opts.LineData{Value: sessionData.KlineData[i].Ma14}) /* Simple Moving Average for 14 periods */
+	klineData := sessionData.KlineData
+
+	/* Load persisted klines for the thread, falling back to the in-memory klines when none are persisted */
+	if sessionData.ThreadID != "" {
+
+		if persisted, err := mysql.GetKline(sessionData); err == nil && len(persisted) > 0 {
+
+			klineData = persisted
+
+		}
+
+	}
+
+	for i := 0; i < len(klineData); i++ {
+		x = append(x, klineLabel(klineData[i].Date))
+		y = append(y, opts.KlineData{Value: klineData[i].Data})
+		v = append(v, opts.BarData{Value: klineData[i].Volumes})
+		ma7 = append(ma7, opts.LineData{Value: klineData[i].Ma7})    /* Simple Moving Average for 7 periods */
+		ma14 = append(ma14, opts.LineData{Value: klineData[i].Ma14}) /* Simple Moving Average for 14 periods */
+	}
+
+	kline := klineBase("KLINE", x, y) /* Create base kline chart */
+
+	if sessionData.ThreadID != "" && len(klineData) > 0 {
+
+		/* Buy and sell markers from the thread orders filled within the chart period */
+		if orders, err := mysql.GetThreadOrdersSince(sessionData, klineData[0].Date-60000); err == nil {
+
+			kline.SetSeriesOptions(charts.WithMarkPointNameCoordItemOpts(orderMarkers(x, orders)...))
+
+		}
+
+		/* Stoploss level from the highest priced open thread transaction */
+		if orders, err := mysql.GetThreadTransactionByThreadID(sessionData); err == nil {
+
+			if level := stoplossLevel(orders, stoplossRatio(configData, sessionData)); level > 0 {
+
+				kline.SetSeriesOptions(charts.WithMarkLineNameYAxisItemOpts(opts.MarkLineNameYAxisItem{
+					Name:  "Stoploss",
+					YAxis: level,
+				}))
+
+			}
+
+		}
+
+		/* Next DCA level from the last BUY order, using the 2nd threshold when the last two orders are BUY as the buy decision tree does */
+		if price, err := mysql.GetLastOrderTransactionPrice(sessionData, "BUY"); err == nil {
+
+			thresholdDown := configData.BuyRepeatThresholdDown
+			if side1, side2, err := mysql.GetOrderTransactionSideLastTwo(sessionData); err == nil &&
+				side1 == "BUY" && side2 == "BUY" {
+
+				thresholdDown = configData.BuyRepeatThresholdDownSecond
+
+			}
+
+			if level := dcaLevel(price, thresholdDown); level > 0 {
+
+				kline.SetSeriesOptions(charts.WithMarkLineNameYAxisItemOpts(opts.MarkLineNameYAxisItem{
+					Name:  "Next DCA",
+					YAxis: level,
+				}))
+
+			}
+
+		}
+
 	}
 
-	kline := klineBase("KLINE", x, y)                                                   /* Create base kline chart */
 	kline.Overlap(lineBase("MA7", x, ma7, "blue"), lineBase("MA14", x, ma14, "orange")) /* Create overlapping line charts */
 
 	return renderToHTML(kline)
 
 }
 
+/* Return the x axis label for a kline or order time in milliseconds */
+func klineLabel(date int64) string {
+
+	return time.Unix((date / 1000), 0).UTC().Local().Format("15:04")
+
+}
+
+/* Return a BUY or SELL mark point for each order within the x axis */
+func orderMarkers(
+	XAxis []string,
+	orders []types.Order) (markers []opts.MarkPointNameCoordItem) {
+
+	labels := make(map[string]bool, len(XAxis))
+	for _, label := range XAxis {
+		labels[label] = true
+	}
+
+	for _, order := range orders {
+
+		label := klineLabel(order.TransactTime)
+		if !labels[label] || order.Price <= 0 || order.Side == "" {
+			continue
+		}
+
+		markers = append(markers, opts.MarkPointNameCoordItem{
+			Name:       order.Side,
+			Coordinate: []interface{}{label, order.Price},
+			Label: &opts.Label{
+				Show:      true,
+				Formatter: order.Side[:1], /* B or S on the default pin symbol */
+			},
+		})
+
+	}
+
+	return markers
+
+}
+
+/* Stoploss ratio in effect for the sell decision tree, see algorithms stoplossRatio */
+func stoplossRatio(
+	configData *types.Config,
+	sessionData *types.Session) float64 {
+
+	if sessionData.VolatilityHalt && configData.SellVolatilityStoploss > 0 {
+
+		return configData.SellVolatilityStoploss
+
+	}
+
+	return configData.Stoploss
+
+}
+
+/* Return the price that triggers the stoploss sale of the highest priced order, 0 when disabled or no orders */
+func stoplossLevel(
+	orders []types.Order,
+	stoploss float64) float64 {
+
+	var high float64
+
+	if stoploss <= 0 {
+		return 0
+	}
+
+	for _, order := range orders {
+		if order.Price > high {
+			high = order.Price
+		}
+	}
+
+	return high * (1 - stoploss)
+
+}
+
+/* Return the price below the last BUY order that triggers the next DCA buy, 0 when disabled or no order */
+func dcaLevel(
+	price float64,
+	thresholdDown float64) float64 {
+
+	if thresholdDown <= 0 || price <= 0 {
+		return 0
+	}
+
+	return price * (1 - thresholdDown)
+
+}
+
+/* Equity curve time ranges */
+var equityRanges = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"all": 0,
+}
+
+// EquityRange return a valid equity curve time range, defaulting to 24h
+func EquityRange(equityRange string) string {
+
+	if _, ok := equityRanges[equityRange]; ok {
+
+		return equityRange
+
+	}
+
+	return "24h"
+
+}
+
+// PlotEquity is responsible for rending the equity curve and running drawdown e-chart across all threads
+func (d Data) PlotEquity(
+	sessionData *types.Session,
+	equityRange string) (htmlSnippet template.HTML) {
+
+	snapshots, err := mysql.GetEquitySince(sessionData, equitySince(equityRange, time.Now()))
+	if err != nil {
+
+		return ""
+
+	}
+
+	x := make([]string, 0)
+	equity := make([]opts.LineData, 0)
+	drawdown := make([]opts.LineData, 0)
+
+	for i, dd := range runningDrawdown(snapshots) {
+		x = append(x, time.Unix((snapshots[i].Time/1000), 0).UTC().Local().Format("01/02 15:04"))
+		equity = append(equity, opts.LineData{Value: math.Round(snapshots[i].Equity*100) / 100})
+		drawdown = append(drawdown, opts.LineData{Value: math.Round(dd*10000) / 100}) /* Drawdown as percentage */
+	}
+
+	line := lineBase("Equity", x, equity, "green")
+	line.SetGlobalOptions(
+		charts.WithYAxisOpts(opts.YAxis{
+			Type:  "value",
+			Scale: true,
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			PageTitle: "CryptoPump",
+			Width:     "1900px",
+			Height:    "300px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+		}),
+	)
+	line.ExtendYAxis(opts.YAxis{ /* Drawdown percentage */
+		Type: "value",
+		AxisLabel: &opts.AxisLabel{
+			Show:      true,
+			Formatter: "{value}%",
+		},
+	})
+	line.AddSeries("Drawdown", drawdown,
+		charts.WithLineChartOpts(opts.LineChart{
+			YAxisIndex: 1,
+		}),
+		charts.WithLineStyleOpts(opts.LineStyle{
+			Color:   "red",
+			Width:   1,
+			Opacity: 0.5,
+		}),
+		charts.WithItemStyleOpts(opts.ItemStyle{
+			Color:   "red",
+			Opacity: 0.5,
+		}),
+	)
+
+	return renderToHTML(line)
+
+}
+
+/* Return the start time in milliseconds of an equity curve time range, 0 for all */
+func equitySince(
+	equityRange string,
+	now time.Time) int64 {
+
+	duration := equityRanges[EquityRange(equityRange)]
+	if duration == 0 {
+
+		return 0
+
+	}
+
+	return now.Add(-duration).UnixNano() / int64(time.Millisecond)
+
+}
+
+/* Return the drawdown ratio of each snapshot from the running equity peak */
+func runningDrawdown(snapshots []types.EquitySnapshot) (drawdown []float64) {
+
+	var peak float64
+
+	for _, snapshot := range snapshots {
+
+		if snapshot.Equity > peak {
+			peak = snapshot.Equity
+		}
+
+		if peak > 0 {
+			drawdown = append(drawdown, math.Max(0, (peak-snapshot.Equity)/peak))
+		} else {
+			drawdown = append(drawdown, 0)
+		}
+
+	}
+
+	return drawdown
+
+}
+
+// PlotBacktest is responsible for rending the simulated equity of backtest runs against buying and holding the symbol, as return percentage over the period
+func PlotBacktest(
+	names []string,
+	equity [][]types.BacktestEquity) (htmlSnippet template.HTML) {
+
+	times := backtestTimes(equity)
+
+	x := make([]string, 0, len(times))
+	for _, t := range times {
+		x = append(x, time.Unix((t/1000), 0).UTC().Local().Format("01/02 15:04"))
+	}
+
+	line := charts.NewLine()
+	line.SetXAxis(x)
+	line.SetGlobalOptions(
+		charts.WithYAxisOpts(opts.YAxis{
+			Type:  "value",
+			Scale: true,
+			AxisLabel: &opts.AxisLabel{
+				Show:      true,
+				Formatter: "{value}%",
+			},
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			PageTitle: "CryptoPump",
+			Width:     "1900px",
+			Height:    "400px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: true,
+		}),
+	)
+
+	for i, name := range names {
+
+		strategy, buyHold := backtestReturns(equity[i], times)
+
+		line.AddSeries(name, strategy,
+			charts.WithLineStyleOpts(opts.LineStyle{
+				Width: 2,
+			}),
+		)
+		line.AddSeries(name+" buy & hold", buyHold,
+			charts.WithLineStyleOpts(opts.LineStyle{
+				Width:   1,
+				Type:    "dashed",
+				Opacity: 0.7,
+			}),
+		)
+
+	}
+
+	return renderToHTML(line)
+
+}
+
+/* Return the sorted times of all backtest equity points, the x axis of runs over different periods */
+func backtestTimes(equity [][]types.BacktestEquity) (times []int64) {
+
+	seen := make(map[int64]bool)
+
+	for _, points := range equity {
+		for _, point := range points {
+			if !seen[point.Time] {
+				seen[point.Time] = true
+				times = append(times, point.Time)
+			}
+		}
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	return times
+
+}
+
+/* Return the return percentage of the simulated equity and of buy-and-hold at each time, "-" (no value) outside the run */
+func backtestReturns(
+	equity []types.BacktestEquity,
+	times []int64) (strategy []opts.LineData, buyHold []opts.LineData) {
+
+	points := make(map[int64]types.BacktestEquity, len(equity))
+	for _, point := range equity {
+		points[point.Time] = point
+	}
+
+	for _, t := range times {
+
+		point, ok := points[t]
+		if !ok || equity[0].Equity == 0 || equity[0].BuyHold == 0 {
+
+			strategy = append(strategy, opts.LineData{Value: "-"})
+			buyHold = append(buyHold, opts.LineData{Value: "-"})
+			continue
+
+		}
+
+		strategy = append(strategy, opts.LineData{Value: math.Round((point.Equity/equity[0].Equity-1)*10000) / 100})
+		buyHold = append(buyHold, opts.LineData{Value: math.Round((point.BuyHold/equity[0].BuyHold-1)*10000) / 100})
+
+	}
+
+	return strategy, buyHold
+
+}
+
 func renderToHTML(c interface{}) template.HTML {
 
 	var buf bytes.Buffer
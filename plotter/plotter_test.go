@@ -2,7 +2,9 @@ package plotter
 
 import (
 	"html/template"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aleibovici/cryptopump/types"
 	"github.com/go-echarts/go-echarts/v2/charts"
@@ -76,6 +78,7 @@ func TestData_Plot(t *testing.T) {
 		Kline types.WsKline
 	}
 	type args struct {
+		configData  *types.Config
 		sessionData *types.Session
 	}
 	tests := []struct {
@@ -90,6 +93,7 @@ func TestData_Plot(t *testing.T) {
 				Kline: types.WsKline{},
 			},
 			args: args{
+				configData: &types.Config{},
 				sessionData: &types.Session{
 					KlineData: []types.KlineData{},
 				},
@@ -102,7 +106,7 @@ func TestData_Plot(t *testing.T) {
 			d := Data{
 				Kline: tt.fields.Kline,
 			}
-			if gotHTMLSnippet := d.Plot(tt.args.sessionData); gotHTMLSnippet != "" {
+			if gotHTMLSnippet := d.Plot(tt.args.configData, tt.args.sessionData); gotHTMLSnippet != "" {
 				return
 			}
 		})
@@ -159,3 +163,291 @@ func TestData_LoadKline(t *testing.T) {
 		})
 	}
 }
+
+func Test_orderMarkers(t *testing.T) {
+	buyTime := int64(1638317400000)
+	sellTime := int64(1638321000000)
+	type args struct {
+		XAxis  []string
+		orders []types.Order
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "buy and sell within chart",
+			args: args{
+				XAxis:  []string{klineLabel(buyTime), klineLabel(sellTime)},
+				orders: []types.Order{{Price: 48000.5, Side: "BUY", TransactTime: buyTime}, {Price: 48650.1, Side: "SELL", TransactTime: sellTime}},
+			},
+			want: 2,
+		},
+		{
+			name: "order outside chart",
+			args: args{
+				XAxis:  []string{klineLabel(buyTime)},
+				orders: []types.Order{{Price: 48650.1, Side: "SELL", TransactTime: sellTime}},
+			},
+			want: 0,
+		},
+		{
+			name: "no orders",
+			args: args{
+				XAxis:  []string{klineLabel(buyTime)},
+				orders: nil,
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := orderMarkers(tt.args.XAxis, tt.args.orders); len(got) != tt.want {
+				t.Errorf("orderMarkers() = %v, want %v markers", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_stoplossLevel(t *testing.T) {
+	type args struct {
+		orders   []types.Order
+		stoploss float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "highest order",
+			args: args{orders: []types.Order{{Price: 100}, {Price: 200}, {Price: 150}}, stoploss: 0.1},
+			want: 180,
+		},
+		{
+			name: "stoploss disabled",
+			args: args{orders: []types.Order{{Price: 200}}, stoploss: 0},
+			want: 0,
+		},
+		{
+			name: "no orders",
+			args: args{orders: nil, stoploss: 0.1},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stoplossLevel(tt.args.orders, tt.args.stoploss); got != tt.want {
+				t.Errorf("stoplossLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dcaLevel(t *testing.T) {
+	type args struct {
+		price         float64
+		thresholdDown float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "last order",
+			args: args{price: 200, thresholdDown: 0.25},
+			want: 150,
+		},
+		{
+			name: "no order",
+			args: args{price: 0, thresholdDown: 0.25},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dcaLevel(tt.args.price, tt.args.thresholdDown); got != tt.want {
+				t.Errorf("dcaLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEquityRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		equityRange string
+		want        string
+	}{
+		{
+			name:        "7 days",
+			equityRange: "7d",
+			want:        "7d",
+		},
+		{
+			name:        "all",
+			equityRange: "all",
+			want:        "all",
+		},
+		{
+			name:        "invalid range",
+			equityRange: "1y",
+			want:        "24h",
+		},
+		{
+			name:        "empty range",
+			equityRange: "",
+			want:        "24h",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EquityRange(tt.equityRange); got != tt.want {
+				t.Errorf("EquityRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_equitySince(t *testing.T) {
+	now := time.Date(2021, 12, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		equityRange string
+		want        int64
+	}{
+		{
+			name:        "24 hours",
+			equityRange: "24h",
+			want:        time.Date(2021, 12, 30, 12, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond),
+		},
+		{
+			name:        "30 days",
+			equityRange: "30d",
+			want:        time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond),
+		},
+		{
+			name:        "all",
+			equityRange: "all",
+			want:        0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := equitySince(tt.equityRange, now); got != tt.want {
+				t.Errorf("equitySince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runningDrawdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshots []types.EquitySnapshot
+		want      []float64
+	}{
+		{
+			name:      "drawdown and recovery",
+			snapshots: []types.EquitySnapshot{{Equity: 1000}, {Equity: 900}, {Equity: 1200}, {Equity: 600}},
+			want:      []float64{0, 0.1, 0, 0.5},
+		},
+		{
+			name:      "no snapshots",
+			snapshots: nil,
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runningDrawdown(tt.snapshots); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("runningDrawdown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_backtestReturns(t *testing.T) {
+	equity := [][]types.BacktestEquity{
+		{{Time: 1, Equity: 1000, BuyHold: 500}, {Time: 3, Equity: 1100, BuyHold: 450}},
+		{{Time: 2, Equity: 200, BuyHold: 100}, {Time: 3, Equity: 150, BuyHold: 125}},
+	}
+	times := backtestTimes(equity)
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(times, want) {
+		t.Fatalf("backtestTimes() = %v, want %v", times, want)
+	}
+	tests := []struct {
+		name         string
+		equity       []types.BacktestEquity
+		wantStrategy []opts.LineData
+		wantBuyHold  []opts.LineData
+	}{
+		{
+			name:         "first run",
+			equity:       equity[0],
+			wantStrategy: []opts.LineData{{Value: float64(0)}, {Value: "-"}, {Value: float64(10)}},
+			wantBuyHold:  []opts.LineData{{Value: float64(0)}, {Value: "-"}, {Value: float64(-10)}},
+		},
+		{
+			name:         "second run",
+			equity:       equity[1],
+			wantStrategy: []opts.LineData{{Value: "-"}, {Value: float64(0)}, {Value: float64(-25)}},
+			wantBuyHold:  []opts.LineData{{Value: "-"}, {Value: float64(0)}, {Value: float64(25)}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, buyHold := backtestReturns(tt.equity, times)
+			if !reflect.DeepEqual(strategy, tt.wantStrategy) {
+				t.Errorf("backtestReturns() strategy = %v, want %v", strategy, tt.wantStrategy)
+			}
+			if !reflect.DeepEqual(buyHold, tt.wantBuyHold) {
+				t.Errorf("backtestReturns() buyHold = %v, want %v", buyHold, tt.wantBuyHold)
+			}
+		})
+	}
+}
+
+func Test_annotationCandles(t *testing.T) {
+	klineData := []types.KlineData{
+		{Date: 1638316859999, Data: [4]float64{57000, 57100, 56950, 57150}},
+		{Date: 1638316919999, Data: [4]float64{57100, 57050, 57000, 57200}},
+	}
+	want := []AnnotationCandle{
+		{Time: 1638316800, Open: 57000, Close: 57100, Low: 56950, High: 57150},
+		{Time: 1638316860, Open: 57100, Close: 57050, Low: 57000, High: 57200},
+	}
+	if got := annotationCandles(klineData); !reflect.DeepEqual(got, want) {
+		t.Errorf("annotationCandles() = %v, want %v", got, want)
+	}
+	if got := annotationCandles(nil); got == nil || len(got) != 0 {
+		t.Errorf("annotationCandles() = %v, want empty", got)
+	}
+}
+
+func Test_annotationMarkers(t *testing.T) {
+	orders := []types.Order{
+		{OrderID: 2, Side: "SELL", Price: 57600.5, TransactTime: 1638317000000},
+		{OrderID: 1, Side: "BUY", Price: 57000, TransactTime: 1638316830000},
+		{OrderID: 3, Side: "BUY", Price: 0, TransactTime: 1638317100000},
+	}
+	want := []AnnotationMarker{
+		{ID: "1", Time: 1638316800, Position: "belowBar", Color: "#28a745", Shape: "arrowUp", Text: "B 57000", Price: 57000},
+		{ID: "2", Time: 1638316980, Position: "aboveBar", Color: "#dc3545", Shape: "arrowDown", Text: "S 57600.5", Price: 57600.5},
+	}
+	if got := annotationMarkers(orders); !reflect.DeepEqual(got, want) {
+		t.Errorf("annotationMarkers() = %v, want %v", got, want)
+	}
+}
+
+func Test_entryLines(t *testing.T) {
+	want := []AnnotationLine{
+		{Price: 57000, Color: "#007bff", LineWidth: 1, LineStyle: lineDotted, AxisLabelVisible: true, Title: "Entry 1"},
+		{Price: 57570.123, Color: "#28a745", LineWidth: 1, LineStyle: lineDashed, AxisLabelVisible: true, Title: "Target 1"},
+	}
+	if got := entryLines(types.Order{OrderID: 1, Price: 57000}, 57570.12345); !reflect.DeepEqual(got, want) {
+		t.Errorf("entryLines() = %v, want %v", got, want)
+	}
+}
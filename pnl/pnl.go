@@ -0,0 +1,186 @@
+package pnl
+
+/* This package implements the live P&L ticker. The Master Node saves the realized and unrealized profit of
+every thread each minute in the pnl table, keeping one day of snapshots, and the dashboard header polls Load
+through GET /pnl to display the totals and a sparkline of the total profit of each thread. */
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Ticker range in minutes */
+const (
+	minutesDefault = 60
+	minutesMax     = 1440 /* Snapshots are kept for one day */
+)
+
+// Ticker struct define the P&L ticker of all threads over the last Minutes
+type Ticker struct {
+	Minutes    int
+	Time       []int64   /* Snapshot times in milliseconds */
+	Realized   float64   /* Latest realized profit of all threads */
+	Unrealized float64   /* Latest unrealized profit of all threads */
+	Total      float64   /* Realized plus unrealized */
+	Series     []float64 /* Total profit of all threads at each Time */
+	Threads    []Thread
+}
+
+// Thread struct define the P&L of a thread in the ticker
+type Thread struct {
+	ThreadID   string
+	Realized   float64
+	Unrealized float64
+	Total      float64
+	Change     float64   /* Total profit change over the ticker range */
+	Series     []float64 /* Total profit at each Ticker.Time, carried forward between snapshots */
+}
+
+// ParseMinutes return the ticker range of the minutes query parameter, limited to one day
+func ParseMinutes(minutes string) int {
+
+	m, err := strconv.Atoi(minutes)
+
+	switch {
+	case err != nil || m <= 0:
+		return minutesDefault
+	case m > minutesMax:
+		return minutesMax
+	default:
+		return m
+	}
+
+}
+
+// Snapshot save the realized and unrealized profit of every thread and delete snapshots older than one day.
+// Only the Master Node saves snapshots to avoid duplicates.
+func Snapshot(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !sessionData.MasterNode {
+
+		return
+
+	}
+
+	now := time.Now()
+
+	if err := mysql.SavePnl(sessionData, milliseconds(now), milliseconds(now.Add(-minutesMax*time.Minute))); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+}
+
+// Load the P&L ticker of the last minutes
+func Load(
+	sessionData *types.Session,
+	minutes int) (ticker Ticker, err error) {
+
+	var snapshots []types.PnlSnapshot
+
+	if snapshots, err = mysql.GetPnlSince(sessionData, milliseconds(time.Now().Add(-time.Duration(minutes)*time.Minute))); err != nil {
+
+		return Ticker{Minutes: minutes}, err
+
+	}
+
+	ticker = build(snapshots)
+	ticker.Minutes = minutes
+
+	return ticker, nil
+
+}
+
+/* Build the ticker of snapshots ordered by time, threads missing from a snapshot keep their previous values */
+func build(snapshots []types.PnlSnapshot) (ticker Ticker) {
+
+	index := map[string]int{} /* Thread position in ticker.Threads */
+
+	for _, snapshot := range snapshots {
+
+		if n := len(ticker.Time); n == 0 || ticker.Time[n-1] != snapshot.Time {
+
+			ticker.Time = append(ticker.Time, snapshot.Time)
+
+			for i := range ticker.Threads { /* Carry forward the previous total */
+				ticker.Threads[i].Series = append(ticker.Threads[i].Series, last(ticker.Threads[i].Series))
+			}
+
+		}
+
+		i, ok := index[snapshot.ThreadID]
+		if !ok {
+
+			i = len(ticker.Threads)
+			index[snapshot.ThreadID] = i
+			ticker.Threads = append(ticker.Threads, Thread{ThreadID: snapshot.ThreadID, Series: make([]float64, len(ticker.Time))})
+
+		}
+
+		thread := &ticker.Threads[i]
+		thread.Realized = functions.Round(snapshot.Realized, 2)
+		thread.Unrealized = functions.Round(snapshot.Unrealized, 2)
+		thread.Total = functions.Round(snapshot.Realized+snapshot.Unrealized, 2)
+		thread.Series[len(thread.Series)-1] = thread.Total
+
+	}
+
+	ticker.Series = make([]float64, len(ticker.Time))
+
+	for i := range ticker.Threads {
+
+		thread := &ticker.Threads[i]
+
+		for t, total := range thread.Series {
+			ticker.Series[t] = functions.Round(ticker.Series[t]+total, 2)
+		}
+
+		thread.Change = functions.Round(thread.Total-thread.Series[0], 2)
+		ticker.Realized = functions.Round(ticker.Realized+thread.Realized, 2)
+		ticker.Unrealized = functions.Round(ticker.Unrealized+thread.Unrealized, 2)
+
+	}
+
+	ticker.Total = functions.Round(ticker.Realized+ticker.Unrealized, 2)
+
+	sort.SliceStable(ticker.Threads, func(i, j int) bool { return ticker.Threads[i].ThreadID < ticker.Threads[j].ThreadID })
+
+	return ticker
+
+}
+
+/* Return the last value of series, 0 when empty */
+func last(series []float64) float64 {
+
+	if len(series) == 0 {
+
+		return 0
+
+	}
+
+	return series[len(series)-1]
+
+}
+
+/* Return t in milliseconds */
+func milliseconds(t time.Time) int64 {
+
+	return t.UnixNano() / int64(time.Millisecond)
+
+}
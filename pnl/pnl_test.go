@@ -0,0 +1,69 @@
+package pnl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParseMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		minutes string
+		want    int
+	}{
+		{name: "empty", minutes: "", want: 60},
+		{name: "valid", minutes: "15", want: 15},
+		{name: "negative", minutes: "-5", want: 60},
+		{name: "above one day", minutes: "10000", want: 1440},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMinutes(tt.minutes); got != tt.want {
+				t.Errorf("ParseMinutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_build(t *testing.T) {
+	tests := []struct {
+		name      string
+		snapshots []types.PnlSnapshot
+		want      Ticker
+	}{
+		{
+			name:      "no snapshots",
+			snapshots: nil,
+			want:      Ticker{Series: []float64{}},
+		},
+		{
+			name: "thread started and thread stopped",
+			snapshots: []types.PnlSnapshot{
+				{Time: 1000, ThreadID: "b", Realized: 10, Unrealized: -2},
+				{Time: 2000, ThreadID: "a", Realized: 0, Unrealized: 1.5},
+				{Time: 2000, ThreadID: "b", Realized: 12, Unrealized: -1},
+				{Time: 3000, ThreadID: "a", Realized: 3, Unrealized: 0.25},
+			},
+			want: Ticker{
+				Time:       []int64{1000, 2000, 3000},
+				Realized:   15,
+				Unrealized: -0.75,
+				Total:      14.25,
+				Series:     []float64{8, 12.5, 14.25},
+				Threads: []Thread{
+					{ThreadID: "a", Realized: 3, Unrealized: 0.25, Total: 3.25, Change: 3.25, Series: []float64{0, 1.5, 3.25}},
+					{ThreadID: "b", Realized: 12, Unrealized: -1, Total: 11, Change: 3, Series: []float64{8, 11, 11}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := build(tt.snapshots); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("build() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
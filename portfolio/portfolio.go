@@ -0,0 +1,340 @@
+package portfolio
+
+/* This package implements the consolidated portfolio page. Every running thread saves a snapshot of the
+balances of its exchange account and the USDT price of each asset held every 5 minutes, and threads sharing
+an exchange account overwrite the same snapshot. The portfolio page aggregates the latest snapshot of all
+exchange accounts and the open transactions of all threads, valued in the selected fiat currency. */
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const quoteAsset = "USDT" /* Asset prices are saved in USDT */
+
+// Currencies list the fiat currencies available to value the portfolio, the first is the default
+var Currencies = []string{"USDT", "EUR", "GBP"}
+
+// Asset struct define an asset held in the portfolio page
+type Asset struct {
+	Asset    string
+	Quantity float64 /* Free and locked */
+	Price    float64 /* Price in the selected currency */
+	Value    float64
+	Priced   bool /* False when the asset has no USDT price */
+}
+
+// Account struct define the balances of an exchange account in the portfolio page
+type Account struct {
+	Account string
+	Assets  []Asset
+	Value   float64
+	Updated string /* Latest snapshot time */
+}
+
+// Position struct define the open transactions of a thread in the portfolio page
+type Position struct {
+	types.Position
+	Cost      float64 /* Purchase cost in the selected currency */
+	Value     float64
+	Profit    float64
+	ProfitPct float64
+	Priced    bool /* False when the symbol or its fiat has no USDT price */
+}
+
+// Overview struct define the portfolio page (portfolio.html)
+type Overview struct {
+	Currency      string
+	Currencies    []string
+	Accounts      []Account
+	Assets        []Asset /* Assets consolidated across exchange accounts */
+	Positions     []Position
+	Value         float64 /* Balances of all exchange accounts */
+	PositionCost  float64
+	PositionValue float64
+	Message       string
+	Theme         string /* UI theme of the logged in user */
+}
+
+// AccountName return the exchange account of the thread, i.e. binance or binance-testnet
+func AccountName(configData *types.Config) string {
+
+	if configData.TestNet {
+
+		return strings.ToLower(configData.ExchangeName) + "-testnet"
+
+	}
+
+	return strings.ToLower(configData.ExchangeName)
+
+}
+
+// Currency return currency when available to value the portfolio, or the default currency
+func Currency(currency string) string {
+
+	for _, c := range Currencies {
+
+		if c == currency {
+
+			return c
+
+		}
+
+	}
+
+	return Currencies[0]
+
+}
+
+// Snapshot save the balances of the thread exchange account and the USDT price of the assets held and of Currencies
+func Snapshot(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+	var balances []types.Balance
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	account := AccountName(configData)
+
+	if balances, err = exchange.GetAccountBalances(configData, sessionData); err != nil {
+
+		return
+
+	}
+
+	assets := append([]string{}, Currencies...)
+
+	for _, balance := range balances {
+
+		balance.Account = account
+		balance.Time = now
+
+		if err = mysql.SaveBalance(sessionData, balance); err != nil {
+
+			return
+
+		}
+
+		assets = append(assets, balance.Asset)
+
+	}
+
+	if err = mysql.DeleteBalanceBefore(sessionData, account, now); err != nil { /* Remove assets no longer held */
+
+		return
+
+	}
+
+	saved := make(map[string]bool)
+
+	for _, asset := range assets {
+
+		if asset == quoteAsset || saved[asset] {
+			continue
+		}
+
+		saved[asset] = true
+
+		price, e := exchange.GetSymbolPrice(configData, sessionData, asset+quoteAsset)
+		if e != nil || price <= 0 { /* Asset not traded against USDT */
+			continue
+		}
+
+		if err = mysql.SaveAssetPrice(sessionData, asset, price, now); err != nil {
+
+			return
+
+		}
+
+	}
+
+}
+
+// Load the latest balances of all exchange accounts and the open transactions of all threads valued in currency
+func Load(
+	sessionData *types.Session,
+	currency string) (overview Overview, err error) {
+
+	var balances []types.Balance
+	var positions []types.Position
+	var prices map[string]float64
+
+	overview.Currency = Currency(currency)
+	overview.Currencies = Currencies
+
+	if balances, err = mysql.GetBalances(sessionData); err != nil {
+
+		return overview, err
+
+	}
+
+	if prices, err = mysql.GetAssetPrices(sessionData); err != nil {
+
+		return overview, err
+
+	}
+
+	if positions, err = mysql.GetOpenPositions(sessionData); err != nil {
+
+		return overview, err
+
+	}
+
+	rate, ok := price(prices, overview.Currency)
+	if !ok { /* Currency price not saved yet */
+
+		overview.Message = "No " + overview.Currency + " price available, valued in " + quoteAsset
+		overview.Currency = quoteAsset
+		rate = 1
+
+	}
+
+	overview.Accounts, overview.Assets, overview.Value = summarize(balances, prices, rate)
+	overview.Positions, overview.PositionCost, overview.PositionValue = valuePositions(positions, prices, rate)
+
+	return overview, nil
+
+}
+
+/* Return the balances by exchange account and consolidated by asset, valued at rate USDT per unit of the selected currency */
+func summarize(
+	balances []types.Balance,
+	prices map[string]float64,
+	rate float64) (accounts []Account, assets []Asset, total float64) {
+
+	quantities := make(map[string]float64)
+
+	for _, balance := range balances {
+
+		if len(accounts) == 0 || accounts[len(accounts)-1].Account != balance.Account {
+			accounts = append(accounts, Account{
+				Account: balance.Account,
+				Updated: time.Unix((balance.Time / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+			})
+		}
+
+		account := &accounts[len(accounts)-1]
+		asset := valueAsset(balance.Asset, balance.Free+balance.Locked, prices, rate)
+
+		account.Assets = append(account.Assets, asset)
+		account.Value = functions.Round(account.Value+asset.Value, 2)
+		quantities[balance.Asset] += balance.Free + balance.Locked
+
+	}
+
+	for name, quantity := range quantities {
+
+		asset := valueAsset(name, quantity, prices, rate)
+		assets = append(assets, asset)
+		total = functions.Round(total+asset.Value, 2)
+
+	}
+
+	sort.Slice(assets, func(i, j int) bool { /* Largest value first */
+		if assets[i].Value == assets[j].Value {
+			return assets[i].Asset < assets[j].Asset
+		}
+		return assets[i].Value > assets[j].Value
+	})
+
+	return accounts, assets, total
+
+}
+
+/* Return the open transactions of all threads with cost and market value in the selected currency */
+func valuePositions(
+	positions []types.Position,
+	prices map[string]float64,
+	rate float64) (rows []Position, cost float64, value float64) {
+
+	for _, position := range positions {
+
+		row := Position{Position: position}
+
+		fiatPrice, fiatOk := price(prices, position.FiatSymbol)
+		basePrice, baseOk := price(prices, strings.TrimSuffix(position.Symbol, position.FiatSymbol))
+
+		if fiatOk && baseOk && position.FiatSymbol != "" && strings.HasSuffix(position.Symbol, position.FiatSymbol) {
+
+			row.Priced = true
+			row.Cost = functions.Round(position.Cost*fiatPrice/rate, 2)
+			row.Value = functions.Round(position.Quantity*basePrice/rate, 2)
+			row.Profit = functions.Round(row.Value-row.Cost, 2)
+
+			if row.Cost > 0 {
+				row.ProfitPct = functions.Round((row.Profit/row.Cost)*100, 2)
+			}
+
+			cost = functions.Round(cost+row.Cost, 2)
+			value = functions.Round(value+row.Value, 2)
+
+		}
+
+		rows = append(rows, row)
+
+	}
+
+	return rows, cost, value
+
+}
+
+/* Return an asset valued at rate USDT per unit of the selected currency */
+func valueAsset(
+	name string,
+	quantity float64,
+	prices map[string]float64,
+	rate float64) (asset Asset) {
+
+	asset.Asset = name
+	asset.Quantity = quantity
+
+	if p, ok := price(prices, name); ok {
+
+		asset.Priced = true
+		asset.Price = functions.Round(p/rate, 4)
+		asset.Value = functions.Round(quantity*p/rate, 2)
+
+	}
+
+	return asset
+
+}
+
+/* Return the USDT price of asset */
+func price(
+	prices map[string]float64,
+	asset string) (float64, bool) {
+
+	if asset == quoteAsset {
+
+		return 1, true
+
+	}
+
+	p, ok := prices[asset]
+
+	return p, ok && p > 0
+
+}
@@ -0,0 +1,78 @@
+package portfolio
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		want     string
+	}{
+		{name: "available", currency: "EUR", want: "EUR"},
+		{name: "not available", currency: "JPY", want: "USDT"},
+		{name: "empty", currency: "", want: "USDT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Currency(tt.currency); got != tt.want {
+				t.Errorf("Currency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_summarize(t *testing.T) {
+	balances := []types.Balance{
+		{Account: "binance", Asset: "BTC", Free: 0.5, Locked: 0.5, Time: 1638321000000},
+		{Account: "binance", Asset: "USDT", Free: 1000, Locked: 0, Time: 1638321000000},
+		{Account: "binance-testnet", Asset: "BTC", Free: 1, Locked: 0, Time: 1638321000000},
+		{Account: "binance-testnet", Asset: "XYZ", Free: 10, Locked: 0, Time: 1638321000000},
+	}
+	prices := map[string]float64{"BTC": 50000, "EUR": 1.25}
+
+	accounts, assets, total := summarize(balances, prices, 1.25) /* Valued in EUR */
+
+	if len(accounts) != 2 || accounts[0].Value != 40800 || accounts[1].Value != 40000 {
+		t.Errorf("summarize() accounts = %v", accounts)
+	}
+
+	want := []Asset{
+		{Asset: "BTC", Quantity: 2, Price: 40000, Value: 80000, Priced: true},
+		{Asset: "USDT", Quantity: 1000, Price: 0.8, Value: 800, Priced: true},
+		{Asset: "XYZ", Quantity: 10, Price: 0, Value: 0, Priced: false},
+	}
+	if !reflect.DeepEqual(assets, want) {
+		t.Errorf("summarize() assets = %v, want %v", assets, want)
+	}
+
+	if total != 80800 {
+		t.Errorf("summarize() total = %v, want %v", total, 80800)
+	}
+}
+
+func Test_valuePositions(t *testing.T) {
+	positions := []types.Position{
+		{ThreadID: "c683ok5mk1u1120gnmmg", Exchange: "binance", Symbol: "BTCUSDT", FiatSymbol: "USDT", Quantity: 0.01, Cost: 450},
+		{ThreadID: "c683ok5mk1u1120gnmmh", Exchange: "binance", Symbol: "XYZBUSD", FiatSymbol: "BUSD", Quantity: 10, Cost: 100},
+	}
+	prices := map[string]float64{"BTC": 50000}
+
+	rows, cost, value := valuePositions(positions, prices, 1)
+
+	want := []Position{
+		{Position: positions[0], Cost: 450, Value: 500, Profit: 50, ProfitPct: 11.11, Priced: true},
+		{Position: positions[1]},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("valuePositions() = %v, want %v", rows, want)
+	}
+
+	if cost != 450 || value != 500 {
+		t.Errorf("valuePositions() cost = %v, value = %v, want 450, 500", cost, value)
+	}
+}
@@ -0,0 +1,242 @@
+package preferences
+
+/* This package implements the UI preferences of dashboard users. Preferences are saved in the preference
+table per authenticated user, so they follow the user across browsers and sessions. */
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/aleibovici/cryptopump/i18n"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Refresh interval limits in seconds */
+const (
+	refreshMin     = 1
+	refreshMax     = 60
+	refreshDefault = 2
+)
+
+/* Preference errors */
+var (
+	ErrInvalidTheme    = errors.New("Invalid theme")
+	ErrInvalidRefresh  = errors.New("Refresh interval must be 1 to 60 seconds")
+	ErrInvalidCurrency = errors.New("Invalid currency")
+	ErrInvalidColumn   = errors.New("Invalid column")
+	ErrInvalidLanguage = errors.New("Invalid language")
+)
+
+// Themes list the available UI themes
+var Themes = []string{"light", "dark"}
+
+// Currency struct define a currency used to display amounts
+type Currency struct {
+	Code   string
+	Symbol string
+}
+
+// Currencies list the currencies available to display amounts, the first is the default
+var Currencies = []Currency{
+	{Code: "USD", Symbol: "$"},
+	{Code: "EUR", Symbol: "€"},
+	{Code: "GBP", Symbol: "£"},
+	{Code: "USDT", Symbol: "₮"},
+}
+
+// Columns list the open transaction columns that can be hidden, OrderID is always visible
+var Columns = []string{"Quantity", "Quote", "Price", "Target", "Diff"}
+
+// Default return the preferences of users that never saved their preferences
+func Default() types.Preference {
+
+	return types.Preference{
+		Theme:           Themes[0],
+		RefreshInterval: refreshDefault,
+		Currency:        Currencies[0].Code,
+		CurrencySymbol:  Currencies[0].Symbol,
+		Columns:         Columns,
+	}
+
+}
+
+// Get retrieve the preferences of username, using the default for preferences not saved or no longer valid
+func Get(
+	sessionData *types.Session,
+	username string) types.Preference {
+
+	preference := Default()
+
+	saved, found, err := mysql.GetPreference(sessionData, username)
+	if err != nil || !found {
+
+		return preference
+
+	}
+
+	if valid(Themes, saved.Theme) {
+		preference.Theme = saved.Theme
+	}
+
+	if saved.RefreshInterval >= refreshMin && saved.RefreshInterval <= refreshMax {
+		preference.RefreshInterval = saved.RefreshInterval
+	}
+
+	if c, ok := currency(saved.Currency); ok {
+		preference.Currency = c.Code
+		preference.CurrencySymbol = c.Symbol
+	}
+
+	if saved.Language == "" || i18n.Supported(saved.Language) {
+		preference.Language = saved.Language
+	}
+
+	preference.Columns = nil
+	for _, column := range saved.Columns {
+		if valid(Columns, column) {
+			preference.Columns = append(preference.Columns, column)
+		}
+	}
+
+	return preference
+
+}
+
+// Parse validate the preferences submitted from the preferences page
+func Parse(
+	theme string,
+	refresh string,
+	currencyCode string,
+	language string,
+	columns []string) (preference types.Preference, err error) {
+
+	if !valid(Themes, theme) {
+
+		return preference, ErrInvalidTheme
+
+	}
+
+	interval, err := strconv.Atoi(refresh)
+	if err != nil || interval < refreshMin || interval > refreshMax {
+
+		return preference, ErrInvalidRefresh
+
+	}
+
+	c, ok := currency(currencyCode)
+	if !ok {
+
+		return preference, ErrInvalidCurrency
+
+	}
+
+	if language != "" && !i18n.Supported(language) { /* Empty language uses the browser language */
+
+		return preference, ErrInvalidLanguage
+
+	}
+
+	for _, column := range columns {
+
+		if !valid(Columns, column) {
+
+			return preference, ErrInvalidColumn
+
+		}
+
+	}
+
+	return types.Preference{
+		Theme:           theme,
+		RefreshInterval: interval,
+		Currency:        c.Code,
+		CurrencySymbol:  c.Symbol,
+		Columns:         columns,
+		Language:        language,
+	}, nil
+
+}
+
+// Save store the preferences of username
+func Save(
+	sessionData *types.Session,
+	username string,
+	preference types.Preference) error {
+
+	return mysql.SavePreference(sessionData, username, preference)
+
+}
+
+// Column struct define an open transaction column in the preferences page
+type Column struct {
+	Name    string
+	Visible bool
+}
+
+// Page struct define the preferences page (preferences.html)
+type Page struct {
+	Preference types.Preference
+	Themes     []string
+	Currencies []Currency
+	Languages  []i18n.Locale
+	Columns    []Column
+	Message    string
+}
+
+// LoadPage Load the preferences page for preference
+func LoadPage(
+	preference types.Preference,
+	message string) (page Page) {
+
+	page.Preference = preference
+	page.Themes = Themes
+	page.Currencies = Currencies
+	page.Languages = i18n.Locales
+	page.Message = message
+
+	for _, column := range Columns {
+
+		page.Columns = append(page.Columns, Column{Name: column, Visible: valid(preference.Columns, column)})
+
+	}
+
+	return page
+
+}
+
+/* Return true when value is in values */
+func valid(
+	values []string,
+	value string) bool {
+
+	for _, v := range values {
+
+		if v == value {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Return the currency with code */
+func currency(code string) (Currency, bool) {
+
+	for _, c := range Currencies {
+
+		if c.Code == code {
+
+			return c, true
+
+		}
+
+	}
+
+	return Currency{}, false
+
+}
@@ -0,0 +1,100 @@
+package preferences
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		theme    string
+		refresh  string
+		currency string
+		language string
+		columns  []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    types.Preference
+		wantErr error
+	}{
+		{
+			name: "valid",
+			args: args{theme: "dark", refresh: "5", currency: "EUR", language: "pt", columns: []string{"Price", "Target"}},
+			want: types.Preference{
+				Theme:           "dark",
+				RefreshInterval: 5,
+				Currency:        "EUR",
+				CurrencySymbol:  "€",
+				Columns:         []string{"Price", "Target"},
+				Language:        "pt",
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "invalid theme",
+			args:    args{theme: "solarized", refresh: "5", currency: "EUR"},
+			want:    types.Preference{},
+			wantErr: ErrInvalidTheme,
+		},
+		{
+			name:    "refresh out of range",
+			args:    args{theme: "light", refresh: "0", currency: "USD"},
+			want:    types.Preference{},
+			wantErr: ErrInvalidRefresh,
+		},
+		{
+			name:    "refresh not a number",
+			args:    args{theme: "light", refresh: "fast", currency: "USD"},
+			want:    types.Preference{},
+			wantErr: ErrInvalidRefresh,
+		},
+		{
+			name:    "invalid currency",
+			args:    args{theme: "light", refresh: "2", currency: "JPY"},
+			want:    types.Preference{},
+			wantErr: ErrInvalidCurrency,
+		},
+		{
+			name:    "invalid column",
+			args:    args{theme: "light", refresh: "2", currency: "USD", columns: []string{"OrderID"}},
+			want:    types.Preference{},
+			wantErr: ErrInvalidColumn,
+		},
+		{
+			name:    "invalid language",
+			args:    args{theme: "light", refresh: "2", currency: "USD", language: "xx"},
+			want:    types.Preference{},
+			wantErr: ErrInvalidLanguage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.theme, tt.args.refresh, tt.args.currency, tt.args.language, tt.args.columns)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPage(t *testing.T) {
+	page := LoadPage(types.Preference{Columns: []string{"Quote", "Diff"}}, "")
+	want := []Column{
+		{Name: "Quantity", Visible: false},
+		{Name: "Quote", Visible: true},
+		{Name: "Price", Visible: false},
+		{Name: "Target", Visible: false},
+		{Name: "Diff", Visible: true},
+	}
+	if !reflect.DeepEqual(page.Columns, want) {
+		t.Errorf("LoadPage() Columns = %v, want %v", page.Columns, want)
+	}
+}
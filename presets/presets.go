@@ -0,0 +1,170 @@
+package presets
+
+/* This package implements strategy presets. The configuration of a thread is saved as a named preset in
+the preset table, and a preset can be loaded into the configuration of a new thread before it is started,
+so the same strategy can be rolled out across many symbols. The symbol is not part of a preset. */
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Preset errors */
+var (
+	ErrInvalidName = errors.New("Preset name must have 1 to 64 letters, digits, spaces, '.', '-' or '_'")
+	ErrNotFound    = errors.New("Preset not found")
+	ErrRunning     = errors.New("Preset can only be loaded before the thread is started")
+)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9 ._-]{1,64}$`)
+
+/* Configuration keys that are set for each thread and never saved in a preset */
+var excluded = map[string]bool{
+	"symbol":     true,
+	"newsession": true,
+}
+
+// Save store the current session configuration as the preset name, replacing an existing preset with the same name
+func Save(
+	viperData *types.ViperData,
+	sessionData *types.Session,
+	username string,
+	name string) (err error) {
+
+	var config []byte
+
+	if !validName.MatchString(name) {
+
+		return ErrInvalidName
+
+	}
+
+	if config, err = json.Marshal(snapshot(viperData.V1.GetStringMap("config"))); err != nil {
+
+		return err
+
+	}
+
+	if err = mysql.SavePreset(sessionData, name, username, string(config)); err != nil {
+
+		return err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   nil,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Preset " + name + " saved by " + username,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Load apply the preset name to the session configuration file, keeping the symbol. Only available
+// before the thread is started.
+func Load(
+	viperData *types.ViperData,
+	sessionData *types.Session,
+	username string,
+	name string) (err error) {
+
+	var config string
+	var values map[string]string
+
+	if sessionData.ThreadID != "" {
+
+		return ErrRunning
+
+	}
+
+	if config, err = mysql.GetPreset(sessionData, name); err != nil {
+
+		return err
+
+	}
+
+	if config == "" {
+
+		return ErrNotFound
+
+	}
+
+	if err = json.Unmarshal([]byte(config), &values); err != nil {
+
+		return err
+
+	}
+
+	for _, key := range keys(values) {
+
+		if excluded[key] {
+			continue
+		}
+
+		viperData.V1.Set("config."+key, values[key])
+
+	}
+
+	if err = viperData.V1.WriteConfig(); err != nil {
+
+		return err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   nil,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Preset " + name + " loaded by " + username,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+/* Return the configuration values saved in a preset as strings */
+func snapshot(config map[string]interface{}) map[string]string {
+
+	values := make(map[string]string)
+
+	for key, value := range config {
+
+		if excluded[key] || value == nil {
+			continue
+		}
+
+		values[key] = fmt.Sprint(value)
+
+	}
+
+	return values
+
+}
+
+/* Return the keys of values sorted */
+func keys(values map[string]string) (sorted []string) {
+
+	for key := range values {
+
+		sorted = append(sorted, key)
+
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+
+}
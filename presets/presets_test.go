@@ -0,0 +1,59 @@
+package presets
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_snapshot(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   map[string]string
+	}{
+		{
+			name: "symbol excluded",
+			config: map[string]interface{}{
+				"symbol":     "BTCUSDT",
+				"newsession": "false",
+				"stoploss":   "0.05",
+				"buy_wait":   30,
+				"debug":      true,
+			},
+			want: map[string]string{"stoploss": "0.05", "buy_wait": "30", "debug": "true"},
+		},
+		{
+			name:   "nil values ignored",
+			config: map[string]interface{}{"rebalance_weights": nil},
+			want:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snapshot(tt.config); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("snapshot() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_validName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "dca-conservative", want: true},
+		{name: "Scalper v1.2", want: true},
+		{name: "", want: false},
+		{name: "../config", want: false},
+		{name: "<script>", want: false},
+		{name: "a123456789b123456789c123456789d123456789e123456789f123456789g1234", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validName.MatchString(tt.name); got != tt.want {
+				t.Errorf("validName.MatchString(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,1383 @@
+// Control-plane API for CryptoPump, mirroring the REST API under /api/v1/ with streaming
+// endpoints for live market and order events.
+//
+// Authentication uses the REST API tokens created in Admin, sent as the metadata
+// "authorization: Bearer <token>", and the same roles: viewer for reads and streams,
+// trader for control actions and admin for configuration updates.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/cryptopump/v1/cryptopump.proto
+
+package cryptopumpv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Session struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId        string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	ThreadIdSession string                 `protobuf:"bytes,2,opt,name=thread_id_session,json=threadIdSession,proto3" json:"thread_id_session,omitempty"`
+	Exchange        string                 `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	FiatSymbol      string                 `protobuf:"bytes,4,opt,name=fiat_symbol,json=fiatSymbol,proto3" json:"fiat_symbol,omitempty"`
+	FiatFunds       float64                `protobuf:"fixed64,5,opt,name=fiat_funds,json=fiatFunds,proto3" json:"fiat_funds,omitempty"`
+	DiffTotal       float64                `protobuf:"fixed64,6,opt,name=diff_total,json=diffTotal,proto3" json:"diff_total,omitempty"`
+	Status          bool                   `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Session) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *Session) GetThreadIdSession() string {
+	if x != nil {
+		return x.ThreadIdSession
+	}
+	return ""
+}
+
+func (x *Session) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *Session) GetFiatSymbol() string {
+	if x != nil {
+		return x.FiatSymbol
+	}
+	return ""
+}
+
+func (x *Session) GetFiatFunds() float64 {
+	if x != nil {
+		return x.FiatFunds
+	}
+	return 0
+}
+
+func (x *Session) GetDiffTotal() float64 {
+	if x != nil {
+		return x.DiffTotal
+	}
+	return 0
+}
+
+func (x *Session) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+type Order struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId                 int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ClientOrderId           string                 `protobuf:"bytes,2,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	Symbol                  string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side                    string                 `protobuf:"bytes,4,opt,name=side,proto3" json:"side,omitempty"`
+	Status                  string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Price                   float64                `protobuf:"fixed64,6,opt,name=price,proto3" json:"price,omitempty"`
+	ExecutedQuantity        float64                `protobuf:"fixed64,7,opt,name=executed_quantity,json=executedQuantity,proto3" json:"executed_quantity,omitempty"`
+	CumulativeQuoteQuantity float64                `protobuf:"fixed64,8,opt,name=cumulative_quote_quantity,json=cumulativeQuoteQuantity,proto3" json:"cumulative_quote_quantity,omitempty"`
+	TransactTime            int64                  `protobuf:"varint,9,opt,name=transact_time,json=transactTime,proto3" json:"transact_time,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Order) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *Order) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *Order) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Order) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Order) GetExecutedQuantity() float64 {
+	if x != nil {
+		return x.ExecutedQuantity
+	}
+	return 0
+}
+
+func (x *Order) GetCumulativeQuoteQuantity() float64 {
+	if x != nil {
+		return x.CumulativeQuoteQuantity
+	}
+	return 0
+}
+
+func (x *Order) GetTransactTime() int64 {
+	if x != nil {
+		return x.TransactTime
+	}
+	return 0
+}
+
+type PendingAction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	OrderId       int64                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Notional      float64                `protobuf:"fixed64,5,opt,name=notional,proto3" json:"notional,omitempty"`
+	CreatedTime   int64                  `protobuf:"varint,6,opt,name=created_time,json=createdTime,proto3" json:"created_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingAction) Reset() {
+	*x = PendingAction{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingAction) ProtoMessage() {}
+
+func (x *PendingAction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingAction.ProtoReflect.Descriptor instead.
+func (*PendingAction) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PendingAction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PendingAction) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *PendingAction) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *PendingAction) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *PendingAction) GetNotional() float64 {
+	if x != nil {
+		return x.Notional
+	}
+	return 0
+}
+
+func (x *PendingAction) GetCreatedTime() int64 {
+	if x != nil {
+		return x.CreatedTime
+	}
+	return 0
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{3}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type ListOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{5}
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type GetProfitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProfitRequest) Reset() {
+	*x = GetProfitRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfitRequest) ProtoMessage() {}
+
+func (x *GetProfitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfitRequest.ProtoReflect.Descriptor instead.
+func (*GetProfitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{7}
+}
+
+type GetProfitResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Profit          float64                `protobuf:"fixed64,1,opt,name=profit,proto3" json:"profit,omitempty"`
+	ProfitNet       float64                `protobuf:"fixed64,2,opt,name=profit_net,json=profitNet,proto3" json:"profit_net,omitempty"`
+	ProfitPct       float64                `protobuf:"fixed64,3,opt,name=profit_pct,json=profitPct,proto3" json:"profit_pct,omitempty"`
+	ThreadProfit    float64                `protobuf:"fixed64,4,opt,name=thread_profit,json=threadProfit,proto3" json:"thread_profit,omitempty"`
+	ThreadProfitPct float64                `protobuf:"fixed64,5,opt,name=thread_profit_pct,json=threadProfitPct,proto3" json:"thread_profit_pct,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetProfitResponse) Reset() {
+	*x = GetProfitResponse{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProfitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProfitResponse) ProtoMessage() {}
+
+func (x *GetProfitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProfitResponse.ProtoReflect.Descriptor instead.
+func (*GetProfitResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetProfitResponse) GetProfit() float64 {
+	if x != nil {
+		return x.Profit
+	}
+	return 0
+}
+
+func (x *GetProfitResponse) GetProfitNet() float64 {
+	if x != nil {
+		return x.ProfitNet
+	}
+	return 0
+}
+
+func (x *GetProfitResponse) GetProfitPct() float64 {
+	if x != nil {
+		return x.ProfitPct
+	}
+	return 0
+}
+
+func (x *GetProfitResponse) GetThreadProfit() float64 {
+	if x != nil {
+		return x.ThreadProfit
+	}
+	return 0
+}
+
+func (x *GetProfitResponse) GetThreadProfitPct() float64 {
+	if x != nil {
+		return x.ThreadProfitPct
+	}
+	return 0
+}
+
+type StartSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartSessionRequest) Reset() {
+	*x = StartSessionRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSessionRequest) ProtoMessage() {}
+
+func (x *StartSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartSessionRequest.ProtoReflect.Descriptor instead.
+func (*StartSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{9}
+}
+
+type StopSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopSessionRequest) Reset() {
+	*x = StopSessionRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopSessionRequest) ProtoMessage() {}
+
+func (x *StopSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopSessionRequest.ProtoReflect.Descriptor instead.
+func (*StopSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{10}
+}
+
+type BuyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuyRequest) Reset() {
+	*x = BuyRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuyRequest) ProtoMessage() {}
+
+func (x *BuyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuyRequest.ProtoReflect.Descriptor instead.
+func (*BuyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{11}
+}
+
+type SellRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SellRequest) Reset() {
+	*x = SellRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SellRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SellRequest) ProtoMessage() {}
+
+func (x *SellRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SellRequest.ProtoReflect.Descriptor instead.
+func (*SellRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SellRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type PendingActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingActionRequest) Reset() {
+	*x = PendingActionRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingActionRequest) ProtoMessage() {}
+
+func (x *PendingActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingActionRequest.ProtoReflect.Descriptor instead.
+func (*PendingActionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PendingActionRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ActionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// starting, stopping, buying, selling, pending or rejected.
+	Status        string         `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	ThreadId      string         `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	PendingAction *PendingAction `protobuf:"bytes,3,opt,name=pending_action,json=pendingAction,proto3" json:"pending_action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActionResponse) Reset() {
+	*x = ActionResponse{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionResponse) ProtoMessage() {}
+
+func (x *ActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionResponse.ProtoReflect.Descriptor instead.
+func (*ActionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ActionResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ActionResponse) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *ActionResponse) GetPendingAction() *PendingAction {
+	if x != nil {
+		return x.PendingAction
+	}
+	return nil
+}
+
+type UpdateConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        map[string]string      `protobuf:"bytes,1,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConfigRequest) Reset() {
+	*x = UpdateConfigRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConfigRequest) ProtoMessage() {}
+
+func (x *UpdateConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConfigRequest.ProtoReflect.Descriptor instead.
+func (*UpdateConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateConfigRequest) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type UpdateConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Config        map[string]string      `protobuf:"bytes,1,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConfigResponse) Reset() {
+	*x = UpdateConfigResponse{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConfigResponse) ProtoMessage() {}
+
+func (x *UpdateConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConfigResponse.ProtoReflect.Descriptor instead.
+func (*UpdateConfigResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateConfigResponse) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type StreamMarketRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMarketRequest) Reset() {
+	*x = StreamMarketRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMarketRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMarketRequest) ProtoMessage() {}
+
+func (x *StreamMarketRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMarketRequest.ProtoReflect.Descriptor instead.
+func (*StreamMarketRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{17}
+}
+
+type MarketEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Spread        float64                `protobuf:"fixed64,3,opt,name=spread,proto3" json:"spread,omitempty"`
+	Rsi3          float64                `protobuf:"fixed64,4,opt,name=rsi3,proto3" json:"rsi3,omitempty"`
+	Rsi7          float64                `protobuf:"fixed64,5,opt,name=rsi7,proto3" json:"rsi7,omitempty"`
+	Rsi14         float64                `protobuf:"fixed64,6,opt,name=rsi14,proto3" json:"rsi14,omitempty"`
+	Macd          float64                `protobuf:"fixed64,7,opt,name=macd,proto3" json:"macd,omitempty"`
+	Stale         bool                   `protobuf:"varint,8,opt,name=stale,proto3" json:"stale,omitempty"`
+	Time          int64                  `protobuf:"varint,9,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarketEvent) Reset() {
+	*x = MarketEvent{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarketEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarketEvent) ProtoMessage() {}
+
+func (x *MarketEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarketEvent.ProtoReflect.Descriptor instead.
+func (*MarketEvent) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *MarketEvent) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *MarketEvent) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *MarketEvent) GetSpread() float64 {
+	if x != nil {
+		return x.Spread
+	}
+	return 0
+}
+
+func (x *MarketEvent) GetRsi3() float64 {
+	if x != nil {
+		return x.Rsi3
+	}
+	return 0
+}
+
+func (x *MarketEvent) GetRsi7() float64 {
+	if x != nil {
+		return x.Rsi7
+	}
+	return 0
+}
+
+func (x *MarketEvent) GetRsi14() float64 {
+	if x != nil {
+		return x.Rsi14
+	}
+	return 0
+}
+
+func (x *MarketEvent) GetMacd() float64 {
+	if x != nil {
+		return x.Macd
+	}
+	return 0
+}
+
+func (x *MarketEvent) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *MarketEvent) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+type StreamOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamOrdersRequest) Reset() {
+	*x = StreamOrdersRequest{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamOrdersRequest) ProtoMessage() {}
+
+func (x *StreamOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamOrdersRequest.ProtoReflect.Descriptor instead.
+func (*StreamOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{19}
+}
+
+type OrderEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// BUY or SELL.
+	Side          string  `protobuf:"bytes,1,opt,name=side,proto3" json:"side,omitempty"`
+	Order         *Order  `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	Profit        float64 `protobuf:"fixed64,3,opt,name=profit,proto3" json:"profit,omitempty"`
+	Time          int64   `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderEvent) Reset() {
+	*x = OrderEvent{}
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderEvent) ProtoMessage() {}
+
+func (x *OrderEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cryptopump_v1_cryptopump_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderEvent.ProtoReflect.Descriptor instead.
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *OrderEvent) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetProfit() float64 {
+	if x != nil {
+		return x.Profit
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+var File_proto_cryptopump_v1_cryptopump_proto protoreflect.FileDescriptor
+
+const file_proto_cryptopump_v1_cryptopump_proto_rawDesc = "" +
+	"\n" +
+	"$proto/cryptopump/v1/cryptopump.proto\x12\rcryptopump.v1\"\xe5\x01\n" +
+	"\aSession\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12*\n" +
+	"\x11thread_id_session\x18\x02 \x01(\tR\x0fthreadIdSession\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12\x1f\n" +
+	"\vfiat_symbol\x18\x04 \x01(\tR\n" +
+	"fiatSymbol\x12\x1d\n" +
+	"\n" +
+	"fiat_funds\x18\x05 \x01(\x01R\tfiatFunds\x12\x1d\n" +
+	"\n" +
+	"diff_total\x18\x06 \x01(\x01R\tdiffTotal\x12\x16\n" +
+	"\x06status\x18\a \x01(\bR\x06status\"\xb2\x02\n" +
+	"\x05Order\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12&\n" +
+	"\x0fclient_order_id\x18\x02 \x01(\tR\rclientOrderId\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x04 \x01(\tR\x04side\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x01R\x05price\x12+\n" +
+	"\x11executed_quantity\x18\a \x01(\x01R\x10executedQuantity\x12:\n" +
+	"\x19cumulative_quote_quantity\x18\b \x01(\x01R\x17cumulativeQuoteQuantity\x12#\n" +
+	"\rtransact_time\x18\t \x01(\x03R\ftransactTime\"\xae\x01\n" +
+	"\rPendingAction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\x03R\aorderId\x12\x1a\n" +
+	"\bnotional\x18\x05 \x01(\x01R\bnotional\x12!\n" +
+	"\fcreated_time\x18\x06 \x01(\x03R\vcreatedTime\"\x15\n" +
+	"\x13ListSessionsRequest\"J\n" +
+	"\x14ListSessionsResponse\x122\n" +
+	"\bsessions\x18\x01 \x03(\v2\x16.cryptopump.v1.SessionR\bsessions\"\x13\n" +
+	"\x11ListOrdersRequest\"B\n" +
+	"\x12ListOrdersResponse\x12,\n" +
+	"\x06orders\x18\x01 \x03(\v2\x14.cryptopump.v1.OrderR\x06orders\"\x12\n" +
+	"\x10GetProfitRequest\"\xba\x01\n" +
+	"\x11GetProfitResponse\x12\x16\n" +
+	"\x06profit\x18\x01 \x01(\x01R\x06profit\x12\x1d\n" +
+	"\n" +
+	"profit_net\x18\x02 \x01(\x01R\tprofitNet\x12\x1d\n" +
+	"\n" +
+	"profit_pct\x18\x03 \x01(\x01R\tprofitPct\x12#\n" +
+	"\rthread_profit\x18\x04 \x01(\x01R\fthreadProfit\x12*\n" +
+	"\x11thread_profit_pct\x18\x05 \x01(\x01R\x0fthreadProfitPct\"\x15\n" +
+	"\x13StartSessionRequest\"\x14\n" +
+	"\x12StopSessionRequest\"\f\n" +
+	"\n" +
+	"BuyRequest\"(\n" +
+	"\vSellRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\"&\n" +
+	"\x14PendingActionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x8a\x01\n" +
+	"\x0eActionResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1b\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12C\n" +
+	"\x0epending_action\x18\x03 \x01(\v2\x1c.cryptopump.v1.PendingActionR\rpendingAction\"\x98\x01\n" +
+	"\x13UpdateConfigRequest\x12F\n" +
+	"\x06config\x18\x01 \x03(\v2..cryptopump.v1.UpdateConfigRequest.ConfigEntryR\x06config\x1a9\n" +
+	"\vConfigEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9a\x01\n" +
+	"\x14UpdateConfigResponse\x12G\n" +
+	"\x06config\x18\x01 \x03(\v2/.cryptopump.v1.UpdateConfigResponse.ConfigEntryR\x06config\x1a9\n" +
+	"\vConfigEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x15\n" +
+	"\x13StreamMarketRequest\"\xcf\x01\n" +
+	"\vMarketEvent\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06spread\x18\x03 \x01(\x01R\x06spread\x12\x12\n" +
+	"\x04rsi3\x18\x04 \x01(\x01R\x04rsi3\x12\x12\n" +
+	"\x04rsi7\x18\x05 \x01(\x01R\x04rsi7\x12\x14\n" +
+	"\x05rsi14\x18\x06 \x01(\x01R\x05rsi14\x12\x12\n" +
+	"\x04macd\x18\a \x01(\x01R\x04macd\x12\x14\n" +
+	"\x05stale\x18\b \x01(\bR\x05stale\x12\x12\n" +
+	"\x04time\x18\t \x01(\x03R\x04time\"\x15\n" +
+	"\x13StreamOrdersRequest\"x\n" +
+	"\n" +
+	"OrderEvent\x12\x12\n" +
+	"\x04side\x18\x01 \x01(\tR\x04side\x12*\n" +
+	"\x05order\x18\x02 \x01(\v2\x14.cryptopump.v1.OrderR\x05order\x12\x16\n" +
+	"\x06profit\x18\x03 \x01(\x01R\x06profit\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\x03R\x04time2\xd3\a\n" +
+	"\fControlPlane\x12W\n" +
+	"\fListSessions\x12\".cryptopump.v1.ListSessionsRequest\x1a#.cryptopump.v1.ListSessionsResponse\x12Q\n" +
+	"\n" +
+	"ListOrders\x12 .cryptopump.v1.ListOrdersRequest\x1a!.cryptopump.v1.ListOrdersResponse\x12N\n" +
+	"\tGetProfit\x12\x1f.cryptopump.v1.GetProfitRequest\x1a .cryptopump.v1.GetProfitResponse\x12Q\n" +
+	"\fStartSession\x12\".cryptopump.v1.StartSessionRequest\x1a\x1d.cryptopump.v1.ActionResponse\x12O\n" +
+	"\vStopSession\x12!.cryptopump.v1.StopSessionRequest\x1a\x1d.cryptopump.v1.ActionResponse\x12?\n" +
+	"\x03Buy\x12\x19.cryptopump.v1.BuyRequest\x1a\x1d.cryptopump.v1.ActionResponse\x12A\n" +
+	"\x04Sell\x12\x1a.cryptopump.v1.SellRequest\x1a\x1d.cryptopump.v1.ActionResponse\x12Q\n" +
+	"\vConfirmSell\x12#.cryptopump.v1.PendingActionRequest\x1a\x1d.cryptopump.v1.ActionResponse\x12P\n" +
+	"\n" +
+	"RejectSell\x12#.cryptopump.v1.PendingActionRequest\x1a\x1d.cryptopump.v1.ActionResponse\x12W\n" +
+	"\fUpdateConfig\x12\".cryptopump.v1.UpdateConfigRequest\x1a#.cryptopump.v1.UpdateConfigResponse\x12P\n" +
+	"\fStreamMarket\x12\".cryptopump.v1.StreamMarketRequest\x1a\x1a.cryptopump.v1.MarketEvent0\x01\x12O\n" +
+	"\fStreamOrders\x12\".cryptopump.v1.StreamOrdersRequest\x1a\x19.cryptopump.v1.OrderEvent0\x01BCZAgithub.com/aleibovici/cryptopump/proto/cryptopump/v1;cryptopumpv1b\x06proto3"
+
+var (
+	file_proto_cryptopump_v1_cryptopump_proto_rawDescOnce sync.Once
+	file_proto_cryptopump_v1_cryptopump_proto_rawDescData []byte
+)
+
+func file_proto_cryptopump_v1_cryptopump_proto_rawDescGZIP() []byte {
+	file_proto_cryptopump_v1_cryptopump_proto_rawDescOnce.Do(func() {
+		file_proto_cryptopump_v1_cryptopump_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_cryptopump_v1_cryptopump_proto_rawDesc), len(file_proto_cryptopump_v1_cryptopump_proto_rawDesc)))
+	})
+	return file_proto_cryptopump_v1_cryptopump_proto_rawDescData
+}
+
+var file_proto_cryptopump_v1_cryptopump_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_proto_cryptopump_v1_cryptopump_proto_goTypes = []any{
+	(*Session)(nil),              // 0: cryptopump.v1.Session
+	(*Order)(nil),                // 1: cryptopump.v1.Order
+	(*PendingAction)(nil),        // 2: cryptopump.v1.PendingAction
+	(*ListSessionsRequest)(nil),  // 3: cryptopump.v1.ListSessionsRequest
+	(*ListSessionsResponse)(nil), // 4: cryptopump.v1.ListSessionsResponse
+	(*ListOrdersRequest)(nil),    // 5: cryptopump.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),   // 6: cryptopump.v1.ListOrdersResponse
+	(*GetProfitRequest)(nil),     // 7: cryptopump.v1.GetProfitRequest
+	(*GetProfitResponse)(nil),    // 8: cryptopump.v1.GetProfitResponse
+	(*StartSessionRequest)(nil),  // 9: cryptopump.v1.StartSessionRequest
+	(*StopSessionRequest)(nil),   // 10: cryptopump.v1.StopSessionRequest
+	(*BuyRequest)(nil),           // 11: cryptopump.v1.BuyRequest
+	(*SellRequest)(nil),          // 12: cryptopump.v1.SellRequest
+	(*PendingActionRequest)(nil), // 13: cryptopump.v1.PendingActionRequest
+	(*ActionResponse)(nil),       // 14: cryptopump.v1.ActionResponse
+	(*UpdateConfigRequest)(nil),  // 15: cryptopump.v1.UpdateConfigRequest
+	(*UpdateConfigResponse)(nil), // 16: cryptopump.v1.UpdateConfigResponse
+	(*StreamMarketRequest)(nil),  // 17: cryptopump.v1.StreamMarketRequest
+	(*MarketEvent)(nil),          // 18: cryptopump.v1.MarketEvent
+	(*StreamOrdersRequest)(nil),  // 19: cryptopump.v1.StreamOrdersRequest
+	(*OrderEvent)(nil),           // 20: cryptopump.v1.OrderEvent
+	nil,                          // 21: cryptopump.v1.UpdateConfigRequest.ConfigEntry
+	nil,                          // 22: cryptopump.v1.UpdateConfigResponse.ConfigEntry
+}
+var file_proto_cryptopump_v1_cryptopump_proto_depIdxs = []int32{
+	0,  // 0: cryptopump.v1.ListSessionsResponse.sessions:type_name -> cryptopump.v1.Session
+	1,  // 1: cryptopump.v1.ListOrdersResponse.orders:type_name -> cryptopump.v1.Order
+	2,  // 2: cryptopump.v1.ActionResponse.pending_action:type_name -> cryptopump.v1.PendingAction
+	21, // 3: cryptopump.v1.UpdateConfigRequest.config:type_name -> cryptopump.v1.UpdateConfigRequest.ConfigEntry
+	22, // 4: cryptopump.v1.UpdateConfigResponse.config:type_name -> cryptopump.v1.UpdateConfigResponse.ConfigEntry
+	1,  // 5: cryptopump.v1.OrderEvent.order:type_name -> cryptopump.v1.Order
+	3,  // 6: cryptopump.v1.ControlPlane.ListSessions:input_type -> cryptopump.v1.ListSessionsRequest
+	5,  // 7: cryptopump.v1.ControlPlane.ListOrders:input_type -> cryptopump.v1.ListOrdersRequest
+	7,  // 8: cryptopump.v1.ControlPlane.GetProfit:input_type -> cryptopump.v1.GetProfitRequest
+	9,  // 9: cryptopump.v1.ControlPlane.StartSession:input_type -> cryptopump.v1.StartSessionRequest
+	10, // 10: cryptopump.v1.ControlPlane.StopSession:input_type -> cryptopump.v1.StopSessionRequest
+	11, // 11: cryptopump.v1.ControlPlane.Buy:input_type -> cryptopump.v1.BuyRequest
+	12, // 12: cryptopump.v1.ControlPlane.Sell:input_type -> cryptopump.v1.SellRequest
+	13, // 13: cryptopump.v1.ControlPlane.ConfirmSell:input_type -> cryptopump.v1.PendingActionRequest
+	13, // 14: cryptopump.v1.ControlPlane.RejectSell:input_type -> cryptopump.v1.PendingActionRequest
+	15, // 15: cryptopump.v1.ControlPlane.UpdateConfig:input_type -> cryptopump.v1.UpdateConfigRequest
+	17, // 16: cryptopump.v1.ControlPlane.StreamMarket:input_type -> cryptopump.v1.StreamMarketRequest
+	19, // 17: cryptopump.v1.ControlPlane.StreamOrders:input_type -> cryptopump.v1.StreamOrdersRequest
+	4,  // 18: cryptopump.v1.ControlPlane.ListSessions:output_type -> cryptopump.v1.ListSessionsResponse
+	6,  // 19: cryptopump.v1.ControlPlane.ListOrders:output_type -> cryptopump.v1.ListOrdersResponse
+	8,  // 20: cryptopump.v1.ControlPlane.GetProfit:output_type -> cryptopump.v1.GetProfitResponse
+	14, // 21: cryptopump.v1.ControlPlane.StartSession:output_type -> cryptopump.v1.ActionResponse
+	14, // 22: cryptopump.v1.ControlPlane.StopSession:output_type -> cryptopump.v1.ActionResponse
+	14, // 23: cryptopump.v1.ControlPlane.Buy:output_type -> cryptopump.v1.ActionResponse
+	14, // 24: cryptopump.v1.ControlPlane.Sell:output_type -> cryptopump.v1.ActionResponse
+	14, // 25: cryptopump.v1.ControlPlane.ConfirmSell:output_type -> cryptopump.v1.ActionResponse
+	14, // 26: cryptopump.v1.ControlPlane.RejectSell:output_type -> cryptopump.v1.ActionResponse
+	16, // 27: cryptopump.v1.ControlPlane.UpdateConfig:output_type -> cryptopump.v1.UpdateConfigResponse
+	18, // 28: cryptopump.v1.ControlPlane.StreamMarket:output_type -> cryptopump.v1.MarketEvent
+	20, // 29: cryptopump.v1.ControlPlane.StreamOrders:output_type -> cryptopump.v1.OrderEvent
+	18, // [18:30] is the sub-list for method output_type
+	6,  // [6:18] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_proto_cryptopump_v1_cryptopump_proto_init() }
+func file_proto_cryptopump_v1_cryptopump_proto_init() {
+	if File_proto_cryptopump_v1_cryptopump_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_cryptopump_v1_cryptopump_proto_rawDesc), len(file_proto_cryptopump_v1_cryptopump_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   23,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_cryptopump_v1_cryptopump_proto_goTypes,
+		DependencyIndexes: file_proto_cryptopump_v1_cryptopump_proto_depIdxs,
+		MessageInfos:      file_proto_cryptopump_v1_cryptopump_proto_msgTypes,
+	}.Build()
+	File_proto_cryptopump_v1_cryptopump_proto = out.File
+	file_proto_cryptopump_v1_cryptopump_proto_goTypes = nil
+	file_proto_cryptopump_v1_cryptopump_proto_depIdxs = nil
+}
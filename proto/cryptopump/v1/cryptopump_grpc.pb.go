@@ -0,0 +1,579 @@
+// Control-plane API for CryptoPump, mirroring the REST API under /api/v1/ with streaming
+// endpoints for live market and order events.
+//
+// Authentication uses the REST API tokens created in Admin, sent as the metadata
+// "authorization: Bearer <token>", and the same roles: viewer for reads and streams,
+// trader for control actions and admin for configuration updates.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/cryptopump/v1/cryptopump.proto
+
+package cryptopumpv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ControlPlane_ListSessions_FullMethodName = "/cryptopump.v1.ControlPlane/ListSessions"
+	ControlPlane_ListOrders_FullMethodName   = "/cryptopump.v1.ControlPlane/ListOrders"
+	ControlPlane_GetProfit_FullMethodName    = "/cryptopump.v1.ControlPlane/GetProfit"
+	ControlPlane_StartSession_FullMethodName = "/cryptopump.v1.ControlPlane/StartSession"
+	ControlPlane_StopSession_FullMethodName  = "/cryptopump.v1.ControlPlane/StopSession"
+	ControlPlane_Buy_FullMethodName          = "/cryptopump.v1.ControlPlane/Buy"
+	ControlPlane_Sell_FullMethodName         = "/cryptopump.v1.ControlPlane/Sell"
+	ControlPlane_ConfirmSell_FullMethodName  = "/cryptopump.v1.ControlPlane/ConfirmSell"
+	ControlPlane_RejectSell_FullMethodName   = "/cryptopump.v1.ControlPlane/RejectSell"
+	ControlPlane_UpdateConfig_FullMethodName = "/cryptopump.v1.ControlPlane/UpdateConfig"
+	ControlPlane_StreamMarket_FullMethodName = "/cryptopump.v1.ControlPlane/StreamMarket"
+	ControlPlane_StreamOrders_FullMethodName = "/cryptopump.v1.ControlPlane/StreamOrders"
+)
+
+// ControlPlaneClient is the client API for ControlPlane service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ControlPlaneClient interface {
+	// List all sessions.
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// Open transactions of the thread running in this session.
+	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
+	// Profit across all threads, and for the thread running in this session.
+	GetProfit(ctx context.Context, in *GetProfitRequest, opts ...grpc.CallOption) (*GetProfitResponse, error)
+	// Start the bot on the trading pair previously set.
+	StartSession(ctx context.Context, in *StartSessionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	// Stop the bot without selling the open transactions.
+	StopSession(ctx context.Context, in *StopSessionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	// Buy market.
+	Buy(ctx context.Context, in *BuyRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	// Sell market an order, or the top order when order_id is 0. Sales above Sell Confirm
+	// Notional return a pending action to be confirmed or rejected.
+	Sell(ctx context.Context, in *SellRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	// Confirm or reject the manual sale pending confirmation.
+	ConfirmSell(ctx context.Context, in *PendingActionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	RejectSell(ctx context.Context, in *PendingActionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	// Update the session configuration. Unknown keys are rejected, and exchangename,
+	// newsession, symbol, symbol_fiat and testnet cannot change while the thread is running.
+	UpdateConfig(ctx context.Context, in *UpdateConfigRequest, opts ...grpc.CallOption) (*UpdateConfigResponse, error)
+	// Live market data of the thread running in this session, one event per price update.
+	StreamMarket(ctx context.Context, in *StreamMarketRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MarketEvent], error)
+	// Live order events of the thread running in this session.
+	StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderEvent], error)
+}
+
+type controlPlaneClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControlPlaneClient(cc grpc.ClientConnInterface) ControlPlaneClient {
+	return &controlPlaneClient{cc}
+}
+
+func (c *controlPlaneClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrdersResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_ListOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) GetProfit(ctx context.Context, in *GetProfitRequest, opts ...grpc.CallOption) (*GetProfitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProfitResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_GetProfit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) StartSession(ctx context.Context, in *StartSessionRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_StartSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) StopSession(ctx context.Context, in *StopSessionRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_StopSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) Buy(ctx context.Context, in *BuyRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_Buy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) Sell(ctx context.Context, in *SellRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_Sell_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) ConfirmSell(ctx context.Context, in *PendingActionRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_ConfirmSell_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) RejectSell(ctx context.Context, in *PendingActionRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_RejectSell_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) UpdateConfig(ctx context.Context, in *UpdateConfigRequest, opts ...grpc.CallOption) (*UpdateConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateConfigResponse)
+	err := c.cc.Invoke(ctx, ControlPlane_UpdateConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlPlaneClient) StreamMarket(ctx context.Context, in *StreamMarketRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MarketEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ControlPlane_ServiceDesc.Streams[0], ControlPlane_StreamMarket_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamMarketRequest, MarketEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlPlane_StreamMarketClient = grpc.ServerStreamingClient[MarketEvent]
+
+func (c *controlPlaneClient) StreamOrders(ctx context.Context, in *StreamOrdersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ControlPlane_ServiceDesc.Streams[1], ControlPlane_StreamOrders_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamOrdersRequest, OrderEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlPlane_StreamOrdersClient = grpc.ServerStreamingClient[OrderEvent]
+
+// ControlPlaneServer is the server API for ControlPlane service.
+// All implementations must embed UnimplementedControlPlaneServer
+// for forward compatibility.
+type ControlPlaneServer interface {
+	// List all sessions.
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// Open transactions of the thread running in this session.
+	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
+	// Profit across all threads, and for the thread running in this session.
+	GetProfit(context.Context, *GetProfitRequest) (*GetProfitResponse, error)
+	// Start the bot on the trading pair previously set.
+	StartSession(context.Context, *StartSessionRequest) (*ActionResponse, error)
+	// Stop the bot without selling the open transactions.
+	StopSession(context.Context, *StopSessionRequest) (*ActionResponse, error)
+	// Buy market.
+	Buy(context.Context, *BuyRequest) (*ActionResponse, error)
+	// Sell market an order, or the top order when order_id is 0. Sales above Sell Confirm
+	// Notional return a pending action to be confirmed or rejected.
+	Sell(context.Context, *SellRequest) (*ActionResponse, error)
+	// Confirm or reject the manual sale pending confirmation.
+	ConfirmSell(context.Context, *PendingActionRequest) (*ActionResponse, error)
+	RejectSell(context.Context, *PendingActionRequest) (*ActionResponse, error)
+	// Update the session configuration. Unknown keys are rejected, and exchangename,
+	// newsession, symbol, symbol_fiat and testnet cannot change while the thread is running.
+	UpdateConfig(context.Context, *UpdateConfigRequest) (*UpdateConfigResponse, error)
+	// Live market data of the thread running in this session, one event per price update.
+	StreamMarket(*StreamMarketRequest, grpc.ServerStreamingServer[MarketEvent]) error
+	// Live order events of the thread running in this session.
+	StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderEvent]) error
+	mustEmbedUnimplementedControlPlaneServer()
+}
+
+// UnimplementedControlPlaneServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedControlPlaneServer struct{}
+
+func (UnimplementedControlPlaneServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedControlPlaneServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrders not implemented")
+}
+func (UnimplementedControlPlaneServer) GetProfit(context.Context, *GetProfitRequest) (*GetProfitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfit not implemented")
+}
+func (UnimplementedControlPlaneServer) StartSession(context.Context, *StartSessionRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartSession not implemented")
+}
+func (UnimplementedControlPlaneServer) StopSession(context.Context, *StopSessionRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopSession not implemented")
+}
+func (UnimplementedControlPlaneServer) Buy(context.Context, *BuyRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Buy not implemented")
+}
+func (UnimplementedControlPlaneServer) Sell(context.Context, *SellRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sell not implemented")
+}
+func (UnimplementedControlPlaneServer) ConfirmSell(context.Context, *PendingActionRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmSell not implemented")
+}
+func (UnimplementedControlPlaneServer) RejectSell(context.Context, *PendingActionRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectSell not implemented")
+}
+func (UnimplementedControlPlaneServer) UpdateConfig(context.Context, *UpdateConfigRequest) (*UpdateConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateConfig not implemented")
+}
+func (UnimplementedControlPlaneServer) StreamMarket(*StreamMarketRequest, grpc.ServerStreamingServer[MarketEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMarket not implemented")
+}
+func (UnimplementedControlPlaneServer) StreamOrders(*StreamOrdersRequest, grpc.ServerStreamingServer[OrderEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamOrders not implemented")
+}
+func (UnimplementedControlPlaneServer) mustEmbedUnimplementedControlPlaneServer() {}
+func (UnimplementedControlPlaneServer) testEmbeddedByValue()                      {}
+
+// UnsafeControlPlaneServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControlPlaneServer will
+// result in compilation errors.
+type UnsafeControlPlaneServer interface {
+	mustEmbedUnimplementedControlPlaneServer()
+}
+
+func RegisterControlPlaneServer(s grpc.ServiceRegistrar, srv ControlPlaneServer) {
+	// If the following call pancis, it indicates UnimplementedControlPlaneServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ControlPlane_ServiceDesc, srv)
+}
+
+func _ControlPlane_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).ListOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_ListOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).ListOrders(ctx, req.(*ListOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_GetProfit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).GetProfit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_GetProfit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).GetProfit(ctx, req.(*GetProfitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_StartSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).StartSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_StartSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).StartSession(ctx, req.(*StartSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_StopSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).StopSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_StopSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).StopSession(ctx, req.(*StopSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_Buy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BuyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).Buy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_Buy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).Buy(ctx, req.(*BuyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_Sell_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SellRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).Sell(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_Sell_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).Sell(ctx, req.(*SellRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_ConfirmSell_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).ConfirmSell(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_ConfirmSell_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).ConfirmSell(ctx, req.(*PendingActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_RejectSell_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).RejectSell(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_RejectSell_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).RejectSell(ctx, req.(*PendingActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_UpdateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlPlaneServer).UpdateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlPlane_UpdateConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlPlaneServer).UpdateConfig(ctx, req.(*UpdateConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlPlane_StreamMarket_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamMarketRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlPlaneServer).StreamMarket(m, &grpc.GenericServerStream[StreamMarketRequest, MarketEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlPlane_StreamMarketServer = grpc.ServerStreamingServer[MarketEvent]
+
+func _ControlPlane_StreamOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamOrdersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlPlaneServer).StreamOrders(m, &grpc.GenericServerStream[StreamOrdersRequest, OrderEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ControlPlane_StreamOrdersServer = grpc.ServerStreamingServer[OrderEvent]
+
+// ControlPlane_ServiceDesc is the grpc.ServiceDesc for ControlPlane service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ControlPlane_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cryptopump.v1.ControlPlane",
+	HandlerType: (*ControlPlaneServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _ControlPlane_ListSessions_Handler,
+		},
+		{
+			MethodName: "ListOrders",
+			Handler:    _ControlPlane_ListOrders_Handler,
+		},
+		{
+			MethodName: "GetProfit",
+			Handler:    _ControlPlane_GetProfit_Handler,
+		},
+		{
+			MethodName: "StartSession",
+			Handler:    _ControlPlane_StartSession_Handler,
+		},
+		{
+			MethodName: "StopSession",
+			Handler:    _ControlPlane_StopSession_Handler,
+		},
+		{
+			MethodName: "Buy",
+			Handler:    _ControlPlane_Buy_Handler,
+		},
+		{
+			MethodName: "Sell",
+			Handler:    _ControlPlane_Sell_Handler,
+		},
+		{
+			MethodName: "ConfirmSell",
+			Handler:    _ControlPlane_ConfirmSell_Handler,
+		},
+		{
+			MethodName: "RejectSell",
+			Handler:    _ControlPlane_RejectSell_Handler,
+		},
+		{
+			MethodName: "UpdateConfig",
+			Handler:    _ControlPlane_UpdateConfig_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMarket",
+			Handler:       _ControlPlane_StreamMarket_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamOrders",
+			Handler:       _ControlPlane_StreamOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/cryptopump/v1/cryptopump.proto",
+}
@@ -0,0 +1,206 @@
+package push
+
+/* This package implements the Pushover and ntfy mobile push notification channels. Notifications are pushed to the
+Pushover user and to the ntfy topic configured in the global configuration, alerts (stoploss sales, exchange order
+errors and risk kill switches) with high priority and at most once per critical interval for the same alert key.
+Messages are posted in the background so notifications never delay trading. */
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const criticalInterval = 10 * time.Minute /* Minimum time between two pushes of the same critical alert */
+
+const ntfyDefaultURL = "https://ntfy.sh" /* ntfy server when NtfyURL is empty */
+
+var pushoverURL = "https://api.pushover.net/1/messages.json" /* Pushover message API */
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+var (
+	mutex sync.Mutex
+	sent  = make(map[string]time.Time) /* Last time each critical alert was pushed */
+)
+
+// ErrNotConfigured is returned when neither Pushover nor ntfy are configured
+var ErrNotConfigured = errors.New("Pushover token and user key or ntfy topic required")
+
+// Send push a notification to Pushover and ntfy, with high priority when high. Alerts with a key are skipped when
+// the same key was pushed within the last 10 minutes.
+func Send(
+	configData *types.Config,
+	sessionData *types.Session,
+	key string,
+	title string,
+	text string,
+	high bool) {
+
+	if !Enabled(configData) || (key != "" && !allow(key, time.Now())) {
+
+		return
+
+	}
+
+	go func() {
+
+		global := configData.ConfigGlobal
+
+		for _, err := range []error{pushover(global, title, text, high), ntfy(global, title, text, high)} {
+
+			if err != nil && err != ErrNotConfigured {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   nil,
+					Market:   nil,
+					Session:  sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+			}
+
+		}
+
+	}()
+
+}
+
+// Enabled return true when Pushover or ntfy are configured
+func Enabled(configData *types.Config) bool {
+
+	global := configData.ConfigGlobal
+
+	return global != nil &&
+		((global.PushoverToken != "" && global.PushoverUser != "") || global.NtfyTopic != "")
+
+}
+
+/* Return true and record the time when key was not pushed within criticalInterval before now */
+func allow(
+	key string,
+	now time.Time) bool {
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if t, ok := sent[key]; ok && now.Sub(t) < criticalInterval {
+
+		return false
+
+	}
+
+	sent[key] = now
+
+	return true
+
+}
+
+/* Post a message to the Pushover user */
+func pushover(
+	global *types.ConfigGlobal,
+	title string,
+	text string,
+	high bool) (err error) {
+
+	var response *http.Response
+
+	priority := "0"
+	if high {
+		priority = "1" /* High priority, bypasses the user quiet hours */
+	}
+
+	if global.PushoverToken == "" || global.PushoverUser == "" {
+
+		return ErrNotConfigured
+
+	}
+
+	if response, err = client.PostForm(pushoverURL, url.Values{
+		"token":    {global.PushoverToken},
+		"user":     {global.PushoverUser},
+		"title":    {title},
+		"message":  {text},
+		"priority": {priority},
+	}); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+
+		return errors.New("Pushover returned " + response.Status)
+
+	}
+
+	return nil
+
+}
+
+/* Publish a message to the ntfy topic */
+func ntfy(
+	global *types.ConfigGlobal,
+	title string,
+	text string,
+	high bool) (err error) {
+
+	var request *http.Request
+	var response *http.Response
+
+	if global.NtfyTopic == "" {
+
+		return ErrNotConfigured
+
+	}
+
+	server := strings.TrimRight(global.NtfyURL, "/")
+	if server == "" {
+		server = ntfyDefaultURL
+	}
+
+	if request, err = http.NewRequest(http.MethodPost, server+"/"+url.PathEscape(global.NtfyTopic), strings.NewReader(text)); err != nil {
+
+		return err
+
+	}
+
+	request.Header.Set("Title", title)
+
+	if high {
+		request.Header.Set("Priority", "high")
+		request.Header.Set("Tags", "warning")
+	}
+
+	if global.NtfyToken != "" {
+		request.Header.Set("Authorization", "Bearer "+global.NtfyToken)
+	}
+
+	if response, err = client.Do(request); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+
+		return errors.New("ntfy returned " + response.Status)
+
+	}
+
+	return nil
+
+}
@@ -0,0 +1,104 @@
+package push
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		global *types.ConfigGlobal
+		want   bool
+	}{
+		{name: "pushover", global: &types.ConfigGlobal{PushoverToken: "azGDORePK8gMaC0QOYAMyEEuzJnyUi", PushoverUser: "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"}, want: true},
+		{name: "pushover without user", global: &types.ConfigGlobal{PushoverToken: "azGDORePK8gMaC0QOYAMyEEuzJnyUi"}, want: false},
+		{name: "ntfy", global: &types.ConfigGlobal{NtfyTopic: "cryptopump-alerts"}, want: true},
+		{name: "not configured", global: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(&types.Config{ConfigGlobal: tt.global}); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_allow(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+
+	if !allow("test-stoploss", now) {
+		t.Errorf("allow() first alert = false, want true")
+	}
+	if allow("test-stoploss", now.Add(9*time.Minute)) {
+		t.Errorf("allow() within 10 minutes = true, want false")
+	}
+	if !allow("test-stoploss", now.Add(10*time.Minute)) {
+		t.Errorf("allow() after 10 minutes = false, want true")
+	}
+}
+
+func Test_pushover(t *testing.T) {
+	var form map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.PostForm
+		if r.PostForm.Get("token") != "azGDORePK8gMaC0QOYAMyEEuzJnyUi" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	saved := pushoverURL
+	pushoverURL = server.URL
+	defer func() { pushoverURL = saved }()
+
+	if err := pushover(&types.ConfigGlobal{}, "title", "text", true); err != ErrNotConfigured {
+		t.Errorf("pushover() error = %v, want %v", err, ErrNotConfigured)
+	}
+
+	global := &types.ConfigGlobal{PushoverToken: "azGDORePK8gMaC0QOYAMyEEuzJnyUi", PushoverUser: "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"}
+	if err := pushover(global, "Stoploss hit BTCUSDT", "BTCUSDT 48000 is 5.00% below order 1", true); err != nil {
+		t.Fatalf("pushover() error = %v", err)
+	}
+	if form["user"][0] != "uQiRzpo4DXghDmr9QzzfQu27cmVRsG" || form["title"][0] != "Stoploss hit BTCUSDT" || form["priority"][0] != "1" {
+		t.Errorf("pushover() form = %v", form)
+	}
+
+	if err := pushover(global, "BUY BTCUSDT", "BUY BTCUSDT 0.002100 @ 57600.0000", false); err != nil || form["priority"][0] != "0" {
+		t.Errorf("pushover() normal priority error = %v, form = %v", err, form)
+	}
+
+	global.PushoverToken = "invalid"
+	if err := pushover(global, "title", "text", true); err == nil {
+		t.Errorf("pushover() with an invalid token error = nil, want 400")
+	}
+}
+
+func Test_ntfy(t *testing.T) {
+	var path, title, priority, authorization, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		path, title, priority, authorization, body = r.URL.Path, r.Header.Get("Title"), r.Header.Get("Priority"), r.Header.Get("Authorization"), string(data)
+	}))
+	defer server.Close()
+
+	if err := ntfy(&types.ConfigGlobal{NtfyURL: server.URL}, "title", "text", true); err != ErrNotConfigured {
+		t.Errorf("ntfy() error = %v, want %v", err, ErrNotConfigured)
+	}
+
+	if err := ntfy(&types.ConfigGlobal{NtfyURL: server.URL + "/", NtfyTopic: "cryptopump-alerts", NtfyToken: "tk_secret"}, "Exchange error BTCUSDT", "SELL order failed", true); err != nil {
+		t.Fatalf("ntfy() error = %v", err)
+	}
+	if path != "/cryptopump-alerts" || title != "Exchange error BTCUSDT" || priority != "high" || authorization != "Bearer tk_secret" || body != "SELL order failed" {
+		t.Errorf("ntfy() path = %v, title = %v, priority = %v, authorization = %v, body = %v", path, title, priority, authorization, body)
+	}
+}
@@ -0,0 +1,286 @@
+package rebalancer
+
+/* This package implements the portfolio rebalancing strategy mode. Instead of trading
+sessionData.Symbol the thread keeps target weights across a basket of assets valued in
+configData.SymbolFiat, trading only when an asset weight drifts outside configData.RebalanceBand. */
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const minTradeFiat = 10 /* Exchange minimum notional value for an order in fiat */
+
+/* trade define a rebalance order for asset against fiat */
+type trade struct {
+	Asset    string  /* Asset to be bought or sold */
+	Side     string  /* BUY or SELL */
+	Quantity float64 /* Order quantity in fiat */
+}
+
+// Run value the basket of assets defined in configData.RebalanceWeights and place
+// market orders for the assets that drifted from target more than configData.RebalanceBand
+func Run(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+	var weights map[string]float64
+	var balances map[string]float64
+
+	if !configData.Rebalance {
+
+		return
+
+	}
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if weights, err = parseWeights(configData.RebalanceWeights); err != nil {
+
+		return
+
+	}
+
+	if balances, err = exchange.GetBalances(configData, sessionData); err != nil {
+
+		return
+
+	}
+
+	values := make(map[string]float64)
+
+	for asset := range weights {
+
+		if asset == configData.SymbolFiat {
+
+			values[asset] = balances[asset]
+			continue
+
+		}
+
+		var price float64
+
+		if price, err = exchange.GetSymbolPrice(configData, sessionData, asset+configData.SymbolFiat); err != nil {
+
+			return
+
+		}
+
+		values[asset] = balances[asset] * price
+
+	}
+
+	for _, t := range calculateTrades(weights, values, configData.SymbolFiat, configData.RebalanceBand) {
+
+		symbol := t.Asset + configData.SymbolFiat
+
+		if configData.DryRun {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  fmt.Sprintf("REBALANCEDRYRUN %s %s %.2f", t.Side, symbol, t.Quantity),
+				LogLevel: "InfoLevel",
+			}.Do()
+
+			continue
+
+		}
+
+		var order *types.Order
+
+		if order, err = exchange.QuoteOrder(configData, sessionData, symbol, t.Side, strconv.FormatFloat(t.Quantity, 'f', 2, 64)); err != nil {
+
+			return
+
+		}
+
+		orderPrice := order.CumulativeQuoteQuantity / order.ExecutedQuantity
+
+		if math.IsNaN(orderPrice) || math.IsInf(orderPrice, 0) {
+
+			orderPrice = 0
+
+		}
+
+		if err = mysql.SaveOrder(sessionData, order, 0, orderPrice); err != nil {
+
+			return
+
+		}
+
+		if err = mysql.UpdateOrderType(sessionData, order.OrderID, "REBALANCE"); err != nil {
+
+			return
+
+		}
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    order,
+			Message:  fmt.Sprintf("REBALANCE %s %s %.2f @ %.4f", t.Side, symbol, t.Quantity, orderPrice),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Parse target weights defined as ASSET:weight separated by commas (e.g. BTC:0.5,ETH:0.3,USDT:0.2) */
+func parseWeights(s string) (weights map[string]float64, err error) {
+
+	var total float64
+
+	weights = make(map[string]float64)
+
+	for _, item := range strings.Split(s, ",") {
+
+		if strings.TrimSpace(item) == "" {
+
+			continue
+
+		}
+
+		pair := strings.Split(item, ":")
+
+		if len(pair) != 2 {
+
+			return nil, errors.New("Invalid rebalance weight " + item)
+
+		}
+
+		asset := strings.ToUpper(strings.TrimSpace(pair[0]))
+
+		var weight float64
+
+		if weight, err = strconv.ParseFloat(strings.TrimSpace(pair[1]), 64); err != nil || weight < 0 || weight > 1 {
+
+			return nil, errors.New("Invalid rebalance weight " + item)
+
+		}
+
+		weights[asset] = weight
+		total += weight
+
+	}
+
+	if len(weights) == 0 {
+
+		return nil, errors.New("Rebalance weights not defined")
+
+	}
+
+	if math.Abs(total-1) > 0.001 {
+
+		return nil, errors.New("Rebalance weights must add up to 1")
+
+	}
+
+	return weights, nil
+
+}
+
+/* Calculate the orders required to bring assets outside band back to their target weight, SELL orders first so that fiat is available for BUY orders */
+func calculateTrades(
+	weights map[string]float64,
+	values map[string]float64,
+	fiat string,
+	band float64) (trades []trade) {
+
+	var total float64
+
+	for asset := range weights {
+
+		total += values[asset]
+
+	}
+
+	if total == 0 {
+
+		return nil
+
+	}
+
+	for asset, weight := range weights {
+
+		if asset == fiat {
+
+			continue
+
+		}
+
+		drift := values[asset]/total - weight
+
+		if math.Abs(drift) <= band {
+
+			continue
+
+		}
+
+		quantity := math.Abs(drift) * total
+
+		if quantity < minTradeFiat {
+
+			continue
+
+		}
+
+		side := "BUY"
+		if drift > 0 {
+			side = "SELL"
+		}
+
+		trades = append(trades, trade{
+			Asset:    asset,
+			Side:     side,
+			Quantity: quantity,
+		})
+
+	}
+
+	sort.Slice(trades, func(i, j int) bool {
+		if trades[i].Side != trades[j].Side {
+			return trades[i].Side == "SELL"
+		}
+		return trades[i].Asset < trades[j].Asset
+	})
+
+	return trades
+
+}
+
+// ValidateWeights check that target weights are defined as ASSET:weight separated by commas and add up to 1
+func ValidateWeights(s string) error {
+
+	_, err := parseWeights(s)
+
+	return err
+
+}
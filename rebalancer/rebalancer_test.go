@@ -0,0 +1,134 @@
+package rebalancer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseWeights(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]float64
+		wantErr bool
+	}{
+		{
+			name: "success",
+			args: args{
+				s: "btc:0.5, ETH:0.3,USDT:0.2",
+			},
+			want:    map[string]float64{"BTC": 0.5, "ETH": 0.3, "USDT": 0.2},
+			wantErr: false,
+		},
+		{
+			name: "sum",
+			args: args{
+				s: "BTC:0.5,USDT:0.2",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid",
+			args: args{
+				s: "BTC0.5,USDT:0.5",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "empty",
+			args: args{
+				s: "",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWeights(tt.args.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseWeights() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseWeights() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_calculateTrades(t *testing.T) {
+	type args struct {
+		weights map[string]float64
+		values  map[string]float64
+		fiat    string
+		band    float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want []trade
+	}{
+		{
+			name: "inside band",
+			args: args{
+				weights: map[string]float64{"BTC": 0.5, "ETH": 0.3, "USDT": 0.2},
+				values:  map[string]float64{"BTC": 520, "ETH": 290, "USDT": 190},
+				fiat:    "USDT",
+				band:    0.05,
+			},
+			want: nil,
+		},
+		{
+			name: "drift",
+			args: args{
+				weights: map[string]float64{"BTC": 0.5, "ETH": 0.3, "USDT": 0.2},
+				values:  map[string]float64{"BTC": 600, "ETH": 200, "USDT": 200},
+				fiat:    "USDT",
+				band:    0.05,
+			},
+			want: []trade{
+				{Asset: "BTC", Side: "SELL", Quantity: 100},
+				{Asset: "ETH", Side: "BUY", Quantity: 100},
+			},
+		},
+		{
+			name: "below minimum notional",
+			args: args{
+				weights: map[string]float64{"BTC": 0.5, "USDT": 0.5},
+				values:  map[string]float64{"BTC": 12, "USDT": 8},
+				fiat:    "USDT",
+				band:    0.05,
+			},
+			want: nil,
+		},
+		{
+			name: "empty",
+			args: args{
+				weights: map[string]float64{"BTC": 0.5, "USDT": 0.5},
+				values:  map[string]float64{},
+				fiat:    "USDT",
+				band:    0.05,
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateTrades(tt.args.weights, tt.args.values, tt.args.fiat, tt.args.band)
+			if len(got) != len(tt.want) {
+				t.Fatalf("calculateTrades() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Asset != tt.want[i].Asset || got[i].Side != tt.want[i].Side || got[i].Quantity-tt.want[i].Quantity > 1e-9 || tt.want[i].Quantity-got[i].Quantity > 1e-9 {
+					t.Errorf("calculateTrades() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,561 @@
+package reload
+
+/* This package implements the hot-reload of the configuration. The configuration files in ./config are watched with
+fsnotify, and checked every 60 seconds when file events are not delivered (i.e. some network or container volumes).
+On a change the configuration of each thread is read again and validated: a valid configuration is published as the
+snapshot applied by the next trading cycle before any decision, so running threads use the new parameters without a
+restart and a cycle never runs with a mix of old and new values. An invalid configuration is rejected with its errors
+logged and notified, and the previous valid configuration stays in effect until the files are corrected. The
+structural parameters (symbol, exchange, testnet, dry run and rebalance mode) only apply after a restart. */
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/risk"
+	"github.com/aleibovici/cryptopump/scheduler"
+	"github.com/aleibovici/cryptopump/types"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	configPath = "./config"             /* Configuration files watched */
+	debounce   = 500 * time.Millisecond /* Wait for the writes of a file save to complete */
+)
+
+/* Parameters that apply after a restart, kept from the running configuration */
+var structural = []string{"ThreadID", "Symbol", "SymbolFiat", "ExchangeName", "TestNet", "DryRun", "NewSession", "Rebalance", "RebalanceWeights"}
+
+/* Reload state of a thread */
+type entry struct {
+	viperData *types.ViperData
+	config    *types.Config     /* Last valid configuration, nil until loaded */
+	values    map[string]string /* Values of the configuration files of the last valid configuration */
+	rejected  string            /* Errors of the last rejected configuration, logged once */
+	pending   string            /* Structural changes of the last configuration, logged once */
+}
+
+var watched = struct {
+	sync.Mutex
+	entries map[*types.Session]*entry
+}{
+	entries: make(map[*types.Session]*entry),
+}
+
+var watching sync.Once /* The configuration files are watched once for the threads of the process */
+
+// Watch reload the configuration of the thread when the configuration files change
+func Watch(
+	viperData *types.ViperData,
+	sessionData *types.Session) {
+
+	watched.Lock()
+	watched.entries[sessionData] = &entry{viperData: viperData}
+	watched.Unlock()
+
+	_ = Load(viperData, sessionData)
+
+	watching.Do(func() {
+
+		go watch(configPath)
+
+	})
+
+}
+
+// Load reload the configuration of the thread, validate it and publish it for the next trading cycle. An invalid
+// configuration is rejected and the previous valid configuration stays in effect.
+func Load(
+	viperData *types.ViperData,
+	sessionData *types.Session) (err error) {
+
+	configData := functions.GetConfigData(viperData, sessionData)
+	values := fileValues(viperData)
+
+	watched.Lock()
+
+	e, ok := watched.entries[sessionData]
+	if !ok {
+		e = &entry{viperData: viperData}
+		watched.entries[sessionData] = e
+	}
+
+	previous, previousValues, rejected, pending := e.config, e.values, e.rejected, e.pending
+
+	watched.Unlock()
+
+	errs := append(Validate(configData), numeric(previousValues, values)...)
+
+	if len(errs) > 0 {
+
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			messages[i] = err.Error()
+		}
+
+		err = errors.New(strings.Join(messages, ", "))
+
+		if err.Error() != rejected { /* Logged and notified once until the files change */
+
+			reject(configData, sessionData, err)
+
+		}
+
+		watched.Lock()
+		e.rejected = err.Error()
+		watched.Unlock()
+
+		return err
+
+	}
+
+	if previous != nil {
+
+		changes, restart := Diff(previous, configData)
+
+		if len(changes) > 0 {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  "Configuration reloaded - " + strings.Join(changes, ", "),
+				LogLevel: "InfoLevel",
+			}.Do()
+
+		}
+
+		if len(restart) > 0 && strings.Join(restart, ", ") != pending {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  "Configuration changes applied after a restart - " + strings.Join(restart, ", "),
+				LogLevel: "InfoLevel",
+			}.Do()
+
+		}
+
+		pending = strings.Join(restart, ", ")
+
+		keep(configData, previous) /* Structural parameters apply after a restart */
+
+	}
+
+	watched.Lock()
+	e.config, e.values, e.rejected, e.pending = configData, values, "", pending
+	watched.Unlock()
+
+	risk.WatchLimits(configData, sessionData) /* Risk limits of the valid configuration */
+
+	return nil
+
+}
+
+// Apply the last valid configuration published by Load to configData of a trading cycle, except the structural
+// parameters that apply after a restart
+func Apply(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	watched.Lock()
+
+	e, ok := watched.entries[sessionData]
+	if !ok || e.config == nil {
+
+		watched.Unlock()
+		return
+
+	}
+
+	valid := *e.config
+
+	watched.Unlock()
+
+	running := *configData
+	*configData = valid
+
+	keep(configData, &running)
+
+}
+
+// Config return a copy of the last valid configuration of the thread, read from the configuration files when none
+func Config(
+	viperData *types.ViperData,
+	sessionData *types.Session) *types.Config {
+
+	watched.Lock()
+
+	e, ok := watched.entries[sessionData]
+	if !ok || e.config == nil {
+
+		watched.Unlock()
+		return functions.GetConfigData(viperData, sessionData)
+
+	}
+
+	configData := *e.config
+
+	watched.Unlock()
+
+	return &configData
+
+}
+
+// Validate return the errors of the parameters of configData that can't be applied
+func Validate(configData *types.Config) (errs []error) {
+
+	value := reflect.ValueOf(configData).Elem()
+
+	for i := 0; i < value.NumField(); i++ {
+
+		field := value.Field(i)
+		name := value.Type().Field(i).Name
+
+		switch field.Kind() {
+		case reflect.Int, reflect.Int64:
+			if field.Int() < 0 {
+				errs = append(errs, fmt.Errorf("%s %d is negative", name, field.Int()))
+			}
+		case reflect.Float64:
+			if field.Float() < 0 {
+				errs = append(errs, fmt.Errorf("%s %v is negative", name, field.Float()))
+			}
+		}
+
+	}
+
+	for name, ratio := range map[string]float64{
+		"Stoploss":               configData.Stoploss,
+		"SellVolatilityStoploss": configData.SellVolatilityStoploss,
+		"SellTrailingDistance":   configData.SellTrailingDistance,
+	} {
+
+		if ratio >= 1 {
+			errs = append(errs, fmt.Errorf("%s %v is not a ratio below 1", name, ratio))
+		}
+
+	}
+
+	if configData.BuySizingFraction > 1 {
+		errs = append(errs, fmt.Errorf("BuySizingFraction %v is above 1", configData.BuySizingFraction))
+	}
+
+	switch strings.ToLower(configData.BuySizingMode) {
+	case "", "fixed", "fraction", "volatility", "kelly":
+	default:
+		errs = append(errs, fmt.Errorf("BuySizingMode %s is not fixed, fraction, volatility or kelly", configData.BuySizingMode))
+	}
+
+	if configData.TimeEnforce {
+
+		for name, clock := range map[string]string{"TimeStart": configData.TimeStart, "TimeStop": configData.TimeStop} {
+
+			if !isClock(clock) {
+				errs = append(errs, fmt.Errorf("%s %s is not a time (i.e. 04:00AM or 16:00)", name, clock))
+			}
+
+		}
+
+	}
+
+	for name, spec := range map[string]string{
+		"ScheduleStart":  configData.ScheduleStart,
+		"ScheduleStop":   configData.ScheduleStop,
+		"SchedulePause":  configData.SchedulePause,
+		"ScheduleResume": configData.ScheduleResume,
+	} {
+
+		if _, err := scheduler.ParseCron(spec); spec != "" && err != nil {
+			errs = append(errs, fmt.Errorf("%s %s is not a cron expression", name, spec))
+		}
+
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+
+	return errs
+
+}
+
+// Diff return the changed parameters from previous to current as "Name old -> new", and the changed structural
+// parameters that apply after a restart. The risk limits are logged by risk.WatchLimits.
+func Diff(
+	previous *types.Config,
+	current *types.Config) (changes []string, restart []string) {
+
+	oldValue := reflect.ValueOf(previous).Elem()
+	newValue := reflect.ValueOf(current).Elem()
+
+	limits := reflect.TypeOf(types.RiskLimits{})
+
+	for i := 0; i < oldValue.NumField(); i++ {
+
+		name := oldValue.Type().Field(i).Name
+
+		switch oldValue.Field(i).Kind() {
+		case reflect.Interface, reflect.Slice, reflect.Struct, reflect.Ptr, reflect.Map, reflect.Func, reflect.Chan:
+			continue
+		}
+
+		if _, ok := limits.FieldByName(name); ok {
+			continue
+		}
+
+		if oldValue.Field(i).Interface() == newValue.Field(i).Interface() {
+			continue
+		}
+
+		change := fmt.Sprintf("%s %v -> %v", name, oldValue.Field(i).Interface(), newValue.Field(i).Interface())
+
+		if isStructural(name) {
+			restart = append(restart, change)
+		} else {
+			changes = append(changes, change)
+		}
+
+	}
+
+	return changes, restart
+
+}
+
+/* Keep the structural parameters of the running configuration in configData */
+func keep(
+	configData *types.Config,
+	running *types.Config) {
+
+	value := reflect.ValueOf(configData).Elem()
+	runningValue := reflect.ValueOf(running).Elem()
+
+	for _, name := range structural {
+
+		value.FieldByName(name).Set(runningValue.FieldByName(name))
+
+	}
+
+}
+
+/* Return the errors of the values that were numbers in previous and are no longer numbers in current */
+func numeric(
+	previous map[string]string,
+	current map[string]string) (errs []error) {
+
+	for key, value := range current {
+
+		if before, ok := previous[key]; ok && isNumber(before) && !isNumber(value) {
+
+			errs = append(errs, fmt.Errorf("%s %q is not a number", key, value))
+
+		}
+
+	}
+
+	return errs
+
+}
+
+/* Return the values of the session and global configuration files, by key */
+func fileValues(viperData *types.ViperData) map[string]string {
+
+	values := make(map[string]string)
+
+	for key, value := range viperData.V1.GetStringMap("config") {
+
+		values["config."+key] = fmt.Sprint(value)
+
+	}
+
+	for key, value := range viperData.V2.GetStringMap("config_global") {
+
+		values["config_global."+key] = fmt.Sprint(value)
+
+	}
+
+	return values
+
+}
+
+/* Log and notify a rejected configuration */
+func reject(
+	configData *types.Config,
+	sessionData *types.Session,
+	err error) {
+
+	message := "Configuration rejected, keeping the previous values - " + err.Error()
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "DebugLevel",
+	}.Do()
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Warning,
+		Key:      "reload-" + sessionData.ThreadID,
+		Title:    "Configuration rejected",
+		Data: messages.Data{
+			ThreadID: sessionData.ThreadID,
+			Symbol:   sessionData.Symbol,
+			Message:  message,
+		},
+	}.Send(configData, sessionData)
+
+}
+
+/* Reload the configuration of the threads on the changes of the configuration files in path */
+func watch(path string) {
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = watcher.Add(path)
+	}
+
+	if err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  nil,
+			Order:    &types.Order{},
+			Message:  "Configuration files not watched, checked every 60 seconds - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return
+
+	}
+
+	defer watcher.Close()
+
+	var timer *time.Timer
+
+	for {
+
+		select {
+		case event, ok := <-watcher.Events:
+
+			if !ok {
+				return
+			}
+
+			if filepath.Ext(event.Name) != ".yml" || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+
+			if timer == nil {
+				timer = time.AfterFunc(debounce, loadAll)
+			} else {
+				timer.Reset(debounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+
+			if !ok {
+				return
+			}
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  nil,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+		}
+
+	}
+
+}
+
+/* Reload the configuration of every thread watched, forgetting the threads stopped */
+func loadAll() {
+
+	watched.Lock()
+
+	entries := make(map[*types.Session]*entry)
+
+	for sessionData, e := range watched.entries {
+
+		if sessionData.Stopping {
+
+			delete(watched.entries, sessionData)
+			continue
+
+		}
+
+		entries[sessionData] = e
+
+	}
+
+	watched.Unlock()
+
+	for sessionData, e := range entries {
+
+		_ = Load(e.viperData, sessionData)
+
+	}
+
+}
+
+/* Return true when name is a structural parameter */
+func isStructural(name string) bool {
+
+	for _, s := range structural {
+
+		if s == name {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Return true when value is a number */
+func isNumber(value string) bool {
+
+	_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+
+	return err == nil
+
+}
+
+/* Return true when clock is a time of day in Kitchen (3:04PM) or 24 hour (15:04) format */
+func isClock(clock string) bool {
+
+	if _, err := time.Parse(time.Kitchen, clock); err == nil {
+
+		return true
+
+	}
+
+	_, err := time.Parse("15:04", clock)
+
+	return err == nil
+
+}
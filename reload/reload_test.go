@@ -0,0 +1,97 @@
+package reload
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestValidate(t *testing.T) {
+	type args struct {
+		configData *types.Config
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "valid",
+			args: args{
+				configData: &types.Config{Stoploss: 0.05, BuySizingMode: "fraction", BuySizingFraction: 0.1, TimeEnforce: true, TimeStart: "04:00AM", TimeStop: "16:00"},
+			},
+			want: nil,
+		},
+		{
+			name: "invalid",
+			args: args{
+				configData: &types.Config{Stoploss: 1.5, BuyQuantityFiatUp: -10, BuySizingMode: "double", TimeEnforce: true, TimeStart: "25:00", TimeStop: "16:00"},
+			},
+			want: []string{
+				"BuyQuantityFiatUp -10 is negative",
+				"BuySizingMode double is not fixed, fraction, volatility or kelly",
+				"Stoploss 1.5 is not a ratio below 1",
+				"TimeStart 25:00 is not a time (i.e. 04:00AM or 16:00)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range Validate(tt.args.configData) {
+				got = append(got, err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+
+	previous := &types.Config{Symbol: "BTCUSDT", Stoploss: 0.05, BuyQuantityFiatUp: 10}
+	current := &types.Config{Symbol: "ETHUSDT", Stoploss: 0.1, BuyQuantityFiatUp: 20}
+
+	changes, restart := Diff(previous, current)
+
+	if want := []string{"BuyQuantityFiatUp 10 -> 20"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("Diff() changes = %v, want %v", changes, want)
+	}
+
+	if want := []string{"Symbol BTCUSDT -> ETHUSDT"}; !reflect.DeepEqual(restart, want) {
+		t.Errorf("Diff() restart = %v, want %v", restart, want)
+	}
+
+}
+
+func TestApply(t *testing.T) {
+
+	sessionData := &types.Session{}
+
+	watched.entries[sessionData] = &entry{config: &types.Config{Symbol: "ETHUSDT", DryRun: true, BuyQuantityFiatUp: 20}}
+	defer delete(watched.entries, sessionData)
+
+	configData := &types.Config{Symbol: "BTCUSDT", BuyQuantityFiatUp: 10}
+
+	Apply(configData, sessionData)
+
+	if configData.BuyQuantityFiatUp != 20 || configData.Symbol != "BTCUSDT" || configData.DryRun {
+		t.Errorf("Apply() = %+v, want BuyQuantityFiatUp 20, Symbol BTCUSDT and DryRun false", *configData)
+	}
+
+}
+
+func Test_numeric(t *testing.T) {
+
+	previous := map[string]string{"config.stoploss": "0.05", "config.symbol": "BTCUSDT"}
+	current := map[string]string{"config.stoploss": "0.05x", "config.symbol": "ETHUSDT"}
+
+	errs := numeric(previous, current)
+
+	if len(errs) != 1 || errs[0].Error() != `config.stoploss "0.05x" is not a number` {
+		t.Errorf("numeric() = %v, want [config.stoploss \"0.05x\" is not a number]", errs)
+	}
+
+}
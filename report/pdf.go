@@ -0,0 +1,192 @@
+package report
+
+/* Minimal PDF 1.4 writer for report tables. Reports are laid out as fixed width text in the Courier standard
+font on landscape A4 pages, so no font embedding or external library is required. */
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* PDF page layout in points */
+const (
+	pdfPageWidth   = 842 /* Landscape A4 */
+	pdfPageHeight  = 595
+	pdfMargin      = 36
+	pdfFontSize    = 8
+	pdfLineHeight  = 10
+	pdfColumnWidth = 24 /* Maximum characters per column, longer values are truncated */
+)
+
+/* Return the table rows of columns and records as fixed width text lines */
+func tableLines(
+	columns []string,
+	records [][]string) (header string, lines []string) {
+
+	widths := make([]int, len(columns))
+
+	for i, column := range columns {
+		widths[i] = len(column)
+	}
+
+	for _, record := range records {
+
+		for i := 0; i < len(record) && i < len(widths); i++ {
+
+			if len(record[i]) > widths[i] {
+				widths[i] = len(record[i])
+			}
+
+		}
+
+	}
+
+	format := func(values []string) string {
+
+		var b strings.Builder
+
+		for i, width := range widths {
+
+			if width > pdfColumnWidth {
+				width = pdfColumnWidth
+			}
+
+			value := ""
+			if i < len(values) {
+				value = values[i]
+			}
+
+			if len(value) > width {
+				value = value[:width]
+			}
+
+			fmt.Fprintf(&b, "%-*s  ", width, value)
+
+		}
+
+		return strings.TrimRight(b.String(), " ")
+
+	}
+
+	for _, record := range records {
+		lines = append(lines, format(record))
+	}
+
+	return format(columns), lines
+
+}
+
+/* Write a PDF document with title, subtitle and the table of columns and records, repeating the table header on every page */
+func writePDF(
+	w io.Writer,
+	title string,
+	subtitle string,
+	columns []string,
+	records [][]string) error {
+
+	header, lines := tableLines(columns, records)
+	rule := strings.Repeat("-", len(header))
+	perPage := (pdfPageHeight-2*pdfMargin)/pdfLineHeight - 5 /* Lines left after title, subtitle, blank line and table header */
+
+	var pages [][]string
+
+	for start := 0; ; start += perPage {
+
+		end := start + perPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		page := []string{title, subtitle, "", header, rule}
+		page = append(page, lines[start:end]...)
+		pages = append(pages, page)
+
+		if end == len(lines) {
+			break
+		}
+
+	}
+
+	/* Objects: 1 catalog, 2 pages, 3 font, then a page and a content stream per page */
+	var objects []string
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+
+		var content bytes.Buffer
+
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin-pdfFontSize)
+
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", escapePDF(line))
+		}
+
+		fmt.Fprintf(&content, "(Page %d of %d) Tj\nET", i+1, len(pages))
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+
+	}
+
+	var b bytes.Buffer
+	offsets := make([]int, len(objects))
+
+	b.WriteString("%PDF-1.4\n")
+
+	for i, object := range objects {
+
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+
+	}
+
+	xref := b.Len()
+
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(b.Bytes())
+
+	return err
+
+}
+
+/* Escape a text line for a PDF string literal, characters outside printable ASCII are replaced with '?' */
+func escapePDF(text string) string {
+
+	var b strings.Builder
+
+	for _, r := range text {
+
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+
+	}
+
+	return b.String()
+
+}
@@ -0,0 +1,298 @@
+package report
+
+/* This package implements the report export. Reports summarize the filled trades, the realized profit per thread
+and the monthly performance for a date range, and are written as CSV, streamed row by row from the database export
+procedures, or as a PDF table generated by pdf.go. */
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	dateLayout  = "2006-01-02"
+	timeLayout  = "2006-01-02 15:04:05"
+	defaultDays = 30 /* Date range when From is empty */
+)
+
+/* Report errors */
+var (
+	ErrInvalidKind   = errors.New("Report must be trades, threads or monthly")
+	ErrInvalidFormat = errors.New("Format must be csv or pdf")
+	ErrInvalidDate   = errors.New("Dates must be formatted as YYYY-MM-DD")
+	ErrInvalidRange  = errors.New("From must be before To")
+)
+
+// Kind struct define a report
+type Kind struct {
+	Name        string
+	Title       string
+	Description string
+	Columns     []string
+}
+
+// Kinds list the available reports
+var Kinds = []Kind{
+	{Name: "trades", Title: "Trades", Description: "Filled orders with the realized profit of each sale", Columns: []string{"Time", "ThreadID", "Symbol", "Side", "OrderID", "OrderIDSource", "Price", "Quantity", "Quote", "Source", "Profit"}},
+	{Name: "threads", Title: "Profit per Thread", Description: "Realized profit of the sales of each thread", Columns: []string{"ThreadID", "Symbol", "Trades", "Profit", "ProfitPct"}},
+	{Name: "monthly", Title: "Monthly Performance", Description: "Realized profit of the sales of each month", Columns: []string{"Month", "Trades", "Profit", "ProfitPct"}},
+}
+
+// Formats list the available report formats
+var Formats = []string{"csv", "pdf"}
+
+// Request struct define a report export
+type Request struct {
+	Kind   Kind
+	Format string
+	From   time.Time /* Inclusive */
+	To     time.Time /* Exclusive */
+}
+
+// Page struct define the reports page (reports.html)
+type Page struct {
+	Kinds   []Kind
+	Formats []string
+	From    string
+	To      string
+	Message string
+	Theme   string /* UI theme of the logged in user */
+}
+
+// LoadPage load the reports page with the default date range
+func LoadPage(message string) (page Page) {
+
+	to := time.Now()
+
+	page.Kinds = Kinds
+	page.Formats = Formats
+	page.From = to.AddDate(0, 0, -defaultDays).Format(dateLayout)
+	page.To = to.Format(dateLayout)
+	page.Message = message
+
+	return page
+
+}
+
+// Parse validate a report export request. Dates are YYYY-MM-DD in local time and To is inclusive,
+// an empty To is today and an empty From is defaultDays before To.
+func Parse(
+	kind string,
+	format string,
+	from string,
+	to string) (request Request, err error) {
+
+	var ok bool
+
+	if request.Kind, ok = kindByName(kind); !ok {
+
+		return request, ErrInvalidKind
+
+	}
+
+	if request.Format = format; request.Format != "csv" && request.Format != "pdf" {
+
+		return request, ErrInvalidFormat
+
+	}
+
+	request.To = time.Now()
+
+	if to != "" {
+
+		if request.To, err = time.ParseInLocation(dateLayout, to, time.Local); err != nil {
+
+			return request, ErrInvalidDate
+
+		}
+
+	}
+
+	y, m, d := request.To.Date()
+	request.To = time.Date(y, m, d+1, 0, 0, 0, 0, time.Local) /* Include the whole To day */
+
+	request.From = request.To.AddDate(0, 0, -defaultDays)
+
+	if from != "" {
+
+		if request.From, err = time.ParseInLocation(dateLayout, from, time.Local); err != nil {
+
+			return request, ErrInvalidDate
+
+		}
+
+	}
+
+	if !request.From.Before(request.To) {
+
+		return request, ErrInvalidRange
+
+	}
+
+	return request, nil
+
+}
+
+// Filename return the file name of the report, i.e. cryptopump-trades-2021-11-01-2021-11-30.csv
+func (request Request) Filename() string {
+
+	return fmt.Sprintf("cryptopump-%s-%s-%s.%s", request.Kind.Name, request.From.Format(dateLayout), request.To.AddDate(0, 0, -1).Format(dateLayout), request.Format)
+
+}
+
+// ContentType return the MIME type of the report
+func (request Request) ContentType() string {
+
+	if request.Format == "pdf" {
+
+		return "application/pdf"
+
+	}
+
+	return "text/csv"
+
+}
+
+// Write the report to w
+func Write(
+	w io.Writer,
+	sessionData *types.Session,
+	request Request) error {
+
+	if request.Format == "csv" {
+
+		return writeCSV(w, sessionData, request)
+
+	}
+
+	var records [][]string
+
+	if err := export(sessionData, request, func(record []string) error {
+		records = append(records, record)
+		return nil
+	}); err != nil {
+
+		return err
+
+	}
+
+	subtitle := fmt.Sprintf("%s to %s - %d rows - generated %s", request.From.Format(dateLayout), request.To.AddDate(0, 0, -1).Format(dateLayout), len(records), time.Now().Format(timeLayout))
+
+	return writePDF(w, "CryptoPump - "+request.Kind.Title, subtitle, request.Kind.Columns, records)
+
+}
+
+/* Stream the report as CSV with a header row */
+func writeCSV(
+	w io.Writer,
+	sessionData *types.Session,
+	request Request) error {
+
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(request.Kind.Columns); err != nil {
+
+		return err
+
+	}
+
+	if err := export(sessionData, request, writer.Write); err != nil {
+
+		return err
+
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+
+}
+
+/* Stream the report rows from the database to fn as text records */
+func export(
+	sessionData *types.Session,
+	request Request,
+	fn func([]string) error) error {
+
+	from := request.From.UnixNano() / int64(time.Millisecond)
+	to := request.To.UnixNano() / int64(time.Millisecond)
+
+	switch request.Kind.Name {
+	case "trades":
+		return mysql.ExportTrades(sessionData, from, to, func(trade types.Trade) error {
+			return fn(tradeRecord(trade))
+		})
+	case "threads":
+		return mysql.ExportThreadProfit(sessionData, from, to, func(summary types.ProfitSummary) error {
+			return fn([]string{summary.Key, summary.Symbol, strconv.Itoa(summary.Trades), formatFloat(summary.Profit), formatFloat(summary.ProfitPct)})
+		})
+	case "monthly":
+		return mysql.ExportMonthlyProfit(sessionData, from, to, func(summary types.ProfitSummary) error {
+			return fn([]string{summary.Key, strconv.Itoa(summary.Trades), formatFloat(summary.Profit), formatFloat(summary.ProfitPct)})
+		})
+	}
+
+	return ErrInvalidKind
+
+}
+
+/* Return the text record of a trade, Profit is empty for buys */
+func tradeRecord(trade types.Trade) []string {
+
+	profit := ""
+	orderIDSource := ""
+
+	if trade.Side == "SELL" {
+
+		profit = formatFloat(trade.Profit)
+		orderIDSource = strconv.FormatInt(trade.OrderIDSource, 10)
+
+	}
+
+	return []string{
+		time.Unix(0, trade.TransactTime*int64(time.Millisecond)).Format(timeLayout),
+		trade.ThreadID,
+		trade.Symbol,
+		trade.Side,
+		strconv.FormatInt(trade.OrderID, 10),
+		orderIDSource,
+		formatFloat(trade.Price),
+		formatFloat(trade.Quantity),
+		formatFloat(trade.Quote),
+		trade.Source,
+		profit,
+	}
+
+}
+
+/* Return f rounded to 8 decimals without trailing zeros, the orders table stores single precision floats */
+func formatFloat(f float64) string {
+
+	return strconv.FormatFloat(math.Round(f*1e8)/1e8, 'f', -1, 64)
+
+}
+
+/* Return the report named name */
+func kindByName(name string) (Kind, bool) {
+
+	for _, kind := range Kinds {
+
+		if kind.Name == name {
+
+			return kind, true
+
+		}
+
+	}
+
+	return Kind{}, false
+
+}
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		kind   string
+		format string
+		from   string
+		to     string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  error
+	}{
+		{
+			name:     "monthly pdf",
+			args:     args{kind: "monthly", format: "pdf", from: "2021-11-01", to: "2021-11-30"},
+			wantFrom: time.Date(2021, 11, 1, 0, 0, 0, 0, time.Local),
+			wantTo:   time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local),
+			wantErr:  nil,
+		},
+		{
+			name:     "default from",
+			args:     args{kind: "trades", format: "csv", to: "2021-11-30"},
+			wantFrom: time.Date(2021, 11, 1, 0, 0, 0, 0, time.Local),
+			wantTo:   time.Date(2021, 12, 1, 0, 0, 0, 0, time.Local),
+			wantErr:  nil,
+		},
+		{
+			name:    "unknown report",
+			args:    args{kind: "orders", format: "csv"},
+			wantErr: ErrInvalidKind,
+		},
+		{
+			name:    "unknown format",
+			args:    args{kind: "threads", format: "xlsx"},
+			wantErr: ErrInvalidFormat,
+		},
+		{
+			name:    "invalid date",
+			args:    args{kind: "threads", format: "csv", from: "01/11/2021"},
+			wantErr: ErrInvalidDate,
+		},
+		{
+			name:    "from after to",
+			args:    args{kind: "threads", format: "csv", from: "2021-12-02", to: "2021-12-01"},
+			wantErr: ErrInvalidRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.kind, tt.args.format, tt.args.from, tt.args.to)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (!got.From.Equal(tt.wantFrom) || !got.To.Equal(tt.wantTo)) {
+				t.Errorf("Parse() = %v - %v, want %v - %v", got.From, got.To, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestRequest_Filename(t *testing.T) {
+	request, _ := Parse("trades", "csv", "2021-11-01", "2021-11-30")
+	if got, want := request.Filename(), "cryptopump-trades-2021-11-01-2021-11-30.csv"; got != want {
+		t.Errorf("Filename() = %v, want %v", got, want)
+	}
+}
+
+func Test_tradeRecord(t *testing.T) {
+	transactTime := time.Date(2021, 12, 1, 10, 15, 0, 0, time.Local).UnixNano() / int64(time.Millisecond)
+	tests := []struct {
+		name  string
+		trade types.Trade
+		want  []string
+	}{
+		{
+			name:  "buy",
+			trade: types.Trade{TransactTime: transactTime, ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Side: "BUY", OrderID: 123, Price: 40000.12109375, Quantity: 0.0025, Quote: 100.0003, Source: "bot"},
+			want:  []string{"2021-12-01 10:15:00", "c683ok5mk1u1120gnmmg", "BTCUSDT", "BUY", "123", "", "40000.12109375", "0.0025", "100.0003", "bot", ""},
+		},
+		{
+			name:  "sell",
+			trade: types.Trade{TransactTime: transactTime, ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT", Side: "SELL", OrderID: 124, OrderIDSource: 123, Price: 41000, Quantity: 0.0025, Quote: 102.5, Source: "operator", Profit: 2.49970000001},
+			want:  []string{"2021-12-01 10:15:00", "c683ok5mk1u1120gnmmg", "BTCUSDT", "SELL", "124", "123", "41000", "0.0025", "102.5", "operator", "2.4997"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tradeRecord(tt.trade); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tradeRecord() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_writePDF(t *testing.T) {
+	var records [][]string
+	for i := 0; i < 100; i++ {
+		records = append(records, []string{"2021-11", strconv.Itoa(i), "(1.5)"})
+	}
+	var b bytes.Buffer
+	if err := writePDF(&b, "CryptoPump - Monthly Performance", "subtitle", []string{"Month", "Trades", "Profit"}, records); err != nil {
+		t.Fatal(err)
+	}
+	pdf := b.String()
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatalf("writePDF() missing header or trailer")
+	}
+	if !strings.Contains(pdf, "/Count 3") {
+		t.Errorf("writePDF() want 3 pages")
+	}
+	if !strings.Contains(pdf, `(2021-11  99      \(1.5\)) Tj`) {
+		t.Errorf("writePDF() missing escaped row")
+	}
+	startxref, _ := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(pdf)[1])
+	if !strings.HasPrefix(pdf[startxref:], "xref\n") {
+		t.Fatalf("writePDF() startxref %d does not point to xref", startxref)
+	}
+	for i, m := range regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(pdf, -1) {
+		offset, _ := strconv.Atoi(m[1])
+		if want := strconv.Itoa(i+1) + " 0 obj"; !strings.HasPrefix(pdf[offset:], want) {
+			t.Errorf("writePDF() xref entry %d does not point to %q", i+1, want)
+		}
+	}
+}
+
+func Test_escapePDF(t *testing.T) {
+	if got, want := escapePDF(`a(b)c\d €`), `a\(b\)c\\d ?`; got != want {
+		t.Errorf("escapePDF() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,368 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* The capital allocator divides the fiat funds and the open transactions of all threads, less the fiat reserve
+floor, into funds allocator reservations by weight. In weights mode each thread weighs its ThreadID or symbol
+weight in configData.ConfigGlobal.AllocatorWeights, and in performance mode that weight is tilted by the realized
+return of the thread over AllocatorWindow days relative to the other threads. The Master Node rebalances the
+reservations every AllocatorInterval minutes. */
+
+/* Capital allocator modes */
+const (
+	AllocatorWeights     = "weights"
+	AllocatorPerformance = "performance"
+)
+
+/* Bounds of the performance tilt of a thread weight */
+const (
+	tiltMin = 0.25
+	tiltMax = 2
+)
+
+// ErrAllocatorMode is returned for an unknown capital allocator mode
+var ErrAllocatorMode = errors.New("Allocator mode must be weights or performance")
+
+// Allocation struct define the fiat budget and utilization of a thread under the capital allocator
+type Allocation struct {
+	ThreadID    string  `json:"threadId"`
+	Symbol      string  `json:"symbol"`
+	Weight      float64 `json:"weight"`      /* Share of the allocated funds as ratio */
+	Return      float64 `json:"return"`      /* Realized return over the allocator window */
+	Budget      float64 `json:"budget"`      /* Fiat funds reserved */
+	Used        float64 `json:"used"`        /* Fiat amount of open transactions */
+	Utilization float64 `json:"utilization"` /* Used as ratio of budget */
+}
+
+var allocator = struct {
+	sync.Mutex
+	last time.Time
+}{}
+
+// Allocate rebalance the reservations of the running threads by the capital allocator when
+// configData.ConfigGlobal.AllocatorInterval minutes elapsed since the last rebalance (only Master Node)
+func Allocate(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !sessionData.MasterNode || configData.ConfigGlobal.AllocatorMode == "" {
+
+		return
+
+	}
+
+	allocator.Lock()
+
+	if time.Since(allocator.last) < time.Duration(configData.ConfigGlobal.AllocatorInterval)*time.Minute {
+
+		allocator.Unlock()
+		return
+
+	}
+
+	allocator.last = time.Now()
+	allocator.Unlock()
+
+	_ = RebalanceAllocations(configData, sessionData)
+
+}
+
+// RebalanceAllocations divide the fiat balance and the open transactions of all threads, less the fiat reserve floor,
+// between the running threads by the weights of the capital allocator and reserve each thread its budget
+func RebalanceAllocations(
+	configData *types.Config,
+	sessionData *types.Session) (err error) {
+
+	var allocations []Allocation
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if allocations, err = Allocations(configData, sessionData); err != nil {
+
+		return err
+
+	}
+
+	if len(allocations) == 0 {
+
+		return errors.New("No threads to allocate funds")
+
+	}
+
+	var amount float64
+
+	for _, allocation := range allocations {
+
+		amount += allocation.Used
+
+	}
+
+	sessionData.FiatReserve = fiatReserveFloor(configData.ConfigGlobal.FiatReserve, configData.ConfigGlobal.FiatReservePct, sessionData.SymbolFiatFunds+amount)
+
+	budgets := allocationBudgets(math.Max(0, sessionData.SymbolFiatFunds-sessionData.FiatReserve+amount), allocations)
+
+	summary := make([]string, 0, len(allocations))
+
+	for i, allocation := range allocations {
+
+		if budgets[i] != allocation.Budget {
+
+			if err = mysql.UpdateSessionReservationByThreadID(sessionData, allocation.ThreadID, budgets[i]); err != nil {
+
+				return err
+
+			}
+
+		}
+
+		if allocation.ThreadID == sessionData.ThreadID {
+
+			sessionData.Reservation = budgets[i]
+
+		}
+
+		summary = append(summary, fmt.Sprintf("%s %.2f", allocationName(allocation), budgets[i]))
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  fmt.Sprintf("Allocations rebalanced by %s across %d threads - %s", configData.ConfigGlobal.AllocatorMode, len(allocations), strings.Join(summary, ", ")),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Allocations return the weight, realized return, budget and utilization of each running thread under the capital
+// allocator. The budget is the current reservation of the thread.
+func Allocations(
+	configData *types.Config,
+	sessionData *types.Session) (allocations []Allocation, err error) {
+
+	var sessions []types.SessionAllocation
+
+	if configData.ConfigGlobal.AllocatorMode != "" &&
+		configData.ConfigGlobal.AllocatorMode != AllocatorWeights &&
+		configData.ConfigGlobal.AllocatorMode != AllocatorPerformance {
+
+		return nil, ErrAllocatorMode
+
+	}
+
+	window := time.Duration(configData.ConfigGlobal.AllocatorWindow) * 24 * time.Hour
+
+	if sessions, err = mysql.GetSessionAllocations(sessionData, time.Now().Add(-window).UnixNano()/int64(time.Millisecond)); err != nil {
+
+		return nil, err
+
+	}
+
+	allocations = make([]Allocation, len(sessions))
+	weights := parseWeights(configData.ConfigGlobal.AllocatorWeights)
+
+	for i, session := range sessions {
+
+		allocations[i] = Allocation{
+			ThreadID: session.ThreadID,
+			Symbol:   session.Symbol,
+			Weight:   allocationWeight(weights, session.ThreadID, session.Symbol),
+			Budget:   session.Reservation,
+			Used:     session.Amount,
+		}
+
+		if session.Cost > 0 {
+
+			allocations[i].Return = session.Profit / session.Cost
+
+		}
+
+		if session.Reservation > 0 {
+
+			allocations[i].Utilization = session.Amount / session.Reservation
+
+		}
+
+	}
+
+	if configData.ConfigGlobal.AllocatorMode == AllocatorPerformance {
+
+		tiltWeights(allocations)
+
+	}
+
+	normalizeWeights(allocations)
+
+	return allocations, nil
+
+}
+
+/* Parse a comma separated SYMBOL:weight or ThreadID:weight list, ignoring malformed and negative weights */
+func parseWeights(spec string) map[string]float64 {
+
+	weights := make(map[string]float64)
+
+	for _, item := range strings.Split(spec, ",") {
+
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		weight, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || weight < 0 {
+			continue
+		}
+
+		weights[strings.ToUpper(strings.TrimSpace(parts[0]))] = weight
+
+	}
+
+	return weights
+
+}
+
+/* Return the configured weight of a thread by ThreadID, then by symbol, and 1 when not configured */
+func allocationWeight(
+	weights map[string]float64,
+	threadID string,
+	symbol string) float64 {
+
+	if weight, ok := weights[strings.ToUpper(threadID)]; ok {
+
+		return weight
+
+	}
+
+	if weight, ok := weights[strings.ToUpper(symbol)]; ok && symbol != "" {
+
+		return weight
+
+	}
+
+	return 1
+
+}
+
+/* Tilt the weights by the z-score of the realized return of each thread, bounded between tiltMin and tiltMax */
+func tiltWeights(allocations []Allocation) {
+
+	if len(allocations) < 2 {
+
+		return
+
+	}
+
+	var mean, variance float64
+
+	for _, allocation := range allocations {
+
+		mean += allocation.Return
+
+	}
+
+	mean /= float64(len(allocations))
+
+	for _, allocation := range allocations {
+
+		variance += math.Pow(allocation.Return-mean, 2)
+
+	}
+
+	stdev := math.Sqrt(variance / float64(len(allocations)))
+
+	if stdev == 0 { /* Same performance, weights unchanged */
+
+		return
+
+	}
+
+	for i := range allocations {
+
+		allocations[i].Weight *= math.Min(tiltMax, math.Max(tiltMin, 1+0.5*(allocations[i].Return-mean)/stdev))
+
+	}
+
+}
+
+/* Scale the weights to sum 1, equal weights when all weights are 0 */
+func normalizeWeights(allocations []Allocation) {
+
+	var total float64
+
+	for _, allocation := range allocations {
+
+		total += allocation.Weight
+
+	}
+
+	for i := range allocations {
+
+		if total > 0 {
+			allocations[i].Weight /= total
+		} else {
+			allocations[i].Weight = 1 / float64(len(allocations))
+		}
+
+	}
+
+}
+
+/* Divide capital between the allocations by weight, rounded down to cents (a 0 weight releases the reservation) */
+func allocationBudgets(
+	capital float64,
+	allocations []Allocation) []float64 {
+
+	budgets := make([]float64, len(allocations))
+
+	for i, allocation := range allocations {
+
+		budgets[i] = math.Floor(capital*allocation.Weight*100) / 100
+
+	}
+
+	return budgets
+
+}
+
+/* Return the symbol of an allocation, or its ThreadID before the first order */
+func allocationName(allocation Allocation) string {
+
+	if allocation.Symbol != "" {
+
+		return allocation.Symbol
+
+	}
+
+	return allocation.ThreadID
+
+}
@@ -0,0 +1,97 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// LoadDailyLoss load realized profit since the start of the UTC day across all threads and halt
+// new buys when the loss exceeds configData.ConfigGlobal.DailyLossMax. Realized profit is calculated
+// from the orders table, so a restart does not reset it, and the halt clears on the next UTC day.
+func LoadDailyLoss(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+
+	if configData.ConfigGlobal.DailyLossMax == 0 { /* Limit disabled */
+
+		sessionData.Global.DailyLossHalt = false
+		return
+
+	}
+
+	if sessionData.Global.DailyProfit, err = mysql.GetProfitSince(sessionData, startOfDayUTC(time.Now())); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return
+
+	}
+
+	halt := -sessionData.Global.DailyProfit >= configData.ConfigGlobal.DailyLossMax
+
+	/* Notify once when the limit is reached (only Master Node to avoid one message per thread) */
+	if halt && !sessionData.Global.DailyLossHalt && sessionData.MasterNode {
+
+		message := fmt.Sprintf("Daily loss limit reached - realized %.2f, buys halted until next UTC day",
+			sessionData.Global.DailyProfit)
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  message,
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		notifyHalt(configData, sessionData, "dailyloss", message)
+
+	}
+
+	sessionData.Global.DailyLossHalt = halt
+
+}
+
+// ErrDailyLossHalt is returned by CheckDailyLoss when the daily loss limit was reached
+var ErrDailyLossHalt = errors.New("Daily loss limit reached")
+
+// CheckDailyLoss reject every buy, including Force Buy and manual buys, until the next UTC day once
+// the daily loss limit was reached, registered with exchange.RegisterBuyCheck
+func CheckDailyLoss(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error {
+
+	if sessionData.Global != nil && sessionData.Global.DailyLossHalt {
+
+		return ErrDailyLossHalt
+
+	}
+
+	return nil
+
+}
+
+/* Return the start of the UTC day for t */
+func startOfDayUTC(t time.Time) time.Time {
+
+	t = t.UTC()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+}
@@ -0,0 +1,230 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// LoadDrawdown load the drawdown kill switch status, and on the Master Node track peak-to-trough
+// equity across all threads triggering the kill switch when drawdown exceeds configData.ConfigGlobal.DrawdownMax
+func LoadDrawdown(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+	var equityPeak float64
+	var drawdownHalt bool
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if equityPeak, drawdownHalt, err = mysql.GetGlobalDrawdown(sessionData); err != nil {
+
+		return
+
+	}
+
+	sessionData.Global.EquityPeak = equityPeak
+	sessionData.Global.DrawdownHalt = drawdownHalt
+
+	/* Only the Master Node tracks equity to avoid concurrent updates of the equity peak */
+	if !sessionData.MasterNode || configData.ConfigGlobal.DrawdownMax == 0 {
+
+		return
+
+	}
+
+	if sessionData.Global.Equity, err = mysql.GetGlobalEquity(sessionData); err != nil {
+
+		return
+
+	}
+
+	if sessionData.Global.Equity > equityPeak {
+
+		equityPeak = sessionData.Global.Equity
+
+	}
+
+	sessionData.Global.EquityPeak = equityPeak
+	sessionData.Global.Drawdown = calculateDrawdown(equityPeak, sessionData.Global.Equity)
+
+	if !drawdownHalt && sessionData.Global.Drawdown >= configData.ConfigGlobal.DrawdownMax {
+
+		drawdownHalt = true
+		sessionData.Global.DrawdownHalt = true
+
+		message := fmt.Sprintf("Drawdown kill switch triggered - drawdown %.2f%% equity %.2f peak %.2f",
+			sessionData.Global.Drawdown*100,
+			sessionData.Global.Equity,
+			equityPeak)
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  message,
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		notifyHalt(configData, sessionData, "drawdown", message)
+
+	}
+
+	err = mysql.UpdateGlobalDrawdown(sessionData, equityPeak, drawdownHalt)
+
+}
+
+// ResumeDrawdown re-enable buys after the drawdown kill switch was triggered.
+// The equity peak is reset and set to current equity on the next LoadDrawdown.
+func ResumeDrawdown(
+	configData *types.Config,
+	sessionData *types.Session) (err error) {
+
+	if err = mysql.UpdateGlobalDrawdown(sessionData, 0, false); err != nil {
+
+		return err
+
+	}
+
+	sessionData.Global.EquityPeak = 0
+	sessionData.Global.Drawdown = 0
+	sessionData.Global.DrawdownHalt = false
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Drawdown kill switch reset by operator",
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+/* Buy halt errors */
+var (
+	ErrLiquidate    = errors.New("Emergency liquidation active")
+	ErrDrawdownHalt = errors.New("Drawdown kill switch active")
+)
+
+// CheckHalt reject every buy, including Force Buy and manual buys, while an emergency liquidation or the
+// drawdown kill switch is active, registered with exchange.RegisterBuyCheck
+func CheckHalt(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error {
+
+	switch {
+	case sessionData.Global == nil:
+
+		return nil
+
+	case sessionData.Global.Liquidate:
+
+		return ErrLiquidate
+
+	case sessionData.Global.DrawdownHalt:
+
+		return ErrDrawdownHalt
+
+	}
+
+	return nil
+
+}
+
+// SaveEquity save a portfolio valuation snapshot across all threads for the equity curve.
+// Only the Master Node saves snapshots to avoid duplicates.
+func SaveEquity(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+	var equity float64
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if !sessionData.MasterNode {
+
+		return
+
+	}
+
+	if equity, err = mysql.GetGlobalEquity(sessionData); err != nil || equity == 0 {
+
+		return
+
+	}
+
+	err = mysql.SaveEquity(sessionData, types.EquitySnapshot{
+		Time:   time.Now().UnixNano() / int64(time.Millisecond),
+		Equity: equity,
+	})
+
+}
+
+/* Calculate drawdown from equity peak as ratio */
+func calculateDrawdown(equityPeak float64, equity float64) float64 {
+
+	if equityPeak <= 0 {
+
+		return 0
+
+	}
+
+	return math.Max(0, (equityPeak-equity)/equityPeak)
+
+}
+
+/* Send a risk limit halting buys as a critical error notification */
+func notifyHalt(
+	configData *types.Config,
+	sessionData *types.Session,
+	key string,
+	message string) {
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Critical,
+		Key:      key,
+		Title:    "Buys halted",
+		Data:     messages.Data{Message: message},
+	}.Send(configData, sessionData)
+
+}
@@ -0,0 +1,69 @@
+package risk
+
+import (
+	"errors"
+	"math"
+
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// IsThreadExposureExceeded Check if opening a new position would exceed configData.BuyExposureMax
+// for the open transactions of sessionData.ThreadID
+func IsThreadExposureExceeded(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) bool {
+
+	var err error
+
+	if configData.BuyExposureMax == 0 { /* Cap disabled */
+
+		return false
+
+	}
+
+	/* Reload exposure before every buy since the last buy may have not been loaded yet */
+	if sessionData.ThreadExposure, err = mysql.GetThreadAmountByThreadID(sessionData); err != nil {
+
+		return true /* Fail closed when exposure cannot be verified */
+
+	}
+
+	return (sessionData.ThreadExposure + buyQuantityFiat) > configData.BuyExposureMax
+
+}
+
+// ErrThreadExposure is returned by CheckThreadExposure when a buy would exceed configData.BuyExposureMax
+var ErrThreadExposure = errors.New("Thread exposure limit reached")
+
+// CheckThreadExposure reject a buy exceeding configData.BuyExposureMax, registered with exchange.RegisterBuyCheck
+func CheckThreadExposure(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error {
+
+	if IsThreadExposureExceeded(configData, sessionData, buyQuantityFiat) {
+
+		return ErrThreadExposure
+
+	}
+
+	return nil
+
+}
+
+// ThreadExposureHeadroom return the fiat amount still available under configData.BuyExposureMax for sessionData.ThreadID
+func ThreadExposureHeadroom(
+	configData *types.Config,
+	sessionData *types.Session) float64 {
+
+	if configData.BuyExposureMax == 0 { /* Cap disabled */
+
+		return 0
+
+	}
+
+	return math.Max(0, configData.BuyExposureMax-sessionData.ThreadExposure)
+
+}
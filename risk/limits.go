@@ -0,0 +1,131 @@
+package risk
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Risk limits are hot-reloaded without restarting threads. The configuration hot-reload validates the
+configuration files and publishes a new snapshot of the risk limits in sessionData.RiskLimits, and
+every trading cycle applies the snapshot to its configuration before any decision is taken, so a
+cycle never runs with a mix of old and new limits. */
+
+// WatchLimits publish the risk limits of a reloaded configuration for the next trading cycle
+func WatchLimits(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	limits := limitsFromConfig(configData)
+
+	sessionData.RiskLimitsMutex.Lock()
+	defer sessionData.RiskLimitsMutex.Unlock()
+
+	if sessionData.RiskLimits != nil {
+
+		changes := diffLimits(*sessionData.RiskLimits, limits)
+
+		if len(changes) == 0 {
+
+			return
+
+		}
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Risk limits reloaded - " + strings.Join(changes, ", "),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+	sessionData.RiskLimits = &limits
+
+}
+
+// ApplyLimits apply the risk limits published by WatchLimits to configData
+func ApplyLimits(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	sessionData.RiskLimitsMutex.Lock()
+	defer sessionData.RiskLimitsMutex.Unlock()
+
+	if sessionData.RiskLimits == nil {
+
+		return
+
+	}
+
+	limits := *sessionData.RiskLimits
+
+	configData.Stoploss = limits.Stoploss
+	configData.SellVolatilityStoploss = limits.SellVolatilityStoploss
+	configData.SellTrailingActivation = limits.SellTrailingActivation
+	configData.SellTrailingDistance = limits.SellTrailingDistance
+	configData.BuyExposureMax = limits.BuyExposureMax
+	configData.BuyCorrelationMax = limits.BuyCorrelationMax
+	configData.BuyCorrelationExposureMax = limits.BuyCorrelationExposureMax
+	configData.BuySlippageMaxBps = limits.BuySlippageMaxBps
+	configData.BuyVolatilityReturnSigma = limits.BuyVolatilityReturnSigma
+	configData.BuyVolatilitySpreadSigma = limits.BuyVolatilitySpreadSigma
+	configData.BuyLossStreakCount = limits.BuyLossStreakCount
+	configData.BuyLossStreakCooldown = limits.BuyLossStreakCooldown
+	configData.ConfigGlobal.DrawdownMax = limits.DrawdownMax
+	configData.ConfigGlobal.DailyLossMax = limits.DailyLossMax
+	configData.ConfigGlobal.FiatReserve = limits.FiatReserve
+	configData.ConfigGlobal.FiatReservePct = limits.FiatReservePct
+
+}
+
+/* Extract the risk limits from configData */
+func limitsFromConfig(configData *types.Config) types.RiskLimits {
+
+	return types.RiskLimits{
+		Stoploss:                  configData.Stoploss,
+		SellVolatilityStoploss:    configData.SellVolatilityStoploss,
+		SellTrailingActivation:    configData.SellTrailingActivation,
+		SellTrailingDistance:      configData.SellTrailingDistance,
+		BuyExposureMax:            configData.BuyExposureMax,
+		BuyCorrelationMax:         configData.BuyCorrelationMax,
+		BuyCorrelationExposureMax: configData.BuyCorrelationExposureMax,
+		BuySlippageMaxBps:         configData.BuySlippageMaxBps,
+		BuyVolatilityReturnSigma:  configData.BuyVolatilityReturnSigma,
+		BuyVolatilitySpreadSigma:  configData.BuyVolatilitySpreadSigma,
+		BuyLossStreakCount:        configData.BuyLossStreakCount,
+		BuyLossStreakCooldown:     configData.BuyLossStreakCooldown,
+		DrawdownMax:               configData.ConfigGlobal.DrawdownMax,
+		DailyLossMax:              configData.ConfigGlobal.DailyLossMax,
+		FiatReserve:               configData.ConfigGlobal.FiatReserve,
+		FiatReservePct:            configData.ConfigGlobal.FiatReservePct,
+	}
+
+}
+
+/* Return the changed risk limits as "Name old -> new" */
+func diffLimits(
+	previous types.RiskLimits,
+	current types.RiskLimits) (changes []string) {
+
+	oldValue := reflect.ValueOf(previous)
+	newValue := reflect.ValueOf(current)
+
+	for i := 0; i < oldValue.NumField(); i++ {
+
+		if oldValue.Field(i).Interface() != newValue.Field(i).Interface() {
+
+			changes = append(changes, fmt.Sprintf("%s %v -> %v", oldValue.Type().Field(i).Name, oldValue.Field(i).Interface(), newValue.Field(i).Interface()))
+
+		}
+
+	}
+
+	return changes
+
+}
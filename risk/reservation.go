@@ -0,0 +1,313 @@
+package risk
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Threads sharing one exchange account see the same fiat balance. The funds allocator reserves
+fiat budget per ThreadID in the Session table: a thread with a reservation may only hold open
+transactions up to its reservation, and a thread without a reservation may only use the fiat
+balance not reserved by other threads. The fiat reserve floor is never spent by any thread. */
+
+// LoadReservation load the fiat funds reserved for sessionData.ThreadID and calculate the funds still
+// available to the thread under the funds allocator. sessionData.ThreadExposure must be loaded.
+func LoadReservation(
+	sessionData *types.Session) (err error) {
+
+	var reserved float64
+
+	if sessionData.Reservation, err = mysql.GetSessionReservation(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if reserved, err = mysql.GetSessionReservedFunds(sessionData); err != nil {
+
+		return err
+
+	}
+
+	sessionData.ReservationAvailable = reservationAvailable(
+		sessionData.Reservation,
+		sessionData.ThreadExposure,
+		sessionData.SymbolFiatFunds,
+		reserved,
+		sessionData.FiatReserve)
+
+	return nil
+
+}
+
+// IsReservationExceeded Check if opening a new position would exceed the fiat funds reserved for
+// sessionData.ThreadID, or use fiat funds reserved for other threads
+func IsReservationExceeded(
+	sessionData *types.Session,
+	buyQuantityFiat float64) bool {
+
+	var err error
+
+	/* Reload exposure and reservations before every buy since other threads may have changed them */
+	if sessionData.ThreadExposure, err = mysql.GetThreadAmountByThreadID(sessionData); err != nil {
+
+		return true /* Fail closed when reservation cannot be verified */
+
+	}
+
+	if err = LoadReservation(sessionData); err != nil {
+
+		return true /* Fail closed when reservation cannot be verified */
+
+	}
+
+	return buyQuantityFiat > sessionData.ReservationAvailable
+
+}
+
+// ErrReservationExceeded is returned by CheckReservation when a buy would exceed the fiat funds available to the thread
+var ErrReservationExceeded = errors.New("Reservation exceeded")
+
+// CheckReservation reject a buy exceeding the fiat funds available to sessionData.ThreadID under the funds
+// allocator, registered with exchange.RegisterBuyCheck
+func CheckReservation(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error {
+
+	if IsReservationExceeded(sessionData, buyQuantityFiat) {
+
+		return ErrReservationExceeded
+
+	}
+
+	return nil
+
+}
+
+// SetReservation reserve fiat funds for sessionData.ThreadID. The reservation is rejected when
+// it exceeds the fiat balance not reserved by other threads. Zero releases the reservation.
+func SetReservation(
+	configData *types.Config,
+	sessionData *types.Session,
+	reservation float64) (err error) {
+
+	var reserved float64
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "InfoLevel",
+			}.Do()
+		}
+	}()
+
+	if reservation < 0 {
+
+		return errors.New("Reservation must not be negative")
+
+	}
+
+	if sessionData.ThreadExposure, err = mysql.GetThreadAmountByThreadID(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if reserved, err = mysql.GetSessionReservedFunds(sessionData); err != nil {
+
+		return err
+
+	}
+
+	/* Open transactions of the thread are already paid for, only the remainder of the reservation must be covered by free fiat */
+	if free := math.Max(0, sessionData.SymbolFiatFunds-reserved-sessionData.FiatReserve); reservation > 0 && math.Max(0, reservation-sessionData.ThreadExposure) > free {
+
+		return fmt.Errorf("Reservation %.2f exceeds available funds %.2f", reservation, free+sessionData.ThreadExposure)
+
+	}
+
+	sessionData.Reservation = reservation
+
+	if err = mysql.UpdateSessionReservation(sessionData); err != nil {
+
+		return err
+
+	}
+
+	sessionData.ReservationAvailable = reservationAvailable(reservation, sessionData.ThreadExposure, sessionData.SymbolFiatFunds, reserved, sessionData.FiatReserve)
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  fmt.Sprintf("Reservation set to %.2f", reservation),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// RebalanceReservations split the fiat balance and the open transactions of all threads
+// equally between the threads in the Session table
+func RebalanceReservations(
+	configData *types.Config,
+	sessionData *types.Session) (err error) {
+
+	var count int
+	var amount float64
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if count, err = mysql.GetSessionCount(sessionData); err != nil {
+
+		return err
+
+	}
+
+	if count == 0 {
+
+		return errors.New("No threads to allocate funds")
+
+	}
+
+	if amount, err = mysql.GetThreadAmount(sessionData); err != nil {
+
+		return err
+
+	}
+
+	sessionData.FiatReserve = fiatReserveFloor(configData.ConfigGlobal.FiatReserve, configData.ConfigGlobal.FiatReservePct, sessionData.SymbolFiatFunds+amount)
+
+	reservation := math.Floor(math.Max(0, sessionData.SymbolFiatFunds-sessionData.FiatReserve+amount)/float64(count)*100) / 100
+
+	if err = mysql.UpdateSessionReservationAll(sessionData, reservation); err != nil {
+
+		return err
+
+	}
+
+	sessionData.Reservation = reservation
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  fmt.Sprintf("Reservations rebalanced to %.2f across %d threads", reservation, count),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// ErrFiatReserve is returned by CheckFiatReserve when a buy would spend the fiat reserve floor
+var ErrFiatReserve = errors.New("Fiat reserve floor reached")
+
+// CheckFiatReserve reject a buy spending the fiat reserve floor loaded by LoadFiatReserve, registered
+// with exchange.RegisterBuyCheck
+func CheckFiatReserve(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error {
+
+	if isFiatReserveExceeded(sessionData.SymbolFiatFunds, sessionData.FiatReserve, buyQuantityFiat) {
+
+		return ErrFiatReserve
+
+	}
+
+	return nil
+
+}
+
+// LoadFiatReserve calculate the fiat reserve floor that no thread may spend, the higher of
+// configData.ConfigGlobal.FiatReserve and FiatReservePct of fiat funds plus open transactions across all threads
+func LoadFiatReserve(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var amount float64
+	var err error
+
+	if configData.ConfigGlobal.FiatReservePct > 0 {
+
+		if amount, err = mysql.GetThreadAmount(sessionData); err != nil {
+
+			return
+
+		}
+
+	}
+
+	sessionData.FiatReserve = fiatReserveFloor(
+		configData.ConfigGlobal.FiatReserve,
+		configData.ConfigGlobal.FiatReservePct,
+		sessionData.SymbolFiatFunds+amount)
+
+}
+
+/* Calculate the fiat funds available to a thread under the funds allocator, keeping fiatReserve unspent */
+func reservationAvailable(
+	reservation float64,
+	exposure float64,
+	fiatFunds float64,
+	reserved float64,
+	fiatReserve float64) float64 {
+
+	if reservation > 0 {
+
+		return math.Max(0, math.Min(reservation-exposure, fiatFunds-fiatReserve))
+
+	}
+
+	return math.Max(0, fiatFunds-reserved-fiatReserve)
+
+}
+
+/* Calculate the fiat reserve floor as the higher of a fixed amount and a ratio of capital */
+func fiatReserveFloor(
+	reserve float64,
+	reservePct float64,
+	capital float64) float64 {
+
+	return math.Max(reserve, reservePct*capital)
+
+}
+
+/* Return true when a buy of buyQuantityFiat would leave less than fiatReserve of fiatFunds unspent */
+func isFiatReserveExceeded(
+	fiatFunds float64,
+	fiatReserve float64,
+	buyQuantityFiat float64) bool {
+
+	return fiatReserve > 0 && fiatFunds-buyQuantityFiat < fiatReserve
+
+}
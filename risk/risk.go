@@ -0,0 +1,191 @@
+package risk
+
+/* This package contains risk management functions shared across threads.
+Values are calculated at intervals via asyncFunctions and stored in sessionData
+so BuyDecisionTree and SellDecisionTree can run without additional exchange or mySQL calls. */
+
+import (
+	"math"
+
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// LoadCorrelatedExposure calculate the aggregate open exposure of all symbols held across threads
+// with a rolling correlation to sessionData.Symbol above configData.BuyCorrelationMax
+func LoadCorrelatedExposure(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var err error
+	var exposure map[string]float64
+	var closes []float64
+	var total float64
+
+	/* Conditional defer logging when there is an error retriving data */
+	defer func() {
+		if err != nil {
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+		}
+	}()
+
+	if exposure, err = mysql.GetThreadSymbolExposure(sessionData); err != nil {
+
+		return
+
+	}
+
+	if closes, err = getCloses(configData, sessionData, sessionData.Symbol); err != nil {
+
+		return
+
+	}
+
+	total = exposure[sessionData.Symbol] /* Own symbol is always part of the cluster */
+
+	for symbol, amount := range exposure {
+
+		if symbol == sessionData.Symbol {
+
+			continue
+
+		}
+
+		var closesSymbol []float64
+
+		if closesSymbol, err = getCloses(configData, sessionData, symbol); err != nil {
+
+			return
+
+		}
+
+		if correlation(returns(closes), returns(closesSymbol)) >= configData.BuyCorrelationMax {
+
+			total += amount
+
+		}
+
+	}
+
+	sessionData.CorrelatedExposure = total
+
+}
+
+/* Retrieve close prices for symbol over configData.BuyCorrelationWindow hours */
+func getCloses(
+	configData *types.Config,
+	sessionData *types.Session,
+	symbol string) (closes []float64, err error) {
+
+	var klines []*types.Kline
+
+	if klines, err = exchange.GetSymbolKlines(
+		configData,
+		sessionData,
+		symbol,
+		configData.BuyCorrelationWindow+1); err != nil {
+
+		return nil, err
+
+	}
+
+	for _, kline := range klines {
+
+		closes = append(closes, functions.StrToFloat64(kline.Close))
+
+	}
+
+	return closes, err
+
+}
+
+/* Calculate period returns from a price series */
+func returns(prices []float64) (r []float64) {
+
+	for i := 1; i < len(prices); i++ {
+
+		if prices[i-1] == 0 {
+
+			r = append(r, 0)
+			continue
+
+		}
+
+		r = append(r, (prices[i]-prices[i-1])/prices[i-1])
+
+	}
+
+	return r
+
+}
+
+/* Calculate Pearson correlation coefficient between two series. Series are aligned by their most recent values. */
+func correlation(x []float64, y []float64) float64 {
+
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+
+	if n < 2 {
+
+		return 0
+
+	}
+
+	x = x[len(x)-n:]
+	y = y[len(y)-n:]
+
+	var sumX, sumY float64
+	for i := 0; i < n; i++ {
+		sumX += x[i]
+		sumY += y[i]
+	}
+
+	meanX := sumX / float64(n)
+	meanY := sumY / float64(n)
+
+	var cov, varX, varY float64
+	for i := 0; i < n; i++ {
+		cov += (x[i] - meanX) * (y[i] - meanY)
+		varX += (x[i] - meanX) * (x[i] - meanX)
+		varY += (y[i] - meanY) * (y[i] - meanY)
+	}
+
+	if varX == 0 || varY == 0 {
+
+		return 0
+
+	}
+
+	return cov / math.Sqrt(varX*varY)
+
+}
+
+// IsCorrelatedExposureExceeded Check if opening a new position would exceed configData.BuyCorrelationExposureMax
+// for the cluster of symbols correlated to sessionData.Symbol
+func IsCorrelatedExposureExceeded(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) bool {
+
+	if configData.BuyCorrelationMax == 0 || /* Guard disabled */
+		configData.BuyCorrelationExposureMax == 0 {
+
+		return false
+
+	}
+
+	return (sessionData.CorrelatedExposure + buyQuantityFiat) > configData.BuyCorrelationExposureMax
+
+}
@@ -0,0 +1,812 @@
+package risk
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_correlation(t *testing.T) {
+	type args struct {
+		x []float64
+		y []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "positive",
+			args: args{
+				x: []float64{1, 2, 3, 4},
+				y: []float64{2, 4, 6, 8},
+			},
+			want: 1,
+		},
+		{
+			name: "negative",
+			args: args{
+				x: []float64{1, 2, 3, 4},
+				y: []float64{8, 6, 4, 2},
+			},
+			want: -1,
+		},
+		{
+			name: "flat",
+			args: args{
+				x: []float64{1, 2, 3, 4},
+				y: []float64{5, 5, 5, 5},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := correlation(tt.args.x, tt.args.y); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("correlation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCorrelatedExposureExceeded(t *testing.T) {
+	type args struct {
+		configData      *types.Config
+		sessionData     *types.Session
+		buyQuantityFiat float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "exceeded",
+			args: args{
+				configData:      &types.Config{BuyCorrelationMax: 0.8, BuyCorrelationExposureMax: 500},
+				sessionData:     &types.Session{CorrelatedExposure: 480},
+				buyQuantityFiat: 50,
+			},
+			want: true,
+		},
+		{
+			name: "within",
+			args: args{
+				configData:      &types.Config{BuyCorrelationMax: 0.8, BuyCorrelationExposureMax: 500},
+				sessionData:     &types.Session{CorrelatedExposure: 100},
+				buyQuantityFiat: 50,
+			},
+			want: false,
+		},
+		{
+			name: "disabled",
+			args: args{
+				configData:      &types.Config{BuyCorrelationMax: 0, BuyCorrelationExposureMax: 500},
+				sessionData:     &types.Session{CorrelatedExposure: 1000},
+				buyQuantityFiat: 50,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsCorrelatedExposureExceeded(tt.args.configData, tt.args.sessionData, tt.args.buyQuantityFiat); got != tt.want {
+				t.Errorf("IsCorrelatedExposureExceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_calculateDrawdown(t *testing.T) {
+	type args struct {
+		equityPeak float64
+		equity     float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "drawdown",
+			args: args{
+				equityPeak: 1000,
+				equity:     850,
+			},
+			want: 0.15,
+		},
+		{
+			name: "new peak",
+			args: args{
+				equityPeak: 1000,
+				equity:     1100,
+			},
+			want: 0,
+		},
+		{
+			name: "no peak",
+			args: args{
+				equityPeak: 0,
+				equity:     500,
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateDrawdown(tt.args.equityPeak, tt.args.equity); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("calculateDrawdown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHalt(t *testing.T) {
+	tests := []struct {
+		name    string
+		global  *types.Global
+		wantErr error
+	}{
+		{
+			name:    "not loaded",
+			global:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "active",
+			global:  &types.Global{},
+			wantErr: nil,
+		},
+		{
+			name:    "emergency liquidation",
+			global:  &types.Global{Liquidate: true, DrawdownHalt: true},
+			wantErr: ErrLiquidate,
+		},
+		{
+			name:    "drawdown kill switch",
+			global:  &types.Global{DrawdownHalt: true},
+			wantErr: ErrDrawdownHalt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckHalt(&types.Config{}, &types.Session{Global: tt.global}, 100); err != tt.wantErr {
+				t.Errorf("CheckHalt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestThreadExposureHeadroom(t *testing.T) {
+	type args struct {
+		configData  *types.Config
+		sessionData *types.Session
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "disabled",
+			args: args{
+				configData:  &types.Config{BuyExposureMax: 0},
+				sessionData: &types.Session{ThreadExposure: 100},
+			},
+			want: 0,
+		},
+		{
+			name: "headroom",
+			args: args{
+				configData:  &types.Config{BuyExposureMax: 500},
+				sessionData: &types.Session{ThreadExposure: 350},
+			},
+			want: 150,
+		},
+		{
+			name: "exceeded",
+			args: args{
+				configData:  &types.Config{BuyExposureMax: 500},
+				sessionData: &types.Session{ThreadExposure: 650},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ThreadExposureHeadroom(tt.args.configData, tt.args.sessionData); got != tt.want {
+				t.Errorf("ThreadExposureHeadroom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDailyLoss(t *testing.T) {
+	tests := []struct {
+		name    string
+		global  *types.Global
+		wantErr error
+	}{
+		{
+			name:    "not loaded",
+			global:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "below limit",
+			global:  &types.Global{DailyProfit: -50},
+			wantErr: nil,
+		},
+		{
+			name:    "limit reached",
+			global:  &types.Global{DailyProfit: -150, DailyLossHalt: true},
+			wantErr: ErrDailyLossHalt,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckDailyLoss(&types.Config{}, &types.Session{Global: tt.global}, 100); err != tt.wantErr {
+				t.Errorf("CheckDailyLoss() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_startOfDayUTC(t *testing.T) {
+	type args struct {
+		t time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{
+			name: "utc",
+			args: args{
+				t: time.Date(2021, 12, 1, 15, 30, 0, 0, time.UTC),
+			},
+			want: time.Date(2021, 12, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "local offset",
+			args: args{
+				t: time.Date(2021, 12, 1, 22, 30, 0, 0, time.FixedZone("UTC-5", -5*3600)),
+			},
+			want: time.Date(2021, 12, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := startOfDayUTC(tt.args.t); !got.Equal(tt.want) {
+				t.Errorf("startOfDayUTC() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_reservationAvailable(t *testing.T) {
+	type args struct {
+		reservation float64
+		exposure    float64
+		fiatFunds   float64
+		reserved    float64
+		fiatReserve float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "reserved thread",
+			args: args{
+				reservation: 1000,
+				exposure:    300,
+				fiatFunds:   2000,
+				reserved:    500,
+			},
+			want: 700,
+		},
+		{
+			name: "reserved thread fully used",
+			args: args{
+				reservation: 1000,
+				exposure:    1200,
+				fiatFunds:   2000,
+				reserved:    500,
+			},
+			want: 0,
+		},
+		{
+			name: "unreserved thread",
+			args: args{
+				reservation: 0,
+				exposure:    300,
+				fiatFunds:   2000,
+				reserved:    1500,
+			},
+			want: 500,
+		},
+		{
+			name: "unreserved thread no free funds",
+			args: args{
+				reservation: 0,
+				exposure:    0,
+				fiatFunds:   1000,
+				reserved:    1500,
+			},
+			want: 0,
+		},
+		{
+			name: "reserved thread fiat reserve floor",
+			args: args{
+				reservation: 1000,
+				exposure:    300,
+				fiatFunds:   800,
+				reserved:    500,
+				fiatReserve: 400,
+			},
+			want: 400,
+		},
+		{
+			name: "unreserved thread fiat reserve floor",
+			args: args{
+				reservation: 0,
+				exposure:    300,
+				fiatFunds:   2000,
+				reserved:    500,
+				fiatReserve: 1200,
+			},
+			want: 300,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reservationAvailable(tt.args.reservation, tt.args.exposure, tt.args.fiatFunds, tt.args.reserved, tt.args.fiatReserve); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("reservationAvailable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_estimateBuySlippage(t *testing.T) {
+	orderBook := &types.OrderBook{
+		Asks: []types.OrderBookEntry{
+			{Price: 100, Quantity: 1},
+			{Price: 102, Quantity: 1},
+		},
+	}
+	type args struct {
+		orderBook     *types.OrderBook
+		quoteQuantity float64
+		price         float64
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantBps    float64
+		wantFilled bool
+	}{
+		{
+			name: "best ask",
+			args: args{
+				orderBook:     orderBook,
+				quoteQuantity: 50,
+				price:         100,
+			},
+			wantBps:    0,
+			wantFilled: true,
+		},
+		{
+			name: "two levels",
+			args: args{
+				orderBook:     orderBook,
+				quoteQuantity: 202,
+				price:         100,
+			},
+			wantBps:    100,
+			wantFilled: true,
+		},
+		{
+			name: "insufficient depth",
+			args: args{
+				orderBook:     orderBook,
+				quoteQuantity: 500,
+				price:         100,
+			},
+			wantBps:    0,
+			wantFilled: false,
+		},
+		{
+			name: "no order book",
+			args: args{
+				orderBook:     nil,
+				quoteQuantity: 50,
+				price:         100,
+			},
+			wantBps:    0,
+			wantFilled: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBps, gotFilled := estimateBuySlippage(tt.args.orderBook, tt.args.quoteQuantity, tt.args.price)
+			if math.Abs(gotBps-tt.wantBps) > 1e-9 || gotFilled != tt.wantFilled {
+				t.Errorf("estimateBuySlippage() = %v, %v, want %v, %v", gotBps, gotFilled, tt.wantBps, tt.wantFilled)
+			}
+		})
+	}
+}
+
+func Test_sigmaScore(t *testing.T) {
+	type args struct {
+		history []float64
+		value   float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "above mean",
+			args: args{
+				history: []float64{1, 3, 1, 3},
+				value:   5,
+			},
+			want: 3,
+		},
+		{
+			name: "below mean",
+			args: args{
+				history: []float64{1, 3, 1, 3},
+				value:   0,
+			},
+			want: -2,
+		},
+		{
+			name: "flat history",
+			args: args{
+				history: []float64{2, 2, 2},
+				value:   5,
+			},
+			want: 0,
+		},
+		{
+			name: "empty",
+			args: args{
+				history: nil,
+				value:   5,
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sigmaScore(tt.args.history, tt.args.value); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("sigmaScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_symbolAllowed(t *testing.T) {
+	type args struct {
+		symbol     string
+		symbolList map[string]string
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "empty list",
+			args: args{
+				symbol:     "BTCUSDT",
+				symbolList: map[string]string{},
+			},
+			want: true,
+		},
+		{
+			name: "denied",
+			args: args{
+				symbol:     "LUNAUSDT",
+				symbolList: map[string]string{"LUNAUSDT": "DENY"},
+			},
+			want: false,
+		},
+		{
+			name: "not denied",
+			args: args{
+				symbol:     "BTCUSDT",
+				symbolList: map[string]string{"LUNAUSDT": "DENY"},
+			},
+			want: true,
+		},
+		{
+			name: "allowed",
+			args: args{
+				symbol:     "BTCUSDT",
+				symbolList: map[string]string{"BTCUSDT": "ALLOW", "ETHUSDT": "ALLOW"},
+			},
+			want: true,
+		},
+		{
+			name: "not in allow list",
+			args: args{
+				symbol:     "LUNAUSDT",
+				symbolList: map[string]string{"BTCUSDT": "ALLOW", "ETHUSDT": "ALLOW"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := symbolAllowed(tt.args.symbol, tt.args.symbolList); got != tt.want {
+				t.Errorf("symbolAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSymbol(t *testing.T) {
+	tests := []struct {
+		name         string
+		symbolDenied bool
+		wantErr      error
+	}{
+		{
+			name:         "allowed",
+			symbolDenied: false,
+			wantErr:      nil,
+		},
+		{
+			name:         "denied",
+			symbolDenied: true,
+			wantErr:      ErrSymbolDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckSymbol(&types.Config{}, &types.Session{Symbol: "BTCUSDT", SymbolDenied: tt.symbolDenied}, 100); err != tt.wantErr {
+				t.Errorf("CheckSymbol() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_fiatReserveFloor(t *testing.T) {
+	type args struct {
+		reserve    float64
+		reservePct float64
+		capital    float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "disabled",
+			args: args{
+				reserve:    0,
+				reservePct: 0,
+				capital:    5000,
+			},
+			want: 0,
+		},
+		{
+			name: "amount",
+			args: args{
+				reserve:    500,
+				reservePct: 0.05,
+				capital:    5000,
+			},
+			want: 500,
+		},
+		{
+			name: "percentage",
+			args: args{
+				reserve:    100,
+				reservePct: 0.05,
+				capital:    5000,
+			},
+			want: 250,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fiatReserveFloor(tt.args.reserve, tt.args.reservePct, tt.args.capital); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("fiatReserveFloor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isFiatReserveExceeded(t *testing.T) {
+	type args struct {
+		fiatFunds       float64
+		fiatReserve     float64
+		buyQuantityFiat float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "disabled",
+			args: args{
+				fiatFunds:       50,
+				fiatReserve:     0,
+				buyQuantityFiat: 100,
+			},
+			want: false,
+		},
+		{
+			name: "above reserve",
+			args: args{
+				fiatFunds:       1000,
+				fiatReserve:     500,
+				buyQuantityFiat: 500,
+			},
+			want: false,
+		},
+		{
+			name: "spends reserve",
+			args: args{
+				fiatFunds:       1000,
+				fiatReserve:     500,
+				buyQuantityFiat: 600,
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFiatReserveExceeded(tt.args.fiatFunds, tt.args.fiatReserve, tt.args.buyQuantityFiat); got != tt.want {
+				t.Errorf("isFiatReserveExceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseWeights(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want map[string]float64
+	}{
+		{
+			name: "empty",
+			spec: "",
+			want: map[string]float64{},
+		},
+		{
+			name: "weights",
+			spec: "btcusdt:2, ETHUSDT:0.5,c683ok5mk1u1120gnmmg:0,BNBUSDT,XRPUSDT:-1,ADAUSDT:x",
+			want: map[string]float64{"BTCUSDT": 2, "ETHUSDT": 0.5, "C683OK5MK1U1120GNMMG": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseWeights(tt.spec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseWeights() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_allocationWeight(t *testing.T) {
+
+	weights := map[string]float64{"BTCUSDT": 2, "C683OK5MK1U1120GNMMG": 0}
+
+	tests := []struct {
+		name     string
+		threadID string
+		symbol   string
+		want     float64
+	}{
+		{name: "thread", threadID: "c683ok5mk1u1120gnmmg", symbol: "BTCUSDT", want: 0},
+		{name: "symbol", threadID: "c683ok5mk1u1120gnmng", symbol: "BTCUSDT", want: 2},
+		{name: "unlisted", threadID: "c683ok5mk1u1120gnmo0", symbol: "", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allocationWeight(weights, tt.threadID, tt.symbol); got != tt.want {
+				t.Errorf("allocationWeight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_allocationBudgets(t *testing.T) {
+	tests := []struct {
+		name        string
+		allocations []Allocation
+		tilt        bool
+		want        []float64
+	}{
+		{
+			name:        "weights",
+			allocations: []Allocation{{Weight: 2}, {Weight: 1}, {Weight: 1}},
+			want:        []float64{500, 250, 250},
+		},
+		{
+			name:        "zero weights",
+			allocations: []Allocation{{Weight: 0}, {Weight: 0}},
+			want:        []float64{500, 500},
+		},
+		{
+			name:        "performance",
+			allocations: []Allocation{{Weight: 1, Return: 0.1}, {Weight: 1, Return: -0.1}},
+			tilt:        true,
+			want:        []float64{750, 250},
+		},
+		{
+			name:        "same performance",
+			allocations: []Allocation{{Weight: 3, Return: 0.05}, {Weight: 1, Return: 0.05}},
+			tilt:        true,
+			want:        []float64{750, 250},
+		},
+		{
+			name:        "bounded tilt",
+			allocations: []Allocation{{Weight: 1, Return: 0.5}, {Weight: 1, Return: 0}, {Weight: 1, Return: 0}, {Weight: 1, Return: 0}, {Weight: 1, Return: 0}, {Weight: 1, Return: -0.5}},
+			tilt:        true,
+			want:        []float64{305.1, 163.5, 163.5, 163.5, 163.5, 40.87}, /* 1.87, 1 and 0.25 (bounded) weights */
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.tilt {
+				tiltWeights(tt.allocations)
+			}
+			normalizeWeights(tt.allocations)
+			if got := allocationBudgets(1000, tt.allocations); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocationBudgets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_diffLimits(t *testing.T) {
+	type args struct {
+		previous types.RiskLimits
+		current  types.RiskLimits
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "unchanged",
+			args: args{
+				previous: types.RiskLimits{Stoploss: 0.1, BuyLossStreakCount: 3},
+				current:  types.RiskLimits{Stoploss: 0.1, BuyLossStreakCount: 3},
+			},
+			want: nil,
+		},
+		{
+			name: "changed",
+			args: args{
+				previous: types.RiskLimits{Stoploss: 0.1, BuyLossStreakCount: 3, DailyLossMax: 100},
+				current:  types.RiskLimits{Stoploss: 0.05, BuyLossStreakCount: 3, DailyLossMax: 50},
+			},
+			want: []string{"Stoploss 0.1 -> 0.05", "DailyLossMax 100 -> 50"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLimits(tt.args.previous, tt.args.current); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLimits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package risk
+
+import (
+	"math"
+	"strings"
+
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Minimum number of completed cycles required before Kelly sizing is used */
+const kellyMinCycles = 10
+
+// PositionSize calculate the fiat amount for a new position according to configData.BuySizingMode:
+//
+//	fixed      - buyQuantityFiat as defined in the Buy Quantity settings (default)
+//	fraction   - configData.BuySizingFraction of equity
+//	volatility - configData.BuySizingVolatilityTarget of equity divided by recent 1m volatility
+//	kelly      - Kelly fraction from recent cycles times configData.BuySizingKellyFraction of equity
+//
+// Equity is calculated from live fiat and symbol balances.
+func PositionSize(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	buyQuantityFiat float64) float64 {
+
+	equity := sessionData.SymbolFiatFunds + (sessionData.SymbolFunds * marketData.Price)
+
+	switch strings.ToLower(configData.BuySizingMode) {
+	case "fraction":
+
+		return capSize(equity*configData.BuySizingFraction, sessionData)
+
+	case "volatility":
+
+		volatility := calculateVolatility(marketData, 14)
+
+		if volatility == 0 {
+
+			return buyQuantityFiat
+
+		}
+
+		return capSize(equity*configData.BuySizingVolatilityTarget/volatility, sessionData)
+
+	case "kelly":
+
+		profits, _, err := mysql.GetThreadCycleProfitLast(sessionData, 50)
+
+		if err != nil || len(profits) < kellyMinCycles { /* Not enough trade statistics */
+
+			return buyQuantityFiat
+
+		}
+
+		return capSize(equity*calculateKelly(profits)*configData.BuySizingKellyFraction, sessionData)
+
+	}
+
+	return buyQuantityFiat
+
+}
+
+/* Position size can't be negative or exceed available fiat funds */
+func capSize(
+	size float64,
+	sessionData *types.Session) float64 {
+
+	return math.Max(0, math.Min(size, sessionData.SymbolFiatFunds))
+
+}
+
+/* Calculate standard deviation of close price returns over the last window candles */
+func calculateVolatility(
+	marketData *types.Market,
+	window int) float64 {
+
+	if marketData.Series == nil || len(marketData.Series.Candles) < 2 {
+
+		return 0
+
+	}
+
+	candles := marketData.Series.Candles
+	if len(candles) > window+1 {
+		candles = candles[len(candles)-(window+1):]
+	}
+
+	var closes []float64
+	for _, candle := range candles {
+		closes = append(closes, candle.ClosePrice.Float())
+	}
+
+	return standardDeviation(returns(closes))
+
+}
+
+/* Calculate standard deviation of a series */
+func standardDeviation(x []float64) float64 {
+
+	if len(x) == 0 {
+
+		return 0
+
+	}
+
+	var sum, sumSq float64
+	for _, v := range x {
+		sum += v
+	}
+
+	mean := sum / float64(len(x))
+
+	for _, v := range x {
+		sumSq += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(sumSq / float64(len(x)))
+
+}
+
+/* Calculate Kelly fraction W - (1-W)/R where W is the win rate and R the average win/loss ratio */
+func calculateKelly(profits []float64) float64 {
+
+	var wins, losses int
+	var sumWin, sumLoss float64
+
+	for _, profit := range profits {
+
+		if profit > 0 {
+			wins++
+			sumWin += profit
+		} else if profit < 0 {
+			losses++
+			sumLoss += -profit
+		}
+
+	}
+
+	if wins == 0 {
+
+		return 0
+
+	}
+
+	if losses == 0 {
+
+		return 1
+
+	}
+
+	winRate := float64(wins) / float64(wins+losses)
+	ratio := (sumWin / float64(wins)) / (sumLoss / float64(losses))
+
+	return math.Max(0, winRate-((1-winRate)/ratio))
+
+}
@@ -0,0 +1,100 @@
+package risk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func Test_calculateKelly(t *testing.T) {
+	type args struct {
+		profits []float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "edge",
+			args: args{
+				profits: []float64{2, 2, 2, -1, -1, -1},
+			},
+			want: 0.25,
+		},
+		{
+			name: "no edge",
+			args: args{
+				profits: []float64{1, -2, -2, -2},
+			},
+			want: 0,
+		},
+		{
+			name: "no wins",
+			args: args{
+				profits: []float64{-1, -1},
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateKelly(tt.args.profits); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("calculateKelly() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPositionSize(t *testing.T) {
+	type args struct {
+		configData      *types.Config
+		marketData      *types.Market
+		sessionData     *types.Session
+		buyQuantityFiat float64
+	}
+	tests := []struct {
+		name string
+		args args
+		want float64
+	}{
+		{
+			name: "fixed",
+			args: args{
+				configData:      &types.Config{BuySizingMode: "fixed"},
+				marketData:      &types.Market{Price: 100},
+				sessionData:     &types.Session{SymbolFiatFunds: 1000, SymbolFunds: 10},
+				buyQuantityFiat: 50,
+			},
+			want: 50,
+		},
+		{
+			name: "fraction",
+			args: args{
+				configData:      &types.Config{BuySizingMode: "fraction", BuySizingFraction: 0.05},
+				marketData:      &types.Market{Price: 100},
+				sessionData:     &types.Session{SymbolFiatFunds: 1000, SymbolFunds: 10},
+				buyQuantityFiat: 50,
+			},
+			want: 100,
+		},
+		{
+			name: "fraction capped",
+			args: args{
+				configData:      &types.Config{BuySizingMode: "fraction", BuySizingFraction: 0.9},
+				marketData:      &types.Market{Price: 100},
+				sessionData:     &types.Session{SymbolFiatFunds: 1000, SymbolFunds: 10},
+				buyQuantityFiat: 50,
+			},
+			want: 1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PositionSize(tt.args.configData, tt.args.marketData, tt.args.sessionData, tt.args.buyQuantityFiat); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("PositionSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package risk
+
+import (
+	"fmt"
+
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// IsSlippageExceeded Check if a market buy of buyQuantityFiat would fill above marketData.Price by more
+// than configData.BuySlippageMaxBps, estimated by walking the ask side of a fresh order book snapshot
+func IsSlippageExceeded(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	buyQuantityFiat float64) bool {
+
+	var err error
+	var orderBook *types.OrderBook
+
+	if configData.BuySlippageMaxBps == 0 { /* Guard disabled */
+
+		return false
+
+	}
+
+	/* Market orders fill against the current book, a fresh snapshot is required */
+	if orderBook, err = exchange.GetOrderBook(configData, sessionData); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   marketData,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return true /* Fail closed when slippage cannot be estimated */
+
+	}
+
+	bps, filled := estimateBuySlippage(orderBook, buyQuantityFiat, marketData.Price)
+
+	if filled && bps <= configData.BuySlippageMaxBps {
+
+		return false
+
+	}
+
+	message := fmt.Sprintf("SLIPPAGE Buy aborted - expected slippage %.2f bps above %.2f bps for %.2f %s at %.4f", bps, configData.BuySlippageMaxBps, buyQuantityFiat, sessionData.SymbolFiat, marketData.Price)
+
+	if !filled {
+
+		message = fmt.Sprintf("SLIPPAGE Buy aborted - order book depth insufficient for %.2f %s", buyQuantityFiat, sessionData.SymbolFiat)
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   marketData,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return true
+
+}
+
+// estimateBuySlippage estimate slippage in basis points of a market buy of quoteQuantity against price by walking
+// the ask side of the order book. filled is false when the order book does not have enough depth to fill the order.
+func estimateBuySlippage(
+	orderBook *types.OrderBook,
+	quoteQuantity float64,
+	price float64) (bps float64, filled bool) {
+
+	var quantity float64
+
+	if orderBook == nil || price <= 0 || quoteQuantity <= 0 {
+
+		return 0, false
+
+	}
+
+	remaining := quoteQuantity
+
+	for _, ask := range orderBook.Asks {
+
+		cost := ask.Price * ask.Quantity
+
+		if cost >= remaining {
+
+			quantity += remaining / ask.Price
+			remaining = 0
+			break
+
+		}
+
+		quantity += ask.Quantity
+		remaining -= cost
+
+	}
+
+	if remaining > 0 || quantity == 0 {
+
+		return 0, false
+
+	}
+
+	return ((quoteQuantity/quantity)/price - 1) * 10000, true
+
+}
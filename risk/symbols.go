@@ -0,0 +1,233 @@
+package risk
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* The symbol allow/deny list is stored in the symbollist table and applies to all threads. A denied
+symbol is never traded, and when the allow list is not empty only allowed symbols are traded. */
+
+const symbolListAllow = "ALLOW" /* Symbol allowed */
+const symbolListDeny = "DENY"   /* Symbol denied */
+
+// LoadSymbolList reload the symbol allow/deny list and suspend new buys when sessionData.Symbol is not allowed
+func LoadSymbolList(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	var symbolList map[string]string
+	var err error
+
+	if sessionData.Symbol == "" {
+
+		return
+
+	}
+
+	if symbolList, err = mysql.GetSymbolList(sessionData); err != nil {
+
+		return
+
+	}
+
+	denied := !symbolAllowed(sessionData.Symbol, symbolList)
+
+	if denied && !sessionData.SymbolDenied {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  sessionData.Symbol + " denied by symbol list - new buys suspended",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+	sessionData.SymbolDenied = denied
+
+}
+
+// ErrSymbolDenied is returned by CheckSymbol when sessionData.Symbol is denied by the symbol allow/deny list
+var ErrSymbolDenied = errors.New("Symbol denied by symbol list")
+
+// CheckSymbol reject every buy, including Force Buy and manual buys, of a symbol denied by the symbol
+// allow/deny list loaded by LoadSymbolList, registered with exchange.RegisterBuyCheck
+func CheckSymbol(
+	configData *types.Config,
+	sessionData *types.Session,
+	buyQuantityFiat float64) error {
+
+	if sessionData.SymbolDenied {
+
+		return ErrSymbolDenied
+
+	}
+
+	return nil
+
+}
+
+// IsSymbolAllowed Check the symbol allow/deny list for symbol, failing closed when the list cannot be retrieved
+func IsSymbolAllowed(
+	sessionData *types.Session,
+	symbol string) bool {
+
+	var symbolList map[string]string
+	var err error
+
+	if symbolList, err = mysql.GetSymbolList(sessionData); err != nil {
+
+		return false
+
+	}
+
+	return symbolAllowed(strings.ToUpper(symbol), symbolList)
+
+}
+
+// GetSymbolList retrieve the symbol allow and deny lists as comma separated strings for admin.html population
+func GetSymbolList(
+	sessionData *types.Session) (allow string, deny string, err error) {
+
+	var symbolList map[string]string
+	var allowList, denyList []string
+
+	if symbolList, err = mysql.GetSymbolList(sessionData); err != nil {
+
+		return "", "", err
+
+	}
+
+	for symbol, list := range symbolList {
+
+		if list == symbolListAllow {
+
+			allowList = append(allowList, symbol)
+
+		} else {
+
+			denyList = append(denyList, symbol)
+
+		}
+
+	}
+
+	sort.Strings(allowList)
+	sort.Strings(denyList)
+
+	return strings.Join(allowList, ","), strings.Join(denyList, ","), nil
+
+}
+
+// SetSymbolList replace the symbol allow/deny list with comma separated allow and deny symbols
+func SetSymbolList(
+	configData *types.Config,
+	sessionData *types.Session,
+	allow string,
+	deny string) (err error) {
+
+	allowList := parseSymbolList(allow)
+	denyList := parseSymbolList(deny)
+
+	for _, symbol := range allowList {
+
+		for _, denied := range denyList {
+
+			if symbol == denied {
+
+				err = errors.New(symbol + " in both symbol allow and deny lists")
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   nil,
+					Session:  sessionData,
+					Order:    &types.Order{},
+					Message:  "Symbol list not saved - " + err.Error(),
+					LogLevel: "InfoLevel",
+				}.Do()
+
+				return err
+
+			}
+
+		}
+
+	}
+
+	if err = mysql.DeleteSymbolList(sessionData); err != nil {
+
+		return err
+
+	}
+
+	for _, symbol := range allowList {
+
+		if err = mysql.SaveSymbolList(sessionData, symbol, symbolListAllow); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	for _, symbol := range denyList {
+
+		if err = mysql.SaveSymbolList(sessionData, symbol, symbolListDeny); err != nil {
+
+			return err
+
+		}
+
+	}
+
+	return nil
+
+}
+
+/* Return false when symbol is denied, or when the allow list is not empty and symbol is not allowed */
+func symbolAllowed(
+	symbol string,
+	symbolList map[string]string) bool {
+
+	switch symbolList[symbol] {
+	case symbolListAllow:
+
+		return true
+
+	case symbolListDeny:
+
+		return false
+
+	}
+
+	for _, list := range symbolList {
+
+		if list == symbolListAllow {
+
+			return false
+
+		}
+
+	}
+
+	return true
+
+}
+
+/* Parse a comma or space separated list of symbols in upper case, i.e. "btcusdt, ethusdt" */
+func parseSymbolList(symbols string) []string {
+
+	return strings.FieldsFunc(strings.ToUpper(symbols), func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+}
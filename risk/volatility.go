@@ -0,0 +1,166 @@
+package risk
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const volatilityReturnWindow = 60  /* Number of 1 minute returns used as baseline */
+const volatilitySpreadWindow = 120 /* Number of spread samples used as baseline */
+const volatilityMinSamples = 10    /* Minimum baseline samples before the circuit breaker is evaluated */
+
+// LoadVolatility trip the volatility circuit breaker suspending new buys when the current 1 minute return or the
+// bid/ask spread deviate from their baseline by more than the configured sigma thresholds. The circuit breaker
+// resets after conditions stayed normal for configData.BuyVolatilityResume minutes. Every trip is saved in the DB.
+func LoadVolatility(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) {
+
+	if configData.BuyVolatilityReturnSigma == 0 && configData.BuyVolatilitySpreadSigma == 0 { /* Circuit breaker disabled */
+
+		sessionData.VolatilityHalt = false
+		return
+
+	}
+
+	/* Sample spread for the spread baseline */
+	if marketData.Spread > 0 {
+
+		marketData.SpreadHistory = append(marketData.SpreadHistory, marketData.Spread)
+
+		if len(marketData.SpreadHistory) > volatilitySpreadWindow+1 {
+
+			marketData.SpreadHistory = marketData.SpreadHistory[len(marketData.SpreadHistory)-(volatilitySpreadWindow+1):]
+
+		}
+
+	}
+
+	reason, sigma, threshold := abnormalVolatility(configData, marketData)
+
+	if reason != "" {
+
+		sessionData.VolatilityTripTime = time.Now()
+
+		if sessionData.VolatilityHalt {
+
+			return
+
+		}
+
+		sessionData.VolatilityHalt = true
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   marketData,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  fmt.Sprintf("Volatility circuit breaker tripped - %s %.2f sigma above %.2f", reason, sigma, threshold),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		_ = mysql.SaveVolatilityTrip(sessionData, reason, sigma, threshold, time.Now().UnixNano()/int64(time.Millisecond))
+
+		return
+
+	}
+
+	if sessionData.VolatilityHalt &&
+		time.Since(sessionData.VolatilityTripTime) >= time.Duration(configData.BuyVolatilityResume)*time.Minute {
+
+		sessionData.VolatilityHalt = false
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   marketData,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Volatility circuit breaker reset - conditions normalized",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Return the reason, sigma and threshold of the first abnormal condition found, or an empty reason */
+func abnormalVolatility(
+	configData *types.Config,
+	marketData *types.Market) (reason string, sigma float64, threshold float64) {
+
+	if configData.BuyVolatilityReturnSigma > 0 &&
+		marketData.Series != nil &&
+		len(marketData.Series.Candles) > volatilityMinSamples {
+
+		candles := marketData.Series.Candles
+		if len(candles) > volatilityReturnWindow+1 {
+			candles = candles[len(candles)-(volatilityReturnWindow+1):]
+		}
+
+		var closes []float64
+		for _, candle := range candles {
+			closes = append(closes, candle.ClosePrice.Float())
+		}
+
+		/* Current return against the last closed 1 minute candle */
+		if last := closes[len(closes)-1]; last > 0 && marketData.Price > 0 {
+
+			if sigma = math.Abs(sigmaScore(returns(closes), marketData.Price/last-1)); sigma > configData.BuyVolatilityReturnSigma {
+
+				return "Return", sigma, configData.BuyVolatilityReturnSigma
+
+			}
+
+		}
+
+	}
+
+	if configData.BuyVolatilitySpreadSigma > 0 &&
+		len(marketData.SpreadHistory) > volatilityMinSamples {
+
+		history := marketData.SpreadHistory[:len(marketData.SpreadHistory)-1]
+
+		/* Only spread widening is abnormal */
+		if sigma = sigmaScore(history, marketData.Spread); sigma > configData.BuyVolatilitySpreadSigma {
+
+			return "Spread", sigma, configData.BuyVolatilitySpreadSigma
+
+		}
+
+	}
+
+	return "", 0, 0
+
+}
+
+/* Calculate how many standard deviations value is from the mean of history */
+func sigmaScore(history []float64, value float64) float64 {
+
+	if len(history) == 0 {
+
+		return 0
+
+	}
+
+	var sum float64
+	for _, v := range history {
+		sum += v
+	}
+
+	deviation := standardDeviation(history)
+
+	if deviation == 0 {
+
+		return 0
+
+	}
+
+	return (value - sum/float64(len(history))) / deviation
+
+}
@@ -0,0 +1,243 @@
+package scheduler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* Cron expression errors */
+var (
+	ErrCronFields = errors.New("Cron expression must have 5 fields (minute hour day-of-month month day-of-week)")
+	ErrCronValue  = errors.New("Invalid cron expression value")
+	ErrCronNever  = errors.New("Cron expression never runs")
+)
+
+/* Cron expression macros */
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+/* Range and names of a cron expression field */
+type field struct {
+	min   int
+	max   int
+	names []string /* Names of the values from min, i.e. jan or sun */
+}
+
+var fields = []field{
+	{min: 0, max: 59},
+	{min: 0, max: 23},
+	{min: 1, max: 31},
+	{min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// Cron is a parsed cron expression
+type Cron struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	anyDom bool /* Day of month is *, days match the day of week only */
+	anyDow bool /* Day of week is *, days match the day of month only */
+}
+
+// ParseCron parse a standard 5 fields cron expression (minute hour day-of-month month day-of-week) with lists, ranges,
+// steps, month and day names, and the @hourly, @daily, @weekly, @monthly and @yearly macros
+func ParseCron(spec string) (*Cron, error) {
+
+	spec = strings.ToLower(strings.TrimSpace(spec))
+
+	if macro, ok := macros[spec]; ok {
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+
+		return nil, ErrCronFields
+
+	}
+
+	bits := make([]uint64, len(fields))
+
+	for i, part := range parts {
+
+		var err error
+
+		if bits[i], err = parseField(part, fields[i]); err != nil {
+
+			return nil, err
+
+		}
+
+	}
+
+	if bits[4]&(1<<7) != 0 { /* 7 is also Sunday */
+		bits[4] |= 1
+	}
+
+	c := &Cron{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		anyDom: parts[2] == "*" || parts[2] == "?",
+		anyDow: parts[4] == "*" || parts[4] == "?",
+	}
+
+	if c.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() { /* i.e. 30 of February */
+
+		return nil, ErrCronNever
+
+	}
+
+	return c, nil
+
+}
+
+// Next return the first time after t that matches the cron expression, in the location of t, or the zero time when
+// none within 5 years
+func (c *Cron) Next(t time.Time) time.Time {
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+	}
+
+	return time.Time{}
+
+}
+
+/* Return true when the day of t matches the day of month and day of week, either of them when both are set */
+func (c *Cron) matchDay(t time.Time) bool {
+
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.anyDom && c.anyDow:
+		return true
+	case c.anyDom:
+		return dow
+	case c.anyDow:
+		return dom
+	}
+
+	return dom || dow
+
+}
+
+/* Parse a cron expression field as a bit for each value */
+func parseField(
+	part string,
+	f field) (bits uint64, err error) {
+
+	for _, item := range strings.Split(part, ",") {
+
+		low, high, step, stepped := f.min, f.max, 1, false
+
+		if i := strings.Index(item, "/"); i >= 0 {
+
+			stepped = true
+
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step < 1 {
+				return 0, ErrCronValue
+			}
+
+			item = item[:i]
+
+		}
+
+		switch {
+		case item == "*" || item == "?":
+		case strings.Contains(item, "-"):
+
+			bounds := strings.SplitN(item, "-", 2)
+
+			if low, err = parseValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+
+			if high, err = parseValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+
+		default:
+
+			if low, err = parseValue(item, f); err != nil {
+				return 0, err
+			}
+
+			if stepped { /* i.e. 5/15, from 5 to max */
+				high = f.max
+			} else {
+				high = low
+			}
+
+		}
+
+		if low > high {
+			return 0, ErrCronValue
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+
+	}
+
+	return bits, nil
+
+}
+
+/* Parse a cron expression field value, as a number or a name */
+func parseValue(
+	value string,
+	f field) (int, error) {
+
+	for i, name := range f.names {
+
+		if value == name {
+
+			return f.min + i, nil
+
+		}
+
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil || v < f.min || v > f.max {
+
+		return 0, ErrCronValue
+
+	}
+
+	return v, nil
+
+}
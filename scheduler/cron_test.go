@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	type args struct {
+		spec string
+		t    time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{
+			name: "weekend pause",
+			args: args{
+				spec: "0 0 * * sat",
+				t:    time.Date(2026, 10, 14, 12, 30, 0, 0, time.UTC), /* Wednesday */
+			},
+			want: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekdays step",
+			args: args{
+				spec: "*/15 9-17 * * mon-fri",
+				t:    time.Date(2026, 10, 16, 17, 50, 0, 0, time.UTC), /* Friday */
+			},
+			want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sunday as 7",
+			args: args{
+				spec: "30 6 * * 7",
+				t:    time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			},
+			want: time.Date(2026, 10, 18, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			args: args{
+				spec: "0 0 1 * mon",
+				t:    time.Date(2026, 10, 27, 0, 0, 0, 0, time.UTC), /* Tuesday */
+			},
+			want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "macro",
+			args: args{
+				spec: "@daily",
+				t:    time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC),
+			},
+			want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.args.spec)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := c.Next(tt.args.t); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr error
+	}{
+		{name: "fields", spec: "0 0 * *", wantErr: ErrCronFields},
+		{name: "range", spec: "0 24 * * *", wantErr: ErrCronValue},
+		{name: "name", spec: "0 0 * * weekend", wantErr: ErrCronValue},
+		{name: "step", spec: "*/0 * * * *", wantErr: ErrCronValue},
+		{name: "never", spec: "0 0 30 feb *", wantErr: ErrCronNever},
+		{name: "valid", spec: "0 22 * * fri", wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCron(tt.spec); err != tt.wantErr {
+				t.Errorf("ParseCron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
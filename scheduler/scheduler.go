@@ -0,0 +1,345 @@
+package scheduler
+
+/* This package implements the thread scheduler. Threads stop, start, pause and resume new buys on the cron expressions
+of their session configurations (i.e. pause over weekends with Schedule Pause 0 0 * * sat and Schedule Resume
+0 0 * * mon), in local time or UTC as the trading window (TimeUTC). Each expression runs on its first match after it
+is set or changed, so a schedule never runs the transitions missed while the thread was not running. A thread stopped
+by its schedule keeps its session state and is started again resuming its ThreadID on its Schedule Start expression,
+while the process keeps running. Every transition is logged and saved in the thread timeline. */
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/supervisor"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Schedule actions */
+const (
+	Start  = "start"  /* Start the thread stopped by its schedule */
+	Stop   = "stop"   /* Stop the thread */
+	Pause  = "pause"  /* Pause new buys */
+	Resume = "resume" /* Resume new buys */
+)
+
+const source = "schedule" /* Source of the transitions in the logs and thread timeline */
+
+// Run struct define the next run of a schedule action of a thread
+type Run struct {
+	Action string
+	Spec   string    /* Cron expression */
+	Next   time.Time /* Zero when the expression is invalid */
+	Error  string    /* Error of an invalid expression */
+}
+
+/* Next run of a schedule action */
+type next struct {
+	spec string
+	at   time.Time
+}
+
+/* Thread stopped by its schedule, started again on its Schedule Start expression */
+type stopped struct {
+	configData *types.Config
+	at         time.Time
+}
+
+var scheduler = struct {
+	sync.Mutex
+	start   supervisor.Starter
+	next    map[*types.Session]map[string]*next
+	stopped map[*types.Session]*stopped
+}{
+	next:    make(map[*types.Session]map[string]*next),
+	stopped: make(map[*types.Session]*stopped),
+}
+
+var starting sync.Once /* The threads stopped by their schedule are started by one goroutine of the process */
+
+// Register the Starter of the threads stopped by their schedule
+func Register(start supervisor.Starter) {
+
+	scheduler.Lock()
+	defer scheduler.Unlock()
+
+	scheduler.start = start
+
+}
+
+// Check the schedule of the thread at now and stop, pause or resume it when an expression is due. Called every 10
+// seconds.
+func Check(
+	configData *types.Config,
+	sessionData *types.Session,
+	now time.Time) {
+
+	if sessionData.Stopping {
+
+		scheduler.Lock()
+		delete(scheduler.next, sessionData)
+		scheduler.Unlock()
+
+		return
+
+	}
+
+	if sessionData.ThreadID == "" {
+
+		return
+
+	}
+
+	now = localize(configData, now)
+
+	for _, action := range []string{Stop, Pause, Resume} {
+
+		if sessionData.Stopping { /* Stopped by its schedule */
+
+			return
+
+		}
+
+		if due(sessionData, action, spec(configData, action), now) {
+
+			transition(configData, sessionData, action)
+
+		}
+
+	}
+
+}
+
+// Runs return the next run of each schedule action set in configData after now, in schedule order
+func Runs(
+	configData *types.Config,
+	now time.Time) (runs []Run) {
+
+	now = localize(configData, now)
+
+	for _, action := range []string{Start, Stop, Pause, Resume} {
+
+		s := spec(configData, action)
+		if s == "" {
+			continue
+		}
+
+		run := Run{Action: action, Spec: s}
+
+		if c, err := ParseCron(s); err != nil {
+			run.Error = err.Error()
+		} else {
+			run.Next = c.Next(now)
+		}
+
+		runs = append(runs, run)
+
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return !runs[i].Next.IsZero() && (runs[j].Next.IsZero() || runs[i].Next.Before(runs[j].Next))
+	})
+
+	return runs
+
+}
+
+/* Return true when the expression of action is due at now, and schedule its next run */
+func due(
+	sessionData *types.Session,
+	action string,
+	s string,
+	now time.Time) bool {
+
+	scheduler.Lock()
+	defer scheduler.Unlock()
+
+	actions, ok := scheduler.next[sessionData]
+	if !ok {
+		actions = make(map[string]*next)
+		scheduler.next[sessionData] = actions
+	}
+
+	if s == "" {
+
+		delete(actions, action)
+		return false
+
+	}
+
+	c, err := ParseCron(s)
+	if err != nil { /* Rejected by the configuration validation */
+
+		delete(actions, action)
+		return false
+
+	}
+
+	n, ok := actions[action]
+	if !ok || n.spec != s { /* Expression set or changed, runs on its next match */
+
+		actions[action] = &next{spec: s, at: c.Next(now)}
+		return false
+
+	}
+
+	if n.at.IsZero() || now.Before(n.at) {
+
+		return false
+
+	}
+
+	n.at = c.Next(now)
+
+	return true
+
+}
+
+/* Execute a schedule action of a running thread */
+func transition(
+	configData *types.Config,
+	sessionData *types.Session,
+	action string) {
+
+	switch action {
+	case Pause, Resume:
+
+		if sessionData.Paused == (action == Pause) {
+
+			return
+
+		}
+
+		_ = threads.Thread{}.Pause(sessionData, action == Pause, source) /* Logged and saved in the thread timeline */
+
+	case Stop:
+
+		message := "Thread stopped by " + source
+		startAt := time.Time{}
+
+		if c, err := ParseCron(configData.ScheduleStart); err == nil {
+
+			startAt = c.Next(localize(configData, time.Now()))
+			message += ", starting at " + startAt.Format("2006-01-02 15:04")
+
+		}
+
+		_ = mysql.SaveThreadEvent(sessionData, Stop, source, message) /* Thread timeline */
+
+		scheduler.Lock()
+
+		delete(scheduler.next, sessionData)
+
+		if !startAt.IsZero() {
+
+			scheduler.stopped[sessionData] = &stopped{configData: configData, at: startAt}
+
+		}
+
+		scheduler.Unlock()
+
+		if !startAt.IsZero() {
+
+			starting.Do(func() {
+
+				go startStopped()
+
+			})
+
+		}
+
+		threads.Thread{}.Stop(configData, sessionData, message)
+
+	}
+
+}
+
+/* Start the threads stopped by their schedule on their Schedule Start expression, checked every 10 seconds */
+func startStopped() {
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+
+		scheduler.Lock()
+
+		start := scheduler.start
+		var ready []*types.Session
+
+		for sessionData, s := range scheduler.stopped {
+
+			if !localize(s.configData, time.Now()).Before(s.at) {
+
+				ready = append(ready, sessionData)
+				delete(scheduler.stopped, sessionData)
+
+			}
+
+		}
+
+		scheduler.Unlock()
+
+		for _, sessionData := range ready {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  "Thread started by " + source,
+				LogLevel: "InfoLevel",
+			}.Do()
+
+			_ = mysql.SaveThreadEvent(sessionData, Start, source, "Thread started by "+source) /* Thread timeline */
+
+			if start != nil {
+
+				start(sessionData)
+
+			}
+
+		}
+
+	}
+
+}
+
+/* Return the cron expression of a schedule action */
+func spec(
+	configData *types.Config,
+	action string) string {
+
+	switch action {
+	case Start:
+		return configData.ScheduleStart
+	case Stop:
+		return configData.ScheduleStop
+	case Pause:
+		return configData.SchedulePause
+	case Resume:
+		return configData.ScheduleResume
+	}
+
+	return ""
+
+}
+
+/* Return now in UTC or local time as the trading window of configData */
+func localize(
+	configData *types.Config,
+	now time.Time) time.Time {
+
+	if configData.TimeUTC {
+
+		return now.UTC()
+
+	}
+
+	return now.Local()
+
+}
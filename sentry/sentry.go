@@ -0,0 +1,443 @@
+package sentry
+
+/* This package implements the error reporting to Sentry, or any server compatible with the Sentry envelope API
+(GlitchTip, Bugsink...), to the DSN of the global configuration (SentryDsn). Panics of the execution process and the
+websocket handlers, and critical notifications from any package are reported as events with the stack trace, the ThreadID, symbol and exchange tags, the environment (production or testnet) and the
+release version. Panics are reported before the process crashes as it did without reporting, or before the supervisor restarts the
+thread that panicked. */
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	sdkName     = "cryptopump"
+	framesMax   = 50               /* Stack frames of an event */
+	repeatDelay = 10 * time.Minute /* Delay before an event with the same key is reported again */
+)
+
+/* Event levels */
+const (
+	LevelFatal = "fatal"
+	LevelError = "error"
+)
+
+// Release is the version reported with the events, set at build time with
+// -ldflags "-X github.com/aleibovici/cryptopump/sentry.Release=<version>", the module version when empty
+var Release string
+
+// ErrInvalidDSN is returned for a DSN that is not <scheme>://<public key>@<host>[/<path>]/<project id>
+var ErrInvalidDSN = errors.New("Sentry DSN must be <scheme>://<public key>@<host>/<project id>")
+
+var client = &http.Client{Timeout: 5 * time.Second}
+
+var reported = struct {
+	sync.Mutex
+	keys map[string]time.Time /* Last report time of each event key */
+}{
+	keys: make(map[string]time.Time),
+}
+
+// DSN struct define the envelope endpoint and the public key of a Sentry DSN
+type DSN struct {
+	Endpoint  string /* <scheme>://<host>[/<path>]/api/<project id>/envelope/ */
+	PublicKey string
+}
+
+// Frame struct define a stack frame of an event
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// Exception struct define the exception of an event
+type Exception struct {
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	Stacktrace struct {
+		Frames []Frame `json:"frames"` /* Oldest call first */
+	} `json:"stacktrace"`
+}
+
+// Event struct define a Sentry event
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Tags        map[string]string `json:"tags"`
+	Exception   struct {
+		Values []Exception `json:"values"`
+	} `json:"exception"`
+}
+
+// ParseDSN return the envelope endpoint and the public key of dsn
+func ParseDSN(dsn string) (DSN, error) {
+
+	u, err := url.Parse(strings.TrimSpace(dsn))
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+
+		return DSN{}, ErrInvalidDSN
+
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	if i < 0 || path[i+1:] == "" {
+
+		return DSN{}, ErrInvalidDSN
+
+	}
+
+	return DSN{
+		Endpoint:  u.Scheme + "://" + u.Host + path[:i] + "/api/" + path[i+1:] + "/envelope/",
+		PublicKey: u.User.Username(),
+	}, nil
+
+}
+
+// Recover report a panic of the calling goroutine and panic again, called deferred at the start of a goroutine
+func Recover(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	r := recover()
+	if r == nil {
+
+		return
+
+	}
+
+	if enabled(configData) {
+
+		report(configData, sessionData, NewEvent(configData, sessionData, LevelFatal, "panic", fmt.Sprint(r), Stack(3)))
+
+	}
+
+	panic(r)
+
+}
+
+// Panicked report the panic r recovered by the caller instead of crashing, called from the deferred function that
+// recovered it
+func Panicked(
+	configData *types.Config,
+	sessionData *types.Session,
+	r interface{}) {
+
+	if enabled(configData) {
+
+		report(configData, sessionData, NewEvent(configData, sessionData, LevelError, "panic", fmt.Sprint(r), Stack(4)))
+
+	}
+
+}
+
+// Capture report an error of severity level with the stack trace of the caller in the background. An error with the
+// same key is reported at most once every repeatDelay, all errors are reported when key is empty.
+func Capture(
+	configData *types.Config,
+	sessionData *types.Session,
+	level string,
+	key string,
+	title string,
+	message string) {
+
+	if !enabled(configData) || !Due(key, time.Now()) {
+
+		return
+
+	}
+
+	event := NewEvent(configData, sessionData, level, title, message, Stack(3))
+
+	go report(configData, sessionData, event)
+
+}
+
+// Notification report the critical notifications sent by any package, registered with notify.Observe
+func Notification(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification notify.Notification) {
+
+	if notification.Severity != notify.Critical {
+
+		return
+
+	}
+
+	title := notification.Title
+	if title == "" {
+		title = notification.Event
+	}
+
+	message := notification.Data.Message
+	if message == "" {
+		message = title
+	}
+
+	Capture(configData, sessionData, LevelError, notification.Key, title, message)
+
+}
+
+// Due return whether an event with key can be reported at now, and record the report
+func Due(
+	key string,
+	now time.Time) bool {
+
+	if key == "" {
+
+		return true
+
+	}
+
+	reported.Lock()
+	defer reported.Unlock()
+
+	if last, ok := reported.keys[key]; ok && now.Sub(last) < repeatDelay {
+
+		return false
+
+	}
+
+	reported.keys[key] = now
+
+	return true
+
+}
+
+// NewEvent return an event of the exception kind: value with the stack frames and the thread context
+func NewEvent(
+	configData *types.Config,
+	sessionData *types.Session,
+	level string,
+	kind string,
+	value string,
+	frames []Frame) (event Event) {
+
+	event = Event{
+		EventID:     eventID(),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       level,
+		Logger:      sdkName,
+		Release:     release(),
+		Environment: "production",
+		Tags:        make(map[string]string),
+	}
+
+	event.ServerName, _ = os.Hostname()
+
+	if sessionData != nil && sessionData.ThreadID != "" {
+		event.Tags["thread"] = sessionData.ThreadID
+	}
+
+	if configData != nil {
+
+		if configData.TestNet {
+			event.Environment = "testnet"
+		}
+
+		if configData.Symbol != "" {
+			event.Tags["symbol"] = configData.Symbol
+		}
+
+		if configData.ExchangeName != "" {
+			event.Tags["exchange"] = configData.ExchangeName
+		}
+
+	}
+
+	exception := Exception{Type: kind, Value: value}
+	exception.Stacktrace.Frames = frames
+
+	event.Exception.Values = []Exception{exception}
+
+	return event
+
+}
+
+// Stack return the stack frames of the calling goroutine, oldest call first, skipping skip frames as runtime.Callers
+func Stack(skip int) (frames []Frame) {
+
+	pc := make([]uintptr, framesMax)
+	pc = pc[:runtime.Callers(skip, pc)]
+
+	callers := runtime.CallersFrames(pc)
+
+	for {
+
+		frame, more := callers.Next()
+
+		if frame.Function != "" {
+
+			module, function := "", frame.Function
+			if i := strings.LastIndex(function, "/"); i >= 0 {
+
+				if j := strings.Index(function[i:], "."); j >= 0 {
+					module, function = function[:i+j], function[i+j+1:]
+				}
+
+			} else if j := strings.Index(function, "."); j >= 0 {
+				module, function = function[:j], function[j+1:]
+			}
+
+			frames = append([]Frame{{
+				Function: function,
+				Module:   module,
+				Filename: frame.File,
+				Lineno:   frame.Line,
+				InApp:    strings.HasPrefix(module, "github.com/aleibovici/cryptopump"),
+			}}, frames...)
+
+		}
+
+		if !more {
+
+			break
+
+		}
+
+	}
+
+	return frames
+
+}
+
+/* Return whether error reporting is enabled */
+func enabled(configData *types.Config) bool {
+
+	return configData != nil && configData.ConfigGlobal != nil && configData.ConfigGlobal.SentryDsn != ""
+
+}
+
+/* Send event to the DSN, logging the failures */
+func report(
+	configData *types.Config,
+	sessionData *types.Session,
+	event Event) {
+
+	if err := post(configData.ConfigGlobal.SentryDsn, event); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Post event to the envelope endpoint of dsn */
+func post(
+	dsn string,
+	event Event) (err error) {
+
+	var endpoint DSN
+	var payload []byte
+	var request *http.Request
+	var response *http.Response
+
+	if endpoint, err = ParseDSN(dsn); err != nil {
+
+		return err
+
+	}
+
+	if payload, err = json.Marshal(event); err != nil {
+
+		return err
+
+	}
+
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "{\"event_id\":%q,\"sent_at\":%q}\n", event.EventID, time.Now().UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(body, "{\"type\":\"event\",\"length\":%d}\n", len(payload))
+	body.Write(payload)
+	body.WriteString("\n")
+
+	if request, err = http.NewRequest("POST", endpoint.Endpoint, body); err != nil {
+
+		return err
+
+	}
+
+	request.Header.Set("Content-Type", "application/x-sentry-envelope")
+	request.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client="+sdkName+"/"+release()+", sentry_key="+endpoint.PublicKey)
+
+	if response, err = client.Do(request); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+
+		return fmt.Errorf("Sentry returned %s", response.Status)
+
+	}
+
+	return nil
+
+}
+
+/* Return Release, or the module version of the build */
+func release() string {
+
+	if Release != "" {
+
+		return Release
+
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+
+		return info.Main.Version
+
+	}
+
+	return ""
+
+}
+
+/* Return a random event ID of 32 hex characters */
+func eventID() string {
+
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+
+}
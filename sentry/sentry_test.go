@@ -0,0 +1,89 @@
+package sentry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParseDSN(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		dsn     string
+		want    DSN
+		wantErr bool
+	}{
+		{name: "sentry", dsn: "https://abc123@o42.ingest.sentry.io/7", want: DSN{Endpoint: "https://o42.ingest.sentry.io/api/7/envelope/", PublicKey: "abc123"}},
+		{name: "path", dsn: "http://key@glitchtip.local:8000/sentry/3/", want: DSN{Endpoint: "http://glitchtip.local:8000/sentry/api/3/envelope/", PublicKey: "key"}},
+		{name: "no key", dsn: "https://o42.ingest.sentry.io/7", wantErr: true},
+		{name: "no project", dsn: "https://key@o42.ingest.sentry.io", wantErr: true},
+		{name: "empty", dsn: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDSN(tt.dsn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDSN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDSN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestDue(t *testing.T) {
+
+	now := time.Now()
+
+	if !Due("", now) || !Due("", now) {
+		t.Errorf("Due() without key = false, want true")
+	}
+
+	if !Due("fault-1", now) {
+		t.Errorf("Due() first = false, want true")
+	}
+
+	if Due("fault-1", now.Add(repeatDelay-time.Second)) {
+		t.Errorf("Due() within repeatDelay = true, want false")
+	}
+
+	if !Due("fault-1", now.Add(repeatDelay)) {
+		t.Errorf("Due() after repeatDelay = false, want true")
+	}
+
+}
+
+func TestNewEvent(t *testing.T) {
+
+	configData := &types.Config{Symbol: "BTCUSDT", ExchangeName: "BINANCE", TestNet: true}
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg"}
+
+	got := NewEvent(configData, sessionData, LevelError, "System fault", "System Fault @ c683ok5mk1u1120gnmmg", Stack(1))
+
+	if len(got.EventID) != 32 || got.Level != LevelError || got.Environment != "testnet" {
+		t.Errorf("NewEvent() = %v", got)
+	}
+
+	if got.Tags["thread"] != "c683ok5mk1u1120gnmmg" || got.Tags["symbol"] != "BTCUSDT" || got.Tags["exchange"] != "BINANCE" {
+		t.Errorf("NewEvent() Tags = %v", got.Tags)
+	}
+
+	frames := got.Exception.Values[0].Stacktrace.Frames
+	if len(frames) == 0 {
+		t.Fatalf("NewEvent() no frames")
+	}
+
+	last := frames[len(frames)-1] /* Most recent call last */
+	if last.Function != "Stack" || !strings.HasSuffix(last.Module, "/sentry") || !last.InApp {
+		t.Errorf("NewEvent() last frame = %v", last)
+	}
+
+}
@@ -0,0 +1,179 @@
+package settings
+
+import "math"
+
+/* Parameter kinds */
+const (
+	KindFloat  = "float"
+	KindInt    = "int"
+	KindBool   = "bool"
+	KindEnum   = "enum"
+	KindTime   = "time"
+	KindString = "string"
+	KindCron   = "cron"
+)
+
+var unbounded = math.Inf(1) /* No upper or lower limit */
+
+// Parameter struct define a session configuration parameter in the config editor
+type Parameter struct {
+	Key         string /* Key in the config section of the configuration file */
+	Label       string
+	Section     string
+	Kind        string
+	Min         float64 /* Lower limit for KindFloat and KindInt */
+	Max         float64 /* Upper limit for KindFloat and KindInt */
+	Options     []string
+	Immutable   bool /* Cannot change while the thread is running */
+	Description string
+}
+
+// Parameters list every session configuration parameter in config editor order
+var Parameters = []Parameter{
+	{Key: "buy_quantity_fiat_up", Label: "Buy Quantity FIAT Upmarket", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Quantity in Symbol FIAT bought when the market direction is up and price is above the lowest open transaction."},
+	{Key: "buy_quantity_fiat_down", Label: "Buy Quantity FIAT Downmarket", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Quantity in Symbol FIAT bought when the market direction is down."},
+	{Key: "buy_quantity_fiat_init", Label: "Buy Quantity FIAT Initial", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Quantity in Symbol FIAT of the first buy."},
+	{Key: "buy_direction_up", Label: "Buy Direction Upmarket", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Consecutive upward price movements required before an upmarket buy."},
+	{Key: "buy_direction_down", Label: "Buy Direction Downmarket", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Consecutive downward price movements required before a downmarket buy."},
+	{Key: "buy_rsi7_entry", Label: "Buy on RSI7", Section: "Buy", Kind: KindFloat, Min: 0, Max: 100,
+		Description: "RSI7 must be lower than this value for a buy."},
+	{Key: "buy_macd_entry", Label: "Buy MACD Entry", Section: "Buy", Kind: KindFloat, Min: -unbounded, Max: unbounded,
+		Description: "Legacy MACD entry, not used by the buy decision tree."},
+	{Key: "buy_macd_upmarket", Label: "Buy MACD Upmarket", Section: "Buy", Kind: KindFloat, Min: -unbounded, Max: unbounded,
+		Description: "Legacy MACD upmarket entry, not used by the buy decision tree."},
+	{Key: "buy_24hs_highprice_entry", Label: "Buy 24hs HighPrice Entry", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Maximum price as ratio below the 24 hours high price for a buy, i.e. 0.0003."},
+	{Key: "buy_24hs_highprice_entry_macd", Label: "Buy 24hs HighPrice Entry MACD", Section: "Buy", Kind: KindFloat, Min: -unbounded, Max: unbounded,
+		Description: "Legacy MACD limit for Buy 24hs HighPrice Entry, not used by the buy decision tree."},
+	{Key: "buy_repeat_threshold_up", Label: "Buy Repeat Threshold Up", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Price increase as ratio over the last buy before another buy."},
+	{Key: "buy_repeat_threshold_down", Label: "Buy Repeat Threshold Down", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Price decrease as ratio below the last buy before another buy."},
+	{Key: "buy_repeat_threshold_down_second", Label: "Buy Repeat Threshold 2nd Down", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Price decrease as ratio below the last buy used after consecutive downmarket buys."},
+	{Key: "buy_repeat_threshold_down_second_start_count", Label: "Buy Repeat Threshold 2nd Down Start Count", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Number of downmarket buys before Buy Repeat Threshold 2nd Down applies."},
+	{Key: "buy_wait", Label: "Buy Wait", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Minimum seconds between buys."},
+	{Key: "buy_orderbook_depth_bps", Label: "Order Book Depth (bps)", Section: "Buy", Kind: KindFloat, Min: 0, Max: 10000,
+		Description: "Window around the order book mid price, in basis points, used to measure bid and ask volume."},
+	{Key: "buy_orderbook_ask_bid_ratio", Label: "Order Book Ask/Bid Ratio", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Buys are suppressed while ask volume exceeds bid volume by this ratio (0 disables)."},
+	{Key: "buy_slippage_max_bps", Label: "Slippage Max (bps)", Section: "Buy", Kind: KindFloat, Min: 0, Max: 10000,
+		Description: "Abort a buy when its expected fill price is above market price by more than this value (0 disables)."},
+	{Key: "buy_volatility_return_sigma", Label: "Volatility Return Sigma", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Suspend buys when the 1 minute return deviates by more than this number of standard deviations (0 disables)."},
+	{Key: "buy_volatility_spread_sigma", Label: "Volatility Spread Sigma", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Suspend buys when the bid/ask spread widens by more than this number of standard deviations (0 disables)."},
+	{Key: "buy_volatility_resume", Label: "Volatility Resume", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Minutes without abnormal return or spread before buys resume."},
+	{Key: "buy_loss_streak_count", Label: "Loss Streak Count", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Consecutive losing cycles after which new entries are paused (0 disables)."},
+	{Key: "buy_loss_streak_cooldown", Label: "Loss Streak Cooldown", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Minutes new entries are paused after a loss streak."},
+	{Key: "buy_loss_streak_trend_exit", Label: "Loss Streak Trend Exit", Section: "Buy", Kind: KindBool,
+		Description: "End the cooldown early when MA7 crosses above MA14."},
+	{Key: "buy_event_blackout", Label: "Event Blackout", Section: "Buy", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Minutes before and after high-impact economic events without buys (0 disables)."},
+	{Key: "buy_correlation_max", Label: "Correlation Threshold", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Correlation above which symbols of other threads belong to this thread cluster (0 disables)."},
+	{Key: "buy_correlation_exposure_max", Label: "Correlation Exposure Max", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Maximum Symbol FIAT held across the correlated cluster."},
+	{Key: "buy_correlation_window", Label: "Correlation Window", Section: "Buy", Kind: KindInt, Min: 2, Max: 1000,
+		Description: "Hourly candles used to calculate the rolling correlation."},
+	{Key: "buy_exposure_max", Label: "Max Thread Exposure", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Maximum open exposure in Symbol FIAT for the thread (0 disables)."},
+	{Key: "buy_sizing_mode", Label: "Sizing Mode", Section: "Buy", Kind: KindEnum, Options: []string{"fixed", "fraction", "volatility", "kelly"},
+		Description: "fixed uses the Buy Quantity FIAT settings, fraction a fraction of equity, volatility a volatility targeted fraction and kelly the Kelly fraction."},
+	{Key: "buy_sizing_fraction", Label: "Sizing Fraction", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Fraction of equity used on each buy in fraction mode."},
+	{Key: "buy_sizing_volatility_target", Label: "Sizing Volatility Target", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Fraction of equity divided by the 1 minute volatility in volatility mode."},
+	{Key: "buy_sizing_kelly_fraction", Label: "Sizing Kelly Fraction", Section: "Buy", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Multiplier applied to the Kelly fraction in kelly mode."},
+	{Key: "buy_score_threshold", Label: "Buy Score Threshold", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Total indicator score that fires a buy, replacing the RSI7 and direction rules (0 disables)."},
+	{Key: "buy_score_rsi7", Label: "Buy Score RSI7", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Score added when RSI7 is lower than Buy on RSI7."},
+	{Key: "buy_score_macd", Label: "Buy Score MACD", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Score added when MACD is positive."},
+	{Key: "buy_score_direction", Label: "Buy Score Direction", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Score added when the market direction is reached."},
+	{Key: "buy_score_ma", Label: "Buy Score MA", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Score added when MA7 is above MA14."},
+	{Key: "buy_score_orderbook", Label: "Buy Score Order Book", Section: "Buy", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Score added when bid depth exceeds ask depth near mid price."},
+	{Key: "profit_min", Label: "Minimum Profit", Section: "Sell", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Minimum profit as ratio over the exchange commission for a sale."},
+	{Key: "profit_net_min", Label: "Minimum Net Profit", Section: "Sell", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Minimum net profit as ratio after round-trip commissions for a profit sale."},
+	{Key: "sellwaitaftercancel", Label: "Wait After Cancel", Section: "Sell", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Seconds to wait after cancelling an order."},
+	{Key: "sellwaitbeforecancel", Label: "Wait Before Cancel", Section: "Sell", Kind: KindInt, Min: 0, Max: unbounded,
+		Description: "Seconds to wait before cancelling an order."},
+	{Key: "selltocover", Label: "Sell-to-Cover Low Funds", Section: "Sell", Kind: KindBool,
+		Description: "Sell the highest priced transaction at a loss when funds are too low to buy."},
+	{Key: "sellholdonrsi3", Label: "Hold Sale on RSI3", Section: "Sell", Kind: KindFloat, Min: 0, Max: 100,
+		Description: "Hold sales while RSI3 is above this value."},
+	{Key: "stoploss", Label: "Stoploss", Section: "Sell", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Loss as ratio below the purchase price that triggers a sale (0 disables)."},
+	{Key: "sell_volatility_stoploss", Label: "Volatility Stoploss", Section: "Sell", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Stoploss used while the volatility circuit breaker is tripped (0 keeps Stoploss)."},
+	{Key: "selltrailingactivation", Label: "Trailing Stop Activation", Section: "Sell", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Aggregate profit as ratio that activates the trailing stop (0 disables)."},
+	{Key: "selltrailingdistance", Label: "Trailing Stop Distance", Section: "Sell", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Pullback as ratio from the high since activation that sells all thread transactions."},
+	{Key: "sell_confirm_notional", Label: "Sell Confirm Notional", Section: "Sell", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Manual sales above this amount in Symbol FIAT require confirmation (0 disables)."},
+	{Key: "exchangename", Label: "Exchange Name", Section: "Exchange", Kind: KindEnum, Options: []string{"BINANCE"}, Immutable: true,
+		Description: "Exchange used by the thread."},
+	{Key: "exchange_comission", Label: "Exchange Commission", Section: "Exchange", Kind: KindFloat, Min: 0, Max: 0.1,
+		Description: "Commission per order as ratio, used until the account commission is loaded."},
+	{Key: "symbol", Label: "Symbol", Section: "Exchange", Kind: KindString, Immutable: true,
+		Description: "Pair traded by the thread, i.e. BTCUSDT."},
+	{Key: "symbol_fiat", Label: "Symbol FIAT", Section: "Exchange", Kind: KindString, Immutable: true,
+		Description: "FIAT currency of the pair, i.e. USDT."},
+	{Key: "symbol_fiat_stash", Label: "Symbol FIAT Stash", Section: "Exchange", Kind: KindFloat, Min: 0, Max: unbounded,
+		Description: "Symbol FIAT preserved in the wallet."},
+	{Key: "testnet", Label: "TestNet", Section: "Exchange", Kind: KindBool, Immutable: true,
+		Description: "Trade on the exchange testnet."},
+	{Key: "market_data_stale_timeout", Label: "Market Data Stale Timeout", Section: "Exchange", Kind: KindInt, Min: 1, Max: unbounded,
+		Description: "Seconds without market data updates after which trading decisions stop and websockets reconnect."},
+	{Key: "time_enforce", Label: "Enforce Time", Section: "Time", Kind: KindBool,
+		Description: "Open new positions only between Start Time and Stop Time."},
+	{Key: "time_start", Label: "Start Time", Section: "Time", Kind: KindTime,
+		Description: "Start of the trading window, 3:04PM or 15:04."},
+	{Key: "time_stop", Label: "Stop Time", Section: "Time", Kind: KindTime,
+		Description: "End of the trading window, 3:04PM or 15:04."},
+	{Key: "time_utc", Label: "UTC Time", Section: "Time", Kind: KindBool,
+		Description: "Start Time and Stop Time are UTC instead of server local time."},
+	{Key: "time_skip_weekends", Label: "Skip Weekends", Section: "Time", Kind: KindBool,
+		Description: "No new positions on Saturdays and Sundays."},
+	{Key: "schedule_start", Label: "Schedule Start", Section: "Schedule", Kind: KindCron,
+		Description: "Cron expression that starts the thread stopped by Schedule Stop, i.e. 0 0 * * mon. Empty to disable."},
+	{Key: "schedule_stop", Label: "Schedule Stop", Section: "Schedule", Kind: KindCron,
+		Description: "Cron expression that stops the thread, i.e. 0 0 * * sat. Empty to disable."},
+	{Key: "schedule_pause", Label: "Schedule Pause", Section: "Schedule", Kind: KindCron,
+		Description: "Cron expression that pauses new buys, i.e. 0 22 * * fri. Empty to disable."},
+	{Key: "schedule_resume", Label: "Schedule Resume", Section: "Schedule", Kind: KindCron,
+		Description: "Cron expression that resumes new buys, i.e. 0 6 * * mon. Empty to disable."},
+	{Key: "debug", Label: "Debug", Section: "Others", Kind: KindBool,
+		Description: "Debug output on logs."},
+	{Key: "exit", Label: "Exit", Section: "Others", Kind: KindBool,
+		Description: "Stop buying and close the thread once all transactions are sold."},
+	{Key: "dryrun", Label: "DryRun", Section: "Others", Kind: KindBool,
+		Description: "Run without executing buy or sell orders."},
+	{Key: "newsession", Label: "New Session", Section: "Others", Kind: KindBool, Immutable: true,
+		Description: "Start a new session on the next start."},
+	{Key: "rebalance", Label: "Rebalance", Section: "Others", Kind: KindBool,
+		Description: "Maintain Rebalance Weights across a basket of assets instead of trading the symbol."},
+	{Key: "rebalance_weights", Label: "Rebalance Weights", Section: "Others", Kind: KindString,
+		Description: "Target weights as ASSET:weight separated by commas, including Symbol FIAT, adding up to 1."},
+	{Key: "rebalance_band", Label: "Rebalance Band", Section: "Others", Kind: KindFloat, Min: 0, Max: 1,
+		Description: "Weight drift as ratio that triggers a rebalance order."},
+}
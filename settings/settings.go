@@ -0,0 +1,513 @@
+package settings
+
+/* The config editor shows every session configuration parameter with its description, validates
+submitted values server-side and saves them in the thread configuration file. Running threads reload
+the configuration file every 10 seconds, so changes apply without a restart. Every save is stored as
+a new version in the configaudit table with the user and the changed values. */
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/rebalancer"
+	"github.com/aleibovici/cryptopump/scheduler"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const versionLimit = 20 /* Configuration versions listed in the config editor */
+
+/* Validation errors */
+var (
+	ErrInvalidNumber = errors.New("Must be a number")
+	ErrInvalidInt    = errors.New("Must be a whole number")
+	ErrOutOfRange    = errors.New("Out of range")
+	ErrInvalidOption = errors.New("Invalid option")
+	ErrInvalidTime   = errors.New("Must be a time as 3:04PM or 15:04")
+	ErrInvalidCron   = errors.New("Must be a cron expression as minute hour day-of-month month day-of-week")
+	ErrImmutable     = errors.New("Cannot change while the thread is running")
+	ErrConflict      = errors.New("Conflicting values")
+	ErrNoChanges     = errors.New("No changes")
+)
+
+// ParameterError struct define a validation error of a parameter
+type ParameterError struct {
+	Key    string
+	Err    error
+	Detail string
+}
+
+func (e ParameterError) Error() string {
+
+	if e.Detail != "" {
+
+		return e.Key + " - " + e.Err.Error() + ": " + e.Detail
+
+	}
+
+	return e.Key + " - " + e.Err.Error()
+
+}
+
+func (e ParameterError) Unwrap() error {
+
+	return e.Err
+
+}
+
+/* Rule between parameters, checked when any of its keys changes */
+type rule struct {
+	keys  []string
+	check func(values map[string]string) string /* Return the conflict, or empty */
+}
+
+/* Rules between parameters, the error is reported on the first key */
+var rules = []rule{
+	{
+		keys: []string{"selltrailingactivation", "selltrailingdistance"},
+		check: func(values map[string]string) string {
+			if number(values["selltrailingactivation"]) > 0 && number(values["selltrailingdistance"]) <= 0 {
+				return "Trailing Stop Distance must be above 0 when Trailing Stop Activation is set"
+			}
+			return ""
+		},
+	},
+	{
+		keys: []string{"time_enforce", "time_start", "time_stop"},
+		check: func(values map[string]string) string {
+			if values["time_enforce"] == "true" && normalizeTime(values["time_start"]) == normalizeTime(values["time_stop"]) {
+				return "Start Time and Stop Time must differ when Enforce Time is true"
+			}
+			return ""
+		},
+	},
+	{
+		keys: []string{"buy_sizing_mode", "buy_sizing_fraction", "buy_sizing_volatility_target", "buy_sizing_kelly_fraction"},
+		check: func(values map[string]string) string {
+			required := map[string]string{
+				"fraction":   "buy_sizing_fraction",
+				"volatility": "buy_sizing_volatility_target",
+				"kelly":      "buy_sizing_kelly_fraction",
+			}
+			if key, ok := required[values["buy_sizing_mode"]]; ok && number(values[key]) <= 0 {
+				return key + " must be above 0 in " + values["buy_sizing_mode"] + " mode"
+			}
+			return ""
+		},
+	},
+	{
+		keys: []string{"buy_score_threshold", "buy_score_rsi7", "buy_score_macd", "buy_score_direction", "buy_score_ma", "buy_score_orderbook"},
+		check: func(values map[string]string) string {
+			total := number(values["buy_score_rsi7"]) + number(values["buy_score_macd"]) + number(values["buy_score_direction"]) +
+				number(values["buy_score_ma"]) + number(values["buy_score_orderbook"])
+			if threshold := number(values["buy_score_threshold"]); threshold > 0 && threshold > total {
+				return "Buy Score Threshold is above the sum of the buy scores and would never buy"
+			}
+			return ""
+		},
+	},
+	{
+		keys: []string{"buy_correlation_max", "buy_correlation_exposure_max"},
+		check: func(values map[string]string) string {
+			if number(values["buy_correlation_max"]) > 0 && number(values["buy_correlation_exposure_max"]) <= 0 {
+				return "Correlation Exposure Max must be above 0 when Correlation Threshold is set"
+			}
+			return ""
+		},
+	},
+	{
+		keys: []string{"sell_volatility_stoploss", "buy_volatility_return_sigma", "buy_volatility_spread_sigma"},
+		check: func(values map[string]string) string {
+			if number(values["sell_volatility_stoploss"]) > 0 &&
+				number(values["buy_volatility_return_sigma"]) == 0 && number(values["buy_volatility_spread_sigma"]) == 0 {
+				return "Volatility Stoploss requires Volatility Return Sigma or Volatility Spread Sigma"
+			}
+			return ""
+		},
+	},
+	{
+		keys: []string{"rebalance", "rebalance_weights"},
+		check: func(values map[string]string) string {
+			if values["rebalance"] == "true" {
+				if err := rebalancer.ValidateWeights(values["rebalance_weights"]); err != nil {
+					return err.Error()
+				}
+			}
+			return ""
+		},
+	},
+}
+
+// Validate check the submitted values against the parameters and the rules between parameters, and
+// return the changed values normalized for the configuration file. Unchanged values are not checked
+// so a legacy value never blocks an unrelated change.
+func Validate(
+	current map[string]interface{},
+	submitted map[string]string,
+	running bool) (changes map[string]string, errs []error) {
+
+	changes = make(map[string]string)
+	values := make(map[string]string)
+
+	for _, parameter := range Parameters {
+
+		values[parameter.Key] = currentValue(current, parameter.Key)
+
+		value, ok := submitted[parameter.Key]
+		if !ok {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if value == values[parameter.Key] {
+			continue
+		}
+
+		if running && parameter.Immutable {
+			errs = append(errs, ParameterError{Key: parameter.Key, Err: ErrImmutable})
+			continue
+		}
+
+		if err := validateValue(parameter, value); err != nil {
+			errs = append(errs, ParameterError{Key: parameter.Key, Err: err, Detail: value})
+			continue
+		}
+
+		if parameter.Kind == KindTime {
+			value = normalizeTime(value)
+		}
+
+		values[parameter.Key] = value
+		changes[parameter.Key] = value
+
+	}
+
+	for _, r := range rules {
+
+		if !changed(changes, r.keys) {
+			continue
+		}
+
+		if conflict := r.check(values); conflict != "" {
+			errs = append(errs, ParameterError{Key: r.keys[0], Err: ErrConflict, Detail: conflict})
+		}
+
+	}
+
+	if len(errs) > 0 {
+
+		return nil, errs
+
+	}
+
+	return changes, nil
+
+}
+
+// Save apply the changed values to the session configuration file and store the new configuration version.
+// Running threads load the configuration file every 10 seconds.
+func Save(
+	viperData *types.ViperData,
+	sessionData *types.Session,
+	username string,
+	changes map[string]string) (err error) {
+
+	var config []byte
+
+	if len(changes) == 0 {
+
+		return ErrNoChanges
+
+	}
+
+	current := viperData.V1.GetStringMap("config")
+	summary := diff(current, changes)
+
+	for key, value := range changes {
+
+		viperData.V1.Set("config."+key, value)
+
+	}
+
+	if err = viperData.V1.WriteConfig(); err != nil {
+
+		return err
+
+	}
+
+	if config, err = json.Marshal(viperData.V1.GetStringMap("config")); err != nil {
+
+		return err
+
+	}
+
+	if err = mysql.SaveConfigAudit(sessionData, username, string(config), strings.Join(summary, ", ")); err != nil {
+
+		return err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   nil,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Configuration updated by " + username + " - " + strings.Join(summary, ", "),
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+// Field struct define a parameter and its value in the config editor
+type Field struct {
+	Parameter
+	Value    string
+	Error    string
+	Disabled bool
+}
+
+// Section struct define a group of parameters in the config editor
+type Section struct {
+	Name   string
+	Fields []Field
+}
+
+// Editor struct define the config editor page (config.html)
+type Editor struct {
+	ThreadID string
+	Sections []Section
+	Versions []Version
+	Message  string
+	CanAdmin bool
+	Theme    string /* UI theme of the logged in user */
+}
+
+// Version struct define a configuration version in the config editor
+type Version struct {
+	types.ConfigVersion
+	Date string
+}
+
+// LoadEditor Load the parameters, their values and the configuration versions for the config editor.
+// Submitted values and errors are shown when a save failed validation.
+func LoadEditor(
+	configData *types.Config,
+	sessionData *types.Session,
+	current map[string]interface{},
+	submitted map[string]string,
+	errs []error) (editor Editor) {
+
+	var versions []types.ConfigVersion
+	var err error
+
+	running := sessionData.ThreadID != "" /* Thread running in this session */
+
+	editor.ThreadID = sessionData.ThreadID
+	editor.CanAdmin = configData.CanAdmin
+
+	messages := make(map[string]string)
+	for _, e := range errs {
+
+		var parameterError ParameterError
+
+		if errors.As(e, &parameterError) {
+			messages[parameterError.Key] = parameterError.Err.Error()
+			if parameterError.Err == ErrConflict {
+				messages[parameterError.Key] = parameterError.Detail
+			}
+		} else {
+			editor.Message = e.Error()
+		}
+
+	}
+
+	for _, parameter := range Parameters {
+
+		field := Field{
+			Parameter: parameter,
+			Value:     currentValue(current, parameter.Key),
+			Error:     messages[parameter.Key],
+			Disabled:  !configData.CanAdmin || (running && parameter.Immutable),
+		}
+
+		if value, ok := submitted[parameter.Key]; ok {
+			field.Value = value
+		}
+
+		if len(editor.Sections) == 0 || editor.Sections[len(editor.Sections)-1].Name != parameter.Section {
+			editor.Sections = append(editor.Sections, Section{Name: parameter.Section})
+		}
+
+		section := &editor.Sections[len(editor.Sections)-1]
+		section.Fields = append(section.Fields, field)
+
+	}
+
+	if versions, err = mysql.GetConfigAudit(sessionData, versionLimit); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	for _, version := range versions {
+
+		editor.Versions = append(editor.Versions, Version{
+			ConfigVersion: version,
+			Date:          time.Unix((version.Time / 1000), 0).Local().Format("2006-01-02 15:04:05"),
+		})
+
+	}
+
+	return editor
+
+}
+
+/* Check a value against its parameter kind and limits */
+func validateValue(
+	parameter Parameter,
+	value string) error {
+
+	switch parameter.Kind {
+	case KindFloat, KindInt:
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return ErrInvalidNumber
+		}
+
+		if parameter.Kind == KindInt && v != float64(int64(v)) {
+			return ErrInvalidInt
+		}
+
+		if v < parameter.Min || v > parameter.Max {
+			return ErrOutOfRange
+		}
+
+	case KindBool:
+
+		if value != "true" && value != "false" {
+			return ErrInvalidOption
+		}
+
+	case KindEnum:
+
+		for _, option := range parameter.Options {
+			if value == option {
+				return nil
+			}
+		}
+
+		return ErrInvalidOption
+
+	case KindTime:
+
+		if normalizeTime(value) == "" {
+			return ErrInvalidTime
+		}
+
+	case KindCron:
+
+		if _, err := scheduler.ParseCron(value); value != "" && err != nil {
+			return ErrInvalidCron
+		}
+
+	}
+
+	return nil
+
+}
+
+/* Return a time as 3:04PM or 15:04 in 15:04 format, or empty when invalid */
+func normalizeTime(value string) string {
+
+	if t, err := time.Parse(time.Kitchen, value); err == nil {
+
+		return t.Format("15:04")
+
+	}
+
+	if t, err := time.Parse("15:04", value); err == nil {
+
+		return t.Format("15:04")
+
+	}
+
+	return ""
+
+}
+
+/* Return the current value of key as string, empty when not set */
+func currentValue(
+	current map[string]interface{},
+	key string) string {
+
+	if value, ok := current[key]; ok && value != nil {
+
+		return fmt.Sprint(value)
+
+	}
+
+	return ""
+
+}
+
+/* Return a number value, 0 when invalid */
+func number(value string) float64 {
+
+	v, _ := strconv.ParseFloat(value, 64)
+
+	return v
+
+}
+
+/* Return true when any of keys changed */
+func changed(
+	changes map[string]string,
+	keys []string) bool {
+
+	for _, key := range keys {
+
+		if _, ok := changes[key]; ok {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Return the changes as "key: old -> new" sorted by key */
+func diff(
+	current map[string]interface{},
+	changes map[string]string) (summary []string) {
+
+	for key, value := range changes {
+
+		summary = append(summary, key+": "+currentValue(current, key)+" -> "+value)
+
+	}
+
+	sort.Strings(summary)
+
+	return summary
+
+}
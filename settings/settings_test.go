@@ -0,0 +1,208 @@
+package settings
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	current := map[string]interface{}{
+		"stoploss":                     "0",
+		"buy_wait":                     "60",
+		"buy_sizing_mode":              "fixed",
+		"buy_sizing_fraction":          "0",
+		"selltrailingactivation":       "0",
+		"selltrailingdistance":         "0.01",
+		"symbol":                       "BTCUSDT",
+		"time_enforce":                 "false",
+		"time_start":                   "04:00AM",
+		"time_stop":                    "07:00PM",
+		"rebalance":                    "false",
+		"rebalance_weights":            "",
+		"buy_correlation_window":       "1", /* Legacy value out of range */
+		"buy_correlation_max":          "0",
+		"buy_correlation_exposure_max": "0",
+	}
+	type args struct {
+		submitted map[string]string
+		running   bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "valid changes",
+			args: args{
+				submitted: map[string]string{"stoploss": "0.05", "buy_wait": " 30 ", "symbol": "BTCUSDT", "buy_correlation_window": "1"},
+				running:   true,
+			},
+			want:    map[string]string{"stoploss": "0.05", "buy_wait": "30"},
+			wantErr: nil,
+		},
+		{
+			name: "not a number",
+			args: args{
+				submitted: map[string]string{"stoploss": "five"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrInvalidNumber,
+		},
+		{
+			name: "out of range",
+			args: args{
+				submitted: map[string]string{"stoploss": "1.5"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrOutOfRange,
+		},
+		{
+			name: "not a whole number",
+			args: args{
+				submitted: map[string]string{"buy_wait": "2.5"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrInvalidInt,
+		},
+		{
+			name: "invalid option",
+			args: args{
+				submitted: map[string]string{"buy_sizing_mode": "martingale"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrInvalidOption,
+		},
+		{
+			name: "immutable while running",
+			args: args{
+				submitted: map[string]string{"symbol": "ETHUSDT"},
+				running:   true,
+			},
+			want:    nil,
+			wantErr: ErrImmutable,
+		},
+		{
+			name: "immutable while stopped",
+			args: args{
+				submitted: map[string]string{"symbol": "ETHUSDT"},
+				running:   false,
+			},
+			want:    map[string]string{"symbol": "ETHUSDT"},
+			wantErr: nil,
+		},
+		{
+			name: "time normalized",
+			args: args{
+				submitted: map[string]string{"time_stop": "9:30PM"},
+				running:   false,
+			},
+			want:    map[string]string{"time_stop": "21:30"},
+			wantErr: nil,
+		},
+		{
+			name: "invalid time",
+			args: args{
+				submitted: map[string]string{"time_start": "25:00"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrInvalidTime,
+		},
+		{
+			name: "invalid cron",
+			args: args{
+				submitted: map[string]string{"schedule_pause": "0 0 * * weekend"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrInvalidCron,
+		},
+		{
+			name: "sizing mode without fraction",
+			args: args{
+				submitted: map[string]string{"buy_sizing_mode": "fraction"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrConflict,
+		},
+		{
+			name: "trailing stop without distance",
+			args: args{
+				submitted: map[string]string{"selltrailingactivation": "0.03", "selltrailingdistance": "0"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrConflict,
+		},
+		{
+			name: "enforce time with same start and stop",
+			args: args{
+				submitted: map[string]string{"time_enforce": "true", "time_stop": "4:00"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrConflict,
+		},
+		{
+			name: "rebalance without weights",
+			args: args{
+				submitted: map[string]string{"rebalance": "true"},
+				running:   false,
+			},
+			want:    nil,
+			wantErr: ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := Validate(current, tt.args.submitted, tt.args.running)
+			if tt.wantErr == nil && len(errs) > 0 {
+				t.Errorf("Validate() errors = %v, want none", errs)
+				return
+			}
+			if tt.wantErr != nil && (len(errs) != 1 || !errors.Is(errs[0], tt.wantErr)) {
+				t.Errorf("Validate() errors = %v, wantErr %v", errs, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_diff(t *testing.T) {
+	current := map[string]interface{}{"stoploss": "0", "buy_wait": "60"}
+	changes := map[string]string{"stoploss": "0.05", "buy_wait": "30", "debug": "true"}
+	want := []string{"buy_wait: 60 -> 30", "debug:  -> true", "stoploss: 0 -> 0.05"}
+	if got := diff(current, changes); !reflect.DeepEqual(got, want) {
+		t.Errorf("diff() = %v, want %v", got, want)
+	}
+}
+
+func TestParameters(t *testing.T) {
+	keys := make(map[string]bool)
+	for _, parameter := range Parameters {
+		if keys[parameter.Key] {
+			t.Errorf("Parameters duplicate key %v", parameter.Key)
+		}
+		keys[parameter.Key] = true
+		if parameter.Label == "" || parameter.Description == "" {
+			t.Errorf("Parameters %v missing label or description", parameter.Key)
+		}
+		if (parameter.Kind == KindFloat || parameter.Kind == KindInt) && parameter.Min > parameter.Max {
+			t.Errorf("Parameters %v minimum above maximum", parameter.Key)
+		}
+		if parameter.Kind == KindEnum && len(parameter.Options) == 0 {
+			t.Errorf("Parameters %v without options", parameter.Key)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package shadow
+
+/* This package implements the shadow orders of threads in dry-run mode. DryRun is a thread configuration, so a new
+symbol can be trialed alongside live threads: the decision trees run as usual, but the buys and sells they decide
+are simulated at the market price, logged and saved in the shadoworder table with the decision that led to them,
+and never sent to the exchange. Simulated buys stay open until a simulated sale reaches the profit target. */
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Simulated orders in the thread detail page and the REST API */
+const ordersLimit = 100
+
+// Status struct define the simulated orders of a thread in dry-run mode
+type Status struct {
+	Orders       []types.ShadowOrder `json:"orders"`       /* Latest simulated orders, most recent first */
+	Open         int                 `json:"open"`         /* Simulated buys not sold */
+	OpenQuantity float64             `json:"openQuantity"` /* Quantity of the simulated buys not sold */
+	OpenQuote    float64             `json:"openQuote"`    /* Cost of the simulated buys not sold */
+	Unrealized   float64             `json:"unrealized"`   /* Profit of the simulated buys not sold at the last price */
+	Realized     float64             `json:"realized"`     /* Profit of the simulated sales in Orders */
+}
+
+// Record simulate an order of side for quantity at the market price, selling source for a SELL, save it in the
+// shadoworder table with the decision tree result and log it. The order is never sent to the exchange.
+func Record(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session,
+	side string,
+	quantity float64,
+	source types.Order) (order types.ShadowOrder, err error) {
+
+	order = types.ShadowOrder{
+		Symbol:                  sessionData.Symbol,
+		Side:                    side,
+		Price:                   marketData.Price,
+		Quantity:                quantity,
+		CumulativeQuoteQuantity: quantity * marketData.Price,
+		Decision:                sessionData.BuyDecisionTreeResult,
+		TransactTime:            time.Now().UnixNano() / int64(time.Millisecond),
+	}
+
+	if side == "SELL" {
+
+		order.OrderIDSource = source.OrderID
+		order.Profit = profit(source.Price, marketData.Price, quantity)
+		order.Decision = sessionData.SellDecisionTreeResult
+
+	}
+
+	if err = mysql.SaveShadowOrder(sessionData, order); err != nil {
+
+		return order, err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:  configData,
+		Market:  marketData,
+		Session: sessionData,
+		Order: &types.Order{
+			OrderID: source.OrderID,
+			Price:   marketData.Price,
+		},
+		Message:  side + "DRYRUN " + strconv.FormatFloat(quantity, 'f', -1, 64) + " " + sessionData.Symbol + " - " + order.Decision,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return order, nil
+
+}
+
+// Load return the latest simulated orders of sessionData.ThreadID and the simulated buys not sold at price
+func Load(
+	sessionData *types.Session,
+	price float64) (status Status, err error) {
+
+	var open []types.ShadowOrder
+
+	if status.Orders, err = mysql.GetShadowOrders(sessionData, sessionData.ThreadID, ordersLimit); err != nil {
+
+		return status, err
+
+	}
+
+	if open, err = mysql.GetShadowOpenOrders(sessionData); err != nil {
+
+		return status, err
+
+	}
+
+	return summarize(status.Orders, open, price), nil
+
+}
+
+// Target return the lowest priced simulated buy of open (ordered by price) whose price increased by profit is reached
+// by price, the simulated buy to sell
+func Target(
+	open []types.ShadowOrder,
+	price float64,
+	profit float64) (order types.ShadowOrder, ok bool) {
+
+	for _, order := range open {
+
+		if price >= order.Price*(1+profit) {
+
+			return order, true
+
+		}
+
+	}
+
+	return order, false
+
+}
+
+// Order return the simulated buy as an open transaction to be sold by exchange.SellTicker
+func Order(order types.ShadowOrder) types.Order {
+
+	return types.Order{
+		CumulativeQuoteQuantity: order.CumulativeQuoteQuantity,
+		ExecutedQuantity:        order.Quantity,
+		OrderID:                 order.ID,
+		Price:                   order.Price,
+		Side:                    order.Side,
+		Status:                  "FILLED",
+		Symbol:                  order.Symbol,
+		TransactTime:            order.TransactTime,
+	}
+
+}
+
+/* Return the status of the simulated orders and of the simulated buys not sold at price */
+func summarize(
+	orders []types.ShadowOrder,
+	open []types.ShadowOrder,
+	price float64) (status Status) {
+
+	status.Orders = orders
+	status.Open = len(open)
+
+	for _, order := range orders {
+
+		if order.Side == "SELL" {
+
+			status.Realized += order.Profit
+
+		}
+
+	}
+
+	for _, order := range open {
+
+		status.OpenQuantity += order.Quantity
+		status.OpenQuote += order.CumulativeQuoteQuantity
+
+		if price > 0 { /* No price before the first market data */
+			status.Unrealized += profit(order.Price, price, order.Quantity)
+		}
+
+	}
+
+	status.Realized = math.Round(status.Realized*100) / 100
+	status.OpenQuote = math.Round(status.OpenQuote*100) / 100
+	status.Unrealized = math.Round(status.Unrealized*100) / 100
+
+	return status
+
+}
+
+/* Return the profit of quantity bought at buyPrice and sold at sellPrice */
+func profit(
+	buyPrice float64,
+	sellPrice float64,
+	quantity float64) float64 {
+
+	return (sellPrice - buyPrice) * quantity
+
+}
@@ -0,0 +1,72 @@
+package shadow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestTarget(t *testing.T) {
+
+	open := []types.ShadowOrder{
+		{ID: 14, Side: "BUY", Price: 55000, Quantity: 0.5},
+		{ID: 12, Side: "BUY", Price: 56000, Quantity: 0.5},
+	}
+
+	tests := []struct {
+		name   string
+		price  float64
+		want   int64
+		wantOk bool
+	}{
+		{name: "lowest", price: 56000, want: 14, wantOk: true},
+		{name: "below target", price: 55500, wantOk: false},
+		{name: "no open orders", price: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Target(open, tt.price, 0.01)
+			if ok != tt.wantOk {
+				t.Errorf("Target() ok = %v, want %v", ok, tt.wantOk)
+				return
+			}
+			if ok && got.ID != tt.want {
+				t.Errorf("Target() = %v, want %v", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func Test_summarize(t *testing.T) {
+
+	orders := []types.ShadowOrder{
+		{ID: 14, Side: "BUY", Price: 55000, Quantity: 0.5, CumulativeQuoteQuantity: 27500},
+		{ID: 13, Side: "SELL", Price: 57000, Quantity: 0.5, CumulativeQuoteQuantity: 28500, OrderIDSource: 12, Profit: 500},
+		{ID: 12, Side: "BUY", Price: 56000, Quantity: 0.5, CumulativeQuoteQuantity: 28000},
+	}
+
+	tests := []struct {
+		name  string
+		price float64
+		want  Status
+	}{
+		{
+			name:  "price",
+			price: 56000,
+			want:  Status{Orders: orders, Open: 1, OpenQuantity: 0.5, OpenQuote: 27500, Unrealized: 500, Realized: 500},
+		},
+		{
+			name:  "no price",
+			price: 0,
+			want:  Status{Orders: orders, Open: 1, OpenQuantity: 0.5, OpenQuote: 27500, Unrealized: 0, Realized: 500},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarize(orders, orders[:1], tt.price); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("summarize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package shutdown
+
+/* This package implements the graceful shutdown of the process on SIGINT and SIGTERM, so container restarts never
+leave half-written state. New buys stop, the buy or sell in progress and the exchange and database calls in flight are
+waited for up to drainTimeout, the websockets are stopped, and every thread of the process saves its session as STOPPED
+and removes its locks before exiting. A second signal exits immediately. */
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aleibovici/cryptopump/exchange"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	drainTimeout = 30 * time.Second /* Wait for the buy or sell and the calls in progress */
+	wsTimeout    = 2 * time.Second  /* Wait for the websockets to stop on their next message */
+)
+
+// Listen stop the process gracefully in the background on SIGINT or SIGTERM
+func Listen(
+	viperData *types.ViperData,
+	sessionData *types.Session) {
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+
+		received := <-signals
+
+		go func() { /* Exit immediately on a second signal */
+
+			<-signals
+			os.Exit(1)
+
+		}()
+
+		Stop(functions.GetConfigData(viperData, sessionData), sessionData, received.String())
+
+	}()
+
+}
+
+// Stop the threads of the process gracefully and exit, reason is the signal received
+func Stop(
+	configData *types.Config,
+	sessionData *types.Session,
+	reason string) {
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Graceful shutdown on " + reason,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	sessions := threads.Sessions()
+
+	if len(sessions) == 0 { /* No thread started */
+
+		os.Exit(0)
+
+	}
+
+	for _, session := range sessions {
+
+		session.Stopping = true /* No new buys */
+
+	}
+
+	deadline := time.Now().Add(drainTimeout)
+
+	/* Verify wether buying/selling to allow graceful session exit */
+	for busy(sessions) && time.Now().Before(deadline) {
+
+		time.Sleep(time.Millisecond * 200)
+
+	}
+
+	for _, session := range sessions {
+
+		session.StopWs = true /* Set all goroutine channels to stop */
+
+	}
+
+	time.Sleep(wsTimeout)
+
+	if !exchange.Drain(time.Until(deadline)) || !mysql.Drain(time.Until(deadline)) || busy(sessions) {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Graceful shutdown - calls still in progress after " + drainTimeout.String(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	threads.Thread{}.Shutdown(configData, sessions, "Graceful shutdown on "+reason)
+
+}
+
+/* Return true when a thread is buying or selling */
+func busy(sessions []*types.Session) bool {
+
+	for _, session := range sessions {
+
+		if session.Busy {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
@@ -0,0 +1,288 @@
+package slack
+
+/* This package implements the Slack notification channel. Filled BUY and SELL orders are posted to a Slack incoming
+webhook or, with a bot token and channel, through the chat.postMessage API, formatted with Block Kit: a header with
+the side and symbol and a section with the price, quantity and the running profit of the thread. Other notifications
+routed to Slack are posted as plain text. Messages are posted
+in the background so notifications never delay trading. */
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/outbox"
+	"github.com/aleibovici/cryptopump/throttle"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+var apiURL = "https://slack.com/api" /* Slack Web API */
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+var throttled = &throttle.Channel{} /* Notification rate limit */
+
+// ErrNotConfigured is returned when neither a webhook URL nor a bot token and channel are configured
+var ErrNotConfigured = errors.New("Slack webhook URL or bot token and channel required")
+
+// Trade defines a filled order notification
+type Trade struct {
+	Side            string /* BUY or SELL */
+	Symbol          string
+	Price           float64
+	Quantity        float64
+	ThreadID        string
+	ThreadProfit    float64 /* Running realized profit of the thread */
+	ThreadProfitPct float64 /* Average transaction profit of the thread as percentage */
+	Fiat            string
+	Text            string /* Plain text fallback rendered from the message templates, default when empty */
+}
+
+// Send the trade notification via Slack when Slack is configured, batched into a plain text digest when the
+// notification rate limit is exceeded
+func (trade Trade) Send(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !Enabled(configData) {
+
+		return
+
+	}
+
+	if !throttled.Allow(throttle.Rate(configData), false, trade.text(), func(text string) {
+		send(configData.ConfigGlobal, sessionData, text, nil)
+	}) {
+
+		return
+
+	}
+
+	send(configData.ConfigGlobal, sessionData, trade.text(), trade.blocks())
+
+}
+
+// Message defines a plain text notification
+type Message struct {
+	Text     string
+	Critical bool /* Never throttled */
+}
+
+// Send the message via Slack when Slack is configured, batched into a digest when the notification rate limit is
+// exceeded unless critical
+func (message Message) Send(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !Enabled(configData) {
+
+		return
+
+	}
+
+	if !throttled.Allow(throttle.Rate(configData), message.Critical, message.Text, func(text string) {
+		send(configData.ConfigGlobal, sessionData, text, nil)
+	}) {
+
+		return
+
+	}
+
+	send(configData.ConfigGlobal, sessionData, message.Text, nil)
+
+}
+
+/* Post the message in the background, errors are logged and the message queued for retry */
+func send(
+	global *types.ConfigGlobal,
+	sessionData *types.Session,
+	text string,
+	blocks []interface{}) {
+
+	go func() {
+
+		if err := post(global, text, blocks); err != nil {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   nil,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  functions.GetFunctionName() + " - " + err.Error(),
+				LogLevel: "DebugLevel",
+			}.Do()
+
+			outbox.Enqueue(sessionData, messages.Slack, text, err) /* Retried by the Master Node */
+
+		}
+
+	}()
+
+}
+
+// Deliver post the text of a queued notification, registered to outbox to retry failed messages
+func Deliver(
+	configData *types.Config,
+	sessionData *types.Session,
+	text string) error {
+
+	return post(configData.ConfigGlobal, text, nil) /* Block Kit formatting is not queued */
+
+}
+
+// Enabled return true when a Slack webhook URL, or a bot token and channel, are configured
+func Enabled(configData *types.Config) bool {
+
+	global := configData.ConfigGlobal
+
+	return global != nil && (global.SlackWebhookURL != "" || (global.SlackBotToken != "" && global.SlackChannel != ""))
+
+}
+
+/* Plain text fallback used by notifications */
+func (trade Trade) text() string {
+
+	if trade.Text != "" {
+
+		return trade.Text
+
+	}
+
+	return fmt.Sprintf("%s %s %s @ %s - %s", trade.Side, trade.Symbol, functions.Float64ToStr(trade.Quantity, 6), functions.Float64ToStr(trade.Price, 4), trade.ThreadID)
+
+}
+
+/* Block Kit blocks of the trade */
+func (trade Trade) blocks() []interface{} {
+
+	emoji := ":large_green_circle:"
+	if trade.Side == "SELL" {
+		emoji = ":red_circle:"
+	}
+
+	field := func(name string, value string) map[string]interface{} {
+		return map[string]interface{}{"type": "mrkdwn", "text": "*" + name + "*\n" + value}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": emoji + " " + trade.Side + " " + trade.Symbol, "emoji": true},
+		},
+		map[string]interface{}{
+			"type": "section",
+			"fields": []interface{}{
+				field("Price", functions.Float64ToStr(trade.Price, 4)),
+				field("Quantity", functions.Float64ToStr(trade.Quantity, 6)),
+				field("Thread Profit", functions.Float64ToStr(trade.ThreadProfit, 2)+" "+trade.Fiat),
+				field("Avg. Transaction", functions.Float64ToStr(trade.ThreadProfitPct, 2)+"%"),
+			},
+		},
+		map[string]interface{}{
+			"type":     "context",
+			"elements": []interface{}{map[string]interface{}{"type": "mrkdwn", "text": "Thread " + trade.ThreadID}},
+		},
+	}
+
+}
+
+/* Post the message to the Slack webhook, or to the channel through chat.postMessage when no webhook is configured */
+func post(
+	global *types.ConfigGlobal,
+	text string,
+	blocks []interface{}) (err error) {
+
+	var body []byte
+	var request *http.Request
+	var response *http.Response
+
+	message := map[string]interface{}{"text": text}
+	if blocks != nil {
+		message["blocks"] = blocks
+	}
+
+	switch {
+	case global.SlackWebhookURL != "":
+
+		if body, err = json.Marshal(message); err != nil {
+
+			return err
+
+		}
+
+		if request, err = http.NewRequest("POST", global.SlackWebhookURL, bytes.NewReader(body)); err != nil {
+
+			return err
+
+		}
+
+	case global.SlackBotToken != "" && global.SlackChannel != "":
+
+		message["channel"] = global.SlackChannel
+
+		if body, err = json.Marshal(message); err != nil {
+
+			return err
+
+		}
+
+		if request, err = http.NewRequest("POST", apiURL+"/chat.postMessage", bytes.NewReader(body)); err != nil {
+
+			return err
+
+		}
+
+		request.Header.Set("Authorization", "Bearer "+global.SlackBotToken)
+
+	default:
+
+		return ErrNotConfigured
+
+	}
+
+	request.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	if response, err = client.Do(request); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+
+		return fmt.Errorf("Slack returned %s", response.Status)
+
+	}
+
+	if global.SlackWebhookURL == "" { /* The Web API returns 200 with ok false on errors */
+
+		var result struct {
+			Ok    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+
+		if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+
+			return err
+
+		}
+
+		if !result.Ok {
+
+			return fmt.Errorf("Slack returned %s", result.Error)
+
+		}
+
+	}
+
+	return nil
+
+}
@@ -0,0 +1,88 @@
+package slack
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		global *types.ConfigGlobal
+		want   bool
+	}{
+		{name: "webhook", global: &types.ConfigGlobal{SlackWebhookURL: "https://hooks.slack.com/services/T/B/x"}, want: true},
+		{name: "bot", global: &types.ConfigGlobal{SlackBotToken: "xoxb-token", SlackChannel: "#trading"}, want: true},
+		{name: "bot without channel", global: &types.ConfigGlobal{SlackBotToken: "xoxb-token"}, want: false},
+		{name: "not configured", global: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(&types.Config{ConfigGlobal: tt.global}); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrade_blocks(t *testing.T) {
+	trade := Trade{Side: "SELL", Symbol: "BTCUSDT", Price: 57600.5, Quantity: 0.0021, ThreadID: "c683ok5mk1u1120gnmmg", ThreadProfit: 12.5, ThreadProfitPct: 1.2, Fiat: "USDT"}
+
+	data, err := json.Marshal(trade.blocks())
+	if err != nil {
+		t.Fatalf("blocks() error = %v", err)
+	}
+	want := `[{"text":{"emoji":true,"text":":red_circle: SELL BTCUSDT","type":"plain_text"},"type":"header"},` +
+		`{"fields":[{"text":"*Price*\n57600.5000","type":"mrkdwn"},{"text":"*Quantity*\n0.002100","type":"mrkdwn"},` +
+		`{"text":"*Thread Profit*\n12.50 USDT","type":"mrkdwn"},{"text":"*Avg. Transaction*\n1.20%","type":"mrkdwn"}],"type":"section"},` +
+		`{"elements":[{"text":"Thread c683ok5mk1u1120gnmmg","type":"mrkdwn"}],"type":"context"}]`
+	if string(data) != want {
+		t.Errorf("blocks() = %s, want %s", data, want)
+	}
+	if got := trade.text(); got != "SELL BTCUSDT 0.002100 @ 57600.5000 - c683ok5mk1u1120gnmmg" {
+		t.Errorf("text() = %v", got)
+	}
+}
+
+func Test_post(t *testing.T) {
+	var message map[string]interface{}
+	var authorization, path string
+	reply := `{"ok":true}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		message = nil
+		_ = json.Unmarshal(data, &message)
+		authorization = r.Header.Get("Authorization")
+		path = r.URL.Path
+		_, _ = w.Write([]byte(reply))
+	}))
+	defer server.Close()
+
+	blocks := Trade{Side: "BUY", Symbol: "BTCUSDT"}.blocks()
+
+	if err := post(&types.ConfigGlobal{SlackWebhookURL: server.URL + "/services/T/B/x"}, "BUY", blocks); err != nil {
+		t.Fatalf("post() error = %v", err)
+	}
+	if message["text"] != "BUY" || message["channel"] != nil || authorization != "" || path != "/services/T/B/x" {
+		t.Errorf("post() webhook message = %v, authorization = %v, path = %v", message, authorization, path)
+	}
+
+	apiURL = server.URL
+	if err := post(&types.ConfigGlobal{SlackBotToken: "xoxb-token", SlackChannel: "#trading"}, "BUY", blocks); err != nil {
+		t.Fatalf("post() error = %v", err)
+	}
+	if message["channel"] != "#trading" || authorization != "Bearer xoxb-token" || path != "/chat.postMessage" {
+		t.Errorf("post() bot message = %v, authorization = %v, path = %v", message, authorization, path)
+	}
+
+	reply = `{"ok":false,"error":"channel_not_found"}`
+	if err := post(&types.ConfigGlobal{SlackBotToken: "xoxb-token", SlackChannel: "#trading"}, "BUY", blocks); err == nil || err.Error() != "Slack returned channel_not_found" {
+		t.Errorf("post() error = %v, want channel_not_found", err)
+	}
+}
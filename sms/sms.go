@@ -0,0 +1,214 @@
+package sms
+
+/* This package implements the Twilio SMS channel, reserved for critical alerts such as exchange authentication
+failures, the database down for longer than DbDownMinutes and risk kill switches. SMS are strictly rate
+limited: the same alert key is sent at most once per key interval, and at most SMSRateMax SMS are sent per hour
+across all alerts, further alerts are logged and dropped. Messages are posted in the background so alerts never
+delay trading. */
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	keyInterval = 30 * time.Minute /* Minimum time between two SMS of the same alert */
+	rateWindow  = time.Hour        /* SMSRateMax window */
+	maxLength   = 320              /* Characters per SMS, longer texts are truncated */
+)
+
+var twilioURL = "https://api.twilio.com/2010-04-01/Accounts/" /* Twilio Messages API, followed by <AccountSID>/Messages.json */
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+var (
+	mutex sync.Mutex
+	sent  = make(map[string]time.Time) /* Last time each alert key was sent */
+	times []time.Time                  /* Times of the SMS sent within rateWindow */
+)
+
+// Send an SMS with text to the SMSTo numbers. The SMS is dropped when key was sent within the last 30 minutes or
+// SMSRateMax SMS were sent within the last hour.
+func Send(
+	configData *types.Config,
+	sessionData *types.Session,
+	key string,
+	text string) {
+
+	if !Enabled(configData) {
+
+		return
+
+	}
+
+	if !allow(key, configData.ConfigGlobal.SMSRateMax, time.Now()) {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "SMS rate limit reached, alert dropped: " + text,
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return
+
+	}
+
+	go func() {
+
+		global := configData.ConfigGlobal
+
+		for _, to := range recipients(global.SMSTo) {
+
+			if err := twilio(global, to, truncate(text)); err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   nil,
+					Market:   nil,
+					Session:  sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+			}
+
+		}
+
+	}()
+
+}
+
+// Enabled return true when Twilio and at least one recipient number are configured
+func Enabled(configData *types.Config) bool {
+
+	global := configData.ConfigGlobal
+
+	return global != nil &&
+		global.TwilioAccountSID != "" &&
+		global.TwilioAuthToken != "" &&
+		global.TwilioFrom != "" &&
+		len(recipients(global.SMSTo)) > 0
+
+}
+
+/* Return true and record the time when key was not sent within keyInterval and less than rate SMS within rateWindow */
+func allow(
+	key string,
+	rate int,
+	now time.Time) bool {
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if t, ok := sent[key]; ok && now.Sub(t) < keyInterval {
+
+		return false
+
+	}
+
+	var recent []time.Time
+
+	for _, t := range times {
+		if now.Sub(t) < rateWindow {
+			recent = append(recent, t)
+		}
+	}
+
+	times = recent
+
+	if len(times) >= rate {
+
+		return false
+
+	}
+
+	times = append(times, now)
+	sent[key] = now
+
+	return true
+
+}
+
+/* Post an SMS to one number through the Twilio Messages API */
+func twilio(
+	global *types.ConfigGlobal,
+	to string,
+	text string) (err error) {
+
+	var request *http.Request
+	var response *http.Response
+
+	if request, err = http.NewRequest(
+		http.MethodPost,
+		twilioURL+url.PathEscape(global.TwilioAccountSID)+"/Messages.json",
+		strings.NewReader(url.Values{
+			"From": {global.TwilioFrom},
+			"To":   {to},
+			"Body": {text},
+		}.Encode())); err != nil {
+
+		return err
+
+	}
+
+	request.SetBasicAuth(global.TwilioAccountSID, global.TwilioAuthToken)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if response, err = client.Do(request); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+
+		return errors.New("Twilio returned " + response.Status)
+
+	}
+
+	return nil
+
+}
+
+/* Return the comma separated phone numbers */
+func recipients(numbers string) (to []string) {
+
+	for _, number := range strings.Split(numbers, ",") {
+
+		if number = strings.TrimSpace(number); number != "" {
+
+			to = append(to, number)
+
+		}
+
+	}
+
+	return to
+
+}
+
+/* Return text truncated to maxLength characters */
+func truncate(text string) string {
+
+	if runes := []rune(strings.TrimSpace(text)); len(runes) > maxLength {
+
+		return string(runes[:maxLength-3]) + "..."
+
+	}
+
+	return strings.TrimSpace(text)
+
+}
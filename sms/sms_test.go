@@ -0,0 +1,98 @@
+package sms
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		global *types.ConfigGlobal
+		want   bool
+	}{
+		{name: "configured", global: &types.ConfigGlobal{TwilioAccountSID: "AC0123456789abcdef0123456789abcdef", TwilioAuthToken: "token", TwilioFrom: "+15005550006", SMSTo: "+15551234567"}, want: true},
+		{name: "no recipients", global: &types.ConfigGlobal{TwilioAccountSID: "AC0123456789abcdef0123456789abcdef", TwilioAuthToken: "token", TwilioFrom: "+15005550006", SMSTo: " , "}, want: false},
+		{name: "no sender", global: &types.ConfigGlobal{TwilioAccountSID: "AC0123456789abcdef0123456789abcdef", TwilioAuthToken: "token", SMSTo: "+15551234567"}, want: false},
+		{name: "not configured", global: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(&types.Config{ConfigGlobal: tt.global}); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_allow(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+
+	if !allow("test-auth", 2, now) {
+		t.Errorf("allow() first alert = false, want true")
+	}
+	if allow("test-auth", 2, now.Add(29*time.Minute)) {
+		t.Errorf("allow() same key within 30 minutes = true, want false")
+	}
+	if !allow("test-database", 2, now.Add(time.Minute)) {
+		t.Errorf("allow() second alert = false, want true")
+	}
+	if allow("test-drawdown", 2, now.Add(2*time.Minute)) {
+		t.Errorf("allow() above the hourly rate = true, want false")
+	}
+	if !allow("test-drawdown", 2, now.Add(time.Hour)) {
+		t.Errorf("allow() after the rate window = false, want true")
+	}
+	if allow("test-disabled", 0, now.Add(3*time.Hour)) {
+		t.Errorf("allow() rate 0 = true, want false")
+	}
+}
+
+func Test_twilio(t *testing.T) {
+	var path string
+	var form map[string][]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = r.ParseForm()
+		form = r.PostForm
+		if user, password, ok := r.BasicAuth(); !ok || user != "AC0123456789abcdef0123456789abcdef" || password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	saved := twilioURL
+	twilioURL = server.URL + "/2010-04-01/Accounts/"
+	defer func() { twilioURL = saved }()
+
+	global := &types.ConfigGlobal{TwilioAccountSID: "AC0123456789abcdef0123456789abcdef", TwilioAuthToken: "token", TwilioFrom: "+15005550006"}
+	if err := twilio(global, "+15551234567", "Buys halted"); err != nil {
+		t.Fatalf("twilio() error = %v", err)
+	}
+	if path != "/2010-04-01/Accounts/AC0123456789abcdef0123456789abcdef/Messages.json" ||
+		form["From"][0] != "+15005550006" || form["To"][0] != "+15551234567" || form["Body"][0] != "Buys halted" {
+		t.Errorf("twilio() path = %v, form = %v", path, form)
+	}
+
+	global.TwilioAuthToken = "invalid"
+	if err := twilio(global, "+15551234567", "Buys halted"); err == nil {
+		t.Errorf("twilio() error = nil, want unauthorized")
+	}
+}
+
+func Test_truncate(t *testing.T) {
+	if got := truncate(" Database down "); got != "Database down" {
+		t.Errorf("truncate() = %v, want Database down", got)
+	}
+	if got := truncate(strings.Repeat("x", 400)); len(got) != maxLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("truncate() length = %v, want %v", len(got), maxLength)
+	}
+}
@@ -0,0 +1,373 @@
+package snapshot
+
+/* This package implements the state snapshot of the thread running in this process, for attaching to bug reports.
+The snapshot has the thread configuration and global configuration, the market indicators and their buffers, the open
+transactions, the balances and the last errors logged by the thread, as JSON. Secrets (API keys, tokens, passwords,
+webhook URLs and DSNs) and contact details are redacted, keeping only whether they are set. */
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// Redacted replace the value of the secret configuration keys that are set
+const Redacted = "[REDACTED]"
+
+const (
+	errorsMax = 20  /* Last errors kept for the snapshot */
+	closesMax = 100 /* Last candle close prices in the snapshot */
+)
+
+/* Configuration keys redacted, matched case insensitive */
+var secretKey = regexp.MustCompile(`(?i)apikey|secret|token|password|dsn|webhookurl|code|sid|username|pushoveruser|emailfrom|emailto|smsto|twiliofrom|chatid`)
+
+/* Configuration keys omitted from the snapshot, html output of the webui */
+var omittedKeys = map[string]bool{"ConfigTemplateList": true, "HTMLSnippet": true, "EquitySnippet": true}
+
+// Snapshot struct define the state of the thread
+type Snapshot struct {
+	Time         time.Time              `json:"time"`
+	Config       map[string]interface{} `json:"config"`       /* Thread and global configuration, redacted */
+	Session      Session                `json:"session"`      /* Session state */
+	Market       Market                 `json:"market"`       /* Market indicators and buffers */
+	Orders       []types.Order          `json:"orders"`       /* Open transactions of the thread */
+	OrdersError  string                 `json:"ordersError"`  /* Error retrieving the open transactions, if any */
+	Balances     Balances               `json:"balances"`     /* Funds of the thread */
+	Errors       []Error                `json:"errors"`       /* Last errors logged by the thread, oldest first */
+	ErrorsLogged int                    `json:"errorsLogged"` /* Errors logged by the thread since start */
+}
+
+// Session struct define the session state of the snapshot
+type Session struct {
+	ThreadID               string    `json:"threadId"`
+	ThreadIDSession        string    `json:"threadIdSession"`
+	CorrelationID          string    `json:"correlationId"`
+	CycleState             string    `json:"cycleState"`
+	CycleOrderID           int64     `json:"cycleOrderId"`
+	Symbol                 string    `json:"symbol"`
+	SymbolFiat             string    `json:"symbolFiat"`
+	Port                   string    `json:"port"`
+	MasterNode             bool      `json:"masterNode"`
+	Busy                   bool      `json:"busy"`
+	StopWs                 bool      `json:"stopWs"`
+	Paused                 bool      `json:"paused"`
+	Status                 bool      `json:"status"` /* System status Good (false) or Bad (true) */
+	ForceBuy               bool      `json:"forceBuy"`
+	ForceSell              bool      `json:"forceSell"`
+	ThreadCount            int       `json:"threadCount"`
+	SellTransactionCount   float64   `json:"sellTransactionCount"`
+	MinQuantity            float64   `json:"minQuantity"`
+	MaxQuantity            float64   `json:"maxQuantity"`
+	StepSize               float64   `json:"stepSize"`
+	MinNotional            float64   `json:"minNotional"`
+	Commission             float64   `json:"commission"`
+	Latency                int64     `json:"latency"`
+	StopPrice              float64   `json:"stopPrice"`
+	TrailingHigh           float64   `json:"trailingHigh"`
+	VolatilityHalt         bool      `json:"volatilityHalt"`
+	SymbolDenied           bool      `json:"symbolDenied"`
+	BuyScore               float64   `json:"buyScore"`
+	BuyDecisionTreeResult  string    `json:"buyDecisionTreeResult"`
+	SellDecisionTreeResult string    `json:"sellDecisionTreeResult"`
+	LastBuyTransactTime    time.Time `json:"lastBuyTransactTime"`
+	LastSellCanceledTime   time.Time `json:"lastSellCanceledTime"`
+	LastWsKlineTime        time.Time `json:"lastWsKlineTime"`
+	LastWsBookTickerTime   time.Time `json:"lastWsBookTickerTime"`
+	LastWsUserDataTime     time.Time `json:"lastWsUserDataServeTime"`
+	LastExchangeTime       time.Time `json:"lastExchangeTime"`
+	RateLimitedUntil       time.Time `json:"rateLimitedUntil"`
+	CooldownUntil          time.Time `json:"cooldownUntil"`
+	DbDownTime             time.Time `json:"dbDownTime"`
+}
+
+// Market struct define the market indicators and buffers of the snapshot
+type Market struct {
+	Price              float64   `json:"price"`
+	TimeStamp          time.Time `json:"timeStamp"`
+	Stale              bool      `json:"stale"`
+	Direction          int       `json:"direction"`
+	Rsi3               float64   `json:"rsi3"`
+	Rsi7               float64   `json:"rsi7"`
+	Rsi14              float64   `json:"rsi14"`
+	MACD               float64   `json:"macd"`
+	Ma7                float64   `json:"ma7"`
+	Ma14               float64   `json:"ma14"`
+	HighPrice          float64   `json:"highPrice"`
+	LowPrice           float64   `json:"lowPrice"`
+	Spread             float64   `json:"spread"`
+	SpreadHistory      []float64 `json:"spreadHistory"`
+	OrderBookBidDepth  float64   `json:"orderBookBidDepth"`
+	OrderBookAskDepth  float64   `json:"orderBookAskDepth"`
+	OrderBookImbalance float64   `json:"orderBookImbalance"`
+	Candles            int       `json:"candles"` /* Candles in the technical analysis series */
+	Closes             []float64 `json:"closes"`  /* Close prices of the last 100 candles, oldest first */
+	Klines             int       `json:"klines"`  /* Klines of the chart buffer */
+}
+
+// Balances struct define the funds of the snapshot
+type Balances struct {
+	SymbolFunds          float64 `json:"symbolFunds"`
+	SymbolFiatFunds      float64 `json:"symbolFiatFunds"`
+	Reservation          float64 `json:"reservation"`
+	ReservationAvailable float64 `json:"reservationAvailable"`
+	FiatReserve          float64 `json:"fiatReserve"`
+	ThreadExposure       float64 `json:"threadExposure"`
+}
+
+// Error struct define an error logged by the thread
+type Error struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+/* Last errors logged by the thread */
+var recent = struct {
+	sync.Mutex
+	errors []Error
+	count  int
+}{}
+
+// Observe keep the last errors logged by this process for the snapshot. Called at startup.
+func Observe() {
+
+	logger.Observe(func(entry logger.LogEntry) {
+
+		if !strings.EqualFold(entry.LogLevel, "DebugLevel") {
+
+			return
+
+		}
+
+		Record(entry.Message, time.Now())
+
+	})
+
+}
+
+// Record keep the error message logged at time, dropping the oldest beyond the last 20
+func Record(
+	message string,
+	now time.Time) {
+
+	recent.Lock()
+	defer recent.Unlock()
+
+	recent.errors = append(recent.errors, Error{Time: now, Message: message})
+	recent.count++
+
+	if len(recent.errors) > errorsMax {
+		recent.errors = recent.errors[len(recent.errors)-errorsMax:]
+	}
+
+}
+
+// Take the snapshot of the thread
+func Take(
+	configData *types.Config,
+	marketData *types.Market,
+	sessionData *types.Session) (snapshot Snapshot) {
+
+	snapshot = Snapshot{
+		Time:     time.Now(),
+		Config:   Redact(configData),
+		Session:  session(sessionData),
+		Market:   market(marketData, sessionData),
+		Orders:   []types.Order{},
+		Balances: balances(sessionData),
+	}
+
+	if sessionData.Db != nil {
+
+		orders, err := mysql.GetThreadTransactionByThreadID(sessionData)
+		if err != nil {
+
+			snapshot.OrdersError = err.Error()
+
+		} else if orders != nil {
+
+			snapshot.Orders = orders
+
+		}
+
+	}
+
+	recent.Lock()
+	snapshot.Errors = append([]Error{}, recent.errors...)
+	snapshot.ErrorsLogged = recent.count
+	recent.Unlock()
+
+	return snapshot
+
+}
+
+// Redact return configData as a map with the secret keys redacted
+func Redact(configData *types.Config) map[string]interface{} {
+
+	var config map[string]interface{}
+
+	if configData == nil {
+
+		return map[string]interface{}{}
+
+	}
+
+	redacted := *configData /* The html output of the webui is not marshaled */
+	redacted.ConfigTemplateList, redacted.HTMLSnippet, redacted.EquitySnippet = nil, nil, nil
+
+	data, err := json.Marshal(redacted)
+	if err != nil {
+
+		return map[string]interface{}{}
+
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+
+		return map[string]interface{}{}
+
+	}
+
+	redact(config)
+
+	return config
+
+}
+
+/* Redact the secret keys of config and of its nested objects in place */
+func redact(config map[string]interface{}) {
+
+	for key, value := range config {
+
+		if omittedKeys[key] {
+
+			delete(config, key)
+			continue
+
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			redact(value)
+		case string:
+			if value != "" && secretKey.MatchString(key) {
+				config[key] = Redacted
+			}
+		}
+
+	}
+
+}
+
+/* Return the session state of sessionData */
+func session(sessionData *types.Session) Session {
+
+	return Session{
+		ThreadID:               sessionData.ThreadID,
+		ThreadIDSession:        sessionData.ThreadIDSession,
+		CorrelationID:          sessionData.CorrelationID,
+		CycleState:             sessionData.CycleState,
+		CycleOrderID:           sessionData.CycleOrderID,
+		Symbol:                 sessionData.Symbol,
+		SymbolFiat:             sessionData.SymbolFiat,
+		Port:                   sessionData.Port,
+		MasterNode:             sessionData.MasterNode,
+		Busy:                   sessionData.Busy,
+		StopWs:                 sessionData.StopWs,
+		Paused:                 sessionData.Paused,
+		Status:                 sessionData.Status,
+		ForceBuy:               sessionData.ForceBuy,
+		ForceSell:              sessionData.ForceSell,
+		ThreadCount:            sessionData.ThreadCount,
+		SellTransactionCount:   sessionData.SellTransactionCount,
+		MinQuantity:            sessionData.MinQuantity,
+		MaxQuantity:            sessionData.MaxQuantity,
+		StepSize:               sessionData.StepSize,
+		MinNotional:            sessionData.MinNotional,
+		Commission:             sessionData.Commission,
+		Latency:                sessionData.Latency,
+		StopPrice:              sessionData.StopPrice,
+		TrailingHigh:           sessionData.TrailingHigh,
+		VolatilityHalt:         sessionData.VolatilityHalt,
+		SymbolDenied:           sessionData.SymbolDenied,
+		BuyScore:               sessionData.BuyScore,
+		BuyDecisionTreeResult:  sessionData.BuyDecisionTreeResult,
+		SellDecisionTreeResult: sessionData.SellDecisionTreeResult,
+		LastBuyTransactTime:    sessionData.LastBuyTransactTime,
+		LastSellCanceledTime:   sessionData.LastSellCanceledTime,
+		LastWsKlineTime:        sessionData.LastWsKlineTime,
+		LastWsBookTickerTime:   sessionData.LastWsBookTickerTime,
+		LastWsUserDataTime:     sessionData.LastWsUserDataServeTime,
+		LastExchangeTime:       sessionData.LastExchangeTime,
+		RateLimitedUntil:       sessionData.RateLimitedUntil,
+		CooldownUntil:          sessionData.CooldownUntil,
+		DbDownTime:             sessionData.DbDownTime,
+	}
+
+}
+
+/* Return the market indicators and buffers of marketData */
+func market(
+	marketData *types.Market,
+	sessionData *types.Session) (market Market) {
+
+	market = Market{
+		Price:              marketData.Price,
+		TimeStamp:          marketData.TimeStamp,
+		Stale:              marketData.Stale,
+		Direction:          marketData.Direction,
+		Rsi3:               marketData.Rsi3,
+		Rsi7:               marketData.Rsi7,
+		Rsi14:              marketData.Rsi14,
+		MACD:               marketData.MACD,
+		Ma7:                marketData.Ma7,
+		Ma14:               marketData.Ma14,
+		HighPrice:          marketData.PriceChangeStatsHighPrice,
+		LowPrice:           marketData.PriceChangeStatsLowPrice,
+		Spread:             marketData.Spread,
+		SpreadHistory:      append([]float64{}, marketData.SpreadHistory...),
+		OrderBookBidDepth:  marketData.OrderBookBidDepth,
+		OrderBookAskDepth:  marketData.OrderBookAskDepth,
+		OrderBookImbalance: marketData.OrderBookImbalance,
+		Closes:             []float64{},
+		Klines:             len(sessionData.KlineData),
+	}
+
+	if marketData.Series != nil {
+
+		candles := marketData.Series.Candles
+		market.Candles = len(candles)
+
+		if len(candles) > closesMax {
+			candles = candles[len(candles)-closesMax:]
+		}
+
+		for _, candle := range candles {
+			market.Closes = append(market.Closes, candle.ClosePrice.Float())
+		}
+
+	}
+
+	return market
+
+}
+
+/* Return the funds of sessionData */
+func balances(sessionData *types.Session) Balances {
+
+	return Balances{
+		SymbolFunds:          sessionData.SymbolFunds,
+		SymbolFiatFunds:      sessionData.SymbolFiatFunds,
+		Reservation:          sessionData.Reservation,
+		ReservationAvailable: sessionData.ReservationAvailable,
+		FiatReserve:          sessionData.FiatReserve,
+		ThreadExposure:       sessionData.ThreadExposure,
+	}
+
+}
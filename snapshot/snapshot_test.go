@@ -0,0 +1,79 @@
+package snapshot
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestRedact(t *testing.T) {
+
+	config := Redact(&types.Config{
+		ThreadID:        "c683ok5mk1u1120gnmmg",
+		APIToken:        "5f0c2a",
+		Stoploss:        0.05,
+		HTMLSnippet:     "<div></div>",
+		LiquidationCode: "",
+		ConfigGlobal: &types.ConfigGlobal{
+			Apikey:          "apikey",
+			Secretkey:       "secretkey",
+			SlackWebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX",
+			SMTPPassword:    "password",
+			SentryDsn:       "https://key@sentry.io/1",
+			NtfyURL:         "https://ntfy.sh",
+		},
+	})
+
+	global, _ := config["ConfigGlobal"].(map[string]interface{})
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "not secret", got: config["ThreadID"], want: "c683ok5mk1u1120gnmmg"},
+		{name: "number", got: config["Stoploss"], want: 0.05},
+		{name: "token", got: config["APIToken"], want: Redacted},
+		{name: "empty secret", got: config["LiquidationCode"], want: ""},
+		{name: "api key", got: global["Apikey"], want: Redacted},
+		{name: "secret key", got: global["Secretkey"], want: Redacted},
+		{name: "webhook url", got: global["SlackWebhookURL"], want: Redacted},
+		{name: "password", got: global["SMTPPassword"], want: Redacted},
+		{name: "dsn", got: global["SentryDsn"], want: Redacted},
+		{name: "url", got: global["NtfyURL"], want: "https://ntfy.sh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Redact() = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := config["HTMLSnippet"]; ok {
+		t.Errorf("Redact() HTMLSnippet not omitted")
+	}
+
+}
+
+func TestRecord(t *testing.T) {
+
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < errorsMax+5; i++ {
+		Record(fmt.Sprintf("error %d", i), now.Add(time.Duration(i)*time.Second))
+	}
+
+	snapshot := Take(&types.Config{}, &types.Market{}, &types.Session{})
+
+	if len(snapshot.Errors) != errorsMax || snapshot.ErrorsLogged != errorsMax+5 {
+		t.Errorf("Take() errors = %v, logged %v, want %v, %v", len(snapshot.Errors), snapshot.ErrorsLogged, errorsMax, errorsMax+5)
+	}
+
+	if snapshot.Errors[0].Message != "error 5" || snapshot.Errors[errorsMax-1].Message != fmt.Sprintf("error %d", errorsMax+4) {
+		t.Errorf("Take() errors = %v, want the last %v oldest first", snapshot.Errors, errorsMax)
+	}
+
+}
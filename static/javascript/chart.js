@@ -0,0 +1,47 @@
+/* Thread price chart. Load GET /chart/annotations and render the candles of the thread with the executed orders as
+markers and the pending levels (entries, targets, stoploss, next DCA and stop price) as price lines in a TradingView
+lightweight-charts widget. The chart is rendered in the element with id tradingChart. */
+(function () {
+
+    function render(element, feed) {
+
+        var dark = document.body.classList.contains('theme-dark');
+
+        var chart = LightweightCharts.createChart(element, {
+            height: 360,
+            layout: {
+                backgroundColor: dark ? '#222' : '#fff',
+                textColor: dark ? '#ddd' : '#333'
+            },
+            timeScale: {timeVisible: true, secondsVisible: false}
+        });
+
+        var series = chart.addCandlestickSeries();
+
+        series.setData(feed.candles);
+        series.setMarkers(feed.markers);
+
+        feed.priceLines.forEach(function (line) {
+            series.createPriceLine(line);
+        });
+
+        chart.timeScale().fitContent();
+
+    }
+
+    document.addEventListener('DOMContentLoaded', function () {
+
+        var element = document.getElementById('tradingChart');
+
+        if (!element || typeof LightweightCharts === 'undefined') {
+            return;
+        }
+
+        fetch(window.location.origin + '/chart/annotations', {cache: 'no-cache'})
+            .then(function (response) { return response.json(); })
+            .then(function (feed) { render(element, feed); })
+            .catch(function (error) { console.log(error); });
+
+    });
+
+})();
@@ -0,0 +1,25 @@
+/* Add the CSRF token of the UI session, set by the server in the cryptopump_csrf cookie, as a hidden csrfToken
+field to every POST form. The server rejects form posts without a matching token. */
+document.addEventListener('DOMContentLoaded', function () {
+
+    var match = document.cookie.match(/(?:^|;\s*)cryptopump_csrf=([^;]*)/);
+
+    if (!match) {
+        return;
+    }
+
+    document.querySelectorAll('form').forEach(function (form) {
+
+        if (form.method.toLowerCase() !== 'post' || form.querySelector('input[name="csrfToken"]')) {
+            return;
+        }
+
+        var input = document.createElement('input');
+        input.type = 'hidden';
+        input.name = 'csrfToken';
+        input.value = decodeURIComponent(match[1]);
+        form.appendChild(input);
+
+    });
+
+});
@@ -0,0 +1,76 @@
+/* Live P&L ticker of the dashboard header. Poll GET /pnl every minute, matching the snapshots saved by the
+Master Node, and render the realized, unrealized and total profit of all threads followed by a sparkline of
+the total profit of each thread. The ticker is rendered in the element with id pnlTicker. */
+(function () {
+
+    var width = 80;
+    var height = 16;
+
+    /* Return an SVG polyline of series scaled to the sparkline size */
+    function sparkline(series) {
+
+        if (!series || series.length < 2) {
+            return '';
+        }
+
+        var min = Math.min.apply(null, series);
+        var max = Math.max.apply(null, series);
+        var range = max - min || 1;
+
+        var points = series.map(function (value, i) {
+            var x = (i / (series.length - 1)) * width;
+            var y = height - 1 - ((value - min) / range) * (height - 2);
+            return x.toFixed(1) + ',' + y.toFixed(1);
+        });
+
+        return '<svg width="' + width + '" height="' + height + '"><polyline points="' + points.join(' ') + '" /></svg>';
+
+    }
+
+    /* Return the CSS class of a profit change */
+    function trend(value) {
+        return value < 0 ? 'pnl-down' : 'pnl-up';
+    }
+
+    /* Escape text for html */
+    function escape(text) {
+        return $('<div/>').text(text).html();
+    }
+
+    function render(ticker) {
+
+        var html = '<span class="pnl-thread">P&amp;L ' + ticker.Minutes + 'm: ' +
+            'Realized <span class="' + trend(ticker.Realized) + '">' + ticker.Realized + '</span> &middot; ' +
+            'Unrealized <span class="' + trend(ticker.Unrealized) + '">' + ticker.Unrealized + '</span> &middot; ' +
+            'Total <span class="' + trend(ticker.Total) + '">' + ticker.Total + '</span> ' +
+            '<span class="' + trend(ticker.Series.length ? ticker.Total - ticker.Series[0] : 0) + '">' + sparkline(ticker.Series) + '</span></span>';
+
+        (ticker.Threads || []).forEach(function (thread) {
+            html += '<span class="pnl-thread" title="Realized ' + thread.Realized + ', unrealized ' + thread.Unrealized + '">' +
+                escape(thread.ThreadID) + ' <span class="' + trend(thread.Total) + '">' + thread.Total + '</span> ' +
+                '<span class="' + trend(thread.Change) + '">(' + (thread.Change >= 0 ? '+' : '') + thread.Change + ') ' + sparkline(thread.Series) + '</span></span>';
+        });
+
+        $('#pnlTicker').html(html);
+
+    }
+
+    function load() {
+        fetch(window.location.origin + '/pnl', {cache: 'no-cache'})
+            .then(function (response) { return response.json(); })
+            .then(render)
+            .catch(function (error) { console.log(error); });
+    }
+
+    $(function () {
+
+        if (!document.getElementById('pnlTicker')) {
+            return;
+        }
+
+        load();
+        setInterval(load, 60 * 1000);
+
+    });
+
+})();
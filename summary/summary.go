@@ -0,0 +1,402 @@
+package summary
+
+/* This package implements the scheduled performance summaries. Each schedule of the global configuration
+(SummarySchedules), one per line, is a cron expression followed by the comma separated channels the summary is sent
+to: <minute> <hour> <day of month> <month> <day of week> <channels>, i.e. "0 8 * * * telegram,email" every day at
+08:00 local time. The Master Node checks the schedules every 30 seconds and sends the realized profit and trade count
+since the previous summary of the schedule, the open exposure and the top and bottom threads by profit. */
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	rankMax      = 3                   /* Threads listed in the top and bottom ranking */
+	lookbackMax  = 31 * 24 * time.Hour /* Previous occurrence search limit, the period falls back to 24 hours */
+	fallbackSpan = 24 * time.Hour      /* Summary period when the previous occurrence is not found */
+)
+
+// ErrInvalidSchedule is returned for a schedule line that is not a valid cron expression followed by channels
+var ErrInvalidSchedule = errors.New("Schedules must be <minute> <hour> <day of month> <month> <day of week> <channels>")
+
+// Schedule struct define a summary schedule
+type Schedule struct {
+	Spec     string   /* Schedule line */
+	Channels []string /* Channels the summary is sent to */
+	minute   uint64   /* Bit sets of the matching values */
+	hour     uint64
+	day      uint64
+	month    uint64
+	weekday  uint64
+	anyDay   bool /* Day of month is * */
+	anyWeek  bool /* Day of week is * */
+}
+
+var (
+	mutex   sync.Mutex
+	lastRun = make(map[string]time.Time) /* Last summary minute of each schedule line */
+)
+
+// Run send the summaries of the schedules due at the current minute, only the Master Node sends summaries
+func Run(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	if !sessionData.MasterNode || configData.ConfigGlobal == nil || configData.ConfigGlobal.SummarySchedules == "" {
+
+		return
+
+	}
+
+	schedules, err := ParseSchedules(configData.ConfigGlobal.SummarySchedules)
+	if err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	now := time.Now().Truncate(time.Minute)
+
+	for _, schedule := range schedules {
+
+		if !schedule.Match(now) {
+
+			continue
+
+		}
+
+		mutex.Lock()
+		from, ok := lastRun[schedule.Spec]
+		if ok && !from.Before(now) { /* Already sent this minute */
+			mutex.Unlock()
+			continue
+		}
+		lastRun[schedule.Spec] = now
+		mutex.Unlock()
+
+		if !ok {
+			from = schedule.previous(now)
+		}
+
+		send(configData, sessionData, schedule, from, now)
+
+	}
+
+}
+
+// ParseSchedules parse the schedules, one per line. Empty lines and lines starting with # are skipped, the valid
+// schedules are returned with an error naming the first invalid line.
+func ParseSchedules(text string) (schedules []Schedule, err error) {
+
+	for _, line := range strings.Split(text, "\n") {
+
+		fields := strings.Fields(line)
+
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		schedule, parseErr := parse(fields)
+		if parseErr != nil {
+
+			if err == nil {
+				err = errors.New(ErrInvalidSchedule.Error() + ": " + strings.TrimSpace(line))
+			}
+
+			continue
+
+		}
+
+		schedule.Spec = strings.Join(fields, " ")
+		schedules = append(schedules, schedule)
+
+	}
+
+	return schedules, err
+
+}
+
+// Match return true when the schedule is due at the minute of t
+func (schedule Schedule) Match(t time.Time) bool {
+
+	if schedule.minute&(1<<uint(t.Minute())) == 0 ||
+		schedule.hour&(1<<uint(t.Hour())) == 0 ||
+		schedule.month&(1<<uint(t.Month())) == 0 {
+
+		return false
+
+	}
+
+	day := schedule.day&(1<<uint(t.Day())) != 0
+	weekday := schedule.weekday&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case schedule.anyDay && schedule.anyWeek:
+		return true
+	case schedule.anyDay:
+		return weekday
+	case schedule.anyWeek:
+		return day
+	}
+
+	return day || weekday /* Cron matches either field when both are restricted */
+
+}
+
+/* Return the previous occurrence of the schedule before now, or now minus 24 hours when there is none in lookbackMax */
+func (schedule Schedule) previous(now time.Time) time.Time {
+
+	for t := now.Add(-time.Minute); now.Sub(t) <= lookbackMax; t = t.Add(-time.Minute) {
+
+		if schedule.Match(t) {
+
+			return t
+
+		}
+
+	}
+
+	return now.Add(-fallbackSpan)
+
+}
+
+/* Parse the cron fields and channels of a schedule line */
+func parse(fields []string) (schedule Schedule, err error) {
+
+	if len(fields) != 6 {
+
+		return Schedule{}, ErrInvalidSchedule
+
+	}
+
+	if schedule.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return Schedule{}, err
+	}
+
+	if schedule.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return Schedule{}, err
+	}
+
+	if schedule.day, err = parseField(fields[2], 1, 31); err != nil {
+		return Schedule{}, err
+	}
+
+	if schedule.month, err = parseField(fields[3], 1, 12); err != nil {
+		return Schedule{}, err
+	}
+
+	if schedule.weekday, err = parseField(fields[4], 0, 7); err != nil {
+		return Schedule{}, err
+	}
+
+	if schedule.weekday&(1<<7) != 0 { /* 7 is Sunday */
+		schedule.weekday |= 1
+	}
+
+	schedule.anyDay = fields[2] == "*"
+	schedule.anyWeek = fields[4] == "*"
+
+	for _, channel := range strings.Split(strings.ToLower(fields[5]), ",") {
+
+		if !slices.Contains(notify.Channels, channel) {
+
+			return Schedule{}, ErrInvalidSchedule
+
+		}
+
+		schedule.Channels = append(schedule.Channels, channel)
+
+	}
+
+	return schedule, nil
+
+}
+
+/* Return the values between min and max of a cron field (*, a, a-b, with an optional /step, comma separated) as bits */
+func parseField(
+	field string,
+	min int,
+	max int) (bits uint64, err error) {
+
+	for _, part := range strings.Split(field, ",") {
+
+		step := 1
+		stepped := false
+		low, high := min, max
+
+		if i := strings.Index(part, "/"); i >= 0 {
+
+			stepped = true
+
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, ErrInvalidSchedule
+			}
+
+			part = part[:i]
+
+		}
+
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+
+			bounds := strings.SplitN(part, "-", 2)
+
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, ErrInvalidSchedule
+			}
+
+			if high, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, ErrInvalidSchedule
+			}
+
+		default:
+
+			if low, err = strconv.Atoi(part); err != nil {
+				return 0, ErrInvalidSchedule
+			}
+
+			high = low
+			if stepped { /* a/step runs from a to max */
+				high = max
+			}
+
+		}
+
+		if low < min || high > max || low > high {
+
+			return 0, ErrInvalidSchedule
+
+		}
+
+		for value := low; value <= high; value += step {
+
+			bits |= 1 << uint(value)
+
+		}
+
+	}
+
+	return bits, nil
+
+}
+
+/* Send the summary of the period from to now to the schedule channels */
+func send(
+	configData *types.Config,
+	sessionData *types.Session,
+	schedule Schedule,
+	from time.Time,
+	now time.Time) {
+
+	var summaries []types.ProfitSummary
+
+	if err := mysql.ExportThreadProfit(sessionData, from.UnixNano()/int64(time.Millisecond), now.UnixNano()/int64(time.Millisecond), func(summary types.ProfitSummary) error {
+
+		summaries = append(summaries, summary)
+		return nil
+
+	}); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return
+
+	}
+
+	profit, text := compose(summaries, sessionData.Global.ThreadAmount, sessionData.SymbolFiat, from, now)
+
+	notify.Notification{
+		Event:    messages.Summary,
+		Severity: notify.Info,
+		Title:    "Performance summary",
+		Data: messages.Data{
+			Fiat:    sessionData.SymbolFiat,
+			Profit:  profit,
+			Message: text,
+		},
+	}.SendTo(configData, sessionData, schedule.Channels)
+
+}
+
+/* Return the realized profit and the summary text of the thread summaries of the period from to to */
+func compose(
+	summaries []types.ProfitSummary,
+	exposure float64,
+	fiat string,
+	from time.Time,
+	to time.Time) (profit float64, text string) {
+
+	var buffer bytes.Buffer
+	var trades int
+
+	for _, summary := range summaries {
+
+		trades += summary.Trades
+		profit += summary.Profit
+
+	}
+
+	fmt.Fprintf(&buffer, "Performance summary %s - %s\n", from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&buffer, "Profit: %s %s\n", fiat, functions.Float64ToStr(profit, 2))
+	fmt.Fprintf(&buffer, "Trades: %d\n", trades)
+	fmt.Fprintf(&buffer, "Open exposure: %s %s", fiat, functions.Float64ToStr(exposure, 2))
+
+	ranked := append([]types.ProfitSummary(nil), summaries...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Profit > ranked[j].Profit })
+
+	top := ranked
+	if len(top) > rankMax {
+		top = top[:rankMax]
+	}
+
+	if len(top) > 0 {
+		buffer.WriteString("\nTop:")
+		for _, summary := range top {
+			fmt.Fprintf(&buffer, "\n  %s %s %s", summary.Key, summary.Symbol, functions.Float64ToStr(summary.Profit, 2))
+		}
+	}
+
+	/* Bottom threads, lowest profit first, not already listed in the top */
+	if bottom := len(ranked) - len(top); bottom > 0 {
+		if bottom > rankMax {
+			bottom = rankMax
+		}
+		buffer.WriteString("\nBottom:")
+		for i := len(ranked) - 1; i >= len(ranked)-bottom; i-- {
+			fmt.Fprintf(&buffer, "\n  %s %s %s", ranked[i].Key, ranked[i].Symbol, functions.Float64ToStr(ranked[i].Profit, 2))
+		}
+	}
+
+	return profit, buffer.String()
+
+}
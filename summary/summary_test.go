@@ -0,0 +1,99 @@
+package summary
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParseSchedules(t *testing.T) {
+	schedules, err := ParseSchedules("# daily\n0 8 * * * Telegram,email\n\n*/15 9-17 * * 1-5 slack\n0 8 * * * pager\n61 * * * * telegram\n0 8 * *")
+	if err == nil {
+		t.Errorf("ParseSchedules() error = nil, want invalid line")
+	}
+	if len(schedules) != 2 {
+		t.Fatalf("ParseSchedules() = %v schedules, want 2", len(schedules))
+	}
+	if schedules[0].Spec != "0 8 * * * Telegram,email" || !reflect.DeepEqual(schedules[0].Channels, []string{"telegram", "email"}) {
+		t.Errorf("ParseSchedules() = %v, %v", schedules[0].Spec, schedules[0].Channels)
+	}
+}
+
+func TestSchedule_Match(t *testing.T) {
+	monday := time.Date(2021, 12, 6, 8, 0, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{name: "daily", spec: "0 8 * * * telegram", t: monday, want: true},
+		{name: "daily other minute", spec: "0 8 * * * telegram", t: monday.Add(time.Minute), want: false},
+		{name: "step", spec: "*/15 9-17 * * 1-5 slack", t: monday.Add(75 * time.Minute), want: true},
+		{name: "step off", spec: "*/15 9-17 * * 1-5 slack", t: monday.Add(70 * time.Minute), want: false},
+		{name: "weekend", spec: "*/15 9-17 * * 1-5 slack", t: monday.Add(5*24*time.Hour + 75*time.Minute), want: false},
+		{name: "sunday as 7", spec: "0 8 * * 7 email", t: monday.Add(6 * 24 * time.Hour), want: true},
+		{name: "value step", spec: "5/20 * * * * email", t: monday.Add(45 * time.Minute), want: true},
+		{name: "day or weekday", spec: "0 8 1 * 1 email", t: monday, want: true},
+		{name: "first of month", spec: "0 8 1 * * email", t: monday, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedules, err := ParseSchedules(tt.spec)
+			if err != nil || len(schedules) != 1 {
+				t.Fatalf("ParseSchedules() error = %v", err)
+			}
+			if got := schedules[0].Match(tt.t); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedule_previous(t *testing.T) {
+	now := time.Date(2021, 12, 6, 8, 0, 0, 0, time.Local)
+
+	schedules, _ := ParseSchedules("0 8 * * * telegram\n0 8 29 2 * telegram")
+	if got := schedules[0].previous(now); !got.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("previous() = %v, want %v", got, now.Add(-24*time.Hour))
+	}
+	if got := schedules[1].previous(now); !got.Equal(now.Add(-fallbackSpan)) {
+		t.Errorf("previous() without occurrence = %v, want %v", got, now.Add(-fallbackSpan))
+	}
+}
+
+func Test_compose(t *testing.T) {
+	from := time.Date(2021, 12, 5, 8, 0, 0, 0, time.Local)
+	to := from.Add(24 * time.Hour)
+	summaries := []types.ProfitSummary{
+		{Key: "c683ok5mk1u1120gnmm1", Symbol: "BTCUSDT", Trades: 4, Profit: 12.5},
+		{Key: "c683ok5mk1u1120gnmm2", Symbol: "ETHUSDT", Trades: 2, Profit: -3.25},
+		{Key: "c683ok5mk1u1120gnmm3", Symbol: "BNBUSDT", Trades: 1, Profit: 1},
+		{Key: "c683ok5mk1u1120gnmm4", Symbol: "ADAUSDT", Trades: 3, Profit: 4},
+		{Key: "c683ok5mk1u1120gnmm5", Symbol: "SOLUSDT", Trades: 1, Profit: -1},
+	}
+
+	profit, text := compose(summaries, 1500, "USDT", from, to)
+	if profit != 13.25 {
+		t.Errorf("compose() profit = %v, want 13.25", profit)
+	}
+	for _, want := range []string{
+		"Performance summary 2021-12-05 08:00 - 2021-12-06 08:00",
+		"Profit: USDT 13.25",
+		"Trades: 11",
+		"Open exposure: USDT 1500.00",
+		"Top:\n  c683ok5mk1u1120gnmm1 BTCUSDT 12.50\n  c683ok5mk1u1120gnmm4 ADAUSDT 4.00\n  c683ok5mk1u1120gnmm3 BNBUSDT 1.00",
+		"Bottom:\n  c683ok5mk1u1120gnmm2 ETHUSDT -3.25\n  c683ok5mk1u1120gnmm5 SOLUSDT -1.00",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("compose() = %v, want %v", text, want)
+		}
+	}
+
+	if _, text := compose(nil, 0, "USDT", from, to); strings.Contains(text, "Top:") || strings.Contains(text, "Bottom:") {
+		t.Errorf("compose() without sales = %v", text)
+	}
+}
@@ -0,0 +1,276 @@
+package supervisor
+
+/* This package implements the thread supervisor. A thread fails when one of its goroutines panics, recovered by
+Recover instead of crashing the process, or when its trading loop stalls, with no book ticker processed for
+SupervisorTimeout seconds (the heartbeat). A failed thread is logged and notified, stopped with its session state
+saved, and started again resuming its ThreadID from the database after a backoff of SupervisorBackoff seconds doubled
+on each consecutive failure up to SupervisorMaxDelay. The failures are no longer consecutive once the thread runs for
+stablePeriod after its restart. */
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/sentry"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Supervisor defaults */
+const (
+	defaultBackoff  = 10 * time.Second
+	defaultMaxDelay = 10 * time.Minute
+	stablePeriod    = 30 * time.Minute /* Running time after a restart that resets the consecutive failures */
+)
+
+// Starter start a thread again resuming the ThreadID of the failed thread, registered by main as the execution
+// process can't be imported
+type Starter func(failed *types.Session)
+
+/* Supervision state of a ThreadID */
+type state struct {
+	started   time.Time /* Start of the supervision of the running thread */
+	restarted time.Time /* Last restart */
+	failures  int       /* Consecutive failures */
+}
+
+var supervisor = struct {
+	sync.Mutex
+	start   Starter
+	threads map[string]*state
+	failing map[*types.Session]bool /* Sessions failed, once each */
+}{
+	threads: make(map[string]*state),
+	failing: make(map[*types.Session]bool),
+}
+
+// Register the Starter of the failed threads
+func Register(start Starter) {
+
+	supervisor.Lock()
+	defer supervisor.Unlock()
+
+	supervisor.start = start
+
+}
+
+// Recover the panic of a goroutine of the thread and restart the thread, deferred by the thread goroutines
+func Recover(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	r := recover()
+	if r == nil {
+
+		return
+
+	}
+
+	sentry.Panicked(configData, sessionData, r)
+
+	Fail(configData, sessionData, fmt.Sprintf("panic - %v", r))
+
+}
+
+// Check the heartbeat of the thread at now and restart the thread when stalled. Called every 10 seconds.
+func Check(
+	configData *types.Config,
+	sessionData *types.Session,
+	now time.Time) {
+
+	if sessionData.ThreadID == "" || sessionData.Stopping {
+
+		return
+
+	}
+
+	supervisor.Lock()
+
+	s := get(sessionData.ThreadID)
+	if s.started.IsZero() {
+		s.started = now
+	}
+
+	started := s.started
+
+	supervisor.Unlock()
+
+	if configData.ConfigGlobal == nil || configData.ConfigGlobal.SupervisorTimeout <= 0 {
+
+		return
+
+	}
+
+	timeout := time.Duration(configData.ConfigGlobal.SupervisorTimeout) * time.Second
+
+	if Stalled(sessionData.LastWsBookTickerTime, started, now, timeout) {
+
+		last := sessionData.LastWsBookTickerTime
+		if last.Before(started) {
+			last = started
+		}
+
+		Fail(configData, sessionData, "stalled - no book ticker processed since "+last.Format("15:04:05"))
+
+	}
+
+}
+
+// Fail stop the thread after a failure and start it again after the backoff, once for each session
+func Fail(
+	configData *types.Config,
+	sessionData *types.Session,
+	reason string) {
+
+	supervisor.Lock()
+
+	if sessionData.Stopping || supervisor.failing[sessionData] { /* Thread stopping or already failed */
+
+		supervisor.Unlock()
+		return
+
+	}
+
+	supervisor.failing[sessionData] = true
+
+	global := configData.ConfigGlobal
+	if global == nil {
+		global = &types.ConfigGlobal{}
+	}
+
+	now := time.Now()
+
+	s := get(sessionData.ThreadID)
+
+	if !s.restarted.IsZero() && now.Sub(s.restarted) > stablePeriod {
+		s.failures = 0
+	}
+
+	s.failures++
+	s.started = time.Time{}
+	s.restarted = now
+
+	failures := s.failures
+	start := supervisor.start
+
+	supervisor.Unlock()
+
+	delay := Backoff(failures, time.Duration(global.SupervisorBackoff)*time.Second, time.Duration(global.SupervisorMaxDelay)*time.Second)
+
+	message := fmt.Sprintf("Thread failed - %s, restarting in %s (failure %d)", reason, delay, failures)
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Critical,
+		Key:      "supervisor-" + sessionData.ThreadID,
+		Title:    "Thread failed",
+		Data: messages.Data{
+			ThreadID: sessionData.ThreadID,
+			Symbol:   sessionData.Symbol,
+			Message:  message,
+		},
+	}.Send(configData, sessionData)
+
+	threads.Thread{}.Stop(configData, sessionData, message)
+
+	if start == nil || (sessionData.ThreadID == "" && sessionData.Symbol == "") { /* Nothing to resume */
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Thread failed - " + reason + ", not restarted",
+			LogLevel: "DebugLevel",
+		}.Do()
+
+		return
+
+	}
+
+	go func() {
+
+		time.Sleep(delay)
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Thread restarting after failure",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		start(sessionData)
+
+	}()
+
+}
+
+// Backoff return the delay before restarting a thread after failures consecutive failures, base doubled on each
+// failure up to max
+func Backoff(
+	failures int,
+	base time.Duration,
+	max time.Duration) time.Duration {
+
+	if base <= 0 {
+		base = defaultBackoff
+	}
+
+	if max <= 0 {
+		max = defaultMaxDelay
+	}
+
+	delay := base
+
+	for i := 1; i < failures && delay < max; i++ {
+
+		delay *= 2
+
+	}
+
+	if delay > max {
+
+		return max
+
+	}
+
+	return delay
+
+}
+
+// Stalled return true when no book ticker was processed within timeout at now, counted from started when the thread
+// didn't process one since
+func Stalled(
+	last time.Time,
+	started time.Time,
+	now time.Time,
+	timeout time.Duration) bool {
+
+	if last.Before(started) {
+
+		last = started
+
+	}
+
+	return now.Sub(last) > timeout
+
+}
+
+/* Return the state of threadID, the supervisor must be locked */
+func get(threadID string) *state {
+
+	s, ok := supervisor.threads[threadID]
+	if !ok {
+		s = &state{}
+		supervisor.threads[threadID] = s
+	}
+
+	return s
+
+}
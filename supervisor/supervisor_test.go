@@ -0,0 +1,52 @@
+package supervisor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		base     time.Duration
+		max      time.Duration
+		want     time.Duration
+	}{
+		{name: "first failure", failures: 1, base: 10 * time.Second, max: 10 * time.Minute, want: 10 * time.Second},
+		{name: "third failure", failures: 3, base: 10 * time.Second, max: 10 * time.Minute, want: 40 * time.Second},
+		{name: "capped", failures: 12, base: 10 * time.Second, max: 10 * time.Minute, want: 10 * time.Minute},
+		{name: "defaults", failures: 2, want: 20 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Backoff(tt.failures, tt.base, tt.max); got != tt.want {
+				t.Errorf("Backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStalled(t *testing.T) {
+
+	started := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		last time.Time
+		now  time.Time
+		want bool
+	}{
+		{name: "ticking", last: started.Add(10 * time.Minute), now: started.Add(11 * time.Minute), want: false},
+		{name: "stalled", last: started.Add(10 * time.Minute), now: started.Add(16 * time.Minute), want: true},
+		{name: "no tick since start", last: time.Time{}, now: started.Add(4 * time.Minute), want: false},
+		{name: "no tick since start stalled", last: time.Time{}, now: started.Add(6 * time.Minute), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stalled(tt.last, started, tt.now, 5*time.Minute); got != tt.want {
+				t.Errorf("Stalled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,34 +1,79 @@
 package telegram
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/aleibovici/cryptopump/approval"
+	"github.com/aleibovici/cryptopump/commands"
 	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/liquidation"
 	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
 	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/outbox"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/throttle"
 	"github.com/aleibovici/cryptopump/types"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
+const (
+	rateLimit      = 10               /* Commands allowed per chat in rateWindow */
+	rateWindow     = time.Minute      /* Rate limit window */
+	approvalsCheck = 10 * time.Second /* Interval between checks for manual sales pending confirmation */
+)
+
+/* Inline keyboard callback kinds */
+const (
+	callbackSell      = "sell"      /* Manual sale pending confirmation, args are the ThreadID and pending action ID */
+	callbackLiquidate = "liquidate" /* Emergency liquidation, arg is the confirmation code */
+)
+
+// ErrNotConnected is returned when the thread is not connected to a Telegram chat
+var ErrNotConnected = errors.New("Telegram not connected")
+
 // Message defines the message structure to send via Telegram
 type Message struct {
 	Text             string
 	ReplyToMessageID int
+	Keyboard         *tgbotapi.InlineKeyboardMarkup /* Optional Approve/Reject inline keyboard */
 }
 
 // Connect to connect to Telegram
 type Connect struct{}
 
+var throttled = &throttle.Channel{} /* Notification rate limit, command replies are not throttled */
+
 // Send a message via Telegram
 func (message Message) Send(sessionData *types.Session) {
 
-	msg := tgbotapi.NewMessage(sessionData.TgBotAPIChatID, message.Text)
+	if err := message.send(sessionData); err != nil {
 
-	if _, err := sessionData.TgBotAPI.Send(msg); err != nil {
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Send a notification message via Telegram, errors are logged and the message queued for retry */
+func (message Message) deliver(sessionData *types.Session) {
+
+	if err := message.send(sessionData); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -39,10 +84,83 @@ func (message Message) Send(sessionData *types.Session) {
 			LogLevel: "DebugLevel",
 		}.Do()
 
+		outbox.Enqueue(sessionData, messages.Telegram, message.Text, err) /* Retried by the Master Node */
+
 	}
 
 }
 
+/* Send the message to the Telegram chat */
+func (message Message) send(sessionData *types.Session) (err error) {
+
+	if sessionData.TgBotAPI == nil || sessionData.TgBotAPIChatID == 0 {
+
+		return ErrNotConnected
+
+	}
+
+	msg := tgbotapi.NewMessage(sessionData.TgBotAPIChatID, message.Text)
+
+	if message.Keyboard != nil {
+
+		msg.ReplyMarkup = *message.Keyboard
+
+	}
+
+	_, err = sessionData.TgBotAPI.Send(msg)
+
+	return err
+
+}
+
+// Deliver send the text of a queued notification, registered to outbox to retry failed messages
+func Deliver(
+	configData *types.Config,
+	sessionData *types.Session,
+	text string) error {
+
+	return Message{Text: text}.send(sessionData)
+
+}
+
+// Notify send a notification message via Telegram, batched into a digest when the notification rate limit is
+// exceeded unless critical
+func (message Message) Notify(
+	configData *types.Config,
+	sessionData *types.Session,
+	critical bool) {
+
+	if !throttled.Allow(throttle.Rate(configData), critical, strings.TrimPrefix(message.Text, "\f"), func(text string) {
+		Message{Text: "\f" + text}.deliver(sessionData)
+	}) {
+
+		return
+
+	}
+
+	message.deliver(sessionData)
+
+}
+
+// Notification send a routed notification via Telegram, only the Master Node is connected to Telegram
+func Notification(
+	configData *types.Config,
+	sessionData *types.Session,
+	notification notify.Notification,
+	text string) {
+
+	if sessionData.TgBotAPIChatID == 0 {
+
+		return
+
+	}
+
+	Message{
+		Text: "\f" + text,
+	}.Notify(configData, sessionData, notification.Severity == notify.Critical)
+
+}
+
 // Do establish connectivity to Telegram
 func (Connect) Do(
 	configData *types.Config,
@@ -109,8 +227,25 @@ func CheckUpdates(
 
 	}
 
+	limiter := newRateLimiter(rateLimit, rateWindow)
+
+	go approvals(configData, sessionData) /* Request approval of manual sales above SellConfirmNotional */
+
 	for update := range updates {
 
+		/* Approve/Reject inline keyboard buttons */
+		if update.CallbackQuery != nil {
+
+			if limiter.allow(int64(update.CallbackQuery.From.ID), time.Now()) {
+
+				callback(configData, sessionData, update.CallbackQuery)
+
+			}
+
+			continue
+
+		}
+
 		/* ignore any non-Message Updates */
 		if update.Message == nil {
 
@@ -118,73 +253,688 @@ func CheckUpdates(
 
 		}
 
+		chatID := update.Message.Chat.ID
+
+		/* Drop commands above the rate limit of the chat */
+		if !limiter.allow(chatID, time.Now()) {
+
+			continue
+
+		}
+
+		/* Only whitelisted chats can send commands and receive notifications */
+		if !authorized(configData.ConfigGlobal.TgChatIDs, chatID) {
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  fmt.Sprintf("Telegram command from unauthorized chat ID %d", chatID),
+				LogLevel: "InfoLevel",
+			}.Do()
+
+			if _, err := sessionData.TgBotAPI.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("Chat ID %d is not authorized", chatID))); err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   nil,
+					Market:   nil,
+					Session:  sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+			}
+
+			continue
+
+		}
+
 		/* Store Telegram ChatID to allow the system to send direct messages to Telegram server */
-		sessionData.TgBotAPIChatID = update.Message.Chat.ID
+		sessionData.TgBotAPIChatID = chatID
+
+		name, arg := parseCommand(update.Message.Text)
+
+		var text string
+		var keyboard *tgbotapi.InlineKeyboardMarkup
+
+		switch name {
+		case "/status":
+
+			text = status(sessionData)
+
+		case "/profit":
+
+			text = profit(sessionData)
 
-		switch update.Message.Text {
 		case "/sell":
 
-			Message{
-				Text:             "\f" + "Selling @ " + sessionData.ThreadID,
-				ReplyToMessageID: update.Message.MessageID,
-			}.Send(sessionData)
+			if arg != "" { /* Sell an open transaction of any thread */
 
-			sessionData.ForceSell = true
+				text = sell(sessionData, arg)
+				break
 
-		case "/buy":
+			}
 
-			Message{
-				Text:             "\f" + "Buying @ " + sessionData.ThreadID,
-				ReplyToMessageID: update.Message.MessageID,
-			}.Send(sessionData)
+			/* Queued so the sale is confirmed above SellConfirmNotional */
+			text = "Selling @ " + sessionData.ThreadID
+			if err := queue(sessionData, sessionData.ThreadID, commands.Sell, 0); err != nil {
+				text = err.Error()
+			}
 
+		case "/buy":
+
+			text = "Buying @ " + sessionData.ThreadID
 			sessionData.ForceBuy = true
 
-		case "/report":
+		case "/pause", "/resume":
 
-			var profit float64
-			var profitNet float64
-			var profitPct float64
-			var threadCount int
-			var status string
-			var err error
+			text = pause(sessionData, arg, name == "/pause")
 
-			if profit, profitNet, profitPct, err = mysql.GetProfit(sessionData); err != nil {
-				return
+		case "/stop":
+
+			threadID := arg
+			if threadID == "" {
+				threadID = sessionData.ThreadID
 			}
 
-			if threadCount, err = mysql.GetThreadCount(sessionData); err != nil {
-				return
+			text = "Stopping @ " + threadID
+			if err := queue(sessionData, threadID, commands.Stop, 0); err != nil {
+				text = err.Error()
 			}
 
-			if threadID, err := mysql.GetSessionStatus(sessionData); err == nil {
+		case "/report":
+
+			text = report(sessionData)
+
+		case "/liquidate":
+
+			/* Emergency liquidation with two-step confirmation, /liquidate followed by the Approve button or /liquidate <code> */
+			if arg == "" {
+
+				if code, err := liquidation.Request(sessionData); err != nil {
+
+					text = "Emergency liquidation request failed: " + err.Error()
 
-				if threadID != "" {
-					status = "\f" + "System Fault @ " + threadID
 				} else {
-					status = "\f" + "System nominal"
+
+					text = "Approve emergency liquidation of all threads, or confirm with /liquidate " + code + ", within 60 seconds"
+					keyboard = inlineKeyboard(callbackLiquidate, code)
+
 				}
 
+			} else if err := liquidation.Confirm(configData, sessionData, arg); err != nil {
+
+				text = err.Error()
+
+			} else {
+
+				text = "Emergency liquidation confirmed @ " + sessionData.ThreadID
+
 			}
 
+		default:
+
+			continue
+
+		}
+
+		Message{
+			Text:             "\f" + text,
+			ReplyToMessageID: update.Message.MessageID,
+			Keyboard:         keyboard,
+		}.Send(sessionData)
+
+	}
+
+}
+
+/* Send an Approve/Reject inline keyboard for each manual sale pending confirmation not yet announced */
+func approvals(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	announced := make(map[int64]bool) /* Pending action IDs already sent */
+
+	for {
+
+		time.Sleep(approvalsCheck)
+
+		if sessionData.TgBotAPIChatID == 0 { /* No authorized chat known yet */
+
+			continue
+
+		}
+
+		actions, err := mysql.GetPendingActions(sessionData)
+		if err != nil {
+
+			continue
+
+		}
+
+		pending := make(map[int64]bool)
+
+		for _, action := range actions {
+
+			pending[action.ID] = true
+
+			if announced[action.ID] || approval.Expired(action, time.Now()) {
+
+				continue
+
+			}
+
+			announced[action.ID] = true
+
 			Message{
-				Text: "\f" + "Available Funds: " + sessionData.SymbolFiat + " " + functions.Float64ToStr(sessionData.SymbolFiatFunds, 2) + "\n" +
-					"Deployed Funds: " + sessionData.SymbolFiat + " " + functions.Float64ToStr((math.Round(sessionData.Global.ThreadAmount*100)/100), 2) + "\n" +
-					"Profit: $" + functions.Float64ToStr(profit, 2) + "\n" +
-					"ROI: " + functions.Float64ToStr(getROI(profit, sessionData), 2) + "%\n" +
-					"Net Profit: $" + functions.Float64ToStr(profitNet, 2) + "\n" +
-					"Net ROI: " + functions.Float64ToStr(getROI(profitNet, sessionData), 2) + "%" + "\n" +
-					"Avg. Transaction: " + functions.Float64ToStr(profitPct, 2) + "%" + "\n" +
-					"Thread Count: " + strconv.Itoa(threadCount) + "\n" +
-					"Status: " + status + "\n" +
-					"Master: " + sessionData.ThreadID,
-				ReplyToMessageID: update.Message.MessageID,
+				Text: fmt.Sprintf("\fApprove manual sale of %.2f @ %s (OrderID %d) within 5 minutes",
+					action.Notional, action.ThreadID, action.OrderID),
+				Keyboard: inlineKeyboard(callbackSell, action.ThreadID, strconv.FormatInt(action.ID, 10)),
 			}.Send(sessionData)
 
 		}
 
+		for id := range announced { /* Forget actions no longer pending */
+
+			if !pending[id] {
+
+				delete(announced, id)
+
+			}
+
+		}
+
+	}
+
+}
+
+/* Execute the Approve/Reject button pressed by an authorized user and replace the keyboard with the result */
+func callback(
+	configData *types.Config,
+	sessionData *types.Session,
+	query *tgbotapi.CallbackQuery) {
+
+	var text string
+
+	/* Only whitelisted users can approve, in private chats the chat ID is the user ID */
+	allowed := authorized(configData.ConfigGlobal.TgChatIDs, int64(query.From.ID))
+
+	if !allowed {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  fmt.Sprintf("Telegram approval from unauthorized user ID %d", query.From.ID),
+			LogLevel: "InfoLevel",
+		}.Do()
+
+		text = fmt.Sprintf("User ID %d is not authorized", query.From.ID)
+
+	} else if approve, kind, args, ok := parseCallback(query.Data); !ok {
+
+		text = "Unknown action"
+
+	} else {
+
+		text = resolve(configData, sessionData, approve, kind, args)
+
+	}
+
+	if _, err := sessionData.TgBotAPI.AnswerCallbackQuery(tgbotapi.NewCallback(query.ID, text)); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+	if query.Message == nil || !allowed {
+
+		return
+
+	}
+
+	/* Remove the keyboard so the action cannot be approved twice */
+	if _, err := sessionData.TgBotAPI.Send(tgbotapi.NewEditMessageText(
+		query.Message.Chat.ID,
+		query.Message.MessageID,
+		query.Message.Text+"\n\n"+text)); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Approve or reject the guarded action of kind, returning the result */
+func resolve(
+	configData *types.Config,
+	sessionData *types.Session,
+	approve bool,
+	kind string,
+	args []string) string {
+
+	switch kind {
+	case callbackLiquidate:
+
+		if !approve {
+
+			if err := liquidation.Cancel(configData, sessionData, args[0]); err != nil {
+				return err.Error()
+			}
+
+			return "Emergency liquidation rejected"
+
+		}
+
+		if err := liquidation.Confirm(configData, sessionData, args[0]); err != nil {
+			return err.Error()
+		}
+
+		return "Emergency liquidation confirmed @ " + sessionData.ThreadID
+
+	case callbackSell:
+
+		threadID := args[0]
+		id, _ := strconv.ParseInt(args[1], 10, 64)
+
+		actions, err := mysql.GetPendingActions(sessionData)
+		if err != nil {
+			return err.Error()
+		}
+
+		for _, action := range actions {
+
+			if action.ID != id || action.ThreadID != threadID {
+
+				continue
+
+			}
+
+			if approval.Expired(action, time.Now()) {
+
+				if err := mysql.UpdatePendingAction(sessionData, id, "EXPIRED"); err != nil {
+					return err.Error()
+				}
+
+				return approval.ErrExpired.Error()
+
+			}
+
+			if !approve {
+
+				/* The sale is cancelled by the thread holding the order */
+				if err := queue(sessionData, threadID, commands.Reject, id); err != nil {
+					return err.Error()
+				}
+
+				return "Manual sale rejected @ " + threadID
+
+			}
+
+			/* The sale is executed by the thread holding the order */
+			if err := queue(sessionData, threadID, commands.Approve, id); err != nil {
+				return err.Error()
+			}
+
+			return "Manual sale approved @ " + threadID
+
+		}
+
+		return approval.ErrNotPending.Error()
+
+	}
+
+	return "Unknown action"
+
+}
+
+/* Return an Approve/Reject inline keyboard for the action of kind with args */
+func inlineKeyboard(
+	kind string,
+	args ...string) *tgbotapi.InlineKeyboardMarkup {
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Approve", formatCallback(true, kind, args...)),
+		tgbotapi.NewInlineKeyboardButtonData("Reject", formatCallback(false, kind, args...)),
+	))
+
+	return &keyboard
+
+}
+
+/* Return the callback data of an Approve/Reject button, i.e. approve:sell:<ThreadID>:<ID> */
+func formatCallback(
+	approve bool,
+	kind string,
+	args ...string) string {
+
+	verb := "reject"
+	if approve {
+		verb = "approve"
+	}
+
+	return strings.Join(append([]string{verb, kind}, args...), ":")
+
+}
+
+/* Parse the callback data of an Approve/Reject button, ok is false when it is not valid */
+func parseCallback(data string) (approve bool, kind string, args []string, ok bool) {
+
+	fields := strings.Split(data, ":")
+
+	if len(fields) < 3 || (fields[0] != "approve" && fields[0] != "reject") {
+
+		return false, "", nil, false
+
+	}
+
+	switch kind, args = fields[1], fields[2:]; kind {
+	case callbackLiquidate:
+
+		ok = len(args) == 1 && args[0] != ""
+
+	case callbackSell:
+
+		id, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+		ok = len(args) == 2 && args[0] != "" && err == nil && id > 0
+
+	}
+
+	if !ok {
+
+		return false, "", nil, false
+
+	}
+
+	return fields[0] == "approve", kind, args, true
+
+}
+
+/* Return the command name, lower case without the bot username, and its argument */
+func parseCommand(text string) (name string, arg string) {
+
+	fields := strings.Fields(text)
+
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+
+		return "", ""
+
+	}
+
+	name = strings.ToLower(strings.SplitN(fields[0], "@", 2)[0]) /* /status@cryptopump_bot */
+
+	return name, strings.Join(fields[1:], " ")
+
+}
+
+/* Return true when chatID is in the comma separated chatIDs whitelist */
+func authorized(
+	chatIDs string,
+	chatID int64) bool {
+
+	for _, id := range strings.Split(chatIDs, ",") {
+
+		if id = strings.TrimSpace(id); id != "" && id == strconv.FormatInt(chatID, 10) {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Rate limiter allowing limit commands per chat in a sliding window */
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+	times  map[int64][]time.Time /* Times of the allowed commands of each chat within the window */
+}
+
+func newRateLimiter(
+	limit int,
+	window time.Duration) *rateLimiter {
+
+	return &rateLimiter{limit: limit, window: window, times: make(map[int64][]time.Time)}
+
+}
+
+/* Return true and record the command when chatID sent less than limit commands in the window before now */
+func (limiter *rateLimiter) allow(
+	chatID int64,
+	now time.Time) bool {
+
+	var recent []time.Time
+
+	for _, t := range limiter.times[chatID] {
+		if now.Sub(t) < limiter.window {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= limiter.limit {
+
+		limiter.times[chatID] = recent
+		return false
+
+	}
+
+	limiter.times[chatID] = append(recent, now)
+
+	return true
+
+}
+
+/* Return the running threads with their fiat funds, order difference and status */
+func status(sessionData *types.Session) string {
+
+	sessions, err := mysql.GetSessions(sessionData)
+	if err != nil {
+
+		return err.Error()
+
+	}
+
+	if len(sessions) == 0 {
+
+		return "No threads running"
+
+	}
+
+	text := ""
+
+	for _, session := range sessions {
+
+		state := "nominal"
+		if session.Status {
+			state = "fault"
+		}
+
+		if session.ThreadID == sessionData.ThreadID {
+			state += ", master"
+		}
+
+		text += session.ThreadID + " " + session.Exchange + "\n" +
+			"  Funds: " + session.FiatSymbol + " " + functions.Float64ToStr(session.FiatFunds, 2) + "\n" +
+			"  Order Diff: " + functions.Float64ToStr(session.DiffTotal, 2) + "\n" +
+			"  Status: " + state + "\n"
+
+	}
+
+	return text
+
+}
+
+/* Return the profit and ROI of all threads */
+func profit(sessionData *types.Session) string {
+
+	profit, profitNet, profitPct, err := mysql.GetProfit(sessionData)
+	if err != nil {
+
+		return err.Error()
+
+	}
+
+	return "Profit: $" + functions.Float64ToStr(profit, 2) + "\n" +
+		"ROI: " + functions.Float64ToStr(getROI(profit, sessionData), 2) + "%\n" +
+		"Net Profit: $" + functions.Float64ToStr(profitNet, 2) + "\n" +
+		"Net ROI: " + functions.Float64ToStr(getROI(profitNet, sessionData), 2) + "%" + "\n" +
+		"Avg. Transaction: " + functions.Float64ToStr(profitPct, 2) + "%"
+
+}
+
+/* Return the funds, profit, thread count and system status report */
+func report(sessionData *types.Session) string {
+
+	var profit float64
+	var profitNet float64
+	var profitPct float64
+	var threadCount int
+	var status string
+	var err error
+
+	if profit, profitNet, profitPct, err = mysql.GetProfit(sessionData); err != nil {
+		return err.Error()
+	}
+
+	if threadCount, err = mysql.GetThreadCount(sessionData); err != nil {
+		return err.Error()
+	}
+
+	if threadID, err := mysql.GetSessionStatus(sessionData); err == nil {
+
+		if threadID != "" {
+			status = "\f" + "System Fault @ " + threadID
+		} else {
+			status = "\f" + "System nominal"
+		}
+
 	}
 
+	return "Available Funds: " + sessionData.SymbolFiat + " " + functions.Float64ToStr(sessionData.SymbolFiatFunds, 2) + "\n" +
+		"Deployed Funds: " + sessionData.SymbolFiat + " " + functions.Float64ToStr((math.Round(sessionData.Global.ThreadAmount*100)/100), 2) + "\n" +
+		"Profit: $" + functions.Float64ToStr(profit, 2) + "\n" +
+		"ROI: " + functions.Float64ToStr(getROI(profit, sessionData), 2) + "%\n" +
+		"Net Profit: $" + functions.Float64ToStr(profitNet, 2) + "\n" +
+		"Net ROI: " + functions.Float64ToStr(getROI(profitNet, sessionData), 2) + "%" + "\n" +
+		"Avg. Transaction: " + functions.Float64ToStr(profitPct, 2) + "%" + "\n" +
+		"Thread Count: " + strconv.Itoa(threadCount) + "\n" +
+		"Status: " + status + "\n" +
+		"Master: " + sessionData.ThreadID
+
+}
+
+/* Pause or resume threadID, the Master Node thread when empty */
+func pause(
+	sessionData *types.Session,
+	threadID string,
+	paused bool) string {
+
+	text := "Paused @ "
+	command := commands.Pause
+	if !paused {
+		text = "Resumed @ "
+		command = commands.Resume
+	}
+
+	if threadID == "" || threadID == sessionData.ThreadID {
+
+		if err := (threads.Thread{}).Pause(sessionData, paused, "Telegram"); err != nil {
+			return err.Error()
+		}
+
+		return text + sessionData.ThreadID
+
+	}
+
+	if err := queue(sessionData, threadID, command, 0); err != nil {
+		return err.Error()
+	}
+
+	return text + threadID
+
+}
+
+/* Sell the open transaction orderID of the thread holding it */
+func sell(
+	sessionData *types.Session,
+	orderID string) string {
+
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil || id <= 0 {
+
+		return "Invalid OrderID " + orderID
+
+	}
+
+	threadID, err := mysql.GetThreadIDByOrderID(sessionData, id)
+	if err != nil {
+
+		return err.Error()
+
+	}
+
+	if threadID == "" {
+
+		return "Order " + orderID + " is not an open transaction"
+
+	}
+
+	if err := queue(sessionData, threadID, commands.Sell, id); err != nil {
+
+		return err.Error()
+
+	}
+
+	return "Selling " + orderID + " @ " + threadID
+
+}
+
+/* Queue a command for a running threadID */
+func queue(
+	sessionData *types.Session,
+	threadID string,
+	command string,
+	orderID int64) error {
+
+	sessions, err := mysql.GetSessions(sessionData)
+	if err != nil {
+
+		return err
+
+	}
+
+	for _, session := range sessions {
+
+		if session.ThreadID == threadID {
+
+			return commands.Queue(sessionData, threadID, command, orderID, "Telegram")
+
+		}
+
+	}
+
+	return fmt.Errorf("Thread %s not running", threadID)
+
 }
 
 // getROI returns the ROI of a given profit
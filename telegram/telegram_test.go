@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantName string
+		wantArg  string
+	}{
+		{name: "command", text: "/status", wantName: "/status"},
+		{name: "argument", text: "/pause  c683ok5mk1u1120gnmmg ", wantName: "/pause", wantArg: "c683ok5mk1u1120gnmmg"},
+		{name: "bot username", text: "/Sell@cryptopump_bot 1234567", wantName: "/sell", wantArg: "1234567"},
+		{name: "not a command", text: "hello", wantName: ""},
+		{name: "empty", text: " ", wantName: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, arg := parseCommand(tt.text)
+			if name != tt.wantName || arg != tt.wantArg {
+				t.Errorf("parseCommand() = %v, %v, want %v, %v", name, arg, tt.wantName, tt.wantArg)
+			}
+		})
+	}
+}
+
+func Test_authorized(t *testing.T) {
+	tests := []struct {
+		name    string
+		chatIDs string
+		chatID  int64
+		want    bool
+	}{
+		{name: "whitelisted", chatIDs: "12345, -100987654", chatID: -100987654, want: true},
+		{name: "not whitelisted", chatIDs: "12345,-100987654", chatID: 1234, want: false},
+		{name: "empty whitelist", chatIDs: "", chatID: 12345, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authorized(tt.chatIDs, tt.chatID); got != tt.want {
+				t.Errorf("authorized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_rateLimiter_allow(t *testing.T) {
+	limiter := newRateLimiter(2, time.Minute)
+	now := time.Date(2021, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	if !limiter.allow(1, now) || !limiter.allow(1, now.Add(10*time.Second)) {
+		t.Fatalf("allow() = false, want true within the limit")
+	}
+	if limiter.allow(1, now.Add(20*time.Second)) {
+		t.Errorf("allow() = true, want false above the limit")
+	}
+	if !limiter.allow(2, now.Add(20*time.Second)) {
+		t.Errorf("allow() = false, want true for another chat")
+	}
+	if !limiter.allow(1, now.Add(61*time.Second)) {
+		t.Errorf("allow() = false, want true after the window")
+	}
+}
+
+func Test_parseCallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantApprove bool
+		wantKind    string
+		wantArgs    int
+		wantOk      bool
+	}{
+		{name: "approve sell", data: formatCallback(true, callbackSell, "c683ok5mk1u1120gnmmg", "12"), wantApprove: true, wantKind: callbackSell, wantArgs: 2, wantOk: true},
+		{name: "reject sell", data: formatCallback(false, callbackSell, "c683ok5mk1u1120gnmmg", "12"), wantApprove: false, wantKind: callbackSell, wantArgs: 2, wantOk: true},
+		{name: "approve liquidate", data: "approve:liquidate:012345", wantApprove: true, wantKind: callbackLiquidate, wantArgs: 1, wantOk: true},
+		{name: "invalid action ID", data: "approve:sell:c683ok5mk1u1120gnmmg:abc", wantOk: false},
+		{name: "missing code", data: "approve:liquidate:", wantOk: false},
+		{name: "unknown kind", data: "approve:buy:12", wantOk: false},
+		{name: "unknown verb", data: "maybe:liquidate:012345", wantOk: false},
+		{name: "empty", data: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approve, kind, args, ok := parseCallback(tt.data)
+			if ok != tt.wantOk || approve != tt.wantApprove || kind != tt.wantKind || len(args) != tt.wantArgs {
+				t.Errorf("parseCallback() = %v, %v, %v, %v, want %v, %v, %d args, %v", approve, kind, args, ok, tt.wantApprove, tt.wantKind, tt.wantArgs, tt.wantOk)
+			}
+		})
+	}
+}
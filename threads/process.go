@@ -0,0 +1,136 @@
+package threads
+
+/* Threads of the process. One process runs a thread for each symbol of the -symbols flag, each with its own session,
+lock file and websockets, and the threads are registered here once started so the graceful shutdown and the restart
+stop all of them. A thread terminated while others keep running only stops its own goroutines and tasks. */
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// SymbolsFlag is the command line flag of the symbols run as threads of one process
+const SymbolsFlag = "symbols"
+
+// Clone struct define the result of cloning the configuration of a thread onto a symbol
+type Clone struct {
+	Symbol string `json:"symbol"`
+	Error  string `json:"error,omitempty"` /* Reason the symbol was skipped, empty when its thread is starting */
+}
+
+var registry = struct {
+	sync.Mutex
+	sessions map[*types.Session]bool
+}{
+	sessions: make(map[*types.Session]bool),
+}
+
+// Register a thread started in this process
+func Register(sessionData *types.Session) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.sessions[sessionData] = true
+
+}
+
+// Sessions return the threads running in this process, sorted by ThreadID
+func Sessions() (sessions []*types.Session) {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	for sessionData := range registry.sessions {
+
+		sessions = append(sessions, sessionData)
+
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ThreadID < sessions[j].ThreadID })
+
+	return sessions
+
+}
+
+// RunTaskAtInterval run task after delay and then every interval until the thread is stopping
+func RunTaskAtInterval(
+	sessionData *types.Session,
+	task func(),
+	interval time.Duration,
+	delay time.Duration) {
+
+	go func() {
+
+		time.Sleep(delay)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for !sessionData.Stopping {
+
+			task()
+			<-ticker.C
+
+		}
+
+	}()
+
+}
+
+// ParseSymbols return the symbols of lists separated by commas or spaces, uppercase and without duplicates
+func ParseSymbols(lists []string) (symbols []string) {
+
+	seen := make(map[string]bool)
+
+	for _, list := range lists {
+
+		for _, symbol := range strings.FieldsFunc(strings.ToUpper(list), func(r rune) bool { return r == ',' || r == ' ' || r == '\n' || r == '\t' }) {
+
+			if !seen[symbol] {
+
+				seen[symbol] = true
+				symbols = append(symbols, symbol)
+
+			}
+
+		}
+
+	}
+
+	return symbols
+
+}
+
+// Running return true when a thread of symbol runs in this process
+func Running(symbol string) bool {
+
+	for _, sessionData := range Sessions() {
+
+		if sessionData.Symbol == symbol && !sessionData.Stopping {
+
+			return true
+
+		}
+
+	}
+
+	return false
+
+}
+
+/* Remove a thread from the registry and return the number of threads still running in this process */
+func unregister(sessionData *types.Session) int {
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.sessions, sessionData)
+
+	return len(registry.sessions)
+
+}
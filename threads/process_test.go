@@ -0,0 +1,83 @@
+package threads
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestSessions(t *testing.T) {
+
+	eth := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "ETHUSDT"}
+	btc := &types.Session{ThreadID: "c2q3mt84a8024t1f6590", Symbol: "BTCUSDT"}
+
+	Register(eth)
+	Register(btc)
+
+	if got := Sessions(); len(got) != 2 || got[0] != btc || got[1] != eth {
+		t.Errorf("Sessions() = %v, want [%v %v]", got, btc, eth)
+	}
+
+	if got := unregister(btc); got != 1 {
+		t.Errorf("unregister() = %v, want 1", got)
+	}
+
+	if got := unregister(eth); got != 0 {
+		t.Errorf("unregister() = %v, want 0", got)
+	}
+
+}
+
+func TestRunTaskAtInterval(t *testing.T) {
+
+	sessionData := &types.Session{}
+
+	var runs int64
+
+	RunTaskAtInterval(sessionData, func() { atomic.AddInt64(&runs, 1) }, 10*time.Millisecond, 0)
+
+	time.Sleep(55 * time.Millisecond)
+
+	sessionData.Stopping = true
+
+	time.Sleep(30 * time.Millisecond)
+
+	stopped := atomic.LoadInt64(&runs)
+
+	if stopped < 2 {
+		t.Errorf("RunTaskAtInterval() runs = %v, want at least 2", stopped)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if got := atomic.LoadInt64(&runs); got != stopped {
+		t.Errorf("RunTaskAtInterval() runs after stopping = %v, want %v", got, stopped)
+	}
+
+}
+
+func TestParseSymbols(t *testing.T) {
+
+	got := ParseSymbols([]string{"ethusdt, BNBUSDT ADAUSDT", "ETHUSDT", ""})
+
+	if want := []string{"ETHUSDT", "BNBUSDT", "ADAUSDT"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSymbols() = %v, want %v", got, want)
+	}
+
+}
+
+func TestRunning(t *testing.T) {
+
+	eth := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "ETHUSDT"}
+
+	Register(eth)
+	defer unregister(eth)
+
+	if !Running("ETHUSDT") || Running("BTCUSDT") {
+		t.Errorf("Running() = %v, %v, want true, false", Running("ETHUSDT"), Running("BTCUSDT"))
+	}
+
+}
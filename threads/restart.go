@@ -0,0 +1,117 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package threads
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/nodes"
+	"github.com/aleibovici/cryptopump/types"
+	"github.com/aleibovici/cryptopump/webhooks"
+)
+
+// Restart the process on the same port resuming its threads. The session state of each thread is saved and the
+// Session table rows are kept so the new process restores them, then the thread locks and node roles are released.
+// Restart only returns on failure, with the threads locked again.
+func (Thread) Restart(configData *types.Config, sessionData *types.Session, message string) (err error) {
+
+	executable, err := os.Executable()
+	if err != nil {
+
+		return err
+
+	}
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "DebugLevel",
+	}.Do()
+
+	sessions := Sessions()
+	if len(sessions) == 0 {
+		sessions = []*types.Session{sessionData}
+	}
+
+	var threadIDs []string
+
+	for _, session := range sessions {
+
+		/* Verify wether buying/selling to allow graceful restart */
+		for session.Busy {
+
+			time.Sleep(time.Millisecond * 200)
+
+		}
+
+		save(configData, session) /* Session state restored when the thread resumes */
+
+		events.Publish(configData, session, events.SessionStopped, events.Session{
+			Symbol: session.Symbol,
+			Port:   session.Port,
+			Reason: message,
+		})
+
+		/* Release node role if Master */
+		if session.MasterNode {
+
+			nodes.Node{}.ReleaseMasterRole(session)
+
+		}
+
+		Thread{}.Unlock(session) /* The new process locks the thread when resuming */
+
+		threadIDs = append(threadIDs, session.ThreadID)
+
+	}
+
+	webhooks.Flush(10 * time.Second) /* Wait up to 10 seconds for the deliveries before exit */
+
+	err = syscall.Exec(executable, restartArgs(os.Args, strings.Join(threadIDs, ",")), append(os.Environ(), "PORT="+sessionData.Port))
+
+	for _, session := range sessions {
+
+		Thread{}.Lock(session)
+
+	}
+
+	return err
+
+}
+
+/* Return the command line arguments of the restarted process resuming threadIDs, without resume or symbols flags */
+func restartArgs(args []string, threadIDs string) (restart []string) {
+
+	for i := 0; i < len(args); i++ {
+
+		name := strings.TrimLeft(args[i], "-")
+
+		if i > 0 && (name == ResumeFlag || name == SymbolsFlag) { /* Skip the flag and its value */
+
+			i++
+			continue
+
+		}
+
+		if i > 0 && (strings.HasPrefix(name, ResumeFlag+"=") || strings.HasPrefix(name, SymbolsFlag+"=")) {
+
+			continue
+
+		}
+
+		restart = append(restart, args[i])
+
+	}
+
+	return append(restart, "-"+ResumeFlag, threadIDs)
+
+}
@@ -0,0 +1,17 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package threads
+
+import (
+	"errors"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// Restart is not supported, the process can't replace itself on this platform
+func (Thread) Restart(configData *types.Config, sessionData *types.Session, message string) (err error) {
+
+	return errors.New("restart not supported on this platform")
+
+}
@@ -0,0 +1,30 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package threads
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_restartArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{name: "no flags", args: []string{"./cryptopump"}, want: []string{"./cryptopump", "-resume", "c683ok5mk1u1120gnmmg"}},
+		{name: "other flags", args: []string{"./cryptopump", "-debug", "6060"}, want: []string{"./cryptopump", "-debug", "6060", "-resume", "c683ok5mk1u1120gnmmg"}},
+		{name: "previous restart", args: []string{"./cryptopump", "-resume", "c2q3mt84a8024t1f6590", "-debug=6060"}, want: []string{"./cryptopump", "-debug=6060", "-resume", "c683ok5mk1u1120gnmmg"}},
+		{name: "previous restart with equal", args: []string{"./cryptopump", "--resume=c2q3mt84a8024t1f6590"}, want: []string{"./cryptopump", "-resume", "c683ok5mk1u1120gnmmg"}},
+		{name: "symbols", args: []string{"./cryptopump", "-symbols", "BTCUSDT,ETHUSDT", "--symbols=BNBUSDT"}, want: []string{"./cryptopump", "-resume", "c683ok5mk1u1120gnmmg"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restartArgs(tt.args, "c683ok5mk1u1120gnmmg"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("restartArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
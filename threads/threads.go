@@ -2,15 +2,23 @@ package threads
 
 import (
 	"os"
+	"runtime"
 	"time"
 
+	"github.com/aleibovici/cryptopump/events"
 	"github.com/aleibovici/cryptopump/functions"
 	"github.com/aleibovici/cryptopump/logger"
 	"github.com/aleibovici/cryptopump/mysql"
 	"github.com/aleibovici/cryptopump/nodes"
 	"github.com/aleibovici/cryptopump/types"
+	"github.com/aleibovici/cryptopump/webhooks"
 )
 
+// ResumeFlag is the command line flag of the ThreadID resumed by a restarted process
+const ResumeFlag = "resume"
+
+const stopTimeout = 30 * time.Second /* Wait for the buy or sell in progress of a thread stopped by the supervisor */
+
 // Thread locking control
 type Thread struct{}
 
@@ -37,6 +45,16 @@ func (Thread) Terminate(sessionData *types.Session, message string) {
 
 	}
 
+	sessionData.Stopping = true /* Stop the tasks of the thread */
+	sessionData.StopWs = true   /* Set all goroutine channels to stop */
+
+	events.Publish(nil, sessionData, events.SessionStopped, events.Session{
+		Symbol: sessionData.Symbol,
+		Port:   sessionData.Port,
+		Reason: message,
+	})
+	webhooks.Flush(10 * time.Second) /* Wait up to 10 seconds for the deliveries before exit */
+
 	/* Release node role if Master */
 	if sessionData.MasterNode {
 
@@ -47,8 +65,8 @@ func (Thread) Terminate(sessionData *types.Session, message string) {
 	// Unlock existing thread
 	Thread{}.Unlock(sessionData)
 
-	/* Delete session from Session table */
-	if err := mysql.DeleteSession(sessionData); err != nil {
+	/* Keep or delete session on Session table */
+	if err := stopSession(sessionData); err != nil {
 
 		logger.LogEntry{ /* Log Entry */
 			Config:   nil,
@@ -72,10 +90,132 @@ func (Thread) Terminate(sessionData *types.Session, message string) {
 
 	}
 
+	/* Other threads keep running in this process, only the goroutines and tasks of this thread stop */
+	if unregister(sessionData) > 0 {
+
+		runtime.Goexit()
+
+	}
+
 	os.Exit(1)
 
 }
 
+// Shutdown stop the threads of the process and exit once a graceful shutdown stopped new buys and drained the calls
+// in progress. The session state of each thread is saved before its session is stopped as with Terminate.
+func (Thread) Shutdown(configData *types.Config, sessions []*types.Session, message string) {
+
+	failed := false
+
+	for _, sessionData := range sessions {
+
+		save(configData, sessionData)
+
+		err := stopSession(sessionData)
+
+		events.Publish(configData, sessionData, events.SessionStopped, events.Session{
+			Symbol: sessionData.Symbol,
+			Port:   sessionData.Port,
+			Reason: message,
+		})
+
+		/* Release node role if Master */
+		if sessionData.MasterNode {
+
+			nodes.Node{}.ReleaseMasterRole(sessionData)
+
+		}
+
+		// Unlock existing thread
+		Thread{}.Unlock(sessionData)
+
+		if err != nil {
+
+			failed = true
+
+			logger.LogEntry{ /* Log Entry */
+				Config:   configData,
+				Market:   nil,
+				Session:  sessionData,
+				Order:    &types.Order{},
+				Message:  "Clean Shutdown Failed",
+				LogLevel: "DebugLevel",
+			}.Do()
+
+			continue
+
+		}
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   configData,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  "Clean Shutdown",
+			LogLevel: "InfoLevel",
+		}.Do()
+
+	}
+
+	webhooks.Flush(10 * time.Second) /* Wait up to 10 seconds for the deliveries before exit */
+
+	if failed {
+
+		os.Exit(1)
+
+	}
+
+	os.Exit(0)
+
+}
+
+// Stop the thread without exiting the process, for the supervisor to start it again. New buys and the tasks and
+// websockets of the thread stop, the buy or sell in progress is waited for up to stopTimeout as the thread may be
+// stalled in it, and the session state is saved with the Session table row kept so the thread resumes from it.
+func (Thread) Stop(configData *types.Config, sessionData *types.Session, message string) {
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "DebugLevel",
+	}.Do()
+
+	sessionData.Stopping = true /* Stop the tasks of the thread */
+	sessionData.StopWs = true   /* Set all goroutine channels to stop */
+
+	deadline := time.Now().Add(stopTimeout)
+
+	/* Verify wether buying/selling to allow graceful stop */
+	for sessionData.Busy && time.Now().Before(deadline) {
+
+		time.Sleep(time.Millisecond * 200)
+
+	}
+
+	save(configData, sessionData) /* Session state restored when the thread resumes */
+
+	events.Publish(configData, sessionData, events.SessionStopped, events.Session{
+		Symbol: sessionData.Symbol,
+		Port:   sessionData.Port,
+		Reason: message,
+	})
+
+	/* Release node role if Master */
+	if sessionData.MasterNode {
+
+		nodes.Node{}.ReleaseMasterRole(sessionData)
+
+	}
+
+	Thread{}.Unlock(sessionData) /* The thread started again locks the thread when resuming */
+
+	unregister(sessionData)
+
+}
+
 // Lock existing thread
 func (Thread) Lock(sessionData *types.Session) bool {
 
@@ -128,3 +268,59 @@ func (Thread) Unlock(sessionData *types.Session) {
 	}
 
 }
+
+// Pause or resume new buys of a thread, exits are still managed while paused. The paused state is saved in the
+// Session table so it survives restarts.
+func (Thread) Pause(sessionData *types.Session, paused bool, source string) (err error) {
+
+	sessionData.Paused = paused
+
+	if err = mysql.UpdateSessionPaused(sessionData); err != nil {
+
+		return err
+
+	}
+
+	kind, message := "resume", "Thread resumed by "+source
+	if paused {
+		kind, message = "pause", "Thread paused by "+source
+	}
+
+	_ = mysql.SaveThreadEvent(sessionData, kind, source, message) /* Thread timeline */
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   nil,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  message,
+		LogLevel: "InfoLevel",
+	}.Do()
+
+	return nil
+
+}
+
+/* Save the session state restored when the thread resumes, failures are logged by mysql */
+func save(configData *types.Config, sessionData *types.Session) {
+
+	_ = mysql.UpdateSession(configData, sessionData)
+	_ = mysql.UpdateSessionStopPrice(sessionData)
+	_ = mysql.UpdateSessionTrailingHigh(sessionData)
+	_ = mysql.UpdateSessionCooldown(sessionData)
+	_ = mysql.UpdateSessionPaused(sessionData)
+
+}
+
+/* Keep the session as STOPPED for the thread to resume its open transactions, otherwise delete it */
+func stopSession(sessionData *types.Session) error {
+
+	if count, _ := mysql.GetThreadTransactionCount(sessionData); count > 0 {
+
+		return mysql.UpdateSessionState(sessionData, mysql.SessionStopped)
+
+	}
+
+	return mysql.DeleteSession(sessionData)
+
+}
@@ -0,0 +1,177 @@
+package throttle
+
+/* This package implements the notification rate limiting of a channel. Once a channel sent the configured number of
+notifications within the last minute (NotifyRateMax), further notifications are batched and sent as a single digest
+message when the minute ends, so volatile periods don't flood the channel. Critical notifications bypass the limit
+and are never batched. */
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	window    = time.Minute /* Rate limit window */
+	digestMax = 20          /* Notifications listed in a digest, the rest are counted */
+)
+
+// Channel struct define the rate limit state of a notification channel
+type Channel struct {
+	mutex   sync.Mutex
+	sent    []time.Time /* Notifications sent within the window */
+	pending []string    /* Notifications batched into the next digest */
+	timer   *time.Timer
+}
+
+// Allow return true when text can be sent now. Otherwise text is batched and digest is called with the digest of
+// the batched notifications at the end of the window. Critical notifications and rate 0 are never throttled.
+func (channel *Channel) Allow(
+	rate int,
+	critical bool,
+	text string,
+	digest func(text string)) bool {
+
+	if critical || rate <= 0 {
+
+		return true
+
+	}
+
+	channel.mutex.Lock()
+	defer channel.mutex.Unlock()
+
+	now := time.Now()
+
+	if channel.allow(rate, now) {
+
+		return true
+
+	}
+
+	channel.pending = append(channel.pending, text)
+
+	if channel.timer == nil {
+
+		channel.timer = time.AfterFunc(channel.wait(now), func() {
+
+			if text := channel.flush(time.Now()); text != "" {
+				digest(text)
+			}
+
+		})
+
+	}
+
+	return false
+
+}
+
+// Rate return the notifications allowed per minute and channel, 0 when not configured
+func Rate(configData *types.Config) int {
+
+	if configData == nil || configData.ConfigGlobal == nil {
+
+		return 0
+
+	}
+
+	return configData.ConfigGlobal.NotifyRateMax
+
+}
+
+/* Return true and record the notification when under rate within the window and nothing is batched (keeps order) */
+func (channel *Channel) allow(
+	rate int,
+	now time.Time) bool {
+
+	sent := channel.sent[:0]
+
+	for _, t := range channel.sent {
+
+		if now.Sub(t) < window {
+			sent = append(sent, t)
+		}
+
+	}
+
+	channel.sent = sent
+
+	if len(channel.pending) > 0 || len(channel.sent) >= rate {
+
+		return false
+
+	}
+
+	channel.sent = append(channel.sent, now)
+
+	return true
+
+}
+
+/* Return the time until the oldest notification sent leaves the window */
+func (channel *Channel) wait(now time.Time) time.Duration {
+
+	if len(channel.sent) == 0 {
+
+		return window
+
+	}
+
+	if wait := channel.sent[0].Add(window).Sub(now); wait > time.Second {
+
+		return wait
+
+	}
+
+	return time.Second
+
+}
+
+/* Return the digest of the batched notifications, recorded as sent at now, and clear the batch */
+func (channel *Channel) flush(now time.Time) string {
+
+	channel.mutex.Lock()
+	defer channel.mutex.Unlock()
+
+	pending := channel.pending
+
+	channel.pending = nil
+	channel.timer = nil
+
+	if len(pending) == 0 {
+
+		return ""
+
+	}
+
+	channel.sent = append(channel.sent, now)
+
+	return digest(pending)
+
+}
+
+/* Return a message listing the notifications, up to digestMax */
+func digest(texts []string) string {
+
+	lines := []string{fmt.Sprintf("%d notifications batched:", len(texts))}
+
+	for i, text := range texts {
+
+		if i == digestMax {
+
+			lines = append(lines, fmt.Sprintf("... and %d more", len(texts)-digestMax))
+			break
+
+		}
+
+		lines = append(lines, text)
+
+	}
+
+	return strings.Join(lines, "\n")
+
+}
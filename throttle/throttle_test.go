@@ -0,0 +1,84 @@
+package throttle
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestChannel_allow(t *testing.T) {
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+	channel := &Channel{}
+
+	for i := 0; i < 3; i++ {
+		if !channel.allow(3, now.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("allow() notification %d = false, want true", i+1)
+		}
+	}
+	if channel.allow(3, now.Add(10*time.Second)) {
+		t.Errorf("allow() over rate = true, want false")
+	}
+	if wait := channel.wait(now.Add(10 * time.Second)); wait != 50*time.Second {
+		t.Errorf("wait() = %v, want 50s", wait)
+	}
+	if !channel.allow(3, now.Add(60*time.Second)) {
+		t.Errorf("allow() after the window = false, want true")
+	}
+
+	channel.pending = []string{"SELL BTCUSDT"}
+	if channel.allow(3, now.Add(5*time.Minute)) {
+		t.Errorf("allow() with batched notifications = true, want false")
+	}
+}
+
+func TestChannel_Allow(t *testing.T) {
+	channel := &Channel{}
+	digests := make(chan string, 1)
+	digest := func(text string) { digests <- text }
+
+	if !channel.Allow(1, false, "BUY BTCUSDT", digest) {
+		t.Fatalf("Allow() first notification = false, want true")
+	}
+	if channel.Allow(1, false, "SELL BTCUSDT", digest) || channel.Allow(1, false, "BUY ETHUSDT", digest) {
+		t.Errorf("Allow() over rate = true, want false")
+	}
+	if !channel.Allow(1, true, "System Fault", digest) {
+		t.Errorf("Allow() critical = false, want true")
+	}
+	if !channel.Allow(0, false, "BUY BNBUSDT", digest) {
+		t.Errorf("Allow() without rate = false, want true")
+	}
+
+	channel.timer.Stop()
+	if got, want := channel.flush(time.Now()), "2 notifications batched:\nSELL BTCUSDT\nBUY ETHUSDT"; got != want {
+		t.Errorf("flush() = %q, want %q", got, want)
+	}
+	if channel.flush(time.Now()) != "" || channel.timer != nil {
+		t.Errorf("flush() without batched notifications = not empty")
+	}
+}
+
+func Test_digest(t *testing.T) {
+	var texts []string
+	for i := 1; i <= 25; i++ {
+		texts = append(texts, fmt.Sprintf("BUY %d", i))
+	}
+
+	got := digest(texts)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 22 || lines[0] != "25 notifications batched:" || lines[20] != "BUY 20" || lines[21] != "... and 5 more" {
+		t.Errorf("digest() = %v", got)
+	}
+}
+
+func TestRate(t *testing.T) {
+	if got := Rate(&types.Config{ConfigGlobal: &types.ConfigGlobal{NotifyRateMax: 10}}); got != 10 {
+		t.Errorf("Rate() = %v, want 10", got)
+	}
+	if got := Rate(&types.Config{}); got != 0 {
+		t.Errorf("Rate() without global configuration = %v, want 0", got)
+	}
+}
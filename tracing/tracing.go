@@ -0,0 +1,374 @@
+package tracing
+
+/* This package implements the OpenTelemetry tracing of the trade pipeline. Each websocket tick that leads to a trade
+decision is traced: the tick root span, the decision span, and the exchange and database calls made while the trade is
+processed. Ticks without a trade decision are not traced. Finished traces are batched and exported every 5 seconds to
+the OTLP/HTTP collector endpoint of the global configuration (OtlpEndpoint) as OTLP JSON, so the latency of the
+decision-to-fill path can be analyzed in any OpenTelemetry backend (Jaeger, Tempo...). */
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+const (
+	serviceName = "cryptopump"
+	scopeName   = "github.com/aleibovici/cryptopump"
+	pendingMax  = 2048 /* Finished spans awaiting export, the oldest are dropped */
+)
+
+/* OTLP span kinds */
+const (
+	KindInternal = 1
+	KindClient   = 3
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Span struct define a span of a trace
+type Span struct {
+	TraceID    string /* 32 hex characters */
+	SpanID     string /* 16 hex characters */
+	ParentID   string /* Empty for the root span */
+	Name       string
+	Kind       int
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]string
+	Err        string /* Error status message, empty when the span succeeded */
+	trace      *Trace
+}
+
+// Trace struct define the trace of a trade decision
+type Trace struct {
+	mutex       sync.Mutex
+	root        *Span
+	spans       []*Span /* Finished spans */
+	sessionData *types.Session
+}
+
+var (
+	mutex   sync.Mutex
+	active  = make(map[*types.Session]*Trace) /* Trace of the trade being processed by each session */
+	pending []*Span                           /* Finished spans awaiting export */
+)
+
+// Begin a trace of the trade decision of sessionData with a root span started at start, nil when tracing is disabled.
+// Spans started with Start until Finish are children of the root span.
+func Begin(
+	configData *types.Config,
+	sessionData *types.Session,
+	name string,
+	start time.Time,
+	attributes map[string]string) *Trace {
+
+	if configData.ConfigGlobal == nil || configData.ConfigGlobal.OtlpEndpoint == "" {
+
+		return nil
+
+	}
+
+	trace := &Trace{sessionData: sessionData}
+	trace.root = &Span{
+		TraceID:    newID(16),
+		SpanID:     newID(8),
+		Name:       name,
+		Kind:       KindInternal,
+		StartTime:  start,
+		Attributes: map[string]string{"thread.id": sessionData.ThreadID, "symbol": sessionData.Symbol},
+		trace:      trace,
+	}
+
+	for key, value := range attributes {
+		trace.root.Attributes[key] = value
+	}
+
+	mutex.Lock()
+	active[sessionData] = trace
+	mutex.Unlock()
+
+	return trace
+
+}
+
+// Span add a finished child span of the root span from start to end
+func (trace *Trace) Span(
+	name string,
+	start time.Time,
+	end time.Time,
+	attributes map[string]string) {
+
+	if trace == nil {
+
+		return
+
+	}
+
+	span := trace.child(name, KindInternal, attributes)
+	span.StartTime = start
+	span.EndTime = end
+
+	trace.add(span)
+
+}
+
+// Finish end the root span and queue the spans of the trace for export
+func (trace *Trace) Finish() {
+
+	if trace == nil {
+
+		return
+
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if active[trace.sessionData] == trace {
+		delete(active, trace.sessionData)
+	}
+
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+
+	trace.root.EndTime = time.Now()
+	pending = append(pending, trace.root)
+	pending = append(pending, trace.spans...)
+
+	if len(pending) > pendingMax {
+		pending = pending[len(pending)-pendingMax:]
+	}
+
+}
+
+// Start a client span (exchange or database call) as child of the trace of sessionData being processed, nil when no
+// trace is active
+func Start(
+	sessionData *types.Session,
+	name string) *Span {
+
+	mutex.Lock()
+	trace := active[sessionData]
+	mutex.Unlock()
+
+	if trace == nil {
+
+		return nil
+
+	}
+
+	span := trace.child(name, KindClient, nil)
+	span.StartTime = time.Now()
+
+	return span
+
+}
+
+// End the span with the error of the call, nil when it succeeded
+func (span *Span) End(err error) {
+
+	if span == nil {
+
+		return
+
+	}
+
+	span.EndTime = time.Now()
+
+	if err != nil {
+		span.Err = err.Error()
+	}
+
+	span.trace.add(span)
+
+}
+
+/* Return a new child span of the root span */
+func (trace *Trace) child(
+	name string,
+	kind int,
+	attributes map[string]string) *Span {
+
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+
+	return &Span{
+		TraceID:    trace.root.TraceID,
+		SpanID:     newID(8),
+		ParentID:   trace.root.SpanID,
+		Name:       name,
+		Kind:       kind,
+		Attributes: attributes,
+		trace:      trace,
+	}
+
+}
+
+/* Add a finished span to the trace */
+func (trace *Trace) add(span *Span) {
+
+	trace.mutex.Lock()
+	defer trace.mutex.Unlock()
+
+	trace.spans = append(trace.spans, span)
+
+}
+
+// Export post the finished spans to the OTLP/HTTP collector endpoint, spans that fail to export are logged and dropped
+func Export(
+	configData *types.Config,
+	sessionData *types.Session) {
+
+	mutex.Lock()
+	spans := pending
+	pending = nil
+	mutex.Unlock()
+
+	if len(spans) == 0 || configData.ConfigGlobal == nil || configData.ConfigGlobal.OtlpEndpoint == "" {
+
+		return
+
+	}
+
+	if err := post(configData.ConfigGlobal.OtlpEndpoint, spans); err != nil {
+
+		logger.LogEntry{ /* Log Entry */
+			Config:   nil,
+			Market:   nil,
+			Session:  sessionData,
+			Order:    &types.Order{},
+			Message:  functions.GetFunctionName() + " - " + strconv.Itoa(len(spans)) + " spans dropped: " + err.Error(),
+			LogLevel: "DebugLevel",
+		}.Do()
+
+	}
+
+}
+
+/* Post spans as an OTLP JSON export request to endpoint */
+func post(
+	endpoint string,
+	spans []*Span) (err error) {
+
+	var body []byte
+	var response *http.Response
+
+	if body, err = json.Marshal(request(spans)); err != nil {
+
+		return err
+
+	}
+
+	if response, err = client.Post(strings.TrimRight(endpoint, "/")+"/v1/traces", "application/json", bytes.NewReader(body)); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+
+		return fmt.Errorf("OTLP collector returned %s", response.Status)
+
+	}
+
+	return nil
+
+}
+
+/* Return the OTLP JSON export request of spans */
+func request(spans []*Span) map[string]interface{} {
+
+	var otlpSpans []map[string]interface{}
+
+	for _, span := range spans {
+
+		otlpSpan := map[string]interface{}{
+			"traceId":           span.TraceID,
+			"spanId":            span.SpanID,
+			"name":              span.Name,
+			"kind":              span.Kind,
+			"startTimeUnixNano": strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			"attributes":        attributes(span.Attributes),
+		}
+
+		if span.ParentID != "" {
+			otlpSpan["parentSpanId"] = span.ParentID
+		}
+
+		if span.Err != "" {
+			otlpSpan["status"] = map[string]interface{}{"code": 2, "message": span.Err} /* STATUS_CODE_ERROR */
+		}
+
+		otlpSpans = append(otlpSpans, otlpSpan)
+
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": attributes(map[string]string{"service.name": serviceName}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": scopeName},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+
+}
+
+/* Return OTLP string attributes */
+func attributes(values map[string]string) (list []map[string]interface{}) {
+
+	list = []map[string]interface{}{}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+
+		list = append(list, map[string]interface{}{
+			"key":   key,
+			"value": map[string]string{"stringValue": values[key]},
+		})
+
+	}
+
+	return list
+
+}
+
+/* Return a random ID of size bytes as hex */
+func newID(size int) string {
+
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestBegin_disabled(t *testing.T) {
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg"}
+
+	trace := Begin(&types.Config{ConfigGlobal: &types.ConfigGlobal{}}, sessionData, "tick", time.Now(), nil)
+	if trace != nil {
+		t.Fatalf("Begin() = %v, want nil", trace)
+	}
+
+	/* Disabled traces and spans are no-ops */
+	trace.Span("decision", time.Now(), time.Now(), nil)
+	Start(sessionData, "exchange.BuyOrder").End(nil)
+	trace.Finish()
+}
+
+func TestExport(t *testing.T) {
+	var path string
+	var body struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Kind         int    `json:"kind"`
+					Status       struct {
+						Code    int    `json:"code"`
+						Message string `json:"message"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer server.Close()
+
+	configData := &types.Config{ConfigGlobal: &types.ConfigGlobal{OtlpEndpoint: server.URL + "/"}}
+	sessionData := &types.Session{ThreadID: "c683ok5mk1u1120gnmmg", Symbol: "BTCUSDT"}
+	other := &types.Session{ThreadID: "c683ok5mk1u1120gnmmh"}
+
+	if span := Start(sessionData, "mysql.GetThreadCount"); span != nil {
+		t.Errorf("Start() without trace = %v, want nil", span)
+	}
+
+	tick := time.Now()
+	trace := Begin(configData, sessionData, "tick", tick, map[string]string{"side": "BUY"})
+	trace.Span("decision", tick, time.Now(), nil)
+	Start(sessionData, "exchange.BuyOrder").End(errors.New("<APIError> code=-2010"))
+	Start(sessionData, "mysql.SaveOrder").End(nil)
+	if span := Start(other, "mysql.SaveOrder"); span != nil {
+		t.Errorf("Start() other session = %v, want nil", span)
+	}
+	trace.Finish()
+
+	if span := Start(sessionData, "mysql.GetThreadCount"); span != nil {
+		t.Errorf("Start() after Finish = %v, want nil", span)
+	}
+
+	Export(configData, sessionData)
+
+	if path != "/v1/traces" {
+		t.Errorf("Export() path = %v, want /v1/traces", path)
+	}
+	if len(body.ResourceSpans) != 1 || len(body.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Export() body = %+v", body)
+	}
+
+	spans := body.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 4 || spans[0].Name != "tick" || spans[0].ParentSpanID != "" || len(spans[0].TraceID) != 32 {
+		t.Fatalf("Export() spans = %+v", spans)
+	}
+	for _, span := range spans[1:] {
+		if span.TraceID != spans[0].TraceID || span.ParentSpanID != spans[0].SpanID || len(span.SpanID) != 16 {
+			t.Errorf("Export() span %+v not a child of %+v", span, spans[0])
+		}
+	}
+	if spans[2].Name != "exchange.BuyOrder" || spans[2].Kind != KindClient || spans[2].Status.Code != 2 || spans[2].Status.Message != "<APIError> code=-2010" {
+		t.Errorf("Export() span = %+v, want failed exchange.BuyOrder", spans[2])
+	}
+
+	path = ""
+	Export(configData, sessionData) /* Nothing pending */
+	if path != "" {
+		t.Errorf("Export() without spans posted to %v", path)
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"database/sql"
+	"sync"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
@@ -24,7 +25,355 @@ type Order struct {
 	TransactTime            int64   `json:"transactTime"`
 	ThreadID                int64
 	ThreadIDSession         int64
-	OrderIDSource           int64 /* Used for logging purposes to define source OrderID for a sale */
+	OrderIDSource           int64  /* Used for logging purposes to define source OrderID for a sale */
+	CorrelationID           string /* Correlation ID of the trade cycle of the order */
+}
+
+// RiskLimits struct define the risk-related settings hot-reloaded by the risk-config watcher
+type RiskLimits struct {
+	Stoploss                  float64
+	SellVolatilityStoploss    float64
+	SellTrailingActivation    float64
+	SellTrailingDistance      float64
+	BuyExposureMax            float64
+	BuyCorrelationMax         float64
+	BuyCorrelationExposureMax float64
+	BuySlippageMaxBps         float64
+	BuyVolatilityReturnSigma  float64
+	BuyVolatilitySpreadSigma  float64
+	BuyLossStreakCount        int
+	BuyLossStreakCooldown     int64
+	DrawdownMax               float64
+	DailyLossMax              float64
+	FiatReserve               float64
+	FiatReservePct            float64
+}
+
+// SessionSummary struct define a session as listed by the REST API
+type SessionSummary struct {
+	ThreadID        string   `json:"threadId"`
+	ThreadIDSession string   `json:"threadIdSession"`
+	Exchange        string   `json:"exchange"`
+	FiatSymbol      string   `json:"fiatSymbol"`
+	FiatFunds       float64  `json:"fiatFunds"`
+	DiffTotal       float64  `json:"diffTotal"`
+	Status          bool     `json:"status"`
+	Name            string   `json:"name"` /* Session name, empty when not named */
+	Tags            []string `json:"tags"` /* Session tags */
+}
+
+// SessionLabel struct define the human-friendly name and tags of a ThreadID
+type SessionLabel struct {
+	ThreadID string   `json:"threadId"`
+	Name     string   `json:"name"`
+	Tags     []string `json:"tags"`
+}
+
+// SessionAllocation struct define the reservation, open transactions and realized performance of a running ThreadID
+type SessionAllocation struct {
+	ThreadID    string
+	Symbol      string  /* Symbol of the last order, empty before the first order */
+	Reservation float64 /* Fiat funds reserved */
+	Amount      float64 /* Fiat amount of open transactions */
+	Profit      float64 /* Realized profit in fiat */
+	Cost        float64 /* Fiat cost of the sold transactions */
+}
+
+// EquitySnapshot struct define a portfolio valuation snapshot for the equity curve
+type EquitySnapshot struct {
+	Time   int64
+	Equity float64
+}
+
+// Backtest struct define a backtest run, its parameters and key metrics
+type Backtest struct {
+	ID             int64
+	Name           string
+	Symbol         string
+	StartTime      int64 /* Simulated period in milliseconds */
+	EndTime        int64
+	CreatedTime    int64
+	Parameters     string /* Configuration parameters as a JSON object */
+	Trades         int
+	ProfitPct      float64 /* Return of the simulated equity over the period */
+	BuyHoldPct     float64 /* Return of buying and holding the symbol over the period */
+	MaxDrawdownPct float64
+	WinRatePct     float64 /* Sales with a profit as percentage of sales */
+}
+
+// BacktestEquity struct define the simulated equity and buy-and-hold value of a backtest at a time
+type BacktestEquity struct {
+	Time    int64
+	Equity  float64
+	BuyHold float64
+}
+
+// PnlSnapshot struct define the realized and unrealized profit of a thread at a minute for the P&L ticker
+type PnlSnapshot struct {
+	Time       int64 /* Snapshot time in milliseconds */
+	ThreadID   string
+	Realized   float64 /* Realized profit of the thread sales */
+	Unrealized float64 /* Unrealized profit of the thread open transactions (session DiffTotal) */
+}
+
+// ThreadEvent struct define an event in the history of a thread (order, configuration change, pause, note, ...)
+type ThreadEvent struct {
+	Time    int64  /* Event time in milliseconds */
+	Kind    string /* buy, sell, config, pause, resume, note, approval, liquidation, warning or error */
+	Source  string /* User, order source or process that caused the event */
+	OrderID int64  /* Order the event applies to, 0 if none */
+	Detail  string
+}
+
+// ShadowOrder struct define a simulated order of a thread in dry-run mode, saved in the shadoworder table and never sent to the exchange
+type ShadowOrder struct {
+	ID                      int64
+	Symbol                  string
+	Side                    string
+	Price                   float64
+	Quantity                float64
+	CumulativeQuoteQuantity float64
+	OrderIDSource           int64   /* Shadow BUY sold by a shadow SELL, 0 for buys */
+	Profit                  float64 /* Simulated profit of a shadow SELL */
+	Decision                string  /* Decision tree result that led to the order */
+	TransactTime            int64   /* Milliseconds */
+}
+
+// ThreadCommand struct define a command queued for a thread by another thread (Telegram commands are received by the Master Node)
+type ThreadCommand struct {
+	ID          int64
+	ThreadID    string
+	Command     string /* pause, resume, sell or stop */
+	OrderID     int64  /* Order to sell, 0 if none */
+	Source      string /* Channel that queued the command */
+	CreatedTime int64  /* Milliseconds */
+}
+
+// ThreadCycle struct define a closed BUY/SELL cycle of a thread
+type ThreadCycle struct {
+	BuyOrderID   int64
+	SellOrderID  int64
+	Quantity     float64
+	BuyPrice     float64
+	SellPrice    float64
+	BuyQuote     float64
+	SellQuote    float64
+	TransactTime int64 /* Sale time */
+}
+
+// ConfigVersion struct define a version of a thread configuration saved by the config editor
+type ConfigVersion struct {
+	Version  int
+	Username string
+	Time     int64
+	Changes  string
+}
+
+// Preference struct define the UI preferences of a dashboard user
+type Preference struct {
+	Theme           string   /* light or dark */
+	RefreshInterval int      /* Seconds between live data updates */
+	Currency        string   /* Currency code used to display amounts */
+	CurrencySymbol  string   /* Currency symbol used to display amounts */
+	Columns         []string /* Visible open transaction columns */
+	Language        string   /* Saved UI language, empty for the browser language */
+	Locale          string   /* Locale used to display the UI, resolved from Language or the browser language */
+}
+
+// Balance struct define the balance of an asset in an exchange account
+type Balance struct {
+	Account string /* Exchange account, i.e. binance or binance-testnet */
+	Asset   string
+	Free    float64
+	Locked  float64 /* Locked in open orders */
+	Time    int64   /* Snapshot time in milliseconds */
+}
+
+// Position struct define the open transactions of a thread
+type Position struct {
+	ThreadID   string
+	Exchange   string
+	Symbol     string
+	FiatSymbol string
+	Quantity   float64
+	Cost       float64 /* Purchase cost in FiatSymbol */
+}
+
+// ThreadTransaction struct define an open transaction of any thread
+type ThreadTransaction struct {
+	ThreadID         string
+	ThreadIDSession  string
+	OrderID          int64
+	Symbol           string
+	ExecutedQuantity float64
+}
+
+// Note struct define an operator note attached to an order or a session
+type Note struct {
+	ID       int64
+	ThreadID string
+	OrderID  int64 /* 0 for a session note */
+	Username string
+	Time     int64
+	Tags     []string
+	Text     string
+	Symbol   string /* Order symbol, empty for a session note */
+	Side     string
+	Price    float64
+	Quantity float64
+}
+
+// Trade struct define a filled order exported in reports
+type Trade struct {
+	TransactTime  int64
+	ThreadID      string
+	Symbol        string
+	Side          string
+	Status        string /* Order status, only loaded by the order history */
+	OrderID       int64
+	OrderIDSource int64 /* Source BUY OrderID of a SELL */
+	Price         float64
+	Quantity      float64
+	Quote         float64 /* Cumulative quote quantity */
+	Source        string  /* Order source, i.e. bot or operator */
+	Profit        float64 /* Realized profit of a SELL */
+}
+
+// OrderFilter struct define the filters, sort and page of the order history, empty filters match all orders
+type OrderFilter struct {
+	ThreadID   string
+	Symbol     string
+	Side       string
+	Status     string
+	Search     string /* Text matched in OrderID, ClientOrderId, Symbol, ThreadID and Source */
+	Sort       string /* TransactTime, ThreadID, Symbol, Side, Status, OrderID, Price, Quantity, Quote or Source */
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+// ProfitSummary struct define the realized profit of a thread or a month exported in reports
+type ProfitSummary struct {
+	Key       string /* ThreadID or month (2006-01) */
+	Symbol    string /* Thread symbol, empty for months */
+	Trades    int
+	Profit    float64
+	ProfitPct float64 /* Average profit as ratio of the buy cost */
+}
+
+// AlertRule struct define an alert on a thread metric and the channel it is routed to
+type AlertRule struct {
+	ID        int64
+	Name      string
+	ThreadID  string /* Empty for all threads */
+	Metric    string
+	Operator  string /* > or < */
+	Threshold float64
+	Channel   string /* telegram, webhook or log */
+	Target    string /* Webhook URL */
+}
+
+// Webhook struct define an outbound webhook destination and the events it subscribes to
+type Webhook struct {
+	ID      int64
+	Name    string
+	URL     string
+	Events  []string
+	Secret  string /* HMAC-SHA256 signing key */
+	Enabled bool
+}
+
+// User struct define a dashboard user
+type User struct {
+	Username     string /* Username */
+	PasswordHash string /* bcrypt password hash */
+	Role         string /* Role, i.e. viewer, trader or admin */
+	FailedLogins int    /* Consecutive failed logins */
+	LockedUntil  int64  /* Login lockout end time in milliseconds */
+	TOTPSecret   string /* Base32 TOTP secret, set while enrolling or enrolled */
+	TOTPEnabled  bool   /* Two-factor authentication enabled */
+}
+
+// AuthToken struct define a UI session or REST API token
+type AuthToken struct {
+	Username string /* Username the token was issued to */
+	Role     string /* Role of the user the token was issued to */
+	Kind     string /* Token kind, i.e. SESSION or API */
+	Expires  int64  /* Expiry time in milliseconds, 0 never expires */
+	LastSeen int64  /* Last request time in milliseconds, used for the UI session idle timeout */
+}
+
+// PendingAction struct define a manual action awaiting operator confirmation
+type PendingAction struct {
+	ID          int64   /* Pending action ID */
+	ThreadID    string  /* ThreadID the action applies to */
+	Action      string  /* Action type, i.e. SELL */
+	OrderID     int64   /* OrderID the action applies to, 0 for the most recent thread transaction */
+	Notional    float64 /* Order value in fiat at request time */
+	CreatedTime int64   /* Request time in milliseconds */
+}
+
+// QueuedNotification struct define a notification awaiting delivery retry
+type QueuedNotification struct {
+	ID          int64  /* Queued notification ID */
+	ThreadID    string /* ThreadID that sent the notification */
+	Channel     string /* Notification channel, i.e. telegram */
+	Text        string /* Rendered notification text */
+	Attempts    int    /* Delivery attempts so far */
+	NextAttempt int64  /* Next delivery attempt time in milliseconds */
+	LastError   string /* Error of the last delivery attempt */
+	CreatedTime int64  /* Queue time in milliseconds */
+}
+
+// LogRecord struct define a log entry saved to the log table
+type LogRecord struct {
+	ID        int64  `json:"id"`
+	Time      int64  `json:"time"`      /* Entry time in milliseconds */
+	Level     string `json:"level"`     /* info or debug */
+	ThreadID  string `json:"threadId"`  /* ThreadID that logged the entry, empty before the thread starts */
+	Component string `json:"component"` /* Package that logged the entry, i.e. exchange, empty when unknown */
+	Message   string `json:"message"`
+}
+
+// LogFilter struct define the filters of the log entries retrieved from the log table, empty values match all entries
+type LogFilter struct {
+	ThreadID  string
+	Level     string
+	Component string
+	Text      string /* Text contained in the message */
+	From      int64  /* Time in milliseconds, 0 for an open range */
+	To        int64
+}
+
+// AuditRecord struct define an order event of the audit table, Hash is the SHA-256 of PrevHash and Payload
+type AuditRecord struct {
+	ID       int64
+	Time     int64 /* Event time in milliseconds */
+	ThreadID string
+	Event    string /* order.create or order.update */
+	OrderID  int64
+	Payload  string /* AuditEvent JSON */
+	PrevHash string /* Hash of the previous record, 64 zeros for the first record */
+	Hash     string
+}
+
+// WsConnection struct define a websocket connection of a thread, from its connection to its disconnection
+type WsConnection struct {
+	ThreadID         string
+	Stream           string /* kline, bookticker or userdata */
+	ConnectedTime    int64  /* Connection time in milliseconds */
+	DisconnectedTime int64  /* Disconnection time in milliseconds */
+	Messages         int64  /* Messages received during the connection */
+	Reason           string /* closed, stale or stopped */
+	Error            string /* Last error of the connection, if any */
+}
+
+// AuditEvent struct define the payload of an audit table record
+type AuditEvent struct {
+	Event    string `json:"event"`
+	Time     int64  `json:"time"`
+	ThreadID string `json:"threadId"`
+	Order    Order  `json:"order"`
 }
 
 // Kline struct define a kline
@@ -63,54 +412,103 @@ type PriceChangeStats struct {
 	LowPrice  string `json:"lowPrice"`
 }
 
+// OrderBook define a local snapshot of the exchange order book
+type OrderBook struct {
+	Bids      []OrderBookEntry /* Bid side entries sorted by best price */
+	Asks      []OrderBookEntry /* Ask side entries sorted by best price */
+	TimeStamp time.Time        /* Time of last retrieved order book */
+}
+
+// OrderBookEntry define a price level in the order book
+type OrderBookEntry struct {
+	Price    float64
+	Quantity float64
+}
+
 // ExchangeInfo define exchange order size
 type ExchangeInfo struct {
-	MaxQuantity string `json:"maxQty"`
-	MinQuantity string `json:"minQty"`
-	StepSize    string `json:"stepSize"`
+	MaxQuantity  string `json:"maxQty"`
+	MinQuantity  string `json:"minQty"`
+	StepSize     string `json:"stepSize"`
+	MinNotional  string `json:"minNotional"`
+	MaxNumOrders string `json:"maxNumOrders"`
 }
 
 // Session struct define session elements
 type Session struct {
-	ThreadID                string /* Unique session ID for the thread */
-	ThreadIDSession         string
-	ThreadCount             int
-	SellTransactionCount    float64   /* Number of SELL transactions in the last 60 minutes */
-	Symbol                  string    /* Symbol */
-	SymbolFunds             float64   /* Available crypto funds in exchange */
-	SymbolFiat              string    /* Fiat symbol */
-	SymbolFiatFunds         float64   /* Available fiat funds in exchange */
-	LastBuyTransactTime     time.Time /* This session variable stores the time of the last buy */
-	LastSellCanceledTime    time.Time /* This session variable stores the time of the cancelled sell */
-	LastWsKlineTime         time.Time /* This session variable stores the time of the last WsKline used for status check */
-	LastWsBookTickerTime    time.Time /* This session variable stores the time of the last WsBookTicker used for status check */
-	LastWsUserDataServeTime time.Time /* This session variable stores the time of the last WsUserDataServe used for status check */
-	ConfigTemplate          int
-	ForceBuy                bool                     /* This boolean when True force BUY transaction */
-	ForceSell               bool                     /* This boolean when True force SELL transaction */
-	ForceSellOrderID        int64                    /* This variable stores the OrderID of ForceSell */
-	ListenKey               string                   /* Listen key for user stream service */
-	MasterNode              bool                     /* This boolean is true when Master Node is elected */
-	TgBotAPI                *tgbotapi.BotAPI         /* This variable holds Telegram session bot */
-	TgBotAPIChatID          int64                    /* This variable holds Telegram chat ID */
-	Db                      *sql.DB                  /* mySQL database connection */
-	Clients                 Client                   /* Binance client connection */
-	KlineData               []KlineData              /* kline data format for go-echart plotter */
-	StopWs                  bool                     /* Control when to stop Ws Channels */
-	Busy                    bool                     /* Control wether buy/selling to allow graceful session exit */
-	MinQuantity             float64                  /* Defines the minimum quantity allowed by exchange */
-	MaxQuantity             float64                  /* Defines the maximum quantity allowed by exchange */
-	StepSize                float64                  /* Defines the intervals that a quantity can be increased/decreased by exchange */
-	Latency                 int64                    /* Latency between the exchange and client */
-	Status                  bool                     /* System status Good (false) or Bad (true) */
-	RateCounter             *ratecounter.RateCounter /* Average Number of transactions per second proccessed by WsBookTicker */
-	BuyDecisionTreeResult   string                   /* Hold BuyDecisionTree result for web UI */
-	SellDecisionTreeResult  string                   /* Hold SellDecisionTree result for web UI */
-	QuantityOffsetFlag      bool                     /* This flag is true when the quantity is offset */
-	DiffTotal               float64                  /* This variable holds the difference between the total funds and the total funds in the last session */
-	Global                  *Global
-	Admin                   bool   /* This flag is true when the admin page is selected */
-	Port                    string /* This variable holds the port number for the web server */
+	ThreadID                  string /* Unique session ID for the thread */
+	ThreadIDSession           string
+	CorrelationID             string /* Correlation ID of the trade cycle being processed, empty between trade decisions */
+	CycleState                string /* State of the trade cycle state machine, persisted in the Session table */
+	CycleOrderID              int64  /* Order in flight of the trade cycle, 0 when none or not acknowledged by the exchange */
+	CycleOrderIDSource        int64  /* Open transaction sold by the order in flight */
+	ThreadCount               int
+	SellTransactionCount      float64   /* Number of SELL transactions in the last 60 minutes */
+	Symbol                    string    /* Symbol */
+	SymbolFunds               float64   /* Available crypto funds in exchange */
+	SymbolFiat                string    /* Fiat symbol */
+	SymbolFiatFunds           float64   /* Available fiat funds in exchange */
+	LastBuyTransactTime       time.Time /* This session variable stores the time of the last buy */
+	LastSellCanceledTime      time.Time /* This session variable stores the time of the cancelled sell */
+	RateLimitedUntil          time.Time /* Trade decisions paused until this time after the exchange rate limited an order */
+	LastWsKlineTime           time.Time /* This session variable stores the time of the last WsKline used for status check */
+	LastWsBookTickerTime      time.Time /* This session variable stores the time of the last WsBookTicker used for status check */
+	LastWsUserDataServeTime   time.Time /* This session variable stores the time of the last WsUserDataServe used for status check */
+	ConfigTemplate            int
+	ForceBuy                  bool                     /* This boolean when True force BUY transaction */
+	ForceSell                 bool                     /* This boolean when True force SELL transaction */
+	ForceSellOrderID          int64                    /* This variable stores the OrderID of ForceSell */
+	ListenKey                 string                   /* Listen key for user stream service */
+	MasterNode                bool                     /* This boolean is true when Master Node is elected */
+	TgBotAPI                  *tgbotapi.BotAPI         /* This variable holds Telegram session bot */
+	TgBotAPIChatID            int64                    /* This variable holds Telegram chat ID */
+	Db                        *sql.DB                  /* mySQL database connection */
+	Clients                   Client                   /* Binance client connection */
+	KlineData                 []KlineData              /* kline data format for go-echart plotter */
+	StopWs                    bool                     /* Control when to stop Ws Channels */
+	Busy                      bool                     /* Control wether buy/selling to allow graceful session exit */
+	MinQuantity               float64                  /* Defines the minimum quantity allowed by exchange */
+	MaxQuantity               float64                  /* Defines the maximum quantity allowed by exchange */
+	StepSize                  float64                  /* Defines the intervals that a quantity can be increased/decreased by exchange */
+	MinNotional               float64                  /* Defines the minimum order value in fiat allowed by exchange */
+	MaxNumOrders              int                      /* Defines the maximum number of open orders allowed by exchange, 0 when not defined */
+	Latency                   int64                    /* Latency between the exchange and client */
+	LastExchangeTime          time.Time                /* Time of the last successful exchange latency check, used for health check */
+	Status                    bool                     /* System status Good (false) or Bad (true) */
+	RateCounter               *ratecounter.RateCounter /* Average Number of transactions per second proccessed by WsBookTicker */
+	BuyDecisionTreeResult     string                   /* Hold BuyDecisionTree result for web UI */
+	SellDecisionTreeResult    string                   /* Hold SellDecisionTree result for web UI */
+	QuantityOffsetFlag        bool                     /* This flag is true when the quantity is offset */
+	DiffTotal                 float64                  /* This variable holds the difference between the total funds and the total funds in the last session */
+	Global                    *Global
+	Port                      string         /* This variable holds the port number for the web server */
+	CooldownStart             time.Time      /* Start of the current loss streak cooldown */
+	CooldownUntil             time.Time      /* New entries are paused until this time after a loss streak */
+	TrailingHigh              float64        /* Aggregate trailing stop high-water mark, 0 when not active */
+	TrailingStopTriggered     bool           /* Aggregate trailing stop triggered, sell all thread transactions */
+	StopPrice                 float64        /* Absolute price that triggers the sale of all thread transactions, 0 disables */
+	Paused                    bool           /* Thread paused by an operator, no new buys while exits are still managed */
+	Stopping                  bool           /* Thread shutting down gracefully on a signal, no new buys */
+	AlertsFired               map[int64]bool /* Alert rules fired and not yet cleared, by rule ID */
+	Events                    []Event        /* High-impact economic events loaded from calendar feed */
+	CorrelatedExposure        float64        /* Open exposure across threads for symbols correlated with Symbol */
+	ThreadExposure            float64        /* Open exposure in fiat for ThreadID */
+	VolatilityHalt            bool           /* Volatility circuit breaker tripped, new buys suspended */
+	VolatilityTripTime        time.Time      /* Time of the last abnormal volatility observation */
+	Reservation               float64        /* Fiat funds reserved for ThreadID by the funds allocator, 0 when not reserved */
+	ReservationAvailable      float64        /* Fiat funds still available to ThreadID under the funds allocator */
+	BuyScore                  float64        /* Weighted indicator score of the last buy decision */
+	LiquidationCode           string         /* One-time emergency liquidation confirmation code */
+	LiquidationCodeTime       time.Time      /* Time the emergency liquidation confirmation code was issued */
+	LiquidationOrdersCanceled bool           /* Open orders cancelled for the active emergency liquidation */
+	LiquidationReported       bool           /* Report saved for the active emergency liquidation */
+	LiquidationSwept          bool           /* Holdings no running thread sells sold by the master node for the active emergency liquidation */
+	DbDownTime                time.Time      /* Time the database became unreachable, zero while reachable */
+	SymbolDenied              bool           /* Symbol denied by the symbol allow/deny list, new buys suspended */
+	Commission                float64        /* Account commission rate per order as ratio, 0 until loaded from the exchange */
+	FiatReserve               float64        /* Fiat reserve floor never spent by any thread */
+	RiskLimits                *RiskLimits    /* Risk limits applied at the start of every trading cycle, nil until loaded by the risk-config watcher */
+	RiskLimitsMutex           sync.Mutex     /* Guards RiskLimits between the risk-config watcher and trading cycles */
 }
 
 // Global (Session.Global) struct store semi-persistent values to help offload mySQL queries load
@@ -123,6 +521,21 @@ type Global struct {
 	ThreadCount       int     /* Thread count */
 	ThreadAmount      float64 /* Thread cost amount */
 	DiffTotal         float64 /* /* This variable holds the difference between purchase price and current value across all sessions */
+	Equity            float64 /* Equity across all threads */
+	EquityPeak        float64 /* Highest equity across all threads since last drawdown reset */
+	Drawdown          float64 /* Drawdown from EquityPeak as ratio */
+	DailyProfit       float64 /* Realized profit since the start of the UTC day across all threads */
+	DailyLossHalt     bool    /* Daily loss limit reached, new buys halted until next UTC day */
+	DrawdownHalt      bool    /* Drawdown kill switch active, new buys halted until operator re-enable */
+	Liquidate         bool    /* Emergency liquidation active, all threads cancel open orders and sell all transactions */
+	LiquidateTime     int64   /* Emergency liquidation confirmation time in milliseconds */
+}
+
+// Event struct define a high-impact economic event (i.e. CPI, FOMC)
+type Event struct {
+	Title   string    /* Event title */
+	Country string    /* Country or currency affected */
+	Time    time.Time /* Event time */
 }
 
 // Client struct for client libraries
@@ -160,11 +573,30 @@ type Market struct {
 	Series                    *techan.TimeSeries /* kline data format for technical analysis */
 	Ma7                       float64            /* Simple Moving Average for 7 periods */
 	Ma14                      float64            /* Simple Moving Average for 14 periods */
+	OrderBook                 *OrderBook         /* Local order book snapshot */
+	OrderBookBidDepth         float64            /* Bid side volume within configured basis points of mid */
+	OrderBookAskDepth         float64            /* Ask side volume within configured basis points of mid */
+	OrderBookImbalance        float64            /* Bid/Ask volume imbalance from -1 (asks only) to 1 (bids only) */
+	Spread                    float64            /* Best ask/bid spread in basis points of mid price */
+	SpreadHistory             []float64          /* Spread samples used as volatility circuit breaker baseline */
+	Stale                     bool               /* Market data not updated within configData.MarketDataStaleTimeout */
 }
 
 // Config struct for configuration
 type Config struct {
-	ThreadID                               string /* For index.html population */
+	ThreadID                               string        /* For index.html population */
+	LiquidationCode                        string        /* For admin.html population */
+	SymbolAllow                            string        /* For admin.html population */
+	SymbolDeny                             string        /* For admin.html population */
+	PendingAction                          PendingAction /* For index.html population */
+	Username                               string        /* For index.html population */
+	Role                                   string        /* For index.html population */
+	CanTrade                               bool          /* For index.html population */
+	CanAdmin                               bool          /* For index.html population */
+	Admin                                  bool          /* This flag is true when the admin page is selected */
+	APIToken                               string        /* For admin.html population */
+	LoginMessage                           string        /* For login.html population */
+	LoginSetup                             bool          /* For login.html population */
 	Buy24hsHighpriceEntry                  float64
 	BuyDirectionDown                       int
 	BuyDirectionUp                         int
@@ -176,38 +608,155 @@ type Config struct {
 	BuyRepeatThresholdDownSecondStartCount int
 	BuyRepeatThresholdUp                   float64
 	BuyRsi7Entry                           float64
-	BuyWait                                int64 /* Wait time between BUY transactions in seconds */
+	BuyWait                                int64   /* Wait time between BUY transactions in seconds */
+	BuyOrderBookDepthBps                   float64 /* Order book depth window around mid price in basis points */
+	BuyOrderBookAskBidRatio                float64 /* Suppress buys when ask depth exceeds bid depth by this ratio (0 to disable) */
+	BuyVolatilityReturnSigma               float64 /* Suspend buys when the 1 minute return deviates more than this in sigma (0 to disable) */
+	BuyVolatilitySpreadSigma               float64 /* Suspend buys when the spread widens more than this in sigma (0 to disable) */
+	BuyVolatilityResume                    int64   /* Minutes of normal conditions before the volatility circuit breaker resets */
+	MarketDataStaleTimeout                 int64   /* Seconds without market data updates after which trading is blocked and websockets reconnect */
+	BuySlippageMaxBps                      float64 /* Abort market buys when expected slippage exceeds this in basis points (0 to disable) */
+	BuyLossStreakCount                     int     /* Number of consecutive losing cycles that trigger a cooldown (0 to disable) */
+	BuyLossStreakCooldown                  int64   /* Cooldown duration in minutes after a loss streak */
+	BuyLossStreakTrendExit                 bool    /* End cooldown early when MA7 crosses above MA14 */
+	BuyEventBlackout                       int64   /* Suppress buys this many minutes before and after high-impact events (0 to disable) */
+	BuyCorrelationMax                      float64 /* Correlation above which symbols are considered the same cluster (0 to disable) */
+	BuyCorrelationExposureMax              float64 /* Maximum fiat exposure for the correlated cluster */
+	BuyCorrelationWindow                   int     /* Number of hourly candles used for rolling correlation */
+	BuyExposureMax                         float64 /* Maximum open exposure in fiat per thread, 0 disables */
+	BuySizingMode                          string  /* Position sizing mode: fixed, fraction, volatility or kelly */
+	BuySizingFraction                      float64 /* Fraction of equity per position in fraction mode */
+	BuySizingVolatilityTarget              float64 /* Target volatility as fraction of equity in volatility mode */
+	BuySizingKellyFraction                 float64 /* Multiplier applied to the Kelly fraction in kelly mode */
+	BuyScoreThreshold                      float64 /* Weighted indicator score required to buy, 0 disables scoring */
+	BuyScoreRsi7                           float64 /* Score when RSI7 lower than BuyRsi7Entry */
+	BuyScoreMacd                           float64 /* Score when MACD positive */
+	BuyScoreDirection                      float64 /* Score when market direction reached */
+	BuyScoreMa                             float64 /* Score when MA7 above MA14 */
+	BuyScoreOrderBook                      float64 /* Score when order book bid depth exceeds ask depth */
 	ExchangeComission                      float64
 	ProfitMin                              float64
+	ProfitNetMin                           float64 /* Minimum net profit as ratio after round-trip commissions for profit sales */
 	SellWaitBeforeCancel                   int64   /* Wait time before cancelling a sale in seconds */
 	SellWaitAfterCancel                    int64   /* Wait time before selling after a cancel in seconds */
 	SellToCover                            bool    /* Define if will sell to cover low funds */
 	SellHoldOnRSI3                         float64 /* Hold sale if RSI3 above defined threshold */
 	Stoploss                               float64 /* Loss as ratio that should trigger a sale */
+	SellVolatilityStoploss                 float64 /* Stoploss ratio used while the volatility circuit breaker is tripped (0 to disable) */
+	SellTrailingActivation                 float64 /* Aggregate profit as ratio over thread average entry that activates the trailing stop, 0 disables */
+	SellTrailingDistance                   float64 /* Pullback as ratio from the high-water mark that sells all thread transactions */
+	SellConfirmNotional                    float64 /* Manual sales of orders above this notional in fiat require confirmation, 0 disables */
 	SymbolFiat                             string
 	SymbolFiatStash                        float64
 	Symbol                                 string
 	TimeEnforce                            bool
 	TimeStart                              string
 	TimeStop                               string
+	TimeUTC                                bool   /* Interpret TimeStart and TimeStop as UTC instead of local time */
+	TimeSkipWeekends                       bool   /* Do not open new positions on Saturdays and Sundays */
+	ScheduleStart                          string /* Cron expression that starts the thread stopped by its schedule */
+	ScheduleStop                           string /* Cron expression that stops the thread */
+	SchedulePause                          string /* Cron expression that pauses new buys */
+	ScheduleResume                         string /* Cron expression that resumes new buys */
 	Debug                                  bool
 	Exit                                   bool
 	DryRun                                 bool        /* Dry Run mode */
+	Rebalance                              bool        /* Rebalance mode: maintain target weights across a basket of assets */
+	RebalanceWeights                       string      /* Target weights as ASSET:weight separated by commas */
+	RebalanceBand                          float64     /* Drift from target weight that triggers a rebalance */
 	NewSession                             bool        /* Force a new session instead of resume */
 	ConfigTemplateList                     interface{} /* List of configuration templates available in ./config folder */
+	PresetList                             []string    /* Strategy preset names for html population */
+	Preference                             Preference  /* UI preferences of the logged in user */
 	ExchangeName                           string      /* Exchange name */
 	TestNet                                bool        /* Use Exchange TestNet */
 	HTMLSnippet                            interface{} /* Store kline plotter graph for html output */
+	EquitySnippet                          interface{} /* Store equity curve and drawdown graph for html output */
+	EquityRange                            string      /* Equity curve time range (24h, 7d, 30d or all) */
 	ConfigGlobal                           *ConfigGlobal
 }
 
 // ConfigGlobal struct for global configuration
 type ConfigGlobal struct {
-	Apikey           string /* Exchange API Key */
-	Secretkey        string /* Exchange Secret Key */
-	ApikeyTestNet    string /* API key for exchange test network, used with launch.json */
-	SecretkeyTestNet string /* Secret key for exchange test network, used with launch.json */
-	TgBotApikey      string /* Telegram bot API key */
+	Apikey              string  /* Exchange API Key */
+	Secretkey           string  /* Exchange Secret Key */
+	ApikeyTestNet       string  /* API key for exchange test network, used with launch.json */
+	SecretkeyTestNet    string  /* Secret key for exchange test network, used with launch.json */
+	TgBotApikey         string  /* Telegram bot API key */
+	TgChatIDs           string  /* Comma separated Telegram chat IDs allowed to send bot commands */
+	DiscordWebhookURL   string  /* Discord webhook URL for notifications */
+	DiscordBotToken     string  /* Discord bot token, used with DiscordChannelID when DiscordWebhookURL is empty */
+	DiscordChannelID    string  /* Discord channel ID for bot notifications */
+	DiscordEvents       string  /* Comma separated Discord event types (order, profit, error) */
+	SlackWebhookURL     string  /* Slack incoming webhook URL for trade notifications */
+	SlackBotToken       string  /* Slack bot token, used with SlackChannel when SlackWebhookURL is empty */
+	SlackChannel        string  /* Slack channel for bot notifications */
+	MatrixServerURL     string  /* Matrix homeserver URL for notifications */
+	MatrixAccessToken   string  /* Matrix access token of the bot account */
+	MatrixRoomID        string  /* Matrix room ID the notifications are posted to */
+	SMTPHost            string  /* SMTP server host for email notifications */
+	SMTPPort            int     /* SMTP server port with STARTTLS (587 when 0) */
+	SMTPUsername        string  /* SMTP username, no authentication when empty */
+	SMTPPassword        string  /* SMTP password */
+	EmailFrom           string  /* Email notifications sender address */
+	EmailTo             string  /* Comma separated email notifications recipient addresses */
+	EmailDigestTime     string  /* Daily digest email time (HH:MM, local time), empty disables the digest */
+	PushoverToken       string  /* Pushover application API token for critical alerts */
+	PushoverUser        string  /* Pushover user or group key */
+	NtfyURL             string  /* ntfy server URL (https://ntfy.sh when empty) */
+	NtfyTopic           string  /* ntfy topic for critical alerts */
+	NtfyToken           string  /* ntfy access token for protected topics */
+	TwilioAccountSID    string  /* Twilio account SID for critical SMS alerts */
+	TwilioAuthToken     string  /* Twilio auth token */
+	TwilioFrom          string  /* Twilio sender phone number */
+	SMSTo               string  /* Comma separated critical SMS alert recipient phone numbers */
+	SMSRateMax          int     /* SMS alerts sent per hour across all alerts, further alerts are dropped */
+	DbDownMinutes       int     /* Minutes the database is unreachable before a critical alert, 0 disables */
+	NotifyRateMax       int     /* Notifications per minute and channel before batching into a digest, 0 disables */
+	ErrorBurstMax       int     /* Errors of a category (exchange, database, websocket) within ErrorBurstWindow that send an alert, 0 disables */
+	ErrorBurstWindow    int     /* Error burst sliding window in minutes (5 when 0) */
+	SummarySchedules    string  /* Performance summary schedules, one per line: <cron expression> <channels> */
+	NotifyRoutes        string  /* Notification routing rules, one per line: <event> <channel> [<severity>] [<ThreadID>] */
+	OtlpEndpoint        string  /* OpenTelemetry OTLP/HTTP collector endpoint for trade pipeline traces, empty disables */
+	SentryDsn           string  /* Sentry DSN receiving the panics and critical errors, empty disables */
+	ExchangeSlowMs      int     /* Exchange REST call latency in milliseconds logged as slow, 0 disables */
+	ExchangeConcurrency int     /* Exchange REST calls in progress across the threads of the process, 0 disables the limit */
+	WatchdogInterval    int     /* Minutes between the watchdog samples of the goroutines and heap, 0 disables */
+	WatchdogSamples     int     /* Consecutive growing watchdog samples that send a warning (6 when 0) */
+	WatchdogGoroutines  int     /* Goroutines above which a growing trend sends a warning, 0 disables */
+	WatchdogHeapMB      int     /* Heap in MB above which a growing trend sends a warning, 0 disables */
+	WatchdogRestart     bool    /* Restart the process resuming the thread after a watchdog warning */
+	SupervisorTimeout   int     /* Seconds without a book ticker processed after which the supervisor restarts a stalled thread, 0 disables */
+	SupervisorBackoff   int     /* Seconds before the supervisor restarts a failed thread, doubled on each consecutive failure (10 when 0) */
+	SupervisorMaxDelay  int     /* Maximum seconds before the supervisor restarts a failed thread (600 when 0) */
+	LogLevel            string  /* Log level: debug, info or off */
+	LogLevelExchange    string  /* Log level of the exchange subsystem, the global log level when empty */
+	LogLevelMysql       string  /* Log level of the mysql subsystem, the global log level when empty */
+	LogLevelThreads     string  /* Log level of the threads subsystem, the global log level when empty */
+	LogLevelAlgorithms  string  /* Log level of the algorithms subsystem, the global log level when empty */
+	LogMaxSize          int     /* Log file size in MB that rotates the log file, 0 disables */
+	LogRotateHours      int     /* Hours between log file rotations aligned to local midnight, 0 disables */
+	LogMaxBackups       int     /* Rotated log files kept per log file, 0 keeps all */
+	LogMaxDays          int     /* Days rotated log files are kept, 0 keeps all */
+	LogCompress         bool    /* Compress rotated log files with gzip */
+	LogSyslog           string  /* Syslog target: local, unix:///path, udp://host:port or tcp://host:port, empty disables */
+	LogSyslogFacility   string  /* Syslog facility, i.e. daemon or local0, daemon when empty */
+	LogSyslogTag        string  /* Syslog tag, cryptopump when empty */
+	LogSyslogOnly       bool    /* Write the log entries to syslog only, not to the log files */
+	LogDatabase         bool    /* Save the log entries to the log table */
+	LogDatabaseDays     int     /* Days log entries are kept in the log table, 0 keeps all */
+	EventFeedURL        string  /* High-impact economic events calendar feed URL */
+	DrawdownMax         float64 /* Drawdown from equity peak as ratio that halts new buys across all threads, 0 disables */
+	DailyLossMax        float64 /* Realized loss in fiat since the start of the UTC day that halts new buys across all threads, 0 disables */
+	DrawdownLiquidate   bool    /* Sell all open transactions when the drawdown kill switch is triggered */
+	FiatReserve         float64 /* Fiat amount never spent across all threads, 0 disables */
+	FiatReservePct      float64 /* Fiat reserve as ratio of fiat funds plus open transactions across all threads, 0 disables */
+	AllocatorMode       string  /* Capital allocator mode: weights or performance, empty disables */
+	AllocatorWeights    string  /* Allocator weights as SYMBOL:weight or ThreadID:weight list, unlisted threads weigh 1 */
+	AllocatorInterval   int     /* Minutes between allocator rebalances */
+	AllocatorWindow     int     /* Days of realized performance weighing the threads in performance mode */
+	SessionIdleTimeout  int     /* Minutes without requests after which UI sessions expire, 0 disables */
+	SessionMax          int     /* Concurrent UI sessions per user, the least recently used are revoked, 0 disables */
 }
 
 // OutboundAccountPosition Struct for User Data Streams for Binance
@@ -0,0 +1,241 @@
+package watchdog
+
+/* This package implements the self-monitoring watchdog of the process. The goroutine count and the heap in use are
+sampled every WatchdogInterval minutes, and when either grows on WatchdogSamples consecutive samples beyond
+WatchdogGoroutines or WatchdogHeapMB a warning is logged and notified, as a growth that never levels off is a leak.
+With WatchdogRestart the process then restarts itself after saving the session, and the new process resumes the
+thread. */
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/diagnostics"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/messages"
+	"github.com/aleibovici/cryptopump/notify"
+	"github.com/aleibovici/cryptopump/threads"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Resources monitored */
+const (
+	Goroutines = "goroutines"
+	Heap       = "heap"
+)
+
+const defaultSamples = 6 /* Consecutive growing samples when WatchdogSamples is 0 */
+
+// Sample struct define the resources of the process at a time
+type Sample struct {
+	Goroutines int
+	Heap       uint64 /* Bytes of allocated heap objects */
+}
+
+// Leak struct define a resource that grew beyond its threshold
+type Leak struct {
+	Resource  string
+	Value     float64 /* Goroutines, or heap in MB */
+	Threshold float64
+	Growth    float64 /* Growth over the samples */
+	Samples   int
+}
+
+// Monitor struct define the growing trend of each resource
+type Monitor struct {
+	mutex   sync.Mutex
+	trends  map[string][]float64 /* Consecutive growing values of each resource, oldest first */
+	sampled time.Time            /* Time of the last sample */
+}
+
+var (
+	monitor    = &Monitor{}
+	restarting sync.Once /* The process restarts once */
+)
+
+// Check sample the resources when WatchdogInterval elapsed since the last sample, warn of the leaks and restart the
+// process when WatchdogRestart is enabled. Called every minute.
+func Check(
+	configData *types.Config,
+	sessionData *types.Session,
+	now time.Time) {
+
+	global := configData.ConfigGlobal
+	if global == nil || global.WatchdogInterval <= 0 {
+
+		return
+
+	}
+
+	if !monitor.Due(time.Duration(global.WatchdogInterval)*time.Minute, now) {
+
+		return
+
+	}
+
+	samples := global.WatchdogSamples
+	if samples <= 0 {
+		samples = defaultSamples
+	}
+
+	stats := diagnostics.Read(now)
+
+	leaks := monitor.Record(
+		Sample{Goroutines: stats.Goroutines, Heap: stats.HeapAlloc},
+		samples,
+		float64(global.WatchdogGoroutines),
+		float64(global.WatchdogHeapMB))
+
+	for _, leak := range leaks {
+
+		warn(configData, sessionData, leak)
+
+	}
+
+	if len(leaks) > 0 && global.WatchdogRestart && sessionData.ThreadID != "" {
+
+		restarting.Do(func() {
+
+			if err := (threads.Thread{}).Restart(configData, sessionData, "Watchdog restart - "+leaks[0].String()); err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   configData,
+					Market:   nil,
+					Session:  sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+			}
+
+		})
+
+	}
+
+}
+
+// Due return true when interval elapsed since the last sample at now, and record now as the sample time
+func (monitor *Monitor) Due(
+	interval time.Duration,
+	now time.Time) bool {
+
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	if !monitor.sampled.IsZero() && now.Sub(monitor.sampled) < interval {
+
+		return false
+
+	}
+
+	monitor.sampled = now
+
+	return true
+
+}
+
+// Record a sample and return the resources that grew on samples consecutive samples to above their threshold, in
+// heap MB for the heap. A threshold of 0 disables the resource. A resource warns again only after samples more
+// growing samples.
+func (monitor *Monitor) Record(
+	sample Sample,
+	samples int,
+	goroutinesMax float64,
+	heapMax float64) (leaks []Leak) {
+
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	if monitor.trends == nil {
+		monitor.trends = make(map[string][]float64)
+	}
+
+	for _, resource := range []struct {
+		name      string
+		value     float64
+		threshold float64
+	}{
+		{name: Goroutines, value: float64(sample.Goroutines), threshold: goroutinesMax},
+		{name: Heap, value: float64(sample.Heap) / (1 << 20), threshold: heapMax},
+	} {
+
+		trend := monitor.trends[resource.name]
+
+		if len(trend) > 0 && resource.value <= trend[len(trend)-1] { /* The trend ends when the value levels off */
+
+			trend = nil
+
+		}
+
+		trend = append(trend, resource.value)
+
+		if len(trend) > samples && resource.threshold > 0 && resource.value > resource.threshold {
+
+			leaks = append(leaks, Leak{
+				Resource:  resource.name,
+				Value:     resource.value,
+				Threshold: resource.threshold,
+				Growth:    resource.value - trend[0],
+				Samples:   samples,
+			})
+
+			trend = []float64{resource.value} /* Count a new trend from the value warned */
+
+		} else if len(trend) > samples {
+
+			trend = trend[1:]
+
+		}
+
+		monitor.trends[resource.name] = trend
+
+	}
+
+	return leaks
+
+}
+
+// String return the description of the leak
+func (leak Leak) String() string {
+
+	if leak.Resource == Heap {
+
+		return fmt.Sprintf("heap grew by %.1fMB on %d consecutive samples to %.1fMB, above %.0fMB", leak.Growth, leak.Samples, leak.Value, leak.Threshold)
+
+	}
+
+	return fmt.Sprintf("goroutines grew by %.0f on %d consecutive samples to %.0f, above %.0f", leak.Growth, leak.Samples, leak.Value, leak.Threshold)
+
+}
+
+/* Log and notify a leak */
+func warn(
+	configData *types.Config,
+	sessionData *types.Session,
+	leak Leak) {
+
+	logger.LogEntry{ /* Log Entry */
+		Config:   configData,
+		Market:   nil,
+		Session:  sessionData,
+		Order:    &types.Order{},
+		Message:  "Watchdog - " + leak.String(),
+		LogLevel: "DebugLevel",
+	}.Do()
+
+	notify.Notification{
+		Event:    messages.Error,
+		Severity: notify.Warning,
+		Key:      "watchdog-" + leak.Resource,
+		Title:    "Watchdog " + leak.Resource,
+		Data: messages.Data{
+			ThreadID: sessionData.ThreadID,
+			Symbol:   sessionData.Symbol,
+			Message:  leak.String(),
+		},
+	}.Send(configData, sessionData)
+
+}
@@ -0,0 +1,82 @@
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonitor_Record(t *testing.T) {
+
+	monitor := &Monitor{}
+
+	tests := []struct {
+		name       string
+		goroutines int
+		heapMB     uint64
+		want       []string
+	}{
+		{name: "first sample", goroutines: 100, heapMB: 50},
+		{name: "growth 1", goroutines: 200, heapMB: 50},
+		{name: "growth 2", goroutines: 300, heapMB: 50},
+		{name: "growth 3 above threshold", goroutines: 400, heapMB: 50, want: []string{Goroutines}},
+		{name: "growth after warning", goroutines: 500, heapMB: 60},
+		{name: "level off", goroutines: 500, heapMB: 70},
+		{name: "heap growth 3 above threshold", goroutines: 600, heapMB: 80, want: []string{Heap}},
+		{name: "growth 2 after level off", goroutines: 700, heapMB: 80},
+		{name: "growth 3 after level off", goroutines: 800, heapMB: 90, want: []string{Goroutines}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaks := monitor.Record(Sample{Goroutines: tt.goroutines, Heap: tt.heapMB << 20}, 3, 350, 75)
+			var got []string
+			for _, leak := range leaks {
+				got = append(got, leak.Resource)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("Monitor.Record() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestMonitor_Record_Growth(t *testing.T) {
+
+	monitor := &Monitor{}
+
+	var leaks []Leak
+
+	for _, goroutines := range []int{100, 150, 200, 600} {
+		leaks = monitor.Record(Sample{Goroutines: goroutines}, 3, 500, 0)
+	}
+
+	want := Leak{Resource: Goroutines, Value: 600, Threshold: 500, Growth: 500, Samples: 3}
+
+	if len(leaks) != 1 || leaks[0] != want {
+		t.Errorf("Monitor.Record() = %v, want %v", leaks, want)
+	}
+
+	if got := leaks[0].String(); got != "goroutines grew by 500 on 3 consecutive samples to 600, above 500" {
+		t.Errorf("Leak.String() = %v", got)
+	}
+
+}
+
+func TestMonitor_Due(t *testing.T) {
+
+	monitor := &Monitor{}
+	now := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+
+	if !monitor.Due(5*time.Minute, now) {
+		t.Errorf("Monitor.Due() first sample = false, want true")
+	}
+
+	if monitor.Due(5*time.Minute, now.Add(4*time.Minute)) {
+		t.Errorf("Monitor.Due() within interval = true, want false")
+	}
+
+	if !monitor.Due(5*time.Minute, now.Add(5*time.Minute)) {
+		t.Errorf("Monitor.Due() after interval = false, want true")
+	}
+
+}
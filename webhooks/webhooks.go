@@ -0,0 +1,516 @@
+package webhooks
+
+/* This package implements the outbound webhook destinations. A webhook posts the bot events it subscribes to as a
+JSON body to its URL, signed with the HMAC-SHA256 of the body using the webhook secret so the receiver can verify
+the sender: X-Cryptopump-Signature: sha256=<hex>. Events are dispatched asynchronously to the enabled webhooks
+subscribing to them, retrying failed deliveries with exponential backoff. The webhooks page adds, edits and deletes
+the destinations and sends them a test event. */
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/logger"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+// Event struct define a bot event webhooks subscribe to
+type Event struct {
+	Name        string
+	Description string
+}
+
+/* Bot event names */
+const (
+	EventOrderPlaced       = "order.placed"
+	EventOrderFilled       = "order.filled"
+	EventSessionStarted    = "session.started"
+	EventSessionStopped    = "session.stopped"
+	EventStoplossTriggered = "stoploss.triggered"
+	EventError             = "error"
+)
+
+// Events list the bot events webhooks subscribe to
+var Events = []Event{
+	{Name: EventOrderPlaced, Description: "Order sent to the exchange"},
+	{Name: EventOrderFilled, Description: "Order filled by the exchange"},
+	{Name: EventSessionStarted, Description: "Thread started"},
+	{Name: EventSessionStopped, Description: "Thread stopped"},
+	{Name: EventStoplossTriggered, Description: "Stoploss sale triggered"},
+	{Name: EventError, Description: "Error logged by a thread"},
+}
+
+// TestEvent is the event sent by the test button of the webhooks page
+const TestEvent = "webhook.test"
+
+/* Webhook errors */
+var (
+	ErrInvalidName   = errors.New("Name must have 1 to 64 characters")
+	ErrInvalidURL    = errors.New("URL must be an http or https URL of up to 255 characters")
+	ErrInvalidEvents = errors.New("Select at least one event")
+	ErrInvalidSecret = errors.New("Secret must have 16 to 64 characters")
+	ErrNotFound      = errors.New("Webhook not found")
+)
+
+var validName = regexp.MustCompile(`^.{1,64}$`)
+
+/* Wait before each retry of a failed delivery, the delivery is attempted len(backoff)+1 times */
+var backoff = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+
+var pending sync.WaitGroup /* Deliveries in progress */
+
+// Payload struct define the JSON body posted to webhooks
+type Payload struct {
+	Event    string      `json:"event"`
+	Time     int64       `json:"time"` /* Milliseconds */
+	ThreadID string      `json:"threadId"`
+	Data     interface{} `json:"data"`
+}
+
+// Order struct define the data of order.placed, order.filled and stoploss.triggered events
+type Order struct {
+	OrderID  int64   `json:"orderId"`
+	Side     string  `json:"side"`
+	Symbol   string  `json:"symbol"`
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity,omitempty"`
+	Status   string  `json:"status,omitempty"`
+	Reason   string  `json:"reason,omitempty"` /* stoploss.triggered reason */
+}
+
+// Session struct define the data of session.started and session.stopped events
+type Session struct {
+	Symbol  string `json:"symbol"`
+	Port    string `json:"port"`
+	Resumed bool   `json:"resumed,omitempty"`
+	Reason  string `json:"reason,omitempty"` /* Error that stopped the thread */
+}
+
+// Error struct define the data of error events
+type Error struct {
+	Symbol  string `json:"symbol"`
+	Message string `json:"message"`
+}
+
+/* Error returned when a webhook responds with a status other than 2xx */
+type statusError struct {
+	name   string
+	status string
+	code   int
+}
+
+func (err statusError) Error() string {
+
+	return "Webhook " + err.name + " returned " + err.status
+
+}
+
+// Page struct define the webhooks page (webhooks.html)
+type Page struct {
+	Webhooks []types.Webhook
+	Events   []Event
+	Edit     types.Webhook /* Webhook in the form, ID 0 for a new webhook */
+	CanAdmin bool
+	Message  string
+	Theme    string /* UI theme of the logged in user */
+}
+
+// LoadPage load the webhooks page with the webhook id in the form, 0 for a new webhook
+func LoadPage(
+	sessionData *types.Session,
+	id int64,
+	message string) (page Page) {
+
+	var err error
+
+	page.Events = Events
+	page.Edit = types.Webhook{Enabled: true}
+	page.Message = message
+
+	if page.Webhooks, err = mysql.GetWebhooks(sessionData); err != nil && page.Message == "" {
+
+		page.Message = err.Error()
+
+	}
+
+	if webhook, ok := find(page.Webhooks, id); ok {
+
+		page.Edit = webhook
+
+	}
+
+	return page
+
+}
+
+// Subscribed return true when the webhook in the form subscribes to event
+func (page Page) Subscribed(event string) bool {
+
+	return slices.Contains(page.Edit.Events, event)
+
+}
+
+// Parse validate a webhook entered in the webhooks page. A new webhook without secret gets a random secret, an
+// edited webhook without secret keeps its secret.
+func Parse(
+	id int64,
+	name string,
+	target string,
+	events []string,
+	secret string,
+	enabled bool) (webhook types.Webhook, err error) {
+
+	webhook = types.Webhook{
+		ID:      id,
+		Name:    strings.TrimSpace(name),
+		URL:     strings.TrimSpace(target),
+		Secret:  strings.TrimSpace(secret),
+		Enabled: enabled,
+	}
+
+	if !validName.MatchString(webhook.Name) {
+
+		return webhook, ErrInvalidName
+
+	}
+
+	if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(webhook.URL) > 255 {
+
+		return webhook, ErrInvalidURL
+
+	}
+
+	for _, event := range Events { /* Keep the order of Events and drop unknown events */
+
+		if slices.Contains(events, event.Name) {
+
+			webhook.Events = append(webhook.Events, event.Name)
+
+		}
+
+	}
+
+	if len(webhook.Events) == 0 {
+
+		return webhook, ErrInvalidEvents
+
+	}
+
+	if webhook.Secret != "" && (len(webhook.Secret) < 16 || len(webhook.Secret) > 64) {
+
+		return webhook, ErrInvalidSecret
+
+	}
+
+	if webhook.Secret == "" && webhook.ID == 0 {
+
+		if webhook.Secret, err = NewSecret(); err != nil {
+
+			return webhook, err
+
+		}
+
+	}
+
+	return webhook, nil
+
+}
+
+// NewSecret return a random webhook secret of 64 hex characters
+func NewSecret() (string, error) {
+
+	key := make([]byte, 32)
+
+	if _, err := rand.Read(key); err != nil {
+
+		return "", err
+
+	}
+
+	return hex.EncodeToString(key), nil
+
+}
+
+// Sign return the X-Cryptopump-Signature header value of body, sha256= followed by the hex HMAC-SHA256 of body
+func Sign(
+	secret string,
+	body []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+}
+
+// Post the payload as signed JSON to the webhook URL
+func Post(
+	webhook types.Webhook,
+	payload Payload) (err error) {
+
+	var body []byte
+	var request *http.Request
+	var response *http.Response
+
+	if body, err = json.Marshal(payload); err != nil {
+
+		return err
+
+	}
+
+	if request, err = http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body)); err != nil {
+
+		return err
+
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Cryptopump-Event", payload.Event)
+	request.Header.Set("X-Cryptopump-Signature", Sign(webhook.Secret, body))
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	if response, err = client.Do(request); err != nil {
+
+		return err
+
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+
+		return statusError{name: webhook.Name, status: response.Status, code: response.StatusCode}
+
+	}
+
+	return nil
+
+}
+
+// Dispatch post event with data to the enabled webhooks subscribing to event. Deliveries are asynchronous and
+// retried with backoff, failed deliveries are logged.
+func Dispatch(
+	sessionData *types.Session,
+	event string,
+	data interface{}) {
+
+	if sessionData.Db == nil {
+
+		return
+
+	}
+
+	webhooks, err := mysql.GetWebhooks(sessionData)
+	if err != nil { /* Errors are logged by mysql */
+
+		return
+
+	}
+
+	payload := Payload{
+		Event:    event,
+		Time:     time.Now().UnixNano() / int64(time.Millisecond),
+		ThreadID: sessionData.ThreadID,
+		Data:     data,
+	}
+
+	for _, webhook := range webhooks {
+
+		if !webhook.Enabled || !slices.Contains(webhook.Events, event) {
+			continue
+		}
+
+		pending.Add(1)
+
+		go func(webhook types.Webhook) {
+
+			defer pending.Done()
+
+			if err := deliver(webhook, payload); err != nil {
+
+				logger.LogEntry{ /* Log Entry */
+					Config:   nil,
+					Market:   nil,
+					Session:  sessionData,
+					Order:    &types.Order{},
+					Message:  functions.GetFunctionName() + " - " + err.Error(),
+					LogLevel: "DebugLevel",
+				}.Do()
+
+			}
+
+		}(webhook)
+
+	}
+
+}
+
+// Handle dispatch the webhook event of the events published on the event bus
+func Handle(event events.Event) {
+
+	if name, data, ok := NewEvent(event); ok {
+
+		Dispatch(event.Session, name, data)
+
+	}
+
+}
+
+// NewEvent return the webhook event name and data of event, ok is false for the events webhooks can't subscribe to
+func NewEvent(event events.Event) (name string, data interface{}, ok bool) {
+
+	switch d := event.Data.(type) {
+	case events.Order:
+
+		switch event.Topic {
+		case events.OrderPlaced:
+			name = EventOrderPlaced
+		case events.OrderFilled:
+			name = EventOrderFilled
+		default:
+			return "", nil, false
+		}
+
+		return name, Order{OrderID: d.OrderID, Side: d.Side, Symbol: d.Symbol, Price: d.Price, Quantity: d.Quantity, Status: d.Status}, true
+
+	case events.Stoploss:
+
+		return EventStoplossTriggered, Order{OrderID: d.Order.OrderID, Side: "SELL", Symbol: d.Symbol, Price: d.Price, Reason: d.Reason}, true
+
+	case events.Session:
+
+		switch event.Topic {
+		case events.SessionStarted:
+			name = EventSessionStarted
+		case events.SessionStopped:
+			name = EventSessionStopped
+		default:
+			return "", nil, false
+		}
+
+		return name, Session{Symbol: d.Symbol, Port: d.Port, Resumed: d.Resumed, Reason: d.Reason}, true
+
+	case events.Error:
+
+		return EventError, Error{Symbol: d.Symbol, Message: d.Message}, true
+
+	}
+
+	return "", nil, false
+
+}
+
+// Flush wait up to timeout for the deliveries in progress, used before the thread exits
+func Flush(timeout time.Duration) {
+
+	done := make(chan struct{})
+
+	go func() {
+		pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+
+}
+
+/* Post the payload to the webhook, retrying after each backoff while the error is retryable */
+func deliver(
+	webhook types.Webhook,
+	payload Payload) (err error) {
+
+	for attempt := 0; ; attempt++ {
+
+		if err = Post(webhook, payload); err == nil || !retryable(err) || attempt == len(backoff) {
+
+			return err
+
+		}
+
+		time.Sleep(backoff[attempt])
+
+	}
+
+}
+
+/* Return false for client errors other than 408 and 429, the same request would be rejected again */
+func retryable(err error) bool {
+
+	var status statusError
+
+	if errors.As(err, &status) {
+
+		return status.code >= 500 || status.code == http.StatusRequestTimeout || status.code == http.StatusTooManyRequests
+
+	}
+
+	return true
+
+}
+
+// Test send a test event to the webhook id, disabled webhooks included
+func Test(
+	sessionData *types.Session,
+	id int64) (err error) {
+
+	var webhooks []types.Webhook
+
+	if webhooks, err = mysql.GetWebhooks(sessionData); err != nil {
+
+		return err
+
+	}
+
+	webhook, ok := find(webhooks, id)
+	if !ok {
+
+		return ErrNotFound
+
+	}
+
+	return Post(webhook, Payload{
+		Event:    TestEvent,
+		Time:     time.Now().UnixNano() / int64(time.Millisecond),
+		ThreadID: sessionData.ThreadID,
+		Data:     map[string]string{"text": "Test event from the cryptopump webhooks page"},
+	})
+
+}
+
+/* Return the webhook with id */
+func find(
+	webhooks []types.Webhook,
+	id int64) (types.Webhook, bool) {
+
+	for _, webhook := range webhooks {
+
+		if webhook.ID == id {
+
+			return webhook, true
+
+		}
+
+	}
+
+	return types.Webhook{}, false
+
+}
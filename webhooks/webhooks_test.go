@@ -0,0 +1,232 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/events"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		id      int64
+		name    string
+		target  string
+		events  []string
+		secret  string
+		enabled bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    types.Webhook
+		wantErr error
+	}{
+		{
+			name:    "edit keeps secret",
+			args:    args{id: 3, name: " Fills ", target: "https://example.com/hook", events: []string{"error", "order.filled", "unknown"}, enabled: true},
+			want:    types.Webhook{ID: 3, Name: "Fills", URL: "https://example.com/hook", Events: []string{"order.filled", "error"}, Enabled: true},
+			wantErr: nil,
+		},
+		{
+			name:    "secret",
+			args:    args{name: "Errors", target: "http://10.0.0.1:8000/cryptopump", events: []string{"error"}, secret: "0123456789abcdef"},
+			want:    types.Webhook{Name: "Errors", URL: "http://10.0.0.1:8000/cryptopump", Events: []string{"error"}, Secret: "0123456789abcdef"},
+			wantErr: nil,
+		},
+		{
+			name:    "empty name",
+			args:    args{name: " ", target: "https://example.com/hook", events: []string{"error"}},
+			wantErr: ErrInvalidName,
+		},
+		{
+			name:    "invalid URL",
+			args:    args{name: "Fills", target: "ftp://example.com/hook", events: []string{"error"}},
+			wantErr: ErrInvalidURL,
+		},
+		{
+			name:    "no events",
+			args:    args{name: "Fills", target: "https://example.com/hook", events: []string{"unknown"}},
+			wantErr: ErrInvalidEvents,
+		},
+		{
+			name:    "short secret",
+			args:    args{name: "Fills", target: "https://example.com/hook", events: []string{"error"}, secret: "secret"},
+			wantErr: ErrInvalidSecret,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.args.id, tt.args.name, tt.args.target, tt.args.events, tt.args.secret, tt.args.enabled)
+			if err != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_newSecret(t *testing.T) {
+	got, err := Parse(0, "Fills", "https://example.com/hook", []string{"order.filled"}, "", true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(got.Secret) != 64 {
+		t.Errorf("Parse() secret = %v, want 64 hex characters", got.Secret)
+	}
+}
+
+func TestSign(t *testing.T) {
+	/* echo -n '{"event":"webhook.test"}' | openssl dgst -sha256 -hmac 0123456789abcdef */
+	want := "sha256=8a45d16e04d0c75208dfb3d8762fe2c85afcd4749616029cb3817b18ac23657c"
+	if got := Sign("0123456789abcdef", []byte(`{"event":"webhook.test"}`)); got != want {
+		t.Errorf("Sign() = %v, want %v", got, want)
+	}
+}
+
+func TestPost(t *testing.T) {
+	var payload Payload
+	var signature, event string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		signature = r.Header.Get("X-Cryptopump-Signature")
+		event = r.Header.Get("X-Cryptopump-Event")
+		if signature != Sign("0123456789abcdef", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	webhook := types.Webhook{Name: "Fills", URL: server.URL, Secret: "0123456789abcdef"}
+	if err := Post(webhook, Payload{Event: TestEvent, Time: 1638316800000, ThreadID: "c683ok5mk1u1120gnmmg"}); err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	if event != TestEvent || payload.ThreadID != "c683ok5mk1u1120gnmmg" || payload.Time != 1638316800000 {
+		t.Errorf("Post() event = %v, payload = %v", event, payload)
+	}
+
+	webhook.Secret = "fedcba9876543210"
+	if err := Post(webhook, Payload{Event: TestEvent}); err == nil {
+		t.Errorf("Post() with a different secret error = nil, want 401")
+	}
+}
+
+func Test_deliver(t *testing.T) {
+	var calls int
+	var statuses []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statuses[calls])
+		calls++
+	}))
+	defer server.Close()
+
+	saved := backoff
+	backoff = []time.Duration{0, 0}
+	defer func() { backoff = saved }()
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "delivered", statuses: []int{http.StatusOK}, wantCalls: 1},
+		{name: "retried", statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusNoContent}, wantCalls: 3},
+		{name: "retries exhausted", statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}, wantCalls: 3, wantErr: true},
+		{name: "not retryable", statuses: []int{http.StatusNotFound}, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, statuses = 0, tt.statuses
+			err := deliver(types.Webhook{Name: "Fills", URL: server.URL, Secret: "0123456789abcdef"}, Payload{Event: EventOrderFilled})
+			if (err != nil) != tt.wantErr || calls != tt.wantCalls {
+				t.Errorf("deliver() error = %v, calls = %v, want error %v, calls %v", err, calls, tt.wantErr, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_retryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "network", err: errors.New("connection refused"), want: true},
+		{name: "server error", err: statusError{name: "Fills", status: "503 Service Unavailable", code: 503}, want: true},
+		{name: "timeout", err: statusError{name: "Fills", status: "408 Request Timeout", code: 408}, want: true},
+		{name: "unauthorized", err: statusError{name: "Fills", status: "401 Unauthorized", code: 401}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    events.Event
+		wantName string
+		wantData interface{}
+		wantOk   bool
+	}{
+		{
+			name:     "placed",
+			event:    events.Event{Topic: events.OrderPlaced, Data: events.Order{OrderID: 42, Side: "BUY", Symbol: "BTCUSDT", Price: 100, Quantity: 0.5, Status: "NEW"}},
+			wantName: EventOrderPlaced,
+			wantData: Order{OrderID: 42, Side: "BUY", Symbol: "BTCUSDT", Price: 100, Quantity: 0.5, Status: "NEW"},
+			wantOk:   true,
+		},
+		{
+			name:   "failed",
+			event:  events.Event{Topic: events.OrderFailed, Data: events.Order{Side: "BUY", Err: errors.New("timeout")}},
+			wantOk: false,
+		},
+		{
+			name:     "stoploss",
+			event:    events.Event{Topic: events.StoplossTriggered, Data: events.Stoploss{Order: types.Order{OrderID: 42}, Symbol: "BTCUSDT", Price: 100, Reason: "stop price"}},
+			wantName: EventStoplossTriggered,
+			wantData: Order{OrderID: 42, Side: "SELL", Symbol: "BTCUSDT", Price: 100, Reason: "stop price"},
+			wantOk:   true,
+		},
+		{
+			name:     "stopped",
+			event:    events.Event{Topic: events.SessionStopped, Data: events.Session{Symbol: "BTCUSDT", Port: "8080", Reason: "stopped"}},
+			wantName: EventSessionStopped,
+			wantData: Session{Symbol: "BTCUSDT", Port: "8080", Reason: "stopped"},
+			wantOk:   true,
+		},
+		{
+			name:     "error",
+			event:    events.Event{Topic: events.ErrorRaised, Data: events.Error{Symbol: "BTCUSDT", Message: "BUY order failed: timeout"}},
+			wantName: EventError,
+			wantData: Error{Symbol: "BTCUSDT", Message: "BUY order failed: timeout"},
+			wantOk:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, data, ok := NewEvent(tt.event)
+			if name != tt.wantName || !reflect.DeepEqual(data, tt.wantData) || ok != tt.wantOk {
+				t.Errorf("NewEvent() = %v, %v, %v, want %v, %v, %v", name, data, ok, tt.wantName, tt.wantData, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package wsstats
+
+/* This package implements the websocket connection statistics of the threads running in this process. Every
+connection of the kline, book ticker and user data streams is tracked from its connection to its disconnection with
+the messages received and the last error, exported to the metrics endpoint and displayed on the thread detail page.
+Disconnected connections are saved to the wsconnection table with their duration and reason, so the connectivity
+history of a thread survives restarts and can be exported. */
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aleibovici/cryptopump/functions"
+	"github.com/aleibovici/cryptopump/metrics"
+	"github.com/aleibovici/cryptopump/mysql"
+	"github.com/aleibovici/cryptopump/types"
+)
+
+/* Disconnection reasons */
+const (
+	ReasonClosed  = "closed"  /* Connection closed by the exchange or a network error */
+	ReasonStale   = "stale"   /* No update within the market data stale timeout, reconnected */
+	ReasonStopped = "stopped" /* Thread stopping */
+)
+
+// Stream struct define the connection statistics of a websocket stream
+type Stream struct {
+	Stream      string
+	Connected   bool
+	Since       time.Time /* Time of the current connection, or of the last disconnection */
+	Uptime      float64   /* Seconds connected of the current connection */
+	Messages    int64     /* Messages received during the current connection */
+	MessageRate float64   /* Messages per second of the current connection */
+	Connections int       /* Connections since the thread started */
+	Disconnects int       /* Disconnections since the thread started */
+	Available   float64   /* Ratio of time connected since the first connection */
+	LastError   string    /* Last error of the stream since the thread started */
+}
+
+/* Connection state of a stream */
+type state struct {
+	connected   bool
+	since       time.Time     /* Connection or disconnection time */
+	first       time.Time     /* First connection time */
+	uptime      time.Duration /* Time connected of the previous connections */
+	messages    int64         /* Messages of the current connection */
+	connections int
+	disconnects int
+	err         string /* Last error of the current connection */
+	lastError   string /* Last error since the thread started */
+}
+
+var streams = struct {
+	sync.Mutex
+	states map[string]map[string]*state /* States of the streams of each ThreadID */
+}{
+	states: make(map[string]map[string]*state),
+}
+
+// Reset clear the statistics of threadID for a new thread
+func Reset(threadID string) {
+
+	streams.Lock()
+	defer streams.Unlock()
+
+	delete(streams.states, threadID)
+
+}
+
+// Connected record the connection of stream of threadID at now
+func Connected(
+	threadID string,
+	stream string,
+	now time.Time) {
+
+	metrics.WebsocketConnected(stream, true)
+
+	streams.Lock()
+	defer streams.Unlock()
+
+	s := get(threadID, stream)
+
+	if s.first.IsZero() {
+		s.first = now
+	}
+
+	s.connected = true
+	s.since = now
+	s.messages = 0
+	s.connections++
+	s.err = ""
+
+}
+
+// Message count a message received on stream of threadID
+func Message(
+	threadID string,
+	stream string) {
+
+	metrics.WebsocketMessage(stream)
+
+	streams.Lock()
+	defer streams.Unlock()
+
+	get(threadID, stream).messages++
+
+}
+
+// Error record an error of stream of threadID
+func Error(
+	threadID string,
+	stream string,
+	err error) {
+
+	streams.Lock()
+	defer streams.Unlock()
+
+	s := get(threadID, stream)
+	s.err = err.Error()
+	s.lastError = s.err
+
+}
+
+// Disconnected record the disconnection of stream at now for reason and save the connection to the wsconnection table
+func Disconnected(
+	sessionData *types.Session,
+	stream string,
+	reason string,
+	now time.Time) {
+
+	if connection, ok := disconnect(sessionData.ThreadID, stream, reason, now); ok && sessionData.Db != nil {
+
+		_ = mysql.SaveWsConnection(sessionData, connection) /* A failure is logged by SaveWsConnection */
+
+	}
+
+}
+
+// Read return the statistics of the streams of threadID at now, sorted by stream
+func Read(
+	threadID string,
+	now time.Time) (stats []Stream) {
+
+	streams.Lock()
+	defer streams.Unlock()
+
+	for name, s := range streams.states[threadID] {
+
+		stream := Stream{
+			Stream:      name,
+			Connected:   s.connected,
+			Since:       s.since,
+			Connections: s.connections,
+			Disconnects: s.disconnects,
+			LastError:   s.lastError,
+		}
+
+		uptime := s.uptime
+
+		if s.connected {
+
+			current := now.Sub(s.since)
+			uptime += current
+
+			stream.Uptime = functions.Round(current.Seconds(), 2)
+			stream.Messages = s.messages
+
+			if current > 0 {
+				stream.MessageRate = functions.Round(float64(s.messages)/current.Seconds(), 2)
+			}
+
+		}
+
+		if total := now.Sub(s.first); total > 0 {
+			stream.Available = functions.Round(float64(uptime)/float64(total), 2)
+		}
+
+		stats = append(stats, stream)
+
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Stream < stats[j].Stream })
+
+	return stats
+
+}
+
+/* Record the disconnection of stream and return its connection, false when it wasn't connected */
+func disconnect(
+	threadID string,
+	stream string,
+	reason string,
+	now time.Time) (connection types.WsConnection, ok bool) {
+
+	metrics.WebsocketConnected(stream, false)
+
+	streams.Lock()
+	defer streams.Unlock()
+
+	s := get(threadID, stream)
+
+	if !s.connected {
+
+		return connection, false
+
+	}
+
+	connection = types.WsConnection{
+		ThreadID:         threadID,
+		Stream:           stream,
+		ConnectedTime:    s.since.UnixNano() / int64(time.Millisecond),
+		DisconnectedTime: now.UnixNano() / int64(time.Millisecond),
+		Messages:         s.messages,
+		Reason:           reason,
+		Error:            s.err,
+	}
+
+	s.uptime += now.Sub(s.since)
+	s.connected = false
+	s.since = now
+	s.messages = 0
+	s.disconnects++
+
+	return connection, true
+
+}
+
+/* Return the state of stream of threadID, the streams must be locked */
+func get(
+	threadID string,
+	stream string) *state {
+
+	states, ok := streams.states[threadID]
+	if !ok {
+		states = make(map[string]*state)
+		streams.states[threadID] = states
+	}
+
+	s, ok := states[stream]
+	if !ok {
+		s = &state{}
+		states[stream] = s
+	}
+
+	return s
+
+}
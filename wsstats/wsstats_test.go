@@ -0,0 +1,70 @@
+package wsstats
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aleibovici/cryptopump/types"
+)
+
+func TestRead(t *testing.T) {
+
+	threadID := "c683ok5mk1u1120gnmmg"
+
+	Reset(threadID)
+	defer Reset(threadID)
+
+	start := time.Date(2021, 12, 6, 10, 0, 0, 0, time.UTC)
+
+	Connected(threadID, "kline", start)
+	for i := 0; i < 30; i++ {
+		Message(threadID, "kline")
+	}
+	Error(threadID, "kline", errors.New("websocket: close 1006 (abnormal closure): unexpected EOF"))
+
+	connection, ok := disconnect("c683ok5mk1u1120gnmmg", "kline", ReasonClosed, start.Add(30*time.Second))
+
+	want := types.WsConnection{
+		ThreadID:         "c683ok5mk1u1120gnmmg",
+		Stream:           "kline",
+		ConnectedTime:    1638784800000,
+		DisconnectedTime: 1638784830000,
+		Messages:         30,
+		Reason:           ReasonClosed,
+		Error:            "websocket: close 1006 (abnormal closure): unexpected EOF",
+	}
+
+	if !ok || !reflect.DeepEqual(connection, want) {
+		t.Errorf("disconnect() = %v, %v, want %v, true", connection, ok, want)
+	}
+
+	if _, ok := disconnect("c683ok5mk1u1120gnmmg", "kline", ReasonClosed, start.Add(31*time.Second)); ok {
+		t.Errorf("disconnect() not connected = true, want false")
+	}
+
+	Connected(threadID, "kline", start.Add(40*time.Second))
+	for i := 0; i < 20; i++ {
+		Message(threadID, "kline")
+	}
+
+	Connected(threadID, "bookticker", start)
+
+	got := Read(threadID, start.Add(50*time.Second))
+
+	wantStats := []Stream{
+		{Stream: "bookticker", Connected: true, Since: start, Uptime: 50, Connections: 1, Available: 1},
+		{Stream: "kline", Connected: true, Since: start.Add(40 * time.Second), Uptime: 10, Messages: 20, MessageRate: 2, Connections: 2, Disconnects: 1, Available: 0.8,
+			LastError: "websocket: close 1006 (abnormal closure): unexpected EOF"},
+	}
+
+	if !reflect.DeepEqual(got, wantStats) {
+		t.Errorf("Read() = %v, want %v", got, wantStats)
+	}
+
+	if got := Read("c683ok5mk1u1120gnmmh", start.Add(50*time.Second)); got != nil {
+		t.Errorf("Read() other thread = %v, want nil", got)
+	}
+
+}